@@ -3,18 +3,23 @@ package main
 import (
 	"context"
 	"io"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
 	"ms-decision-service/internal/domain/usecase"
 	"ms-decision-service/internal/infrastructure/telemetry"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	httpAdapter "ms-decision-service/internal/infrastructure/adapter/in/http"
 	kafkaIn "ms-decision-service/internal/infrastructure/adapter/in/kafka"
 	dynamodbAdapter "ms-decision-service/internal/infrastructure/adapter/out/aws/dynamodb"
+	"ms-decision-service/internal/infrastructure/adapter/out/cache"
 	kafkaOut "ms-decision-service/internal/infrastructure/adapter/out/kafka"
+	"ms-decision-service/internal/infrastructure/adapter/out/memory"
 
 	"github.com/IBM/sarama"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -69,13 +74,36 @@ func main() {
 	}
 
 	rulesTable := getEnvOrDefault("DYNAMO_DB_RULES_TABLE", "ddb-rules")
-	ruleRepo := dynamodbAdapter.NewDynamoDBRuleRepository(dynamoClient, rulesTable, logger)
+	ruleHistoryTable := getEnvOrDefault("DYNAMO_DB_RULE_HISTORY_TABLE", "ddb-rule-history")
+	ruleRepo := dynamodbAdapter.NewDynamoDBRuleRepository(dynamoClient, rulesTable, ruleHistoryTable, logger)
 	logger.Info().Str("table", rulesTable).Msg("rules repository initialized")
 
 	ruleEvalsTable := getEnvOrDefault("DYNAMO_DB_RULE_EVALUATIONS_TABLE", "ddb-rule-evaluations")
 	ruleEvalRepo := dynamodbAdapter.NewDynamoDBRuleEvaluationRepository(dynamoClient, ruleEvalsTable, logger)
 	logger.Info().Str("table", ruleEvalsTable).Msg("rule evaluations repository initialized")
 
+	ruleHistoryRepo := dynamodbAdapter.NewDynamoDBRuleHistoryRepository(dynamoClient, ruleHistoryTable, logger)
+	logger.Info().Str("table", ruleHistoryTable).Msg("rule history repository initialized")
+
+	listsTable := getEnvOrDefault("DYNAMO_DB_LISTS_TABLE", "ddb-lists")
+	listRepo := dynamodbAdapter.NewDynamoDBListRepository(dynamoClient, listsTable, logger)
+	logger.Info().Str("table", listsTable).Msg("lists repository initialized")
+
+	policiesTable := getEnvOrDefault("DYNAMO_DB_DECISION_POLICIES_TABLE", "ddb-decision-policies")
+	policyRepo := dynamodbAdapter.NewDynamoDBDecisionPolicyRepository(dynamoClient, policiesTable, logger)
+	logger.Info().Str("table", policiesTable).Msg("decision policies repository initialized")
+
+	// Processed messages: redelivered Kafka messages return the recorded decision
+	processedMessagesTable := getEnvOrDefault("DYNAMO_DB_PROCESSED_MESSAGES_TABLE", "ddb-processed-messages")
+	processedMessageTTL := getDurationOrDefault("PROCESSED_MESSAGE_TTL", 7*24*time.Hour, logger)
+	processedStore := dynamodbAdapter.NewDynamoDBProcessedMessageStore(dynamoClient, processedMessagesTable, processedMessageTTL, logger)
+	logger.Info().Str("table", processedMessagesTable).Dur("ttl", processedMessageTTL).Msg("processed message store initialized")
+
+	// Historical transactions are read from the transaction evaluator's table for backtests
+	transactionsTable := getEnvOrDefault("DYNAMO_DB_TRANSACTIONS_TABLE", "ddb-transactions")
+	transactionRepo := dynamodbAdapter.NewDynamoDBTransactionRepository(dynamoClient, transactionsTable, logger)
+	logger.Info().Str("table", transactionsTable).Msg("transactions repository initialized")
+
 	// Kafka producer for decision results
 	brokerAddress := getEnvOrDefault("KAFKA_BROKER_ADDRESS", "localhost:9092")
 	decisionTopic := getEnvOrDefault("KAFKA_DECISION_CALCULATED_TOPIC", "Decision.Calculated")
@@ -98,11 +126,106 @@ func main() {
 	fraudScorePublisher := kafkaOut.NewSaramaFraudScoreRequestPublisher(producer, fraudScoreRequestTopic, logger)
 	logger.Info().Str("topic", fraudScoreRequestTopic).Msg("fraud score request publisher initialized")
 
+	// Dead-letter topics for consumed messages that cannot be processed
+	pendingTopic := getEnvOrDefault("KAFKA_TRANSACTION_CREATED_TOPIC", "Transaction.Created")
+	fraudScoreCalculatedTopic := getEnvOrDefault("KAFKA_FRAUD_SIGNALS_CALCULATED_TOPIC", "FraudSignals.Calculated")
+	transactionDLQTopic := getEnvOrDefault("KAFKA_TRANSACTION_CREATED_DLQ_TOPIC", "Transaction.Created.DLQ")
+	transactionDeadLetters := kafkaOut.NewSaramaDeadLetterPublisher(producer, transactionDLQTopic, logger)
+	fraudScoreDLQTopic := getEnvOrDefault("KAFKA_FRAUD_SIGNALS_CALCULATED_DLQ_TOPIC", "FraudSignals.Calculated.DLQ")
+	fraudScoreDeadLetters := kafkaOut.NewSaramaDeadLetterPublisher(producer, fraudScoreDLQTopic, logger)
+	logger.Info().Str("transaction_topic", transactionDLQTopic).Str("fraud_score_topic", fraudScoreDLQTopic).Msg("dead-letter publishers initialized")
+
+	// Dead-letter topics are read with a Kafka client for inspection and redrive
+	dlqClientConfig := sarama.NewConfig()
+	dlqClientConfig.Consumer.Return.Errors = true
+	dlqClient, err := sarama.NewClient([]string{brokerAddress}, dlqClientConfig)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create Kafka client for dead-letter topics")
+	}
+	defer dlqClient.Close()
+	deadLetterRepo := kafkaOut.NewSaramaDeadLetterRepository(dlqClient, producer, logger)
+	deadLetterQueues := []entity.DeadLetterQueue{
+		{Topic: transactionDLQTopic, SourceTopic: pendingTopic},
+		{Topic: fraudScoreDLQTopic, SourceTopic: fraudScoreCalculatedTopic},
+	}
+
+	defaultRetryPolicy := kafkaIn.DefaultRetryPolicy()
+	retryPolicy := kafkaIn.RetryPolicy{
+		MaxAttempts:    getIntOrDefault("KAFKA_CONSUMER_MAX_ATTEMPTS", defaultRetryPolicy.MaxAttempts, logger),
+		InitialBackoff: getDurationOrDefault("KAFKA_CONSUMER_RETRY_BACKOFF", defaultRetryPolicy.InitialBackoff, logger),
+		MaxBackoff:     getDurationOrDefault("KAFKA_CONSUMER_RETRY_MAX_BACKOFF", defaultRetryPolicy.MaxBackoff, logger),
+	}
+
+	// Rule cache: evaluations read active rules from memory, writes invalidate the snapshot
+	cachedRuleRepo := cache.NewCachingRuleRepository(ruleRepo, ruleHistoryRepo, logger)
+	cachedRuleHistoryRepo := cache.NewCachingRuleHistoryRepository(ruleHistoryRepo, cachedRuleRepo)
+	if err := cachedRuleRepo.Refresh(context.Background()); err != nil {
+		logger.Warn().Err(err).Msg("failed to warm rule cache, rules will be loaded on first evaluation")
+	}
+	ruleCacheVersionCheckInterval := getDurationOrDefault("RULE_CACHE_VERSION_CHECK_INTERVAL", 5*time.Second, logger)
+	ruleCacheRefreshInterval := getDurationOrDefault("RULE_CACHE_REFRESH_INTERVAL", 5*time.Minute, logger)
+
+	// List cache: evaluations read managed lists from memory, writes invalidate the snapshot
+	cachedListRepo := cache.NewCachingListRepository(listRepo, logger)
+	if err := cachedListRepo.Refresh(context.Background()); err != nil {
+		logger.Warn().Err(err).Msg("failed to warm list cache, lists will be loaded on first evaluation")
+	}
+	listCacheRefreshInterval := getDurationOrDefault("LIST_CACHE_REFRESH_INTERVAL", 30*time.Second, logger)
+
+	// Decision policy cache: evaluations read policies from memory, updates invalidate them
+	cachedPolicyRepo := cache.NewCachingDecisionPolicyRepository(policyRepo, logger)
+	if err := cachedPolicyRepo.Refresh(context.Background()); err != nil {
+		logger.Warn().Err(err).Msg("failed to warm decision policy cache, policies will be loaded on first evaluation")
+	}
+	policyRefreshInterval := getDurationOrDefault("DECISION_POLICY_REFRESH_INTERVAL", 30*time.Second, logger)
+
+	// Reference rates for amount_in_usd_cents, as CODE=rate pairs of US dollars per unit
+	currencyRates := getCurrencyRatesOrDefault("USD_REFERENCE_RATES", "USD=1,EUR=1.08,COP=0.00025", logger)
+
+	// Velocity counters, shared through DynamoDB; events are kept for the longest window.
+	// The in-memory store keeps separate counters per instance and is only suitable
+	// for a single instance.
+	var velocityStore repository.VelocityStore
+	switch velocityStoreKind := getEnvOrDefault("VELOCITY_STORE", "dynamodb"); velocityStoreKind {
+	case "memory":
+		velocityStore = memory.NewInMemoryVelocityStore(24 * time.Hour)
+		logger.Warn().Msg("velocity counters are kept in memory and are not shared between instances")
+	case "dynamodb":
+		velocityEventsTable := getEnvOrDefault("DYNAMO_DB_VELOCITY_EVENTS_TABLE", "ddb-velocity-events")
+		velocityStore = dynamodbAdapter.NewDynamoDBVelocityStore(dynamoClient, velocityEventsTable, 24*time.Hour, logger)
+		logger.Info().Str("table", velocityEventsTable).Msg("velocity store initialized")
+	default:
+		logger.Fatal().Str("velocity_store", velocityStoreKind).Msg("unknown VELOCITY_STORE, expected dynamodb or memory")
+	}
+
 	// Use cases
-	evaluateUC := usecase.NewEvaluateTransactionUseCase(ruleRepo, decisionPublisher, fraudScorePublisher, ruleEvalRepo, logger)
-	evaluateFraudScoreUC := usecase.NewEvaluateFraudScoreUseCase(ruleRepo, decisionPublisher, ruleEvalRepo, logger)
+	evaluateUC := usecase.NewEvaluateTransactionUseCase(cachedRuleRepo, decisionPublisher, fraudScorePublisher, ruleEvalRepo, cachedRuleHistoryRepo, velocityStore, cachedListRepo, cachedPolicyRepo, processedStore, currencyRates, logger)
+	evaluateFraudScoreUC := usecase.NewEvaluateFraudScoreUseCase(cachedRuleRepo, decisionPublisher, ruleEvalRepo, cachedRuleHistoryRepo, cachedPolicyRepo, processedStore, logger)
 	getRuleEvaluationsUC := usecase.NewGetRuleEvaluationsUseCase(ruleEvalRepo)
 	listRulesUC := usecase.NewListRulesUseCase(ruleRepo)
+	createRuleUC := usecase.NewCreateRuleUseCase(cachedRuleRepo, ruleHistoryRepo, listRepo)
+	updateRuleUC := usecase.NewUpdateRuleUseCase(cachedRuleRepo, ruleHistoryRepo, listRepo)
+	setRuleActiveUC := usecase.NewSetRuleActiveUseCase(cachedRuleRepo, ruleHistoryRepo)
+	deleteRuleUC := usecase.NewDeleteRuleUseCase(cachedRuleRepo, ruleHistoryRepo)
+	getRuleHistoryUC := usecase.NewGetRuleHistoryUseCase(ruleRepo, ruleHistoryRepo)
+	rollbackRuleUC := usecase.NewRollbackRuleUseCase(cachedRuleRepo, ruleHistoryRepo)
+	rollbackRulesetUC := usecase.NewRollbackRulesetUseCase(cachedRuleRepo, ruleHistoryRepo)
+	getRuleCacheStatusUC := usecase.NewGetRuleCacheStatusUseCase(cachedRuleRepo)
+	refreshRuleCacheUC := usecase.NewRefreshRuleCacheUseCase(cachedRuleRepo)
+	createListUC := usecase.NewCreateListUseCase(cachedListRepo)
+	getListsUC := usecase.NewGetListsUseCase(cachedListRepo)
+	getListUC := usecase.NewGetListUseCase(cachedListRepo)
+	deleteListUC := usecase.NewDeleteListUseCase(cachedListRepo, ruleRepo)
+	addListEntriesUC := usecase.NewAddListEntriesUseCase(cachedListRepo)
+	removeListEntryUC := usecase.NewRemoveListEntryUseCase(cachedListRepo)
+	getDecisionPoliciesUC := usecase.NewGetDecisionPoliciesUseCase(cachedPolicyRepo)
+	updateDecisionPolicyUC := usecase.NewUpdateDecisionPolicyUseCase(cachedPolicyRepo, ruleRepo)
+	getShadowReportUC := usecase.NewGetShadowReportUseCase(ruleRepo, ruleEvalRepo)
+	backtestRulesUC := usecase.NewBacktestRulesUseCase(cachedRuleRepo, transactionRepo, cachedListRepo, cachedPolicyRepo, currencyRates)
+	getDeadLetterQueuesUC := usecase.NewGetDeadLetterQueuesUseCase(deadLetterRepo, deadLetterQueues)
+	listDeadLettersUC := usecase.NewListDeadLettersUseCase(deadLetterRepo, deadLetterQueues)
+	getDeadLetterUC := usecase.NewGetDeadLetterUseCase(deadLetterRepo, deadLetterQueues)
+	redriveDeadLettersUC := usecase.NewRedriveDeadLettersUseCase(deadLetterRepo, deadLetterQueues)
 
 	// Echo HTTP server
 	e := echo.New()
@@ -121,13 +244,45 @@ func main() {
 	e.Use(echootel.NewMiddleware("ms-decision-service"))
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"http://localhost:5173"},
-		AllowMethods: []string{http.MethodGet, http.MethodOptions},
-		AllowHeaders: []string{echo.HeaderContentType},
+		AllowMethods: []string{
+			http.MethodGet, http.MethodPost, http.MethodPut,
+			http.MethodPatch, http.MethodDelete, http.MethodOptions,
+		},
+		AllowHeaders: []string{echo.HeaderContentType, "X-Changed-By"},
 	}))
 
 	evaluationController := httpAdapter.NewEvaluationController(getRuleEvaluationsUC, listRulesUC, logger)
 	evaluationController.RegisterRoutes(e)
 
+	ruleController := httpAdapter.NewRuleController(
+		createRuleUC, updateRuleUC, setRuleActiveUC, deleteRuleUC,
+		getRuleHistoryUC, rollbackRuleUC, rollbackRulesetUC, logger,
+	)
+	ruleController.RegisterRoutes(e)
+
+	ruleCacheController := httpAdapter.NewRuleCacheController(getRuleCacheStatusUC, refreshRuleCacheUC, logger)
+	ruleCacheController.RegisterRoutes(e)
+
+	listController := httpAdapter.NewListController(
+		createListUC, getListsUC, getListUC, deleteListUC,
+		addListEntriesUC, removeListEntryUC, logger,
+	)
+	listController.RegisterRoutes(e)
+
+	decisionPolicyController := httpAdapter.NewDecisionPolicyController(getDecisionPoliciesUC, updateDecisionPolicyUC, logger)
+	decisionPolicyController.RegisterRoutes(e)
+
+	shadowReportController := httpAdapter.NewShadowReportController(getShadowReportUC, logger)
+	shadowReportController.RegisterRoutes(e)
+
+	backtestController := httpAdapter.NewBacktestController(backtestRulesUC, logger)
+	backtestController.RegisterRoutes(e)
+
+	deadLetterController := httpAdapter.NewDeadLetterController(
+		getDeadLetterQueuesUC, listDeadLettersUC, getDeadLetterUC, redriveDeadLettersUC, logger,
+	)
+	deadLetterController.RegisterRoutes(e)
+
 	// Prometheus metrics endpoint
 	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 
@@ -135,7 +290,6 @@ func main() {
 
 	// Kafka consumer
 	consumerGroup := getEnvOrDefault("KAFKA_CONSUMER_GROUP", "decision-service-group")
-	pendingTopic := getEnvOrDefault("KAFKA_TRANSACTION_CREATED_TOPIC", "Transaction.Created")
 
 	saramaConfig := sarama.NewConfig()
 	saramaConfig.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{
@@ -151,11 +305,10 @@ func main() {
 	defer group.Close()
 	logger.Info().Str("group", consumerGroup).Str("broker", brokerAddress).Msg("Kafka consumer group connected")
 
-	consumer := kafkaIn.NewTransactionConsumer(evaluateUC, logger)
+	consumer := kafkaIn.NewTransactionConsumer(evaluateUC, transactionDeadLetters, retryPolicy, logger)
 	wrappedConsumer := otelsarama.WrapConsumerGroupHandler(consumer)
 
 	// Fraud score consumer group
-	fraudScoreCalculatedTopic := getEnvOrDefault("KAFKA_FRAUD_SIGNALS_CALCULATED_TOPIC", "FraudSignals.Calculated")
 	fraudScoreConsumerGroup := "fraud-score-consumer-group"
 
 	logger.Info().Str("group", fraudScoreConsumerGroup).Str("topic", fraudScoreCalculatedTopic).Msg("creating fraud score consumer group")
@@ -166,7 +319,7 @@ func main() {
 	defer fsGroup.Close()
 	logger.Info().Str("group", fraudScoreConsumerGroup).Str("broker", brokerAddress).Msg("fraud score consumer group connected")
 
-	fraudScoreConsumer := kafkaIn.NewFraudScoreConsumer(evaluateFraudScoreUC, logger)
+	fraudScoreConsumer := kafkaIn.NewFraudScoreConsumer(evaluateFraudScoreUC, fraudScoreDeadLetters, retryPolicy, logger)
 	wrappedFraudScoreConsumer := otelsarama.WrapConsumerGroupHandler(fraudScoreConsumer)
 
 	// Graceful shutdown
@@ -187,6 +340,11 @@ func main() {
 		Str("topic", pendingTopic).
 		Msg("decision service started, consuming messages")
 
+	// Keep the rule cache in sync with changes made through other instances
+	go cachedRuleRepo.Run(ctx, ruleCacheVersionCheckInterval, ruleCacheRefreshInterval)
+	go cachedListRepo.Run(ctx, listCacheRefreshInterval)
+	go cachedPolicyRepo.Run(ctx, policyRefreshInterval)
+
 	// Start Echo HTTP server in a goroutine
 	go func() {
 		logger.Info().Str("port", port).Msg("starting HTTP server")
@@ -247,3 +405,42 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getDurationOrDefault(key string, defaultValue time.Duration, logger zerolog.Logger) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		logger.Warn().Str("key", key).Str("value", value).Msg("invalid duration, using default")
+		return defaultValue
+	}
+	return duration
+}
+
+func getIntOrDefault(key string, defaultValue int, logger zerolog.Logger) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		logger.Warn().Str("key", key).Str("value", value).Msg("invalid number, using default")
+		return defaultValue
+	}
+	return number
+}
+
+func getCurrencyRatesOrDefault(key, defaultValue string, logger zerolog.Logger) entity.CurrencyRates {
+	value := getEnvOrDefault(key, defaultValue)
+
+	rates, err := entity.ParseCurrencyRates(value)
+	if err != nil {
+		logger.Warn().Err(err).Str("key", key).Str("value", value).Msg("invalid currency rates, using default")
+		rates, _ = entity.ParseCurrencyRates(defaultValue)
+	}
+	return rates
+}
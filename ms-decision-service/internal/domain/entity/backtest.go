@@ -0,0 +1,151 @@
+package entity
+
+import "sort"
+
+// MaxBacktestSamples bounds the transactions listed in a backtest report as
+// examples of changed decisions.
+const MaxBacktestSamples = 20
+
+// BacktestRuleStats reports how often a candidate rule matched during a backtest.
+type BacktestRuleStats struct {
+	RuleID       string         `json:"rule_id"`
+	RuleName     string         `json:"rule_name"`
+	ResultStatus DecisionStatus `json:"result_status"`
+	Mode         RuleMode       `json:"mode"`
+	Matches      int            `json:"matches"`
+	HitRate      float64        `json:"hit_rate"`
+}
+
+// BacktestTransition counts the transactions whose actual decision was
+// ActualStatus and whose replayed decision was BacktestStatus.
+type BacktestTransition struct {
+	ActualStatus   DecisionStatus `json:"actual_status"`
+	BacktestStatus DecisionStatus `json:"backtest_status"`
+	Count          int            `json:"count"`
+}
+
+// BacktestReport is the outcome of replaying historical transactions through a
+// candidate rule set. Compared counts the transactions that had a final actual
+// decision (APPROVED or DECLINED); Changed those whose replayed decision differs.
+type BacktestReport struct {
+	TransactionCount            int                    `json:"transaction_count"`
+	Decisions                   map[DecisionStatus]int `json:"decisions"`
+	Rules                       []BacktestRuleStats    `json:"rules"`
+	Compared                    int                    `json:"compared"`
+	Changed                     int                    `json:"changed"`
+	Transitions                 []BacktestTransition   `json:"transitions"`
+	SampleChangedTransactionIDs []string               `json:"sample_changed_transaction_ids"`
+}
+
+// Backtest replays transactions through a candidate rule set under a decision
+// policy and accumulates a BacktestReport. It never publishes anything.
+type Backtest struct {
+	policy      DecisionPolicy
+	rules       []Rule
+	matches     []int
+	total       int
+	decisions   map[DecisionStatus]int
+	compared    int
+	changed     int
+	transitions map[[2]DecisionStatus]int
+	samples     []string
+}
+
+// NewBacktest creates a backtest of the rules, which must be sorted by priority.
+func NewBacktest(policy DecisionPolicy, rules []Rule) *Backtest {
+	return &Backtest{
+		policy: policy,
+		rules:  rules,
+		decisions: map[DecisionStatus]int{
+			APPROVED:   0,
+			DECLINED:   0,
+			FRAUDCHECK: 0,
+		},
+		matches:     make([]int, len(rules)),
+		transitions: make(map[[2]DecisionStatus]int),
+	}
+}
+
+// Replay evaluates one transaction and compares the result with actual, the
+// decision that was really made. An actual status other than APPROVED or
+// DECLINED (e.g. PENDING) is not compared.
+func (b *Backtest) Replay(transactionID string, src FieldValueSource, actual DecisionStatus) DecisionStatus {
+	status := b.policy.Evaluate(src, b.rules).Status
+
+	b.total++
+	b.decisions[status]++
+	for i := range b.rules {
+		if b.rules[i].Matches(src) {
+			b.matches[i]++
+		}
+	}
+
+	if actual != APPROVED && actual != DECLINED {
+		return status
+	}
+
+	b.compared++
+	if status == actual {
+		return status
+	}
+
+	b.changed++
+	b.transitions[[2]DecisionStatus{actual, status}]++
+	if len(b.samples) < MaxBacktestSamples {
+		b.samples = append(b.samples, transactionID)
+	}
+
+	return status
+}
+
+// Report returns the accumulated report. Transitions are ordered by count, most
+// frequent first.
+func (b *Backtest) Report() *BacktestReport {
+	report := &BacktestReport{
+		TransactionCount:            b.total,
+		Decisions:                   make(map[DecisionStatus]int, len(b.decisions)),
+		Rules:                       make([]BacktestRuleStats, len(b.rules)),
+		Compared:                    b.compared,
+		Changed:                     b.changed,
+		Transitions:                 []BacktestTransition{},
+		SampleChangedTransactionIDs: append([]string{}, b.samples...),
+	}
+
+	for status, count := range b.decisions {
+		report.Decisions[status] = count
+	}
+
+	for i := range b.rules {
+		stats := BacktestRuleStats{
+			RuleID:       b.rules[i].RuleID,
+			RuleName:     b.rules[i].RuleName,
+			ResultStatus: b.rules[i].ResultStatus,
+			Mode:         b.rules[i].EffectiveMode(),
+			Matches:      b.matches[i],
+		}
+		if b.total > 0 {
+			stats.HitRate = float64(b.matches[i]) / float64(b.total)
+		}
+		report.Rules[i] = stats
+	}
+
+	for key, count := range b.transitions {
+		report.Transitions = append(report.Transitions, BacktestTransition{
+			ActualStatus:   key[0],
+			BacktestStatus: key[1],
+			Count:          count,
+		})
+	}
+	sort.Slice(report.Transitions, func(i, j int) bool {
+		a, c := report.Transitions[i], report.Transitions[j]
+		if a.Count != c.Count {
+			return a.Count > c.Count
+		}
+		if a.ActualStatus != c.ActualStatus {
+			return a.ActualStatus < c.ActualStatus
+		}
+		return a.BacktestStatus < c.BacktestStatus
+	})
+
+	return report
+}
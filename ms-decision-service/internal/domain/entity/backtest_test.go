@@ -0,0 +1,70 @@
+package entity
+
+import "testing"
+
+func TestBacktest_Report(t *testing.T) {
+	rules := []Rule{
+		{RuleID: "crypto", RuleName: "Block CRYPTO", ConditionField: FieldPaymentMethod, ConditionOperator: OpEqual, ConditionValue: "CRYPTO", ResultStatus: DECLINED, Priority: 1},
+		{RuleID: "high-value", RuleName: "Decline high-value", ConditionField: FieldAmountInCents, ConditionOperator: OpGreaterThan, ConditionValue: "2500000", ResultStatus: DECLINED, Priority: 2},
+		{RuleID: "medium-value", RuleName: "Fraud check medium-value", ConditionField: FieldAmountInCents, ConditionOperator: OpGreaterThan, ConditionValue: "500000", ResultStatus: FRAUDCHECK, Priority: 3},
+	}
+	backtest := NewBacktest(DefaultDecisionPolicy(RuleSetTransaction), rules)
+
+	replays := []struct {
+		tx     TransactionMessage
+		actual DecisionStatus
+		want   DecisionStatus
+	}{
+		{TransactionMessage{ID: "txn-1", PaymentMethod: "CRYPTO", AmountInCents: 100}, DECLINED, DECLINED},
+		{TransactionMessage{ID: "txn-2", PaymentMethod: "CARD", AmountInCents: 3000000}, APPROVED, DECLINED},
+		{TransactionMessage{ID: "txn-3", PaymentMethod: "CARD", AmountInCents: 4000000}, APPROVED, DECLINED},
+		{TransactionMessage{ID: "txn-4", PaymentMethod: "CARD", AmountInCents: 1000000}, DECLINED, FRAUDCHECK},
+		{TransactionMessage{ID: "txn-5", PaymentMethod: "CARD", AmountInCents: 100}, "PENDING", APPROVED},
+	}
+	for _, r := range replays {
+		tx := r.tx
+		if got := backtest.Replay(tx.ID, &tx, r.actual); got != r.want {
+			t.Errorf("%s: expected %s, got %s", tx.ID, r.want, got)
+		}
+	}
+
+	report := backtest.Report()
+
+	if report.TransactionCount != 5 || report.Compared != 4 || report.Changed != 3 {
+		t.Errorf("unexpected counts: %+v", report)
+	}
+	if report.Decisions[DECLINED] != 3 || report.Decisions[FRAUDCHECK] != 1 || report.Decisions[APPROVED] != 1 {
+		t.Errorf("unexpected decisions: %v", report.Decisions)
+	}
+
+	wantMatches := map[string]int{"crypto": 1, "high-value": 2, "medium-value": 3}
+	for _, stats := range report.Rules {
+		if stats.Matches != wantMatches[stats.RuleID] {
+			t.Errorf("%s: expected %d matches, got %d", stats.RuleID, wantMatches[stats.RuleID], stats.Matches)
+		}
+		if stats.HitRate != float64(stats.Matches)/5 {
+			t.Errorf("%s: unexpected hit rate %v", stats.RuleID, stats.HitRate)
+		}
+	}
+
+	if len(report.Transitions) != 2 {
+		t.Fatalf("expected two transitions, got %+v", report.Transitions)
+	}
+	if first := report.Transitions[0]; first.ActualStatus != APPROVED || first.BacktestStatus != DECLINED || first.Count != 2 {
+		t.Errorf("expected APPROVED -> DECLINED first, got %+v", first)
+	}
+	if len(report.SampleChangedTransactionIDs) != 3 {
+		t.Errorf("expected three sample transactions, got %v", report.SampleChangedTransactionIDs)
+	}
+}
+
+func TestBacktest_EmptyReport(t *testing.T) {
+	report := NewBacktest(DefaultDecisionPolicy(RuleSetTransaction), nil).Report()
+
+	if report.TransactionCount != 0 || report.Transitions == nil || report.SampleChangedTransactionIDs == nil {
+		t.Errorf("expected an empty report with empty slices, got %+v", report)
+	}
+	if _, ok := report.Decisions[APPROVED]; !ok {
+		t.Errorf("expected every decision to be reported, got %v", report.Decisions)
+	}
+}
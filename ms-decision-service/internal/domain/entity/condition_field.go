@@ -0,0 +1,133 @@
+package entity
+
+import "sort"
+
+// ConditionField represents a transaction attribute that a rule can evaluate against.
+type ConditionField string
+
+const (
+	FieldTransactionID     ConditionField = "transaction_id"
+	FieldAmountInCents     ConditionField = "amount_in_cents"
+	FieldCurrency          ConditionField = "currency"
+	FieldPaymentMethod     ConditionField = "payment_method"
+	FieldCustomerID        ConditionField = "customer_id"
+	FieldCustomerName      ConditionField = "customer_name"
+	FieldCustomerEmail     ConditionField = "customer_email"
+	FieldCustomerPhone     ConditionField = "customer_phone"
+	FieldCustomerIPAddress ConditionField = "customer_ip_address"
+	FieldStatus            ConditionField = "status"
+	FieldCreatedAt         ConditionField = "created_at"
+	FieldUpdatedAt         ConditionField = "updated_at"
+	FieldFraudScore        ConditionField = "fraud_score"
+
+	// Derived fields are computed from the transaction attributes at evaluation time.
+	FieldEmailDomain      ConditionField = "email_domain"
+	FieldHourOfDayUTC     ConditionField = "hour_of_day_utc"
+	FieldDayOfWeek        ConditionField = "day_of_week"
+	FieldAmountInUSDCents ConditionField = "amount_in_usd_cents"
+	FieldIPVersion        ConditionField = "ip_version"
+)
+
+// FieldType determines how a field's value is compared against a condition value.
+type FieldType string
+
+const (
+	// FieldTypeNumeric values are parsed as int64 and compared numerically.
+	FieldTypeNumeric FieldType = "NUMERIC"
+	// FieldTypeString values are compared as plain strings.
+	FieldTypeString FieldType = "STRING"
+	// FieldTypeTime values are RFC 3339 timestamps compared chronologically.
+	FieldTypeTime FieldType = "TIME"
+)
+
+// FieldDefinition describes a condition field in the field registry.
+type FieldDefinition struct {
+	Field       ConditionField `json:"field"`
+	Type        FieldType      `json:"type"`
+	Derived     bool           `json:"derived"`
+	Description string         `json:"description"`
+}
+
+// fieldRegistry lists every field the rules engine knows how to evaluate.
+var fieldRegistry = map[ConditionField]FieldDefinition{
+	FieldTransactionID:     {Type: FieldTypeString, Description: "Transaction identifier"},
+	FieldAmountInCents:     {Type: FieldTypeNumeric, Description: "Amount in minor units of the transaction currency"},
+	FieldCurrency:          {Type: FieldTypeString, Description: "ISO 4217 currency code"},
+	FieldPaymentMethod:     {Type: FieldTypeString, Description: "Payment method, e.g. CARD or CRYPTO"},
+	FieldCustomerID:        {Type: FieldTypeString, Description: "Customer identifier"},
+	FieldCustomerName:      {Type: FieldTypeString, Description: "Customer full name"},
+	FieldCustomerEmail:     {Type: FieldTypeString, Description: "Customer email address"},
+	FieldCustomerPhone:     {Type: FieldTypeString, Description: "Customer phone number"},
+	FieldCustomerIPAddress: {Type: FieldTypeString, Description: "Customer IPv4 or IPv6 address"},
+	FieldStatus:            {Type: FieldTypeString, Description: "Transaction status when it was published"},
+	FieldCreatedAt:         {Type: FieldTypeTime, Description: "Transaction creation time"},
+	FieldUpdatedAt:         {Type: FieldTypeTime, Description: "Transaction last update time"},
+	FieldFraudScore:        {Type: FieldTypeNumeric, Description: "Fraud score (0-100) from the fraud signals service"},
+	FieldEmailDomain: {
+		Type: FieldTypeString, Derived: true,
+		Description: "Lower-cased domain of customer_email",
+	},
+	FieldHourOfDayUTC: {
+		Type: FieldTypeNumeric, Derived: true,
+		Description: "Hour of created_at in UTC (0-23)",
+	},
+	FieldDayOfWeek: {
+		Type: FieldTypeString, Derived: true,
+		Description: "Weekday of created_at in UTC, e.g. SATURDAY",
+	},
+	FieldAmountInUSDCents: {
+		Type: FieldTypeNumeric, Derived: true,
+		Description: "amount_in_cents converted to US cents using reference exchange rates",
+	},
+	FieldIPVersion: {
+		Type: FieldTypeNumeric, Derived: true,
+		Description: "4 or 6 depending on customer_ip_address",
+	},
+}
+
+// Definition returns the registry entry for the field and whether it exists.
+func (f ConditionField) Definition() (FieldDefinition, bool) {
+	def, ok := fieldRegistry[f]
+	if !ok {
+		return FieldDefinition{}, false
+	}
+	def.Field = f
+	return def, true
+}
+
+// IsValid reports whether the field is one the rules engine knows how to evaluate.
+func (f ConditionField) IsValid() bool {
+	_, ok := fieldRegistry[f]
+	return ok
+}
+
+// Type returns how the field is compared. Unknown fields are compared as strings.
+func (f ConditionField) Type() FieldType {
+	if def, ok := fieldRegistry[f]; ok {
+		return def.Type
+	}
+	return FieldTypeString
+}
+
+// IsNumeric reports whether the field is compared numerically.
+func (f ConditionField) IsNumeric() bool {
+	return f.Type() == FieldTypeNumeric
+}
+
+// IsTime reports whether the field is compared chronologically.
+func (f ConditionField) IsTime() bool {
+	return f.Type() == FieldTypeTime
+}
+
+// fields returns the definition of every registered field, sorted by name.
+func fields() []FieldDefinition {
+	defs := make([]FieldDefinition, 0, len(fieldRegistry))
+	for field := range fieldRegistry {
+		def, _ := field.Definition()
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Field < defs[j].Field
+	})
+	return defs
+}
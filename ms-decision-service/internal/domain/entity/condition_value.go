@@ -0,0 +1,171 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// valueMatcher is a condition value compiled once for a set-membership or pattern
+// operator, so evaluation does not re-parse lists, CIDRs or regular expressions
+// for every transaction.
+type valueMatcher struct {
+	operator ConditionOperator
+	numeric  bool
+	values   map[string]struct{}
+	prefixes []netip.Prefix
+	pattern  *regexp.Regexp
+	literal  string
+}
+
+// compileValueMatcher compiles the condition value for the operator. Comparison
+// and managed list operators need no compilation and return a nil matcher; for
+// managed list operators the value is only checked to be a valid list name.
+func compileValueMatcher(op ConditionOperator, value string, field ConditionField) (*valueMatcher, error) {
+	m := &valueMatcher{operator: op, numeric: field.IsNumeric()}
+
+	switch op {
+	case OpIn, OpNotIn:
+		items, err := parseValueList(value)
+		if err != nil {
+			return nil, err
+		}
+		m.values = make(map[string]struct{}, len(items))
+		for _, item := range items {
+			if m.numeric {
+				n, err := strconv.ParseInt(item, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("list value %q must be an integer for field %s", item, field)
+				}
+				item = strconv.FormatInt(n, 10)
+			}
+			m.values[item] = struct{}{}
+		}
+	case OpInCIDR:
+		items, err := parseValueList(value)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			prefix, err := parsePrefix(item)
+			if err != nil {
+				return nil, err
+			}
+			m.prefixes = append(m.prefixes, prefix)
+		}
+	case OpMatchesRegex:
+		pattern, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		m.pattern = pattern
+	case OpStartsWith, OpEndsWith:
+		m.literal = value
+	case OpInList, OpNotInList:
+		// Lists are resolved at evaluation time; only the name is checked here.
+		return nil, validateListName(value)
+	default:
+		return nil, nil
+	}
+
+	return m, nil
+}
+
+// match reports whether the field value satisfies the compiled condition.
+func (m *valueMatcher) match(fieldValue string) bool {
+	switch m.operator {
+	case OpIn, OpNotIn:
+		if m.numeric {
+			n, err := strconv.ParseInt(fieldValue, 10, 64)
+			if err != nil {
+				return false
+			}
+			fieldValue = strconv.FormatInt(n, 10)
+		}
+		_, found := m.values[fieldValue]
+		return found == (m.operator == OpIn)
+	case OpInCIDR:
+		addr, err := netip.ParseAddr(fieldValue)
+		if err != nil {
+			return false
+		}
+		addr = addr.Unmap()
+		for _, prefix := range m.prefixes {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	case OpMatchesRegex:
+		return m.pattern.MatchString(fieldValue)
+	case OpStartsWith:
+		return strings.HasPrefix(fieldValue, m.literal)
+	case OpEndsWith:
+		return strings.HasSuffix(fieldValue, m.literal)
+	default:
+		return false
+	}
+}
+
+// parseValueList splits a list condition value. Values are either a JSON array
+// of strings or numbers (e.g. ["USD","EUR"]) or a comma-separated list
+// (e.g. "USD, EUR"); surrounding whitespace is ignored.
+func parseValueList(value string) ([]string, error) {
+	trimmed := strings.TrimSpace(value)
+
+	var items []string
+	if strings.HasPrefix(trimmed, "[") {
+		decoder := json.NewDecoder(bytes.NewReader([]byte(trimmed)))
+		decoder.UseNumber()
+
+		var raw []interface{}
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("invalid JSON list: %w", err)
+		}
+		for _, item := range raw {
+			switch v := item.(type) {
+			case string:
+				items = append(items, strings.TrimSpace(v))
+			case json.Number:
+				items = append(items, v.String())
+			default:
+				return nil, fmt.Errorf("list values must be strings or numbers, got %v", item)
+			}
+		}
+	} else {
+		for _, item := range strings.Split(trimmed, ",") {
+			items = append(items, strings.TrimSpace(item))
+		}
+	}
+
+	values := items[:0]
+	for _, item := range items {
+		if item != "" {
+			values = append(values, item)
+		}
+	}
+	if len(values) == 0 {
+		return nil, errors.New("list must contain at least one value")
+	}
+
+	return values, nil
+}
+
+// parsePrefix parses a CIDR block. A bare IP address is treated as a single-host block.
+func parsePrefix(value string) (netip.Prefix, error) {
+	if prefix, err := netip.ParsePrefix(value); err == nil {
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("%q is not a valid CIDR block or IP address", value)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
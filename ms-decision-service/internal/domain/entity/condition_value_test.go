@@ -0,0 +1,178 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConditionOperator_CompareSetAndPatternOperators(t *testing.T) {
+	tests := []struct {
+		name           string
+		op             ConditionOperator
+		field          ConditionField
+		fieldValue     string
+		conditionValue string
+		want           bool
+	}{
+		{"IN matches comma-separated list", OpIn, FieldCurrency, "EUR", "USD, EUR,COP", true},
+		{"IN matches JSON list", OpIn, FieldCurrency, "COP", `["USD","COP"]`, true},
+		{"IN does not match missing value", OpIn, FieldCurrency, "GBP", "USD,EUR", false},
+		{"IN compares numeric fields numerically", OpIn, FieldAmountInCents, "0100", "[100, 200]", true},
+		{"IN on numeric field rejects non-numeric value", OpIn, FieldAmountInCents, "abc", "100", false},
+		{"NOT_IN matches missing value", OpNotIn, FieldPaymentMethod, "CARD", "CRYPTO,WIRE", true},
+		{"NOT_IN does not match listed value", OpNotIn, FieldPaymentMethod, "CRYPTO", "CRYPTO,WIRE", false},
+		{"IN_CIDR matches address inside block", OpInCIDR, FieldCustomerIPAddress, "10.1.2.3", "10.0.0.0/8", true},
+		{"IN_CIDR matches any listed block", OpInCIDR, FieldCustomerIPAddress, "192.168.1.7", "10.0.0.0/8, 192.168.1.0/24", true},
+		{"IN_CIDR treats bare address as single host", OpInCIDR, FieldCustomerIPAddress, "203.0.113.9", "203.0.113.9", true},
+		{"IN_CIDR matches IPv6 block", OpInCIDR, FieldCustomerIPAddress, "2001:db8::1", "2001:db8::/32", true},
+		{"IN_CIDR matches IPv4-mapped address", OpInCIDR, FieldCustomerIPAddress, "::ffff:10.0.0.1", "10.0.0.0/8", true},
+		{"IN_CIDR does not match address outside block", OpInCIDR, FieldCustomerIPAddress, "11.0.0.1", "10.0.0.0/8", false},
+		{"IN_CIDR does not match invalid address", OpInCIDR, FieldCustomerIPAddress, "not-an-ip", "10.0.0.0/8", false},
+		{"MATCHES_REGEX matches pattern", OpMatchesRegex, FieldCustomerID, "cust-00042", `^cust-\d+$`, true},
+		{"MATCHES_REGEX does not match other values", OpMatchesRegex, FieldCustomerID, "vip-1", `^cust-\d+$`, false},
+		{"MATCHES_REGEX with invalid pattern never matches", OpMatchesRegex, FieldCustomerID, "cust-1", "cust-([", false},
+		{"STARTS_WITH matches prefix", OpStartsWith, FieldCustomerID, "test-123", "test-", true},
+		{"STARTS_WITH does not match other prefix", OpStartsWith, FieldCustomerID, "cust-123", "test-", false},
+		{"ENDS_WITH matches suffix", OpEndsWith, FieldCustomerID, "alice@mailinator.com", "@mailinator.com", true},
+		{"ENDS_WITH does not match other suffix", OpEndsWith, FieldCustomerID, "alice@example.com", "@mailinator.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.op.Compare(tt.fieldValue, tt.conditionValue, tt.field); got != tt.want {
+				t.Errorf("Compare(%q, %q) = %v, want %v", tt.fieldValue, tt.conditionValue, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionOperator_SupportsField(t *testing.T) {
+	tests := []struct {
+		op    ConditionOperator
+		field ConditionField
+		want  bool
+	}{
+		{OpIn, FieldCurrency, true},
+		{OpNotIn, FieldAmountInCents, true},
+		{OpInCIDR, FieldCustomerIPAddress, true},
+		{OpInCIDR, FieldCustomerID, false},
+		{OpMatchesRegex, FieldCustomerID, true},
+		{OpMatchesRegex, FieldFraudScore, false},
+		{OpStartsWith, FieldAmountInCents, false},
+		{OpGreaterThan, FieldCurrency, false},
+		{OpGreaterThan, FieldFraudScore, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.op.SupportsField(tt.field); got != tt.want {
+			t.Errorf("%s.SupportsField(%s) = %v, want %v", tt.op, tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestParseValueList(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "comma-separated values are trimmed", value: " USD , EUR,, COP ", want: []string{"USD", "EUR", "COP"}},
+		{name: "JSON strings and numbers", value: `["USD", 100]`, want: []string{"USD", "100"}},
+		{name: "single value", value: "USD", want: []string{"USD"}},
+		{name: "empty list", value: ",", wantErr: true},
+		{name: "malformed JSON", value: `["USD"`, wantErr: true},
+		{name: "nested JSON values", value: `[["USD"]]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseValueList(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseValueList(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseValueList(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRule_Compile(t *testing.T) {
+	rule := Rule{
+		RuleName: "Risky IP or disposable email",
+		Condition: &ConditionNode{
+			Logic: LogicOr,
+			Children: []ConditionNode{
+				{Field: FieldCustomerIPAddress, Operator: OpInCIDR, Value: "10.0.0.0/8"},
+				{Field: FieldCustomerID, Operator: OpEndsWith, Value: "@mailinator.com"},
+			},
+		},
+		ResultStatus: DECLINED,
+	}
+
+	if err := rule.Compile(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.Condition.Children[0].matcher != nil {
+		t.Error("Compile must not modify the stored condition tree")
+	}
+	if rule.compiled.Children[0].matcher == nil {
+		t.Error("expected IN_CIDR leaf to be pre-compiled")
+	}
+
+	if !rule.Matches(&TransactionMessage{CustomerIPAddress: "10.9.9.9"}) {
+		t.Error("expected compiled rule to match address inside block")
+	}
+	if rule.Matches(&TransactionMessage{CustomerIPAddress: "8.8.8.8", CustomerID: "bob@example.com"}) {
+		t.Error("expected compiled rule not to match")
+	}
+
+	result := NewRuleEvaluationResult("tx-1", &rule, &TransactionMessage{CustomerID: "eve@mailinator.com"}, 1, time.Now())
+	if !result.Matched || len(result.ConditionResults) != 2 || !result.ConditionResults[1].Matched {
+		t.Errorf("unexpected evaluation result: %+v", result)
+	}
+}
+
+func TestRule_CompileReportsInvalidValues(t *testing.T) {
+	rule := Rule{
+		RuleName:          "Broken pattern",
+		ConditionField:    FieldCustomerID,
+		ConditionOperator: OpMatchesRegex,
+		ConditionValue:    "cust-([",
+		ResultStatus:      DECLINED,
+	}
+
+	if err := rule.Compile(); err == nil {
+		t.Fatal("expected compile error for invalid regular expression")
+	}
+	if rule.Matches(&TransactionMessage{CustomerID: "cust-(["}) {
+		t.Error("rule with invalid pattern must never match")
+	}
+}
+
+func TestConditionOperator_CompareTimeFields(t *testing.T) {
+	createdAt := "2025-01-15T10:30:00Z"
+
+	tests := []struct {
+		op             ConditionOperator
+		conditionValue string
+		want           bool
+	}{
+		{OpGreaterThan, "2025-01-15T10:00:00Z", true},
+		{OpLessThan, "2025-01-15T10:00:00Z", false},
+		{OpEqual, "2025-01-15T05:30:00-05:00", true},
+		{OpGreaterThanOrEqual, "2025-01-15T10:30:00Z", true},
+		{OpLessThanOrEqual, "2025-01-15T10:29:59.5Z", false},
+		{OpGreaterThan, "yesterday", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.op)+" "+tt.conditionValue, func(t *testing.T) {
+			if got := tt.op.Compare(createdAt, tt.conditionValue, FieldCreatedAt); got != tt.want {
+				t.Errorf("Compare(%q, %q) = %v, want %v", createdAt, tt.conditionValue, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CurrencyRates converts one unit of a currency into US dollars, keyed by upper-case
+// currency code. They are reference rates for comparing amounts across currencies,
+// not settlement rates.
+type CurrencyRates map[string]float64
+
+// ParseCurrencyRates parses rates written as comma-separated CODE=rate pairs, e.g.
+// "USD=1,EUR=1.08,COP=0.00025".
+func ParseCurrencyRates(value string) (CurrencyRates, error) {
+	rates := CurrencyRates{}
+	for _, pair := range strings.Split(value, ",") {
+		code, rate, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid currency rate %q: expected CODE=rate", pair)
+		}
+
+		code = strings.ToUpper(strings.TrimSpace(code))
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(rate), 64)
+		if code == "" || err != nil || parsed <= 0 || math.IsInf(parsed, 0) {
+			return nil, fmt.Errorf("invalid currency rate %q: expected a currency code and a positive rate", pair)
+		}
+		rates[code] = parsed
+	}
+	return rates, nil
+}
+
+// ToUSDCents converts an amount in cents of the given currency into US cents. It
+// reports false when there is no rate for the currency.
+func (r CurrencyRates) ToUSDCents(amountInCents int64, currency string) (int64, bool) {
+	rate, ok := r[strings.ToUpper(currency)]
+	if !ok {
+		return 0, false
+	}
+	return int64(math.Round(float64(amountInCents) * rate)), true
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCurrencyRates(t *testing.T) {
+	rates, err := ParseCurrencyRates("USD=1, eur = 1.08,COP=0.00025")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := CurrencyRates{"USD": 1, "EUR": 1.08, "COP": 0.00025}
+	if !reflect.DeepEqual(rates, want) {
+		t.Errorf("ParseCurrencyRates = %v, want %v", rates, want)
+	}
+
+	for _, value := range []string{"", "USD", "USD=abc", "=1", "EUR=0", "EUR=-1"} {
+		if _, err := ParseCurrencyRates(value); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestCurrencyRates_ToUSDCents(t *testing.T) {
+	rates := CurrencyRates{"USD": 1, "COP": 0.00025}
+
+	if amount, ok := rates.ToUSDCents(400000000, "cop"); !ok || amount != 100000 {
+		t.Errorf("expected 100000 US cents, got %d (%v)", amount, ok)
+	}
+	if _, ok := rates.ToUSDCents(100, "GBP"); ok {
+		t.Error("expected no conversion without a rate")
+	}
+}
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrDeadLetterIDInvalid is returned when a dead letter ID is not of the form
+// <partition>-<offset>.
+var ErrDeadLetterIDInvalid = errors.New("dead letter ID must be <partition>-<offset>")
+
+// DeadLetter is a Transaction.Created or FraudSignals.Calculated message that could
+// not be processed, as published to the matching dead-letter topic. Key, Payload
+// and Headers are those of the original message, so it can be redriven unchanged.
+// TransactionID is read from the payload and is empty when the payload is
+// malformed. Attempts counts the processing attempts made before giving up.
+// Partition and Offset locate the message in the dead-letter topic once it has
+// been published.
+type DeadLetter struct {
+	ID              string            `json:"id"`
+	Partition       int32             `json:"partition"`
+	Offset          int64             `json:"offset"`
+	TransactionID   string            `json:"transaction_id,omitempty"`
+	SourceTopic     string            `json:"source_topic"`
+	SourcePartition int32             `json:"source_partition"`
+	SourceOffset    int64             `json:"source_offset"`
+	Key             string            `json:"key"`
+	Payload         string            `json:"payload"`
+	Headers         map[string]string `json:"headers"`
+	Error           string            `json:"error"`
+	Attempts        int               `json:"attempts"`
+	FailedAt        time.Time         `json:"failed_at"`
+}
+
+// DeadLetterID returns the ID of the message at the given position of a
+// dead-letter topic.
+func DeadLetterID(partition int32, offset int64) string {
+	return fmt.Sprintf("%d-%d", partition, offset)
+}
+
+// ParseDeadLetterID returns the position in its dead-letter topic of the message
+// with the given ID.
+func ParseDeadLetterID(id string) (int32, int64, error) {
+	rawPartition, rawOffset, ok := strings.Cut(id, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: %q", ErrDeadLetterIDInvalid, id)
+	}
+
+	partition, err := strconv.ParseInt(rawPartition, 10, 32)
+	if err != nil || partition < 0 {
+		return 0, 0, fmt.Errorf("%w: %q", ErrDeadLetterIDInvalid, id)
+	}
+	offset, err := strconv.ParseInt(rawOffset, 10, 64)
+	if err != nil || offset < 0 {
+		return 0, 0, fmt.Errorf("%w: %q", ErrDeadLetterIDInvalid, id)
+	}
+
+	return int32(partition), offset, nil
+}
+
+// DeadLetterQueue is a dead-letter topic and the topic its messages are redriven
+// to. Count is the number of messages retained in the topic, which Kafka keeps
+// after they have been redriven.
+type DeadLetterQueue struct {
+	Topic       string `json:"topic"`
+	SourceTopic string `json:"source_topic"`
+	Count       int64  `json:"count"`
+}
+
+// RedriveFailure describes a dead letter that could not be redriven.
+type RedriveFailure struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// RedriveResult is the outcome of redriving dead letters back to their source
+// topic. In a dry run, RedrivenIDs lists the messages that would have been
+// redriven and nothing is published.
+type RedriveResult struct {
+	Topic       string           `json:"topic"`
+	SourceTopic string           `json:"source_topic"`
+	DryRun      bool             `json:"dry_run"`
+	Selected    int              `json:"selected"`
+	Redriven    int              `json:"redriven"`
+	RedrivenIDs []string         `json:"redriven_ids"`
+	Failed      []RedriveFailure `json:"failed"`
+}
@@ -0,0 +1,247 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// RuleSet identifies the stage of the pipeline a group of rules is evaluated in.
+// Rules referencing fraud_score form the FRAUD_SCORE rule set; every other rule
+// belongs to the TRANSACTION rule set.
+type RuleSet string
+
+const (
+	RuleSetTransaction RuleSet = "TRANSACTION"
+	RuleSetFraudScore  RuleSet = "FRAUD_SCORE"
+)
+
+// RuleSets lists every rule set in evaluation order.
+var RuleSets = []RuleSet{RuleSetTransaction, RuleSetFraudScore}
+
+// IsValid reports whether the rule set is known.
+func (s RuleSet) IsValid() bool {
+	return s == RuleSetTransaction || s == RuleSetFraudScore
+}
+
+// RuleSet returns the rule set the rule is evaluated in.
+func (r *Rule) RuleSet() RuleSet {
+	if r.ReferencesField(FieldFraudScore) {
+		return RuleSetFraudScore
+	}
+	return RuleSetTransaction
+}
+
+// RulesInSet returns the rules belonging to the rule set, keeping their order.
+func RulesInSet(rules []Rule, ruleSet RuleSet) []Rule {
+	var filtered []Rule
+	for i := range rules {
+		if rules[i].RuleSet() == ruleSet {
+			filtered = append(filtered, rules[i])
+		}
+	}
+	return filtered
+}
+
+// DecisionMode is the strategy used to turn the rules of a rule set into a decision.
+type DecisionMode string
+
+const (
+	// ModeFirstMatch returns the result status of the first matching rule.
+	ModeFirstMatch DecisionMode = "FIRST_MATCH"
+	// ModeScore adds up the score weights of every matching rule and compares the
+	// total against the policy thresholds.
+	ModeScore DecisionMode = "SCORE"
+)
+
+// IsValid reports whether the mode is known.
+func (m DecisionMode) IsValid() bool {
+	return m == ModeFirstMatch || m == ModeScore
+}
+
+// MaxScoreWeight bounds the absolute score weight of a single rule.
+const MaxScoreWeight = 1000
+
+// DecisionPolicy selects how the rules of a rule set are turned into a decision.
+// In SCORE mode a total at or above DeclineThreshold is DECLINED, a total at or
+// above FraudCheckThreshold is FRAUD_CHECK and anything lower is APPROVED.
+// FraudCheckThreshold is optional and not allowed for the FRAUD_SCORE rule set,
+// which runs after the fraud check.
+type DecisionPolicy struct {
+	RuleSet             RuleSet      `json:"rule_set"`
+	Mode                DecisionMode `json:"mode"`
+	FraudCheckThreshold *int         `json:"fraud_check_threshold,omitempty"`
+	DeclineThreshold    int          `json:"decline_threshold"`
+	UpdatedBy           string       `json:"updated_by,omitempty"`
+	UpdatedAt           time.Time    `json:"updated_at,omitempty"`
+}
+
+// DefaultDecisionPolicy returns the policy used for a rule set that has never
+// been configured: first match wins.
+func DefaultDecisionPolicy(ruleSet RuleSet) DecisionPolicy {
+	return DecisionPolicy{RuleSet: ruleSet, Mode: ModeFirstMatch}
+}
+
+// Validate checks the policy and returns every violation found.
+func (p *DecisionPolicy) Validate() []RuleViolation {
+	var violations []RuleViolation
+
+	if !p.RuleSet.IsValid() {
+		violations = append(violations, RuleViolation{
+			Field:   "rule_set",
+			Message: fmt.Sprintf("rule_set %q is invalid", p.RuleSet),
+		})
+	}
+
+	if !p.Mode.IsValid() {
+		violations = append(violations, RuleViolation{
+			Field:   "mode",
+			Message: fmt.Sprintf("mode %q is invalid", p.Mode),
+		})
+	}
+
+	if p.Mode != ModeScore {
+		return violations
+	}
+
+	if p.DeclineThreshold <= 0 {
+		violations = append(violations, RuleViolation{
+			Field:   "decline_threshold",
+			Message: "decline_threshold must be positive",
+		})
+	}
+
+	if p.FraudCheckThreshold != nil {
+		switch {
+		case p.RuleSet == RuleSetFraudScore:
+			violations = append(violations, RuleViolation{
+				Field:   "fraud_check_threshold",
+				Message: "fraud_check_threshold is not allowed for the FRAUD_SCORE rule set",
+			})
+		case *p.FraudCheckThreshold <= 0 || *p.FraudCheckThreshold >= p.DeclineThreshold:
+			violations = append(violations, RuleViolation{
+				Field:   "fraud_check_threshold",
+				Message: "fraud_check_threshold must be positive and lower than decline_threshold",
+			})
+		}
+	}
+
+	return violations
+}
+
+// ValidateRules checks that a SCORE policy can decide anything with the given rules:
+// at least one LIVE rule of the rule set must carry a score weight, and the positive
+// weights of those rules must add up to every threshold. Inactive rules, SHADOW
+// rules and rules of other rule sets are ignored. Other modes are always valid.
+func (p *DecisionPolicy) ValidateRules(rules []Rule) []RuleViolation {
+	if p.Mode != ModeScore {
+		return nil
+	}
+
+	weighted, reachable := 0, 0
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.IsActive || rule.IsShadow() || rule.ScoreWeight == 0 || rule.RuleSet() != p.RuleSet {
+			continue
+		}
+		weighted++
+		reachable += max(rule.ScoreWeight, 0)
+	}
+
+	if weighted == 0 {
+		return []RuleViolation{{
+			Field:   "mode",
+			Message: fmt.Sprintf("SCORE mode needs at least one active LIVE rule of the %s rule set with a score_weight", p.RuleSet),
+		}}
+	}
+
+	var violations []RuleViolation
+	if p.FraudCheckThreshold != nil && reachable < *p.FraudCheckThreshold {
+		violations = append(violations, RuleViolation{
+			Field:   "fraud_check_threshold",
+			Message: fmt.Sprintf("fraud_check_threshold cannot be reached: the rules add up to at most %d", reachable),
+		})
+	}
+	if reachable < p.DeclineThreshold {
+		violations = append(violations, RuleViolation{
+			Field:   "decline_threshold",
+			Message: fmt.Sprintf("decline_threshold cannot be reached: the rules add up to at most %d", reachable),
+		})
+	}
+	return violations
+}
+
+// StatusForScore maps a total score to a decision using the policy thresholds.
+func (p *DecisionPolicy) StatusForScore(score int) DecisionStatus {
+	switch {
+	case score >= p.DeclineThreshold:
+		return DECLINED
+	case p.FraudCheckThreshold != nil && score >= *p.FraudCheckThreshold:
+		return FRAUDCHECK
+	default:
+		return APPROVED
+	}
+}
+
+// ScoreContribution is the weight a matching rule added to the total score.
+type ScoreContribution struct {
+	RuleID   string `json:"rule_id"`
+	RuleName string `json:"rule_name"`
+	Weight   int    `json:"weight"`
+}
+
+// Evaluation is the outcome of evaluating a transaction against a rule set.
+// Score and Contributions are only set in SCORE mode.
+type Evaluation struct {
+	Status        DecisionStatus
+	Mode          DecisionMode
+	Score         int
+	Contributions []ScoreContribution
+}
+
+// Evaluate applies the policy to the rules. In FIRST_MATCH mode it behaves like
+// EvaluateRules; in SCORE mode every matching rule contributes its score weight.
+// SHADOW rules never affect the evaluation.
+func (p *DecisionPolicy) Evaluate(src FieldValueSource, rules []Rule) Evaluation {
+	if p.Mode != ModeScore {
+		return Evaluation{Status: EvaluateRules(src, rules), Mode: ModeFirstMatch}
+	}
+
+	evaluation := Evaluation{Mode: ModeScore}
+	for _, rule := range rules {
+		if rule.IsShadow() || rule.ScoreWeight == 0 || !rule.Matches(src) {
+			continue
+		}
+		evaluation.Score += rule.ScoreWeight
+		evaluation.Contributions = append(evaluation.Contributions, ScoreContribution{
+			RuleID:   rule.RuleID,
+			RuleName: rule.RuleName,
+			Weight:   rule.ScoreWeight,
+		})
+	}
+	evaluation.Status = p.StatusForScore(evaluation.Score)
+
+	return evaluation
+}
+
+// ShadowStatus returns the decision the policy would have reached had the SHADOW
+// rule with the given ID been LIVE. Other SHADOW rules stay excluded.
+func (p *DecisionPolicy) ShadowStatus(src FieldValueSource, rules []Rule, ruleID string) DecisionStatus {
+	promoted := make([]Rule, len(rules))
+	copy(promoted, rules)
+	for i := range promoted {
+		if promoted[i].RuleID == ruleID {
+			promoted[i].Mode = RuleModeLive
+		}
+	}
+	return p.Evaluate(src, promoted).Status
+}
+
+// Contribution returns the weight the rule contributed to the evaluation, or zero.
+func (e *Evaluation) Contribution(ruleID string) int {
+	for _, c := range e.Contributions {
+		if c.RuleID == ruleID {
+			return c.Weight
+		}
+	}
+	return 0
+}
@@ -0,0 +1,214 @@
+package entity
+
+import (
+	"testing"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestDecisionPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    DecisionPolicy
+		wantField string
+	}{
+		{
+			name:   "default first match policy is valid",
+			policy: DefaultDecisionPolicy(RuleSetTransaction),
+		},
+		{
+			name:   "score policy with both thresholds is valid",
+			policy: DecisionPolicy{RuleSet: RuleSetTransaction, Mode: ModeScore, FraudCheckThreshold: intPtr(40), DeclineThreshold: 80},
+		},
+		{
+			name:      "unknown rule set",
+			policy:    DecisionPolicy{RuleSet: "CHECKOUT", Mode: ModeFirstMatch},
+			wantField: "rule_set",
+		},
+		{
+			name:      "unknown mode",
+			policy:    DecisionPolicy{RuleSet: RuleSetTransaction, Mode: "WEIGHTED"},
+			wantField: "mode",
+		},
+		{
+			name:      "score policy without decline threshold",
+			policy:    DecisionPolicy{RuleSet: RuleSetTransaction, Mode: ModeScore},
+			wantField: "decline_threshold",
+		},
+		{
+			name:      "fraud check threshold not below decline threshold",
+			policy:    DecisionPolicy{RuleSet: RuleSetTransaction, Mode: ModeScore, FraudCheckThreshold: intPtr(80), DeclineThreshold: 80},
+			wantField: "fraud_check_threshold",
+		},
+		{
+			name:      "fraud check threshold on the fraud score rule set",
+			policy:    DecisionPolicy{RuleSet: RuleSetFraudScore, Mode: ModeScore, FraudCheckThreshold: intPtr(40), DeclineThreshold: 80},
+			wantField: "fraud_check_threshold",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			violations := tc.policy.Validate()
+
+			if tc.wantField == "" {
+				if len(violations) != 0 {
+					t.Fatalf("expected no violations, got %+v", violations)
+				}
+				return
+			}
+			if len(violations) != 1 || violations[0].Field != tc.wantField {
+				t.Fatalf("expected a single violation on %s, got %+v", tc.wantField, violations)
+			}
+		})
+	}
+}
+
+func TestDecisionPolicy_ValidateRules(t *testing.T) {
+	weighted := func(id string, weight int) Rule {
+		return Rule{RuleID: id, ConditionField: FieldAmountInCents, ConditionOperator: OpGreaterThan, ConditionValue: "1000", IsActive: true, ScoreWeight: weight}
+	}
+	shadow := weighted("shadow", 100)
+	shadow.Mode = RuleModeShadow
+	inactive := weighted("inactive", 100)
+	inactive.IsActive = false
+	fraudScore := Rule{RuleID: "fraud", ConditionField: FieldFraudScore, ConditionOperator: OpGreaterThan, ConditionValue: "0.5", IsActive: true, ScoreWeight: 100}
+
+	tests := []struct {
+		name       string
+		policy     DecisionPolicy
+		rules      []Rule
+		wantFields []string
+	}{
+		{
+			name:   "first match policy needs no weights",
+			policy: DefaultDecisionPolicy(RuleSetTransaction),
+		},
+		{
+			name:   "reachable thresholds",
+			policy: DecisionPolicy{RuleSet: RuleSetTransaction, Mode: ModeScore, FraudCheckThreshold: intPtr(40), DeclineThreshold: 80},
+			rules:  []Rule{weighted("a", 50), weighted("b", 30), weighted("c", -10)},
+		},
+		{
+			name:       "no weighted LIVE rule in the rule set",
+			policy:     DecisionPolicy{RuleSet: RuleSetTransaction, Mode: ModeScore, DeclineThreshold: 80},
+			rules:      []Rule{weighted("a", 0), shadow, inactive, fraudScore},
+			wantFields: []string{"mode"},
+		},
+		{
+			name:       "negative weights do not help reach a threshold",
+			policy:     DecisionPolicy{RuleSet: RuleSetTransaction, Mode: ModeScore, FraudCheckThreshold: intPtr(40), DeclineThreshold: 80},
+			rules:      []Rule{weighted("a", 30), weighted("b", -10), shadow},
+			wantFields: []string{"fraud_check_threshold", "decline_threshold"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			violations := tc.policy.ValidateRules(tc.rules)
+
+			if len(violations) != len(tc.wantFields) {
+				t.Fatalf("expected violations on %v, got %+v", tc.wantFields, violations)
+			}
+			for i, field := range tc.wantFields {
+				if violations[i].Field != field {
+					t.Errorf("violation %d: expected field %s, got %+v", i, field, violations[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecisionPolicy_Evaluate(t *testing.T) {
+	rules := []Rule{
+		{RuleID: "crypto", ConditionField: FieldPaymentMethod, ConditionOperator: OpEqual, ConditionValue: "CRYPTO", ResultStatus: DECLINED, Priority: 1, ScoreWeight: 30},
+		{RuleID: "high-value", ConditionField: FieldAmountInCents, ConditionOperator: OpGreaterThan, ConditionValue: "100000", ResultStatus: FRAUDCHECK, Priority: 2, ScoreWeight: 25},
+		{RuleID: "usd", ConditionField: FieldCurrency, ConditionOperator: OpEqual, ConditionValue: "USD", ResultStatus: APPROVED, Priority: 3, ScoreWeight: -10},
+		{RuleID: "unweighted", ConditionField: FieldCurrency, ConditionOperator: OpEqual, ConditionValue: "USD", ResultStatus: DECLINED, Priority: 4},
+	}
+	scorePolicy := DecisionPolicy{RuleSet: RuleSetTransaction, Mode: ModeScore, FraudCheckThreshold: intPtr(20), DeclineThreshold: 50}
+
+	tests := []struct {
+		name       string
+		policy     DecisionPolicy
+		tx         *TransactionMessage
+		wantStatus DecisionStatus
+		wantScore  int
+		wantRules  []string
+	}{
+		{
+			name:       "first match returns the first matching rule's status",
+			policy:     DefaultDecisionPolicy(RuleSetTransaction),
+			tx:         &TransactionMessage{PaymentMethod: "CRYPTO", AmountInCents: 500000, Currency: "USD"},
+			wantStatus: DECLINED,
+		},
+		{
+			name:       "weak signals add up to a decline",
+			policy:     scorePolicy,
+			tx:         &TransactionMessage{PaymentMethod: "CRYPTO", AmountInCents: 500000, Currency: "EUR"},
+			wantStatus: DECLINED,
+			wantScore:  55,
+			wantRules:  []string{"crypto", "high-value"},
+		},
+		{
+			name:       "negative weights lower the total",
+			policy:     scorePolicy,
+			tx:         &TransactionMessage{PaymentMethod: "CRYPTO", AmountInCents: 500000, Currency: "USD"},
+			wantStatus: FRAUDCHECK,
+			wantScore:  45,
+			wantRules:  []string{"crypto", "high-value", "usd"},
+		},
+		{
+			name:       "total below every threshold is approved",
+			policy:     scorePolicy,
+			tx:         &TransactionMessage{PaymentMethod: "CARD", AmountInCents: 500, Currency: "USD"},
+			wantStatus: APPROVED,
+			wantScore:  -10,
+			wantRules:  []string{"usd"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluation := tc.policy.Evaluate(tc.tx, rules)
+
+			if evaluation.Status != tc.wantStatus {
+				t.Errorf("expected status %s, got %s", tc.wantStatus, evaluation.Status)
+			}
+			if evaluation.Score != tc.wantScore {
+				t.Errorf("expected score %d, got %d", tc.wantScore, evaluation.Score)
+			}
+			if len(evaluation.Contributions) != len(tc.wantRules) {
+				t.Fatalf("expected contributions from %v, got %+v", tc.wantRules, evaluation.Contributions)
+			}
+			for i, ruleID := range tc.wantRules {
+				if evaluation.Contributions[i].RuleID != ruleID {
+					t.Errorf("contribution %d: expected %s, got %s", i, ruleID, evaluation.Contributions[i].RuleID)
+				}
+			}
+		})
+	}
+}
+
+func TestRuleEvaluationResult_RecordScore(t *testing.T) {
+	evaluation := Evaluation{
+		Status:        DECLINED,
+		Mode:          ModeScore,
+		Score:         55,
+		Contributions: []ScoreContribution{{RuleID: "crypto", Weight: 30}, {RuleID: "high-value", Weight: 25}},
+	}
+
+	matched := RuleEvaluationResult{RuleID: "high-value"}
+	matched.RecordScore(&evaluation)
+	if matched.DecisionMode != ModeScore || matched.ScoreContribution != 25 || matched.TotalScore != 55 {
+		t.Errorf("unexpected score fields for a contributing rule: %+v", matched)
+	}
+
+	firstMatch := RuleEvaluationResult{RuleID: "crypto"}
+	firstMatch.RecordScore(&Evaluation{Status: DECLINED, Mode: ModeFirstMatch})
+	if firstMatch.DecisionMode != "" || firstMatch.TotalScore != 0 {
+		t.Errorf("expected no score fields in first match mode, got %+v", firstMatch)
+	}
+}
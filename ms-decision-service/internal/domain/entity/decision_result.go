@@ -1,7 +1,23 @@
 package entity
 
+// DecisionPath is how a final decision was reached.
+type DecisionPath string
+
+const (
+	// DecisionPathDirect is a decision made by the transaction rules alone.
+	DecisionPathDirect DecisionPath = "DIRECT"
+	// DecisionPathFraudCheck is a decision made by the fraud-score rules after the
+	// transaction rules yielded FRAUD_CHECK.
+	DecisionPathFraudCheck DecisionPath = "FRAUD_CHECK"
+)
+
 // DecisionResult represents the outcome of evaluating a transaction against the rules engine.
+// Score is set when the decision was reached in SCORE mode. DecisionPath is set on
+// APPROVED and DECLINED decisions.
 type DecisionResult struct {
-	TransactionID string         `json:"transaction_id"`
-	Status        DecisionStatus `json:"status"`
+	TransactionID  string         `json:"transaction_id"`
+	Status         DecisionStatus `json:"status"`
+	RulesetVersion int            `json:"ruleset_version"`
+	Score          *int           `json:"score,omitempty"`
+	DecisionPath   DecisionPath   `json:"decision_path,omitempty"`
 }
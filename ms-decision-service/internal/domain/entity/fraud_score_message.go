@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
 // FraudScoreCalculatedMessage represents the payload consumed from the FraudScore.Calculated Kafka topic.
 type FraudScoreCalculatedMessage struct {
@@ -8,3 +11,12 @@ type FraudScoreCalculatedMessage struct {
 	FraudScore    int       `json:"fraud_score"`
 	CalculatedAt  time.Time `json:"calculated_at"`
 }
+
+// GetFieldValue returns the fraud score for FieldFraudScore and an empty string for
+// any other field, since the fraud score message carries no transaction attributes.
+func (m *FraudScoreCalculatedMessage) GetFieldValue(field ConditionField) string {
+	if field == FieldFraudScore {
+		return strconv.Itoa(m.FraudScore)
+	}
+	return ""
+}
@@ -0,0 +1,200 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListType tells whether a managed list holds known-bad or known-good values.
+type ListType string
+
+const (
+	ListBlocklist ListType = "BLOCKLIST"
+	ListAllowlist ListType = "ALLOWLIST"
+)
+
+// IsValid reports whether the list type is known.
+func (t ListType) IsValid() bool {
+	return t == ListBlocklist || t == ListAllowlist
+}
+
+// listNamePattern restricts list names to values that are safe in URLs and rule conditions.
+var listNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ManagedList is a named set of values of a single transaction field, referenced by
+// rules through the IN_LIST and NOT_IN_LIST operators.
+type ManagedList struct {
+	Name        string         `json:"name"`
+	Type        ListType       `json:"type"`
+	Field       ConditionField `json:"field"`
+	Description string         `json:"description,omitempty"`
+	CreatedBy   string         `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// ListEntry is a single value of a managed list. Entries with an ExpiresAt in the
+// past are ignored during evaluation.
+type ListEntry struct {
+	ListName  string     `json:"list_name"`
+	Value     string     `json:"value"`
+	Reason    string     `json:"reason,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	AddedBy   string     `json:"added_by"`
+	AddedAt   time.Time  `json:"added_at"`
+}
+
+// IsExpired reports whether the entry has expired at the given time.
+func (e *ListEntry) IsExpired(now time.Time) bool {
+	return e.ExpiresAt != nil && !e.ExpiresAt.After(now)
+}
+
+// ListHit records that a value of the evaluated transaction was found in a managed list.
+type ListHit struct {
+	ListName string         `json:"list_name"`
+	ListType ListType       `json:"list_type"`
+	Field    ConditionField `json:"field"`
+	Value    string         `json:"value"`
+}
+
+// NormalizeListValue trims the value and lower-cases fields that are case-insensitive,
+// so list entries and transaction values are compared in the same form.
+func NormalizeListValue(field ConditionField, value string) string {
+	value = strings.TrimSpace(value)
+	switch field {
+	case FieldCustomerEmail, FieldEmailDomain:
+		return strings.ToLower(value)
+	default:
+		return value
+	}
+}
+
+// validateListName returns an error when the name cannot identify a managed list.
+func validateListName(name string) error {
+	if !listNamePattern.MatchString(name) {
+		return fmt.Errorf("list name %q must be 1-64 lower-case letters, digits, '-' or '_'", name)
+	}
+	return nil
+}
+
+// Validate checks the list definition and returns every violation found.
+func (l *ManagedList) Validate() []RuleViolation {
+	var violations []RuleViolation
+
+	if err := validateListName(l.Name); err != nil {
+		violations = append(violations, RuleViolation{Field: "name", Message: err.Error()})
+	}
+
+	if !l.Type.IsValid() {
+		violations = append(violations, RuleViolation{
+			Field:   "type",
+			Message: fmt.Sprintf("type %q is invalid", l.Type),
+		})
+	}
+
+	switch {
+	case !l.Field.IsValid():
+		violations = append(violations, RuleViolation{
+			Field:   "field",
+			Message: fmt.Sprintf("field %q is not supported", l.Field),
+		})
+	case !OpInList.SupportsField(l.Field):
+		violations = append(violations, RuleViolation{
+			Field:   "field",
+			Message: fmt.Sprintf("field %s cannot be used in a list", l.Field),
+		})
+	}
+
+	return violations
+}
+
+// ValidateEntry checks an entry to be added to the list at the given time.
+func (l *ManagedList) ValidateEntry(entry *ListEntry, index int, now time.Time) []RuleViolation {
+	var violations []RuleViolation
+	path := fmt.Sprintf("entries[%d]", index)
+
+	switch {
+	case strings.TrimSpace(entry.Value) == "":
+		violations = append(violations, RuleViolation{Field: path + ".value", Message: "value is required"})
+	case l.Field.IsNumeric():
+		if _, err := strconv.ParseInt(strings.TrimSpace(entry.Value), 10, 64); err != nil {
+			violations = append(violations, RuleViolation{
+				Field:   path + ".value",
+				Message: fmt.Sprintf("value %q must be an integer for field %s", entry.Value, l.Field),
+			})
+		}
+	}
+
+	if entry.IsExpired(now) {
+		violations = append(violations, RuleViolation{Field: path + ".expires_at", Message: "expires_at must be in the future"})
+	}
+
+	return violations
+}
+
+// ListLookup resolves managed list membership during rule evaluation.
+type ListLookup interface {
+	// Lookup returns the list with the given name and, when value is one of its
+	// unexpired entries, that entry. The list is nil when no list with that name
+	// exists for the field.
+	Lookup(listName string, field ConditionField, value string) (*ManagedList, *ListEntry)
+}
+
+// ListSnapshot is an in-memory index of managed lists and their entries.
+type ListSnapshot struct {
+	lists map[string]*indexedList
+	now   func() time.Time
+}
+
+type indexedList struct {
+	list    ManagedList
+	entries map[string]ListEntry
+}
+
+// NewListSnapshot indexes the given lists and entries. Entries of unknown lists are ignored.
+func NewListSnapshot(lists []ManagedList, entries []ListEntry) *ListSnapshot {
+	s := &ListSnapshot{lists: make(map[string]*indexedList, len(lists)), now: time.Now}
+
+	for _, list := range lists {
+		s.lists[list.Name] = &indexedList{list: list, entries: make(map[string]ListEntry)}
+	}
+
+	for _, entry := range entries {
+		indexed, ok := s.lists[entry.ListName]
+		if !ok {
+			continue
+		}
+		indexed.entries[NormalizeListValue(indexed.list.Field, entry.Value)] = entry
+	}
+
+	return s
+}
+
+// Lookup implements ListLookup. Expired entries are treated as absent.
+func (s *ListSnapshot) Lookup(listName string, field ConditionField, value string) (*ManagedList, *ListEntry) {
+	if s == nil {
+		return nil, nil
+	}
+
+	indexed, ok := s.lists[listName]
+	if !ok || indexed.list.Field != field {
+		return nil, nil
+	}
+
+	entry, ok := indexed.entries[NormalizeListValue(field, value)]
+	if !ok || entry.IsExpired(s.now()) {
+		return &indexed.list, nil
+	}
+
+	return &indexed.list, &entry
+}
+
+// ListCount returns the number of lists in the snapshot.
+func (s *ListSnapshot) ListCount() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.lists)
+}
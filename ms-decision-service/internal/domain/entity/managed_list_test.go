@@ -0,0 +1,194 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestListSnapshot() *ListSnapshot {
+	expired := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	return NewListSnapshot(
+		[]ManagedList{
+			{Name: "bad-emails", Type: ListBlocklist, Field: FieldCustomerEmail},
+			{Name: "trusted-customers", Type: ListAllowlist, Field: FieldCustomerID},
+		},
+		[]ListEntry{
+			{ListName: "bad-emails", Value: "Fraudster@Example.com"},
+			{ListName: "bad-emails", Value: "old@example.com", ExpiresAt: &expired},
+			{ListName: "bad-emails", Value: "temp@example.com", ExpiresAt: &future},
+			{ListName: "trusted-customers", Value: "cust-vip"},
+			{ListName: "unknown-list", Value: "ignored"},
+		},
+	)
+}
+
+func TestListSnapshot_Lookup(t *testing.T) {
+	snapshot := newTestListSnapshot()
+
+	tests := []struct {
+		name      string
+		listName  string
+		field     ConditionField
+		value     string
+		wantList  bool
+		wantEntry bool
+	}{
+		{"entry found case-insensitively for emails", "bad-emails", FieldCustomerEmail, " fraudster@example.COM", true, true},
+		{"unexpired entry found", "bad-emails", FieldCustomerEmail, "temp@example.com", true, true},
+		{"expired entry ignored", "bad-emails", FieldCustomerEmail, "old@example.com", true, false},
+		{"missing entry", "bad-emails", FieldCustomerEmail, "good@example.com", true, false},
+		{"unknown list", "no-such-list", FieldCustomerEmail, "fraudster@example.com", false, false},
+		{"list of another field", "bad-emails", FieldCustomerID, "fraudster@example.com", false, false},
+		{"case-sensitive field", "trusted-customers", FieldCustomerID, "CUST-VIP", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, entry := snapshot.Lookup(tt.listName, tt.field, tt.value)
+			if (list != nil) != tt.wantList || (entry != nil) != tt.wantEntry {
+				t.Errorf("Lookup() = (%v, %v), want list %v entry %v", list, entry, tt.wantList, tt.wantEntry)
+			}
+		})
+	}
+}
+
+func TestConditionNode_ListOperators(t *testing.T) {
+	snapshot := newTestListSnapshot()
+
+	tests := []struct {
+		name string
+		node ConditionNode
+		tx   *TransactionMessage
+		want bool
+	}{
+		{
+			name: "IN_LIST matches listed value",
+			node: ConditionNode{Field: FieldCustomerEmail, Operator: OpInList, Value: "bad-emails"},
+			tx:   &TransactionMessage{CustomerEmail: "fraudster@example.com"},
+			want: true,
+		},
+		{
+			name: "IN_LIST does not match unlisted value",
+			node: ConditionNode{Field: FieldCustomerEmail, Operator: OpInList, Value: "bad-emails"},
+			tx:   &TransactionMessage{CustomerEmail: "good@example.com"},
+			want: false,
+		},
+		{
+			name: "NOT_IN_LIST matches unlisted value",
+			node: ConditionNode{Field: FieldCustomerID, Operator: OpNotInList, Value: "trusted-customers"},
+			tx:   &TransactionMessage{CustomerID: "cust-new"},
+			want: true,
+		},
+		{
+			name: "NOT_IN_LIST does not match missing value",
+			node: ConditionNode{Field: FieldCustomerID, Operator: OpNotInList, Value: "trusted-customers"},
+			tx:   &TransactionMessage{},
+			want: false,
+		},
+		{
+			name: "NOT_IN_LIST does not match unknown list",
+			node: ConditionNode{Field: FieldCustomerID, Operator: OpNotInList, Value: "no-such-list"},
+			tx:   &TransactionMessage{CustomerID: "cust-new"},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &EnrichedTransaction{TransactionMessage: tt.tx, Lists: snapshot}
+			if got := tt.node.Evaluate(src); got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("sources without lists never match", func(t *testing.T) {
+		node := ConditionNode{Field: FieldCustomerID, Operator: OpNotInList, Value: "trusted-customers"}
+		if node.Evaluate(&TransactionMessage{CustomerID: "cust-new"}) {
+			t.Error("expected list condition not to match without a list lookup")
+		}
+	})
+}
+
+func TestNewRuleEvaluationResult_RecordsListHits(t *testing.T) {
+	rule := Rule{
+		RuleID:   "rule-lists",
+		RuleName: "Blocked email unless trusted",
+		Condition: &ConditionNode{
+			Logic: LogicAnd,
+			Children: []ConditionNode{
+				{Field: FieldCustomerEmail, Operator: OpInList, Value: "bad-emails"},
+				{Field: FieldCustomerID, Operator: OpNotInList, Value: "trusted-customers"},
+			},
+		},
+		ResultStatus: DECLINED,
+	}
+	src := &EnrichedTransaction{
+		TransactionMessage: &TransactionMessage{CustomerEmail: "FRAUDSTER@example.com", CustomerID: "cust-vip"},
+		Lists:              newTestListSnapshot(),
+	}
+
+	result := NewRuleEvaluationResult("tx-1", &rule, src, 1, time.Now())
+
+	if result.Matched {
+		t.Error("expected allowlisted customer not to match")
+	}
+	want := []ListHit{
+		{ListName: "bad-emails", ListType: ListBlocklist, Field: FieldCustomerEmail, Value: "Fraudster@Example.com"},
+		{ListName: "trusted-customers", ListType: ListAllowlist, Field: FieldCustomerID, Value: "cust-vip"},
+	}
+	if len(result.ListHits) != len(want) {
+		t.Fatalf("expected %d list hits, got %+v", len(want), result.ListHits)
+	}
+	for i := range want {
+		if result.ListHits[i] != want[i] {
+			t.Errorf("ListHits[%d] = %+v, want %+v", i, result.ListHits[i], want[i])
+		}
+	}
+}
+
+func TestManagedList_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		list       ManagedList
+		wantFields []string
+	}{
+		{name: "valid list", list: ManagedList{Name: "bad-ips", Type: ListBlocklist, Field: FieldCustomerIPAddress}},
+		{name: "invalid name", list: ManagedList{Name: "Bad IPs", Type: ListBlocklist, Field: FieldCustomerIPAddress}, wantFields: []string{"name"}},
+		{name: "invalid type", list: ManagedList{Name: "bad-ips", Type: "GREYLIST", Field: FieldCustomerIPAddress}, wantFields: []string{"type"}},
+		{name: "unknown field", list: ManagedList{Name: "bad-ips", Type: ListBlocklist, Field: "shoe_size"}, wantFields: []string{"field"}},
+		{name: "time field", list: ManagedList{Name: "dates", Type: ListBlocklist, Field: FieldCreatedAt}, wantFields: []string{"field"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := tt.list.Validate()
+			if len(violations) != len(tt.wantFields) {
+				t.Fatalf("expected %d violations, got %+v", len(tt.wantFields), violations)
+			}
+			for i, field := range tt.wantFields {
+				if violations[i].Field != field {
+					t.Errorf("violation[%d].Field = %q, want %q", i, violations[i].Field, field)
+				}
+			}
+		})
+	}
+}
+
+func TestManagedList_ValidateEntry(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	list := ManagedList{Name: "big-amounts", Type: ListBlocklist, Field: FieldAmountInCents}
+
+	if v := list.ValidateEntry(&ListEntry{Value: "100"}, 0, now); len(v) != 0 {
+		t.Errorf("expected valid entry, got %+v", v)
+	}
+	if v := list.ValidateEntry(&ListEntry{Value: "lots"}, 1, now); len(v) != 1 || v[0].Field != "entries[1].value" {
+		t.Errorf("expected value violation, got %+v", v)
+	}
+	if v := list.ValidateEntry(&ListEntry{Value: "100", ExpiresAt: &past}, 2, now); len(v) != 1 || v[0].Field != "entries[2].expires_at" {
+		t.Errorf("expected expiry violation, got %+v", v)
+	}
+}
@@ -0,0 +1,13 @@
+package entity
+
+// ProcessingStage identifies the step of the decision flow a consumed message
+// belongs to. A transaction is processed once per stage.
+type ProcessingStage string
+
+// Processing stages of the decision flow.
+const (
+	// StageTransactionCreated is the evaluation of a Transaction.Created message.
+	StageTransactionCreated ProcessingStage = "TRANSACTION_CREATED"
+	// StageFraudScoreCalculated is the evaluation of a FraudSignals.Calculated message.
+	StageFraudScoreCalculated ProcessingStage = "FRAUD_SCORE_CALCULATED"
+)
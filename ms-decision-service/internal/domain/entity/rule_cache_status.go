@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// RuleCacheStatus describes the snapshot of active rules held in memory by the decision service.
+type RuleCacheStatus struct {
+	RulesetVersion int       `json:"ruleset_version"`
+	RuleCount      int       `json:"rule_count"`
+	RefreshedAt    time.Time `json:"refreshed_at"`
+	AgeSeconds     float64   `json:"age_seconds"`
+	Stale          bool      `json:"stale"`
+}
@@ -0,0 +1,239 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LogicalOperator combines the child conditions of a compound condition node.
+type LogicalOperator string
+
+const (
+	LogicAnd LogicalOperator = "AND"
+	LogicOr  LogicalOperator = "OR"
+	LogicNot LogicalOperator = "NOT"
+)
+
+// FieldValueSource provides the string value of a condition field during rule evaluation.
+type FieldValueSource interface {
+	GetFieldValue(field ConditionField) string
+}
+
+// ConditionNode is a node of a boolean condition tree. A node with a Logic operator
+// combines its Children (AND/OR over one or more children, NOT over exactly one);
+// a node without Logic is a leaf comparing Field against Value using Operator.
+type ConditionNode struct {
+	Logic    LogicalOperator   `json:"logic,omitempty"`
+	Children []ConditionNode   `json:"children,omitempty"`
+	Field    ConditionField    `json:"field,omitempty"`
+	Operator ConditionOperator `json:"operator,omitempty"`
+	Value    string            `json:"value,omitempty"`
+
+	// matcher holds the pre-compiled value of a set-membership or pattern leaf.
+	matcher *valueMatcher
+}
+
+// ConditionResult captures the outcome of a single leaf condition evaluated against a transaction.
+type ConditionResult struct {
+	ConditionField    string `json:"condition_field"`
+	ConditionOperator string `json:"condition_operator"`
+	ConditionValue    string `json:"condition_value"`
+	ActualFieldValue  string `json:"actual_field_value"`
+	Matched           bool   `json:"matched"`
+}
+
+// IsLeaf reports whether the node is a single field comparison.
+func (n *ConditionNode) IsLeaf() bool {
+	return n.Logic == ""
+}
+
+// Evaluate reports whether the condition tree is satisfied by the given source.
+// AND and OR short-circuit; unknown logical operators never match.
+func (n *ConditionNode) Evaluate(src FieldValueSource) bool {
+	switch n.Logic {
+	case "":
+		_, matched := n.evaluateLeaf(src)
+		return matched
+	case LogicAnd:
+		if len(n.Children) == 0 {
+			return false
+		}
+		for i := range n.Children {
+			if !n.Children[i].Evaluate(src) {
+				return false
+			}
+		}
+		return true
+	case LogicOr:
+		for i := range n.Children {
+			if n.Children[i].Evaluate(src) {
+				return true
+			}
+		}
+		return false
+	case LogicNot:
+		if len(n.Children) != 1 {
+			return false
+		}
+		return !n.Children[0].Evaluate(src)
+	default:
+		return false
+	}
+}
+
+// Trace evaluates every leaf of the tree (without short-circuiting) and returns
+// the per-leaf results in depth-first order.
+func (n *ConditionNode) Trace(src FieldValueSource) []ConditionResult {
+	if n.IsLeaf() {
+		actual, matched := n.evaluateLeaf(src)
+		return []ConditionResult{{
+			ConditionField:    string(n.Field),
+			ConditionOperator: string(n.Operator),
+			ConditionValue:    n.Value,
+			ActualFieldValue:  actual,
+			Matched:           matched,
+		}}
+	}
+
+	var results []ConditionResult
+	for i := range n.Children {
+		results = append(results, n.Children[i].Trace(src)...)
+	}
+	return results
+}
+
+// ListHits returns a hit for every managed list leaf whose list contains the
+// source's field value, in depth-first order. Sources that cannot resolve lists
+// have no hits.
+func (n *ConditionNode) ListHits(src FieldValueSource) []ListHit {
+	if !n.IsLeaf() {
+		var hits []ListHit
+		for i := range n.Children {
+			hits = append(hits, n.Children[i].ListHits(src)...)
+		}
+		return hits
+	}
+
+	if !n.Operator.isListOperator() {
+		return nil
+	}
+	lookup, ok := src.(ListLookup)
+	if !ok {
+		return nil
+	}
+
+	actual := src.GetFieldValue(n.Field)
+	list, entry := lookup.Lookup(n.Value, n.Field, actual)
+	if list == nil || entry == nil {
+		return nil
+	}
+
+	return []ListHit{{ListName: list.Name, ListType: list.Type, Field: n.Field, Value: entry.Value}}
+}
+
+// evaluateLeaf returns the source's value for the leaf's field and whether the leaf matches it.
+func (n *ConditionNode) evaluateLeaf(src FieldValueSource) (string, bool) {
+	actual := src.GetFieldValue(n.Field)
+	if n.Operator.isListOperator() {
+		return actual, n.matchList(src, actual)
+	}
+	return actual, n.compare(actual)
+}
+
+// matchList checks the actual value against the managed list named by the leaf.
+// Unknown lists, lists of another field, missing values and sources without list
+// support never match, for IN_LIST and NOT_IN_LIST alike.
+func (n *ConditionNode) matchList(src FieldValueSource, actual string) bool {
+	lookup, ok := src.(ListLookup)
+	if !ok || actual == "" {
+		return false
+	}
+
+	list, entry := lookup.Lookup(n.Value, n.Field, actual)
+	if list == nil {
+		return false
+	}
+
+	return (entry != nil) == (n.Operator == OpInList)
+}
+
+// compare evaluates a leaf against the actual field value, using the pre-compiled
+// value when the tree has been compiled.
+func (n *ConditionNode) compare(actual string) bool {
+	if n.matcher != nil {
+		return n.matcher.match(actual)
+	}
+	return n.Operator.Compare(actual, n.Value, n.Field)
+}
+
+// compile returns a copy of the tree with the value of every set-membership and
+// pattern leaf pre-compiled. Leaves that fail to compile are kept uncompiled and
+// their errors are joined into the returned error.
+func (n *ConditionNode) compile() (*ConditionNode, error) {
+	compiled := *n
+
+	if n.IsLeaf() {
+		matcher, err := compileValueMatcher(n.Operator, n.Value, n.Field)
+		if err != nil {
+			return &compiled, fmt.Errorf("%s: %w", n.String(), err)
+		}
+		compiled.matcher = matcher
+		return &compiled, nil
+	}
+
+	compiled.Children = make([]ConditionNode, len(n.Children))
+	var errs []error
+	for i := range n.Children {
+		child, err := n.Children[i].compile()
+		compiled.Children[i] = *child
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return &compiled, errors.Join(errs...)
+}
+
+// References reports whether any leaf of the tree compares the given field.
+func (n *ConditionNode) References(field ConditionField) bool {
+	if n.IsLeaf() {
+		return n.Field == field
+	}
+	for i := range n.Children {
+		if n.Children[i].References(field) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReferencesList reports whether any IN_LIST or NOT_IN_LIST leaf of the tree names the list.
+func (n *ConditionNode) ReferencesList(name string) bool {
+	if n.IsLeaf() {
+		return n.Operator.isListOperator() && n.Value == name
+	}
+	for i := range n.Children {
+		if n.Children[i].ReferencesList(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the tree as a human-readable expression,
+// e.g. "(payment_method EQUAL CRYPTO AND amount_in_cents GREATER_THAN 100000)".
+func (n *ConditionNode) String() string {
+	if n.IsLeaf() {
+		return string(n.Field) + " " + string(n.Operator) + " " + n.Value
+	}
+
+	if n.Logic == LogicNot && len(n.Children) == 1 {
+		return "NOT " + n.Children[0].String()
+	}
+
+	parts := make([]string, len(n.Children))
+	for i := range n.Children {
+		parts[i] = n.Children[i].String()
+	}
+	return "(" + strings.Join(parts, " "+string(n.Logic)+" ") + ")"
+}
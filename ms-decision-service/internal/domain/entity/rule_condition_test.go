@@ -0,0 +1,258 @@
+package entity
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func cryptoOverThousandCondition() *ConditionNode {
+	return &ConditionNode{
+		Logic: LogicAnd,
+		Children: []ConditionNode{
+			{Field: FieldPaymentMethod, Operator: OpEqual, Value: "CRYPTO"},
+			{Field: FieldAmountInCents, Operator: OpGreaterThan, Value: "100000"},
+		},
+	}
+}
+
+func TestConditionNode_Evaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition *ConditionNode
+		tx        *TransactionMessage
+		want      bool
+	}{
+		{
+			name:      "AND matches when all children match",
+			condition: cryptoOverThousandCondition(),
+			tx:        &TransactionMessage{PaymentMethod: "CRYPTO", AmountInCents: 150000},
+			want:      true,
+		},
+		{
+			name:      "AND does not match when one child fails",
+			condition: cryptoOverThousandCondition(),
+			tx:        &TransactionMessage{PaymentMethod: "CRYPTO", AmountInCents: 50000},
+			want:      false,
+		},
+		{
+			name: "OR matches when any child matches",
+			condition: &ConditionNode{
+				Logic: LogicOr,
+				Children: []ConditionNode{
+					{Field: FieldCurrency, Operator: OpEqual, Value: "COP"},
+					{Field: FieldPaymentMethod, Operator: OpEqual, Value: "CRYPTO"},
+				},
+			},
+			tx:   &TransactionMessage{Currency: "USD", PaymentMethod: "CRYPTO"},
+			want: true,
+		},
+		{
+			name: "NOT negates its single child",
+			condition: &ConditionNode{
+				Logic:    LogicNot,
+				Children: []ConditionNode{{Field: FieldCurrency, Operator: OpEqual, Value: "USD"}},
+			},
+			tx:   &TransactionMessage{Currency: "EUR"},
+			want: true,
+		},
+		{
+			name: "nested tree is evaluated recursively",
+			condition: &ConditionNode{
+				Logic: LogicAnd,
+				Children: []ConditionNode{
+					{Field: FieldAmountInCents, Operator: OpGreaterThan, Value: "100000"},
+					{
+						Logic: LogicNot,
+						Children: []ConditionNode{{
+							Logic: LogicOr,
+							Children: []ConditionNode{
+								{Field: FieldCurrency, Operator: OpEqual, Value: "USD"},
+								{Field: FieldCurrency, Operator: OpEqual, Value: "EUR"},
+							},
+						}},
+					},
+				},
+			},
+			tx:   &TransactionMessage{AmountInCents: 200000, Currency: "COP"},
+			want: true,
+		},
+		{
+			name:      "AND without children never matches",
+			condition: &ConditionNode{Logic: LogicAnd},
+			tx:        &TransactionMessage{},
+			want:      false,
+		},
+		{
+			name: "NOT with more than one child never matches",
+			condition: &ConditionNode{
+				Logic: LogicNot,
+				Children: []ConditionNode{
+					{Field: FieldCurrency, Operator: OpEqual, Value: "USD"},
+					{Field: FieldCurrency, Operator: OpEqual, Value: "EUR"},
+				},
+			},
+			tx:   &TransactionMessage{Currency: "COP"},
+			want: false,
+		},
+		{
+			name:      "unknown logical operator never matches",
+			condition: &ConditionNode{Logic: "XOR", Children: []ConditionNode{{Field: FieldCurrency, Operator: OpEqual, Value: "USD"}}},
+			tx:        &TransactionMessage{Currency: "USD"},
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.condition.Evaluate(tt.tx); got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionNode_TraceRecordsEveryLeaf(t *testing.T) {
+	tx := &TransactionMessage{PaymentMethod: "CARD", AmountInCents: 150000}
+
+	got := cryptoOverThousandCondition().Trace(tx)
+
+	want := []ConditionResult{
+		{ConditionField: "payment_method", ConditionOperator: "EQUAL", ConditionValue: "CRYPTO", ActualFieldValue: "CARD", Matched: false},
+		{ConditionField: "amount_in_cents", ConditionOperator: "GREATER_THAN", ConditionValue: "100000", ActualFieldValue: "150000", Matched: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Trace() = %+v, want %+v", got, want)
+	}
+}
+
+func TestConditionNode_String(t *testing.T) {
+	condition := &ConditionNode{
+		Logic: LogicOr,
+		Children: []ConditionNode{
+			*cryptoOverThousandCondition(),
+			{Logic: LogicNot, Children: []ConditionNode{{Field: FieldCurrency, Operator: OpEqual, Value: "USD"}}},
+		},
+	}
+
+	want := "((payment_method EQUAL CRYPTO AND amount_in_cents GREATER_THAN 100000) OR NOT currency EQUAL USD)"
+	if got := condition.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestRule_MatchesSingleAndCompoundConditions(t *testing.T) {
+	tx := &TransactionMessage{PaymentMethod: "CRYPTO", AmountInCents: 150000}
+
+	single := Rule{ConditionField: FieldPaymentMethod, ConditionOperator: OpEqual, ConditionValue: "CRYPTO"}
+	if !single.Matches(tx) {
+		t.Error("expected single-condition rule to match")
+	}
+	if single.IsCompound() {
+		t.Error("expected single-condition rule not to be compound")
+	}
+
+	compound := Rule{Condition: cryptoOverThousandCondition()}
+	if !compound.Matches(tx) {
+		t.Error("expected compound rule to match")
+	}
+	if !compound.ReferencesField(FieldAmountInCents) {
+		t.Error("expected compound rule to reference amount_in_cents")
+	}
+	if compound.ReferencesField(FieldFraudScore) {
+		t.Error("expected compound rule not to reference fraud_score")
+	}
+}
+
+func TestRule_CompoundConditionJSONRoundTrip(t *testing.T) {
+	original := Rule{
+		RuleID:       "rule-100",
+		RuleName:     "Decline CRYPTO over $1,000",
+		Condition:    cryptoOverThousandCondition(),
+		ResultStatus: DECLINED,
+		Priority:     1,
+		IsActive:     true,
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("failed to marshal rule: %v", err)
+	}
+
+	var decoded Rule
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal rule: %v", err)
+	}
+
+	if !reflect.DeepEqual(original, decoded) {
+		t.Errorf("round-trip mismatch: got %+v, want %+v", decoded, original)
+	}
+}
+
+func TestNewRuleEvaluationResult(t *testing.T) {
+	evaluatedAt := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	tx := &TransactionMessage{ID: "tx-1", PaymentMethod: "CRYPTO", AmountInCents: 50000}
+
+	t.Run("single-condition rule records its field and actual value", func(t *testing.T) {
+		rule := &Rule{
+			RuleID:            "rule-001",
+			RuleName:          "Block CRYPTO",
+			ConditionField:    FieldPaymentMethod,
+			ConditionOperator: OpEqual,
+			ConditionValue:    "CRYPTO",
+			ResultStatus:      DECLINED,
+			Priority:          1,
+			Version:           3,
+		}
+
+		got := NewRuleEvaluationResult(tx.ID, rule, tx, 7, evaluatedAt)
+
+		want := RuleEvaluationResult{
+			TransactionID:     "tx-1",
+			RuleID:            "rule-001",
+			RuleName:          "Block CRYPTO",
+			ConditionField:    "payment_method",
+			ConditionOperator: "EQUAL",
+			ConditionValue:    "CRYPTO",
+			ActualFieldValue:  "CRYPTO",
+			Matched:           true,
+			ResultStatus:      "DECLINED",
+			EvaluatedAt:       evaluatedAt,
+			Priority:          1,
+			RuleVersion:       3,
+			RulesetVersion:    7,
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("compound rule records the expression and per-leaf results", func(t *testing.T) {
+		rule := &Rule{
+			RuleID:       "rule-100",
+			RuleName:     "Decline CRYPTO over $1,000",
+			Condition:    cryptoOverThousandCondition(),
+			ResultStatus: DECLINED,
+			Priority:     1,
+		}
+
+		got := NewRuleEvaluationResult(tx.ID, rule, tx, 7, evaluatedAt)
+
+		if got.Matched {
+			t.Error("expected compound rule not to match")
+		}
+		if got.ConditionOperator != "AND" {
+			t.Errorf("ConditionOperator = %q, want %q", got.ConditionOperator, "AND")
+		}
+		if got.ConditionValue != rule.Condition.String() {
+			t.Errorf("ConditionValue = %q, want %q", got.ConditionValue, rule.Condition.String())
+		}
+		if len(got.ConditionResults) != 2 {
+			t.Fatalf("expected 2 condition results, got %d", len(got.ConditionResults))
+		}
+		if !got.ConditionResults[0].Matched || got.ConditionResults[1].Matched {
+			t.Errorf("unexpected leaf results: %+v", got.ConditionResults)
+		}
+	})
+}
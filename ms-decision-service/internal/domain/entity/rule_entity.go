@@ -1,17 +1,8 @@
 package entity
 
-import "strconv"
-
-// ConditionField represents a transaction attribute that a rule can evaluate against.
-type ConditionField string
-
-const (
-	FieldAmountInCents     ConditionField = "amount_in_cents"
-	FieldCurrency          ConditionField = "currency"
-	FieldPaymentMethod     ConditionField = "payment_method"
-	FieldCustomerID        ConditionField = "customer_id"
-	FieldCustomerIPAddress ConditionField = "customer_ip_address"
-	FieldFraudScore        ConditionField = "fraud_score"
+import (
+	"strconv"
+	"time"
 )
 
 // ConditionOperator represents a comparison operator used in rule evaluation.
@@ -24,8 +15,62 @@ const (
 	OpNotEqual           ConditionOperator = "NOT_EQUAL"
 	OpGreaterThanOrEqual ConditionOperator = "GREATER_THAN_OR_EQUAL"
 	OpLessThanOrEqual    ConditionOperator = "LESS_THAN_OR_EQUAL"
+	OpIn                 ConditionOperator = "IN"
+	OpNotIn              ConditionOperator = "NOT_IN"
+	OpInCIDR             ConditionOperator = "IN_CIDR"
+	OpMatchesRegex       ConditionOperator = "MATCHES_REGEX"
+	OpStartsWith         ConditionOperator = "STARTS_WITH"
+	OpEndsWith           ConditionOperator = "ENDS_WITH"
+	OpInList             ConditionOperator = "IN_LIST"
+	OpNotInList          ConditionOperator = "NOT_IN_LIST"
 )
 
+// IsValid reports whether the operator is a known comparison, set-membership, pattern
+// or managed list operator.
+func (op ConditionOperator) IsValid() bool {
+	switch op {
+	case OpIn, OpNotIn, OpInCIDR, OpMatchesRegex, OpStartsWith, OpEndsWith, OpInList, OpNotInList:
+		return true
+	default:
+		return op.isComparison()
+	}
+}
+
+// isListOperator reports whether the operator checks membership of a managed list
+// whose name is the condition value.
+func (op ConditionOperator) isListOperator() bool {
+	return op == OpInList || op == OpNotInList
+}
+
+// isComparison reports whether the operator compares the field against a single scalar value.
+func (op ConditionOperator) isComparison() bool {
+	switch op {
+	case OpGreaterThan, OpLessThan, OpEqual, OpNotEqual, OpGreaterThanOrEqual, OpLessThanOrEqual:
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportsField reports whether the operator can be applied to the given field.
+// Ordering operators only make sense for numeric and time fields, set-membership
+// operators for numeric and string fields, pattern operators only for string
+// fields and IN_CIDR only for the customer IP address.
+func (op ConditionOperator) SupportsField(field ConditionField) bool {
+	switch op {
+	case OpEqual, OpNotEqual:
+		return true
+	case OpIn, OpNotIn, OpInList, OpNotInList:
+		return !field.IsTime()
+	case OpInCIDR:
+		return field == FieldCustomerIPAddress
+	case OpMatchesRegex, OpStartsWith, OpEndsWith:
+		return field.Type() == FieldTypeString
+	default:
+		return (field.IsNumeric() || field.IsTime()) && op.IsValid()
+	}
+}
+
 // DecisionStatus represents the outcome of a rule evaluation.
 type DecisionStatus string
 
@@ -35,27 +80,75 @@ const (
 	FRAUDCHECK DecisionStatus = "FRAUD_CHECK"
 )
 
+// IsValid reports whether the status is a decision a rule can produce.
+func (s DecisionStatus) IsValid() bool {
+	return s == APPROVED || s == DECLINED || s == FRAUDCHECK
+}
+
+// RuleMode controls whether a rule takes part in decisions.
+type RuleMode string
+
+const (
+	// RuleModeLive rules decide the outcome of their rule set.
+	RuleModeLive RuleMode = "LIVE"
+	// RuleModeShadow rules are evaluated and recorded but never affect the decision.
+	RuleModeShadow RuleMode = "SHADOW"
+)
+
+// IsValid reports whether the mode is known. An empty mode is treated as LIVE.
+func (m RuleMode) IsValid() bool {
+	return m == "" || m == RuleModeLive || m == RuleModeShadow
+}
+
 // Rule represents a single fraud detection rule stored in DynamoDB.
+// A rule either holds a single ConditionField/ConditionOperator/ConditionValue
+// triple or, when Condition is set, a compound boolean condition tree.
+// Version is incremented on every change and is zero for rules that have never
+// been changed through the rules API. ScoreWeight is only used when the rule's
+// rule set is evaluated in SCORE mode; a negative weight lowers the total.
+// SHADOW rules are evaluated and recorded but excluded from the decision; rules
+// without a Mode are LIVE.
 type Rule struct {
 	RuleID            string            `json:"rule_id"`
 	RuleName          string            `json:"rule_name"`
 	ConditionField    ConditionField    `json:"condition_field"`
 	ConditionOperator ConditionOperator `json:"condition_operator"`
 	ConditionValue    string            `json:"condition_value"`
+	Condition         *ConditionNode    `json:"condition,omitempty"`
 	ResultStatus      DecisionStatus    `json:"result_status"`
 	Priority          int               `json:"priority"`
+	ScoreWeight       int               `json:"score_weight"`
 	IsActive          bool              `json:"is_active"`
+	Mode              RuleMode          `json:"mode"`
+	Version           int               `json:"version"`
+
+	// compiled is the condition tree with its values pre-compiled by Compile.
+	compiled *ConditionNode
 }
 
-// Compare evaluates fieldValue against conditionValue using the operator.
-// For FieldAmountInCents, both values are parsed as int64 and compared numerically.
-// For all other fields, only EQUAL and NOT_EQUAL are supported (string comparison).
+// Compare evaluates fieldValue against conditionValue using the operator, according
+// to the field's registered type. Numeric values are parsed as int64 and time values
+// as RFC 3339 timestamps; string fields only support EQUAL and NOT_EQUAL.
+// Set-membership and pattern operators compile conditionValue on every call and
+// never match when it is invalid; rules loaded for evaluation use Compile instead.
+// Managed list operators need a ListLookup and never match here.
 func (op ConditionOperator) Compare(fieldValue, conditionValue string, field ConditionField) bool {
-	if field == FieldAmountInCents || field == FieldFraudScore {
-		return op.compareNumeric(fieldValue, conditionValue)
+	if !op.isComparison() {
+		m, err := compileValueMatcher(op, conditionValue, field)
+		if err != nil || m == nil {
+			return false
+		}
+		return m.match(fieldValue)
 	}
 
-	return op.compareString(fieldValue, conditionValue)
+	switch field.Type() {
+	case FieldTypeNumeric:
+		return op.compareNumeric(fieldValue, conditionValue)
+	case FieldTypeTime:
+		return op.compareTime(fieldValue, conditionValue)
+	default:
+		return op.compareString(fieldValue, conditionValue)
+	}
 }
 
 func (op ConditionOperator) compareNumeric(fieldValue, conditionValue string) bool {
@@ -87,6 +180,35 @@ func (op ConditionOperator) compareNumeric(fieldValue, conditionValue string) bo
 	}
 }
 
+func (op ConditionOperator) compareTime(fieldValue, conditionValue string) bool {
+	fv, err := time.Parse(time.RFC3339Nano, fieldValue)
+	if err != nil {
+		return false
+	}
+
+	cv, err := time.Parse(time.RFC3339Nano, conditionValue)
+	if err != nil {
+		return false
+	}
+
+	switch op {
+	case OpGreaterThan:
+		return fv.After(cv)
+	case OpLessThan:
+		return fv.Before(cv)
+	case OpEqual:
+		return fv.Equal(cv)
+	case OpNotEqual:
+		return !fv.Equal(cv)
+	case OpGreaterThanOrEqual:
+		return !fv.Before(cv)
+	case OpLessThanOrEqual:
+		return !fv.After(cv)
+	default:
+		return false
+	}
+}
+
 func (op ConditionOperator) compareString(fieldValue, conditionValue string) bool {
 	switch op {
 	case OpEqual:
@@ -98,8 +220,65 @@ func (op ConditionOperator) compareString(fieldValue, conditionValue string) boo
 	}
 }
 
-// Matches checks whether the given transaction satisfies this rule's condition.
-func (r *Rule) Matches(transaction *TransactionMessage) bool {
-	fieldValue := transaction.GetFieldValue(r.ConditionField)
-	return r.ConditionOperator.Compare(fieldValue, r.ConditionValue, r.ConditionField)
+// IsShadow reports whether the rule is observe-only.
+func (r *Rule) IsShadow() bool {
+	return r.Mode == RuleModeShadow
+}
+
+// EffectiveMode returns the rule's mode, LIVE when none is set.
+func (r *Rule) EffectiveMode() RuleMode {
+	if r.Mode == "" {
+		return RuleModeLive
+	}
+	return r.Mode
+}
+
+// IsCompound reports whether the rule carries a condition tree instead of a single condition.
+func (r *Rule) IsCompound() bool {
+	return r.Condition != nil
+}
+
+// ConditionTree returns the rule's condition as a tree. Single-condition rules
+// are returned as a one-leaf tree built from their field/operator/value triple.
+func (r *Rule) ConditionTree() *ConditionNode {
+	if r.Condition != nil {
+		return r.Condition
+	}
+	return &ConditionNode{
+		Field:    r.ConditionField,
+		Operator: r.ConditionOperator,
+		Value:    r.ConditionValue,
+	}
+}
+
+// Compile pre-compiles the values of every set-membership and pattern condition of
+// the rule, so later evaluations do not re-parse them. Conditions whose value
+// cannot be compiled never match; their errors are returned joined together.
+func (r *Rule) Compile() error {
+	tree, err := r.ConditionTree().compile()
+	r.compiled = tree
+	return err
+}
+
+// evaluationTree returns the compiled condition tree when the rule has been compiled.
+func (r *Rule) evaluationTree() *ConditionNode {
+	if r.compiled != nil {
+		return r.compiled
+	}
+	return r.ConditionTree()
+}
+
+// ReferencesField reports whether any condition of the rule evaluates the given field.
+func (r *Rule) ReferencesField(field ConditionField) bool {
+	return r.ConditionTree().References(field)
+}
+
+// ReferencesList reports whether any condition of the rule is checked against the managed list.
+func (r *Rule) ReferencesList(name string) bool {
+	return r.ConditionTree().ReferencesList(name)
+}
+
+// Matches checks whether the given source (usually a transaction) satisfies this rule's condition.
+func (r *Rule) Matches(src FieldValueSource) bool {
+	return r.evaluationTree().Evaluate(src)
 }
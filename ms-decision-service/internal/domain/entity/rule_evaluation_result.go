@@ -3,16 +3,96 @@ package entity
 import "time"
 
 // RuleEvaluationResult represents the outcome of evaluating a single rule against a transaction.
+// For compound rules, ConditionValue holds the rendered condition expression and
+// ConditionResults records the outcome of every leaf condition. ListHits records
+// every managed list entry the transaction matched, whether or not the rule did.
+// When the rule set was evaluated in SCORE mode, every row carries the rule's
+// contribution and the total score of the evaluation. Rows of SHADOW rules carry
+// the decision the rule set would have reached with the rule LIVE next to the
+// decision it actually reached.
 type RuleEvaluationResult struct {
-	TransactionID     string    `json:"transaction_id"`
-	RuleID            string    `json:"rule_id"`
-	RuleName          string    `json:"rule_name"`
-	ConditionField    string    `json:"condition_field"`
-	ConditionOperator string    `json:"condition_operator"`
-	ConditionValue    string    `json:"condition_value"`
-	ActualFieldValue  string    `json:"actual_field_value"`
-	Matched           bool      `json:"matched"`
-	ResultStatus      string    `json:"result_status"`
-	EvaluatedAt       time.Time `json:"evaluated_at"`
-	Priority          int       `json:"priority"`
+	TransactionID     string            `json:"transaction_id"`
+	RuleID            string            `json:"rule_id"`
+	RuleName          string            `json:"rule_name"`
+	ConditionField    string            `json:"condition_field"`
+	ConditionOperator string            `json:"condition_operator"`
+	ConditionValue    string            `json:"condition_value"`
+	ActualFieldValue  string            `json:"actual_field_value"`
+	Matched           bool              `json:"matched"`
+	ResultStatus      string            `json:"result_status"`
+	EvaluatedAt       time.Time         `json:"evaluated_at"`
+	Priority          int               `json:"priority"`
+	ConditionResults  []ConditionResult `json:"condition_results,omitempty"`
+	RuleVersion       int               `json:"rule_version"`
+	RulesetVersion    int               `json:"ruleset_version"`
+	ListHits          []ListHit         `json:"list_hits,omitempty"`
+	DecisionMode      DecisionMode      `json:"decision_mode,omitempty"`
+	ScoreContribution int               `json:"score_contribution,omitempty"`
+	TotalScore        int               `json:"total_score,omitempty"`
+	Shadow            bool              `json:"shadow,omitempty"`
+	ShadowStatus      DecisionStatus    `json:"shadow_status,omitempty"`
+	ActualStatus      DecisionStatus    `json:"actual_status,omitempty"`
+}
+
+// RecordShadowOutcome stores the decision the rule set would have reached with
+// this SHADOW rule LIVE and the decision it actually reached.
+func (r *RuleEvaluationResult) RecordShadowOutcome(shadowStatus, actualStatus DecisionStatus) {
+	r.ShadowStatus = shadowStatus
+	r.ActualStatus = actualStatus
+}
+
+// ChangesDecision reports whether the SHADOW rule would have changed the decision.
+func (r *RuleEvaluationResult) ChangesDecision() bool {
+	return r.ShadowStatus != r.ActualStatus
+}
+
+// RecordScore stores the rule's contribution to a SCORE mode evaluation. It does
+// nothing for FIRST_MATCH evaluations.
+func (r *RuleEvaluationResult) RecordScore(evaluation *Evaluation) {
+	if evaluation.Mode != ModeScore {
+		return
+	}
+	r.DecisionMode = evaluation.Mode
+	r.ScoreContribution = evaluation.Contribution(r.RuleID)
+	r.TotalScore = evaluation.Score
+}
+
+// NewRuleEvaluationResult evaluates the rule against the source and builds the
+// result record persisted for the given transaction, stamped with the version of
+// the ruleset the rule was loaded from.
+func NewRuleEvaluationResult(
+	transactionID string,
+	rule *Rule,
+	src FieldValueSource,
+	rulesetVersion int,
+	evaluatedAt time.Time,
+) RuleEvaluationResult {
+	result := RuleEvaluationResult{
+		TransactionID:  transactionID,
+		RuleID:         rule.RuleID,
+		RuleName:       rule.RuleName,
+		Matched:        rule.Matches(src),
+		ResultStatus:   string(rule.ResultStatus),
+		EvaluatedAt:    evaluatedAt,
+		Priority:       rule.Priority,
+		RuleVersion:    rule.Version,
+		RulesetVersion: rulesetVersion,
+		Shadow:         rule.IsShadow(),
+	}
+
+	result.ListHits = rule.evaluationTree().ListHits(src)
+
+	if rule.IsCompound() {
+		result.ConditionOperator = string(rule.Condition.Logic)
+		result.ConditionValue = rule.Condition.String()
+		result.ConditionResults = rule.evaluationTree().Trace(src)
+		return result
+	}
+
+	result.ConditionField = string(rule.ConditionField)
+	result.ConditionOperator = string(rule.ConditionOperator)
+	result.ConditionValue = rule.ConditionValue
+	result.ActualFieldValue = src.GetFieldValue(rule.ConditionField)
+
+	return result
 }
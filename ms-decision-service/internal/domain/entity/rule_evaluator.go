@@ -1,9 +1,13 @@
 package entity
 
 // EvaluateRules iterates rules in order and returns the ResultStatus of the first
-// matching rule. If no rule matches, it returns APPROVED (fail-open by design).
-func EvaluateRules(transaction *TransactionMessage, rules []Rule) DecisionStatus {
+// matching rule, evaluating compound condition trees as a whole. SHADOW rules are
+// skipped. If no rule matches, it returns APPROVED (fail-open by design).
+func EvaluateRules(transaction FieldValueSource, rules []Rule) DecisionStatus {
 	for _, rule := range rules {
+		if rule.IsShadow() {
+			continue
+		}
 		if rule.Matches(transaction) {
 			return rule.ResultStatus
 		}
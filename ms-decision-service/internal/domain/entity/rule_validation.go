@@ -0,0 +1,194 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RuleViolation describes a single reason why a rule definition is invalid.
+type RuleViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Validate checks the rule definition and returns every violation found.
+// An empty result means the rule can be safely persisted and evaluated.
+func (r *Rule) Validate() []RuleViolation {
+	var violations []RuleViolation
+
+	if strings.TrimSpace(r.RuleName) == "" {
+		violations = append(violations, RuleViolation{Field: "rule_name", Message: "rule_name is required"})
+	}
+
+	if !r.ResultStatus.IsValid() {
+		violations = append(violations, RuleViolation{
+			Field:   "result_status",
+			Message: fmt.Sprintf("result_status %q is invalid", r.ResultStatus),
+		})
+	}
+
+	if r.Priority < 0 {
+		violations = append(violations, RuleViolation{Field: "priority", Message: "priority must not be negative"})
+	}
+
+	if r.ScoreWeight < -MaxScoreWeight || r.ScoreWeight > MaxScoreWeight {
+		violations = append(violations, RuleViolation{
+			Field:   "score_weight",
+			Message: fmt.Sprintf("score_weight must be between -%d and %d", MaxScoreWeight, MaxScoreWeight),
+		})
+	}
+
+	if !r.Mode.IsValid() {
+		violations = append(violations, RuleViolation{
+			Field:   "mode",
+			Message: fmt.Sprintf("mode %q is invalid", r.Mode),
+		})
+	}
+
+	if r.Condition != nil {
+		if r.ConditionField != "" || r.ConditionOperator != "" || r.ConditionValue != "" {
+			violations = append(violations, RuleViolation{
+				Field:   "condition",
+				Message: "condition cannot be combined with condition_field, condition_operator or condition_value",
+			})
+		}
+		return append(violations, r.Condition.validate("condition")...)
+	}
+
+	leaf := ConditionNode{Field: r.ConditionField, Operator: r.ConditionOperator, Value: r.ConditionValue}
+	return append(violations, leaf.validateLeaf("condition_field", "condition_operator", "condition_value")...)
+}
+
+// validate checks a condition tree node rooted at the given path.
+func (n *ConditionNode) validate(path string) []RuleViolation {
+	switch n.Logic {
+	case "":
+		return n.validateLeaf(path+".field", path+".operator", path+".value")
+	case LogicAnd, LogicOr:
+		if len(n.Children) == 0 {
+			return []RuleViolation{{
+				Field:   path + ".children",
+				Message: fmt.Sprintf("%s requires at least one child condition", n.Logic),
+			}}
+		}
+	case LogicNot:
+		if len(n.Children) != 1 {
+			return []RuleViolation{{Field: path + ".children", Message: "NOT requires exactly one child condition"}}
+		}
+	default:
+		return []RuleViolation{{Field: path + ".logic", Message: fmt.Sprintf("logic %q is invalid", n.Logic)}}
+	}
+
+	var violations []RuleViolation
+	for i := range n.Children {
+		violations = append(violations, n.Children[i].validate(fmt.Sprintf("%s.children[%d]", path, i))...)
+	}
+	return violations
+}
+
+// validateLeaf checks the field/operator/value combination of a leaf condition,
+// reporting violations under the given attribute names.
+func (n *ConditionNode) validateLeaf(fieldAttr, operatorAttr, valueAttr string) []RuleViolation {
+	var violations []RuleViolation
+
+	if !n.Field.IsValid() {
+		violations = append(violations, RuleViolation{
+			Field:   fieldAttr,
+			Message: fmt.Sprintf("field %q is not supported", n.Field),
+		})
+	}
+
+	switch {
+	case !n.Operator.IsValid():
+		violations = append(violations, RuleViolation{
+			Field:   operatorAttr,
+			Message: fmt.Sprintf("operator %q is not supported", n.Operator),
+		})
+	case n.Field.IsValid() && !n.Operator.SupportsField(n.Field):
+		violations = append(violations, RuleViolation{
+			Field:   operatorAttr,
+			Message: fmt.Sprintf("operator %s cannot be applied to field %s", n.Operator, n.Field),
+		})
+	}
+
+	switch {
+	case n.Value == "":
+		violations = append(violations, RuleViolation{Field: valueAttr, Message: "value is required"})
+	case n.Operator.IsValid() && !n.Operator.isComparison():
+		if !n.Field.IsValid() || !n.Operator.SupportsField(n.Field) {
+			break
+		}
+		if _, err := compileValueMatcher(n.Operator, n.Value, n.Field); err != nil {
+			violations = append(violations, RuleViolation{
+				Field:   valueAttr,
+				Message: fmt.Sprintf("value %q is invalid for operator %s: %v", n.Value, n.Operator, err),
+			})
+		}
+	case n.Field.IsNumeric():
+		if _, err := strconv.ParseInt(n.Value, 10, 64); err != nil {
+			violations = append(violations, RuleViolation{
+				Field:   valueAttr,
+				Message: fmt.Sprintf("value %q must be an integer for field %s", n.Value, n.Field),
+			})
+		}
+	case n.Field.IsTime():
+		if _, err := time.Parse(time.RFC3339Nano, n.Value); err != nil {
+			violations = append(violations, RuleViolation{
+				Field:   valueAttr,
+				Message: fmt.Sprintf("value %q must be an RFC 3339 timestamp for field %s", n.Value, n.Field),
+			})
+		}
+	}
+
+	return violations
+}
+
+// ValidateLists checks that every IN_LIST and NOT_IN_LIST condition names one of the
+// given lists and that the list holds values of the condition's field. Violations
+// are reported under the same attribute names as Validate.
+func (r *Rule) ValidateLists(lists []ManagedList) []RuleViolation {
+	byName := make(map[string]*ManagedList, len(lists))
+	for i := range lists {
+		byName[lists[i].Name] = &lists[i]
+	}
+
+	if r.Condition != nil {
+		return r.Condition.validateLists(byName, "condition")
+	}
+	leaf := ConditionNode{Field: r.ConditionField, Operator: r.ConditionOperator, Value: r.ConditionValue}
+	return leaf.validateListLeaf(byName, "condition_value")
+}
+
+// validateLists checks the list references of a condition tree node rooted at the given path.
+func (n *ConditionNode) validateLists(lists map[string]*ManagedList, path string) []RuleViolation {
+	if n.IsLeaf() {
+		return n.validateListLeaf(lists, path+".value")
+	}
+
+	var violations []RuleViolation
+	for i := range n.Children {
+		violations = append(violations, n.Children[i].validateLists(lists, fmt.Sprintf("%s.children[%d]", path, i))...)
+	}
+	return violations
+}
+
+// validateListLeaf checks the list named by a list leaf, reporting violations under valueAttr.
+func (n *ConditionNode) validateListLeaf(lists map[string]*ManagedList, valueAttr string) []RuleViolation {
+	if !n.Operator.isListOperator() || n.Value == "" {
+		return nil
+	}
+
+	list, ok := lists[n.Value]
+	switch {
+	case !ok:
+		return []RuleViolation{{Field: valueAttr, Message: fmt.Sprintf("list %q does not exist", n.Value)}}
+	case list.Field != n.Field:
+		return []RuleViolation{{
+			Field:   valueAttr,
+			Message: fmt.Sprintf("list %q holds %s values and cannot be checked against field %s", n.Value, list.Field, n.Field),
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+package entity
+
+import "testing"
+
+func validRule() Rule {
+	return Rule{
+		RuleID:            "rule-001",
+		RuleName:          "Block CRYPTO payments",
+		ConditionField:    FieldPaymentMethod,
+		ConditionOperator: OpEqual,
+		ConditionValue:    "CRYPTO",
+		ResultStatus:      DECLINED,
+		Priority:          1,
+		IsActive:          true,
+	}
+}
+
+func TestRule_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		mutate     func(r *Rule)
+		wantFields []string
+	}{
+		{
+			name:   "valid single-condition rule",
+			mutate: func(_ *Rule) {},
+		},
+		{
+			name: "valid compound rule",
+			mutate: func(r *Rule) {
+				*r = Rule{RuleName: "Compound", Condition: cryptoOverThousandCondition(), ResultStatus: DECLINED}
+			},
+		},
+		{
+			name:       "missing name",
+			mutate:     func(r *Rule) { r.RuleName = "  " },
+			wantFields: []string{"rule_name"},
+		},
+		{
+			name:       "invalid result status",
+			mutate:     func(r *Rule) { r.ResultStatus = "MAYBE" },
+			wantFields: []string{"result_status"},
+		},
+		{
+			name:       "negative priority",
+			mutate:     func(r *Rule) { r.Priority = -1 },
+			wantFields: []string{"priority"},
+		},
+		{
+			name:   "shadow rule",
+			mutate: func(r *Rule) { r.Mode = RuleModeShadow },
+		},
+		{
+			name:       "unknown mode",
+			mutate:     func(r *Rule) { r.Mode = "DRY_RUN" },
+			wantFields: []string{"mode"},
+		},
+		{
+			name:       "score weight out of range",
+			mutate:     func(r *Rule) { r.ScoreWeight = MaxScoreWeight + 1 },
+			wantFields: []string{"score_weight"},
+		},
+		{
+			name:       "unknown field",
+			mutate:     func(r *Rule) { r.ConditionField = "shoe_size" },
+			wantFields: []string{"condition_field"},
+		},
+		{
+			name:       "unknown operator",
+			mutate:     func(r *Rule) { r.ConditionOperator = "LIKE" },
+			wantFields: []string{"condition_operator"},
+		},
+		{
+			name: "ordering operator on string field",
+			mutate: func(r *Rule) {
+				r.ConditionField = FieldCurrency
+				r.ConditionOperator = OpGreaterThan
+				r.ConditionValue = "USD"
+			},
+			wantFields: []string{"condition_operator"},
+		},
+		{
+			name: "non-numeric value on numeric field",
+			mutate: func(r *Rule) {
+				r.ConditionField = FieldAmountInCents
+				r.ConditionOperator = OpGreaterThan
+				r.ConditionValue = "lots"
+			},
+			wantFields: []string{"condition_value"},
+		},
+		{
+			name: "valid set-membership rule",
+			mutate: func(r *Rule) {
+				r.ConditionField = FieldCurrency
+				r.ConditionOperator = OpIn
+				r.ConditionValue = `["USD", "EUR"]`
+			},
+		},
+		{
+			name: "invalid regular expression",
+			mutate: func(r *Rule) {
+				r.ConditionField = FieldCustomerID
+				r.ConditionOperator = OpMatchesRegex
+				r.ConditionValue = "cust-(["
+			},
+			wantFields: []string{"condition_value"},
+		},
+		{
+			name: "invalid CIDR block",
+			mutate: func(r *Rule) {
+				r.ConditionField = FieldCustomerIPAddress
+				r.ConditionOperator = OpInCIDR
+				r.ConditionValue = "10.0.0.0/33"
+			},
+			wantFields: []string{"condition_value"},
+		},
+		{
+			name: "IN_CIDR on a field other than the IP address",
+			mutate: func(r *Rule) {
+				r.ConditionField = FieldCurrency
+				r.ConditionOperator = OpInCIDR
+				r.ConditionValue = "10.0.0.0/8"
+			},
+			wantFields: []string{"condition_operator"},
+		},
+		{
+			name: "non-numeric list value on numeric field",
+			mutate: func(r *Rule) {
+				r.ConditionField = FieldAmountInCents
+				r.ConditionOperator = OpNotIn
+				r.ConditionValue = "100, lots"
+			},
+			wantFields: []string{"condition_value"},
+		},
+		{
+			name: "empty list",
+			mutate: func(r *Rule) {
+				r.ConditionOperator = OpIn
+				r.ConditionValue = " , "
+			},
+			wantFields: []string{"condition_value"},
+		},
+		{
+			name: "valid derived field rule",
+			mutate: func(r *Rule) {
+				r.ConditionField = FieldHourOfDayUTC
+				r.ConditionOperator = OpLessThan
+				r.ConditionValue = "6"
+			},
+		},
+		{
+			name: "non-timestamp value on time field",
+			mutate: func(r *Rule) {
+				r.ConditionField = FieldCreatedAt
+				r.ConditionOperator = OpGreaterThan
+				r.ConditionValue = "yesterday"
+			},
+			wantFields: []string{"condition_value"},
+		},
+		{
+			name: "set-membership operator on time field",
+			mutate: func(r *Rule) {
+				r.ConditionField = FieldCreatedAt
+				r.ConditionOperator = OpIn
+				r.ConditionValue = "2025-01-15T10:30:00Z"
+			},
+			wantFields: []string{"condition_operator"},
+		},
+		{
+			name: "valid managed list rule",
+			mutate: func(r *Rule) {
+				r.ConditionField = FieldCustomerEmail
+				r.ConditionOperator = OpInList
+				r.ConditionValue = "bad-emails"
+			},
+		},
+		{
+			name: "invalid list name",
+			mutate: func(r *Rule) {
+				r.ConditionField = FieldCustomerEmail
+				r.ConditionOperator = OpNotInList
+				r.ConditionValue = "Bad Emails"
+			},
+			wantFields: []string{"condition_value"},
+		},
+		{
+			name:       "missing value",
+			mutate:     func(r *Rule) { r.ConditionValue = "" },
+			wantFields: []string{"condition_value"},
+		},
+		{
+			name:       "condition combined with flat attributes",
+			mutate:     func(r *Rule) { r.Condition = cryptoOverThousandCondition() },
+			wantFields: []string{"condition"},
+		},
+		{
+			name: "invalid nested leaves are reported with their path",
+			mutate: func(r *Rule) {
+				*r = Rule{
+					RuleName:     "Compound",
+					ResultStatus: DECLINED,
+					Condition: &ConditionNode{
+						Logic: LogicAnd,
+						Children: []ConditionNode{
+							{Field: FieldCurrency, Operator: OpLessThan, Value: "USD"},
+							{Logic: LogicNot},
+						},
+					},
+				}
+			},
+			wantFields: []string{"condition.children[0].operator", "condition.children[1].children"},
+		},
+		{
+			name: "unknown logic",
+			mutate: func(r *Rule) {
+				*r = Rule{RuleName: "Compound", ResultStatus: DECLINED, Condition: &ConditionNode{Logic: "XOR"}}
+			},
+			wantFields: []string{"condition.logic"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := validRule()
+			tt.mutate(&rule)
+
+			violations := rule.Validate()
+
+			if len(violations) != len(tt.wantFields) {
+				t.Fatalf("expected %d violations, got %d: %+v", len(tt.wantFields), len(violations), violations)
+			}
+			for i, field := range tt.wantFields {
+				if violations[i].Field != field {
+					t.Errorf("violation[%d].Field = %q, want %q", i, violations[i].Field, field)
+				}
+				if violations[i].Message == "" {
+					t.Errorf("violation[%d].Message is empty", i)
+				}
+			}
+		})
+	}
+}
+
+func TestRule_ValidateLists(t *testing.T) {
+	lists := []ManagedList{
+		{Name: "blocked-emails", Type: ListBlocklist, Field: FieldCustomerEmail},
+		{Name: "trusted-ips", Type: ListAllowlist, Field: FieldCustomerIPAddress},
+	}
+
+	tests := []struct {
+		name       string
+		rule       Rule
+		wantFields []string
+	}{
+		{
+			name: "existing list of the condition field",
+			rule: Rule{ConditionField: FieldCustomerEmail, ConditionOperator: OpInList, ConditionValue: "blocked-emails"},
+		},
+		{
+			name: "rule without list conditions",
+			rule: validRule(),
+		},
+		{
+			name:       "unknown list",
+			rule:       Rule{ConditionField: FieldCustomerEmail, ConditionOperator: OpNotInList, ConditionValue: "missing"},
+			wantFields: []string{"condition_value"},
+		},
+		{
+			name: "list of another field in a condition tree",
+			rule: Rule{Condition: &ConditionNode{Logic: LogicAnd, Children: []ConditionNode{
+				{Field: FieldCustomerEmail, Operator: OpInList, Value: "blocked-emails"},
+				{Field: FieldCustomerEmail, Operator: OpInList, Value: "trusted-ips"},
+			}}},
+			wantFields: []string{"condition.children[1].value"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := tt.rule.ValidateLists(lists)
+
+			if len(violations) != len(tt.wantFields) {
+				t.Fatalf("expected %d violations, got %+v", len(tt.wantFields), violations)
+			}
+			for i, field := range tt.wantFields {
+				if violations[i].Field != field {
+					t.Errorf("violation[%d].Field = %q, want %q", i, violations[i].Field, field)
+				}
+			}
+		})
+	}
+}
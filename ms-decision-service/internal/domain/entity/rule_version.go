@@ -0,0 +1,144 @@
+package entity
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// ErrRuleChangeConflict is returned when a RuleChangeSet no longer applies because
+// one of its rules or the ruleset version was changed concurrently.
+var ErrRuleChangeConflict = errors.New("rule changed concurrently")
+
+// RuleChangeType describes what kind of change produced a rule version.
+type RuleChangeType string
+
+const (
+	// RuleBaseline records the state of a rule that existed before it was first
+	// changed through the rules API (e.g. seeded rules).
+	RuleBaseline    RuleChangeType = "BASELINE"
+	RuleCreated     RuleChangeType = "CREATED"
+	RuleUpdated     RuleChangeType = "UPDATED"
+	RuleActivated   RuleChangeType = "ACTIVATED"
+	RuleDeactivated RuleChangeType = "DEACTIVATED"
+	RuleDeleted     RuleChangeType = "DELETED"
+	RuleRolledBack  RuleChangeType = "ROLLED_BACK"
+)
+
+// RuleFieldChange describes how a single rule attribute changed between two versions.
+type RuleFieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// RuleVersion is an immutable record of a single change to a rule.
+// Rule holds the rule as it was after the change; when Deleted is true it holds
+// the rule as it was right before it was removed.
+// RulesetVersion is the version of the whole rule set the change produced.
+type RuleVersion struct {
+	RuleID          string            `json:"rule_id"`
+	Version         int               `json:"version"`
+	RulesetVersion  int               `json:"ruleset_version"`
+	ChangeType      RuleChangeType    `json:"change_type"`
+	ChangedBy       string            `json:"changed_by"`
+	ChangedAt       time.Time         `json:"changed_at"`
+	Deleted         bool              `json:"deleted"`
+	RestoredVersion int               `json:"restored_version,omitempty"`
+	Rule            Rule              `json:"rule"`
+	Changes         []RuleFieldChange `json:"changes"`
+}
+
+// MaxRuleChanges is the most changes a RuleChangeSet can hold. Each change writes
+// up to three items, its baseline, its version and the rule, and the set writes the
+// ruleset version, within the 100 items of one DynamoDB transaction.
+const MaxRuleChanges = 33
+
+// RuleChangeSet is a group of rule changes applied atomically under one new ruleset
+// version: either every rule is written, every version is appended to the history
+// and the ruleset version advances, or nothing is stored.
+type RuleChangeSet struct {
+	// RulesetVersion is the ruleset version the changes produce. The set applies
+	// only while the ruleset version in effect is still RulesetVersion-1.
+	RulesetVersion int
+	Changes        []RuleChange
+}
+
+// RuleChange is a single rule write of a RuleChangeSet and the versions it records.
+type RuleChange struct {
+	// Before is the stored rule the change was made from, nil when the rule is
+	// created. The change applies only while the stored rule is at Before.Version.
+	Before *Rule
+	// Baseline records the state of a rule that predates the history, and is nil
+	// for rules that already have one.
+	Baseline *RuleVersion
+	// Version records the change. The rule is removed when Version.Deleted is set
+	// and stored as Version.Rule otherwise.
+	Version RuleVersion
+}
+
+// DiffRules returns the attributes that differ between before and after.
+// A nil rule is treated as one with every attribute empty, so creations and
+// deletions list every attribute that was set.
+func DiffRules(before, after *Rule) []RuleFieldChange {
+	beforeAttrs := before.attributes()
+	afterAttrs := after.attributes()
+
+	var changes []RuleFieldChange
+	for i := range beforeAttrs {
+		if beforeAttrs[i].value != afterAttrs[i].value {
+			changes = append(changes, RuleFieldChange{
+				Field:  beforeAttrs[i].name,
+				Before: beforeAttrs[i].value,
+				After:  afterAttrs[i].value,
+			})
+		}
+	}
+
+	return changes
+}
+
+type ruleAttribute struct {
+	name  string
+	value string
+}
+
+var ruleAttributeNames = []string{
+	"rule_name", "condition_field", "condition_operator", "condition_value",
+	"condition", "result_status", "priority", "score_weight", "is_active", "mode",
+}
+
+// attributes renders the user-editable attributes of the rule in the order of
+// ruleAttributeNames. A nil rule renders every attribute as empty.
+func (r *Rule) attributes() []ruleAttribute {
+	attrs := make([]ruleAttribute, len(ruleAttributeNames))
+	for i, name := range ruleAttributeNames {
+		attrs[i].name = name
+	}
+	if r == nil {
+		return attrs
+	}
+
+	condition := ""
+	if r.Condition != nil {
+		condition = r.Condition.String()
+	}
+
+	values := []string{
+		r.RuleName,
+		string(r.ConditionField),
+		string(r.ConditionOperator),
+		r.ConditionValue,
+		condition,
+		string(r.ResultStatus),
+		strconv.Itoa(r.Priority),
+		strconv.Itoa(r.ScoreWeight),
+		strconv.FormatBool(r.IsActive),
+		string(r.EffectiveMode()),
+	}
+	for i := range attrs {
+		attrs[i].value = values[i]
+	}
+
+	return attrs
+}
@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// MaxShadowReportSamples bounds the transactions listed in a shadow report as
+// examples of changed decisions.
+const MaxShadowReportSamples = 20
+
+// ShadowTransition counts the transactions whose decision a SHADOW rule would
+// have changed from ActualStatus to ShadowStatus.
+type ShadowTransition struct {
+	ActualStatus DecisionStatus `json:"actual_status"`
+	ShadowStatus DecisionStatus `json:"shadow_status"`
+	Count        int            `json:"count"`
+}
+
+// ShadowReport summarises how a SHADOW rule would have performed had it been LIVE
+// since the given time. Evaluated counts the transactions the rule was evaluated
+// against in its rule set, Matched those it matched and DecisionChanges those
+// whose decision would have been different.
+type ShadowReport struct {
+	RuleID               string             `json:"rule_id"`
+	RuleName             string             `json:"rule_name"`
+	Mode                 RuleMode           `json:"mode"`
+	Since                time.Time          `json:"since"`
+	Evaluated            int                `json:"evaluated"`
+	Matched              int                `json:"matched"`
+	DecisionChanges      int                `json:"decision_changes"`
+	Transitions          []ShadowTransition `json:"transitions"`
+	SampleTransactionIDs []string           `json:"sample_transaction_ids"`
+}
+
+// NewShadowReport builds the shadow report of the rule from its evaluation results.
+// Only results recorded while the rule was SHADOW and evaluated in its own rule
+// set are counted. Transitions are ordered by count, most frequent first, and the
+// sample transactions are the most recent changed decisions.
+func NewShadowReport(rule *Rule, since time.Time, results []RuleEvaluationResult) *ShadowReport {
+	report := &ShadowReport{
+		RuleID:               rule.RuleID,
+		RuleName:             rule.RuleName,
+		Mode:                 rule.EffectiveMode(),
+		Since:                since,
+		Transitions:          []ShadowTransition{},
+		SampleTransactionIDs: []string{},
+	}
+
+	sorted := make([]RuleEvaluationResult, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].EvaluatedAt.After(sorted[j].EvaluatedAt)
+	})
+
+	transitions := make(map[[2]DecisionStatus]int)
+	for _, result := range sorted {
+		if !result.Shadow || result.ShadowStatus == "" {
+			continue
+		}
+
+		report.Evaluated++
+		if result.Matched {
+			report.Matched++
+		}
+		if !result.ChangesDecision() {
+			continue
+		}
+
+		report.DecisionChanges++
+		transitions[[2]DecisionStatus{result.ActualStatus, result.ShadowStatus}]++
+		if len(report.SampleTransactionIDs) < MaxShadowReportSamples {
+			report.SampleTransactionIDs = append(report.SampleTransactionIDs, result.TransactionID)
+		}
+	}
+
+	for key, count := range transitions {
+		report.Transitions = append(report.Transitions, ShadowTransition{
+			ActualStatus: key[0],
+			ShadowStatus: key[1],
+			Count:        count,
+		})
+	}
+	sort.Slice(report.Transitions, func(i, j int) bool {
+		a, b := report.Transitions[i], report.Transitions[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.ActualStatus != b.ActualStatus {
+			return a.ActualStatus < b.ActualStatus
+		}
+		return a.ShadowStatus < b.ShadowStatus
+	})
+
+	return report
+}
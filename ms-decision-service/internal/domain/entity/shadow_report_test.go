@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecisionPolicy_ShadowRulesNeverDecide(t *testing.T) {
+	rules := []Rule{
+		{RuleID: "shadow", ConditionField: FieldCurrency, ConditionOperator: OpEqual, ConditionValue: "USD", ResultStatus: DECLINED, Priority: 1, ScoreWeight: 100, Mode: RuleModeShadow},
+		{RuleID: "live", ConditionField: FieldCurrency, ConditionOperator: OpEqual, ConditionValue: "USD", ResultStatus: FRAUDCHECK, Priority: 2, ScoreWeight: 30},
+	}
+	tx := &TransactionMessage{Currency: "USD"}
+
+	tests := []struct {
+		name       string
+		policy     DecisionPolicy
+		wantStatus DecisionStatus
+		wantShadow DecisionStatus
+	}{
+		{
+			name:       "first match",
+			policy:     DefaultDecisionPolicy(RuleSetTransaction),
+			wantStatus: FRAUDCHECK,
+			wantShadow: DECLINED,
+		},
+		{
+			name:       "score",
+			policy:     DecisionPolicy{RuleSet: RuleSetTransaction, Mode: ModeScore, FraudCheckThreshold: intPtr(20), DeclineThreshold: 100},
+			wantStatus: FRAUDCHECK,
+			wantShadow: DECLINED,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.policy.Evaluate(tx, rules).Status; got != tc.wantStatus {
+				t.Errorf("expected live decision %s, got %s", tc.wantStatus, got)
+			}
+			if got := tc.policy.ShadowStatus(tx, rules, "shadow"); got != tc.wantShadow {
+				t.Errorf("expected shadow decision %s, got %s", tc.wantShadow, got)
+			}
+			if rules[0].Mode != RuleModeShadow {
+				t.Error("expected ShadowStatus not to modify the rules")
+			}
+		})
+	}
+}
+
+func TestNewShadowReport(t *testing.T) {
+	rule := &Rule{RuleID: "rule-shadow", RuleName: "New BIN rule", Mode: RuleModeShadow}
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minute int) time.Time { return since.Add(time.Duration(minute) * time.Minute) }
+
+	results := []RuleEvaluationResult{
+		{TransactionID: "txn-1", Matched: true, Shadow: true, ShadowStatus: DECLINED, ActualStatus: APPROVED, EvaluatedAt: at(1)},
+		{TransactionID: "txn-2", Matched: true, Shadow: true, ShadowStatus: DECLINED, ActualStatus: APPROVED, EvaluatedAt: at(3)},
+		{TransactionID: "txn-3", Matched: true, Shadow: true, ShadowStatus: DECLINED, ActualStatus: FRAUDCHECK, EvaluatedAt: at(2)},
+		{TransactionID: "txn-4", Matched: true, Shadow: true, ShadowStatus: DECLINED, ActualStatus: DECLINED, EvaluatedAt: at(4)},
+		{TransactionID: "txn-5", Shadow: true, ShadowStatus: APPROVED, ActualStatus: APPROVED, EvaluatedAt: at(5)},
+		// Recorded outside the rule's own rule set, or while the rule was LIVE.
+		{TransactionID: "txn-6", Matched: true, Shadow: true, EvaluatedAt: at(6)},
+		{TransactionID: "txn-7", Matched: true, EvaluatedAt: at(7)},
+	}
+
+	report := NewShadowReport(rule, since, results)
+
+	if report.Evaluated != 5 || report.Matched != 4 || report.DecisionChanges != 3 {
+		t.Errorf("unexpected counts: %+v", report)
+	}
+	if len(report.Transitions) != 2 {
+		t.Fatalf("expected two transitions, got %+v", report.Transitions)
+	}
+	if first := report.Transitions[0]; first.ActualStatus != APPROVED || first.ShadowStatus != DECLINED || first.Count != 2 {
+		t.Errorf("expected APPROVED -> DECLINED first, got %+v", first)
+	}
+	wantSamples := []string{"txn-2", "txn-3", "txn-1"}
+	if len(report.SampleTransactionIDs) != len(wantSamples) {
+		t.Fatalf("expected samples %v, got %v", wantSamples, report.SampleTransactionIDs)
+	}
+	for i, id := range wantSamples {
+		if report.SampleTransactionIDs[i] != id {
+			t.Errorf("sample %d: expected %s, got %s", i, id, report.SampleTransactionIDs[i])
+		}
+	}
+}
@@ -2,6 +2,9 @@ package entity
 
 import (
 	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -19,12 +22,20 @@ type TransactionMessage struct {
 	Status            string    `json:"status"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
+	// RepublishAttempt is set from the republish-attempt header when the
+	// Transaction Evaluator publishes a transaction again because it never
+	// received a decision. It is 0 for the original event.
+	RepublishAttempt int `json:"-"`
 }
 
 // GetFieldValue returns the string representation of the transaction field
-// identified by the given ConditionField. Returns empty string for unknown fields.
+// identified by the given ConditionField, including derived fields.
+// Returns empty string for unknown fields and for values that cannot be derived.
+// amount_in_usd_cents needs currency rates and is derived by EnrichedTransaction.
 func (t *TransactionMessage) GetFieldValue(field ConditionField) string {
 	switch field {
+	case FieldTransactionID:
+		return t.ID
 	case FieldAmountInCents:
 		return fmt.Sprintf("%d", t.AmountInCents)
 	case FieldCurrency:
@@ -33,9 +44,61 @@ func (t *TransactionMessage) GetFieldValue(field ConditionField) string {
 		return t.PaymentMethod
 	case FieldCustomerID:
 		return t.CustomerID
+	case FieldCustomerName:
+		return t.CustomerName
+	case FieldCustomerEmail:
+		return t.CustomerEmail
+	case FieldCustomerPhone:
+		return t.CustomerPhone
 	case FieldCustomerIPAddress:
 		return t.CustomerIPAddress
+	case FieldStatus:
+		return t.Status
+	case FieldCreatedAt:
+		return formatTime(t.CreatedAt)
+	case FieldUpdatedAt:
+		return formatTime(t.UpdatedAt)
+	case FieldEmailDomain:
+		return emailDomain(t.CustomerEmail)
+	case FieldHourOfDayUTC:
+		if t.CreatedAt.IsZero() {
+			return ""
+		}
+		return strconv.Itoa(t.CreatedAt.UTC().Hour())
+	case FieldDayOfWeek:
+		if t.CreatedAt.IsZero() {
+			return ""
+		}
+		return strings.ToUpper(t.CreatedAt.UTC().Weekday().String())
+	case FieldIPVersion:
+		addr, err := netip.ParseAddr(t.CustomerIPAddress)
+		if err != nil {
+			return ""
+		}
+		if addr.Unmap().Is4() {
+			return "4"
+		}
+		return "6"
 	default:
 		return ""
 	}
 }
+
+// formatTime renders a timestamp for time comparisons; the zero time renders as
+// an empty string so it never matches.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// emailDomain returns the lower-cased domain of an email address, or an empty
+// string when the address has no domain.
+func emailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 || at == len(email)-1 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
@@ -132,3 +132,114 @@ func TestProperty_GetFieldValueCoversAllValidConditionFields(t *testing.T) {
 
 	properties.TestingRun(t)
 }
+
+func TestTransactionMessage_GetFieldValue(t *testing.T) {
+	tx := &TransactionMessage{
+		ID:                "tx-001",
+		AmountInCents:     200000,
+		Currency:          "EUR",
+		PaymentMethod:     "CARD",
+		CustomerID:        "cust-001",
+		CustomerName:      "Jane Doe",
+		CustomerEmail:     "Jane@Mailinator.COM",
+		CustomerPhone:     "+573001234567",
+		CustomerIPAddress: "2001:db8::1",
+		Status:            "PENDING",
+		CreatedAt:         time.Date(2025, 1, 18, 3, 15, 0, 0, time.FixedZone("COT", -5*3600)),
+	}
+
+	tests := []struct {
+		field ConditionField
+		want  string
+	}{
+		{FieldTransactionID, "tx-001"},
+		{FieldCustomerName, "Jane Doe"},
+		{FieldCustomerEmail, "Jane@Mailinator.COM"},
+		{FieldCustomerPhone, "+573001234567"},
+		{FieldStatus, "PENDING"},
+		{FieldCreatedAt, "2025-01-18T08:15:00Z"},
+		{FieldUpdatedAt, ""},
+		{FieldEmailDomain, "mailinator.com"},
+		{FieldHourOfDayUTC, "8"},
+		{FieldDayOfWeek, "SATURDAY"},
+		{FieldAmountInUSDCents, ""},
+		{FieldIPVersion, "6"},
+		{FieldFraudScore, ""},
+		{"shoe_size", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.field), func(t *testing.T) {
+			if got := tx.GetFieldValue(tt.field); got != tt.want {
+				t.Errorf("GetFieldValue(%s) = %q, want %q", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionMessage_DerivedFieldsWithMissingInputs(t *testing.T) {
+	tx := &TransactionMessage{Currency: "GBP", CustomerEmail: "no-domain@", CustomerIPAddress: "::ffff:10.0.0.1"}
+
+	for _, field := range []ConditionField{FieldAmountInUSDCents, FieldEmailDomain, FieldHourOfDayUTC, FieldDayOfWeek} {
+		if got := tx.GetFieldValue(field); got != "" {
+			t.Errorf("GetFieldValue(%s) = %q, want empty", field, got)
+		}
+	}
+	if got := tx.GetFieldValue(FieldIPVersion); got != "4" {
+		t.Errorf("expected IPv4-mapped address to report version 4, got %q", got)
+	}
+}
+
+func TestFields_RegistryDescribesEveryField(t *testing.T) {
+	defs := fields()
+
+	for i, def := range defs {
+		if !def.Field.IsValid() || def.Type == "" || def.Description == "" {
+			t.Errorf("incomplete field definition: %+v", def)
+		}
+		if i > 0 && defs[i-1].Field >= def.Field {
+			t.Errorf("fields are not sorted: %s before %s", defs[i-1].Field, def.Field)
+		}
+	}
+
+	if def, ok := FieldHourOfDayUTC.Definition(); !ok || def.Type != FieldTypeNumeric || !def.Derived {
+		t.Errorf("unexpected definition for hour_of_day_utc: %+v", def)
+	}
+	if FieldCreatedAt.Type() != FieldTypeTime {
+		t.Error("expected created_at to be a time field")
+	}
+}
+
+func TestEnrichedTransaction_GetFieldValue(t *testing.T) {
+	tx := &EnrichedTransaction{
+		TransactionMessage: &TransactionMessage{CustomerID: "cust-001"},
+		Velocity:           VelocityCounters{},
+	}
+	tx.Velocity.Set(VelocityDimensions[0], []VelocityAggregate{{Count: 1, AmountSum: 10}, {Count: 5, AmountSum: 50}})
+
+	if got := tx.GetFieldValue("customer_txn_count_1h"); got != "5" {
+		t.Errorf("customer_txn_count_1h = %q, want 5", got)
+	}
+	if got := tx.GetFieldValue("customer_amount_sum_1m"); got != "10" {
+		t.Errorf("customer_amount_sum_1m = %q, want 10", got)
+	}
+	if got := tx.GetFieldValue("ip_txn_count_1h"); got != "" {
+		t.Errorf("expected uncomputed velocity field to be empty, got %q", got)
+	}
+	if got := tx.GetFieldValue(FieldCustomerID); got != "cust-001" {
+		t.Errorf("expected transaction attributes to pass through, got %q", got)
+	}
+	if got := tx.GetFieldValue(FieldAmountInUSDCents); got != "" {
+		t.Errorf("expected amount_in_usd_cents to be empty without rates, got %q", got)
+	}
+
+	tx.TransactionMessage = &TransactionMessage{AmountInCents: 200000, Currency: "eur"}
+	tx.Rates = CurrencyRates{"EUR": 1.08}
+	if got := tx.GetFieldValue(FieldAmountInUSDCents); got != "216000" {
+		t.Errorf("amount_in_usd_cents = %q, want 216000", got)
+	}
+
+	if def, ok := ConditionField("ip_amount_sum_24h").Definition(); !ok || def.Type != FieldTypeNumeric {
+		t.Errorf("expected velocity fields to be registered as numeric, got %+v", def)
+	}
+}
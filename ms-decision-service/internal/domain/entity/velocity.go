@@ -0,0 +1,135 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// VelocityDimension is a transaction attribute that velocity counters are kept for,
+// e.g. the customer or the IP address.
+type VelocityDimension struct {
+	Name  string
+	Field ConditionField
+}
+
+// VelocityWindow is a sliding window that velocity counters are aggregated over.
+type VelocityWindow struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Velocity metrics aggregated for every dimension and window.
+const (
+	VelocityMetricCount     = "txn_count"
+	VelocityMetricAmountSum = "amount_sum"
+)
+
+// VelocityDimensions lists the attributes velocity counters are kept for.
+var VelocityDimensions = []VelocityDimension{
+	{Name: "customer", Field: FieldCustomerID},
+	{Name: "ip", Field: FieldCustomerIPAddress},
+	{Name: "email", Field: FieldCustomerEmail},
+}
+
+// VelocityWindows lists the sliding windows velocity counters are aggregated over.
+var VelocityWindows = []VelocityWindow{
+	{Name: "1m", Duration: time.Minute},
+	{Name: "1h", Duration: time.Hour},
+	{Name: "24h", Duration: 24 * time.Hour},
+}
+
+// VelocityEvent is a single transaction recorded in a velocity counter. The amount
+// is converted to US cents so sums are comparable across currencies.
+type VelocityEvent struct {
+	TransactionID    string
+	AmountInUSDCents int64
+	OccurredAt       time.Time
+}
+
+// VelocityAggregate is the number of transactions and their total amount in US cents
+// within a window.
+type VelocityAggregate struct {
+	Count     int64
+	AmountSum int64
+}
+
+// VelocityField returns the rule field exposing a metric of a dimension over a window,
+// e.g. customer_txn_count_1h or ip_amount_sum_24h.
+func VelocityField(dimension VelocityDimension, metric string, window VelocityWindow) ConditionField {
+	return ConditionField(fmt.Sprintf("%s_%s_%s", dimension.Name, metric, window.Name))
+}
+
+// VelocityKey returns the counter key of a dimension for the transaction, or an empty
+// string when the transaction has no value for the dimension.
+func VelocityKey(dimension VelocityDimension, transaction *TransactionMessage) string {
+	value := transaction.GetFieldValue(dimension.Field)
+	if value == "" {
+		return ""
+	}
+	return dimension.Name + ":" + value
+}
+
+// VelocityCounters holds the velocity field values computed for a transaction.
+type VelocityCounters map[ConditionField]int64
+
+// Set stores the aggregates of a dimension, one per window in VelocityWindows order.
+func (c VelocityCounters) Set(dimension VelocityDimension, aggregates []VelocityAggregate) {
+	for i, window := range VelocityWindows {
+		if i >= len(aggregates) {
+			return
+		}
+		c[VelocityField(dimension, VelocityMetricCount, window)] = aggregates[i].Count
+		c[VelocityField(dimension, VelocityMetricAmountSum, window)] = aggregates[i].AmountSum
+	}
+}
+
+// EnrichedTransaction is a transaction together with the velocity counters, managed
+// lists and currency rates used to evaluate it. Velocity fields that were not
+// computed evaluate as empty and never match; without lists, list conditions never
+// match; without a rate for its currency, amount_in_usd_cents is empty.
+type EnrichedTransaction struct {
+	*TransactionMessage
+	Velocity VelocityCounters
+	Lists    ListLookup
+	Rates    CurrencyRates
+}
+
+// Lookup implements ListLookup using the transaction's lists.
+func (e *EnrichedTransaction) Lookup(listName string, field ConditionField, value string) (*ManagedList, *ListEntry) {
+	if e.Lists == nil {
+		return nil, nil
+	}
+	return e.Lists.Lookup(listName, field, value)
+}
+
+// GetFieldValue returns the velocity counter for velocity fields, the converted
+// amount for amount_in_usd_cents and the transaction attribute for every other field.
+func (e *EnrichedTransaction) GetFieldValue(field ConditionField) string {
+	if value, ok := e.Velocity[field]; ok {
+		return strconv.FormatInt(value, 10)
+	}
+	if field == FieldAmountInUSDCents {
+		amount, ok := e.Rates.ToUSDCents(e.AmountInCents, e.Currency)
+		if !ok {
+			return ""
+		}
+		return strconv.FormatInt(amount, 10)
+	}
+	return e.TransactionMessage.GetFieldValue(field)
+}
+
+func init() {
+	for _, dimension := range VelocityDimensions {
+		for _, window := range VelocityWindows {
+			fieldRegistry[VelocityField(dimension, VelocityMetricCount, window)] = FieldDefinition{
+				Type: FieldTypeNumeric, Derived: true,
+				Description: fmt.Sprintf("Transactions with the same %s in the last %s", dimension.Field, window.Name),
+			}
+			fieldRegistry[VelocityField(dimension, VelocityMetricAmountSum, window)] = FieldDefinition{
+				Type: FieldTypeNumeric, Derived: true,
+				Description: fmt.Sprintf("Total amount_in_usd_cents of transactions with the same %s in the last %s", dimension.Field, window.Name),
+			}
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+)
+
+// DeadLetterPublisher defines the port for publishing messages that could not be
+// processed to a dead-letter topic.
+type DeadLetterPublisher interface {
+	Publish(ctx context.Context, deadLetter *entity.DeadLetter) error
+}
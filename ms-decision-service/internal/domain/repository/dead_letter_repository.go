@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+)
+
+// DeadLetterRepository defines the port for inspecting the Transaction.Created and
+// FraudSignals.Calculated dead-letter topics and redriving their messages.
+type DeadLetterRepository interface {
+	// Count returns the number of messages retained in the dead-letter topic.
+	Count(ctx context.Context, topic string) (int64, error)
+	// FindAll returns up to limit messages of the dead-letter topic, oldest first.
+	FindAll(ctx context.Context, topic string, limit int) ([]entity.DeadLetter, error)
+	// FindByOffset returns the message at the given position of the dead-letter
+	// topic, or nil if there is none.
+	FindByOffset(ctx context.Context, topic string, partition int32, offset int64) (*entity.DeadLetter, error)
+	// Redrive publishes the original message back to its source topic.
+	Redrive(ctx context.Context, deadLetter *entity.DeadLetter) error
+}
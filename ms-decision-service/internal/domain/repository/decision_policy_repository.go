@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+)
+
+// DecisionPolicyRepository defines the port for the decision policy of each rule set.
+type DecisionPolicyRepository interface {
+	// FindAll returns every stored policy. Rule sets without a stored policy are omitted.
+	FindAll(ctx context.Context) ([]entity.DecisionPolicy, error)
+	// FindByRuleSet returns the stored policy of the rule set, or nil when there is none.
+	FindByRuleSet(ctx context.Context, ruleSet entity.RuleSet) (*entity.DecisionPolicy, error)
+	// Save stores the policy, replacing any previous policy of the same rule set.
+	Save(ctx context.Context, policy *entity.DecisionPolicy) error
+}
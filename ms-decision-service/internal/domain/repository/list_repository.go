@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+)
+
+// ListRepository defines the port for managed blocklists and allowlists.
+type ListRepository interface {
+	// FindAll returns every list sorted by name.
+	FindAll(ctx context.Context) ([]entity.ManagedList, error)
+	// FindByName returns the list with the given name, or nil when it does not exist.
+	FindByName(ctx context.Context, name string) (*entity.ManagedList, error)
+	// FindEntries returns every entry of the list, including expired ones, sorted by value.
+	FindEntries(ctx context.Context, name string) ([]entity.ListEntry, error)
+	// Create stores a new list and fails if a list with the same name already exists.
+	Create(ctx context.Context, list *entity.ManagedList) error
+	// Delete removes the list and all of its entries.
+	Delete(ctx context.Context, name string) error
+	// PutEntries adds the entries, replacing existing entries with the same value.
+	PutEntries(ctx context.Context, entries []entity.ListEntry) error
+	// DeleteEntry removes a single entry and fails if it does not exist.
+	DeleteEntry(ctx context.Context, name, value string) error
+	// LoadSnapshot returns every list and entry indexed for rule evaluation.
+	LoadSnapshot(ctx context.Context) (*entity.ListSnapshot, error)
+}
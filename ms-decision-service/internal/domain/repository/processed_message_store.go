@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+)
+
+// ProcessedMessageStore defines the port for remembering the decision computed for a
+// transaction at each processing stage, so that redelivered messages are not
+// processed twice.
+type ProcessedMessageStore interface {
+	// Find returns the result recorded for the transaction at the stage, or nil when
+	// none is recorded or it has expired.
+	Find(ctx context.Context, transactionID string, stage entity.ProcessingStage) (*entity.DecisionResult, error)
+	// Save records the result of processing the transaction at the stage.
+	Save(ctx context.Context, stage entity.ProcessingStage, result *entity.DecisionResult) error
+}
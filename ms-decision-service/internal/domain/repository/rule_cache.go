@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+)
+
+// RuleCache defines the port for managing the in-memory snapshot of active rules.
+type RuleCache interface {
+	// Refresh reloads the snapshot from the underlying store.
+	Refresh(ctx context.Context) error
+	// Status reports the version and age of the current snapshot.
+	Status() entity.RuleCacheStatus
+}
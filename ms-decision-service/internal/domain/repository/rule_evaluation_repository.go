@@ -3,10 +3,14 @@ package repository
 import (
 	"context"
 	"ms-decision-service/internal/domain/entity"
+	"time"
 )
 
 // RuleEvaluationRepository defines the port for persisting and retrieving rule evaluation results.
 type RuleEvaluationRepository interface {
 	SaveBatch(ctx context.Context, results []entity.RuleEvaluationResult) error
 	FindByTransactionID(ctx context.Context, transactionID string) ([]entity.RuleEvaluationResult, error)
+	// FindShadowByRuleID returns the results recorded for the rule while it was SHADOW,
+	// evaluated at or after since.
+	FindShadowByRuleID(ctx context.Context, ruleID string, since time.Time) ([]entity.RuleEvaluationResult, error)
 }
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+)
+
+// RuleHistoryRepository defines the port for reading the append-only history of rule
+// changes and the ruleset version counter. Both are written by
+// RuleRepository.ApplyChanges together with the rules.
+type RuleHistoryRepository interface {
+	// FindByRuleID returns every version of the rule sorted by version ascending.
+	FindByRuleID(ctx context.Context, ruleID string) ([]entity.RuleVersion, error)
+	// FindAll returns every recorded rule version sorted by ruleset version ascending.
+	FindAll(ctx context.Context) ([]entity.RuleVersion, error)
+	// CurrentRulesetVersion returns the version of the rule set currently in effect.
+	CurrentRulesetVersion(ctx context.Context) (int, error)
+}
@@ -5,8 +5,24 @@ import (
 	"ms-decision-service/internal/domain/entity"
 )
 
-// RuleRepository defines the port for retrieving fraud detection rules.
+// RuleRepository defines the port for retrieving and managing fraud detection rules.
 type RuleRepository interface {
 	FindActiveRulesSortedByPriority(ctx context.Context) ([]entity.Rule, error)
 	FindAll(ctx context.Context) ([]entity.Rule, error)
+	// FindByID returns the rule with the given ID, or nil when it does not exist.
+	FindByID(ctx context.Context, ruleID string) (*entity.Rule, error)
+	// ApplyChanges atomically writes the rules of the set, appends their versions to
+	// the rule history and advances the ruleset version. It fails with
+	// entity.ErrRuleChangeConflict, storing nothing, when a rule or the ruleset
+	// version no longer matches the one the set was made from.
+	ApplyChanges(ctx context.Context, set *entity.RuleChangeSet) error
+}
+
+// ActiveRulesetRepository is implemented by rule repositories that keep the active
+// rules in a snapshot together with the ruleset version they were loaded at, so
+// that both are read from the same snapshot.
+type ActiveRulesetRepository interface {
+	// FindActiveRuleset returns the active rules sorted by priority and the
+	// ruleset version they were loaded at.
+	FindActiveRuleset(ctx context.Context) ([]entity.Rule, int, error)
 }
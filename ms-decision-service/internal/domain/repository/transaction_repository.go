@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"time"
+)
+
+// TransactionRepository defines the read-only port for historical transactions,
+// which are owned by the transaction evaluator.
+type TransactionRepository interface {
+	// FindCreatedBetween returns the most recent limit transactions created in
+	// [from, to), oldest first.
+	FindCreatedBetween(ctx context.Context, from, to time.Time, limit int) ([]entity.TransactionMessage, error)
+}
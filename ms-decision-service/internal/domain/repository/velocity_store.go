@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"time"
+)
+
+// VelocityStore defines the port for sliding-window transaction counters.
+type VelocityStore interface {
+	// Record adds the event to the counters of key and returns the key's aggregates
+	// over each window, in order, including the event. Windows end at the event's
+	// OccurredAt. Recording the same transaction twice for a key counts it once.
+	Record(ctx context.Context, key string, event entity.VelocityEvent, windows []time.Duration) ([]entity.VelocityAggregate, error)
+}
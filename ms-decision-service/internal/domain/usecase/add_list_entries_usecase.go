@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+	"time"
+)
+
+// MaxListEntriesPerRequest caps the number of entries added by a single request.
+const MaxListEntriesPerRequest = 10000
+
+// AddListEntriesUseCase adds one or many entries to a managed list.
+type AddListEntriesUseCase struct {
+	listRepo repository.ListRepository
+}
+
+// NewAddListEntriesUseCase creates a new use case with the given repository.
+func NewAddListEntriesUseCase(
+	listRepo repository.ListRepository,
+) *AddListEntriesUseCase {
+	return &AddListEntriesUseCase{
+		listRepo: listRepo,
+	}
+}
+
+// Execute validates the entries and adds them to the list, attributed to actor.
+// Values are normalized for the list's field; an entry whose value is already in
+// the list replaces it, and duplicates within the request keep the last one.
+// Nothing is written when any entry is invalid.
+func (uc *AddListEntriesUseCase) Execute(
+	ctx context.Context,
+	name string,
+	entries []entity.ListEntry,
+	actor string,
+) ([]entity.ListEntry, error) {
+	list, err := findExistingList(ctx, uc.listRepo, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, ErrListEntriesEmpty
+	}
+	if len(entries) > MaxListEntriesPerRequest {
+		return nil, &ValidationError{Err: ErrListValidationFailed, Violations: []entity.RuleViolation{{
+			Field:   "entries",
+			Message: fmt.Sprintf("at most %d entries can be added per request", MaxListEntriesPerRequest),
+		}}}
+	}
+
+	now := time.Now().UTC()
+
+	var violations []entity.RuleViolation
+	for i := range entries {
+		violations = append(violations, list.ValidateEntry(&entries[i], i, now)...)
+	}
+	if len(violations) > 0 {
+		return nil, &ValidationError{Err: ErrListValidationFailed, Violations: violations}
+	}
+
+	positions := make(map[string]int, len(entries))
+	added := make([]entity.ListEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.ListName = list.Name
+		entry.Value = entity.NormalizeListValue(list.Field, entry.Value)
+		entry.AddedBy = actor
+		entry.AddedAt = now
+
+		if i, ok := positions[entry.Value]; ok {
+			added[i] = entry
+			continue
+		}
+		positions[entry.Value] = len(added)
+		added = append(added, entry)
+	}
+
+	if err := uc.listRepo.PutEntries(ctx, added); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrListPersistenceFailed, err)
+	}
+
+	return added, nil
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+	"time"
+)
+
+func TestAddListEntriesUseCase_Execute(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		listName    string
+		entries     []entity.ListEntry
+		listRepo    *mockListRepository
+		wantErr     error
+		wantEntries []string
+	}{
+		{
+			name:     "empty name returns ErrListNameEmpty",
+			listName: "",
+			entries:  []entity.ListEntry{{Value: "fraud@example.com"}},
+			listRepo: &mockListRepository{},
+			wantErr:  ErrListNameEmpty,
+		},
+		{
+			name:     "unknown list returns ErrListNotFound",
+			listName: "missing",
+			entries:  []entity.ListEntry{{Value: "fraud@example.com"}},
+			listRepo: &mockListRepository{findByNameFunc: findEmailBlocklist},
+			wantErr:  ErrListNotFound,
+		},
+		{
+			name:     "no entries returns ErrListEntriesEmpty",
+			listName: "blocked-emails",
+			listRepo: &mockListRepository{findByNameFunc: findEmailBlocklist},
+			wantErr:  ErrListEntriesEmpty,
+		},
+		{
+			name:     "expired entry returns ErrListValidationFailed",
+			listName: "blocked-emails",
+			entries: []entity.ListEntry{
+				{Value: "fraud@example.com"},
+				{Value: "old@example.com", ExpiresAt: &past},
+			},
+			listRepo: &mockListRepository{findByNameFunc: findEmailBlocklist},
+			wantErr:  ErrListValidationFailed,
+		},
+		{
+			name:     "repository write failure returns ErrListPersistenceFailed",
+			listName: "blocked-emails",
+			entries:  []entity.ListEntry{{Value: "fraud@example.com"}},
+			listRepo: &mockListRepository{
+				findByNameFunc: findEmailBlocklist,
+				putEntriesFunc: func(_ context.Context, _ []entity.ListEntry) error {
+					return errors.New("dynamo timeout")
+				},
+			},
+			wantErr: ErrListPersistenceFailed,
+		},
+		{
+			name:     "values are normalized and deduplicated",
+			listName: "blocked-emails",
+			entries: []entity.ListEntry{
+				{Value: " Fraud@Example.com ", Reason: "first"},
+				{Value: "mule@example.com", ExpiresAt: &future},
+				{Value: "fraud@example.com", Reason: "second"},
+			},
+			listRepo:    &mockListRepository{findByNameFunc: findEmailBlocklist},
+			wantEntries: []string{"fraud@example.com", "mule@example.com"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := NewAddListEntriesUseCase(tc.listRepo)
+			added, err := uc.Execute(context.Background(), tc.listName, tc.entries, "analyst@example.com")
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+				}
+				if tc.listRepo.putEntries != nil && errors.Is(tc.wantErr, ErrListValidationFailed) {
+					t.Error("expected nothing to be written when an entry is invalid")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(added) != len(tc.wantEntries) {
+				t.Fatalf("expected %d entries, got %d", len(tc.wantEntries), len(added))
+			}
+			for i, want := range tc.wantEntries {
+				if added[i].Value != want {
+					t.Errorf("entry %d: expected value %q, got %q", i, want, added[i].Value)
+				}
+				if added[i].ListName != tc.listName || added[i].AddedBy != "analyst@example.com" {
+					t.Errorf("entry %d: expected list name and actor to be set, got %+v", i, added[i])
+				}
+			}
+			if added[0].Reason != "second" {
+				t.Errorf("expected the last duplicate to win, got reason %q", added[0].Reason)
+			}
+		})
+	}
+}
+
+func TestRemoveListEntryUseCase_Execute(t *testing.T) {
+	entries := func(_ context.Context, _ string) ([]entity.ListEntry, error) {
+		return []entity.ListEntry{{ListName: "blocked-emails", Value: "fraud@example.com"}}, nil
+	}
+
+	t.Run("normalizes the value before removing it", func(t *testing.T) {
+		listRepo := &mockListRepository{findByNameFunc: findEmailBlocklist, findEntriesFunc: entries}
+
+		if err := NewRemoveListEntryUseCase(listRepo).Execute(context.Background(), "blocked-emails", "FRAUD@example.com"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if listRepo.deletedEntry != "fraud@example.com" {
+			t.Errorf("expected normalized value to be removed, got %q", listRepo.deletedEntry)
+		}
+	})
+
+	t.Run("unknown value returns ErrListEntryNotFound", func(t *testing.T) {
+		listRepo := &mockListRepository{findByNameFunc: findEmailBlocklist, findEntriesFunc: entries}
+
+		err := NewRemoveListEntryUseCase(listRepo).Execute(context.Background(), "blocked-emails", "other@example.com")
+		if !errors.Is(err, ErrListEntryNotFound) {
+			t.Fatalf("expected ErrListEntryNotFound, got %v", err)
+		}
+		if listRepo.deletedEntry != "" {
+			t.Error("expected nothing to be removed")
+		}
+	})
+}
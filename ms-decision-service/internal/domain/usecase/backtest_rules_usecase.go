@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultBacktestWindow is how far back a backtest loads transactions when no
+	// start is given.
+	DefaultBacktestWindow = 30 * 24 * time.Hour
+	// DefaultBacktestLimit is the number of transactions loaded when no limit is given.
+	DefaultBacktestLimit = 10000
+	// MaxBacktestTransactions bounds the transactions replayed by a single backtest.
+	MaxBacktestTransactions = 100000
+)
+
+// BacktestRequest describes a backtest. Rules is the candidate rule set; when nil,
+// the currently active rules are replayed. When Transactions is nil, up to Limit
+// transactions created in [From, To) are loaded from the transaction store.
+type BacktestRequest struct {
+	Rules        []entity.Rule
+	From         time.Time
+	To           time.Time
+	Limit        int
+	Transactions []entity.TransactionMessage
+}
+
+// BacktestRulesUseCase replays historical transactions through a candidate rule set
+// and reports the decisions it would have made, without publishing anything.
+type BacktestRulesUseCase struct {
+	ruleRepo        repository.RuleRepository
+	transactionRepo repository.TransactionRepository
+	listRepo        repository.ListRepository
+	policyRepo      repository.DecisionPolicyRepository
+	rates           entity.CurrencyRates
+}
+
+// NewBacktestRulesUseCase creates a new use case with the given ports. listRepo and
+// policyRepo are optional: without them list conditions never match and rules are
+// replayed in FIRST_MATCH mode. rates converts amounts for amount_in_usd_cents.
+func NewBacktestRulesUseCase(
+	ruleRepo repository.RuleRepository,
+	transactionRepo repository.TransactionRepository,
+	listRepo repository.ListRepository,
+	policyRepo repository.DecisionPolicyRepository,
+	rates entity.CurrencyRates,
+) *BacktestRulesUseCase {
+	return &BacktestRulesUseCase{
+		ruleRepo:        ruleRepo,
+		transactionRepo: transactionRepo,
+		listRepo:        listRepo,
+		policyRepo:      policyRepo,
+		rates:           rates,
+	}
+}
+
+// Execute runs the backtest. Only rules of the TRANSACTION rule set are replayed,
+// since historical transactions carry no fraud score, and velocity fields are not
+// reconstructed. Managed lists and the decision policy are the current ones.
+func (uc *BacktestRulesUseCase) Execute(
+	ctx context.Context,
+	req BacktestRequest,
+) (*entity.BacktestReport, error) {
+	rules, err := uc.candidateRules(ctx, req.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := uc.loadTransactions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := loadDecisionPolicy(ctx, uc.policyRepo, entity.RuleSetTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	var lists entity.ListLookup
+	if uc.listRepo != nil {
+		snapshot, err := uc.listRepo.LoadSnapshot(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrListRetrievalFailed, err)
+		}
+		lists = snapshot
+	}
+
+	backtest := entity.NewBacktest(policy, entity.RulesInSet(rules, entity.RuleSetTransaction))
+	for i := range transactions {
+		src := &entity.EnrichedTransaction{TransactionMessage: &transactions[i], Lists: lists, Rates: uc.rates}
+		backtest.Replay(transactions[i].ID, src, entity.DecisionStatus(transactions[i].Status))
+	}
+
+	return backtest.Report(), nil
+}
+
+// candidateRules validates and compiles the candidate rule set, or loads the active
+// rules when none is given. Inactive candidates are dropped and the rest sorted by
+// priority; candidates without an ID are named after their position.
+func (uc *BacktestRulesUseCase) candidateRules(ctx context.Context, candidates []entity.Rule) ([]entity.Rule, error) {
+	if candidates == nil {
+		rules, err := uc.ruleRepo.FindActiveRulesSortedByPriority(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRuleRetrievalFailed, err)
+		}
+		return rules, nil
+	}
+
+	var violations []entity.RuleViolation
+	rules := make([]entity.Rule, 0, len(candidates))
+	for i := range candidates {
+		rule := candidates[i]
+		if rule.RuleID == "" {
+			rule.RuleID = fmt.Sprintf("candidate-%d", i+1)
+		}
+		for _, v := range rule.Validate() {
+			violations = append(violations, entity.RuleViolation{
+				Field:   fmt.Sprintf("rules[%d].%s", i, v.Field),
+				Message: v.Message,
+			})
+		}
+		if rule.IsActive {
+			rules = append(rules, rule)
+		}
+	}
+	if len(violations) > 0 {
+		return nil, &ValidationError{Err: ErrRuleValidationFailed, Violations: violations}
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority < rules[j].Priority
+	})
+	for i := range rules {
+		// Values were validated above, so compilation cannot fail.
+		_ = rules[i].Compile()
+	}
+
+	return rules, nil
+}
+
+// loadTransactions returns the transactions given in the request, or loads them
+// from the transaction store using the request window and limit.
+func (uc *BacktestRulesUseCase) loadTransactions(ctx context.Context, req BacktestRequest) ([]entity.TransactionMessage, error) {
+	if req.Transactions != nil {
+		if len(req.Transactions) > MaxBacktestTransactions {
+			return nil, fmt.Errorf("%w: %d transactions given, at most %d are allowed",
+				ErrBacktestLimitExceeded, len(req.Transactions), MaxBacktestTransactions)
+		}
+		return req.Transactions, nil
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultBacktestLimit
+	}
+	if limit > MaxBacktestTransactions {
+		return nil, fmt.Errorf("%w: limit %d exceeds %d", ErrBacktestLimitExceeded, limit, MaxBacktestTransactions)
+	}
+
+	to := req.To
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	from := req.From
+	if from.IsZero() {
+		from = to.Add(-DefaultBacktestWindow)
+	}
+
+	transactions, err := uc.transactionRepo.FindCreatedBetween(ctx, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTransactionRetrievalFailed, err)
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+	"time"
+)
+
+// mockTransactionRepository is a test double for repository.TransactionRepository.
+type mockTransactionRepository struct {
+	findFunc  func(ctx context.Context, from, to time.Time, limit int) ([]entity.TransactionMessage, error)
+	lastFrom  time.Time
+	lastTo    time.Time
+	lastLimit int
+}
+
+func (m *mockTransactionRepository) FindCreatedBetween(ctx context.Context, from, to time.Time, limit int) ([]entity.TransactionMessage, error) {
+	m.lastFrom, m.lastTo, m.lastLimit = from, to, limit
+	if m.findFunc != nil {
+		return m.findFunc(ctx, from, to, limit)
+	}
+	return nil, nil
+}
+
+func historicalTransactions() []entity.TransactionMessage {
+	return []entity.TransactionMessage{
+		{ID: "txn-1", PaymentMethod: "CARD", Currency: "USD", AmountInCents: 6000000, Status: "DECLINED"},
+		{ID: "txn-2", PaymentMethod: "CARD", Currency: "USD", AmountInCents: 4000000, Status: "APPROVED"},
+		{ID: "txn-3", PaymentMethod: "CARD", Currency: "USD", AmountInCents: 1000, Status: "APPROVED"},
+	}
+}
+
+func TestBacktestRulesUseCase_Execute(t *testing.T) {
+	activeRules := []entity.Rule{{
+		RuleID: "rule-high-value", RuleName: "Decline high-value", ConditionField: entity.FieldAmountInCents,
+		ConditionOperator: entity.OpGreaterThan, ConditionValue: "5000000", ResultStatus: entity.DECLINED, Priority: 2, IsActive: true,
+	}}
+	ruleRepo := &mockRuleRepository{
+		findFunc: func(_ context.Context) ([]entity.Rule, error) {
+			return activeRules, nil
+		},
+	}
+
+	t.Run("candidate threshold change against stored transactions", func(t *testing.T) {
+		transactionRepo := &mockTransactionRepository{
+			findFunc: func(_ context.Context, _, _ time.Time, _ int) ([]entity.TransactionMessage, error) {
+				return historicalTransactions(), nil
+			},
+		}
+		candidate := activeRules[0]
+		candidate.ConditionValue = "2500000"
+		fraudScoreRule := entity.Rule{
+			RuleID: "rule-fraud-score", RuleName: "High fraud score", ConditionField: entity.FieldFraudScore,
+			ConditionOperator: entity.OpGreaterThan, ConditionValue: "80", ResultStatus: entity.DECLINED, Priority: 10, IsActive: true,
+		}
+		uc := NewBacktestRulesUseCase(ruleRepo, transactionRepo, nil, nil, nil)
+
+		report, err := uc.Execute(context.Background(), BacktestRequest{Rules: []entity.Rule{fraudScoreRule, candidate}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if report.TransactionCount != 3 || report.Decisions[entity.DECLINED] != 2 || report.Changed != 1 {
+			t.Errorf("unexpected report: %+v", report)
+		}
+		if len(report.Rules) != 1 || report.Rules[0].RuleID != "rule-high-value" {
+			t.Errorf("expected only transaction rules to be replayed, got %+v", report.Rules)
+		}
+		if transactionRepo.lastLimit != DefaultBacktestLimit {
+			t.Errorf("expected the default limit, got %d", transactionRepo.lastLimit)
+		}
+		if window := transactionRepo.lastTo.Sub(transactionRepo.lastFrom); window != DefaultBacktestWindow {
+			t.Errorf("expected the default window, got %v", window)
+		}
+	})
+
+	t.Run("active rules against given transactions", func(t *testing.T) {
+		transactionRepo := &mockTransactionRepository{}
+		uc := NewBacktestRulesUseCase(ruleRepo, transactionRepo, nil, nil, nil)
+
+		report, err := uc.Execute(context.Background(), BacktestRequest{Transactions: historicalTransactions()})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if report.Changed != 0 || report.Compared != 3 {
+			t.Errorf("expected the active rules to reproduce every decision, got %+v", report)
+		}
+		if !transactionRepo.lastTo.IsZero() {
+			t.Error("expected the transaction store not to be queried")
+		}
+	})
+
+	t.Run("invalid candidate rules", func(t *testing.T) {
+		uc := NewBacktestRulesUseCase(ruleRepo, &mockTransactionRepository{}, nil, nil, nil)
+		candidate := activeRules[0]
+		candidate.ConditionValue = "lots"
+
+		_, err := uc.Execute(context.Background(), BacktestRequest{Rules: []entity.Rule{candidate}})
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected ValidationError, got %v", err)
+		}
+		if validationErr.Violations[0].Field != "rules[0].condition_value" {
+			t.Errorf("expected violations to be prefixed with the rule index, got %+v", validationErr.Violations)
+		}
+	})
+
+	t.Run("limit above the maximum", func(t *testing.T) {
+		uc := NewBacktestRulesUseCase(ruleRepo, &mockTransactionRepository{}, nil, nil, nil)
+
+		_, err := uc.Execute(context.Background(), BacktestRequest{Limit: MaxBacktestTransactions + 1})
+		if !errors.Is(err, ErrBacktestLimitExceeded) {
+			t.Errorf("expected ErrBacktestLimitExceeded, got %v", err)
+		}
+	})
+
+	t.Run("transaction store failure", func(t *testing.T) {
+		transactionRepo := &mockTransactionRepository{
+			findFunc: func(_ context.Context, _, _ time.Time, _ int) ([]entity.TransactionMessage, error) {
+				return nil, errors.New("table unavailable")
+			},
+		}
+		uc := NewBacktestRulesUseCase(ruleRepo, transactionRepo, nil, nil, nil)
+
+		_, err := uc.Execute(context.Background(), BacktestRequest{})
+		if !errors.Is(err, ErrTransactionRetrievalFailed) {
+			t.Errorf("expected ErrTransactionRetrievalFailed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+	"time"
+)
+
+// CreateListUseCase validates and stores a new managed list.
+type CreateListUseCase struct {
+	listRepo repository.ListRepository
+}
+
+// NewCreateListUseCase creates a new use case with the given repository.
+func NewCreateListUseCase(
+	listRepo repository.ListRepository,
+) *CreateListUseCase {
+	return &CreateListUseCase{
+		listRepo: listRepo,
+	}
+}
+
+// Execute validates the list definition and stores it, attributed to actor.
+// List names are unique.
+func (uc *CreateListUseCase) Execute(
+	ctx context.Context,
+	list *entity.ManagedList,
+	actor string,
+) (*entity.ManagedList, error) {
+	if list == nil {
+		return nil, ErrListNil
+	}
+
+	if violations := list.Validate(); len(violations) > 0 {
+		return nil, &ValidationError{Err: ErrListValidationFailed, Violations: violations}
+	}
+
+	existing, err := uc.listRepo.FindByName(ctx, list.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrListRetrievalFailed, err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w: %s", ErrListAlreadyExists, list.Name)
+	}
+
+	list.CreatedBy = actor
+	list.CreatedAt = time.Now().UTC()
+
+	if err := uc.listRepo.Create(ctx, list); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrListPersistenceFailed, err)
+	}
+
+	return list, nil
+}
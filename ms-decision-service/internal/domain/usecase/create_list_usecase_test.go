@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+)
+
+// mockListRepository is a test double for repository.ListRepository.
+type mockListRepository struct {
+	findAllFunc      func(ctx context.Context) ([]entity.ManagedList, error)
+	findByNameFunc   func(ctx context.Context, name string) (*entity.ManagedList, error)
+	findEntriesFunc  func(ctx context.Context, name string) ([]entity.ListEntry, error)
+	createFunc       func(ctx context.Context, list *entity.ManagedList) error
+	putEntriesFunc   func(ctx context.Context, entries []entity.ListEntry) error
+	loadSnapshotFunc func(ctx context.Context) (*entity.ListSnapshot, error)
+	created          *entity.ManagedList
+	deleted          string
+	putEntries       []entity.ListEntry
+	deletedEntry     string
+}
+
+func (m *mockListRepository) FindAll(ctx context.Context) ([]entity.ManagedList, error) {
+	if m.findAllFunc != nil {
+		return m.findAllFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockListRepository) FindByName(ctx context.Context, name string) (*entity.ManagedList, error) {
+	if m.findByNameFunc != nil {
+		return m.findByNameFunc(ctx, name)
+	}
+	return nil, nil
+}
+
+func (m *mockListRepository) FindEntries(ctx context.Context, name string) ([]entity.ListEntry, error) {
+	if m.findEntriesFunc != nil {
+		return m.findEntriesFunc(ctx, name)
+	}
+	return nil, nil
+}
+
+func (m *mockListRepository) Create(ctx context.Context, list *entity.ManagedList) error {
+	m.created = list
+	if m.createFunc != nil {
+		return m.createFunc(ctx, list)
+	}
+	return nil
+}
+
+func (m *mockListRepository) Delete(_ context.Context, name string) error {
+	m.deleted = name
+	return nil
+}
+
+func (m *mockListRepository) PutEntries(ctx context.Context, entries []entity.ListEntry) error {
+	m.putEntries = entries
+	if m.putEntriesFunc != nil {
+		return m.putEntriesFunc(ctx, entries)
+	}
+	return nil
+}
+
+func (m *mockListRepository) DeleteEntry(_ context.Context, _ string, value string) error {
+	m.deletedEntry = value
+	return nil
+}
+
+func (m *mockListRepository) LoadSnapshot(ctx context.Context) (*entity.ListSnapshot, error) {
+	if m.loadSnapshotFunc != nil {
+		return m.loadSnapshotFunc(ctx)
+	}
+	return entity.NewListSnapshot(nil, nil), nil
+}
+
+func newEmailBlocklist() *entity.ManagedList {
+	return &entity.ManagedList{
+		Name:  "blocked-emails",
+		Type:  entity.ListBlocklist,
+		Field: entity.FieldCustomerEmail,
+	}
+}
+
+func findEmailBlocklist(_ context.Context, name string) (*entity.ManagedList, error) {
+	if name != "blocked-emails" {
+		return nil, nil
+	}
+	return newEmailBlocklist(), nil
+}
+
+func TestCreateListUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name     string
+		list     *entity.ManagedList
+		listRepo *mockListRepository
+		wantErr  error
+	}{
+		{
+			name:     "nil list returns ErrListNil",
+			list:     nil,
+			listRepo: &mockListRepository{},
+			wantErr:  ErrListNil,
+		},
+		{
+			name: "invalid list returns ErrListValidationFailed",
+			list: &entity.ManagedList{
+				Name:  "Blocked Emails",
+				Type:  entity.ListBlocklist,
+				Field: entity.FieldCreatedAt,
+			},
+			listRepo: &mockListRepository{},
+			wantErr:  ErrListValidationFailed,
+		},
+		{
+			name:     "existing name returns ErrListAlreadyExists",
+			list:     newEmailBlocklist(),
+			listRepo: &mockListRepository{findByNameFunc: findEmailBlocklist},
+			wantErr:  ErrListAlreadyExists,
+		},
+		{
+			name: "repository write failure returns ErrListPersistenceFailed",
+			list: newEmailBlocklist(),
+			listRepo: &mockListRepository{
+				createFunc: func(_ context.Context, _ *entity.ManagedList) error {
+					return errors.New("dynamo timeout")
+				},
+			},
+			wantErr: ErrListPersistenceFailed,
+		},
+		{
+			name:     "valid list is stored with audit fields",
+			list:     newEmailBlocklist(),
+			listRepo: &mockListRepository{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := NewCreateListUseCase(tc.listRepo)
+			created, err := uc.Execute(context.Background(), tc.list, "analyst@example.com")
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tc.listRepo.created != created {
+				t.Error("expected the list to be passed to the repository")
+			}
+			if created.CreatedBy != "analyst@example.com" || created.CreatedAt.IsZero() {
+				t.Errorf("expected audit fields to be set, got %q at %v", created.CreatedBy, created.CreatedAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// CreateRuleUseCase validates and stores a new fraud detection rule.
+type CreateRuleUseCase struct {
+	ruleRepo repository.RuleRepository
+	listRepo repository.ListRepository
+	recorder *ruleChangeRecorder
+}
+
+// NewCreateRuleUseCase creates a new use case with the given repositories. listRepo is
+// optional: without it the lists named by list conditions are not checked.
+func NewCreateRuleUseCase(
+	ruleRepo repository.RuleRepository,
+	historyRepo repository.RuleHistoryRepository,
+	listRepo repository.ListRepository,
+) *CreateRuleUseCase {
+	return &CreateRuleUseCase{
+		ruleRepo: ruleRepo,
+		listRepo: listRepo,
+		recorder: &ruleChangeRecorder{ruleRepo: ruleRepo, historyRepo: historyRepo},
+	}
+}
+
+// Execute validates the rule and the lists it references, assigns an ID when none
+// is given, enforces a unique priority and persists the rule as its first version,
+// attributed to actor.
+func (uc *CreateRuleUseCase) Execute(
+	ctx context.Context,
+	rule *entity.Rule,
+	actor string,
+) (*entity.Rule, error) {
+	if rule == nil {
+		return nil, ErrRuleNil
+	}
+
+	if err := validateRule(rule); err != nil {
+		return nil, err
+	}
+	if err := validateRuleLists(ctx, uc.listRepo, rule); err != nil {
+		return nil, err
+	}
+
+	if rule.RuleID == "" {
+		rule.RuleID = "rule-" + uuid.New().String()
+	} else {
+		existing, err := uc.ruleRepo.FindByID(ctx, rule.RuleID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRuleRetrievalFailed, err)
+		}
+		if existing != nil {
+			return nil, fmt.Errorf("%w: %s", ErrRuleAlreadyExists, rule.RuleID)
+		}
+	}
+
+	if err := ensurePriorityAvailable(ctx, uc.ruleRepo, rule); err != nil {
+		return nil, err
+	}
+
+	change := ruleChange{after: rule, changeType: entity.RuleCreated, actor: actor}
+	if _, err := uc.recorder.apply(ctx, change); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
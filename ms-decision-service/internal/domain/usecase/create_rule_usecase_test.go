@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"strings"
+	"testing"
+)
+
+func newRuleDefinition() *entity.Rule {
+	return &entity.Rule{
+		RuleName:          "Block CRYPTO payments",
+		ConditionField:    entity.FieldPaymentMethod,
+		ConditionOperator: entity.OpEqual,
+		ConditionValue:    "CRYPTO",
+		ResultStatus:      entity.DECLINED,
+		Priority:          1,
+		IsActive:          true,
+	}
+}
+
+func existingRules(_ context.Context) ([]entity.Rule, error) {
+	return []entity.Rule{
+		{RuleID: "rule-001", RuleName: "Existing", Priority: 1},
+		{RuleID: "rule-002", RuleName: "Other", Priority: 2},
+	}, nil
+}
+
+func TestCreateRuleUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        *entity.Rule
+		ruleRepo    *mockRuleRepository
+		wantErr     error
+		wantCreated bool
+	}{
+		{
+			name:     "nil rule returns ErrRuleNil",
+			rule:     nil,
+			ruleRepo: &mockRuleRepository{},
+			wantErr:  ErrRuleNil,
+		},
+		{
+			name: "invalid rule returns ErrRuleValidationFailed",
+			rule: func() *entity.Rule {
+				r := newRuleDefinition()
+				r.ConditionField = entity.FieldCurrency
+				r.ConditionOperator = entity.OpGreaterThan
+				return r
+			}(),
+			ruleRepo: &mockRuleRepository{},
+			wantErr:  ErrRuleValidationFailed,
+		},
+		{
+			name: "existing rule ID returns ErrRuleAlreadyExists",
+			rule: func() *entity.Rule {
+				r := newRuleDefinition()
+				r.RuleID = "rule-001"
+				return r
+			}(),
+			ruleRepo: &mockRuleRepository{
+				findByIDFunc: func(_ context.Context, id string) (*entity.Rule, error) {
+					return &entity.Rule{RuleID: id}, nil
+				},
+			},
+			wantErr: ErrRuleAlreadyExists,
+		},
+		{
+			name:     "priority in use returns ErrDuplicatePriority",
+			rule:     newRuleDefinition(),
+			ruleRepo: &mockRuleRepository{findAllFunc: existingRules},
+			wantErr:  ErrDuplicatePriority,
+		},
+		{
+			name: "repository write failure returns ErrRulePersistenceFailed",
+			rule: newRuleDefinition(),
+			ruleRepo: &mockRuleRepository{
+				applyFunc: func(_ context.Context, _ *entity.RuleChangeSet) error {
+					return errors.New("dynamo timeout")
+				},
+			},
+			wantErr: ErrRulePersistenceFailed,
+		},
+		{
+			name: "valid rule with free priority is created",
+			rule: func() *entity.Rule {
+				r := newRuleDefinition()
+				r.Priority = 3
+				return r
+			}(),
+			ruleRepo:    &mockRuleRepository{findAllFunc: existingRules},
+			wantCreated: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := NewCreateRuleUseCase(tc.ruleRepo, &mockRuleHistoryRepository{}, nil)
+			rule, err := uc.Execute(context.Background(), tc.rule, "analyst")
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected error wrapping %v, got %v", tc.wantErr, err)
+				}
+				if tc.ruleRepo.created != nil && !errors.Is(tc.wantErr, ErrRulePersistenceFailed) {
+					t.Error("expected rule not to be written")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.wantCreated && (tc.ruleRepo.created == nil || tc.ruleRepo.created.RuleID != rule.RuleID) {
+				t.Error("expected rule to be written to the repository")
+			}
+			if !strings.HasPrefix(rule.RuleID, "rule-") {
+				t.Errorf("expected generated rule ID, got %q", rule.RuleID)
+			}
+		})
+	}
+}
+
+func TestCreateRuleUseCase_ValidationErrorListsViolations(t *testing.T) {
+	uc := NewCreateRuleUseCase(&mockRuleRepository{}, &mockRuleHistoryRepository{}, nil)
+
+	_, err := uc.Execute(context.Background(), &entity.Rule{ConditionField: entity.FieldCurrency, ConditionOperator: entity.OpGreaterThan}, "analyst")
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	if len(validationErr.Violations) < 3 {
+		t.Errorf("expected several violations, got %+v", validationErr.Violations)
+	}
+}
+
+func TestCreateRuleUseCase_ValidatesListReferences(t *testing.T) {
+	listRepo := &mockListRepository{
+		findAllFunc: func(_ context.Context) ([]entity.ManagedList, error) {
+			return []entity.ManagedList{{Name: "blocked-emails", Type: entity.ListBlocklist, Field: entity.FieldCustomerEmail}}, nil
+		},
+	}
+	listRule := func(field entity.ConditionField, list string) *entity.Rule {
+		rule := newRuleDefinition()
+		rule.ConditionField, rule.ConditionOperator, rule.ConditionValue = field, entity.OpInList, list
+		return rule
+	}
+
+	t.Run("accepts an existing list of the condition field", func(t *testing.T) {
+		uc := NewCreateRuleUseCase(&mockRuleRepository{}, &mockRuleHistoryRepository{}, listRepo)
+
+		if _, err := uc.Execute(context.Background(), listRule(entity.FieldCustomerEmail, "blocked-emails"), "analyst"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	for _, tt := range []struct {
+		name  string
+		field entity.ConditionField
+		list  string
+	}{
+		{"rejects an unknown list", entity.FieldCustomerEmail, "missing"},
+		{"rejects a list of another field", entity.FieldCustomerIPAddress, "blocked-emails"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ruleRepo := &mockRuleRepository{}
+			uc := NewCreateRuleUseCase(ruleRepo, &mockRuleHistoryRepository{}, listRepo)
+
+			_, err := uc.Execute(context.Background(), listRule(tt.field, tt.list), "analyst")
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Violations[0].Field != "condition_value" {
+				t.Fatalf("expected a condition_value violation, got %v", err)
+			}
+			if ruleRepo.created != nil {
+				t.Error("expected nothing to be stored")
+			}
+		})
+	}
+
+	t.Run("list read failure returns ErrListRetrievalFailed", func(t *testing.T) {
+		failing := &mockListRepository{
+			findAllFunc: func(_ context.Context) ([]entity.ManagedList, error) {
+				return nil, errors.New("dynamo timeout")
+			},
+		}
+		uc := NewCreateRuleUseCase(&mockRuleRepository{}, &mockRuleHistoryRepository{}, failing)
+
+		if _, err := uc.Execute(context.Background(), listRule(entity.FieldCustomerEmail, "blocked-emails"), "analyst"); !errors.Is(err, ErrListRetrievalFailed) {
+			t.Fatalf("expected ErrListRetrievalFailed, got %v", err)
+		}
+	})
+}
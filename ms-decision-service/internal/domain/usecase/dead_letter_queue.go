@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+)
+
+// findDeadLetterQueue returns the configured dead-letter queue of the topic.
+func findDeadLetterQueue(queues []entity.DeadLetterQueue, topic string) (entity.DeadLetterQueue, error) {
+	for _, queue := range queues {
+		if queue.Topic == topic {
+			return queue, nil
+		}
+	}
+	return entity.DeadLetterQueue{}, fmt.Errorf("%w: %s", ErrDeadLetterQueueNotFound, topic)
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// loadDecisionPolicy returns the stored policy of the rule set, or the default
+// first-match policy when none is stored or no repository is configured.
+func loadDecisionPolicy(
+	ctx context.Context,
+	policyRepo repository.DecisionPolicyRepository,
+	ruleSet entity.RuleSet,
+) (entity.DecisionPolicy, error) {
+	if policyRepo == nil {
+		return entity.DefaultDecisionPolicy(ruleSet), nil
+	}
+
+	policy, err := policyRepo.FindByRuleSet(ctx, ruleSet)
+	if err != nil {
+		return entity.DecisionPolicy{}, fmt.Errorf("%w: %w", ErrDecisionPolicyRetrievalFailed, err)
+	}
+	if policy == nil {
+		return entity.DefaultDecisionPolicy(ruleSet), nil
+	}
+
+	return *policy, nil
+}
+
+// scoreOf returns the total score of a SCORE mode evaluation, or nil.
+func scoreOf(evaluation *entity.Evaluation) *int {
+	if evaluation.Mode != entity.ModeScore {
+		return nil
+	}
+	score := evaluation.Score
+	return &score
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/repository"
+	"strings"
+)
+
+// DeleteListUseCase removes a managed list and its entries.
+type DeleteListUseCase struct {
+	listRepo repository.ListRepository
+	ruleRepo repository.RuleRepository
+}
+
+// NewDeleteListUseCase creates a new use case with the given repositories.
+func NewDeleteListUseCase(
+	listRepo repository.ListRepository,
+	ruleRepo repository.RuleRepository,
+) *DeleteListUseCase {
+	return &DeleteListUseCase{
+		listRepo: listRepo,
+		ruleRepo: ruleRepo,
+	}
+}
+
+// Execute deletes the list identified by name. The list must exist and must not
+// be referenced by an active rule, LIVE or SHADOW; inactive rules that reference
+// it stop matching.
+func (uc *DeleteListUseCase) Execute(
+	ctx context.Context,
+	name string,
+) error {
+	if _, err := findExistingList(ctx, uc.listRepo, name); err != nil {
+		return err
+	}
+
+	rules, err := uc.ruleRepo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRuleRetrievalFailed, err)
+	}
+
+	var referencing []string
+	for i := range rules {
+		if rules[i].IsActive && rules[i].ReferencesList(name) {
+			referencing = append(referencing, rules[i].RuleID)
+		}
+	}
+	if len(referencing) > 0 {
+		return fmt.Errorf("%w: %s", ErrListInUse, strings.Join(referencing, ", "))
+	}
+
+	if err := uc.listRepo.Delete(ctx, name); err != nil {
+		return fmt.Errorf("%w: %w", ErrListPersistenceFailed, err)
+	}
+
+	return nil
+}
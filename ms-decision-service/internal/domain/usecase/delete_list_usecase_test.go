@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+)
+
+func TestDeleteListUseCase_Execute(t *testing.T) {
+	blocklistRule := func(active bool) entity.Rule {
+		return entity.Rule{
+			RuleID: "rule-blocklist",
+			Condition: &entity.ConditionNode{Logic: entity.LogicNot, Children: []entity.ConditionNode{
+				{Field: entity.FieldCustomerEmail, Operator: entity.OpInList, Value: "blocked-emails"},
+			}},
+			IsActive: active,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		rules       []entity.Rule
+		rulesErr    error
+		wantErr     error
+		wantDeleted bool
+	}{
+		{name: "unreferenced list is deleted", rules: []entity.Rule{*newRuleDefinition()}, wantDeleted: true},
+		{name: "list referenced only by inactive rules is deleted", rules: []entity.Rule{blocklistRule(false)}, wantDeleted: true},
+		{name: "list referenced by an active rule returns ErrListInUse", rules: []entity.Rule{blocklistRule(true)}, wantErr: ErrListInUse},
+		{name: "rule read failure returns ErrRuleRetrievalFailed", rulesErr: errors.New("dynamo timeout"), wantErr: ErrRuleRetrievalFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listRepo := &mockListRepository{findByNameFunc: findEmailBlocklist}
+			ruleRepo := &mockRuleRepository{
+				findAllFunc: func(_ context.Context) ([]entity.Rule, error) {
+					return tt.rules, tt.rulesErr
+				},
+			}
+
+			err := NewDeleteListUseCase(listRepo, ruleRepo).Execute(context.Background(), "blocked-emails")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if deleted := listRepo.deleted == "blocked-emails"; deleted != tt.wantDeleted {
+				t.Errorf("expected deleted=%v, got %v", tt.wantDeleted, deleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// DeleteRuleUseCase removes a fraud detection rule.
+type DeleteRuleUseCase struct {
+	ruleRepo repository.RuleRepository
+	recorder *ruleChangeRecorder
+}
+
+// NewDeleteRuleUseCase creates a new use case with the given repositories.
+func NewDeleteRuleUseCase(
+	ruleRepo repository.RuleRepository,
+	historyRepo repository.RuleHistoryRepository,
+) *DeleteRuleUseCase {
+	return &DeleteRuleUseCase{
+		ruleRepo: ruleRepo,
+		recorder: &ruleChangeRecorder{ruleRepo: ruleRepo, historyRepo: historyRepo},
+	}
+}
+
+// Execute deletes the rule identified by ruleID. The rule must exist.
+// The deleted definition stays in the rule history and can be restored by a rollback.
+func (uc *DeleteRuleUseCase) Execute(
+	ctx context.Context,
+	ruleID string,
+	actor string,
+) error {
+	rule, err := findExistingRule(ctx, uc.ruleRepo, ruleID)
+	if err != nil {
+		return err
+	}
+
+	change := ruleChange{before: rule, changeType: entity.RuleDeleted, actor: actor}
+	if _, err := uc.recorder.apply(ctx, change); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+)
+
+func TestDeleteRuleUseCase_Execute(t *testing.T) {
+	found := func(_ context.Context, id string) (*entity.Rule, error) {
+		return &entity.Rule{RuleID: id}, nil
+	}
+
+	tests := []struct {
+		name     string
+		ruleID   string
+		ruleRepo *mockRuleRepository
+		wantErr  error
+	}{
+		{
+			name:     "empty rule ID returns ErrRuleIDEmpty",
+			ruleRepo: &mockRuleRepository{},
+			wantErr:  ErrRuleIDEmpty,
+		},
+		{
+			name:     "missing rule returns ErrRuleNotFound",
+			ruleID:   "rule-404",
+			ruleRepo: &mockRuleRepository{},
+			wantErr:  ErrRuleNotFound,
+		},
+		{
+			name:   "lookup failure returns ErrRuleRetrievalFailed",
+			ruleID: "rule-001",
+			ruleRepo: &mockRuleRepository{
+				findByIDFunc: func(_ context.Context, _ string) (*entity.Rule, error) {
+					return nil, errors.New("dynamo timeout")
+				},
+			},
+			wantErr: ErrRuleRetrievalFailed,
+		},
+		{
+			name:   "delete failure returns ErrRulePersistenceFailed",
+			ruleID: "rule-001",
+			ruleRepo: &mockRuleRepository{
+				findByIDFunc: found,
+				applyFunc: func(_ context.Context, _ *entity.RuleChangeSet) error {
+					return errors.New("dynamo timeout")
+				},
+			},
+			wantErr: ErrRulePersistenceFailed,
+		},
+		{
+			name:     "existing rule is deleted",
+			ruleID:   "rule-001",
+			ruleRepo: &mockRuleRepository{findByIDFunc: found},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := NewDeleteRuleUseCase(tc.ruleRepo, &mockRuleHistoryRepository{})
+			err := uc.Execute(context.Background(), tc.ruleID, "analyst")
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected error wrapping %v, got %v", tc.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.ruleRepo.deletedID != tc.ruleID {
+				t.Errorf("expected rule %q to be deleted, got %q", tc.ruleID, tc.ruleRepo.deletedID)
+			}
+		})
+	}
+}
@@ -1,13 +1,81 @@
 package usecase
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"strings"
+)
 
 var (
-	ErrRuleRetrievalFailed        = errors.New("failed to retrieve rules")
-	ErrDecisionPublishFailed      = errors.New("failed to publish decision result")
-	ErrFraudScorePublishFailed    = errors.New("failed to publish fraud score request")
-	ErrTransactionNil             = errors.New("transaction is nil")
-	ErrFraudScoreMessageNil       = errors.New("fraud score message is nil")
-	ErrTransactionIDEmpty         = errors.New("transaction ID is empty")
-	ErrEvaluationRetrievalFailed  = errors.New("failed to retrieve rule evaluations")
+	ErrRuleRetrievalFailed             = errors.New("failed to retrieve rules")
+	ErrDecisionPublishFailed           = errors.New("failed to publish decision result")
+	ErrFraudScorePublishFailed         = errors.New("failed to publish fraud score request")
+	ErrTransactionNil                  = errors.New("transaction is nil")
+	ErrFraudScoreMessageNil            = errors.New("fraud score message is nil")
+	ErrTransactionIDEmpty              = errors.New("transaction ID is empty")
+	ErrEvaluationRetrievalFailed       = errors.New("failed to retrieve rule evaluations")
+	ErrRuleNil                         = errors.New("rule is nil")
+	ErrRuleIDEmpty                     = errors.New("rule ID is empty")
+	ErrRuleNotFound                    = errors.New("rule not found")
+	ErrRuleAlreadyExists               = errors.New("rule already exists")
+	ErrRuleValidationFailed            = errors.New("rule validation failed")
+	ErrDuplicatePriority               = errors.New("rule priority already in use")
+	ErrRulePersistenceFailed           = errors.New("failed to persist rule")
+	ErrRuleConflict                    = errors.New("rule was changed concurrently")
+	ErrRuleHistoryRetrievalFailed      = errors.New("failed to retrieve rule history")
+	ErrRuleVersionNotFound             = errors.New("rule version not found")
+	ErrRulesetVersionNotFound          = errors.New("ruleset version not found")
+	ErrRuleCacheRefreshFailed          = errors.New("failed to refresh rule cache")
+	ErrListNil                         = errors.New("list is nil")
+	ErrListNameEmpty                   = errors.New("list name is empty")
+	ErrListNotFound                    = errors.New("list not found")
+	ErrListAlreadyExists               = errors.New("list already exists")
+	ErrListValidationFailed            = errors.New("list validation failed")
+	ErrListRetrievalFailed             = errors.New("failed to retrieve lists")
+	ErrListPersistenceFailed           = errors.New("failed to persist list")
+	ErrListEntriesEmpty                = errors.New("no list entries given")
+	ErrListEntryNotFound               = errors.New("list entry not found")
+	ErrListInUse                       = errors.New("list is referenced by active rules")
+	ErrDecisionPolicyNil               = errors.New("decision policy is nil")
+	ErrDecisionPolicyValidationFailed  = errors.New("decision policy validation failed")
+	ErrDecisionPolicyRetrievalFailed   = errors.New("failed to retrieve decision policy")
+	ErrDecisionPolicyPersistenceFailed = errors.New("failed to persist decision policy")
+	ErrTransactionRetrievalFailed      = errors.New("failed to retrieve historical transactions")
+	ErrBacktestLimitExceeded           = errors.New("too many transactions to backtest")
+	ErrDeadLetterQueueNotFound         = errors.New("dead-letter topic not found")
+	ErrDeadLetterNotFound              = errors.New("dead letter not found")
+	ErrDeadLetterRetrievalFailed       = errors.New("failed to read dead-letter topic")
+	ErrDeadLetterLimitInvalid          = errors.New("invalid dead letter limit")
+	ErrRedriveSelectionInvalid         = errors.New("either ids or all must be given")
+	ErrRedriveRateInvalid              = errors.New("invalid redrive rate")
 )
+
+// IsRetryable reports whether err is a transient failure of a dependency, such as
+// DynamoDB or Kafka being unavailable, so the operation may succeed if retried.
+// Invalid input and validation errors are not retryable.
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrRuleRetrievalFailed) ||
+		errors.Is(err, ErrDecisionPolicyRetrievalFailed) ||
+		errors.Is(err, ErrDecisionPublishFailed) ||
+		errors.Is(err, ErrFraudScorePublishFailed)
+}
+
+// ValidationError carries every violation found in a rule, list or decision policy.
+// It unwraps to Err, the validation sentinel of what was validated.
+type ValidationError struct {
+	Err        error
+	Violations []entity.RuleViolation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Field + ": " + v.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Err, strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
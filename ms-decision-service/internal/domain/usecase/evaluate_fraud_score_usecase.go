@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"ms-decision-service/internal/domain/entity"
 	"ms-decision-service/internal/domain/repository"
-	"strconv"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -16,6 +15,9 @@ type EvaluateFraudScoreUseCase struct {
 	ruleRepo          repository.RuleRepository
 	decisionPublisher repository.DecisionPublisher
 	ruleEvalRepo      repository.RuleEvaluationRepository
+	rulesetVersions   *rulesetVersions
+	policyRepo        repository.DecisionPolicyRepository
+	processed         processedMessages
 	logger            zerolog.Logger
 }
 
@@ -24,18 +26,32 @@ func NewEvaluateFraudScoreUseCase(
 	ruleRepo repository.RuleRepository,
 	decisionPublisher repository.DecisionPublisher,
 	ruleEvalRepo repository.RuleEvaluationRepository,
+	ruleHistoryRepo repository.RuleHistoryRepository,
+	policyRepo repository.DecisionPolicyRepository,
+	processedStore repository.ProcessedMessageStore,
 	logger zerolog.Logger,
 ) *EvaluateFraudScoreUseCase {
 	return &EvaluateFraudScoreUseCase{
 		ruleRepo:          ruleRepo,
 		decisionPublisher: decisionPublisher,
 		ruleEvalRepo:      ruleEvalRepo,
+		rulesetVersions:   &rulesetVersions{repo: ruleHistoryRepo, logger: logger},
+		policyRepo:        policyRepo,
+		processed:         processedMessages{store: processedStore, logger: logger},
 		logger:            logger,
 	}
 }
 
 // Execute evaluates the fraud score against fraud-score rules and publishes the final decision.
-// If no fraud-score rule matches, it defaults to APPROVED (fail-open).
+// If no fraud-score rule matches, it defaults to APPROVED (fail-open). The rules are
+// evaluated with the FRAUD_SCORE rule set's decision policy; SHADOW rules are recorded
+// but never change the decision.
+// The decision and every evaluation record are stamped with the ruleset version in effect.
+// A fraud score that was already processed for the transaction returns the recorded
+// decision without evaluating the rules, and publishes it again: the fraud score
+// request of a transaction the Transaction Evaluator republished carries no
+// republish attempt, so a duplicate cannot be told from one whose decision was
+// lost. The Transaction Evaluator ignores a decision it already applied.
 func (uc *EvaluateFraudScoreUseCase) Execute(
 	ctx context.Context,
 	msg *entity.FraudScoreCalculatedMessage,
@@ -44,65 +60,70 @@ func (uc *EvaluateFraudScoreUseCase) Execute(
 		return nil, ErrFraudScoreMessageNil
 	}
 
-	rules, err := uc.ruleRepo.FindActiveRulesSortedByPriority(ctx)
+	if result := uc.processed.find(ctx, msg.TransactionID, entity.StageFraudScoreCalculated); result != nil {
+		if err := uc.decisionPublisher.Publish(ctx, result); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDecisionPublishFailed, err)
+		}
+		return result, nil
+	}
+
+	rules, rulesetVersion, err := uc.rulesetVersions.activeRules(ctx, uc.ruleRepo)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrRuleRetrievalFailed, err)
 	}
 
-	status := evaluateFraudScoreRules(msg.FraudScore, rules)
+	policy, err := loadDecisionPolicy(ctx, uc.policyRepo, entity.RuleSetFraudScore)
+	if err != nil {
+		return nil, err
+	}
+
+	fraudScoreRules := filterFraudScoreRules(rules)
+	evaluation := policy.Evaluate(msg, fraudScoreRules)
 
 	// Persist fraud-score rule evaluation results (non-fatal — log error but do not block)
-	uc.persistFraudScoreRuleEvaluations(ctx, msg, rules)
+	uc.persistFraudScoreRuleEvaluations(ctx, msg, fraudScoreRules, rulesetVersion, &policy, &evaluation)
 
 	result := &entity.DecisionResult{
-		TransactionID: msg.TransactionID,
-		Status:        status,
+		TransactionID:  msg.TransactionID,
+		Status:         evaluation.Status,
+		RulesetVersion: rulesetVersion,
+		Score:          scoreOf(&evaluation),
+		DecisionPath:   entity.DecisionPathFraudCheck,
 	}
 
 	if err := uc.decisionPublisher.Publish(ctx, result); err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrDecisionPublishFailed, err)
 	}
+	uc.processed.record(ctx, entity.StageFraudScoreCalculated, result)
 
 	return result, nil
 }
 
 // persistFraudScoreRuleEvaluations builds RuleEvaluationResult records for each fraud-score
-// rule evaluated and persists them via SaveBatch. Errors are logged but do not block the flow.
+// rule evaluated and persists them via SaveBatch. SHADOW rules also record the decision
+// they would have produced. Errors are logged but do not block the flow.
 func (uc *EvaluateFraudScoreUseCase) persistFraudScoreRuleEvaluations(
 	ctx context.Context,
 	msg *entity.FraudScoreCalculatedMessage,
-	rules []entity.Rule,
+	fraudScoreRules []entity.Rule,
+	rulesetVersion int,
+	policy *entity.DecisionPolicy,
+	evaluation *entity.Evaluation,
 ) {
-	// Only persist evaluations for fraud_score rules
-	fraudScoreRules := filterFraudScoreRules(rules)
 	if len(fraudScoreRules) == 0 {
 		return
 	}
 
 	now := time.Now()
-	scoreStr := strconv.Itoa(msg.FraudScore)
 	results := make([]entity.RuleEvaluationResult, 0, len(fraudScoreRules))
 
-	for _, rule := range fraudScoreRules {
-		matched := rule.ConditionOperator.Compare(
-			scoreStr,
-			rule.ConditionValue,
-			entity.FieldFraudScore,
-		)
-
-		results = append(results, entity.RuleEvaluationResult{
-			TransactionID:     msg.TransactionID,
-			RuleID:            rule.RuleID,
-			RuleName:          rule.RuleName,
-			ConditionField:    string(rule.ConditionField),
-			ConditionOperator: string(rule.ConditionOperator),
-			ConditionValue:    rule.ConditionValue,
-			ActualFieldValue:  scoreStr,
-			Matched:           matched,
-			ResultStatus:      string(rule.ResultStatus),
-			EvaluatedAt:       now,
-			Priority:          rule.Priority,
-		})
+	for i := range fraudScoreRules {
+		result := entity.NewRuleEvaluationResult(msg.TransactionID, &fraudScoreRules[i], msg, rulesetVersion, now)
+		result.RecordScore(evaluation)
+		if fraudScoreRules[i].IsShadow() {
+			result.RecordShadowOutcome(policy.ShadowStatus(msg, fraudScoreRules, fraudScoreRules[i].RuleID), evaluation.Status)
+		}
+		results = append(results, result)
 	}
 
 	if err := uc.ruleEvalRepo.SaveBatch(ctx, results); err != nil {
@@ -113,28 +134,13 @@ func (uc *EvaluateFraudScoreUseCase) persistFraudScoreRuleEvaluations(
 	}
 }
 
-// filterFraudScoreRules returns only rules with ConditionField == FieldFraudScore.
+// filterFraudScoreRules returns only rules whose condition references FieldFraudScore.
 func filterFraudScoreRules(rules []entity.Rule) []entity.Rule {
 	var filtered []entity.Rule
 	for _, r := range rules {
-		if r.ConditionField == entity.FieldFraudScore {
+		if r.ReferencesField(entity.FieldFraudScore) {
 			filtered = append(filtered, r)
 		}
 	}
 	return filtered
 }
-
-// evaluateFraudScoreRules evaluates the fraud score against rules where ConditionField is fraud_score.
-// Returns the ResultStatus of the first matching rule. If no rule matches, returns APPROVED (fail-open).
-func evaluateFraudScoreRules(fraudScore int, rules []entity.Rule) entity.DecisionStatus {
-	scoreStr := strconv.Itoa(fraudScore)
-	for _, rule := range rules {
-		if rule.ConditionField != entity.FieldFraudScore {
-			continue
-		}
-		if rule.ConditionOperator.Compare(scoreStr, rule.ConditionValue, entity.FieldFraudScore) {
-			return rule.ResultStatus
-		}
-	}
-	return entity.APPROVED
-}
@@ -71,7 +71,7 @@ func TestProperty_FraudScoreRulesNeverProduceFraudCheck(t *testing.T) {
 		}
 		decisionPub := &mockDecisionPublisher{}
 
-		uc := NewEvaluateFraudScoreUseCase(ruleRepo, decisionPub, &mockRuleEvaluationRepository{}, zerolog.Nop())
+		uc := NewEvaluateFraudScoreUseCase(ruleRepo, decisionPub, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, nil, nil, zerolog.Nop())
 		result, err := uc.Execute(context.Background(), msg)
 
 		if err != nil {
@@ -119,7 +119,7 @@ func TestProperty_FraudScoreEvaluationProducesDecisionAndPublishes(t *testing.T)
 		}
 		decisionPub := &mockDecisionPublisher{}
 
-		uc := NewEvaluateFraudScoreUseCase(ruleRepo, decisionPub, &mockRuleEvaluationRepository{}, zerolog.Nop())
+		uc := NewEvaluateFraudScoreUseCase(ruleRepo, decisionPub, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, nil, nil, zerolog.Nop())
 		result, err := uc.Execute(context.Background(), msg)
 
 		// Assert no error returned
@@ -147,6 +147,9 @@ func TestProperty_FraudScoreEvaluationProducesDecisionAndPublishes(t *testing.T)
 		if decisionPub.lastResult.Status != entity.APPROVED && decisionPub.lastResult.Status != entity.DECLINED {
 			t.Fatalf("expected published status to be APPROVED or DECLINED, got %q", decisionPub.lastResult.Status)
 		}
+		if decisionPub.lastResult.DecisionPath != entity.DecisionPathFraudCheck {
+			t.Fatalf("expected published decision path %q, got %q", entity.DecisionPathFraudCheck, decisionPub.lastResult.DecisionPath)
+		}
 
 		// Assert result matches what was published
 		if result.TransactionID != msg.TransactionID {
@@ -209,7 +212,7 @@ func TestEvaluateFraudScoreUseCase_RuleEvaluationPersistence(t *testing.T) {
 		}
 		ruleEvalRepo := &mockRuleEvaluationRepository{}
 
-		uc := NewEvaluateFraudScoreUseCase(ruleRepo, &mockDecisionPublisher{}, ruleEvalRepo, zerolog.Nop())
+		uc := NewEvaluateFraudScoreUseCase(ruleRepo, &mockDecisionPublisher{}, ruleEvalRepo, &mockRuleHistoryRepository{}, nil, nil, zerolog.Nop())
 		_, err := uc.Execute(context.Background(), msg)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -261,7 +264,7 @@ func TestEvaluateFraudScoreUseCase_RuleEvaluationPersistence(t *testing.T) {
 		}
 		ruleEvalRepo := &mockRuleEvaluationRepository{}
 
-		uc := NewEvaluateFraudScoreUseCase(ruleRepo, &mockDecisionPublisher{}, ruleEvalRepo, zerolog.Nop())
+		uc := NewEvaluateFraudScoreUseCase(ruleRepo, &mockDecisionPublisher{}, ruleEvalRepo, &mockRuleHistoryRepository{}, nil, nil, zerolog.Nop())
 		_, err := uc.Execute(context.Background(), msg)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -339,7 +342,7 @@ func TestEvaluateFraudScoreUseCase_RuleEvaluationPersistence(t *testing.T) {
 			},
 		}
 
-		uc := NewEvaluateFraudScoreUseCase(ruleRepo, decisionPub, ruleEvalRepo, zerolog.Nop())
+		uc := NewEvaluateFraudScoreUseCase(ruleRepo, decisionPub, ruleEvalRepo, &mockRuleHistoryRepository{}, nil, nil, zerolog.Nop())
 		result, err := uc.Execute(context.Background(), msg)
 
 		if err != nil {
@@ -358,6 +361,52 @@ func TestEvaluateFraudScoreUseCase_RuleEvaluationPersistence(t *testing.T) {
 			t.Error("expected SaveBatch to have been attempted")
 		}
 	})
+
+	t.Run("compound rule over a fraud score range is evaluated", func(t *testing.T) {
+		msg := &entity.FraudScoreCalculatedMessage{
+			TransactionID: "tx-persist-4",
+			FraudScore:    60,
+			CalculatedAt:  time.Now(),
+		}
+		rules := []entity.Rule{
+			{
+				RuleID:   "rule-fs-range",
+				RuleName: "Decline score between 50 and 70",
+				Condition: &entity.ConditionNode{
+					Logic: entity.LogicAnd,
+					Children: []entity.ConditionNode{
+						{Field: entity.FieldFraudScore, Operator: entity.OpGreaterThanOrEqual, Value: "50"},
+						{Field: entity.FieldFraudScore, Operator: entity.OpLessThan, Value: "70"},
+					},
+				},
+				ResultStatus: entity.DECLINED,
+				Priority:     1,
+				IsActive:     true,
+			},
+		}
+
+		ruleRepo := &mockRuleRepository{
+			findFunc: func(_ context.Context) ([]entity.Rule, error) {
+				return rules, nil
+			},
+		}
+		ruleEvalRepo := &mockRuleEvaluationRepository{}
+
+		uc := NewEvaluateFraudScoreUseCase(ruleRepo, &mockDecisionPublisher{}, ruleEvalRepo, &mockRuleHistoryRepository{}, nil, nil, zerolog.Nop())
+		result, err := uc.Execute(context.Background(), msg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Status != entity.DECLINED {
+			t.Errorf("expected DECLINED status, got %q", result.Status)
+		}
+		if len(ruleEvalRepo.lastResults) != 1 {
+			t.Fatalf("expected 1 result, got %d", len(ruleEvalRepo.lastResults))
+		}
+		if got := len(ruleEvalRepo.lastResults[0].ConditionResults); got != 2 {
+			t.Errorf("expected 2 condition results, got %d", got)
+		}
+	})
 }
 
 // --- Unit Tests for EvaluateFraudScoreUseCase edge cases ---
@@ -484,7 +533,7 @@ func TestEvaluateFraudScoreUseCase_Execute(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			uc := NewEvaluateFraudScoreUseCase(tc.ruleRepo, tc.publisher, &mockRuleEvaluationRepository{}, zerolog.Nop())
+			uc := NewEvaluateFraudScoreUseCase(tc.ruleRepo, tc.publisher, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, nil, nil, zerolog.Nop())
 			result, err := uc.Execute(context.Background(), tc.msg)
 
 			if tc.wantErr != nil {
@@ -528,3 +577,117 @@ func TestEvaluateFraudScoreUseCase_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateFraudScoreUseCase_DuplicateMessages(t *testing.T) {
+	rules := []entity.Rule{
+		{RuleID: "rule-fs", RuleName: "High fraud score", ConditionField: entity.FieldFraudScore,
+			ConditionOperator: entity.OpGreaterThan, ConditionValue: "70", ResultStatus: entity.DECLINED,
+			Priority: 1, IsActive: true},
+	}
+	ruleRepo := &mockRuleRepository{
+		findFunc: func(_ context.Context) ([]entity.Rule, error) {
+			return rules, nil
+		},
+	}
+	msg := &entity.FraudScoreCalculatedMessage{TransactionID: "tx-1", FraudScore: 90, CalculatedAt: time.Now()}
+
+	store := &mockProcessedMessageStore{}
+	uc := NewEvaluateFraudScoreUseCase(ruleRepo, &mockDecisionPublisher{}, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, nil, store, zerolog.Nop())
+	first, err := uc.Execute(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decisionPub := &mockDecisionPublisher{}
+	uc.decisionPublisher = decisionPub
+	second, err := uc.Execute(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if *second != *first || second.Status != entity.DECLINED {
+		t.Errorf("expected the recorded decision %+v, got %+v", first, second)
+	}
+	// The fraud score of a republished FRAUD_CHECK transaction looks like a
+	// duplicate, so the recorded decision is published again
+	if !decisionPub.called || decisionPub.lastResult == nil || *decisionPub.lastResult != *first {
+		t.Errorf("expected the recorded decision to be published again, got %+v", decisionPub.lastResult)
+	}
+
+	if _, ok := store.results["tx-1#"+string(entity.StageTransactionCreated)]; ok {
+		t.Error("expected the fraud score stage to be recorded separately from the transaction stage")
+	}
+}
+
+func TestEvaluateFraudScoreUseCase_RepublishedFraudCheck(t *testing.T) {
+	rules := []entity.Rule{
+		{RuleID: "rule-001", RuleName: "Review cards", ConditionField: entity.FieldPaymentMethod,
+			ConditionOperator: entity.OpEqual, ConditionValue: "CARD", ResultStatus: entity.FRAUDCHECK,
+			Priority: 1, IsActive: true},
+		{RuleID: "rule-fs", RuleName: "High fraud score", ConditionField: entity.FieldFraudScore,
+			ConditionOperator: entity.OpGreaterThan, ConditionValue: "70", ResultStatus: entity.DECLINED,
+			Priority: 2, IsActive: true},
+	}
+	ruleRepo := &mockRuleRepository{
+		findFunc: func(_ context.Context) ([]entity.Rule, error) {
+			return rules, nil
+		},
+	}
+	store := &mockProcessedMessageStore{}
+	fraudScorePub := &mockFraudScoreRequestPublisher{}
+	transactionUC := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, fraudScorePub, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, &mockVelocityStore{}, nil, nil, store, nil, zerolog.Nop())
+	fraudScoreUC := NewEvaluateFraudScoreUseCase(ruleRepo, &mockDecisionPublisher{}, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, nil, store, zerolog.Nop())
+	score := &entity.FraudScoreCalculatedMessage{TransactionID: newTestTransaction().ID, FraudScore: 90, CalculatedAt: time.Now()}
+
+	if _, err := transactionUC.Execute(context.Background(), newTestTransaction()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first, err := fraudScoreUC.Execute(context.Background(), score)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The decision was lost, so the Transaction Evaluator republishes the transaction
+	// and the Fraud Signals Service scores it again
+	fraudScorePub.called = false
+	republished := newTestTransaction()
+	republished.RepublishAttempt = 1
+	if _, err := transactionUC.Execute(context.Background(), republished); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fraudScorePub.called {
+		t.Fatal("expected the republished transaction to be forwarded for a fraud score again")
+	}
+
+	t.Run("the recorded decision is published again", func(t *testing.T) {
+		decisionPub := &mockDecisionPublisher{}
+		ruleEvalRepo := &mockRuleEvaluationRepository{}
+		fraudScoreUC.decisionPublisher, fraudScoreUC.ruleEvalRepo = decisionPub, ruleEvalRepo
+
+		second, err := fraudScoreUC.Execute(context.Background(), score)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if *second != *first || second.DecisionPath != entity.DecisionPathFraudCheck {
+			t.Errorf("expected the recorded decision %+v, got %+v", first, second)
+		}
+		if decisionPub.lastResult == nil || *decisionPub.lastResult != *first {
+			t.Errorf("expected the recorded decision to be published, got %+v", decisionPub.lastResult)
+		}
+		if ruleEvalRepo.saveCalled {
+			t.Error("expected the rules not to be evaluated again")
+		}
+	})
+
+	t.Run("a failed publish is returned", func(t *testing.T) {
+		fraudScoreUC.decisionPublisher = &mockDecisionPublisher{
+			publishFunc: func(_ context.Context, _ *entity.DecisionResult) error {
+				return errors.New("broker unavailable")
+			},
+		}
+
+		if _, err := fraudScoreUC.Execute(context.Background(), score); !errors.Is(err, ErrDecisionPublishFailed) {
+			t.Errorf("expected ErrDecisionPublishFailed, got %v", err)
+		}
+	})
+}
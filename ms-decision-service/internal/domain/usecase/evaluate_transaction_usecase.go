@@ -16,6 +16,12 @@ type EvaluateTransactionUseCase struct {
 	decisionPublisher   repository.DecisionPublisher
 	fraudScorePublisher repository.FraudScoreRequestPublisher
 	ruleEvalRepo        repository.RuleEvaluationRepository
+	rulesetVersions     *rulesetVersions
+	velocity            velocityTracker
+	listRepo            repository.ListRepository
+	policyRepo          repository.DecisionPolicyRepository
+	processed           processedMessages
+	rates               entity.CurrencyRates
 	logger              zerolog.Logger
 }
 
@@ -25,6 +31,12 @@ func NewEvaluateTransactionUseCase(
 	decisionPublisher repository.DecisionPublisher,
 	fraudScorePublisher repository.FraudScoreRequestPublisher,
 	ruleEvalRepo repository.RuleEvaluationRepository,
+	ruleHistoryRepo repository.RuleHistoryRepository,
+	velocityStore repository.VelocityStore,
+	listRepo repository.ListRepository,
+	policyRepo repository.DecisionPolicyRepository,
+	processedStore repository.ProcessedMessageStore,
+	rates entity.CurrencyRates,
 	logger zerolog.Logger,
 ) *EvaluateTransactionUseCase {
 	return &EvaluateTransactionUseCase{
@@ -32,6 +44,12 @@ func NewEvaluateTransactionUseCase(
 		decisionPublisher:   decisionPublisher,
 		fraudScorePublisher: fraudScorePublisher,
 		ruleEvalRepo:        ruleEvalRepo,
+		rulesetVersions:     &rulesetVersions{repo: ruleHistoryRepo, logger: logger},
+		velocity:            velocityTracker{store: velocityStore, rates: rates, logger: logger},
+		listRepo:            listRepo,
+		policyRepo:          policyRepo,
+		processed:           processedMessages{store: processedStore, logger: logger},
+		rates:               rates,
 		logger:              logger,
 	}
 }
@@ -39,6 +57,16 @@ func NewEvaluateTransactionUseCase(
 // Execute evaluates the transaction against active rules and publishes the decision result.
 // When the rule evaluation yields FRAUD_CHECK, the transaction is published to the fraud
 // score request topic instead of the decision results topic.
+// The decision and every evaluation record are stamped with the ruleset version in effect.
+// The transaction is recorded in the velocity counters before the rules are evaluated,
+// so velocity fields include the transaction itself. Managed lists are read from a
+// snapshot; when it cannot be loaded, list conditions never match (fail-open).
+// The rules of the TRANSACTION rule set are evaluated with that rule set's decision
+// policy: first match wins, or in SCORE mode the weights of matching rules add up.
+// SHADOW rules are evaluated and recorded but never change the decision.
+// A transaction that was already processed returns the recorded result without
+// evaluating the rules or publishing again, unless the Transaction Evaluator
+// republished it: then it is evaluated and published as if it were new.
 func (uc *EvaluateTransactionUseCase) Execute(
 	ctx context.Context,
 	transaction *entity.TransactionMessage,
@@ -47,45 +75,101 @@ func (uc *EvaluateTransactionUseCase) Execute(
 		return nil, ErrTransactionNil
 	}
 
-	rules, err := uc.ruleRepo.FindActiveRulesSortedByPriority(ctx)
+	if transaction.RepublishAttempt > 0 {
+		uc.logger.Info().
+			Str("transaction_id", transaction.ID).
+			Int("republish_attempt", transaction.RepublishAttempt).
+			Msg("republished transaction, evaluating again")
+	} else if result := uc.processed.find(ctx, transaction.ID, entity.StageTransactionCreated); result != nil {
+		return result, nil
+	}
+
+	rules, rulesetVersion, err := uc.rulesetVersions.activeRules(ctx, uc.ruleRepo)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrRuleRetrievalFailed, err)
 	}
 
-	status := entity.EvaluateRules(transaction, rules)
+	policy, err := loadDecisionPolicy(ctx, uc.policyRepo, entity.RuleSetTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	enriched := &entity.EnrichedTransaction{
+		TransactionMessage: transaction,
+		Velocity:           uc.velocity.track(ctx, transaction),
+		Lists:              uc.loadLists(ctx, transaction.ID),
+		Rates:              uc.rates,
+	}
+
+	transactionRules := entity.RulesInSet(rules, entity.RuleSetTransaction)
+	evaluation := policy.Evaluate(enriched, transactionRules)
+	status := evaluation.Status
 
 	// Persist rule evaluation results (non-fatal — log error but do not block)
-	uc.persistTransactionRuleEvaluations(ctx, transaction, rules)
+	uc.persistTransactionRuleEvaluations(ctx, enriched, rules, rulesetVersion, &policy, transactionRules, &evaluation)
 
 	if status == entity.FRAUDCHECK {
 		if err := uc.fraudScorePublisher.Publish(ctx, transaction); err != nil {
 			return nil, fmt.Errorf("%w: %w", ErrFraudScorePublishFailed, err)
 		}
 
-		return &entity.DecisionResult{
-			TransactionID: transaction.ID,
-			Status:        status,
-		}, nil
+		result := &entity.DecisionResult{
+			TransactionID:  transaction.ID,
+			Status:         status,
+			RulesetVersion: rulesetVersion,
+			Score:          scoreOf(&evaluation),
+		}
+		uc.processed.record(ctx, entity.StageTransactionCreated, result)
+
+		return result, nil
 	}
 
 	result := &entity.DecisionResult{
-		TransactionID: transaction.ID,
-		Status:        status,
+		TransactionID:  transaction.ID,
+		Status:         status,
+		RulesetVersion: rulesetVersion,
+		Score:          scoreOf(&evaluation),
+		DecisionPath:   entity.DecisionPathDirect,
 	}
 
 	if err := uc.decisionPublisher.Publish(ctx, result); err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrDecisionPublishFailed, err)
 	}
+	uc.processed.record(ctx, entity.StageTransactionCreated, result)
 
 	return result, nil
 }
 
+// loadLists returns the managed lists to evaluate the transaction with, or nil when
+// no list repository is configured or the snapshot cannot be loaded.
+func (uc *EvaluateTransactionUseCase) loadLists(ctx context.Context, transactionID string) entity.ListLookup {
+	if uc.listRepo == nil {
+		return nil
+	}
+
+	snapshot, err := uc.listRepo.LoadSnapshot(ctx)
+	if err != nil {
+		uc.logger.Warn().Err(err).
+			Str("transaction_id", transactionID).
+			Msg("failed to load managed lists")
+		return nil
+	}
+
+	return snapshot
+}
+
 // persistTransactionRuleEvaluations builds RuleEvaluationResult records for each rule
-// evaluated and persists them via SaveBatch. Errors are logged but do not block the flow.
+// evaluated and persists them via SaveBatch. SHADOW rules of the TRANSACTION rule set
+// also record the decision they would have produced. Errors are logged but do not
+// block the flow.
 func (uc *EvaluateTransactionUseCase) persistTransactionRuleEvaluations(
 	ctx context.Context,
-	transaction *entity.TransactionMessage,
+	transaction *entity.EnrichedTransaction,
 	rules []entity.Rule,
+	rulesetVersion int,
+	policy *entity.DecisionPolicy,
+	transactionRules []entity.Rule,
+	evaluation *entity.Evaluation,
 ) {
 	if len(rules) == 0 {
 		return
@@ -94,27 +178,13 @@ func (uc *EvaluateTransactionUseCase) persistTransactionRuleEvaluations(
 	now := time.Now()
 	results := make([]entity.RuleEvaluationResult, 0, len(rules))
 
-	for _, rule := range rules {
-		actualValue := transaction.GetFieldValue(rule.ConditionField)
-		matched := rule.ConditionOperator.Compare(
-			actualValue,
-			rule.ConditionValue,
-			rule.ConditionField,
-		)
-
-		results = append(results, entity.RuleEvaluationResult{
-			TransactionID:     transaction.ID,
-			RuleID:            rule.RuleID,
-			RuleName:          rule.RuleName,
-			ConditionField:    string(rule.ConditionField),
-			ConditionOperator: string(rule.ConditionOperator),
-			ConditionValue:    rule.ConditionValue,
-			ActualFieldValue:  actualValue,
-			Matched:           matched,
-			ResultStatus:      string(rule.ResultStatus),
-			EvaluatedAt:       now,
-			Priority:          rule.Priority,
-		})
+	for i := range rules {
+		result := entity.NewRuleEvaluationResult(transaction.ID, &rules[i], transaction, rulesetVersion, now)
+		result.RecordScore(evaluation)
+		if rules[i].IsShadow() && rules[i].RuleSet() == entity.RuleSetTransaction {
+			result.RecordShadowOutcome(policy.ShadowStatus(transaction, transactionRules, rules[i].RuleID), evaluation.Status)
+		}
+		results = append(results, result)
 	}
 
 	if err := uc.ruleEvalRepo.SaveBatch(ctx, results); err != nil {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"ms-decision-service/internal/domain/entity"
+	"sort"
 	"testing"
 	"time"
 
@@ -15,8 +16,17 @@ import (
 // --- Hand-written mocks ---
 
 type mockRuleRepository struct {
-	findFunc    func(ctx context.Context) ([]entity.Rule, error)
-	findAllFunc func(ctx context.Context) ([]entity.Rule, error)
+	findFunc     func(ctx context.Context) ([]entity.Rule, error)
+	findAllFunc  func(ctx context.Context) ([]entity.Rule, error)
+	findByIDFunc func(ctx context.Context, ruleID string) (*entity.Rule, error)
+	applyFunc    func(ctx context.Context, set *entity.RuleChangeSet) error
+	// rules holds the rules of newInMemoryRuleRepository.
+	rules map[string]entity.Rule
+	// history, when set, receives the versions and ruleset version of applied sets.
+	history   *mockRuleHistoryRepository
+	created   *entity.Rule
+	updated   *entity.Rule
+	deletedID string
 }
 
 func (m *mockRuleRepository) FindActiveRulesSortedByPriority(ctx context.Context) ([]entity.Rule, error) {
@@ -36,6 +46,45 @@ func (m *mockRuleRepository) FindAll(ctx context.Context) ([]entity.Rule, error)
 	return nil, nil
 }
 
+func (m *mockRuleRepository) FindByID(ctx context.Context, ruleID string) (*entity.Rule, error) {
+	if m.findByIDFunc != nil {
+		return m.findByIDFunc(ctx, ruleID)
+	}
+	return nil, nil
+}
+
+func (m *mockRuleRepository) ApplyChanges(ctx context.Context, set *entity.RuleChangeSet) error {
+	if m.applyFunc != nil {
+		if err := m.applyFunc(ctx, set); err != nil {
+			return err
+		}
+	}
+
+	for _, change := range set.Changes {
+		rule := change.Version.Rule
+		switch {
+		case change.Version.Deleted:
+			m.deletedID = change.Version.RuleID
+		case change.Before == nil:
+			m.created = &rule
+		default:
+			m.updated = &rule
+		}
+
+		if m.history != nil {
+			if change.Baseline != nil {
+				m.history.versions = append(m.history.versions, *change.Baseline)
+			}
+			m.history.versions = append(m.history.versions, change.Version)
+		}
+	}
+	if m.history != nil {
+		m.history.rulesetVersion = set.RulesetVersion
+	}
+
+	return nil
+}
+
 type mockDecisionPublisher struct {
 	publishFunc func(ctx context.Context, result *entity.DecisionResult) error
 	lastResult  *entity.DecisionResult
@@ -69,6 +118,7 @@ func (m *mockFraudScoreRequestPublisher) Publish(ctx context.Context, transactio
 type mockRuleEvaluationRepository struct {
 	saveBatchFunc func(ctx context.Context, results []entity.RuleEvaluationResult) error
 	findFunc      func(ctx context.Context, transactionID string) ([]entity.RuleEvaluationResult, error)
+	findShadow    func(ctx context.Context, ruleID string, since time.Time) ([]entity.RuleEvaluationResult, error)
 	lastResults   []entity.RuleEvaluationResult
 	saveCalled    bool
 }
@@ -89,6 +139,97 @@ func (m *mockRuleEvaluationRepository) FindByTransactionID(ctx context.Context,
 	return nil, nil
 }
 
+func (m *mockRuleEvaluationRepository) FindShadowByRuleID(ctx context.Context, ruleID string, since time.Time) ([]entity.RuleEvaluationResult, error) {
+	if m.findShadow != nil {
+		return m.findShadow(ctx, ruleID, since)
+	}
+	return nil, nil
+}
+
+type mockVelocityStore struct {
+	recordFunc func(ctx context.Context, key string, event entity.VelocityEvent, windows []time.Duration) ([]entity.VelocityAggregate, error)
+	keys       []string
+}
+
+func (m *mockVelocityStore) Record(
+	ctx context.Context,
+	key string,
+	event entity.VelocityEvent,
+	windows []time.Duration,
+) ([]entity.VelocityAggregate, error) {
+	m.keys = append(m.keys, key)
+	if m.recordFunc != nil {
+		return m.recordFunc(ctx, key, event, windows)
+	}
+	return make([]entity.VelocityAggregate, len(windows)), nil
+}
+
+// mockProcessedMessageStore keeps saved results in memory, keyed by transaction and stage.
+type mockProcessedMessageStore struct {
+	results map[string]entity.DecisionResult
+	findErr error
+	saveErr error
+}
+
+func (m *mockProcessedMessageStore) Find(_ context.Context, transactionID string, stage entity.ProcessingStage) (*entity.DecisionResult, error) {
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	result, ok := m.results[transactionID+"#"+string(stage)]
+	if !ok {
+		return nil, nil
+	}
+	return &result, nil
+}
+
+func (m *mockProcessedMessageStore) Save(_ context.Context, stage entity.ProcessingStage, result *entity.DecisionResult) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	if m.results == nil {
+		m.results = make(map[string]entity.DecisionResult)
+	}
+	m.results[result.TransactionID+"#"+string(stage)] = *result
+	return nil
+}
+
+// mockRuleHistoryRepository holds the versions and ruleset version written through
+// a mockRuleRepository linked to it.
+type mockRuleHistoryRepository struct {
+	versions       []entity.RuleVersion
+	rulesetVersion int
+	findErr        error
+	rulesetErr     error
+}
+
+func (m *mockRuleHistoryRepository) FindByRuleID(_ context.Context, ruleID string) ([]entity.RuleVersion, error) {
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	var versions []entity.RuleVersion
+	for _, v := range m.versions {
+		if v.RuleID == ruleID {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+func (m *mockRuleHistoryRepository) FindAll(_ context.Context) ([]entity.RuleVersion, error) {
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	versions := append([]entity.RuleVersion(nil), m.versions...)
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].RulesetVersion < versions[j].RulesetVersion
+	})
+	return versions, nil
+}
+
+func (m *mockRuleHistoryRepository) CurrentRulesetVersion(_ context.Context) (int, error) {
+	return m.rulesetVersion, m.rulesetErr
+}
+
 // --- Helpers ---
 
 func newTestTransaction() *entity.TransactionMessage {
@@ -288,7 +429,7 @@ func TestEvaluateTransactionUseCase_Execute(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			uc := NewEvaluateTransactionUseCase(tc.ruleRepo, tc.publisher, tc.fraudScorePublisher, &mockRuleEvaluationRepository{}, zerolog.Nop())
+			uc := NewEvaluateTransactionUseCase(tc.ruleRepo, tc.publisher, tc.fraudScorePublisher, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, &mockVelocityStore{}, nil, nil, nil, nil, zerolog.Nop())
 			result, err := uc.Execute(context.Background(), tc.transaction)
 
 			if tc.wantErr != nil {
@@ -395,7 +536,7 @@ func TestEvaluateTransactionUseCase_RuleEvaluationPersistence(t *testing.T) {
 		}
 		ruleEvalRepo := &mockRuleEvaluationRepository{}
 
-		uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, &mockFraudScoreRequestPublisher{}, ruleEvalRepo, zerolog.Nop())
+		uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, &mockFraudScoreRequestPublisher{}, ruleEvalRepo, &mockRuleHistoryRepository{}, &mockVelocityStore{}, nil, nil, nil, nil, zerolog.Nop())
 		_, err := uc.Execute(context.Background(), tx)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -441,7 +582,7 @@ func TestEvaluateTransactionUseCase_RuleEvaluationPersistence(t *testing.T) {
 		}
 		ruleEvalRepo := &mockRuleEvaluationRepository{}
 
-		uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, &mockFraudScoreRequestPublisher{}, ruleEvalRepo, zerolog.Nop())
+		uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, &mockFraudScoreRequestPublisher{}, ruleEvalRepo, &mockRuleHistoryRepository{}, &mockVelocityStore{}, nil, nil, nil, nil, zerolog.Nop())
 		_, err := uc.Execute(context.Background(), tx)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -521,7 +662,7 @@ func TestEvaluateTransactionUseCase_RuleEvaluationPersistence(t *testing.T) {
 			},
 		}
 
-		uc := NewEvaluateTransactionUseCase(ruleRepo, decisionPub, &mockFraudScoreRequestPublisher{}, ruleEvalRepo, zerolog.Nop())
+		uc := NewEvaluateTransactionUseCase(ruleRepo, decisionPub, &mockFraudScoreRequestPublisher{}, ruleEvalRepo, &mockRuleHistoryRepository{}, &mockVelocityStore{}, nil, nil, nil, nil, zerolog.Nop())
 		result, err := uc.Execute(context.Background(), tx)
 
 		if err != nil {
@@ -540,6 +681,59 @@ func TestEvaluateTransactionUseCase_RuleEvaluationPersistence(t *testing.T) {
 			t.Error("expected SaveBatch to have been attempted")
 		}
 	})
+
+	t.Run("compound rule decides and records per-leaf results", func(t *testing.T) {
+		tx := newTestTransaction()
+		tx.PaymentMethod = "CRYPTO"
+		rules := []entity.Rule{
+			{
+				RuleID:   "rule-compound",
+				RuleName: "Decline CRYPTO over $100",
+				Condition: &entity.ConditionNode{
+					Logic: entity.LogicAnd,
+					Children: []entity.ConditionNode{
+						{Field: entity.FieldPaymentMethod, Operator: entity.OpEqual, Value: "CRYPTO"},
+						{Field: entity.FieldAmountInCents, Operator: entity.OpGreaterThan, Value: "10000"},
+					},
+				},
+				ResultStatus: entity.DECLINED,
+				Priority:     1,
+				IsActive:     true,
+			},
+		}
+
+		ruleRepo := &mockRuleRepository{
+			findFunc: func(_ context.Context) ([]entity.Rule, error) {
+				return rules, nil
+			},
+		}
+		ruleEvalRepo := &mockRuleEvaluationRepository{}
+
+		uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, &mockFraudScoreRequestPublisher{}, ruleEvalRepo, &mockRuleHistoryRepository{}, &mockVelocityStore{}, nil, nil, nil, nil, zerolog.Nop())
+		result, err := uc.Execute(context.Background(), tx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Status != entity.DECLINED {
+			t.Errorf("expected DECLINED status, got %q", result.Status)
+		}
+
+		if len(ruleEvalRepo.lastResults) != 1 {
+			t.Fatalf("expected 1 result, got %d", len(ruleEvalRepo.lastResults))
+		}
+		saved := ruleEvalRepo.lastResults[0]
+		if !saved.Matched {
+			t.Error("expected compound rule result to be matched")
+		}
+		if len(saved.ConditionResults) != 2 {
+			t.Fatalf("expected 2 condition results, got %d", len(saved.ConditionResults))
+		}
+		for i, leaf := range saved.ConditionResults {
+			if !leaf.Matched {
+				t.Errorf("condition result[%d]: expected matched leaf, got %+v", i, leaf)
+			}
+		}
+	})
 }
 
 // Feature: fraud-score-service, Property 1: FRAUD_CHECK routes to fraud score request, not decision results
@@ -636,7 +830,7 @@ func TestProperty_FraudCheckRoutesToFraudScoreRequest(t *testing.T) {
 			},
 		}
 
-		uc := NewEvaluateTransactionUseCase(ruleRepo, decisionPub, fraudScorePub, &mockRuleEvaluationRepository{}, zerolog.Nop())
+		uc := NewEvaluateTransactionUseCase(ruleRepo, decisionPub, fraudScorePub, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, &mockVelocityStore{}, nil, nil, nil, nil, zerolog.Nop())
 		result, err := uc.Execute(context.Background(), tx)
 
 		if err != nil {
@@ -762,7 +956,7 @@ func TestProperty_NonFraudCheckRoutesToDecisionResults(t *testing.T) {
 			},
 		}
 
-		uc := NewEvaluateTransactionUseCase(ruleRepo, decisionPub, fraudScorePub, &mockRuleEvaluationRepository{}, zerolog.Nop())
+		uc := NewEvaluateTransactionUseCase(ruleRepo, decisionPub, fraudScorePub, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, &mockVelocityStore{}, nil, nil, nil, nil, zerolog.Nop())
 		result, err := uc.Execute(context.Background(), tx)
 
 		if err != nil {
@@ -793,6 +987,9 @@ func TestProperty_NonFraudCheckRoutesToDecisionResults(t *testing.T) {
 		if decisionPub.lastResult.Status != resultStatus {
 			t.Fatalf("decision publisher got status %q, want %q", decisionPub.lastResult.Status, resultStatus)
 		}
+		if decisionPub.lastResult.DecisionPath != entity.DecisionPathDirect {
+			t.Fatalf("decision publisher got decision path %q, want %q", decisionPub.lastResult.DecisionPath, entity.DecisionPathDirect)
+		}
 
 		// Assert: fraud score publisher is NOT called
 		if fraudScorePub.called {
@@ -885,7 +1082,7 @@ func TestProperty_RuleEvaluationPersistenceCompleteness(t *testing.T) {
 
 		uc := NewEvaluateTransactionUseCase(
 			ruleRepo, &mockDecisionPublisher{}, &mockFraudScoreRequestPublisher{},
-			ruleEvalRepo, zerolog.Nop(),
+			ruleEvalRepo, &mockRuleHistoryRepository{}, &mockVelocityStore{}, nil, nil, nil, nil, zerolog.Nop(),
 		)
 		_, _ = uc.Execute(context.Background(), tx)
 
@@ -934,3 +1131,509 @@ func TestProperty_RuleEvaluationPersistenceCompleteness(t *testing.T) {
 		}
 	})
 }
+
+func TestEvaluateTransactionUseCase_RulesetVersion(t *testing.T) {
+	rules := []entity.Rule{
+		{RuleID: "rule-001", RuleName: "Block CRYPTO", ConditionField: entity.FieldPaymentMethod,
+			ConditionOperator: entity.OpEqual, ConditionValue: "CRYPTO", ResultStatus: entity.DECLINED,
+			Priority: 1, IsActive: true, Version: 4},
+	}
+	ruleRepo := &mockRuleRepository{
+		findFunc: func(_ context.Context) ([]entity.Rule, error) {
+			return rules, nil
+		},
+	}
+
+	t.Run("stamps the decision and evaluations with the ruleset version", func(t *testing.T) {
+		decisionPub := &mockDecisionPublisher{}
+		ruleEvalRepo := &mockRuleEvaluationRepository{}
+		historyRepo := &mockRuleHistoryRepository{rulesetVersion: 12}
+
+		uc := NewEvaluateTransactionUseCase(ruleRepo, decisionPub, &mockFraudScoreRequestPublisher{}, ruleEvalRepo, historyRepo, &mockVelocityStore{}, nil, nil, nil, nil, zerolog.Nop())
+		result, err := uc.Execute(context.Background(), newTestTransaction())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.RulesetVersion != 12 || decisionPub.lastResult.RulesetVersion != 12 {
+			t.Errorf("expected ruleset version 12 on the decision, got %+v", result)
+		}
+		if len(ruleEvalRepo.lastResults) != 1 {
+			t.Fatalf("expected 1 evaluation, got %d", len(ruleEvalRepo.lastResults))
+		}
+		if got := ruleEvalRepo.lastResults[0]; got.RulesetVersion != 12 || got.RuleVersion != 4 {
+			t.Errorf("expected ruleset version 12 and rule version 4, got %+v", got)
+		}
+	})
+
+	t.Run("ruleset version failure evaluates with the last version read", func(t *testing.T) {
+		decisionPub := &mockDecisionPublisher{}
+		historyRepo := &mockRuleHistoryRepository{rulesetVersion: 12}
+		uc := NewEvaluateTransactionUseCase(ruleRepo, decisionPub, &mockFraudScoreRequestPublisher{}, &mockRuleEvaluationRepository{}, historyRepo, &mockVelocityStore{}, nil, nil, nil, nil, zerolog.Nop())
+		if _, err := uc.Execute(context.Background(), newTestTransaction()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		historyRepo.rulesetErr = errors.New("dynamo timeout")
+		result, err := uc.Execute(context.Background(), newTestTransaction())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.RulesetVersion != 12 || decisionPub.lastResult.RulesetVersion != 12 {
+			t.Errorf("expected a decision at ruleset version 12, got %+v", result)
+		}
+	})
+
+	t.Run("a rule snapshot stamps the version it was loaded at", func(t *testing.T) {
+		decisionPub := &mockDecisionPublisher{}
+		snapshot := &snapshotRuleRepository{mockRuleRepository: ruleRepo, rulesetVersion: 11}
+		historyRepo := &mockRuleHistoryRepository{rulesetVersion: 12}
+		uc := NewEvaluateTransactionUseCase(snapshot, decisionPub, &mockFraudScoreRequestPublisher{}, &mockRuleEvaluationRepository{}, historyRepo, &mockVelocityStore{}, nil, nil, nil, nil, zerolog.Nop())
+
+		result, err := uc.Execute(context.Background(), newTestTransaction())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.RulesetVersion != 11 || decisionPub.lastResult.RulesetVersion != 11 {
+			t.Errorf("expected the version of the rule snapshot, got %+v", result)
+		}
+	})
+}
+
+// snapshotRuleRepository serves the rules of a mockRuleRepository with a ruleset
+// version, like the rule cache.
+type snapshotRuleRepository struct {
+	*mockRuleRepository
+	rulesetVersion int
+}
+
+func (s *snapshotRuleRepository) FindActiveRuleset(ctx context.Context) ([]entity.Rule, int, error) {
+	rules, err := s.FindActiveRulesSortedByPriority(ctx)
+	return rules, s.rulesetVersion, err
+}
+
+func TestEvaluateTransactionUseCase_VelocityFields(t *testing.T) {
+	rules := []entity.Rule{{
+		RuleID:            "rule-velocity",
+		RuleName:          "Too many transactions per customer",
+		ConditionField:    "customer_txn_count_1h",
+		ConditionOperator: entity.OpGreaterThan,
+		ConditionValue:    "3",
+		ResultStatus:      entity.DECLINED,
+		Priority:          1,
+		IsActive:          true,
+	}}
+	ruleRepo := &mockRuleRepository{
+		findFunc: func(_ context.Context) ([]entity.Rule, error) {
+			return rules, nil
+		},
+	}
+
+	t.Run("records every dimension and evaluates velocity fields", func(t *testing.T) {
+		velocityStore := &mockVelocityStore{
+			recordFunc: func(_ context.Context, key string, _ entity.VelocityEvent, windows []time.Duration) ([]entity.VelocityAggregate, error) {
+				aggregates := make([]entity.VelocityAggregate, len(windows))
+				if key == "customer:cust-001" {
+					aggregates[1] = entity.VelocityAggregate{Count: 4, AmountSum: 400000}
+				}
+				return aggregates, nil
+			},
+		}
+		ruleEvalRepo := &mockRuleEvaluationRepository{}
+		tx := newTestTransaction()
+		tx.CustomerID = "cust-001"
+		tx.CustomerIPAddress = "10.0.0.1"
+		tx.CustomerEmail = ""
+
+		uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, &mockFraudScoreRequestPublisher{}, ruleEvalRepo, &mockRuleHistoryRepository{}, velocityStore, nil, nil, nil, nil, zerolog.Nop())
+		result, err := uc.Execute(context.Background(), tx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.Status != entity.DECLINED {
+			t.Errorf("expected DECLINED, got %s", result.Status)
+		}
+		if len(velocityStore.keys) != 2 {
+			t.Errorf("expected customer and ip keys to be recorded, got %v", velocityStore.keys)
+		}
+		if got := ruleEvalRepo.lastResults[0].ActualFieldValue; got != "4" {
+			t.Errorf("expected evaluation to record velocity value 4, got %q", got)
+		}
+	})
+
+	t.Run("records amounts in US cents", func(t *testing.T) {
+		amounts := map[string]int64{}
+		velocityStore := &mockVelocityStore{
+			recordFunc: func(_ context.Context, _ string, event entity.VelocityEvent, windows []time.Duration) ([]entity.VelocityAggregate, error) {
+				amounts[event.TransactionID] = event.AmountInUSDCents
+				return make([]entity.VelocityAggregate, len(windows)), nil
+			},
+		}
+		rates := entity.CurrencyRates{"USD": 1, "COP": 0.00025}
+		uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, &mockFraudScoreRequestPublisher{}, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, velocityStore, nil, nil, nil, rates, zerolog.Nop())
+
+		for _, tt := range []struct {
+			id       string
+			currency string
+			amount   int64
+			want     int64
+		}{
+			{"tx-usd", "USD", 150000, 150000},
+			{"tx-cop", "COP", 400000000, 100000},
+			{"tx-gbp", "GBP", 5000, 0},
+		} {
+			tx := newTestTransaction()
+			tx.ID, tx.Currency, tx.AmountInCents = tt.id, tt.currency, tt.amount
+			if _, err := uc.Execute(context.Background(), tx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if amounts[tt.id] != tt.want {
+				t.Errorf("%s: expected %d US cents, got %d", tt.id, tt.want, amounts[tt.id])
+			}
+		}
+	})
+
+	t.Run("store failure leaves velocity fields unmatched", func(t *testing.T) {
+		velocityStore := &mockVelocityStore{
+			recordFunc: func(_ context.Context, _ string, _ entity.VelocityEvent, _ []time.Duration) ([]entity.VelocityAggregate, error) {
+				return nil, errors.New("store unavailable")
+			},
+		}
+
+		uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, &mockFraudScoreRequestPublisher{}, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, velocityStore, nil, nil, nil, nil, zerolog.Nop())
+		result, err := uc.Execute(context.Background(), newTestTransaction())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.Status != entity.APPROVED {
+			t.Errorf("expected APPROVED when velocity is unavailable, got %s", result.Status)
+		}
+	})
+}
+
+func TestEvaluateTransactionUseCase_ManagedLists(t *testing.T) {
+	rules := []entity.Rule{{
+		RuleID:            "rule-blocklist",
+		RuleName:          "Blocked emails",
+		ConditionField:    entity.FieldCustomerEmail,
+		ConditionOperator: entity.OpInList,
+		ConditionValue:    "blocked-emails",
+		ResultStatus:      entity.DECLINED,
+		Priority:          1,
+		IsActive:          true,
+	}}
+	ruleRepo := &mockRuleRepository{
+		findFunc: func(_ context.Context) ([]entity.Rule, error) {
+			return rules, nil
+		},
+	}
+
+	t.Run("list hit declines and is recorded", func(t *testing.T) {
+		listRepo := &mockListRepository{
+			loadSnapshotFunc: func(_ context.Context) (*entity.ListSnapshot, error) {
+				return entity.NewListSnapshot(
+					[]entity.ManagedList{*newEmailBlocklist()},
+					[]entity.ListEntry{{ListName: "blocked-emails", Value: "john@example.com"}},
+				), nil
+			},
+		}
+		ruleEvalRepo := &mockRuleEvaluationRepository{}
+
+		uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, &mockFraudScoreRequestPublisher{}, ruleEvalRepo, &mockRuleHistoryRepository{}, nil, listRepo, nil, nil, nil, zerolog.Nop())
+		result, err := uc.Execute(context.Background(), newTestTransaction())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.Status != entity.DECLINED {
+			t.Errorf("expected DECLINED, got %s", result.Status)
+		}
+		hits := ruleEvalRepo.lastResults[0].ListHits
+		if len(hits) != 1 || hits[0].ListName != "blocked-emails" || hits[0].Value != "john@example.com" {
+			t.Errorf("expected the list hit to be recorded, got %+v", hits)
+		}
+	})
+
+	t.Run("snapshot failure leaves list conditions unmatched", func(t *testing.T) {
+		listRepo := &mockListRepository{
+			loadSnapshotFunc: func(_ context.Context) (*entity.ListSnapshot, error) {
+				return nil, errors.New("table unavailable")
+			},
+		}
+
+		uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, &mockFraudScoreRequestPublisher{}, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, nil, listRepo, nil, nil, nil, zerolog.Nop())
+		result, err := uc.Execute(context.Background(), newTestTransaction())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.Status != entity.APPROVED {
+			t.Errorf("expected APPROVED when lists are unavailable, got %s", result.Status)
+		}
+	})
+}
+
+func TestEvaluateTransactionUseCase_ScoreMode(t *testing.T) {
+	rules := []entity.Rule{
+		{RuleID: "rule-crypto", RuleName: "Crypto payment", ConditionField: entity.FieldPaymentMethod, ConditionOperator: entity.OpEqual, ConditionValue: "CRYPTO", ResultStatus: entity.DECLINED, Priority: 1, ScoreWeight: 30, IsActive: true},
+		{RuleID: "rule-amount", RuleName: "High amount", ConditionField: entity.FieldAmountInCents, ConditionOperator: entity.OpGreaterThan, ConditionValue: "100000", ResultStatus: entity.FRAUDCHECK, Priority: 2, ScoreWeight: 25, IsActive: true},
+		{RuleID: "rule-fraud-score", RuleName: "High fraud score", ConditionField: entity.FieldFraudScore, ConditionOperator: entity.OpGreaterThan, ConditionValue: "80", ResultStatus: entity.DECLINED, Priority: 3, ScoreWeight: 100, IsActive: true},
+	}
+	ruleRepo := &mockRuleRepository{
+		findFunc: func(_ context.Context) ([]entity.Rule, error) {
+			return rules, nil
+		},
+	}
+	fraudCheck := 20
+
+	tests := []struct {
+		name          string
+		paymentMethod string
+		decline       int
+		wantStatus    entity.DecisionStatus
+		wantScore     int
+	}{
+		{name: "total reaches the decline threshold", paymentMethod: "CRYPTO", decline: 50, wantStatus: entity.DECLINED, wantScore: 55},
+		{name: "total reaches the fraud check threshold", paymentMethod: "CARD", decline: 50, wantStatus: entity.FRAUDCHECK, wantScore: 25},
+		{name: "total below the decline threshold", paymentMethod: "CRYPTO", decline: 100, wantStatus: entity.FRAUDCHECK, wantScore: 55},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			policyRepo := &mockDecisionPolicyRepository{
+				findByRuleSetFunc: func(_ context.Context, ruleSet entity.RuleSet) (*entity.DecisionPolicy, error) {
+					if ruleSet != entity.RuleSetTransaction {
+						t.Errorf("expected the TRANSACTION policy to be loaded, got %s", ruleSet)
+					}
+					return newScorePolicy(entity.RuleSetTransaction, &fraudCheck, tc.decline), nil
+				},
+			}
+			ruleEvalRepo := &mockRuleEvaluationRepository{}
+			tx := newTestTransaction()
+			tx.PaymentMethod = tc.paymentMethod
+			tx.AmountInCents = 500000
+
+			uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, &mockFraudScoreRequestPublisher{}, ruleEvalRepo, &mockRuleHistoryRepository{}, nil, nil, policyRepo, nil, nil, zerolog.Nop())
+			result, err := uc.Execute(context.Background(), tx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Status != tc.wantStatus {
+				t.Errorf("expected %s, got %s", tc.wantStatus, result.Status)
+			}
+			if result.Score == nil || *result.Score != tc.wantScore {
+				t.Errorf("expected score %d, got %v", tc.wantScore, result.Score)
+			}
+			if len(ruleEvalRepo.lastResults) != len(rules) {
+				t.Fatalf("expected every rule to be persisted, got %d", len(ruleEvalRepo.lastResults))
+			}
+			for _, r := range ruleEvalRepo.lastResults {
+				if r.DecisionMode != entity.ModeScore || r.TotalScore != tc.wantScore {
+					t.Errorf("expected score fields on %s, got %+v", r.RuleID, r)
+				}
+				if r.Matched && r.ScoreContribution == 0 {
+					t.Errorf("expected a contribution from matched rule %s", r.RuleID)
+				}
+			}
+		})
+	}
+
+	t.Run("policy retrieval failure", func(t *testing.T) {
+		policyRepo := &mockDecisionPolicyRepository{
+			findByRuleSetFunc: func(_ context.Context, _ entity.RuleSet) (*entity.DecisionPolicy, error) {
+				return nil, errors.New("table unavailable")
+			},
+		}
+		decisionPub := &mockDecisionPublisher{}
+
+		uc := NewEvaluateTransactionUseCase(ruleRepo, decisionPub, &mockFraudScoreRequestPublisher{}, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, nil, nil, policyRepo, nil, nil, zerolog.Nop())
+		_, err := uc.Execute(context.Background(), newTestTransaction())
+
+		if !errors.Is(err, ErrDecisionPolicyRetrievalFailed) {
+			t.Errorf("expected ErrDecisionPolicyRetrievalFailed, got %v", err)
+		}
+		if decisionPub.called {
+			t.Error("expected no decision to be published")
+		}
+	})
+}
+
+func TestEvaluateTransactionUseCase_ShadowRules(t *testing.T) {
+	rules := []entity.Rule{
+		{RuleID: "rule-shadow", RuleName: "Candidate USD rule", ConditionField: entity.FieldCurrency, ConditionOperator: entity.OpEqual, ConditionValue: "USD", ResultStatus: entity.DECLINED, Priority: 1, IsActive: true, Mode: entity.RuleModeShadow},
+		{RuleID: "rule-crypto", RuleName: "Crypto payment", ConditionField: entity.FieldPaymentMethod, ConditionOperator: entity.OpEqual, ConditionValue: "CRYPTO", ResultStatus: entity.DECLINED, Priority: 2, IsActive: true},
+	}
+	ruleRepo := &mockRuleRepository{
+		findFunc: func(_ context.Context) ([]entity.Rule, error) {
+			return rules, nil
+		},
+	}
+	ruleEvalRepo := &mockRuleEvaluationRepository{}
+	tx := newTestTransaction()
+	tx.Currency = "USD"
+	tx.PaymentMethod = "CARD"
+
+	uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, &mockFraudScoreRequestPublisher{}, ruleEvalRepo, &mockRuleHistoryRepository{}, nil, nil, nil, nil, nil, zerolog.Nop())
+	result, err := uc.Execute(context.Background(), tx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Status != entity.APPROVED {
+		t.Errorf("expected the shadow rule not to affect the decision, got %s", result.Status)
+	}
+
+	shadow := ruleEvalRepo.lastResults[0]
+	if !shadow.Shadow || !shadow.Matched || shadow.ShadowStatus != entity.DECLINED || shadow.ActualStatus != entity.APPROVED {
+		t.Errorf("expected the shadow outcome to be recorded, got %+v", shadow)
+	}
+	if live := ruleEvalRepo.lastResults[1]; live.Shadow || live.ShadowStatus != "" {
+		t.Errorf("expected no shadow outcome on live rules, got %+v", live)
+	}
+}
+
+func TestGetShadowReportUseCase_Execute(t *testing.T) {
+	ruleRepo := &mockRuleRepository{
+		findByIDFunc: func(_ context.Context, ruleID string) (*entity.Rule, error) {
+			if ruleID != "rule-shadow" {
+				return nil, nil
+			}
+			return &entity.Rule{RuleID: ruleID, Mode: entity.RuleModeShadow}, nil
+		},
+	}
+
+	t.Run("defaults to the last seven days", func(t *testing.T) {
+		var gotSince time.Time
+		ruleEvalRepo := &mockRuleEvaluationRepository{
+			findShadow: func(_ context.Context, _ string, since time.Time) ([]entity.RuleEvaluationResult, error) {
+				gotSince = since
+				return nil, nil
+			},
+		}
+
+		report, err := NewGetShadowReportUseCase(ruleRepo, ruleEvalRepo).Execute(context.Background(), "rule-shadow", time.Time{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if age := time.Since(gotSince); age < DefaultShadowReportWindow-time.Minute || age > DefaultShadowReportWindow+time.Minute {
+			t.Errorf("expected since about seven days ago, got %v", gotSince)
+		}
+		if report.Evaluated != 0 || report.Transitions == nil {
+			t.Errorf("expected an empty report, got %+v", report)
+		}
+	})
+
+	t.Run("unknown rule", func(t *testing.T) {
+		_, err := NewGetShadowReportUseCase(ruleRepo, &mockRuleEvaluationRepository{}).Execute(context.Background(), "rule-missing", time.Time{})
+		if !errors.Is(err, ErrRuleNotFound) {
+			t.Errorf("expected ErrRuleNotFound, got %v", err)
+		}
+	})
+
+	t.Run("evaluation query failure", func(t *testing.T) {
+		ruleEvalRepo := &mockRuleEvaluationRepository{
+			findShadow: func(_ context.Context, _ string, _ time.Time) ([]entity.RuleEvaluationResult, error) {
+				return nil, errors.New("index unavailable")
+			},
+		}
+
+		_, err := NewGetShadowReportUseCase(ruleRepo, ruleEvalRepo).Execute(context.Background(), "rule-shadow", time.Time{})
+		if !errors.Is(err, ErrEvaluationRetrievalFailed) {
+			t.Errorf("expected ErrEvaluationRetrievalFailed, got %v", err)
+		}
+	})
+}
+
+func TestEvaluateTransactionUseCase_DuplicateMessages(t *testing.T) {
+	rules := []entity.Rule{
+		{RuleID: "rule-001", RuleName: "Review cards", ConditionField: entity.FieldPaymentMethod,
+			ConditionOperator: entity.OpEqual, ConditionValue: "CARD", ResultStatus: entity.FRAUDCHECK,
+			Priority: 1, IsActive: true},
+	}
+	ruleRepo := &mockRuleRepository{
+		findFunc: func(_ context.Context) ([]entity.Rule, error) {
+			return rules, nil
+		},
+	}
+
+	t.Run("a redelivered transaction returns the recorded result without side effects", func(t *testing.T) {
+		store := &mockProcessedMessageStore{}
+		fraudScorePub := &mockFraudScoreRequestPublisher{}
+		uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, fraudScorePub, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{rulesetVersion: 2}, &mockVelocityStore{}, nil, nil, store, nil, zerolog.Nop())
+
+		first, err := uc.Execute(context.Background(), newTestTransaction())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		fraudScorePub.called = false
+		ruleEvalRepo := &mockRuleEvaluationRepository{}
+		uc.ruleEvalRepo = ruleEvalRepo
+		second, err := uc.Execute(context.Background(), newTestTransaction())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if *second != *first || second.Status != entity.FRAUDCHECK {
+			t.Errorf("expected the recorded result %+v, got %+v", first, second)
+		}
+		if fraudScorePub.called || ruleEvalRepo.saveCalled {
+			t.Error("expected no publish and no evaluation records for the duplicate")
+		}
+	})
+
+	t.Run("a republished transaction is evaluated and published again", func(t *testing.T) {
+		store := &mockProcessedMessageStore{}
+		fraudScorePub := &mockFraudScoreRequestPublisher{}
+		uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, fraudScorePub, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, &mockVelocityStore{}, nil, nil, store, nil, zerolog.Nop())
+
+		if _, err := uc.Execute(context.Background(), newTestTransaction()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		fraudScorePub.called = false
+		republished := newTestTransaction()
+		republished.RepublishAttempt = 1
+		if _, err := uc.Execute(context.Background(), republished); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !fraudScorePub.called {
+			t.Error("expected the republished transaction to be forwarded again")
+		}
+	})
+
+	t.Run("a failed publish is not recorded", func(t *testing.T) {
+		store := &mockProcessedMessageStore{}
+		fraudScorePub := &mockFraudScoreRequestPublisher{
+			publishFunc: func(_ context.Context, _ *entity.TransactionMessage) error {
+				return errors.New("broker unavailable")
+			},
+		}
+		uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, fraudScorePub, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, &mockVelocityStore{}, nil, nil, store, nil, zerolog.Nop())
+
+		if _, err := uc.Execute(context.Background(), newTestTransaction()); !errors.Is(err, ErrFraudScorePublishFailed) {
+			t.Fatalf("expected ErrFraudScorePublishFailed, got %v", err)
+		}
+		if len(store.results) != 0 {
+			t.Errorf("expected nothing to be recorded, got %+v", store.results)
+		}
+	})
+
+	t.Run("store failures are fail-open", func(t *testing.T) {
+		store := &mockProcessedMessageStore{findErr: errors.New("dynamo timeout"), saveErr: errors.New("dynamo timeout")}
+		fraudScorePub := &mockFraudScoreRequestPublisher{}
+		uc := NewEvaluateTransactionUseCase(ruleRepo, &mockDecisionPublisher{}, fraudScorePub, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, &mockVelocityStore{}, nil, nil, store, nil, zerolog.Nop())
+
+		result, err := uc.Execute(context.Background(), newTestTransaction())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Status != entity.FRAUDCHECK || !fraudScorePub.called {
+			t.Errorf("expected the transaction to be processed, got %+v", result)
+		}
+	})
+}
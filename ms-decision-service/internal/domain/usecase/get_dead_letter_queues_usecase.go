@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// GetDeadLetterQueuesUseCase retrieves the configured dead-letter topics.
+type GetDeadLetterQueuesUseCase struct {
+	deadLetterRepo repository.DeadLetterRepository
+	queues         []entity.DeadLetterQueue
+}
+
+// NewGetDeadLetterQueuesUseCase creates a new use case for the given dead-letter queues.
+func NewGetDeadLetterQueuesUseCase(
+	deadLetterRepo repository.DeadLetterRepository,
+	queues []entity.DeadLetterQueue,
+) *GetDeadLetterQueuesUseCase {
+	return &GetDeadLetterQueuesUseCase{
+		deadLetterRepo: deadLetterRepo,
+		queues:         queues,
+	}
+}
+
+// Execute returns each dead-letter queue with the number of messages it retains.
+func (uc *GetDeadLetterQueuesUseCase) Execute(
+	ctx context.Context,
+) ([]entity.DeadLetterQueue, error) {
+	queues := make([]entity.DeadLetterQueue, len(uc.queues))
+	for i, queue := range uc.queues {
+		count, err := uc.deadLetterRepo.Count(ctx, queue.Topic)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDeadLetterRetrievalFailed, err)
+		}
+		queue.Count = count
+		queues[i] = queue
+	}
+
+	return queues, nil
+}
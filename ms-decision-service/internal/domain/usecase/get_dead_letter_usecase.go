@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// GetDeadLetterUseCase retrieves a single message of a dead-letter topic.
+type GetDeadLetterUseCase struct {
+	deadLetterRepo repository.DeadLetterRepository
+	queues         []entity.DeadLetterQueue
+}
+
+// NewGetDeadLetterUseCase creates a new use case for the given dead-letter queues.
+func NewGetDeadLetterUseCase(
+	deadLetterRepo repository.DeadLetterRepository,
+	queues []entity.DeadLetterQueue,
+) *GetDeadLetterUseCase {
+	return &GetDeadLetterUseCase{
+		deadLetterRepo: deadLetterRepo,
+		queues:         queues,
+	}
+}
+
+// Execute returns the message of the dead-letter topic with the given ID.
+func (uc *GetDeadLetterUseCase) Execute(
+	ctx context.Context,
+	topic string,
+	id string,
+) (*entity.DeadLetter, error) {
+	if _, err := findDeadLetterQueue(uc.queues, topic); err != nil {
+		return nil, err
+	}
+
+	partition, offset, err := entity.ParseDeadLetterID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetter, err := uc.deadLetterRepo.FindByOffset(ctx, topic, partition, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDeadLetterRetrievalFailed, err)
+	}
+	if deadLetter == nil {
+		return nil, fmt.Errorf("%w: %s", ErrDeadLetterNotFound, id)
+	}
+
+	return deadLetter, nil
+}
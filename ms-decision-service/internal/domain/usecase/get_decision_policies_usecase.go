@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// GetDecisionPoliciesUseCase retrieves the decision policy of every rule set.
+type GetDecisionPoliciesUseCase struct {
+	policyRepo repository.DecisionPolicyRepository
+}
+
+// NewGetDecisionPoliciesUseCase creates a new use case with the given repository.
+func NewGetDecisionPoliciesUseCase(
+	policyRepo repository.DecisionPolicyRepository,
+) *GetDecisionPoliciesUseCase {
+	return &GetDecisionPoliciesUseCase{
+		policyRepo: policyRepo,
+	}
+}
+
+// Execute returns one policy per rule set, in evaluation order. Rule sets that
+// have never been configured report the default first-match policy.
+func (uc *GetDecisionPoliciesUseCase) Execute(
+	ctx context.Context,
+) ([]entity.DecisionPolicy, error) {
+	policies := make([]entity.DecisionPolicy, 0, len(entity.RuleSets))
+	for _, ruleSet := range entity.RuleSets {
+		policy, err := loadDecisionPolicy(ctx, uc.policyRepo, ruleSet)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// GetListUseCase retrieves a managed list together with its entries.
+type GetListUseCase struct {
+	listRepo repository.ListRepository
+}
+
+// NewGetListUseCase creates a new use case with the given repository.
+func NewGetListUseCase(
+	listRepo repository.ListRepository,
+) *GetListUseCase {
+	return &GetListUseCase{
+		listRepo: listRepo,
+	}
+}
+
+// Execute returns the list and every entry of it, including expired ones.
+func (uc *GetListUseCase) Execute(
+	ctx context.Context,
+	name string,
+) (*entity.ManagedList, []entity.ListEntry, error) {
+	list, err := findExistingList(ctx, uc.listRepo, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := uc.listRepo.FindEntries(ctx, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrListRetrievalFailed, err)
+	}
+
+	return list, entries, nil
+}
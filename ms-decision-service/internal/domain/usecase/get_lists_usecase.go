@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// GetListsUseCase retrieves every managed list.
+type GetListsUseCase struct {
+	listRepo repository.ListRepository
+}
+
+// NewGetListsUseCase creates a new use case with the given repository.
+func NewGetListsUseCase(
+	listRepo repository.ListRepository,
+) *GetListsUseCase {
+	return &GetListsUseCase{
+		listRepo: listRepo,
+	}
+}
+
+// Execute retrieves all lists sorted by name.
+func (uc *GetListsUseCase) Execute(
+	ctx context.Context,
+) ([]entity.ManagedList, error) {
+	lists, err := uc.listRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrListRetrievalFailed, err)
+	}
+
+	return lists, nil
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// GetRuleHistoryUseCase retrieves the change history of a single rule.
+type GetRuleHistoryUseCase struct {
+	ruleRepo    repository.RuleRepository
+	historyRepo repository.RuleHistoryRepository
+}
+
+// NewGetRuleHistoryUseCase creates a new use case with the given repositories.
+func NewGetRuleHistoryUseCase(
+	ruleRepo repository.RuleRepository,
+	historyRepo repository.RuleHistoryRepository,
+) *GetRuleHistoryUseCase {
+	return &GetRuleHistoryUseCase{
+		ruleRepo:    ruleRepo,
+		historyRepo: historyRepo,
+	}
+}
+
+// Execute returns every version of the rule sorted by version ascending.
+// A rule that exists but has never been changed has an empty history; a rule
+// that neither exists nor has history yields ErrRuleNotFound.
+func (uc *GetRuleHistoryUseCase) Execute(
+	ctx context.Context,
+	ruleID string,
+) ([]entity.RuleVersion, error) {
+	if ruleID == "" {
+		return nil, ErrRuleIDEmpty
+	}
+
+	versions, err := uc.historyRepo.FindByRuleID(ctx, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRuleHistoryRetrievalFailed, err)
+	}
+
+	if len(versions) == 0 {
+		if _, err := findExistingRule(ctx, uc.ruleRepo, ruleID); err != nil {
+			return nil, err
+		}
+		return []entity.RuleVersion{}, nil
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+	"time"
+)
+
+// DefaultShadowReportWindow is how far back a shadow report looks when no start is given.
+const DefaultShadowReportWindow = 7 * 24 * time.Hour
+
+// GetShadowReportUseCase reports how a SHADOW rule would have changed decisions.
+type GetShadowReportUseCase struct {
+	ruleRepo     repository.RuleRepository
+	ruleEvalRepo repository.RuleEvaluationRepository
+}
+
+// NewGetShadowReportUseCase creates a new use case with the given repositories.
+func NewGetShadowReportUseCase(
+	ruleRepo repository.RuleRepository,
+	ruleEvalRepo repository.RuleEvaluationRepository,
+) *GetShadowReportUseCase {
+	return &GetShadowReportUseCase{
+		ruleRepo:     ruleRepo,
+		ruleEvalRepo: ruleEvalRepo,
+	}
+}
+
+// Execute builds the shadow report of the rule from the evaluations recorded at or
+// after since. A zero since reports on the last DefaultShadowReportWindow.
+func (uc *GetShadowReportUseCase) Execute(
+	ctx context.Context,
+	ruleID string,
+	since time.Time,
+) (*entity.ShadowReport, error) {
+	if ruleID == "" {
+		return nil, ErrRuleIDEmpty
+	}
+
+	rule, err := uc.ruleRepo.FindByID(ctx, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRuleRetrievalFailed, err)
+	}
+	if rule == nil {
+		return nil, ErrRuleNotFound
+	}
+
+	if since.IsZero() {
+		since = time.Now().UTC().Add(-DefaultShadowReportWindow)
+	}
+
+	results, err := uc.ruleEvalRepo.FindShadowByRuleID(ctx, ruleID, since)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrEvaluationRetrievalFailed, err)
+	}
+
+	return entity.NewShadowReport(rule, since, results), nil
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+const (
+	// DefaultDeadLetterListLimit is the number of dead letters listed when no limit is given.
+	DefaultDeadLetterListLimit = 100
+	// MaxDeadLetterListLimit is the largest number of dead letters listed at once.
+	MaxDeadLetterListLimit = 1000
+)
+
+// ListDeadLettersUseCase lists the messages of a dead-letter topic.
+type ListDeadLettersUseCase struct {
+	deadLetterRepo repository.DeadLetterRepository
+	queues         []entity.DeadLetterQueue
+}
+
+// NewListDeadLettersUseCase creates a new use case for the given dead-letter queues.
+func NewListDeadLettersUseCase(
+	deadLetterRepo repository.DeadLetterRepository,
+	queues []entity.DeadLetterQueue,
+) *ListDeadLettersUseCase {
+	return &ListDeadLettersUseCase{
+		deadLetterRepo: deadLetterRepo,
+		queues:         queues,
+	}
+}
+
+// Execute returns up to limit messages of the dead-letter topic, oldest first.
+// A zero limit lists DefaultDeadLetterListLimit messages.
+func (uc *ListDeadLettersUseCase) Execute(
+	ctx context.Context,
+	topic string,
+	limit int,
+) ([]entity.DeadLetter, error) {
+	if _, err := findDeadLetterQueue(uc.queues, topic); err != nil {
+		return nil, err
+	}
+
+	if limit == 0 {
+		limit = DefaultDeadLetterListLimit
+	}
+	if limit < 0 || limit > MaxDeadLetterListLimit {
+		return nil, fmt.Errorf("%w: must be between 1 and %d", ErrDeadLetterLimitInvalid, MaxDeadLetterListLimit)
+	}
+
+	deadLetters, err := uc.deadLetterRepo.FindAll(ctx, topic, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDeadLetterRetrievalFailed, err)
+	}
+
+	return deadLetters, nil
+}
@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// findExistingList returns the list with the given name or ErrListNotFound.
+func findExistingList(ctx context.Context, listRepo repository.ListRepository, name string) (*entity.ManagedList, error) {
+	if name == "" {
+		return nil, ErrListNameEmpty
+	}
+
+	list, err := listRepo.FindByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrListRetrievalFailed, err)
+	}
+	if list == nil {
+		return nil, fmt.Errorf("%w: %s", ErrListNotFound, name)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+
+	"github.com/rs/zerolog"
+)
+
+// processedMessages deduplicates redelivered messages by remembering the result
+// computed for each transaction and stage.
+type processedMessages struct {
+	store  repository.ProcessedMessageStore
+	logger zerolog.Logger
+}
+
+// find returns the result recorded for the transaction at the stage, or nil when
+// the message has not been processed. Store failures are logged and the message is
+// processed again (fail-open).
+func (p *processedMessages) find(
+	ctx context.Context,
+	transactionID string,
+	stage entity.ProcessingStage,
+) *entity.DecisionResult {
+	if p.store == nil {
+		return nil
+	}
+
+	result, err := p.store.Find(ctx, transactionID, stage)
+	if err != nil {
+		p.logger.Warn().Err(err).
+			Str("transaction_id", transactionID).
+			Str("stage", string(stage)).
+			Msg("failed to look up processed message")
+		return nil
+	}
+
+	if result != nil {
+		p.logger.Info().
+			Str("transaction_id", transactionID).
+			Str("stage", string(stage)).
+			Str("status", string(result.Status)).
+			Msg("duplicate message skipped")
+	}
+
+	return result
+}
+
+// record remembers the result once its side effects are done. Store failures are
+// logged; a redelivery of the message is then processed again.
+func (p *processedMessages) record(
+	ctx context.Context,
+	stage entity.ProcessingStage,
+	result *entity.DecisionResult,
+) {
+	if p.store == nil {
+		return
+	}
+
+	if err := p.store.Save(ctx, stage, result); err != nil {
+		p.logger.Error().Err(err).
+			Str("transaction_id", result.TransactionID).
+			Str("stage", string(stage)).
+			Msg("failed to record processed message")
+	}
+}
@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+	"time"
+)
+
+// --- Mock DeadLetterRepository ---
+
+// mockDeadLetterRepository keeps the dead letters of each dead-letter topic.
+type mockDeadLetterRepository struct {
+	deadLetters map[string][]entity.DeadLetter
+	countErr    map[string]error
+	findAllErr  error
+	redriveFunc func(ctx context.Context, deadLetter *entity.DeadLetter) error
+	redriven    []string
+}
+
+func (m *mockDeadLetterRepository) Count(_ context.Context, topic string) (int64, error) {
+	if err := m.countErr[topic]; err != nil {
+		return 0, err
+	}
+	return int64(len(m.deadLetters[topic])), nil
+}
+
+func (m *mockDeadLetterRepository) FindAll(_ context.Context, topic string, limit int) ([]entity.DeadLetter, error) {
+	if m.findAllErr != nil {
+		return nil, m.findAllErr
+	}
+	deadLetters := m.deadLetters[topic]
+	if limit < len(deadLetters) {
+		return deadLetters[:limit], nil
+	}
+	return deadLetters, nil
+}
+
+func (m *mockDeadLetterRepository) FindByOffset(_ context.Context, topic string, partition int32, offset int64) (*entity.DeadLetter, error) {
+	for _, deadLetter := range m.deadLetters[topic] {
+		if deadLetter.Partition == partition && deadLetter.Offset == offset {
+			return &deadLetter, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDeadLetterRepository) Redrive(ctx context.Context, deadLetter *entity.DeadLetter) error {
+	if m.redriveFunc != nil {
+		if err := m.redriveFunc(ctx, deadLetter); err != nil {
+			return err
+		}
+	}
+	m.redriven = append(m.redriven, deadLetter.ID)
+	return nil
+}
+
+func newTransactionDeadLetter(partition int32, offset int64) entity.DeadLetter {
+	return entity.DeadLetter{
+		ID:            entity.DeadLetterID(partition, offset),
+		Partition:     partition,
+		Offset:        offset,
+		TransactionID: "tx-1",
+		SourceTopic:   "Transaction.Created",
+		Payload:       `{"id":"tx-1"}`,
+		Error:         "failed to retrieve rules",
+		Attempts:      3,
+	}
+}
+
+func newFraudScoreDeadLetter(partition int32, offset int64) entity.DeadLetter {
+	return entity.DeadLetter{
+		ID:            entity.DeadLetterID(partition, offset),
+		Partition:     partition,
+		Offset:        offset,
+		TransactionID: "tx-2",
+		SourceTopic:   "FraudSignals.Calculated",
+		Payload:       `{"transaction_id":"tx-2","fraud_score":80}`,
+		Error:         "failed to publish decision result",
+		Attempts:      3,
+	}
+}
+
+var testDeadLetterQueues = []entity.DeadLetterQueue{
+	{Topic: "Transaction.Created.DLQ", SourceTopic: "Transaction.Created"},
+	{Topic: "FraudSignals.Calculated.DLQ", SourceTopic: "FraudSignals.Calculated"},
+}
+
+func newTestRedriveUseCase(repo *mockDeadLetterRepository) (*RedriveDeadLettersUseCase, *[]time.Duration) {
+	uc := NewRedriveDeadLettersUseCase(repo, testDeadLetterQueues)
+	var waits []time.Duration
+	uc.wait = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return uc, &waits
+}
+
+func TestRedriveDeadLettersUseCase_RedrivesSelectedIDs(t *testing.T) {
+	repo := &mockDeadLetterRepository{deadLetters: map[string][]entity.DeadLetter{
+		"Transaction.Created.DLQ": {
+			newTransactionDeadLetter(0, 1),
+			newTransactionDeadLetter(0, 2),
+			newTransactionDeadLetter(1, 1),
+		},
+	}}
+	uc, waits := newTestRedriveUseCase(repo)
+
+	result, err := uc.Execute(context.Background(), RedriveRequest{
+		Topic:         "Transaction.Created.DLQ",
+		IDs:           []string{"0-2", "1-1", "0-2", "0-9", "bad"},
+		RatePerSecond: 4,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(repo.redriven) != 2 || repo.redriven[0] != "0-2" || repo.redriven[1] != "1-1" {
+		t.Errorf("expected 0-2 and 1-1 to be redriven, got %v", repo.redriven)
+	}
+	if result.Selected != 4 || result.Redriven != 2 {
+		t.Errorf("expected 4 selected and 2 redriven, got %d and %d", result.Selected, result.Redriven)
+	}
+	if len(result.Failed) != 2 || result.Failed[0].ID != "0-9" || result.Failed[1].ID != "bad" {
+		t.Errorf("expected 0-9 and bad to fail, got %+v", result.Failed)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 250*time.Millisecond {
+		t.Errorf("expected one 250ms wait between publishes, got %v", *waits)
+	}
+}
+
+func TestRedriveDeadLettersUseCase_EachQueueRedrivesToItsSourceTopic(t *testing.T) {
+	repo := &mockDeadLetterRepository{deadLetters: map[string][]entity.DeadLetter{
+		"Transaction.Created.DLQ": {newTransactionDeadLetter(0, 1)},
+		"FraudSignals.Calculated.DLQ": {
+			newFraudScoreDeadLetter(0, 1),
+			newTransactionDeadLetter(0, 2),
+		},
+	}}
+	uc, _ := newTestRedriveUseCase(repo)
+
+	result, err := uc.Execute(context.Background(), RedriveRequest{Topic: "FraudSignals.Calculated.DLQ", All: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result.SourceTopic != "FraudSignals.Calculated" || result.Redriven != 1 || result.RedrivenIDs[0] != "0-1" {
+		t.Errorf("expected 0-1 to be redriven to FraudSignals.Calculated, got %+v", result)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].ID != "0-2" {
+		t.Errorf("expected the Transaction.Created message to fail, got %+v", result.Failed)
+	}
+
+	result, err = uc.Execute(context.Background(), RedriveRequest{Topic: "Transaction.Created.DLQ", All: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !result.DryRun || result.SourceTopic != "Transaction.Created" || result.Redriven != 1 || len(result.Failed) != 0 {
+		t.Errorf("expected 0-1 to be reported for Transaction.Created, got %+v", result)
+	}
+	if len(repo.redriven) != 1 {
+		t.Errorf("expected the dry run to redrive nothing, got %v", repo.redriven)
+	}
+}
+
+func TestRedriveDeadLettersUseCase_PublishFailureIsReported(t *testing.T) {
+	repo := &mockDeadLetterRepository{
+		deadLetters: map[string][]entity.DeadLetter{"FraudSignals.Calculated.DLQ": {newFraudScoreDeadLetter(0, 1)}},
+		redriveFunc: func(_ context.Context, _ *entity.DeadLetter) error {
+			return errors.New("broker unavailable")
+		},
+	}
+	uc, _ := newTestRedriveUseCase(repo)
+
+	result, err := uc.Execute(context.Background(), RedriveRequest{Topic: "FraudSignals.Calculated.DLQ", All: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result.Redriven != 0 || len(result.Failed) != 1 || result.Failed[0].Error != "broker unavailable" {
+		t.Errorf("expected the publish failure to be reported, got %+v", result)
+	}
+}
+
+func TestRedriveDeadLettersUseCase_InvalidRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      RedriveRequest
+		expected error
+	}{
+		{"unknown topic", RedriveRequest{Topic: "Decision.Calculated.DLQ", All: true}, ErrDeadLetterQueueNotFound},
+		{"no selection", RedriveRequest{Topic: "Transaction.Created.DLQ"}, ErrRedriveSelectionInvalid},
+		{"ids and all", RedriveRequest{Topic: "FraudSignals.Calculated.DLQ", IDs: []string{"0-1"}, All: true}, ErrRedriveSelectionInvalid},
+		{"negative rate", RedriveRequest{Topic: "Transaction.Created.DLQ", All: true, RatePerSecond: -1}, ErrRedriveRateInvalid},
+		{"rate too high", RedriveRequest{Topic: "FraudSignals.Calculated.DLQ", All: true, RatePerSecond: MaxRedriveRate + 1}, ErrRedriveRateInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _ := newTestRedriveUseCase(&mockDeadLetterRepository{})
+			if _, err := uc.Execute(context.Background(), tt.req); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestRedriveDeadLettersUseCase_RetrievalFailure(t *testing.T) {
+	uc, _ := newTestRedriveUseCase(&mockDeadLetterRepository{findAllErr: errors.New("timeout")})
+
+	_, err := uc.Execute(context.Background(), RedriveRequest{Topic: "Transaction.Created.DLQ", All: true})
+	if !errors.Is(err, ErrDeadLetterRetrievalFailed) {
+		t.Errorf("expected ErrDeadLetterRetrievalFailed, got %v", err)
+	}
+}
+
+func TestListDeadLettersUseCase_Limit(t *testing.T) {
+	repo := &mockDeadLetterRepository{deadLetters: map[string][]entity.DeadLetter{
+		"Transaction.Created.DLQ": {newTransactionDeadLetter(0, 1), newTransactionDeadLetter(0, 2)},
+	}}
+	uc := NewListDeadLettersUseCase(repo, testDeadLetterQueues)
+
+	deadLetters, err := uc.Execute(context.Background(), "Transaction.Created.DLQ", 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(deadLetters) != 1 {
+		t.Errorf("expected 1 dead letter, got %d", len(deadLetters))
+	}
+
+	if _, err := uc.Execute(context.Background(), "Transaction.Created.DLQ", MaxDeadLetterListLimit+1); !errors.Is(err, ErrDeadLetterLimitInvalid) {
+		t.Errorf("expected ErrDeadLetterLimitInvalid, got %v", err)
+	}
+}
+
+func TestGetDeadLetterUseCase(t *testing.T) {
+	repo := &mockDeadLetterRepository{deadLetters: map[string][]entity.DeadLetter{
+		"FraudSignals.Calculated.DLQ": {newFraudScoreDeadLetter(2, 5)},
+	}}
+	uc := NewGetDeadLetterUseCase(repo, testDeadLetterQueues)
+
+	deadLetter, err := uc.Execute(context.Background(), "FraudSignals.Calculated.DLQ", "2-5")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if deadLetter.ID != "2-5" || deadLetter.TransactionID != "tx-2" {
+		t.Errorf("expected dead letter 2-5 of tx-2, got %+v", deadLetter)
+	}
+
+	if _, err := uc.Execute(context.Background(), "Transaction.Created.DLQ", "2-5"); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("expected ErrDeadLetterNotFound in the other queue, got %v", err)
+	}
+	if _, err := uc.Execute(context.Background(), "FraudSignals.Calculated.DLQ", "x"); !errors.Is(err, entity.ErrDeadLetterIDInvalid) {
+		t.Errorf("expected ErrDeadLetterIDInvalid, got %v", err)
+	}
+}
+
+func TestGetDeadLetterQueuesUseCase(t *testing.T) {
+	repo := &mockDeadLetterRepository{deadLetters: map[string][]entity.DeadLetter{
+		"Transaction.Created.DLQ":     {newTransactionDeadLetter(0, 1), newTransactionDeadLetter(0, 2)},
+		"FraudSignals.Calculated.DLQ": {newFraudScoreDeadLetter(0, 1)},
+	}}
+	uc := NewGetDeadLetterQueuesUseCase(repo, testDeadLetterQueues)
+
+	queues, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(queues) != 2 ||
+		queues[0].SourceTopic != "Transaction.Created" || queues[0].Count != 2 ||
+		queues[1].SourceTopic != "FraudSignals.Calculated" || queues[1].Count != 1 {
+		t.Errorf("expected both queues with their own counts, got %+v", queues)
+	}
+
+	repo.countErr = map[string]error{"FraudSignals.Calculated.DLQ": errors.New("broker unavailable")}
+	if _, err := uc.Execute(context.Background()); !errors.Is(err, ErrDeadLetterRetrievalFailed) {
+		t.Errorf("expected ErrDeadLetterRetrievalFailed, got %v", err)
+	}
+}
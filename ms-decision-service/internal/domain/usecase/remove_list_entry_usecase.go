@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// RemoveListEntryUseCase removes a single entry from a managed list.
+type RemoveListEntryUseCase struct {
+	listRepo repository.ListRepository
+}
+
+// NewRemoveListEntryUseCase creates a new use case with the given repository.
+func NewRemoveListEntryUseCase(
+	listRepo repository.ListRepository,
+) *RemoveListEntryUseCase {
+	return &RemoveListEntryUseCase{
+		listRepo: listRepo,
+	}
+}
+
+// Execute removes the entry with the given value from the list. The value is
+// normalized for the list's field before lookup.
+func (uc *RemoveListEntryUseCase) Execute(
+	ctx context.Context,
+	name string,
+	value string,
+) error {
+	list, err := findExistingList(ctx, uc.listRepo, name)
+	if err != nil {
+		return err
+	}
+
+	value = entity.NormalizeListValue(list.Field, value)
+
+	entries, err := uc.listRepo.FindEntries(ctx, name)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrListRetrievalFailed, err)
+	}
+
+	found := false
+	for _, entry := range entries {
+		if entry.Value == value {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: %s", ErrListEntryNotFound, value)
+	}
+
+	if err := uc.listRepo.DeleteEntry(ctx, name, value); err != nil {
+		return fmt.Errorf("%w: %w", ErrListPersistenceFailed, err)
+	}
+
+	return nil
+}
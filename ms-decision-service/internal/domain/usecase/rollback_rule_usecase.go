@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// RollbackRuleUseCase restores a single rule to one of its previous versions.
+type RollbackRuleUseCase struct {
+	ruleRepo    repository.RuleRepository
+	historyRepo repository.RuleHistoryRepository
+	recorder    *ruleChangeRecorder
+}
+
+// NewRollbackRuleUseCase creates a new use case with the given repositories.
+func NewRollbackRuleUseCase(
+	ruleRepo repository.RuleRepository,
+	historyRepo repository.RuleHistoryRepository,
+) *RollbackRuleUseCase {
+	return &RollbackRuleUseCase{
+		ruleRepo:    ruleRepo,
+		historyRepo: historyRepo,
+		recorder:    &ruleChangeRecorder{ruleRepo: ruleRepo, historyRepo: historyRepo},
+	}
+}
+
+// Execute restores the rule to the state recorded in the given version and records
+// the rollback as a new version. Restoring a deleted version deletes the rule, and
+// restoring a version of a deleted rule recreates it. When the rule already matches
+// the requested version, the latest recorded version is returned unchanged.
+func (uc *RollbackRuleUseCase) Execute(
+	ctx context.Context,
+	ruleID string,
+	version int,
+	actor string,
+) (*entity.RuleVersion, error) {
+	if ruleID == "" {
+		return nil, ErrRuleIDEmpty
+	}
+
+	versions, err := uc.historyRepo.FindByRuleID(ctx, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRuleHistoryRetrievalFailed, err)
+	}
+
+	var target *entity.RuleVersion
+	for i := range versions {
+		if versions[i].Version == version {
+			target = &versions[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, fmt.Errorf("%w: %s version %d", ErrRuleVersionNotFound, ruleID, version)
+	}
+
+	current, err := uc.ruleRepo.FindByID(ctx, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRuleRetrievalFailed, err)
+	}
+
+	change := restoreChange(current, target, actor)
+	if change == nil {
+		return &versions[len(versions)-1], nil
+	}
+
+	if change.after != nil {
+		if err := ensurePriorityAvailable(ctx, uc.ruleRepo, change.after); err != nil {
+			return nil, err
+		}
+	}
+
+	versions, err = uc.recorder.apply(ctx, *change)
+	if err != nil {
+		return nil, err
+	}
+
+	return &versions[0], nil
+}
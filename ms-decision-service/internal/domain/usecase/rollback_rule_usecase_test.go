@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+)
+
+func TestRollbackRuleUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	// setup creates a rule, changes its decision and returns the repositories.
+	setup := func(t *testing.T) (*mockRuleRepository, *mockRuleHistoryRepository, string) {
+		t.Helper()
+		ruleRepo, historyRepo := newInMemoryRuleRepository()
+
+		created, err := NewCreateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, newRuleDefinition(), "alice")
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		update := newRuleDefinition()
+		update.ResultStatus = entity.FRAUDCHECK
+		if _, err := NewUpdateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, created.RuleID, update, "bob"); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+
+		return ruleRepo, historyRepo, created.RuleID
+	}
+
+	t.Run("restores a previous definition as a new version", func(t *testing.T) {
+		ruleRepo, historyRepo, ruleID := setup(t)
+
+		version, err := NewRollbackRuleUseCase(ruleRepo, historyRepo).Execute(ctx, ruleID, 1, "carol")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if version.Version != 3 || version.ChangeType != entity.RuleRolledBack || version.RestoredVersion != 1 {
+			t.Errorf("unexpected rollback version: %+v", version)
+		}
+		rule, _ := ruleRepo.FindByID(ctx, ruleID)
+		if rule.ResultStatus != entity.DECLINED || rule.Version != 3 {
+			t.Errorf("expected DECLINED rule at version 3, got %+v", rule)
+		}
+	})
+
+	t.Run("recreates a deleted rule", func(t *testing.T) {
+		ruleRepo, historyRepo, ruleID := setup(t)
+		if err := NewDeleteRuleUseCase(ruleRepo, historyRepo).Execute(ctx, ruleID, "bob"); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+
+		if _, err := NewRollbackRuleUseCase(ruleRepo, historyRepo).Execute(ctx, ruleID, 2, "carol"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		rule, _ := ruleRepo.FindByID(ctx, ruleID)
+		if rule == nil || rule.ResultStatus != entity.FRAUDCHECK || rule.Version != 4 {
+			t.Errorf("expected rule to be recreated at version 4, got %+v", rule)
+		}
+	})
+
+	t.Run("rolling back to the current state records nothing", func(t *testing.T) {
+		ruleRepo, historyRepo, ruleID := setup(t)
+
+		version, err := NewRollbackRuleUseCase(ruleRepo, historyRepo).Execute(ctx, ruleID, 2, "carol")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if version.Version != 2 || len(historyRepo.versions) != 2 {
+			t.Errorf("expected no new version, got %+v", historyRepo.versions)
+		}
+	})
+
+	t.Run("unknown version returns ErrRuleVersionNotFound", func(t *testing.T) {
+		ruleRepo, historyRepo, ruleID := setup(t)
+
+		_, err := NewRollbackRuleUseCase(ruleRepo, historyRepo).Execute(ctx, ruleID, 9, "carol")
+		if !errors.Is(err, ErrRuleVersionNotFound) {
+			t.Fatalf("expected ErrRuleVersionNotFound, got %v", err)
+		}
+	})
+
+	t.Run("restored priority taken by another rule returns ErrDuplicatePriority", func(t *testing.T) {
+		ruleRepo, historyRepo, ruleID := setup(t)
+		moved := newRuleDefinition()
+		moved.Priority = 7
+		if _, err := NewUpdateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, ruleID, moved, "bob"); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		other := newRuleDefinition()
+		other.RuleName = "Other"
+		if _, err := NewCreateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, other, "bob"); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+
+		_, err := NewRollbackRuleUseCase(ruleRepo, historyRepo).Execute(ctx, ruleID, 1, "carol")
+		if !errors.Is(err, ErrDuplicatePriority) {
+			t.Fatalf("expected ErrDuplicatePriority, got %v", err)
+		}
+	})
+}
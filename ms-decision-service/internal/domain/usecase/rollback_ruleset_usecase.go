@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+	"sort"
+)
+
+// RollbackRulesetUseCase restores every rule to the state it had at a previous ruleset version.
+type RollbackRulesetUseCase struct {
+	ruleRepo    repository.RuleRepository
+	historyRepo repository.RuleHistoryRepository
+	recorder    *ruleChangeRecorder
+}
+
+// NewRollbackRulesetUseCase creates a new use case with the given repositories.
+func NewRollbackRulesetUseCase(
+	ruleRepo repository.RuleRepository,
+	historyRepo repository.RuleHistoryRepository,
+) *RollbackRulesetUseCase {
+	return &RollbackRulesetUseCase{
+		ruleRepo:    ruleRepo,
+		historyRepo: historyRepo,
+		recorder:    &ruleChangeRecorder{ruleRepo: ruleRepo, historyRepo: historyRepo},
+	}
+}
+
+// Execute brings every rule with recorded history back to its state at the given
+// ruleset version: rules created afterwards are deleted, deleted rules are recreated
+// and changed rules are restored. Rules that were never changed are left untouched.
+// All rules are restored in one atomic write under one new ruleset version, so a
+// failure leaves every rule unchanged. A rollback that would change more than
+// entity.MaxRuleChanges rules fails validation without writing anything. The
+// returned slice holds the versions recorded by the rollback and is empty when
+// nothing had to change.
+func (uc *RollbackRulesetUseCase) Execute(
+	ctx context.Context,
+	rulesetVersion int,
+	actor string,
+) ([]entity.RuleVersion, error) {
+	current, err := uc.historyRepo.CurrentRulesetVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRuleHistoryRetrievalFailed, err)
+	}
+	if rulesetVersion < 0 || rulesetVersion > current {
+		return nil, fmt.Errorf("%w: %d", ErrRulesetVersionNotFound, rulesetVersion)
+	}
+
+	history, err := uc.historyRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRuleHistoryRetrievalFailed, err)
+	}
+
+	rules, err := uc.ruleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRuleRetrievalFailed, err)
+	}
+
+	changes := planRulesetRollback(rulesetVersion, history, rules, actor)
+	if len(changes) == 0 {
+		return []entity.RuleVersion{}, nil
+	}
+	if len(changes) > entity.MaxRuleChanges {
+		return nil, &ValidationError{Err: ErrRuleValidationFailed, Violations: []entity.RuleViolation{{
+			Field:   "ruleset_version",
+			Message: fmt.Sprintf("restoring it would change %d rules, but at most %d can be changed at once", len(changes), entity.MaxRuleChanges),
+		}}}
+	}
+
+	if err := ensureUniquePriorities(rules, changes); err != nil {
+		return nil, err
+	}
+
+	return uc.recorder.apply(ctx, changes...)
+}
+
+// planRulesetRollback returns the changes that restore every rule found in history
+// to its latest version at or before rulesetVersion, ordered by rule ID.
+func planRulesetRollback(
+	rulesetVersion int,
+	history []entity.RuleVersion,
+	rules []entity.Rule,
+	actor string,
+) []ruleChange {
+	targets := make(map[string]*entity.RuleVersion)
+	for i := range history {
+		v := &history[i]
+		if _, ok := targets[v.RuleID]; !ok {
+			targets[v.RuleID] = nil
+		}
+		if v.RulesetVersion <= rulesetVersion {
+			if latest := targets[v.RuleID]; latest == nil || v.Version > latest.Version {
+				targets[v.RuleID] = v
+			}
+		}
+	}
+
+	currentByID := make(map[string]*entity.Rule, len(rules))
+	for i := range rules {
+		currentByID[rules[i].RuleID] = &rules[i]
+	}
+
+	ruleIDs := make([]string, 0, len(targets))
+	for ruleID := range targets {
+		ruleIDs = append(ruleIDs, ruleID)
+	}
+	sort.Strings(ruleIDs)
+
+	var changes []ruleChange
+	for _, ruleID := range ruleIDs {
+		if change := restoreChange(currentByID[ruleID], targets[ruleID], actor); change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	return changes
+}
+
+// ensureUniquePriorities fails with ErrDuplicatePriority when applying the changes
+// to the current rules would leave two rules with the same priority.
+func ensureUniquePriorities(rules []entity.Rule, changes []ruleChange) error {
+	final := make(map[string]int, len(rules))
+	for _, r := range rules {
+		final[r.RuleID] = r.Priority
+	}
+	for _, change := range changes {
+		if change.after == nil {
+			delete(final, change.before.RuleID)
+			continue
+		}
+		final[change.after.RuleID] = change.after.Priority
+	}
+
+	owners := make(map[int]string, len(final))
+	for ruleID, priority := range final {
+		if other, ok := owners[priority]; ok {
+			return fmt.Errorf("%w: priority %d would be used by rules %s and %s", ErrDuplicatePriority, priority, other, ruleID)
+		}
+		owners[priority] = ruleID
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+)
+
+func TestRollbackRulesetUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("restores every tracked rule to the target ruleset version", func(t *testing.T) {
+		untouched := entity.Rule{RuleID: "rule-seed", RuleName: "Seeded", Priority: 50, ResultStatus: entity.APPROVED, IsActive: true}
+		ruleRepo, historyRepo := newInMemoryRuleRepository(untouched)
+
+		// ruleset 1: create A
+		a := newRuleDefinition()
+		a.RuleID = "rule-a"
+		if _, err := NewCreateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, a, "alice"); err != nil {
+			t.Fatalf("create a: %v", err)
+		}
+		// ruleset 2: disable A
+		if _, err := NewSetRuleActiveUseCase(ruleRepo, historyRepo).Execute(ctx, "rule-a", false, "bob"); err != nil {
+			t.Fatalf("disable a: %v", err)
+		}
+		// ruleset 3: create B
+		b := newRuleDefinition()
+		b.RuleID = "rule-b"
+		b.Priority = 2
+		if _, err := NewCreateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, b, "bob"); err != nil {
+			t.Fatalf("create b: %v", err)
+		}
+
+		applied, err := NewRollbackRulesetUseCase(ruleRepo, historyRepo).Execute(ctx, 1, "carol")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(applied) != 2 {
+			t.Fatalf("expected 2 restored rules, got %+v", applied)
+		}
+		for _, v := range applied {
+			if v.RulesetVersion != 4 || v.ChangeType != entity.RuleRolledBack {
+				t.Errorf("expected rollback at ruleset version 4, got %+v", v)
+			}
+		}
+
+		if ruleA, _ := ruleRepo.FindByID(ctx, "rule-a"); ruleA == nil || !ruleA.IsActive {
+			t.Errorf("expected rule-a to be active again, got %+v", ruleA)
+		}
+		if ruleB, _ := ruleRepo.FindByID(ctx, "rule-b"); ruleB != nil {
+			t.Errorf("expected rule-b to be deleted, got %+v", ruleB)
+		}
+		if seed, _ := ruleRepo.FindByID(ctx, "rule-seed"); seed == nil || seed.Version != 0 {
+			t.Errorf("expected untracked rule to be left untouched, got %+v", seed)
+		}
+	})
+
+	t.Run("a failed rollback leaves every rule unchanged", func(t *testing.T) {
+		ruleRepo, historyRepo := newInMemoryRuleRepository()
+		for i, id := range []string{"rule-a", "rule-b"} {
+			rule := newRuleDefinition()
+			rule.RuleID = id
+			rule.Priority = i + 1
+			if _, err := NewCreateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, rule, "alice"); err != nil {
+				t.Fatalf("create %s: %v", id, err)
+			}
+		}
+
+		var sets []*entity.RuleChangeSet
+		ruleRepo.applyFunc = func(_ context.Context, set *entity.RuleChangeSet) error {
+			sets = append(sets, set)
+			return errors.New("dynamo timeout")
+		}
+
+		if _, err := NewRollbackRulesetUseCase(ruleRepo, historyRepo).Execute(ctx, 0, "carol"); !errors.Is(err, ErrRulePersistenceFailed) {
+			t.Fatalf("expected ErrRulePersistenceFailed, got %v", err)
+		}
+		if len(sets) != 1 || len(sets[0].Changes) != 2 {
+			t.Fatalf("expected both rules in one change set, got %+v", sets)
+		}
+		if len(ruleRepo.rules) != 2 || historyRepo.rulesetVersion != 2 {
+			t.Errorf("expected both rules and the ruleset version to be kept, got %v at %d", ruleRepo.rules, historyRepo.rulesetVersion)
+		}
+	})
+
+	t.Run("rolling back to the current version changes nothing", func(t *testing.T) {
+		ruleRepo, historyRepo := newInMemoryRuleRepository()
+		if _, err := NewCreateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, newRuleDefinition(), "alice"); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+
+		applied, err := NewRollbackRulesetUseCase(ruleRepo, historyRepo).Execute(ctx, 1, "carol")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(applied) != 0 || historyRepo.rulesetVersion != 1 {
+			t.Errorf("expected no changes, got %+v", applied)
+		}
+	})
+
+	t.Run("future ruleset version returns ErrRulesetVersionNotFound", func(t *testing.T) {
+		ruleRepo, historyRepo := newInMemoryRuleRepository()
+		historyRepo.rulesetVersion = 3
+		uc := NewRollbackRulesetUseCase(ruleRepo, historyRepo)
+
+		if _, err := uc.Execute(ctx, 4, "carol"); !errors.Is(err, ErrRulesetVersionNotFound) {
+			t.Fatalf("expected ErrRulesetVersionNotFound, got %v", err)
+		}
+	})
+
+	t.Run("restored state with clashing priorities returns ErrDuplicatePriority", func(t *testing.T) {
+		ruleRepo, historyRepo := newInMemoryRuleRepository()
+
+		a := newRuleDefinition()
+		a.RuleID = "rule-a"
+		if _, err := NewCreateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, a, "alice"); err != nil {
+			t.Fatalf("create a: %v", err)
+		}
+		moved := newRuleDefinition()
+		moved.Priority = 9
+		if _, err := NewUpdateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, "rule-a", moved, "bob"); err != nil {
+			t.Fatalf("move a: %v", err)
+		}
+		// A rule created outside the rules API takes over the old priority.
+		ruleRepo.rules["rule-manual"] = entity.Rule{RuleID: "rule-manual", Priority: 1}
+
+		_, err := NewRollbackRulesetUseCase(ruleRepo, historyRepo).Execute(ctx, 1, "carol")
+		if !errors.Is(err, ErrDuplicatePriority) {
+			t.Fatalf("expected ErrDuplicatePriority, got %v", err)
+		}
+	})
+
+	t.Run("a rollback changing more rules than fit in one write fails validation", func(t *testing.T) {
+		for _, count := range []int{entity.MaxRuleChanges, entity.MaxRuleChanges + 1} {
+			ruleRepo, historyRepo := newInMemoryRuleRepository()
+			for i := range count {
+				rule := newRuleDefinition()
+				rule.RuleID = fmt.Sprintf("rule-%03d", i)
+				rule.Priority = i + 1
+				if _, err := NewCreateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, rule, "alice"); err != nil {
+					t.Fatalf("create %s: %v", rule.RuleID, err)
+				}
+			}
+
+			applied, err := NewRollbackRulesetUseCase(ruleRepo, historyRepo).Execute(ctx, 0, "carol")
+			if count <= entity.MaxRuleChanges {
+				if err != nil || len(applied) != count {
+					t.Errorf("expected %d rules to be rolled back, got %d, %v", count, len(applied), err)
+				}
+				continue
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || !errors.Is(err, ErrRuleValidationFailed) || validationErr.Violations[0].Field != "ruleset_version" {
+				t.Fatalf("expected a ruleset_version validation error, got %v", err)
+			}
+			if rules, _ := ruleRepo.FindAll(ctx); len(rules) != count {
+				t.Errorf("expected the %d rules to be left in place, got %d", count, len(rules))
+			}
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// GetRuleCacheStatusUseCase reports the ruleset version and age of the cached rules.
+type GetRuleCacheStatusUseCase struct {
+	ruleCache repository.RuleCache
+}
+
+// NewGetRuleCacheStatusUseCase creates a new use case with the given rule cache.
+func NewGetRuleCacheStatusUseCase(
+	ruleCache repository.RuleCache,
+) *GetRuleCacheStatusUseCase {
+	return &GetRuleCacheStatusUseCase{
+		ruleCache: ruleCache,
+	}
+}
+
+// Execute returns the current status of the rule cache.
+func (uc *GetRuleCacheStatusUseCase) Execute() entity.RuleCacheStatus {
+	return uc.ruleCache.Status()
+}
+
+// RefreshRuleCacheUseCase forces the rule cache to reload the active rules.
+type RefreshRuleCacheUseCase struct {
+	ruleCache repository.RuleCache
+}
+
+// NewRefreshRuleCacheUseCase creates a new use case with the given rule cache.
+func NewRefreshRuleCacheUseCase(
+	ruleCache repository.RuleCache,
+) *RefreshRuleCacheUseCase {
+	return &RefreshRuleCacheUseCase{
+		ruleCache: ruleCache,
+	}
+}
+
+// Execute reloads the rule cache and returns its status after the reload.
+func (uc *RefreshRuleCacheUseCase) Execute(
+	ctx context.Context,
+) (entity.RuleCacheStatus, error) {
+	if err := uc.ruleCache.Refresh(ctx); err != nil {
+		return uc.ruleCache.Status(), fmt.Errorf("%w: %w", ErrRuleCacheRefreshFailed, err)
+	}
+
+	return uc.ruleCache.Status(), nil
+}
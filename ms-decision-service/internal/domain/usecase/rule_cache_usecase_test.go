@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+)
+
+// --- Hand-written mock ---
+
+type mockRuleCache struct {
+	refreshFunc func(ctx context.Context) error
+	status      entity.RuleCacheStatus
+}
+
+func (m *mockRuleCache) Refresh(ctx context.Context) error {
+	if m.refreshFunc != nil {
+		return m.refreshFunc(ctx)
+	}
+	return nil
+}
+
+func (m *mockRuleCache) Status() entity.RuleCacheStatus {
+	return m.status
+}
+
+func TestGetRuleCacheStatusUseCase_Execute(t *testing.T) {
+	cache := &mockRuleCache{status: entity.RuleCacheStatus{RulesetVersion: 7, RuleCount: 3}}
+	uc := NewGetRuleCacheStatusUseCase(cache)
+
+	status := uc.Execute()
+
+	if status.RulesetVersion != 7 || status.RuleCount != 3 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestRefreshRuleCacheUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name    string
+		cache   *mockRuleCache
+		wantErr error
+	}{
+		{
+			name:  "successful refresh returns status",
+			cache: &mockRuleCache{status: entity.RuleCacheStatus{RulesetVersion: 4}},
+		},
+		{
+			name: "refresh error returns ErrRuleCacheRefreshFailed",
+			cache: &mockRuleCache{
+				refreshFunc: func(_ context.Context) error {
+					return errors.New("dynamo unavailable")
+				},
+				status: entity.RuleCacheStatus{RulesetVersion: 4, Stale: true},
+			},
+			wantErr: ErrRuleCacheRefreshFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewRefreshRuleCacheUseCase(tt.cache)
+
+			status, err := uc.Execute(context.Background())
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if status.RulesetVersion != tt.cache.status.RulesetVersion {
+				t.Errorf("expected ruleset version %d, got %d", tt.cache.status.RulesetVersion, status.RulesetVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+	"time"
+)
+
+// systemActor is recorded as the author of baseline versions.
+const systemActor = "system"
+
+// ruleChange describes a single change to a rule. before is nil when the rule is
+// created and after is nil when it is deleted.
+type ruleChange struct {
+	before          *entity.Rule
+	after           *entity.Rule
+	changeType      entity.RuleChangeType
+	actor           string
+	restoredVersion int
+}
+
+// maxRuleChangeAttempts bounds how often a set of changes is rebuilt on top of a
+// ruleset version advanced by a concurrent change.
+const maxRuleChangeAttempts = 3
+
+// ruleChangeRecorder writes rule changes and records each of them as an immutable
+// version in the rule history.
+type ruleChangeRecorder struct {
+	ruleRepo    repository.RuleRepository
+	historyRepo repository.RuleHistoryRepository
+}
+
+// apply writes the changes and their versions atomically under one new ruleset
+// version, and returns the recorded versions in the order of the changes. When a
+// concurrent change advanced the ruleset version first, the changes are applied on
+// top of it; when it changed one of the rules, ErrRuleConflict is returned and
+// nothing is written.
+func (r *ruleChangeRecorder) apply(ctx context.Context, changes ...ruleChange) ([]entity.RuleVersion, error) {
+	for attempt := 1; ; attempt++ {
+		set, err := r.changeSet(ctx, changes)
+		if err != nil {
+			return nil, err
+		}
+
+		err = r.ruleRepo.ApplyChanges(ctx, set)
+		if err == nil {
+			versions := make([]entity.RuleVersion, len(set.Changes))
+			for i := range set.Changes {
+				versions[i] = set.Changes[i].Version
+			}
+			return versions, nil
+		}
+
+		if !errors.Is(err, entity.ErrRuleChangeConflict) {
+			return nil, fmt.Errorf("%w: %w", ErrRulePersistenceFailed, err)
+		}
+		if attempt == maxRuleChangeAttempts {
+			return nil, fmt.Errorf("%w: %w", ErrRuleConflict, err)
+		}
+	}
+}
+
+// changeSet builds the change set producing the ruleset version that follows the
+// current one.
+func (r *ruleChangeRecorder) changeSet(ctx context.Context, changes []ruleChange) (*entity.RuleChangeSet, error) {
+	current, err := r.historyRepo.CurrentRulesetVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRuleHistoryRetrievalFailed, err)
+	}
+
+	set := &entity.RuleChangeSet{RulesetVersion: current + 1}
+	changedAt := time.Now().UTC()
+	for _, change := range changes {
+		c, err := r.ruleChange(ctx, change, set.RulesetVersion, changedAt)
+		if err != nil {
+			return nil, err
+		}
+		set.Changes = append(set.Changes, c)
+	}
+
+	return set, nil
+}
+
+// ruleChange numbers the change after the latest recorded version of the rule.
+// Rules that predate the history get a baseline version recorded first, so the
+// state they had before their first tracked change can be restored later.
+func (r *ruleChangeRecorder) ruleChange(
+	ctx context.Context,
+	change ruleChange,
+	rulesetVersion int,
+	changedAt time.Time,
+) (entity.RuleChange, error) {
+	ruleID := ""
+	if change.before != nil {
+		ruleID = change.before.RuleID
+	} else {
+		ruleID = change.after.RuleID
+	}
+
+	versions, err := r.historyRepo.FindByRuleID(ctx, ruleID)
+	if err != nil {
+		return entity.RuleChange{}, fmt.Errorf("%w: %w", ErrRuleHistoryRetrievalFailed, err)
+	}
+
+	latest := 0
+	if len(versions) > 0 {
+		latest = versions[len(versions)-1].Version
+	}
+	if change.before != nil && change.before.Version > latest {
+		latest = change.before.Version
+	}
+
+	result := entity.RuleChange{Before: change.before}
+	if latest == 0 && change.before != nil {
+		result.Baseline = &entity.RuleVersion{
+			RuleID:     ruleID,
+			Version:    1,
+			ChangeType: entity.RuleBaseline,
+			ChangedBy:  systemActor,
+			ChangedAt:  changedAt,
+			Rule:       *change.before,
+		}
+		result.Baseline.Rule.Version = 1
+		latest = 1
+	}
+
+	version := entity.RuleVersion{
+		RuleID:          ruleID,
+		Version:         latest + 1,
+		RulesetVersion:  rulesetVersion,
+		ChangeType:      change.changeType,
+		ChangedBy:       change.actor,
+		ChangedAt:       changedAt,
+		RestoredVersion: change.restoredVersion,
+		Changes:         entity.DiffRules(change.before, change.after),
+	}
+	if change.after == nil {
+		version.Deleted = true
+		version.Rule = *change.before
+	} else {
+		change.after.Version = version.Version
+		version.Rule = *change.after
+	}
+	result.Version = version
+
+	return result, nil
+}
+
+// restoreChange builds the change that brings current back to the state recorded
+// in target. Returns nil when current already matches target.
+func restoreChange(current *entity.Rule, target *entity.RuleVersion, actor string) *ruleChange {
+	change := &ruleChange{
+		before:     current,
+		changeType: entity.RuleRolledBack,
+		actor:      actor,
+	}
+
+	if target != nil {
+		change.restoredVersion = target.Version
+		if !target.Deleted {
+			restored := target.Rule
+			change.after = &restored
+		}
+	}
+
+	if change.before == nil && change.after == nil {
+		return nil
+	}
+	if change.before != nil && change.after != nil && len(entity.DiffRules(change.before, change.after)) == 0 {
+		return nil
+	}
+
+	return change
+}
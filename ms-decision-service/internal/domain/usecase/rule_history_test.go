@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"sort"
+	"testing"
+)
+
+// newInMemoryRuleRepository returns a mockRuleRepository backed by a map and the
+// rule history it writes to, so that changes made by one use case are visible to
+// the next. Like DynamoDB, it rejects a change set made from a stale ruleset
+// version or a stale rule.
+func newInMemoryRuleRepository(rules ...entity.Rule) (*mockRuleRepository, *mockRuleHistoryRepository) {
+	store := make(map[string]entity.Rule)
+	for _, r := range rules {
+		store[r.RuleID] = r
+	}
+	history := &mockRuleHistoryRepository{}
+
+	ruleRepo := &mockRuleRepository{
+		rules:   store,
+		history: history,
+		findAllFunc: func(_ context.Context) ([]entity.Rule, error) {
+			all := make([]entity.Rule, 0, len(store))
+			for _, r := range store {
+				all = append(all, r)
+			}
+			sort.Slice(all, func(i, j int) bool { return all[i].Priority < all[j].Priority })
+			return all, nil
+		},
+		findByIDFunc: func(_ context.Context, ruleID string) (*entity.Rule, error) {
+			r, ok := store[ruleID]
+			if !ok {
+				return nil, nil
+			}
+			return &r, nil
+		},
+		applyFunc: func(_ context.Context, set *entity.RuleChangeSet) error {
+			if set.RulesetVersion != history.rulesetVersion+1 {
+				return entity.ErrRuleChangeConflict
+			}
+			for _, change := range set.Changes {
+				stored, ok := store[change.Version.RuleID]
+				if ok != (change.Before != nil) || (ok && stored.Version != change.Before.Version) {
+					return entity.ErrRuleChangeConflict
+				}
+			}
+			for _, change := range set.Changes {
+				if change.Version.Deleted {
+					delete(store, change.Version.RuleID)
+				} else {
+					store[change.Version.RuleID] = change.Version.Rule
+				}
+			}
+			return nil
+		},
+	}
+
+	return ruleRepo, history
+}
+
+func TestRuleChanges_RecordHistory(t *testing.T) {
+	ctx := context.Background()
+	ruleRepo, historyRepo := newInMemoryRuleRepository()
+
+	created, err := NewCreateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, newRuleDefinition(), "alice")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	update := newRuleDefinition()
+	update.ResultStatus = entity.FRAUDCHECK
+	if _, err := NewUpdateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, created.RuleID, update, "bob"); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if _, err := NewSetRuleActiveUseCase(ruleRepo, historyRepo).Execute(ctx, created.RuleID, false, "bob"); err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+
+	if err := NewDeleteRuleUseCase(ruleRepo, historyRepo).Execute(ctx, created.RuleID, "carol"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	history, err := NewGetRuleHistoryUseCase(ruleRepo, historyRepo).Execute(ctx, created.RuleID)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+
+	want := []struct {
+		changeType     entity.RuleChangeType
+		changedBy      string
+		rulesetVersion int
+	}{
+		{entity.RuleCreated, "alice", 1},
+		{entity.RuleUpdated, "bob", 2},
+		{entity.RuleDeactivated, "bob", 3},
+		{entity.RuleDeleted, "carol", 4},
+	}
+	if len(history) != len(want) {
+		t.Fatalf("expected %d versions, got %d: %+v", len(want), len(history), history)
+	}
+	for i, w := range want {
+		v := history[i]
+		if v.Version != i+1 || v.ChangeType != w.changeType || v.ChangedBy != w.changedBy || v.RulesetVersion != w.rulesetVersion {
+			t.Errorf("version[%d] = {version %d, %s by %s, ruleset %d}, want {version %d, %s by %s, ruleset %d}",
+				i, v.Version, v.ChangeType, v.ChangedBy, v.RulesetVersion, i+1, w.changeType, w.changedBy, w.rulesetVersion)
+		}
+	}
+
+	if diff := history[1].Changes; len(diff) != 1 || diff[0] != (entity.RuleFieldChange{Field: "result_status", Before: "DECLINED", After: "FRAUD_CHECK"}) {
+		t.Errorf("unexpected update diff: %+v", diff)
+	}
+	if !history[3].Deleted || history[3].Rule.RuleName != "Block CRYPTO payments" {
+		t.Errorf("expected deleted version to keep the removed definition, got %+v", history[3])
+	}
+}
+
+func TestRuleChanges_BaselineRecordedForUntrackedRule(t *testing.T) {
+	ctx := context.Background()
+	seeded := entity.Rule{
+		RuleID:            "rule-001",
+		RuleName:          "Block CRYPTO payments",
+		ConditionField:    entity.FieldPaymentMethod,
+		ConditionOperator: entity.OpEqual,
+		ConditionValue:    "CRYPTO",
+		ResultStatus:      entity.DECLINED,
+		Priority:          1,
+		IsActive:          true,
+	}
+	ruleRepo, historyRepo := newInMemoryRuleRepository(seeded)
+
+	rule, err := NewSetRuleActiveUseCase(ruleRepo, historyRepo).Execute(ctx, "rule-001", false, "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.Version != 2 {
+		t.Errorf("expected rule version 2, got %d", rule.Version)
+	}
+
+	if len(historyRepo.versions) != 2 {
+		t.Fatalf("expected baseline and change versions, got %+v", historyRepo.versions)
+	}
+	baseline := historyRepo.versions[0]
+	if baseline.ChangeType != entity.RuleBaseline || baseline.Version != 1 || baseline.RulesetVersion != 0 || !baseline.Rule.IsActive {
+		t.Errorf("unexpected baseline version: %+v", baseline)
+	}
+}
+
+func TestRuleChanges_HistoryFailures(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ruleset version read failure returns ErrRuleHistoryRetrievalFailed", func(t *testing.T) {
+		ruleRepo, historyRepo := newInMemoryRuleRepository()
+		historyRepo.rulesetErr = errors.New("dynamo timeout")
+		uc := NewCreateRuleUseCase(ruleRepo, historyRepo, nil)
+
+		if _, err := uc.Execute(ctx, newRuleDefinition(), "alice"); !errors.Is(err, ErrRuleHistoryRetrievalFailed) {
+			t.Fatalf("expected ErrRuleHistoryRetrievalFailed, got %v", err)
+		}
+	})
+
+	t.Run("write failure returns ErrRulePersistenceFailed and records nothing", func(t *testing.T) {
+		ruleRepo, historyRepo := newInMemoryRuleRepository()
+		ruleRepo.applyFunc = func(_ context.Context, _ *entity.RuleChangeSet) error {
+			return errors.New("dynamo timeout")
+		}
+		uc := NewCreateRuleUseCase(ruleRepo, historyRepo, nil)
+
+		if _, err := uc.Execute(ctx, newRuleDefinition(), "alice"); !errors.Is(err, ErrRulePersistenceFailed) {
+			t.Fatalf("expected ErrRulePersistenceFailed, got %v", err)
+		}
+		if len(historyRepo.versions) != 0 || len(ruleRepo.rules) != 0 {
+			t.Errorf("expected nothing to be written, got rules %v and history %+v", ruleRepo.rules, historyRepo.versions)
+		}
+	})
+}
+
+func TestRuleChanges_ConcurrentChanges(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("a ruleset version advanced by another rule is retried", func(t *testing.T) {
+		ruleRepo, historyRepo := newInMemoryRuleRepository()
+		apply := ruleRepo.applyFunc
+		calls := 0
+		ruleRepo.applyFunc = func(ctx context.Context, set *entity.RuleChangeSet) error {
+			calls++
+			if calls == 1 {
+				// Another instance changes a different rule first.
+				historyRepo.rulesetVersion++
+			}
+			return apply(ctx, set)
+		}
+
+		if _, err := NewCreateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, newRuleDefinition(), "alice"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != 2 || len(historyRepo.versions) != 1 || historyRepo.versions[0].RulesetVersion != 2 {
+			t.Errorf("expected the change at ruleset version 2 after one retry, got %d calls and %+v", calls, historyRepo.versions)
+		}
+	})
+
+	t.Run("a rule changed concurrently returns ErrRuleConflict", func(t *testing.T) {
+		ruleRepo, historyRepo := newInMemoryRuleRepository()
+		created, err := NewCreateRuleUseCase(ruleRepo, historyRepo, nil).Execute(ctx, newRuleDefinition(), "alice")
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		findByID := ruleRepo.findByIDFunc
+		ruleRepo.findByIDFunc = func(ctx context.Context, ruleID string) (*entity.Rule, error) {
+			rule, err := findByID(ctx, ruleID)
+			// Another instance updates the rule right after it is read.
+			stored := ruleRepo.rules[ruleID]
+			stored.Version++
+			ruleRepo.rules[ruleID] = stored
+			return rule, err
+		}
+
+		_, err = NewSetRuleActiveUseCase(ruleRepo, historyRepo).Execute(ctx, created.RuleID, false, "bob")
+		if !errors.Is(err, ErrRuleConflict) {
+			t.Fatalf("expected ErrRuleConflict, got %v", err)
+		}
+		if len(historyRepo.versions) != 1 || !ruleRepo.rules[created.RuleID].IsActive {
+			t.Errorf("expected the conflicting change not to be written, got %+v", historyRepo.versions)
+		}
+	})
+}
+
+func TestRuleChanges_Lookups(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unknown rule without history returns ErrRuleNotFound", func(t *testing.T) {
+		uc := NewGetRuleHistoryUseCase(newInMemoryRuleRepository())
+
+		if _, err := uc.Execute(ctx, "rule-404"); !errors.Is(err, ErrRuleNotFound) {
+			t.Fatalf("expected ErrRuleNotFound, got %v", err)
+		}
+	})
+}
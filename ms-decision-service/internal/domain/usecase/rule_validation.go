@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// validateRule returns a *ValidationError when the rule definition is invalid.
+func validateRule(rule *entity.Rule) error {
+	if violations := rule.Validate(); len(violations) > 0 {
+		return &ValidationError{Err: ErrRuleValidationFailed, Violations: violations}
+	}
+	return nil
+}
+
+// validateRuleLists returns a *ValidationError when a list condition of the rule
+// names a list that does not exist or holds values of another field. Lists are only
+// loaded when the rule has list conditions, which are exactly the rules that fail
+// validation against no lists. Without a list repository nothing is checked.
+func validateRuleLists(ctx context.Context, listRepo repository.ListRepository, rule *entity.Rule) error {
+	if listRepo == nil || len(rule.ValidateLists(nil)) == 0 {
+		return nil
+	}
+
+	lists, err := listRepo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrListRetrievalFailed, err)
+	}
+
+	if violations := rule.ValidateLists(lists); len(violations) > 0 {
+		return &ValidationError{Err: ErrRuleValidationFailed, Violations: violations}
+	}
+	return nil
+}
+
+// ensurePriorityAvailable fails with ErrDuplicatePriority when a rule other than
+// the given one already uses the same priority.
+func ensurePriorityAvailable(ctx context.Context, ruleRepo repository.RuleRepository, rule *entity.Rule) error {
+	rules, err := ruleRepo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRuleRetrievalFailed, err)
+	}
+
+	for _, existing := range rules {
+		if existing.RuleID != rule.RuleID && existing.Priority == rule.Priority {
+			return fmt.Errorf("%w: priority %d is used by rule %s", ErrDuplicatePriority, rule.Priority, existing.RuleID)
+		}
+	}
+
+	return nil
+}
+
+// findExistingRule returns the rule with the given ID or ErrRuleNotFound.
+func findExistingRule(ctx context.Context, ruleRepo repository.RuleRepository, ruleID string) (*entity.Rule, error) {
+	if ruleID == "" {
+		return nil, ErrRuleIDEmpty
+	}
+
+	rule, err := ruleRepo.FindByID(ctx, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRuleRetrievalFailed, err)
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRuleNotFound, ruleID)
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// rulesetVersions reads the ruleset version that decisions are stamped with and
+// remembers the last one read.
+type rulesetVersions struct {
+	repo   repository.RuleHistoryRepository
+	last   atomic.Int64
+	logger zerolog.Logger
+}
+
+// current returns the ruleset version in effect. Read failures are logged and the
+// last version read is returned, so a decision is never blocked by the version.
+func (v *rulesetVersions) current(ctx context.Context) int {
+	version, err := v.repo.CurrentRulesetVersion(ctx)
+	if err != nil {
+		last := int(v.last.Load())
+		v.logger.Warn().Err(err).Int("ruleset_version", last).
+			Msg("failed to read ruleset version, using the last version read")
+		return last
+	}
+
+	v.last.Store(int64(version))
+	return version
+}
+
+// activeRules returns the active rules sorted by priority and the ruleset version
+// they belong to. A repository that keeps both in one snapshot, like the rule
+// cache, answers them together, so a reload between two reads cannot stamp the
+// rules with another version. Otherwise the version is read first, and the rules
+// are at least as new as it.
+func (v *rulesetVersions) activeRules(ctx context.Context, ruleRepo repository.RuleRepository) ([]entity.Rule, int, error) {
+	if snapshots, ok := ruleRepo.(repository.ActiveRulesetRepository); ok {
+		rules, version, err := snapshots.FindActiveRuleset(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		v.last.Store(int64(version))
+		return rules, version, nil
+	}
+
+	version := v.current(ctx)
+	rules, err := ruleRepo.FindActiveRulesSortedByPriority(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rules, version, nil
+}
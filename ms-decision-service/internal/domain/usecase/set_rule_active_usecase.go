@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// SetRuleActiveUseCase enables or disables an existing fraud detection rule.
+type SetRuleActiveUseCase struct {
+	ruleRepo repository.RuleRepository
+	recorder *ruleChangeRecorder
+}
+
+// NewSetRuleActiveUseCase creates a new use case with the given repositories.
+func NewSetRuleActiveUseCase(
+	ruleRepo repository.RuleRepository,
+	historyRepo repository.RuleHistoryRepository,
+) *SetRuleActiveUseCase {
+	return &SetRuleActiveUseCase{
+		ruleRepo: ruleRepo,
+		recorder: &ruleChangeRecorder{ruleRepo: ruleRepo, historyRepo: historyRepo},
+	}
+}
+
+// Execute sets the is_active flag of the rule identified by ruleID and returns the updated rule.
+// Setting the flag to its current value is a no-op and produces no new version.
+func (uc *SetRuleActiveUseCase) Execute(
+	ctx context.Context,
+	ruleID string,
+	active bool,
+	actor string,
+) (*entity.Rule, error) {
+	rule, err := findExistingRule(ctx, uc.ruleRepo, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rule.IsActive == active {
+		return rule, nil
+	}
+
+	updated := *rule
+	updated.IsActive = active
+
+	changeType := entity.RuleDeactivated
+	if active {
+		changeType = entity.RuleActivated
+	}
+
+	change := ruleChange{before: rule, after: &updated, changeType: changeType, actor: actor}
+	if _, err := uc.recorder.apply(ctx, change); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
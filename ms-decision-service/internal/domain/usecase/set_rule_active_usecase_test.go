@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+)
+
+func TestSetRuleActiveUseCase_Execute(t *testing.T) {
+	t.Run("missing rule returns ErrRuleNotFound", func(t *testing.T) {
+		uc := NewSetRuleActiveUseCase(&mockRuleRepository{}, &mockRuleHistoryRepository{})
+
+		_, err := uc.Execute(context.Background(), "rule-404", true, "analyst")
+		if !errors.Is(err, ErrRuleNotFound) {
+			t.Fatalf("expected ErrRuleNotFound, got %v", err)
+		}
+	})
+
+	t.Run("disables an active rule", func(t *testing.T) {
+		repo := &mockRuleRepository{
+			findByIDFunc: func(_ context.Context, id string) (*entity.Rule, error) {
+				return &entity.Rule{RuleID: id, IsActive: true}, nil
+			},
+		}
+		uc := NewSetRuleActiveUseCase(repo, &mockRuleHistoryRepository{})
+
+		rule, err := uc.Execute(context.Background(), "rule-001", false, "analyst")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rule.IsActive {
+			t.Error("expected rule to be inactive")
+		}
+		if repo.updated == nil || repo.updated.IsActive {
+			t.Error("expected inactive rule to be written to the repository")
+		}
+	})
+
+	t.Run("unchanged state skips the write", func(t *testing.T) {
+		repo := &mockRuleRepository{
+			findByIDFunc: func(_ context.Context, id string) (*entity.Rule, error) {
+				return &entity.Rule{RuleID: id, IsActive: true}, nil
+			},
+		}
+		uc := NewSetRuleActiveUseCase(repo, &mockRuleHistoryRepository{})
+
+		if _, err := uc.Execute(context.Background(), "rule-001", true, "analyst"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repo.updated != nil {
+			t.Error("expected no write when state is unchanged")
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+	"time"
+)
+
+// UpdateDecisionPolicyUseCase validates and stores the decision policy of a rule set.
+type UpdateDecisionPolicyUseCase struct {
+	policyRepo repository.DecisionPolicyRepository
+	ruleRepo   repository.RuleRepository
+}
+
+// NewUpdateDecisionPolicyUseCase creates a new use case with the given repositories.
+// ruleRepo may be nil, in which case a SCORE policy is not checked against the rules.
+func NewUpdateDecisionPolicyUseCase(
+	policyRepo repository.DecisionPolicyRepository,
+	ruleRepo repository.RuleRepository,
+) *UpdateDecisionPolicyUseCase {
+	return &UpdateDecisionPolicyUseCase{
+		policyRepo: policyRepo,
+		ruleRepo:   ruleRepo,
+	}
+}
+
+// Execute validates the policy and replaces the stored policy of its rule set,
+// attributed to actor. A SCORE policy is also refused when no active LIVE rule of
+// the rule set has a score weight or the weights cannot add up to its thresholds.
+func (uc *UpdateDecisionPolicyUseCase) Execute(
+	ctx context.Context,
+	policy *entity.DecisionPolicy,
+	actor string,
+) (*entity.DecisionPolicy, error) {
+	if policy == nil {
+		return nil, ErrDecisionPolicyNil
+	}
+
+	if violations := policy.Validate(); len(violations) > 0 {
+		return nil, &ValidationError{Err: ErrDecisionPolicyValidationFailed, Violations: violations}
+	}
+
+	if policy.Mode == entity.ModeScore && uc.ruleRepo != nil {
+		rules, err := uc.ruleRepo.FindAll(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRuleRetrievalFailed, err)
+		}
+		if violations := policy.ValidateRules(rules); len(violations) > 0 {
+			return nil, &ValidationError{Err: ErrDecisionPolicyValidationFailed, Violations: violations}
+		}
+	}
+
+	policy.UpdatedBy = actor
+	policy.UpdatedAt = time.Now().UTC()
+
+	if err := uc.policyRepo.Save(ctx, policy); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecisionPolicyPersistenceFailed, err)
+	}
+
+	return policy, nil
+}
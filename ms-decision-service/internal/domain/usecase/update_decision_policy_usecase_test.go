@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+)
+
+// mockDecisionPolicyRepository is a test double for repository.DecisionPolicyRepository.
+type mockDecisionPolicyRepository struct {
+	findAllFunc       func(ctx context.Context) ([]entity.DecisionPolicy, error)
+	findByRuleSetFunc func(ctx context.Context, ruleSet entity.RuleSet) (*entity.DecisionPolicy, error)
+	saveFunc          func(ctx context.Context, policy *entity.DecisionPolicy) error
+	saved             *entity.DecisionPolicy
+}
+
+func (m *mockDecisionPolicyRepository) FindAll(ctx context.Context) ([]entity.DecisionPolicy, error) {
+	if m.findAllFunc != nil {
+		return m.findAllFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockDecisionPolicyRepository) FindByRuleSet(ctx context.Context, ruleSet entity.RuleSet) (*entity.DecisionPolicy, error) {
+	if m.findByRuleSetFunc != nil {
+		return m.findByRuleSetFunc(ctx, ruleSet)
+	}
+	return nil, nil
+}
+
+func (m *mockDecisionPolicyRepository) Save(ctx context.Context, policy *entity.DecisionPolicy) error {
+	m.saved = policy
+	if m.saveFunc != nil {
+		return m.saveFunc(ctx, policy)
+	}
+	return nil
+}
+
+func newScorePolicy(ruleSet entity.RuleSet, fraudCheck *int, decline int) *entity.DecisionPolicy {
+	return &entity.DecisionPolicy{
+		RuleSet:             ruleSet,
+		Mode:                entity.ModeScore,
+		FraudCheckThreshold: fraudCheck,
+		DeclineThreshold:    decline,
+	}
+}
+
+func TestUpdateDecisionPolicyUseCase_Execute(t *testing.T) {
+	fraudCheck := 40
+
+	t.Run("valid policy is saved with audit fields", func(t *testing.T) {
+		repo := &mockDecisionPolicyRepository{}
+		uc := NewUpdateDecisionPolicyUseCase(repo, nil)
+
+		policy, err := uc.Execute(context.Background(), newScorePolicy(entity.RuleSetTransaction, &fraudCheck, 80), "alice")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repo.saved == nil || repo.saved.RuleSet != entity.RuleSetTransaction {
+			t.Fatalf("expected the policy to be saved, got %+v", repo.saved)
+		}
+		if policy.UpdatedBy != "alice" || policy.UpdatedAt.IsZero() {
+			t.Errorf("expected audit fields to be set, got %+v", policy)
+		}
+	})
+
+	t.Run("invalid policy returns every violation", func(t *testing.T) {
+		repo := &mockDecisionPolicyRepository{}
+		uc := NewUpdateDecisionPolicyUseCase(repo, nil)
+
+		_, err := uc.Execute(context.Background(), newScorePolicy(entity.RuleSetFraudScore, &fraudCheck, 0), "alice")
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || !errors.Is(err, ErrDecisionPolicyValidationFailed) {
+			t.Fatalf("expected ValidationError, got %v", err)
+		}
+		if len(validationErr.Violations) < 2 {
+			t.Errorf("expected violations for both thresholds, got %+v", validationErr.Violations)
+		}
+		if repo.saved != nil {
+			t.Error("expected an invalid policy not to be saved")
+		}
+	})
+
+	t.Run("score policy the rules cannot reach", func(t *testing.T) {
+		repo := &mockDecisionPolicyRepository{}
+		ruleRepo := &mockRuleRepository{findAllFunc: func(_ context.Context) ([]entity.Rule, error) {
+			return []entity.Rule{
+				{RuleID: "r1", ConditionField: entity.FieldAmountInCents, ConditionOperator: entity.OpGreaterThan, ConditionValue: "1000", IsActive: true, ScoreWeight: 50},
+				{RuleID: "r2", ConditionField: entity.FieldAmountInCents, ConditionOperator: entity.OpGreaterThan, ConditionValue: "5000", IsActive: true, ScoreWeight: 20, Mode: entity.RuleModeShadow},
+			}, nil
+		}}
+		uc := NewUpdateDecisionPolicyUseCase(repo, ruleRepo)
+
+		_, err := uc.Execute(context.Background(), newScorePolicy(entity.RuleSetTransaction, &fraudCheck, 80), "alice")
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || !errors.Is(err, ErrDecisionPolicyValidationFailed) {
+			t.Fatalf("expected ValidationError, got %v", err)
+		}
+		if len(validationErr.Violations) != 1 || validationErr.Violations[0].Field != "decline_threshold" {
+			t.Errorf("expected the decline threshold to be unreachable, got %+v", validationErr.Violations)
+		}
+		if repo.saved != nil {
+			t.Error("expected an unreachable policy not to be saved")
+		}
+	})
+
+	t.Run("rule retrieval failure", func(t *testing.T) {
+		ruleRepo := &mockRuleRepository{findAllFunc: func(_ context.Context) ([]entity.Rule, error) {
+			return nil, errors.New("table unavailable")
+		}}
+		uc := NewUpdateDecisionPolicyUseCase(&mockDecisionPolicyRepository{}, ruleRepo)
+
+		_, err := uc.Execute(context.Background(), newScorePolicy(entity.RuleSetTransaction, nil, 80), "alice")
+		if !errors.Is(err, ErrRuleRetrievalFailed) {
+			t.Errorf("expected ErrRuleRetrievalFailed, got %v", err)
+		}
+	})
+
+	t.Run("nil policy", func(t *testing.T) {
+		uc := NewUpdateDecisionPolicyUseCase(&mockDecisionPolicyRepository{}, nil)
+
+		if _, err := uc.Execute(context.Background(), nil, "alice"); !errors.Is(err, ErrDecisionPolicyNil) {
+			t.Errorf("expected ErrDecisionPolicyNil, got %v", err)
+		}
+	})
+
+	t.Run("save failure", func(t *testing.T) {
+		repo := &mockDecisionPolicyRepository{
+			saveFunc: func(_ context.Context, _ *entity.DecisionPolicy) error {
+				return errors.New("table unavailable")
+			},
+		}
+		uc := NewUpdateDecisionPolicyUseCase(repo, nil)
+
+		_, err := uc.Execute(context.Background(), newScorePolicy(entity.RuleSetTransaction, nil, 80), "alice")
+		if !errors.Is(err, ErrDecisionPolicyPersistenceFailed) {
+			t.Errorf("expected ErrDecisionPolicyPersistenceFailed, got %v", err)
+		}
+	})
+}
+
+func TestGetDecisionPoliciesUseCase_Execute(t *testing.T) {
+	stored := newScorePolicy(entity.RuleSetTransaction, nil, 80)
+	repo := &mockDecisionPolicyRepository{
+		findByRuleSetFunc: func(_ context.Context, ruleSet entity.RuleSet) (*entity.DecisionPolicy, error) {
+			if ruleSet == stored.RuleSet {
+				return stored, nil
+			}
+			return nil, nil
+		},
+	}
+
+	policies, err := NewGetDecisionPoliciesUseCase(repo).Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(policies) != len(entity.RuleSets) {
+		t.Fatalf("expected one policy per rule set, got %+v", policies)
+	}
+	for _, policy := range policies {
+		want := entity.ModeFirstMatch
+		if policy.RuleSet == entity.RuleSetTransaction {
+			want = entity.ModeScore
+		}
+		if policy.Mode != want {
+			t.Errorf("rule set %s: expected mode %s, got %s", policy.RuleSet, want, policy.Mode)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+)
+
+// UpdateRuleUseCase validates and replaces an existing fraud detection rule.
+type UpdateRuleUseCase struct {
+	ruleRepo repository.RuleRepository
+	listRepo repository.ListRepository
+	recorder *ruleChangeRecorder
+}
+
+// NewUpdateRuleUseCase creates a new use case with the given repositories. listRepo is
+// optional: without it the lists named by list conditions are not checked.
+func NewUpdateRuleUseCase(
+	ruleRepo repository.RuleRepository,
+	historyRepo repository.RuleHistoryRepository,
+	listRepo repository.ListRepository,
+) *UpdateRuleUseCase {
+	return &UpdateRuleUseCase{
+		ruleRepo: ruleRepo,
+		listRepo: listRepo,
+		recorder: &ruleChangeRecorder{ruleRepo: ruleRepo, historyRepo: historyRepo},
+	}
+}
+
+// Execute replaces the rule identified by ruleID with the given definition.
+// The rule must exist, the lists it references must exist and hold values of the
+// conditions' fields, and its priority must not be used by any other rule.
+// A definition identical to the stored one is a no-op and produces no new version.
+func (uc *UpdateRuleUseCase) Execute(
+	ctx context.Context,
+	ruleID string,
+	rule *entity.Rule,
+	actor string,
+) (*entity.Rule, error) {
+	if rule == nil {
+		return nil, ErrRuleNil
+	}
+
+	rule.RuleID = ruleID
+
+	if err := validateRule(rule); err != nil {
+		return nil, err
+	}
+	if err := validateRuleLists(ctx, uc.listRepo, rule); err != nil {
+		return nil, err
+	}
+
+	existing, err := findExistingRule(ctx, uc.ruleRepo, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entity.DiffRules(existing, rule)) == 0 {
+		return existing, nil
+	}
+
+	if err := ensurePriorityAvailable(ctx, uc.ruleRepo, rule); err != nil {
+		return nil, err
+	}
+
+	change := ruleChange{before: existing, after: rule, changeType: entity.RuleUpdated, actor: actor}
+	if _, err := uc.recorder.apply(ctx, change); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+)
+
+func TestUpdateRuleUseCase_Execute(t *testing.T) {
+	found := func(_ context.Context, id string) (*entity.Rule, error) {
+		return &entity.Rule{RuleID: id, Priority: 1}, nil
+	}
+
+	tests := []struct {
+		name     string
+		ruleID   string
+		priority int
+		ruleRepo *mockRuleRepository
+		wantErr  error
+	}{
+		{
+			name:     "empty rule ID returns ErrRuleIDEmpty",
+			ruleID:   "",
+			priority: 1,
+			ruleRepo: &mockRuleRepository{},
+			wantErr:  ErrRuleIDEmpty,
+		},
+		{
+			name:     "missing rule returns ErrRuleNotFound",
+			ruleID:   "rule-404",
+			priority: 1,
+			ruleRepo: &mockRuleRepository{},
+			wantErr:  ErrRuleNotFound,
+		},
+		{
+			name:     "priority used by another rule returns ErrDuplicatePriority",
+			ruleID:   "rule-001",
+			priority: 2,
+			ruleRepo: &mockRuleRepository{findByIDFunc: found, findAllFunc: existingRules},
+			wantErr:  ErrDuplicatePriority,
+		},
+		{
+			name:     "keeping its own priority succeeds",
+			ruleID:   "rule-001",
+			priority: 1,
+			ruleRepo: &mockRuleRepository{findByIDFunc: found, findAllFunc: existingRules},
+		},
+		{
+			name:     "repository write failure returns ErrRulePersistenceFailed",
+			ruleID:   "rule-001",
+			priority: 1,
+			ruleRepo: &mockRuleRepository{
+				findByIDFunc: found,
+				applyFunc: func(_ context.Context, _ *entity.RuleChangeSet) error {
+					return errors.New("dynamo timeout")
+				},
+			},
+			wantErr: ErrRulePersistenceFailed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := newRuleDefinition()
+			rule.Priority = tc.priority
+
+			uc := NewUpdateRuleUseCase(tc.ruleRepo, &mockRuleHistoryRepository{}, nil)
+			updated, err := uc.Execute(context.Background(), tc.ruleID, rule, "analyst")
+
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected error wrapping %v, got %v", tc.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if updated.RuleID != tc.ruleID {
+				t.Errorf("expected rule ID %q, got %q", tc.ruleID, updated.RuleID)
+			}
+			if tc.ruleRepo.updated == nil || tc.ruleRepo.updated.RuleID != updated.RuleID {
+				t.Error("expected rule to be written to the repository")
+			}
+		})
+	}
+}
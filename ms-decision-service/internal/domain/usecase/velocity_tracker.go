@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// velocityTracker records every evaluated transaction in the velocity store and
+// computes the velocity counters the rules can reference. Amounts are recorded in
+// US cents using rates; a transaction in a currency without a rate is counted but
+// adds nothing to the amount sums.
+type velocityTracker struct {
+	store  repository.VelocityStore
+	rates  entity.CurrencyRates
+	logger zerolog.Logger
+}
+
+// track records the transaction under each velocity dimension it has a value for
+// and returns the resulting counters. Store failures are logged and the affected
+// dimension is left out, so its velocity fields never match (fail-open).
+func (t *velocityTracker) track(ctx context.Context, transaction *entity.TransactionMessage) entity.VelocityCounters {
+	counters := entity.VelocityCounters{}
+	if t.store == nil {
+		return counters
+	}
+
+	occurredAt := transaction.CreatedAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+	amount, ok := t.rates.ToUSDCents(transaction.AmountInCents, transaction.Currency)
+	if !ok {
+		t.logger.Warn().
+			Str("transaction_id", transaction.ID).
+			Str("currency", transaction.Currency).
+			Msg("no reference rate for currency, velocity amount sums leave the transaction out")
+	}
+	event := entity.VelocityEvent{
+		TransactionID:    transaction.ID,
+		AmountInUSDCents: amount,
+		OccurredAt:       occurredAt,
+	}
+
+	windows := make([]time.Duration, len(entity.VelocityWindows))
+	for i, window := range entity.VelocityWindows {
+		windows[i] = window.Duration
+	}
+
+	for _, dimension := range entity.VelocityDimensions {
+		key := entity.VelocityKey(dimension, transaction)
+		if key == "" {
+			continue
+		}
+
+		aggregates, err := t.store.Record(ctx, key, event, windows)
+		if err != nil {
+			t.logger.Warn().Err(err).
+				Str("transaction_id", transaction.ID).
+				Str("dimension", dimension.Name).
+				Msg("failed to record velocity counters")
+			continue
+		}
+
+		counters.Set(dimension, aggregates)
+	}
+
+	return counters
+}
@@ -0,0 +1,225 @@
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/usecase"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// maxJSONLLineBytes bounds a single line of an imported JSONL file.
+const maxJSONLLineBytes = 1 << 20
+
+// BacktestRequest is the request body for backtesting against stored transactions.
+// Omitting rules replays the currently active rules.
+type BacktestRequest struct {
+	Rules []RuleRequest `json:"rules"`
+	From  time.Time     `json:"from"`
+	To    time.Time     `json:"to"`
+	Limit int           `json:"limit"`
+}
+
+// BacktestController handles HTTP endpoints for replaying historical transactions
+// through a candidate rule set.
+type BacktestController struct {
+	backtestRulesUseCase *usecase.BacktestRulesUseCase
+	logger               zerolog.Logger
+}
+
+// NewBacktestController creates a new BacktestController.
+func NewBacktestController(
+	backtestRulesUseCase *usecase.BacktestRulesUseCase,
+	logger zerolog.Logger,
+) *BacktestController {
+	return &BacktestController{
+		backtestRulesUseCase: backtestRulesUseCase,
+		logger:               logger,
+	}
+}
+
+// Backtest handles POST /rules/backtest, replaying transactions from the
+// transaction store.
+func (bc *BacktestController) Backtest(c *echo.Context) error {
+	var req BacktestRequest
+	if err := c.Bind(&req); err != nil {
+		bc.logger.Warn().Err(err).Msg("failed to bind backtest request")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	return bc.run(c, usecase.BacktestRequest{
+		Rules: toCandidateRules(req.Rules),
+		From:  req.From,
+		To:    req.To,
+		Limit: req.Limit,
+	})
+}
+
+// Import handles POST /rules/backtest/import, replaying transactions from an
+// exported JSONL file. The multipart form carries the file in the transactions
+// field and, optionally, the candidate rules as a JSON array in the rules field.
+// Each line is a transaction as published on Transaction.Created; its status is the
+// decision that was actually made.
+func (bc *BacktestController) Import(c *echo.Context) error {
+	file, err := c.FormFile("transactions")
+	if err != nil {
+		bc.logger.Warn().Err(err).Msg("backtest import without transactions file")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing transactions file",
+			Details: err.Error(),
+		})
+	}
+
+	var candidates []RuleRequest
+	if raw := c.FormValue("rules"); strings.TrimSpace(raw) != "" {
+		if err := json.Unmarshal([]byte(raw), &candidates); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid rules field",
+				Details: err.Error(),
+			})
+		}
+		if candidates == nil {
+			candidates = []RuleRequest{}
+		}
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		bc.logger.Error().Err(err).Msg("failed to open transactions file")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Details: err.Error(),
+		})
+	}
+	defer src.Close()
+
+	transactions, violations, err := parseTransactionsJSONL(src)
+	if err != nil {
+		bc.logger.Warn().Err(err).Msg("failed to parse transactions file")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid transactions file",
+			Details: err.Error(),
+		})
+	}
+	if len(violations) > 0 {
+		return c.JSON(http.StatusBadRequest, ValidationErrorResponse{
+			Error:      "Validation failed",
+			Details:    fmt.Sprintf("%d invalid lines", len(violations)),
+			Violations: violations,
+		})
+	}
+
+	return bc.run(c, usecase.BacktestRequest{
+		Rules:        toCandidateRules(candidates),
+		Transactions: transactions,
+	})
+}
+
+func (bc *BacktestController) run(c *echo.Context, req usecase.BacktestRequest) error {
+	report, err := bc.backtestRulesUseCase.Execute(c.Request().Context(), req)
+	if err != nil {
+		return bc.handleError(c, err)
+	}
+
+	bc.logger.Info().
+		Int("transaction_count", report.TransactionCount).
+		Int("changed", report.Changed).
+		Msg("backtest completed")
+
+	return c.JSON(http.StatusOK, DataResponse{Data: report})
+}
+
+// toCandidateRules converts the requested rules, keeping nil (replay the active
+// rules) distinct from an empty rule set.
+func toCandidateRules(requests []RuleRequest) []entity.Rule {
+	if requests == nil {
+		return nil
+	}
+
+	rules := make([]entity.Rule, len(requests))
+	for i := range requests {
+		rules[i] = *requests[i].toRule()
+	}
+	return rules
+}
+
+// parseTransactionsJSONL reads one transaction per line, skipping blank lines.
+// Lines that are not valid JSON are reported as violations using transactions[i]
+// paths, where i counts non-blank lines from zero. Reading stops with an error
+// once more than usecase.MaxBacktestTransactions lines have been read.
+func parseTransactionsJSONL(body io.Reader) ([]entity.TransactionMessage, []entity.RuleViolation, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLLineBytes)
+
+	transactions := []entity.TransactionMessage{}
+	var violations []entity.RuleViolation
+	for index := 0; scanner.Scan(); {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if index >= usecase.MaxBacktestTransactions {
+			return nil, nil, fmt.Errorf("%w: at most %d transactions are allowed",
+				usecase.ErrBacktestLimitExceeded, usecase.MaxBacktestTransactions)
+		}
+
+		var transaction entity.TransactionMessage
+		if err := json.Unmarshal([]byte(line), &transaction); err != nil {
+			violations = append(violations, entity.RuleViolation{
+				Field:   fmt.Sprintf("transactions[%d]", index),
+				Message: err.Error(),
+			})
+		}
+		transactions = append(transactions, transaction)
+		index++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return transactions, violations, nil
+}
+
+// handleError maps backtest use case errors to HTTP responses.
+func (bc *BacktestController) handleError(c *echo.Context, err error) error {
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		bc.logger.Warn().Err(err).Msg("candidate rule validation failed")
+		return c.JSON(http.StatusBadRequest, ValidationErrorResponse{
+			Error:      "Validation failed",
+			Details:    err.Error(),
+			Violations: validationErr.Violations,
+		})
+	}
+
+	if errors.Is(err, usecase.ErrBacktestLimitExceeded) {
+		bc.logger.Warn().Err(err).Msg("backtest too large")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Backtest too large",
+			Details: err.Error(),
+		})
+	}
+
+	bc.logger.Error().Err(err).Msg("failed to run backtest")
+	return c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   "Internal server error",
+		Details: err.Error(),
+	})
+}
+
+// RegisterRoutes registers the backtest routes on the Echo instance.
+func (bc *BacktestController) RegisterRoutes(e *echo.Echo) {
+	e.POST("/rules/backtest", bc.Backtest)
+	e.POST("/rules/backtest/import", bc.Import)
+}
@@ -0,0 +1,135 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/usecase"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+type mockTransactionRepository struct {
+	transactions []entity.TransactionMessage
+}
+
+func (m *mockTransactionRepository) FindCreatedBetween(_ context.Context, _, _ time.Time, _ int) ([]entity.TransactionMessage, error) {
+	return m.transactions, nil
+}
+
+func newBacktestController(transactions []entity.TransactionMessage) *echo.Echo {
+	ruleRepo := &mockRuleRepository{}
+	uc := usecase.NewBacktestRulesUseCase(ruleRepo, &mockTransactionRepository{transactions: transactions}, nil, nil, nil)
+	controller := NewBacktestController(uc, zerolog.Nop())
+
+	e := echo.New()
+	controller.RegisterRoutes(e)
+
+	return e
+}
+
+func serveBacktestImport(e *echo.Echo, rules, transactions string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if rules != "" {
+		_ = writer.WriteField("rules", rules)
+	}
+	if transactions != "" {
+		part, _ := writer.CreateFormFile("transactions", "transactions.jsonl")
+		_, _ = part.Write([]byte(transactions))
+	}
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/rules/backtest/import", &body)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeBacktestReport(t *testing.T, rec *httptest.ResponseRecorder) entity.BacktestReport {
+	t.Helper()
+
+	var body struct {
+		Data entity.BacktestReport `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return body.Data
+}
+
+func TestBacktestController_Backtest(t *testing.T) {
+	e := newBacktestController([]entity.TransactionMessage{
+		{ID: "txn-1", PaymentMethod: "CRYPTO", Status: "APPROVED"},
+		{ID: "txn-2", PaymentMethod: "CARD", Status: "APPROVED"},
+	})
+
+	rec := serveRuleRequest(e, http.MethodPost, "/rules/backtest", `{"rules": [`+cryptoRuleBody+`], "limit": 100}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	report := decodeBacktestReport(t, rec)
+	if report.TransactionCount != 2 || report.Changed != 1 || report.Decisions[entity.DECLINED] != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestBacktestController_Import(t *testing.T) {
+	tests := []struct {
+		name         string
+		rules        string
+		transactions string
+		wantStatus   int
+		wantCount    int
+	}{
+		{
+			name:         "replays every line",
+			rules:        `[` + cryptoRuleBody + `]`,
+			transactions: "{\"id\":\"txn-1\",\"payment_method\":\"CRYPTO\",\"status\":\"DECLINED\"}\n\n{\"id\":\"txn-2\",\"payment_method\":\"CARD\",\"status\":\"APPROVED\"}\n",
+			wantStatus:   http.StatusOK,
+			wantCount:    2,
+		},
+		{
+			name:         "invalid line returns 400",
+			transactions: "{\"id\":\"txn-1\"}\nnot json\n",
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "invalid candidate rule returns 400",
+			rules:        `[{"rule_name": "Broken", "condition_field": "shoe_size", "condition_operator": "EQUAL", "condition_value": "42", "result_status": "DECLINED"}]`,
+			transactions: "{\"id\":\"txn-1\"}\n",
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:       "missing file returns 400",
+			rules:      `[]`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newBacktestController(nil)
+
+			rec := serveBacktestImport(e, tc.rules, tc.transactions)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tc.wantStatus == http.StatusOK {
+				if report := decodeBacktestReport(t, rec); report.TransactionCount != tc.wantCount || report.Changed != 0 {
+					t.Errorf("unexpected report: %+v", report)
+				}
+			}
+		})
+	}
+}
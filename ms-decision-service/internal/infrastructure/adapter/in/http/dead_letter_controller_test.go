@@ -0,0 +1,199 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/usecase"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// mockDeadLetterRepository keeps the dead letters of each dead-letter topic.
+type mockDeadLetterRepository struct {
+	deadLetters map[string][]entity.DeadLetter
+	redriven    []*entity.DeadLetter
+}
+
+func (m *mockDeadLetterRepository) Count(_ context.Context, topic string) (int64, error) {
+	return int64(len(m.deadLetters[topic])), nil
+}
+
+func (m *mockDeadLetterRepository) FindAll(_ context.Context, topic string, _ int) ([]entity.DeadLetter, error) {
+	return m.deadLetters[topic], nil
+}
+
+func (m *mockDeadLetterRepository) FindByOffset(_ context.Context, topic string, partition int32, offset int64) (*entity.DeadLetter, error) {
+	deadLetters := m.deadLetters[topic]
+	for i := range deadLetters {
+		if deadLetters[i].Partition == partition && deadLetters[i].Offset == offset {
+			return &deadLetters[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDeadLetterRepository) Redrive(_ context.Context, deadLetter *entity.DeadLetter) error {
+	m.redriven = append(m.redriven, deadLetter)
+	return nil
+}
+
+func newDeadLetterController(repo *mockDeadLetterRepository) *echo.Echo {
+	queues := []entity.DeadLetterQueue{
+		{Topic: "Transaction.Created.DLQ", SourceTopic: "Transaction.Created"},
+		{Topic: "FraudSignals.Calculated.DLQ", SourceTopic: "FraudSignals.Calculated"},
+	}
+	controller := NewDeadLetterController(
+		usecase.NewGetDeadLetterQueuesUseCase(repo, queues),
+		usecase.NewListDeadLettersUseCase(repo, queues),
+		usecase.NewGetDeadLetterUseCase(repo, queues),
+		usecase.NewRedriveDeadLettersUseCase(repo, queues),
+		zerolog.Nop(),
+	)
+
+	e := echo.New()
+	controller.RegisterRoutes(e)
+
+	return e
+}
+
+func testDeadLetters() map[string][]entity.DeadLetter {
+	return map[string][]entity.DeadLetter{
+		"Transaction.Created.DLQ": {{
+			ID:            "0-4",
+			Partition:     0,
+			Offset:        4,
+			TransactionID: "tx-1",
+			SourceTopic:   "Transaction.Created",
+			Payload:       `{"id":"tx-1"}`,
+			Error:         "failed to retrieve rules: timeout",
+			Attempts:      3,
+		}},
+		"FraudSignals.Calculated.DLQ": {{
+			ID:            "1-2",
+			Partition:     1,
+			Offset:        2,
+			TransactionID: "tx-2",
+			SourceTopic:   "FraudSignals.Calculated",
+			Payload:       `{"transaction_id":"tx-2","fraud_score":80}`,
+			Error:         "failed to publish decision result: broker unavailable",
+			Attempts:      3,
+		}},
+	}
+}
+
+func TestDeadLetterController_GetQueues(t *testing.T) {
+	repo := &mockDeadLetterRepository{deadLetters: testDeadLetters()}
+	repo.deadLetters["Transaction.Created.DLQ"] = append(repo.deadLetters["Transaction.Created.DLQ"], entity.DeadLetter{ID: "0-5", Offset: 5})
+	e := newDeadLetterController(repo)
+
+	rec := serveRuleRequest(e, http.MethodGet, "/admin/dlq", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		Data []entity.DeadLetterQueue `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Data) != 2 ||
+		body.Data[0].SourceTopic != "Transaction.Created" || body.Data[0].Count != 2 ||
+		body.Data[1].SourceTopic != "FraudSignals.Calculated" || body.Data[1].Count != 1 {
+		t.Errorf("expected both queues with their own counts, got %+v", body.Data)
+	}
+}
+
+func TestDeadLetterController_ListAndGet(t *testing.T) {
+	e := newDeadLetterController(&mockDeadLetterRepository{deadLetters: testDeadLetters()})
+
+	rec := serveRuleRequest(e, http.MethodGet, "/admin/dlq/Transaction.Created.DLQ/messages?limit=10", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serveRuleRequest(e, http.MethodGet, "/admin/dlq/FraudSignals.Calculated.DLQ/messages/1-2", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		Data entity.DeadLetter `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Data.TransactionID != "tx-2" || body.Data.SourceTopic != "FraudSignals.Calculated" {
+		t.Errorf("unexpected dead letter %+v", body.Data)
+	}
+
+	rec = serveRuleRequest(e, http.MethodGet, "/admin/dlq/FraudSignals.Calculated.DLQ/messages/0-4", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected a Transaction.Created dead letter not to be found in FraudSignals.Calculated.DLQ, got %d", rec.Code)
+	}
+}
+
+func TestDeadLetterController_Errors(t *testing.T) {
+	e := newDeadLetterController(&mockDeadLetterRepository{deadLetters: testDeadLetters()})
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		expected int
+	}{
+		{"unknown topic", http.MethodGet, "/admin/dlq/Other.DLQ/messages", "", http.StatusNotFound},
+		{"invalid limit", http.MethodGet, "/admin/dlq/Transaction.Created.DLQ/messages?limit=x", "", http.StatusBadRequest},
+		{"limit too high", http.MethodGet, "/admin/dlq/Transaction.Created.DLQ/messages?limit=5000", "", http.StatusBadRequest},
+		{"invalid id", http.MethodGet, "/admin/dlq/Transaction.Created.DLQ/messages/abc", "", http.StatusBadRequest},
+		{"unknown id", http.MethodGet, "/admin/dlq/Transaction.Created.DLQ/messages/0-5", "", http.StatusNotFound},
+		{"no selection", http.MethodPost, "/admin/dlq/Transaction.Created.DLQ/redrive", `{}`, http.StatusBadRequest},
+		{"invalid rate", http.MethodPost, "/admin/dlq/Transaction.Created.DLQ/redrive", `{"all":true,"rate_per_second":1000}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveRuleRequest(e, tt.method, tt.target, tt.body)
+			if rec.Code != tt.expected {
+				t.Errorf("expected status %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestDeadLetterController_Redrive(t *testing.T) {
+	repo := &mockDeadLetterRepository{deadLetters: testDeadLetters()}
+	e := newDeadLetterController(repo)
+
+	rec := serveRuleRequest(e, http.MethodPost, "/admin/dlq/Transaction.Created.DLQ/redrive", `{"all":true,"dry_run":true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(repo.redriven) != 0 {
+		t.Fatalf("expected a dry run to redrive nothing, got %v", repo.redriven)
+	}
+
+	rec = serveRuleRequest(e, http.MethodPost, "/admin/dlq/FraudSignals.Calculated.DLQ/redrive", `{"ids":["1-2"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		Data entity.RedriveResult `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Data.Redriven != 1 || body.Data.SourceTopic != "FraudSignals.Calculated" {
+		t.Errorf("expected 1-2 to be redriven to FraudSignals.Calculated, got %+v", body.Data)
+	}
+	if len(repo.redriven) != 1 || repo.redriven[0].ID != "1-2" || repo.redriven[0].SourceTopic != "FraudSignals.Calculated" {
+		t.Errorf("expected only 1-2 to be redriven, got %+v", repo.redriven)
+	}
+}
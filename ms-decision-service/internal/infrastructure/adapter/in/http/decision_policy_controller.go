@@ -0,0 +1,102 @@
+package http
+
+import (
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// DecisionPolicyRequest is the request body for replacing the decision policy of a rule set.
+type DecisionPolicyRequest struct {
+	Mode                entity.DecisionMode `json:"mode"`
+	FraudCheckThreshold *int                `json:"fraud_check_threshold"`
+	DeclineThreshold    int                 `json:"decline_threshold"`
+}
+
+// DecisionPolicyController handles HTTP endpoints for the decision policy of each rule set.
+type DecisionPolicyController struct {
+	getDecisionPoliciesUseCase  *usecase.GetDecisionPoliciesUseCase
+	updateDecisionPolicyUseCase *usecase.UpdateDecisionPolicyUseCase
+	logger                      zerolog.Logger
+}
+
+// NewDecisionPolicyController creates a new DecisionPolicyController.
+func NewDecisionPolicyController(
+	getDecisionPoliciesUseCase *usecase.GetDecisionPoliciesUseCase,
+	updateDecisionPolicyUseCase *usecase.UpdateDecisionPolicyUseCase,
+	logger zerolog.Logger,
+) *DecisionPolicyController {
+	return &DecisionPolicyController{
+		getDecisionPoliciesUseCase:  getDecisionPoliciesUseCase,
+		updateDecisionPolicyUseCase: updateDecisionPolicyUseCase,
+		logger:                      logger,
+	}
+}
+
+// GetPolicies handles GET /rules/policies.
+func (pc *DecisionPolicyController) GetPolicies(c *echo.Context) error {
+	policies, err := pc.getDecisionPoliciesUseCase.Execute(c.Request().Context())
+	if err != nil {
+		return pc.handleError(c, err, "")
+	}
+
+	return c.JSON(http.StatusOK, DataResponse{Data: policies})
+}
+
+// UpdatePolicy handles PUT /rules/policies/:rule_set.
+func (pc *DecisionPolicyController) UpdatePolicy(c *echo.Context) error {
+	ruleSet := entity.RuleSet(c.Param("rule_set"))
+
+	var req DecisionPolicyRequest
+	if err := c.Bind(&req); err != nil {
+		pc.logger.Warn().Err(err).Str("rule_set", string(ruleSet)).Msg("failed to bind decision policy body")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	policy, err := pc.updateDecisionPolicyUseCase.Execute(c.Request().Context(), &entity.DecisionPolicy{
+		RuleSet:             ruleSet,
+		Mode:                req.Mode,
+		FraudCheckThreshold: req.FraudCheckThreshold,
+		DeclineThreshold:    req.DeclineThreshold,
+	}, changedBy(c))
+	if err != nil {
+		return pc.handleError(c, err, ruleSet)
+	}
+
+	pc.logger.Info().Str("rule_set", string(policy.RuleSet)).Str("mode", string(policy.Mode)).
+		Str("updated_by", policy.UpdatedBy).Msg("decision policy updated")
+
+	return c.JSON(http.StatusOK, DataResponse{Data: policy})
+}
+
+// handleError maps decision policy use case errors to HTTP responses.
+func (pc *DecisionPolicyController) handleError(c *echo.Context, err error, ruleSet entity.RuleSet) error {
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		pc.logger.Warn().Err(err).Str("rule_set", string(ruleSet)).Msg("decision policy validation failed")
+		return c.JSON(http.StatusBadRequest, ValidationErrorResponse{
+			Error:      "Validation failed",
+			Details:    err.Error(),
+			Violations: validationErr.Violations,
+		})
+	}
+
+	pc.logger.Error().Err(err).Str("rule_set", string(ruleSet)).Msg("failed to manage decision policy")
+	return c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   "Internal server error",
+		Details: err.Error(),
+	})
+}
+
+// RegisterRoutes registers the decision policy routes on the Echo instance.
+func (pc *DecisionPolicyController) RegisterRoutes(e *echo.Echo) {
+	e.GET("/rules/policies", pc.GetPolicies)
+	e.PUT("/rules/policies/:rule_set", pc.UpdatePolicy)
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/usecase"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// --- Hand-written mocks ---
+
+type mockDecisionPolicyRepository struct {
+	policies map[entity.RuleSet]entity.DecisionPolicy
+}
+
+func (m *mockDecisionPolicyRepository) FindAll(_ context.Context) ([]entity.DecisionPolicy, error) {
+	var policies []entity.DecisionPolicy
+	for _, policy := range m.policies {
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+func (m *mockDecisionPolicyRepository) FindByRuleSet(_ context.Context, ruleSet entity.RuleSet) (*entity.DecisionPolicy, error) {
+	policy, ok := m.policies[ruleSet]
+	if !ok {
+		return nil, nil
+	}
+	return &policy, nil
+}
+
+func (m *mockDecisionPolicyRepository) Save(_ context.Context, policy *entity.DecisionPolicy) error {
+	m.policies[policy.RuleSet] = *policy
+	return nil
+}
+
+// --- Helper ---
+
+func newDecisionPolicyController(policyRepo *mockDecisionPolicyRepository) *echo.Echo {
+	controller := NewDecisionPolicyController(
+		usecase.NewGetDecisionPoliciesUseCase(policyRepo),
+		usecase.NewUpdateDecisionPolicyUseCase(policyRepo, nil),
+		zerolog.Nop(),
+	)
+
+	e := echo.New()
+	controller.RegisterRoutes(e)
+
+	return e
+}
+
+// --- Tests ---
+
+func TestDecisionPolicyController_GetPolicies(t *testing.T) {
+	e := newDecisionPolicyController(&mockDecisionPolicyRepository{policies: map[entity.RuleSet]entity.DecisionPolicy{
+		entity.RuleSetFraudScore: {RuleSet: entity.RuleSetFraudScore, Mode: entity.ModeScore, DeclineThreshold: 50},
+	}})
+
+	rec := serveRuleRequest(e, http.MethodGet, "/rules/policies", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Data []entity.DecisionPolicy `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Data) != 2 {
+		t.Fatalf("expected one policy per rule set, got %d", len(resp.Data))
+	}
+	if resp.Data[0].Mode != entity.ModeFirstMatch {
+		t.Errorf("expected the default policy for TRANSACTION, got %s", resp.Data[0].Mode)
+	}
+	if resp.Data[1].Mode != entity.ModeScore {
+		t.Errorf("expected the stored policy for FRAUD_SCORE, got %s", resp.Data[1].Mode)
+	}
+}
+
+func TestDecisionPolicyController_UpdatePolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "should return 200 with the stored policy",
+			target:     "/rules/policies/TRANSACTION",
+			body:       `{"mode":"SCORE","fraud_check_threshold":40,"decline_threshold":80}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "should return 400 for an unknown rule set",
+			target:     "/rules/policies/CHECKOUT",
+			body:       `{"mode":"FIRST_MATCH"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "should return 400 when thresholds are inverted",
+			target:     "/rules/policies/TRANSACTION",
+			body:       `{"mode":"SCORE","fraud_check_threshold":90,"decline_threshold":80}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			policyRepo := &mockDecisionPolicyRepository{policies: map[entity.RuleSet]entity.DecisionPolicy{}}
+			e := newDecisionPolicyController(policyRepo)
+
+			rec := serveRuleRequest(e, http.MethodPut, tc.target, tc.body)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			if _, stored := policyRepo.policies[entity.RuleSetTransaction]; stored != (tc.wantStatus == http.StatusOK) {
+				t.Errorf("expected policy stored=%v", tc.wantStatus == http.StatusOK)
+			}
+		})
+	}
+}
@@ -18,7 +18,8 @@ import (
 // --- Hand-written mocks ---
 
 type mockRuleEvaluationRepository struct {
-	findFunc func(ctx context.Context, transactionID string) ([]entity.RuleEvaluationResult, error)
+	findFunc   func(ctx context.Context, transactionID string) ([]entity.RuleEvaluationResult, error)
+	findShadow func(ctx context.Context, ruleID string, since time.Time) ([]entity.RuleEvaluationResult, error)
 }
 
 func (m *mockRuleEvaluationRepository) SaveBatch(_ context.Context, _ []entity.RuleEvaluationResult) error {
@@ -32,8 +33,18 @@ func (m *mockRuleEvaluationRepository) FindByTransactionID(ctx context.Context,
 	return nil, nil
 }
 
+func (m *mockRuleEvaluationRepository) FindShadowByRuleID(ctx context.Context, ruleID string, since time.Time) ([]entity.RuleEvaluationResult, error) {
+	if m.findShadow != nil {
+		return m.findShadow(ctx, ruleID, since)
+	}
+	return nil, nil
+}
+
 type mockRuleRepository struct {
-	findAllFunc func(ctx context.Context) ([]entity.Rule, error)
+	findAllFunc  func(ctx context.Context) ([]entity.Rule, error)
+	findByIDFunc func(ctx context.Context, ruleID string) (*entity.Rule, error)
+	writeErr     error
+	history      *mockRuleHistoryRepository
 }
 
 func (m *mockRuleRepository) FindActiveRulesSortedByPriority(_ context.Context) ([]entity.Rule, error) {
@@ -47,6 +58,54 @@ func (m *mockRuleRepository) FindAll(ctx context.Context) ([]entity.Rule, error)
 	return nil, nil
 }
 
+func (m *mockRuleRepository) FindByID(ctx context.Context, ruleID string) (*entity.Rule, error) {
+	if m.findByIDFunc != nil {
+		return m.findByIDFunc(ctx, ruleID)
+	}
+	return nil, nil
+}
+
+// ApplyChanges records the versions of a successful change set in the linked history.
+func (m *mockRuleRepository) ApplyChanges(_ context.Context, set *entity.RuleChangeSet) error {
+	if m.writeErr != nil {
+		return m.writeErr
+	}
+	if m.history == nil {
+		return nil
+	}
+	for _, change := range set.Changes {
+		if change.Baseline != nil {
+			m.history.versions = append(m.history.versions, *change.Baseline)
+		}
+		m.history.versions = append(m.history.versions, change.Version)
+	}
+	m.history.rulesetVersion = set.RulesetVersion
+	return nil
+}
+
+type mockRuleHistoryRepository struct {
+	versions       []entity.RuleVersion
+	rulesetVersion int
+}
+
+func (m *mockRuleHistoryRepository) FindByRuleID(_ context.Context, ruleID string) ([]entity.RuleVersion, error) {
+	var versions []entity.RuleVersion
+	for _, v := range m.versions {
+		if v.RuleID == ruleID {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+func (m *mockRuleHistoryRepository) FindAll(_ context.Context) ([]entity.RuleVersion, error) {
+	return m.versions, nil
+}
+
+func (m *mockRuleHistoryRepository) CurrentRulesetVersion(_ context.Context) (int, error) {
+	return m.rulesetVersion, nil
+}
+
 // --- Helper ---
 
 func newEvaluationController(
@@ -0,0 +1,318 @@
+package http
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/usecase"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// ListRequest is the request body for creating a managed list.
+type ListRequest struct {
+	Name        string                `json:"name"`
+	Type        entity.ListType       `json:"type"`
+	Field       entity.ConditionField `json:"field"`
+	Description string                `json:"description"`
+}
+
+// ListEntryRequest is a single entry to add to a managed list.
+type ListEntryRequest struct {
+	Value     string     `json:"value"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// AddListEntriesRequest is the request body for adding entries to a managed list.
+type AddListEntriesRequest struct {
+	Entries []ListEntryRequest `json:"entries"`
+}
+
+// ListDetails is a managed list together with its entries.
+type ListDetails struct {
+	entity.ManagedList
+	Entries []entity.ListEntry `json:"entries"`
+}
+
+// ListController handles HTTP endpoints for managing blocklists and allowlists.
+type ListController struct {
+	createListUseCase      *usecase.CreateListUseCase
+	getListsUseCase        *usecase.GetListsUseCase
+	getListUseCase         *usecase.GetListUseCase
+	deleteListUseCase      *usecase.DeleteListUseCase
+	addListEntriesUseCase  *usecase.AddListEntriesUseCase
+	removeListEntryUseCase *usecase.RemoveListEntryUseCase
+	logger                 zerolog.Logger
+}
+
+// NewListController creates a new ListController.
+func NewListController(
+	createListUseCase *usecase.CreateListUseCase,
+	getListsUseCase *usecase.GetListsUseCase,
+	getListUseCase *usecase.GetListUseCase,
+	deleteListUseCase *usecase.DeleteListUseCase,
+	addListEntriesUseCase *usecase.AddListEntriesUseCase,
+	removeListEntryUseCase *usecase.RemoveListEntryUseCase,
+	logger zerolog.Logger,
+) *ListController {
+	return &ListController{
+		createListUseCase:      createListUseCase,
+		getListsUseCase:        getListsUseCase,
+		getListUseCase:         getListUseCase,
+		deleteListUseCase:      deleteListUseCase,
+		addListEntriesUseCase:  addListEntriesUseCase,
+		removeListEntryUseCase: removeListEntryUseCase,
+		logger:                 logger,
+	}
+}
+
+// GetLists handles GET /lists.
+func (lc *ListController) GetLists(c *echo.Context) error {
+	lists, err := lc.getListsUseCase.Execute(c.Request().Context())
+	if err != nil {
+		return lc.handleError(c, err, "")
+	}
+
+	if lists == nil {
+		lists = []entity.ManagedList{}
+	}
+
+	return c.JSON(http.StatusOK, DataResponse{Data: lists})
+}
+
+// CreateList handles POST /lists.
+func (lc *ListController) CreateList(c *echo.Context) error {
+	var req ListRequest
+	if err := c.Bind(&req); err != nil {
+		lc.logger.Warn().Err(err).Msg("failed to bind list request body")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	list, err := lc.createListUseCase.Execute(c.Request().Context(), &entity.ManagedList{
+		Name:        req.Name,
+		Type:        req.Type,
+		Field:       req.Field,
+		Description: req.Description,
+	}, changedBy(c))
+	if err != nil {
+		return lc.handleError(c, err, req.Name)
+	}
+
+	lc.logger.Info().Str("list_name", list.Name).Str("type", string(list.Type)).Msg("list created")
+
+	return c.JSON(http.StatusCreated, DataResponse{Data: list})
+}
+
+// GetList handles GET /lists/:name.
+func (lc *ListController) GetList(c *echo.Context) error {
+	name := c.Param("name")
+
+	list, entries, err := lc.getListUseCase.Execute(c.Request().Context(), name)
+	if err != nil {
+		return lc.handleError(c, err, name)
+	}
+
+	if entries == nil {
+		entries = []entity.ListEntry{}
+	}
+
+	return c.JSON(http.StatusOK, DataResponse{Data: ListDetails{ManagedList: *list, Entries: entries}})
+}
+
+// DeleteList handles DELETE /lists/:name.
+func (lc *ListController) DeleteList(c *echo.Context) error {
+	name := c.Param("name")
+
+	if err := lc.deleteListUseCase.Execute(c.Request().Context(), name); err != nil {
+		return lc.handleError(c, err, name)
+	}
+
+	lc.logger.Info().Str("list_name", name).Str("changed_by", changedBy(c)).Msg("list deleted")
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AddEntries handles POST /lists/:name/entries.
+func (lc *ListController) AddEntries(c *echo.Context) error {
+	name := c.Param("name")
+
+	var req AddListEntriesRequest
+	if err := c.Bind(&req); err != nil {
+		lc.logger.Warn().Err(err).Str("list_name", name).Msg("failed to bind list entries body")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	entries := make([]entity.ListEntry, len(req.Entries))
+	for i, e := range req.Entries {
+		entries[i] = entity.ListEntry{Value: e.Value, Reason: e.Reason, ExpiresAt: e.ExpiresAt}
+	}
+
+	return lc.addEntries(c, name, entries)
+}
+
+// ImportEntries handles POST /lists/:name/import. The body is CSV with the columns
+// value, reason and expires_at (RFC 3339); only value is required, and a header row
+// starting with "value" is skipped.
+func (lc *ListController) ImportEntries(c *echo.Context) error {
+	name := c.Param("name")
+
+	entries, violations, err := parseListEntriesCSV(c.Request().Body)
+	if err != nil {
+		lc.logger.Warn().Err(err).Str("list_name", name).Msg("failed to parse list import")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid CSV body",
+			Details: err.Error(),
+		})
+	}
+	if len(violations) > 0 {
+		return c.JSON(http.StatusBadRequest, ValidationErrorResponse{
+			Error:      "Validation failed",
+			Details:    fmt.Sprintf("%d invalid rows", len(violations)),
+			Violations: violations,
+		})
+	}
+
+	return lc.addEntries(c, name, entries)
+}
+
+// RemoveEntry handles DELETE /lists/:name/entries/:value.
+func (lc *ListController) RemoveEntry(c *echo.Context) error {
+	name := c.Param("name")
+	value := c.Param("value")
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		value = unescaped
+	}
+
+	if err := lc.removeListEntryUseCase.Execute(c.Request().Context(), name, value); err != nil {
+		return lc.handleError(c, err, name)
+	}
+
+	lc.logger.Info().Str("list_name", name).Str("changed_by", changedBy(c)).Msg("list entry removed")
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (lc *ListController) addEntries(c *echo.Context, name string, entries []entity.ListEntry) error {
+	added, err := lc.addListEntriesUseCase.Execute(c.Request().Context(), name, entries, changedBy(c))
+	if err != nil {
+		return lc.handleError(c, err, name)
+	}
+
+	lc.logger.Info().Str("list_name", name).Int("count", len(added)).Msg("list entries added")
+
+	return c.JSON(http.StatusOK, DataResponse{Data: added})
+}
+
+// parseListEntriesCSV reads list entries from CSV. Rows with an unparseable
+// expires_at are reported as violations using the same entries[i] paths as the
+// use case, where i counts data rows from zero.
+func parseListEntriesCSV(body io.Reader) ([]entity.ListEntry, []entity.RuleViolation, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var entries []entity.ListEntry
+	var violations []entity.RuleViolation
+	for row := 0; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if row == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "value") {
+			continue
+		}
+
+		entry := entity.ListEntry{Value: record[0]}
+		if len(record) > 1 {
+			entry.Reason = strings.TrimSpace(record[1])
+		}
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			expiresAt, err := time.Parse(time.RFC3339, strings.TrimSpace(record[2]))
+			if err != nil {
+				violations = append(violations, entity.RuleViolation{
+					Field:   fmt.Sprintf("entries[%d].expires_at", len(entries)),
+					Message: fmt.Sprintf("expires_at %q must be an RFC 3339 timestamp", record[2]),
+				})
+			}
+			entry.ExpiresAt = &expiresAt
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, violations, nil
+}
+
+// handleError maps list use case errors to HTTP responses.
+func (lc *ListController) handleError(c *echo.Context, err error, name string) error {
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		lc.logger.Warn().Err(err).Str("list_name", name).Msg("list validation failed")
+		return c.JSON(http.StatusBadRequest, ValidationErrorResponse{
+			Error:      "Validation failed",
+			Details:    err.Error(),
+			Violations: validationErr.Violations,
+		})
+	}
+
+	switch {
+	case errors.Is(err, usecase.ErrListNameEmpty), errors.Is(err, usecase.ErrListEntriesEmpty):
+		lc.logger.Warn().Err(err).Str("list_name", name).Msg("invalid list request")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Details: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrListNotFound):
+		lc.logger.Warn().Str("list_name", name).Msg("list not found")
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "List not found",
+			Details: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrListEntryNotFound):
+		lc.logger.Warn().Str("list_name", name).Msg("list entry not found")
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "List entry not found",
+			Details: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrListAlreadyExists), errors.Is(err, usecase.ErrListInUse):
+		lc.logger.Warn().Err(err).Str("list_name", name).Msg("list conflict")
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "List conflict",
+			Details: err.Error(),
+		})
+	default:
+		lc.logger.Error().Err(err).Str("list_name", name).Msg("failed to manage list")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
+
+// RegisterRoutes registers the list management routes on the Echo instance.
+func (lc *ListController) RegisterRoutes(e *echo.Echo) {
+	e.GET("/lists", lc.GetLists)
+	e.POST("/lists", lc.CreateList)
+	e.GET("/lists/:name", lc.GetList)
+	e.DELETE("/lists/:name", lc.DeleteList)
+	e.POST("/lists/:name/entries", lc.AddEntries)
+	e.POST("/lists/:name/import", lc.ImportEntries)
+	e.DELETE("/lists/:name/entries/:value", lc.RemoveEntry)
+}
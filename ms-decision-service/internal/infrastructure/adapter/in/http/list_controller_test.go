@@ -0,0 +1,293 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/usecase"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// --- Hand-written mocks ---
+
+// mockListRepository keeps lists and entries in memory.
+type mockListRepository struct {
+	lists   map[string]entity.ManagedList
+	entries map[string]map[string]entity.ListEntry
+}
+
+func newMockListRepository() *mockListRepository {
+	return &mockListRepository{
+		lists:   map[string]entity.ManagedList{},
+		entries: map[string]map[string]entity.ListEntry{},
+	}
+}
+
+func (m *mockListRepository) FindAll(_ context.Context) ([]entity.ManagedList, error) {
+	var lists []entity.ManagedList
+	for _, list := range m.lists {
+		lists = append(lists, list)
+	}
+	sort.Slice(lists, func(i, j int) bool { return lists[i].Name < lists[j].Name })
+	return lists, nil
+}
+
+func (m *mockListRepository) FindByName(_ context.Context, name string) (*entity.ManagedList, error) {
+	list, ok := m.lists[name]
+	if !ok {
+		return nil, nil
+	}
+	return &list, nil
+}
+
+func (m *mockListRepository) FindEntries(_ context.Context, name string) ([]entity.ListEntry, error) {
+	var entries []entity.ListEntry
+	for _, entry := range m.entries[name] {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Value < entries[j].Value })
+	return entries, nil
+}
+
+func (m *mockListRepository) Create(_ context.Context, list *entity.ManagedList) error {
+	m.lists[list.Name] = *list
+	m.entries[list.Name] = map[string]entity.ListEntry{}
+	return nil
+}
+
+func (m *mockListRepository) Delete(_ context.Context, name string) error {
+	delete(m.lists, name)
+	delete(m.entries, name)
+	return nil
+}
+
+func (m *mockListRepository) PutEntries(_ context.Context, entries []entity.ListEntry) error {
+	for _, entry := range entries {
+		m.entries[entry.ListName][entry.Value] = entry
+	}
+	return nil
+}
+
+func (m *mockListRepository) DeleteEntry(_ context.Context, name, value string) error {
+	delete(m.entries[name], value)
+	return nil
+}
+
+func (m *mockListRepository) LoadSnapshot(ctx context.Context) (*entity.ListSnapshot, error) {
+	lists, _ := m.FindAll(ctx)
+	var entries []entity.ListEntry
+	for name := range m.entries {
+		listEntries, _ := m.FindEntries(ctx, name)
+		entries = append(entries, listEntries...)
+	}
+	return entity.NewListSnapshot(lists, entries), nil
+}
+
+// --- Helper ---
+
+func newListController(listRepo *mockListRepository) *echo.Echo {
+	return newListControllerWithRules(listRepo, &mockRuleRepository{})
+}
+
+func newListControllerWithRules(listRepo *mockListRepository, ruleRepo *mockRuleRepository) *echo.Echo {
+	controller := NewListController(
+		usecase.NewCreateListUseCase(listRepo),
+		usecase.NewGetListsUseCase(listRepo),
+		usecase.NewGetListUseCase(listRepo),
+		usecase.NewDeleteListUseCase(listRepo, ruleRepo),
+		usecase.NewAddListEntriesUseCase(listRepo),
+		usecase.NewRemoveListEntryUseCase(listRepo),
+		zerolog.Nop(),
+	)
+
+	e := echo.New()
+	controller.RegisterRoutes(e)
+
+	return e
+}
+
+func newListRepositoryWithEmailBlocklist() *mockListRepository {
+	listRepo := newMockListRepository()
+	_ = listRepo.Create(context.Background(), &entity.ManagedList{
+		Name:  "blocked-emails",
+		Type:  entity.ListBlocklist,
+		Field: entity.FieldCustomerEmail,
+	})
+	return listRepo
+}
+
+// --- Tests ---
+
+func TestListController_CreateList(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "should return 201 with the created list",
+			body:       `{"name":"blocked-ips","type":"BLOCKLIST","field":"customer_ip_address"}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "should return 400 for an invalid list",
+			body:       `{"name":"Blocked IPs","type":"DENYLIST","field":"customer_ip_address"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "should return 409 when the name is taken",
+			body:       `{"name":"blocked-emails","type":"BLOCKLIST","field":"customer_email"}`,
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newListController(newListRepositoryWithEmailBlocklist())
+
+			rec := serveRuleRequest(e, http.MethodPost, "/lists", tc.body)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestListController_AddAndGetEntries(t *testing.T) {
+	listRepo := newListRepositoryWithEmailBlocklist()
+	e := newListController(listRepo)
+
+	rec := serveRuleRequest(e, http.MethodPost, "/lists/blocked-emails/entries",
+		`{"entries":[{"value":"Fraud@Example.com","reason":"chargeback"}]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serveRuleRequest(e, http.MethodGet, "/lists/blocked-emails", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Data ListDetails `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Data.Name != "blocked-emails" || len(resp.Data.Entries) != 1 {
+		t.Fatalf("unexpected list details: %+v", resp.Data)
+	}
+	if resp.Data.Entries[0].Value != "fraud@example.com" {
+		t.Errorf("expected normalized value, got %q", resp.Data.Entries[0].Value)
+	}
+
+	rec = serveRuleRequest(e, http.MethodDelete, "/lists/blocked-emails/entries/fraud%40example.com", "")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(listRepo.entries["blocked-emails"]) != 0 {
+		t.Error("expected the entry to be removed")
+	}
+}
+
+func TestListController_ImportEntries(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		body        string
+		wantStatus  int
+		wantEntries int
+	}{
+		{
+			name:        "should import every row and skip the header",
+			target:      "/lists/blocked-emails/import",
+			body:        "value,reason,expires_at\nfraud@example.com,chargeback,\nmule@example.com,mule,2099-01-01T00:00:00Z\n",
+			wantStatus:  http.StatusOK,
+			wantEntries: 2,
+		},
+		{
+			name:       "should return 400 for an invalid expiry",
+			target:     "/lists/blocked-emails/import",
+			body:       "fraud@example.com,chargeback,tomorrow\n",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "should return 404 for an unknown list",
+			target:     "/lists/missing/import",
+			body:       "fraud@example.com\n",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			listRepo := newListRepositoryWithEmailBlocklist()
+			e := newListController(listRepo)
+
+			req := httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, "text/csv")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			if got := len(listRepo.entries["blocked-emails"]); got != tc.wantEntries {
+				t.Errorf("expected %d stored entries, got %d", tc.wantEntries, got)
+			}
+		})
+	}
+}
+
+func TestListController_DeleteList(t *testing.T) {
+	listRepo := newListRepositoryWithEmailBlocklist()
+	e := newListController(listRepo)
+
+	rec := serveRuleRequest(e, http.MethodDelete, "/lists/blocked-emails", "")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serveRuleRequest(e, http.MethodGet, "/lists/blocked-emails", "")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", rec.Code)
+	}
+}
+
+func TestListController_DeleteListReferencedByActiveRule(t *testing.T) {
+	listRepo := newListRepositoryWithEmailBlocklist()
+	ruleRepo := &mockRuleRepository{
+		findAllFunc: func(_ context.Context) ([]entity.Rule, error) {
+			return []entity.Rule{{
+				RuleID:            "rule-blocklist",
+				ConditionField:    entity.FieldCustomerEmail,
+				ConditionOperator: entity.OpInList,
+				ConditionValue:    "blocked-emails",
+				IsActive:          true,
+				Mode:              entity.RuleModeShadow,
+			}}, nil
+		},
+	}
+	e := newListControllerWithRules(listRepo, ruleRepo)
+
+	rec := serveRuleRequest(e, http.MethodDelete, "/lists/blocked-emails", "")
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "rule-blocklist") {
+		t.Errorf("expected the referencing rule to be named, got %s", rec.Body.String())
+	}
+
+	rec = serveRuleRequest(e, http.MethodGet, "/lists/blocked-emails", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the list to be kept, got status %d", rec.Code)
+	}
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"ms-decision-service/internal/domain/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// RuleCacheController handles HTTP endpoints for inspecting and refreshing the in-memory rule cache.
+type RuleCacheController struct {
+	getRuleCacheStatusUseCase *usecase.GetRuleCacheStatusUseCase
+	refreshRuleCacheUseCase   *usecase.RefreshRuleCacheUseCase
+	logger                    zerolog.Logger
+}
+
+// NewRuleCacheController creates a new RuleCacheController.
+func NewRuleCacheController(
+	getRuleCacheStatusUseCase *usecase.GetRuleCacheStatusUseCase,
+	refreshRuleCacheUseCase *usecase.RefreshRuleCacheUseCase,
+	logger zerolog.Logger,
+) *RuleCacheController {
+	return &RuleCacheController{
+		getRuleCacheStatusUseCase: getRuleCacheStatusUseCase,
+		refreshRuleCacheUseCase:   refreshRuleCacheUseCase,
+		logger:                    logger,
+	}
+}
+
+// GetStatus handles GET /rules/cache.
+func (rc *RuleCacheController) GetStatus(c *echo.Context) error {
+	return c.JSON(http.StatusOK, DataResponse{Data: rc.getRuleCacheStatusUseCase.Execute()})
+}
+
+// Refresh handles POST /rules/cache/refresh.
+func (rc *RuleCacheController) Refresh(c *echo.Context) error {
+	status, err := rc.refreshRuleCacheUseCase.Execute(c.Request().Context())
+	if err != nil {
+		rc.logger.Error().Err(err).Msg("failed to refresh rule cache")
+		return c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Rule cache refresh failed",
+			Details: err.Error(),
+		})
+	}
+
+	rc.logger.Info().
+		Int("ruleset_version", status.RulesetVersion).
+		Int("rule_count", status.RuleCount).
+		Msg("rule cache refreshed on demand")
+
+	return c.JSON(http.StatusOK, DataResponse{Data: status})
+}
+
+// RegisterRoutes registers the rule cache routes on the Echo instance.
+func (rc *RuleCacheController) RegisterRoutes(e *echo.Echo) {
+	e.GET("/rules/cache", rc.GetStatus)
+	e.POST("/rules/cache/refresh", rc.Refresh)
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/usecase"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// --- Hand-written mock ---
+
+type mockRuleCache struct {
+	refreshErr error
+	status     entity.RuleCacheStatus
+}
+
+func (m *mockRuleCache) Refresh(_ context.Context) error {
+	return m.refreshErr
+}
+
+func (m *mockRuleCache) Status() entity.RuleCacheStatus {
+	return m.status
+}
+
+func newRuleCacheController(cache *mockRuleCache) *echo.Echo {
+	controller := NewRuleCacheController(
+		usecase.NewGetRuleCacheStatusUseCase(cache),
+		usecase.NewRefreshRuleCacheUseCase(cache),
+		zerolog.Nop(),
+	)
+
+	e := echo.New()
+	controller.RegisterRoutes(e)
+
+	return e
+}
+
+func TestRuleCacheController_GetStatus(t *testing.T) {
+	e := newRuleCacheController(&mockRuleCache{
+		status: entity.RuleCacheStatus{RulesetVersion: 5, RuleCount: 2, AgeSeconds: 12.5},
+	})
+
+	rec := serveRuleRequest(e, http.MethodGet, "/rules/cache", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		Data entity.RuleCacheStatus `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Data.RulesetVersion != 5 || body.Data.RuleCount != 2 || body.Data.AgeSeconds != 12.5 {
+		t.Errorf("unexpected status: %+v", body.Data)
+	}
+}
+
+func TestRuleCacheController_Refresh(t *testing.T) {
+	tests := []struct {
+		name       string
+		refreshErr error
+		wantStatus int
+	}{
+		{
+			name:       "successful refresh returns 200",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "refresh failure returns 503",
+			refreshErr: errors.New("dynamo unavailable"),
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newRuleCacheController(&mockRuleCache{
+				refreshErr: tt.refreshErr,
+				status:     entity.RuleCacheStatus{RulesetVersion: 5},
+			})
+
+			rec := serveRuleRequest(e, http.MethodPost, "/rules/cache/refresh", "")
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
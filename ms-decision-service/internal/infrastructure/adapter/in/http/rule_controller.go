@@ -0,0 +1,343 @@
+package http
+
+import (
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// RuleRequest is the request body for creating or replacing a rule.
+// IsActive defaults to true when omitted.
+type RuleRequest struct {
+	RuleID            string                   `json:"rule_id"`
+	RuleName          string                   `json:"rule_name"`
+	ConditionField    entity.ConditionField    `json:"condition_field"`
+	ConditionOperator entity.ConditionOperator `json:"condition_operator"`
+	ConditionValue    string                   `json:"condition_value"`
+	Condition         *entity.ConditionNode    `json:"condition,omitempty"`
+	ResultStatus      entity.DecisionStatus    `json:"result_status"`
+	Priority          int                      `json:"priority"`
+	ScoreWeight       int                      `json:"score_weight"`
+	IsActive          *bool                    `json:"is_active"`
+	Mode              entity.RuleMode          `json:"mode"`
+}
+
+func (r *RuleRequest) toRule() *entity.Rule {
+	isActive := true
+	if r.IsActive != nil {
+		isActive = *r.IsActive
+	}
+
+	mode := r.Mode
+	if mode == "" {
+		mode = entity.RuleModeLive
+	}
+
+	return &entity.Rule{
+		RuleID:            r.RuleID,
+		RuleName:          r.RuleName,
+		ConditionField:    r.ConditionField,
+		ConditionOperator: r.ConditionOperator,
+		ConditionValue:    r.ConditionValue,
+		Condition:         r.Condition,
+		ResultStatus:      r.ResultStatus,
+		Priority:          r.Priority,
+		ScoreWeight:       r.ScoreWeight,
+		IsActive:          isActive,
+		Mode:              mode,
+	}
+}
+
+// changedByHeader identifies who made a rule change; it is recorded in the rule history.
+const changedByHeader = "X-Changed-By"
+
+// anonymousActor is recorded when a rule change carries no X-Changed-By header.
+const anonymousActor = "anonymous"
+
+// SetRuleActiveRequest is the request body for enabling or disabling a rule.
+type SetRuleActiveRequest struct {
+	IsActive *bool `json:"is_active"`
+}
+
+// RollbackRuleRequest is the request body for restoring a previous version of a rule.
+type RollbackRuleRequest struct {
+	Version *int `json:"version"`
+}
+
+// RollbackRulesetRequest is the request body for restoring the whole rule set to a previous version.
+type RollbackRulesetRequest struct {
+	RulesetVersion *int `json:"ruleset_version"`
+}
+
+// ValidationErrorResponse represents a validation failure with one entry per violation.
+type ValidationErrorResponse struct {
+	Error      string                 `json:"error" example:"Validation failed"`
+	Details    string                 `json:"details"`
+	Violations []entity.RuleViolation `json:"violations"`
+}
+
+// RuleController handles HTTP endpoints for managing rules.
+type RuleController struct {
+	createRuleUseCase      *usecase.CreateRuleUseCase
+	updateRuleUseCase      *usecase.UpdateRuleUseCase
+	setRuleActiveUseCase   *usecase.SetRuleActiveUseCase
+	deleteRuleUseCase      *usecase.DeleteRuleUseCase
+	getRuleHistoryUseCase  *usecase.GetRuleHistoryUseCase
+	rollbackRuleUseCase    *usecase.RollbackRuleUseCase
+	rollbackRulesetUseCase *usecase.RollbackRulesetUseCase
+	logger                 zerolog.Logger
+}
+
+// NewRuleController creates a new RuleController.
+func NewRuleController(
+	createRuleUseCase *usecase.CreateRuleUseCase,
+	updateRuleUseCase *usecase.UpdateRuleUseCase,
+	setRuleActiveUseCase *usecase.SetRuleActiveUseCase,
+	deleteRuleUseCase *usecase.DeleteRuleUseCase,
+	getRuleHistoryUseCase *usecase.GetRuleHistoryUseCase,
+	rollbackRuleUseCase *usecase.RollbackRuleUseCase,
+	rollbackRulesetUseCase *usecase.RollbackRulesetUseCase,
+	logger zerolog.Logger,
+) *RuleController {
+	return &RuleController{
+		createRuleUseCase:      createRuleUseCase,
+		updateRuleUseCase:      updateRuleUseCase,
+		setRuleActiveUseCase:   setRuleActiveUseCase,
+		deleteRuleUseCase:      deleteRuleUseCase,
+		getRuleHistoryUseCase:  getRuleHistoryUseCase,
+		rollbackRuleUseCase:    rollbackRuleUseCase,
+		rollbackRulesetUseCase: rollbackRulesetUseCase,
+		logger:                 logger,
+	}
+}
+
+// CreateRule handles POST /rules.
+func (rc *RuleController) CreateRule(c *echo.Context) error {
+	var req RuleRequest
+	if err := c.Bind(&req); err != nil {
+		rc.logger.Warn().Err(err).Msg("failed to bind rule request body")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	rule, err := rc.createRuleUseCase.Execute(c.Request().Context(), req.toRule(), changedBy(c))
+	if err != nil {
+		return rc.handleError(c, err, req.RuleID)
+	}
+
+	rc.logger.Info().Str("rule_id", rule.RuleID).Int("priority", rule.Priority).Msg("rule created")
+
+	return c.JSON(http.StatusCreated, DataResponse{Data: rule})
+}
+
+// UpdateRule handles PUT /rules/:rule_id.
+func (rc *RuleController) UpdateRule(c *echo.Context) error {
+	ruleID := c.Param("rule_id")
+
+	var req RuleRequest
+	if err := c.Bind(&req); err != nil {
+		rc.logger.Warn().Err(err).Str("rule_id", ruleID).Msg("failed to bind rule request body")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	rule, err := rc.updateRuleUseCase.Execute(c.Request().Context(), ruleID, req.toRule(), changedBy(c))
+	if err != nil {
+		return rc.handleError(c, err, ruleID)
+	}
+
+	rc.logger.Info().Str("rule_id", rule.RuleID).Int("priority", rule.Priority).Msg("rule updated")
+
+	return c.JSON(http.StatusOK, DataResponse{Data: rule})
+}
+
+// SetRuleActive handles PATCH /rules/:rule_id.
+func (rc *RuleController) SetRuleActive(c *echo.Context) error {
+	ruleID := c.Param("rule_id")
+
+	var req SetRuleActiveRequest
+	if err := c.Bind(&req); err != nil {
+		rc.logger.Warn().Err(err).Str("rule_id", ruleID).Msg("failed to bind rule patch body")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	if req.IsActive == nil {
+		return c.JSON(http.StatusBadRequest, ValidationErrorResponse{
+			Error:      "Validation failed",
+			Details:    "is_active is required",
+			Violations: []entity.RuleViolation{{Field: "is_active", Message: "is_active is required"}},
+		})
+	}
+
+	rule, err := rc.setRuleActiveUseCase.Execute(c.Request().Context(), ruleID, *req.IsActive, changedBy(c))
+	if err != nil {
+		return rc.handleError(c, err, ruleID)
+	}
+
+	rc.logger.Info().Str("rule_id", rule.RuleID).Bool("is_active", rule.IsActive).Msg("rule activation changed")
+
+	return c.JSON(http.StatusOK, DataResponse{Data: rule})
+}
+
+// DeleteRule handles DELETE /rules/:rule_id.
+func (rc *RuleController) DeleteRule(c *echo.Context) error {
+	ruleID := c.Param("rule_id")
+
+	if err := rc.deleteRuleUseCase.Execute(c.Request().Context(), ruleID, changedBy(c)); err != nil {
+		return rc.handleError(c, err, ruleID)
+	}
+
+	rc.logger.Info().Str("rule_id", ruleID).Msg("rule deleted")
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetRuleHistory handles GET /rules/:rule_id/history.
+func (rc *RuleController) GetRuleHistory(c *echo.Context) error {
+	ruleID := c.Param("rule_id")
+
+	versions, err := rc.getRuleHistoryUseCase.Execute(c.Request().Context(), ruleID)
+	if err != nil {
+		return rc.handleError(c, err, ruleID)
+	}
+
+	return c.JSON(http.StatusOK, DataResponse{Data: versions})
+}
+
+// RollbackRule handles POST /rules/:rule_id/rollback.
+func (rc *RuleController) RollbackRule(c *echo.Context) error {
+	ruleID := c.Param("rule_id")
+
+	var req RollbackRuleRequest
+	if err := c.Bind(&req); err != nil {
+		rc.logger.Warn().Err(err).Str("rule_id", ruleID).Msg("failed to bind rule rollback body")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	if req.Version == nil {
+		return c.JSON(http.StatusBadRequest, ValidationErrorResponse{
+			Error:      "Validation failed",
+			Details:    "version is required",
+			Violations: []entity.RuleViolation{{Field: "version", Message: "version is required"}},
+		})
+	}
+
+	version, err := rc.rollbackRuleUseCase.Execute(c.Request().Context(), ruleID, *req.Version, changedBy(c))
+	if err != nil {
+		return rc.handleError(c, err, ruleID)
+	}
+
+	rc.logger.Info().Str("rule_id", ruleID).Int("restored_version", *req.Version).
+		Int("version", version.Version).Msg("rule rolled back")
+
+	return c.JSON(http.StatusOK, DataResponse{Data: version})
+}
+
+// RollbackRuleset handles POST /rules/rollback.
+func (rc *RuleController) RollbackRuleset(c *echo.Context) error {
+	var req RollbackRulesetRequest
+	if err := c.Bind(&req); err != nil {
+		rc.logger.Warn().Err(err).Msg("failed to bind ruleset rollback body")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	if req.RulesetVersion == nil {
+		return c.JSON(http.StatusBadRequest, ValidationErrorResponse{
+			Error:      "Validation failed",
+			Details:    "ruleset_version is required",
+			Violations: []entity.RuleViolation{{Field: "ruleset_version", Message: "ruleset_version is required"}},
+		})
+	}
+
+	versions, err := rc.rollbackRulesetUseCase.Execute(c.Request().Context(), *req.RulesetVersion, changedBy(c))
+	if err != nil {
+		return rc.handleError(c, err, "")
+	}
+
+	rc.logger.Info().Int("ruleset_version", *req.RulesetVersion).Int("restored_rules", len(versions)).Msg("ruleset rolled back")
+
+	return c.JSON(http.StatusOK, DataResponse{Data: versions})
+}
+
+// changedBy returns the author of a rule change from the X-Changed-By header.
+func changedBy(c *echo.Context) string {
+	if actor := c.Request().Header.Get(changedByHeader); actor != "" {
+		return actor
+	}
+	return anonymousActor
+}
+
+// handleError maps rule use case errors to HTTP responses.
+func (rc *RuleController) handleError(c *echo.Context, err error, ruleID string) error {
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		rc.logger.Warn().Err(err).Str("rule_id", ruleID).Msg("rule validation failed")
+		return c.JSON(http.StatusBadRequest, ValidationErrorResponse{
+			Error:      "Validation failed",
+			Details:    err.Error(),
+			Violations: validationErr.Violations,
+		})
+	}
+
+	switch {
+	case errors.Is(err, usecase.ErrRuleIDEmpty):
+		rc.logger.Warn().Msg("empty rule_id parameter")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid rule_id parameter",
+			Details: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrRuleNotFound):
+		rc.logger.Warn().Str("rule_id", ruleID).Msg("rule not found")
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Rule not found",
+			Details: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrRuleVersionNotFound), errors.Is(err, usecase.ErrRulesetVersionNotFound):
+		rc.logger.Warn().Err(err).Str("rule_id", ruleID).Msg("version not found")
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Version not found",
+			Details: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrRuleAlreadyExists), errors.Is(err, usecase.ErrDuplicatePriority),
+		errors.Is(err, usecase.ErrRuleConflict):
+		rc.logger.Warn().Err(err).Str("rule_id", ruleID).Msg("rule conflict")
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Rule conflict",
+			Details: err.Error(),
+		})
+	default:
+		rc.logger.Error().Err(err).Str("rule_id", ruleID).Msg("failed to manage rule")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
+
+// RegisterRoutes registers the rule management routes on the Echo instance.
+func (rc *RuleController) RegisterRoutes(e *echo.Echo) {
+	e.POST("/rules", rc.CreateRule)
+	e.POST("/rules/rollback", rc.RollbackRuleset)
+	e.PUT("/rules/:rule_id", rc.UpdateRule)
+	e.PATCH("/rules/:rule_id", rc.SetRuleActive)
+	e.DELETE("/rules/:rule_id", rc.DeleteRule)
+	e.GET("/rules/:rule_id/history", rc.GetRuleHistory)
+	e.POST("/rules/:rule_id/rollback", rc.RollbackRule)
+}
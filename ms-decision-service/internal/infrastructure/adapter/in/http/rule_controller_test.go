@@ -0,0 +1,383 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/usecase"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// --- Helper ---
+
+func newRuleController(ruleRepo *mockRuleRepository) (*RuleController, *echo.Echo) {
+	return newRuleControllerWithHistory(ruleRepo, &mockRuleHistoryRepository{})
+}
+
+func newRuleControllerWithHistory(
+	ruleRepo *mockRuleRepository,
+	historyRepo *mockRuleHistoryRepository,
+) (*RuleController, *echo.Echo) {
+	ruleRepo.history = historyRepo
+	controller := NewRuleController(
+		usecase.NewCreateRuleUseCase(ruleRepo, historyRepo, nil),
+		usecase.NewUpdateRuleUseCase(ruleRepo, historyRepo, nil),
+		usecase.NewSetRuleActiveUseCase(ruleRepo, historyRepo),
+		usecase.NewDeleteRuleUseCase(ruleRepo, historyRepo),
+		usecase.NewGetRuleHistoryUseCase(ruleRepo, historyRepo),
+		usecase.NewRollbackRuleUseCase(ruleRepo, historyRepo),
+		usecase.NewRollbackRulesetUseCase(ruleRepo, historyRepo),
+		zerolog.Nop(),
+	)
+
+	e := echo.New()
+	controller.RegisterRoutes(e)
+
+	return controller, e
+}
+
+func serveRuleRequest(e *echo.Echo, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func findRuleOne(_ context.Context, ruleID string) (*entity.Rule, error) {
+	if ruleID != "rule-001" {
+		return nil, nil
+	}
+	return &entity.Rule{RuleID: "rule-001", RuleName: "Block CRYPTO payments", Priority: 1, IsActive: true}, nil
+}
+
+const cryptoRuleBody = `{
+	"rule_name": "Block CRYPTO payments",
+	"condition_field": "payment_method",
+	"condition_operator": "EQUAL",
+	"condition_value": "CRYPTO",
+	"result_status": "DECLINED",
+	"priority": 5
+}`
+
+// --- Tests ---
+
+func TestRuleController_CreateRule(t *testing.T) {
+	t.Run("should return 201 with the created rule", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{})
+
+		rec := serveRuleRequest(e, http.MethodPost, "/rules", cryptoRuleBody)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var body struct {
+			Data entity.Rule `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if !strings.HasPrefix(body.Data.RuleID, "rule-") {
+			t.Errorf("expected generated rule ID, got %q", body.Data.RuleID)
+		}
+		if !body.Data.IsActive {
+			t.Error("expected is_active to default to true")
+		}
+	})
+
+	t.Run("should return 400 with violations for an invalid rule", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{})
+
+		rec := serveRuleRequest(e, http.MethodPost, "/rules", `{
+			"rule_name": "Bad rule",
+			"condition_field": "currency",
+			"condition_operator": "GREATER_THAN",
+			"condition_value": "USD",
+			"result_status": "DECLINED",
+			"priority": 5
+		}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d", rec.Code)
+		}
+
+		var body ValidationErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(body.Violations) != 1 || body.Violations[0].Field != "condition_operator" {
+			t.Errorf("unexpected violations: %+v", body.Violations)
+		}
+	})
+
+	t.Run("should return 400 for a malformed body", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{})
+
+		rec := serveRuleRequest(e, http.MethodPost, "/rules", `{not json`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should return 409 when the priority is already taken", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{
+			findAllFunc: func(_ context.Context) ([]entity.Rule, error) {
+				return []entity.Rule{{RuleID: "rule-009", Priority: 5}}, nil
+			},
+		})
+
+		rec := serveRuleRequest(e, http.MethodPost, "/rules", cryptoRuleBody)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("expected status 409, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should return 500 when the repository fails", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{writeErr: errors.New("dynamo timeout")})
+
+		rec := serveRuleRequest(e, http.MethodPost, "/rules", cryptoRuleBody)
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("expected status 500, got %d", rec.Code)
+		}
+	})
+}
+
+func TestRuleController_UpdateRule(t *testing.T) {
+	t.Run("should return 200 with the replaced rule", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{findByIDFunc: findRuleOne})
+
+		rec := serveRuleRequest(e, http.MethodPut, "/rules/rule-001", cryptoRuleBody)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var body struct {
+			Data entity.Rule `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if body.Data.RuleID != "rule-001" || body.Data.Priority != 5 {
+			t.Errorf("unexpected rule: %+v", body.Data)
+		}
+	})
+
+	t.Run("should return 404 when the rule does not exist", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{findByIDFunc: findRuleOne})
+
+		rec := serveRuleRequest(e, http.MethodPut, "/rules/rule-404", cryptoRuleBody)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should return 409 when the rule keeps changing concurrently", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{
+			findByIDFunc: findRuleOne,
+			writeErr:     entity.ErrRuleChangeConflict,
+		})
+
+		rec := serveRuleRequest(e, http.MethodPut, "/rules/rule-001", cryptoRuleBody)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("expected status 409, got %d", rec.Code)
+		}
+	})
+}
+
+func TestRuleController_SetRuleActive(t *testing.T) {
+	t.Run("should return 200 with the disabled rule", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{findByIDFunc: findRuleOne})
+
+		rec := serveRuleRequest(e, http.MethodPatch, "/rules/rule-001", `{"is_active": false}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+
+		var body struct {
+			Data entity.Rule `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if body.Data.IsActive {
+			t.Error("expected rule to be inactive")
+		}
+	})
+
+	t.Run("should return 400 when is_active is missing", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{findByIDFunc: findRuleOne})
+
+		rec := serveRuleRequest(e, http.MethodPatch, "/rules/rule-001", `{}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should return 404 when the rule does not exist", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{findByIDFunc: findRuleOne})
+
+		rec := serveRuleRequest(e, http.MethodPatch, "/rules/rule-404", `{"is_active": true}`)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+}
+
+func TestRuleController_DeleteRule(t *testing.T) {
+	t.Run("should return 204 when the rule is deleted", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{findByIDFunc: findRuleOne})
+
+		rec := serveRuleRequest(e, http.MethodDelete, "/rules/rule-001", "")
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("expected status 204, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should return 404 when the rule does not exist", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{findByIDFunc: findRuleOne})
+
+		rec := serveRuleRequest(e, http.MethodDelete, "/rules/rule-404", "")
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+}
+
+func TestRuleController_GetRuleHistory(t *testing.T) {
+	t.Run("should return 200 with the versions and their author", func(t *testing.T) {
+		historyRepo := &mockRuleHistoryRepository{}
+		_, e := newRuleControllerWithHistory(&mockRuleRepository{findByIDFunc: findRuleOne}, historyRepo)
+
+		req := httptest.NewRequest(http.MethodPatch, "/rules/rule-001", strings.NewReader(`{"is_active": false}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("X-Changed-By", "analyst@example.com")
+		e.ServeHTTP(httptest.NewRecorder(), req)
+
+		rec := serveRuleRequest(e, http.MethodGet, "/rules/rule-001/history", "")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var body struct {
+			Data []entity.RuleVersion `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(body.Data) != 2 {
+			t.Fatalf("expected baseline and change versions, got %+v", body.Data)
+		}
+		if got := body.Data[1]; got.ChangeType != entity.RuleDeactivated || got.ChangedBy != "analyst@example.com" {
+			t.Errorf("unexpected version: %+v", got)
+		}
+	})
+
+	t.Run("should return 404 for an unknown rule", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{findByIDFunc: findRuleOne})
+
+		rec := serveRuleRequest(e, http.MethodGet, "/rules/rule-404/history", "")
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+}
+
+func TestRuleController_RollbackRule(t *testing.T) {
+	t.Run("should return 400 when version is missing", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{findByIDFunc: findRuleOne})
+
+		rec := serveRuleRequest(e, http.MethodPost, "/rules/rule-001/rollback", `{}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should return 404 for an unknown version", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{findByIDFunc: findRuleOne})
+
+		rec := serveRuleRequest(e, http.MethodPost, "/rules/rule-001/rollback", `{"version": 3}`)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should return 200 with the rollback version", func(t *testing.T) {
+		active, _ := findRuleOne(context.Background(), "rule-001")
+		active.Version = 1
+		historyRepo := &mockRuleHistoryRepository{
+			versions:       []entity.RuleVersion{{RuleID: "rule-001", Version: 1, ChangeType: entity.RuleCreated, Rule: *active}},
+			rulesetVersion: 2,
+		}
+		ruleRepo := &mockRuleRepository{
+			findByIDFunc: func(_ context.Context, _ string) (*entity.Rule, error) {
+				inactive := *active
+				inactive.IsActive = false
+				inactive.Version = 2
+				return &inactive, nil
+			},
+		}
+		_, e := newRuleControllerWithHistory(ruleRepo, historyRepo)
+
+		rec := serveRuleRequest(e, http.MethodPost, "/rules/rule-001/rollback", `{"version": 1}`)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var body struct {
+			Data entity.RuleVersion `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if body.Data.ChangeType != entity.RuleRolledBack || body.Data.RestoredVersion != 1 ||
+			body.Data.ChangedBy != "anonymous" || !body.Data.Rule.IsActive {
+			t.Errorf("unexpected version: %+v", body.Data)
+		}
+	})
+}
+
+func TestRuleController_RollbackRuleset(t *testing.T) {
+	t.Run("should return 400 when ruleset_version is missing", func(t *testing.T) {
+		_, e := newRuleController(&mockRuleRepository{})
+
+		rec := serveRuleRequest(e, http.MethodPost, "/rules/rollback", `{}`)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should return 404 for a future ruleset version", func(t *testing.T) {
+		_, e := newRuleControllerWithHistory(&mockRuleRepository{}, &mockRuleHistoryRepository{rulesetVersion: 2})
+
+		rec := serveRuleRequest(e, http.MethodPost, "/rules/rollback", `{"ruleset_version": 5}`)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"errors"
+	"ms-decision-service/internal/domain/usecase"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// ShadowReportController handles the HTTP endpoint reporting on SHADOW rules.
+type ShadowReportController struct {
+	getShadowReportUseCase *usecase.GetShadowReportUseCase
+	logger                 zerolog.Logger
+}
+
+// NewShadowReportController creates a new ShadowReportController.
+func NewShadowReportController(
+	getShadowReportUseCase *usecase.GetShadowReportUseCase,
+	logger zerolog.Logger,
+) *ShadowReportController {
+	return &ShadowReportController{
+		getShadowReportUseCase: getShadowReportUseCase,
+		logger:                 logger,
+	}
+}
+
+// GetShadowReport handles GET /rules/:rule_id/shadow-report. The optional since
+// query parameter is an RFC 3339 timestamp; it defaults to seven days ago.
+func (sc *ShadowReportController) GetShadowReport(c *echo.Context) error {
+	ruleID := c.Param("rule_id")
+
+	var since time.Time
+	if raw := c.QueryParam("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			sc.logger.Warn().Err(err).Str("since", raw).Msg("invalid since parameter")
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid since parameter",
+				Details: "since must be an RFC 3339 timestamp",
+			})
+		}
+		since = parsed
+	}
+
+	report, err := sc.getShadowReportUseCase.Execute(c.Request().Context(), ruleID, since)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrRuleIDEmpty):
+			sc.logger.Warn().Msg("empty rule_id parameter")
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid rule_id parameter",
+				Details: err.Error(),
+			})
+		case errors.Is(err, usecase.ErrRuleNotFound):
+			sc.logger.Warn().Str("rule_id", ruleID).Msg("rule not found")
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Rule not found",
+				Details: err.Error(),
+			})
+		default:
+			sc.logger.Error().Err(err).Str("rule_id", ruleID).Msg("failed to build shadow report")
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Internal server error",
+				Details: err.Error(),
+			})
+		}
+	}
+
+	sc.logger.Info().
+		Str("rule_id", ruleID).
+		Int("evaluated", report.Evaluated).
+		Int("decision_changes", report.DecisionChanges).
+		Msg("shadow report built")
+
+	return c.JSON(http.StatusOK, DataResponse{Data: report})
+}
+
+// RegisterRoutes registers the shadow report route on the Echo instance.
+func (sc *ShadowReportController) RegisterRoutes(e *echo.Echo) {
+	e.GET("/rules/:rule_id/shadow-report", sc.GetShadowReport)
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/usecase"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+func newShadowReportController(ruleEvalRepo *mockRuleEvaluationRepository) *echo.Echo {
+	ruleRepo := &mockRuleRepository{
+		findByIDFunc: func(_ context.Context, ruleID string) (*entity.Rule, error) {
+			if ruleID != "rule-shadow" {
+				return nil, nil
+			}
+			return &entity.Rule{RuleID: ruleID, RuleName: "New BIN rule", Mode: entity.RuleModeShadow}, nil
+		},
+	}
+	controller := NewShadowReportController(usecase.NewGetShadowReportUseCase(ruleRepo, ruleEvalRepo), zerolog.Nop())
+
+	e := echo.New()
+	controller.RegisterRoutes(e)
+
+	return e
+}
+
+func TestShadowReportController_GetShadowReport(t *testing.T) {
+	var gotSince time.Time
+	ruleEvalRepo := &mockRuleEvaluationRepository{
+		findShadow: func(_ context.Context, _ string, since time.Time) ([]entity.RuleEvaluationResult, error) {
+			gotSince = since
+			return []entity.RuleEvaluationResult{
+				{TransactionID: "txn-1", RuleID: "rule-shadow", Matched: true, Shadow: true, ShadowStatus: entity.DECLINED, ActualStatus: entity.APPROVED},
+				{TransactionID: "txn-2", RuleID: "rule-shadow", Shadow: true, ShadowStatus: entity.APPROVED, ActualStatus: entity.APPROVED},
+			}, nil
+		},
+	}
+	e := newShadowReportController(ruleEvalRepo)
+
+	rec := serveRuleRequest(e, http.MethodGet, "/rules/rule-shadow/shadow-report?since=2025-01-01T00:00:00Z", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !gotSince.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected since to be passed through, got %v", gotSince)
+	}
+
+	var body struct {
+		Data entity.ShadowReport `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Data.Evaluated != 2 || body.Data.Matched != 1 || body.Data.DecisionChanges != 1 {
+		t.Errorf("unexpected report: %+v", body.Data)
+	}
+}
+
+func TestShadowReportController_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		findErr    error
+		wantStatus int
+	}{
+		{
+			name:       "invalid since returns 400",
+			target:     "/rules/rule-shadow/shadow-report?since=yesterday",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown rule returns 404",
+			target:     "/rules/rule-missing/shadow-report",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "query failure returns 500",
+			target:     "/rules/rule-shadow/shadow-report",
+			findErr:    errors.New("index unavailable"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ruleEvalRepo := &mockRuleEvaluationRepository{
+				findShadow: func(_ context.Context, _ string, _ time.Time) ([]entity.RuleEvaluationResult, error) {
+					return nil, tc.findErr
+				},
+			}
+			e := newShadowReportController(ruleEvalRepo)
+
+			rec := serveRuleRequest(e, http.MethodGet, tc.target, "")
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
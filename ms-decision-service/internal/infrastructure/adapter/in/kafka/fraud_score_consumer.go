@@ -3,7 +3,9 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
 	"ms-decision-service/internal/domain/usecase"
 
 	"github.com/IBM/sarama"
@@ -13,16 +15,22 @@ import (
 // FraudScoreConsumer implements sarama.ConsumerGroupHandler for processing fraud score calculated messages.
 type FraudScoreConsumer struct {
 	evaluateUseCase *usecase.EvaluateFraudScoreUseCase
+	processor       *messageProcessor
 	logger          zerolog.Logger
 }
 
 // NewFraudScoreConsumer creates a new consumer with the given use case and logger.
+// Messages that fail with a retryable error are retried according to retryPolicy;
+// messages that still fail are published to deadLetters, which may be nil.
 func NewFraudScoreConsumer(
 	evaluateUseCase *usecase.EvaluateFraudScoreUseCase,
+	deadLetters repository.DeadLetterPublisher,
+	retryPolicy RetryPolicy,
 	logger zerolog.Logger,
 ) *FraudScoreConsumer {
 	return &FraudScoreConsumer{
 		evaluateUseCase: evaluateUseCase,
+		processor:       newMessageProcessor(retryPolicy, deadLetters, logger),
 		logger:          logger,
 	}
 }
@@ -65,39 +73,51 @@ func (c *FraudScoreConsumer) ConsumeClaim(
 			Str("key", string(msg.Key)).
 			Msg("message received")
 
-		var fraudScore entity.FraudScoreCalculatedMessage
-		if err := json.Unmarshal(msg.Value, &fraudScore); err != nil {
-			c.logger.Error().
-				Err(err).
+		if !c.processor.process(session.Context(), msg, c.handle) {
+			c.logger.Info().
 				Str("topic", msg.Topic).
+				Int32("partition", msg.Partition).
 				Int64("offset", msg.Offset).
-				Str("raw", string(msg.Value)).
-				Msg("failed to deserialize message")
-			session.MarkMessage(msg, "")
-			continue
+				Msg("session ended before message was processed, leaving it uncommitted")
+			return nil
 		}
 
-		c.logger.Info().
-			Str("transaction_id", fraudScore.TransactionID).
-			Int("fraud_score", fraudScore.FraudScore).
-			Msg("evaluating fraud score")
+		session.MarkMessage(msg, "")
+	}
+	return nil
+}
 
-		result, err := c.evaluateUseCase.Execute(context.Background(), &fraudScore)
-		if err != nil {
-			c.logger.Error().
-				Err(err).
-				Str("transaction_id", fraudScore.TransactionID).
-				Msg("failed to evaluate fraud score")
-			session.MarkMessage(msg, "")
-			continue
-		}
+// handle deserializes and evaluates a single message.
+func (c *FraudScoreConsumer) handle(msg *sarama.ConsumerMessage) error {
+	var fraudScore entity.FraudScoreCalculatedMessage
+	if err := json.Unmarshal(msg.Value, &fraudScore); err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("topic", msg.Topic).
+			Int64("offset", msg.Offset).
+			Str("raw", string(msg.Value)).
+			Msg("failed to deserialize message")
+		return fmt.Errorf("failed to deserialize message: %w", err)
+	}
 
-		c.logger.Info().
-			Str("transaction_id", result.TransactionID).
-			Str("decision", string(result.Status)).
-			Msg("fraud score evaluated")
+	c.logger.Info().
+		Str("transaction_id", fraudScore.TransactionID).
+		Int("fraud_score", fraudScore.FraudScore).
+		Msg("evaluating fraud score")
 
-		session.MarkMessage(msg, "")
+	result, err := c.evaluateUseCase.Execute(context.Background(), &fraudScore)
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("transaction_id", fraudScore.TransactionID).
+			Msg("failed to evaluate fraud score")
+		return err
 	}
+
+	c.logger.Info().
+		Str("transaction_id", result.TransactionID).
+		Str("decision", string(result.Status)).
+		Msg("fraud score evaluated")
+
 	return nil
 }
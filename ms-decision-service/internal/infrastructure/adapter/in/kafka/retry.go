@@ -0,0 +1,212 @@
+package kafka
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+	"ms-decision-service/internal/domain/usecase"
+	"ms-decision-service/internal/infrastructure/telemetry"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rs/zerolog"
+)
+
+// Dead-letter reasons, used as the reason label of telemetry.KafkaDeadLetters.
+const (
+	deadLetterReasonRetriesExhausted = "retries_exhausted"
+	deadLetterReasonNonRetryable     = "non_retryable"
+)
+
+// RetryPolicy controls how a consumer retries a message whose processing failed
+// with a retryable error (see usecase.IsRetryable). MaxAttempts includes the first
+// attempt. The backoff doubles from InitialBackoff up to MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured: three
+// attempts, waiting 200ms and then 400ms.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+	}
+}
+
+// Backoff returns the delay before the given retry, starting at 1 for the wait
+// after the first failed attempt.
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < retry && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return backoff
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// messageProcessor runs a consumer's handler with retries and dead-letters the
+// messages it cannot process.
+type messageProcessor struct {
+	retryPolicy RetryPolicy
+	deadLetters repository.DeadLetterPublisher
+	logger      zerolog.Logger
+	now         func() time.Time
+}
+
+func newMessageProcessor(
+	retryPolicy RetryPolicy,
+	deadLetters repository.DeadLetterPublisher,
+	logger zerolog.Logger,
+) *messageProcessor {
+	return &messageProcessor{
+		retryPolicy: retryPolicy,
+		deadLetters: deadLetters,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// process handles the message, retrying retryable errors according to the retry
+// policy. A message that still fails, or fails with a non-retryable error, is
+// published to the dead-letter topic; publishing is retried until it succeeds.
+// process returns false when ctx ended before the message was settled, in which
+// case it must not be marked so that it is redelivered. Without a dead-letter
+// publisher, failed messages are logged and dropped.
+func (p *messageProcessor) process(
+	ctx context.Context,
+	msg *sarama.ConsumerMessage,
+	handle func(msg *sarama.ConsumerMessage) error,
+) bool {
+	maxAttempts := p.retryPolicy.maxAttempts()
+
+	attempt := 1
+	err := handle(msg)
+	for err != nil && usecase.IsRetryable(err) && attempt < maxAttempts {
+		backoff := p.retryPolicy.Backoff(attempt)
+		p.logger.Warn().
+			Err(err).
+			Str("topic", msg.Topic).
+			Int32("partition", msg.Partition).
+			Int64("offset", msg.Offset).
+			Int("attempt", attempt).
+			Int("max_attempts", maxAttempts).
+			Dur("backoff", backoff).
+			Msg("retrying message")
+
+		if !sleep(ctx, backoff) {
+			return false
+		}
+		telemetry.KafkaConsumerRetries.WithLabelValues(msg.Topic).Inc()
+		attempt++
+		err = handle(msg)
+	}
+	if err == nil {
+		return true
+	}
+
+	reason := deadLetterReasonNonRetryable
+	if usecase.IsRetryable(err) {
+		reason = deadLetterReasonRetriesExhausted
+	}
+
+	if p.deadLetters == nil {
+		p.logger.Error().
+			Err(err).
+			Str("topic", msg.Topic).
+			Int32("partition", msg.Partition).
+			Int64("offset", msg.Offset).
+			Int("attempts", attempt).
+			Str("reason", reason).
+			Msg("message dropped, no dead-letter topic configured")
+		return true
+	}
+
+	return p.deadLetter(ctx, msg, err, attempt, reason)
+}
+
+// deadLetter publishes the message to the dead-letter topic, waiting the retry
+// policy's maximum backoff between failed publish attempts.
+func (p *messageProcessor) deadLetter(
+	ctx context.Context,
+	msg *sarama.ConsumerMessage,
+	cause error,
+	attempts int,
+	reason string,
+) bool {
+	deadLetter := &entity.DeadLetter{
+		SourceTopic:     msg.Topic,
+		SourcePartition: msg.Partition,
+		SourceOffset:    msg.Offset,
+		Key:             string(msg.Key),
+		Payload:         string(msg.Value),
+		Headers:         toHeaderMap(msg.Headers),
+		Error:           cause.Error(),
+		Attempts:        attempts,
+		FailedAt:        p.now().UTC(),
+	}
+
+	for {
+		err := p.deadLetters.Publish(ctx, deadLetter)
+		if err == nil {
+			telemetry.KafkaDeadLetters.WithLabelValues(msg.Topic, reason).Inc()
+			return true
+		}
+
+		telemetry.KafkaDeadLetterPublishFailures.WithLabelValues(msg.Topic).Inc()
+		p.logger.Error().
+			Err(err).
+			Str("topic", msg.Topic).
+			Int32("partition", msg.Partition).
+			Int64("offset", msg.Offset).
+			Msg("failed to publish message to dead-letter topic")
+
+		if !sleep(ctx, p.retryPolicy.MaxBackoff) {
+			return false
+		}
+	}
+}
+
+func toHeaderMap(headers []*sarama.RecordHeader) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(headers))
+	for _, header := range headers {
+		if header == nil {
+			continue
+		}
+		values[string(header.Key)] = string(header.Value)
+	}
+	return values
+}
+
+// sleep waits for d, returning false if ctx ends first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
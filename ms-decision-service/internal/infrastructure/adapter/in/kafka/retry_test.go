@@ -0,0 +1,170 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/usecase"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rs/zerolog"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	tests := []struct {
+		retry    int
+		expected time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{10, time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Backoff(tt.retry); got != tt.expected {
+			t.Errorf("Backoff(%d) = %v, expected %v", tt.retry, got, tt.expected)
+		}
+	}
+}
+
+func TestMessageProcessor_RetriesUntilSuccess(t *testing.T) {
+	deadLetters := &mockDeadLetterPublisher{}
+	processor := newMessageProcessor(testRetryPolicy(), deadLetters, zerolog.Nop())
+
+	calls := 0
+	ok := processor.process(context.Background(), &sarama.ConsumerMessage{Topic: "Transaction.Created"}, func(_ *sarama.ConsumerMessage) error {
+		calls++
+		if calls < 3 {
+			return usecase.ErrDecisionPublishFailed
+		}
+		return nil
+	})
+
+	if !ok {
+		t.Fatal("expected message to be settled")
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+	if len(deadLetters.published) != 0 {
+		t.Errorf("expected no dead letters, got %d", len(deadLetters.published))
+	}
+}
+
+func TestMessageProcessor_NonRetryableErrorIsNotRetried(t *testing.T) {
+	deadLetters := &mockDeadLetterPublisher{}
+	processor := newMessageProcessor(testRetryPolicy(), deadLetters, zerolog.Nop())
+
+	calls := 0
+	msg := &sarama.ConsumerMessage{
+		Topic:     "FraudSignals.Calculated",
+		Partition: 1,
+		Offset:    9,
+		Key:       []byte("tx-1"),
+		Value:     []byte(`{}`),
+		Headers:   []*sarama.RecordHeader{{Key: []byte("traceparent"), Value: []byte("00-abc-def-01")}},
+	}
+	ok := processor.process(context.Background(), msg, func(_ *sarama.ConsumerMessage) error {
+		calls++
+		return usecase.ErrFraudScoreMessageNil
+	})
+
+	if !ok {
+		t.Fatal("expected message to be settled")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls)
+	}
+	if len(deadLetters.published) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(deadLetters.published))
+	}
+
+	deadLetter := deadLetters.published[0]
+	if deadLetter.SourceTopic != "FraudSignals.Calculated" || deadLetter.SourcePartition != 1 || deadLetter.SourceOffset != 9 {
+		t.Errorf("unexpected source %s[%d]@%d", deadLetter.SourceTopic, deadLetter.SourcePartition, deadLetter.SourceOffset)
+	}
+	if deadLetter.Key != "tx-1" {
+		t.Errorf("expected key tx-1, got %s", deadLetter.Key)
+	}
+	if deadLetter.Headers["traceparent"] != "00-abc-def-01" {
+		t.Errorf("expected original headers to be kept, got %v", deadLetter.Headers)
+	}
+	if deadLetter.Error != usecase.ErrFraudScoreMessageNil.Error() {
+		t.Errorf("expected error %q, got %q", usecase.ErrFraudScoreMessageNil, deadLetter.Error)
+	}
+	if deadLetter.FailedAt.IsZero() {
+		t.Error("expected failed_at to be set")
+	}
+}
+
+func TestMessageProcessor_DeadLetterPublishRetriedUntilSuccess(t *testing.T) {
+	failures := 2
+	deadLetters := &mockDeadLetterPublisher{
+		publishFunc: func(_ context.Context, _ *entity.DeadLetter) error {
+			if failures > 0 {
+				failures--
+				return errors.New("broker unavailable")
+			}
+			return nil
+		},
+	}
+	processor := newMessageProcessor(testRetryPolicy(), deadLetters, zerolog.Nop())
+
+	ok := processor.process(context.Background(), &sarama.ConsumerMessage{}, func(_ *sarama.ConsumerMessage) error {
+		return usecase.ErrTransactionNil
+	})
+
+	if !ok {
+		t.Fatal("expected message to be settled")
+	}
+	if len(deadLetters.published) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(deadLetters.published))
+	}
+}
+
+func TestMessageProcessor_WithoutDeadLetterPublisherDropsMessage(t *testing.T) {
+	processor := newMessageProcessor(testRetryPolicy(), nil, zerolog.Nop())
+
+	ok := processor.process(context.Background(), &sarama.ConsumerMessage{}, func(_ *sarama.ConsumerMessage) error {
+		return usecase.ErrRuleRetrievalFailed
+	})
+
+	if !ok {
+		t.Fatal("expected message to be settled")
+	}
+}
+
+func TestConsumeClaim_SessionEndsBeforeDeadLetterPublished(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	deadLetters := &mockDeadLetterPublisher{
+		publishFunc: func(_ context.Context, _ *entity.DeadLetter) error {
+			cancel()
+			return errors.New("broker unavailable")
+		},
+	}
+	uc := buildUseCase(&mockRuleRepository{}, &mockDecisionPublisher{})
+	consumer := NewTransactionConsumer(uc, deadLetters, testRetryPolicy(), zerolog.Nop())
+
+	session := &mockConsumerGroupSession{ctx: ctx}
+	msgChan := make(chan *sarama.ConsumerMessage, 1)
+	claim := &mockConsumerGroupClaim{messages: msgChan}
+
+	msgChan <- &sarama.ConsumerMessage{Value: []byte("not valid json!!!")}
+	close(msgChan)
+
+	if err := consumer.ConsumeClaim(session, claim); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// The message must stay uncommitted so that it is redelivered
+	if len(session.markedMessages) != 0 {
+		t.Fatalf("expected no marked messages, got %d", len(session.markedMessages))
+	}
+}
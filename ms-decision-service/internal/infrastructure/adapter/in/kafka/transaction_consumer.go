@@ -3,26 +3,39 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
 	"ms-decision-service/internal/domain/usecase"
+	"strconv"
 
 	"github.com/IBM/sarama"
 	"github.com/rs/zerolog"
 )
 
+// HeaderRepublishAttempt marks a Transaction.Created event that the Transaction
+// Evaluator published again for a transaction without a decision.
+const HeaderRepublishAttempt = "republish-attempt"
+
 // TransactionConsumer implements sarama.ConsumerGroupHandler for processing transaction messages.
 type TransactionConsumer struct {
 	evaluateUseCase *usecase.EvaluateTransactionUseCase
+	processor       *messageProcessor
 	logger          zerolog.Logger
 }
 
 // NewTransactionConsumer creates a new consumer with the given use case and logger.
+// Messages that fail with a retryable error are retried according to retryPolicy;
+// messages that still fail are published to deadLetters, which may be nil.
 func NewTransactionConsumer(
 	evaluateUseCase *usecase.EvaluateTransactionUseCase,
+	deadLetters repository.DeadLetterPublisher,
+	retryPolicy RetryPolicy,
 	logger zerolog.Logger,
 ) *TransactionConsumer {
 	return &TransactionConsumer{
 		evaluateUseCase: evaluateUseCase,
+		processor:       newMessageProcessor(retryPolicy, deadLetters, logger),
 		logger:          logger,
 	}
 }
@@ -65,42 +78,70 @@ func (c *TransactionConsumer) ConsumeClaim(
 			Str("key", string(msg.Key)).
 			Msg("message received")
 
-		var transaction entity.TransactionMessage
-		if err := json.Unmarshal(msg.Value, &transaction); err != nil {
-			c.logger.Error().
-				Err(err).
+		if !c.processor.process(session.Context(), msg, c.handle) {
+			c.logger.Info().
 				Str("topic", msg.Topic).
+				Int32("partition", msg.Partition).
 				Int64("offset", msg.Offset).
-				Str("raw", string(msg.Value)).
-				Msg("failed to deserialize message")
-			session.MarkMessage(msg, "")
-			continue
+				Msg("session ended before message was processed, leaving it uncommitted")
+			return nil
 		}
 
-		c.logger.Info().
-			Str("transaction_id", transaction.ID).
-			Int64("amount_in_cents", transaction.AmountInCents).
-			Str("currency", transaction.Currency).
-			Str("payment_method", transaction.PaymentMethod).
-			Str("customer_id", transaction.CustomerID).
-			Msg("evaluating transaction")
-
-		result, err := c.evaluateUseCase.Execute(context.Background(), &transaction)
-		if err != nil {
-			c.logger.Error().
-				Err(err).
-				Str("transaction_id", transaction.ID).
-				Msg("failed to evaluate transaction")
-			session.MarkMessage(msg, "")
-			continue
-		}
+		session.MarkMessage(msg, "")
+	}
+	return nil
+}
 
-		c.logger.Info().
-			Str("transaction_id", result.TransactionID).
-			Str("decision", string(result.Status)).
-			Msg("transaction evaluated")
+// handle deserializes and evaluates a single message.
+func (c *TransactionConsumer) handle(msg *sarama.ConsumerMessage) error {
+	var transaction entity.TransactionMessage
+	if err := json.Unmarshal(msg.Value, &transaction); err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("topic", msg.Topic).
+			Int64("offset", msg.Offset).
+			Str("raw", string(msg.Value)).
+			Msg("failed to deserialize message")
+		return fmt.Errorf("failed to deserialize message: %w", err)
+	}
+	transaction.RepublishAttempt = republishAttempt(msg.Headers)
 
-		session.MarkMessage(msg, "")
+	c.logger.Info().
+		Str("transaction_id", transaction.ID).
+		Int64("amount_in_cents", transaction.AmountInCents).
+		Str("currency", transaction.Currency).
+		Str("payment_method", transaction.PaymentMethod).
+		Str("customer_id", transaction.CustomerID).
+		Msg("evaluating transaction")
+
+	result, err := c.evaluateUseCase.Execute(context.Background(), &transaction)
+	if err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("transaction_id", transaction.ID).
+			Msg("failed to evaluate transaction")
+		return err
 	}
+
+	c.logger.Info().
+		Str("transaction_id", result.TransactionID).
+		Str("decision", string(result.Status)).
+		Msg("transaction evaluated")
+
 	return nil
 }
+
+// republishAttempt returns the value of the republish-attempt header, or 0 when
+// the header is missing or invalid.
+func republishAttempt(headers []*sarama.RecordHeader) int {
+	for _, header := range headers {
+		if header != nil && string(header.Key) == HeaderRepublishAttempt {
+			attempt, err := strconv.Atoi(string(header.Value))
+			if err != nil || attempt < 0 {
+				return 0
+			}
+			return attempt
+		}
+	}
+	return 0
+}
@@ -6,6 +6,8 @@ import (
 	"ms-decision-service/internal/domain/entity"
 	"ms-decision-service/internal/domain/repository"
 	"ms-decision-service/internal/domain/usecase"
+	"ms-decision-service/internal/infrastructure/adapter/out/memory"
+	"strings"
 	"testing"
 	"time"
 
@@ -37,6 +39,14 @@ func (m *mockRuleRepository) FindAll(ctx context.Context) ([]entity.Rule, error)
 	return nil, nil
 }
 
+func (m *mockRuleRepository) FindByID(_ context.Context, _ string) (*entity.Rule, error) {
+	return nil, nil
+}
+
+func (m *mockRuleRepository) ApplyChanges(_ context.Context, _ *entity.RuleChangeSet) error {
+	return nil
+}
+
 // --- Mock DecisionPublisher ---
 
 type mockDecisionPublisher struct {
@@ -77,9 +87,45 @@ func (m *mockRuleEvaluationRepository) FindByTransactionID(_ context.Context, _
 	return nil, nil
 }
 
+func (m *mockRuleEvaluationRepository) FindShadowByRuleID(_ context.Context, _ string, _ time.Time) ([]entity.RuleEvaluationResult, error) {
+	return nil, nil
+}
+
+type mockRuleHistoryRepository struct{}
+
+func (m *mockRuleHistoryRepository) FindByRuleID(_ context.Context, _ string) ([]entity.RuleVersion, error) {
+	return nil, nil
+}
+
+func (m *mockRuleHistoryRepository) FindAll(_ context.Context) ([]entity.RuleVersion, error) {
+	return nil, nil
+}
+
+func (m *mockRuleHistoryRepository) CurrentRulesetVersion(_ context.Context) (int, error) {
+	return 0, nil
+}
+
+// --- Mock DeadLetterPublisher ---
+
+type mockDeadLetterPublisher struct {
+	publishFunc func(ctx context.Context, deadLetter *entity.DeadLetter) error
+	published   []*entity.DeadLetter
+}
+
+func (m *mockDeadLetterPublisher) Publish(ctx context.Context, deadLetter *entity.DeadLetter) error {
+	if m.publishFunc != nil {
+		if err := m.publishFunc(ctx, deadLetter); err != nil {
+			return err
+		}
+	}
+	m.published = append(m.published, deadLetter)
+	return nil
+}
+
 // --- Mock ConsumerGroupSession ---
 
 type mockConsumerGroupSession struct {
+	ctx            context.Context
 	markedMessages []*sarama.ConsumerMessage
 }
 
@@ -89,7 +135,13 @@ func (m *mockConsumerGroupSession) GenerationID() int32                      { r
 func (m *mockConsumerGroupSession) MarkOffset(string, int32, int64, string)  {}
 func (m *mockConsumerGroupSession) Commit()                                  {}
 func (m *mockConsumerGroupSession) ResetOffset(string, int32, int64, string) {}
-func (m *mockConsumerGroupSession) Context() context.Context                 { return context.Background() }
+
+func (m *mockConsumerGroupSession) Context() context.Context {
+	if m.ctx != nil {
+		return m.ctx
+	}
+	return context.Background()
+}
 
 func (m *mockConsumerGroupSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
 	m.markedMessages = append(m.markedMessages, msg)
@@ -110,7 +162,12 @@ func (m *mockConsumerGroupClaim) Messages() <-chan *sarama.ConsumerMessage { ret
 // --- Helper ---
 
 func buildUseCase(ruleRepo repository.RuleRepository, publisher repository.DecisionPublisher) *usecase.EvaluateTransactionUseCase {
-	return usecase.NewEvaluateTransactionUseCase(ruleRepo, publisher, &mockFraudScoreRequestPublisher{}, &mockRuleEvaluationRepository{}, zerolog.Nop())
+	return usecase.NewEvaluateTransactionUseCase(ruleRepo, publisher, &mockFraudScoreRequestPublisher{}, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, nil, nil, nil, nil, nil, zerolog.Nop())
+}
+
+// testRetryPolicy retries without waiting.
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3}
 }
 
 func validTransactionJSON() []byte {
@@ -138,7 +195,8 @@ func TestConsumeClaim_ValidMessage(t *testing.T) {
 	uc := buildUseCase(ruleRepo, publisher)
 	logger := zerolog.Nop()
 
-	consumer := NewTransactionConsumer(uc, logger)
+	deadLetters := &mockDeadLetterPublisher{}
+	consumer := NewTransactionConsumer(uc, deadLetters, testRetryPolicy(), logger)
 
 	session := &mockConsumerGroupSession{}
 	msgChan := make(chan *sarama.ConsumerMessage, 1)
@@ -163,6 +221,83 @@ func TestConsumeClaim_ValidMessage(t *testing.T) {
 	if publisher.published[0].TransactionID != "tx-123" {
 		t.Errorf("expected transaction ID tx-123, got %s", publisher.published[0].TransactionID)
 	}
+
+	if len(deadLetters.published) != 0 {
+		t.Fatalf("expected no dead letters, got %d", len(deadLetters.published))
+	}
+}
+
+func TestConsumeClaim_RedeliveredMessagePublishesOnce(t *testing.T) {
+	publisher := &mockDecisionPublisher{}
+	processedStore := memory.NewInMemoryProcessedMessageStore(time.Hour)
+	uc := usecase.NewEvaluateTransactionUseCase(&mockRuleRepository{}, publisher, &mockFraudScoreRequestPublisher{}, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, nil, nil, nil, processedStore, nil, zerolog.Nop())
+	consumer := NewTransactionConsumer(uc, &mockDeadLetterPublisher{}, testRetryPolicy(), zerolog.Nop())
+
+	session := &mockConsumerGroupSession{}
+	msgChan := make(chan *sarama.ConsumerMessage, 2)
+	claim := &mockConsumerGroupClaim{messages: msgChan}
+
+	msgChan <- &sarama.ConsumerMessage{Offset: 1, Value: validTransactionJSON()}
+	msgChan <- &sarama.ConsumerMessage{Offset: 1, Value: validTransactionJSON()}
+	close(msgChan)
+
+	if err := consumer.ConsumeClaim(session, claim); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(session.markedMessages) != 2 {
+		t.Fatalf("expected 2 marked messages, got %d", len(session.markedMessages))
+	}
+	if len(publisher.published) != 1 {
+		t.Fatalf("expected 1 published decision, got %d", len(publisher.published))
+	}
+}
+
+func TestConsumeClaim_RepublishedMessageIsEvaluatedAgain(t *testing.T) {
+	publisher := &mockDecisionPublisher{}
+	processedStore := memory.NewInMemoryProcessedMessageStore(time.Hour)
+	uc := usecase.NewEvaluateTransactionUseCase(&mockRuleRepository{}, publisher, &mockFraudScoreRequestPublisher{}, &mockRuleEvaluationRepository{}, &mockRuleHistoryRepository{}, nil, nil, nil, processedStore, nil, zerolog.Nop())
+	consumer := NewTransactionConsumer(uc, &mockDeadLetterPublisher{}, testRetryPolicy(), zerolog.Nop())
+
+	session := &mockConsumerGroupSession{}
+	msgChan := make(chan *sarama.ConsumerMessage, 2)
+	claim := &mockConsumerGroupClaim{messages: msgChan}
+
+	msgChan <- &sarama.ConsumerMessage{Offset: 1, Value: validTransactionJSON()}
+	msgChan <- &sarama.ConsumerMessage{
+		Offset:  2,
+		Value:   validTransactionJSON(),
+		Headers: []*sarama.RecordHeader{{Key: []byte(HeaderRepublishAttempt), Value: []byte("1")}},
+	}
+	close(msgChan)
+
+	if err := consumer.ConsumeClaim(session, claim); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(publisher.published) != 2 {
+		t.Fatalf("expected the republished transaction to be published again, got %d decisions", len(publisher.published))
+	}
+}
+
+func TestRepublishAttempt(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []*sarama.RecordHeader
+		want    int
+	}{
+		{"missing", nil, 0},
+		{"present", []*sarama.RecordHeader{{Key: []byte("traceparent"), Value: []byte("x")}, {Key: []byte(HeaderRepublishAttempt), Value: []byte("3")}}, 3},
+		{"invalid", []*sarama.RecordHeader{{Key: []byte(HeaderRepublishAttempt), Value: []byte("three")}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := republishAttempt(tt.headers); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
 }
 
 func TestConsumeClaim_MalformedJSON(t *testing.T) {
@@ -171,13 +306,14 @@ func TestConsumeClaim_MalformedJSON(t *testing.T) {
 	uc := buildUseCase(ruleRepo, publisher)
 	logger := zerolog.Nop()
 
-	consumer := NewTransactionConsumer(uc, logger)
+	deadLetters := &mockDeadLetterPublisher{}
+	consumer := NewTransactionConsumer(uc, deadLetters, testRetryPolicy(), logger)
 
 	session := &mockConsumerGroupSession{}
 	msgChan := make(chan *sarama.ConsumerMessage, 1)
 	claim := &mockConsumerGroupClaim{messages: msgChan}
 
-	msgChan <- &sarama.ConsumerMessage{Value: []byte("not valid json!!!")}
+	msgChan <- &sarama.ConsumerMessage{Topic: "Transaction.Created", Offset: 7, Value: []byte("not valid json!!!")}
 	close(msgChan)
 
 	err := consumer.ConsumeClaim(session, claim)
@@ -185,11 +321,26 @@ func TestConsumeClaim_MalformedJSON(t *testing.T) {
 		t.Fatalf("expected no error (should not crash), got %v", err)
 	}
 
-	// Message should still be marked even though deserialization failed
+	// Message should still be marked once it has been dead-lettered
 	if len(session.markedMessages) != 1 {
 		t.Fatalf("expected 1 marked message (malformed skipped), got %d", len(session.markedMessages))
 	}
 
+	// Deserialization errors are not retried
+	if len(deadLetters.published) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(deadLetters.published))
+	}
+	deadLetter := deadLetters.published[0]
+	if deadLetter.Attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", deadLetter.Attempts)
+	}
+	if deadLetter.SourceTopic != "Transaction.Created" || deadLetter.SourceOffset != 7 {
+		t.Errorf("expected source Transaction.Created@7, got %s@%d", deadLetter.SourceTopic, deadLetter.SourceOffset)
+	}
+	if deadLetter.Payload != "not valid json!!!" {
+		t.Errorf("expected original payload, got %s", deadLetter.Payload)
+	}
+
 	// No decision should have been published
 	if len(publisher.published) != 0 {
 		t.Fatalf("expected 0 published decisions for malformed message, got %d", len(publisher.published))
@@ -206,7 +357,8 @@ func TestConsumeClaim_UseCaseError(t *testing.T) {
 	uc := buildUseCase(ruleRepo, publisher)
 	logger := zerolog.Nop()
 
-	consumer := NewTransactionConsumer(uc, logger)
+	deadLetters := &mockDeadLetterPublisher{}
+	consumer := NewTransactionConsumer(uc, deadLetters, testRetryPolicy(), logger)
 
 	session := &mockConsumerGroupSession{}
 	msgChan := make(chan *sarama.ConsumerMessage, 1)
@@ -220,11 +372,22 @@ func TestConsumeClaim_UseCaseError(t *testing.T) {
 		t.Fatalf("expected no error (use case error should be logged, not returned), got %v", err)
 	}
 
-	// Message should still be marked even when use case fails
+	// Message should still be marked once it has been dead-lettered
 	if len(session.markedMessages) != 1 {
 		t.Fatalf("expected 1 marked message despite use case error, got %d", len(session.markedMessages))
 	}
 
+	// Rule retrieval failures are retried before the message is dead-lettered
+	if len(deadLetters.published) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(deadLetters.published))
+	}
+	if deadLetters.published[0].Attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", deadLetters.published[0].Attempts)
+	}
+	if !strings.Contains(deadLetters.published[0].Error, usecase.ErrRuleRetrievalFailed.Error()) {
+		t.Errorf("expected rule retrieval error, got %q", deadLetters.published[0].Error)
+	}
+
 	// No decision should have been published since rule retrieval failed
 	if len(publisher.published) != 0 {
 		t.Fatalf("expected 0 published decisions when use case errors, got %d", len(publisher.published))
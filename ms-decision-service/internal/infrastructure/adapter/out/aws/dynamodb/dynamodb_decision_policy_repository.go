@@ -0,0 +1,142 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ms-decision-service/internal/domain/entity"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/rs/zerolog"
+)
+
+type decisionPolicyItem struct {
+	RuleSet             string `dynamodbav:"rule_set"`
+	Mode                string `dynamodbav:"mode"`
+	FraudCheckThreshold *int   `dynamodbav:"fraud_check_threshold,omitempty"`
+	DeclineThreshold    int    `dynamodbav:"decline_threshold"`
+	UpdatedBy           string `dynamodbav:"updated_by"`
+	UpdatedAt           string `dynamodbav:"updated_at"`
+}
+
+// DynamoDBDecisionPolicyRepository implements repository.DecisionPolicyRepository using
+// AWS DynamoDB. The table is keyed by rule_set (hash) and holds one item per rule set.
+type DynamoDBDecisionPolicyRepository struct {
+	client    *dynamodb.Client
+	tableName string
+	logger    zerolog.Logger
+}
+
+// NewDynamoDBDecisionPolicyRepository creates a new DynamoDB-backed decision policy repository.
+func NewDynamoDBDecisionPolicyRepository(
+	client *dynamodb.Client,
+	tableName string,
+	logger zerolog.Logger,
+) *DynamoDBDecisionPolicyRepository {
+	return &DynamoDBDecisionPolicyRepository{client: client, tableName: tableName, logger: logger}
+}
+
+// FindAll scans every stored policy.
+func (r *DynamoDBDecisionPolicyRepository) FindAll(ctx context.Context) ([]entity.DecisionPolicy, error) {
+	input := &dynamodb.ScanInput{TableName: aws.String(r.tableName)}
+
+	var policies []entity.DecisionPolicy
+	for {
+		output, err := r.client.Scan(ctx, input)
+		if err != nil {
+			r.logger.Error().Err(err).Str("table", r.tableName).Msg("failed to scan decision policies")
+			return nil, fmt.Errorf("failed to scan decision policies: %w", err)
+		}
+
+		var items []decisionPolicyItem
+		if err := attributevalue.UnmarshalListOfMaps(output.Items, &items); err != nil {
+			r.logger.Error().Err(err).Str("table", r.tableName).Msg("failed to unmarshal decision policies")
+			return nil, fmt.Errorf("failed to unmarshal decision policies: %w", err)
+		}
+		for _, item := range items {
+			policies = append(policies, toDecisionPolicy(item))
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return policies, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// FindByRuleSet returns the policy of the rule set, or nil when none is stored.
+func (r *DynamoDBDecisionPolicyRepository) FindByRuleSet(ctx context.Context, ruleSet entity.RuleSet) (*entity.DecisionPolicy, error) {
+	output, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"rule_set": &types.AttributeValueMemberS{Value: string(ruleSet)},
+		},
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Str("rule_set", string(ruleSet)).Msg("failed to get decision policy")
+		return nil, fmt.Errorf("failed to get decision policy: %w", err)
+	}
+
+	if output.Item == nil {
+		return nil, nil
+	}
+
+	var item decisionPolicyItem
+	if err := attributevalue.UnmarshalMap(output.Item, &item); err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Str("rule_set", string(ruleSet)).Msg("failed to unmarshal decision policy")
+		return nil, fmt.Errorf("failed to unmarshal decision policy: %w", err)
+	}
+
+	policy := toDecisionPolicy(item)
+	return &policy, nil
+}
+
+// Save stores the policy, replacing the previous policy of the rule set.
+func (r *DynamoDBDecisionPolicyRepository) Save(ctx context.Context, policy *entity.DecisionPolicy) error {
+	av, err := attributevalue.MarshalMap(toDecisionPolicyItem(*policy))
+	if err != nil {
+		r.logger.Error().Err(err).Str("rule_set", string(policy.RuleSet)).Msg("failed to marshal decision policy")
+		return fmt.Errorf("failed to marshal decision policy: %w", err)
+	}
+
+	if _, err := r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      av,
+	}); err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Str("rule_set", string(policy.RuleSet)).Msg("failed to save decision policy")
+		return fmt.Errorf("failed to save decision policy: %w", err)
+	}
+
+	r.logger.Info().Str("table", r.tableName).Str("rule_set", string(policy.RuleSet)).
+		Str("mode", string(policy.Mode)).Msg("decision policy saved")
+
+	return nil
+}
+
+func toDecisionPolicyItem(p entity.DecisionPolicy) decisionPolicyItem {
+	return decisionPolicyItem{
+		RuleSet:             string(p.RuleSet),
+		Mode:                string(p.Mode),
+		FraudCheckThreshold: p.FraudCheckThreshold,
+		DeclineThreshold:    p.DeclineThreshold,
+		UpdatedBy:           p.UpdatedBy,
+		UpdatedAt:           p.UpdatedAt.Format(time.RFC3339Nano),
+	}
+}
+
+func toDecisionPolicy(item decisionPolicyItem) entity.DecisionPolicy {
+	updatedAt, _ := time.Parse(time.RFC3339Nano, item.UpdatedAt)
+
+	return entity.DecisionPolicy{
+		RuleSet:             entity.RuleSet(item.RuleSet),
+		Mode:                entity.DecisionMode(item.Mode),
+		FraudCheckThreshold: item.FraudCheckThreshold,
+		DeclineThreshold:    item.DeclineThreshold,
+		UpdatedBy:           item.UpdatedBy,
+		UpdatedAt:           updatedAt,
+	}
+}
@@ -0,0 +1,58 @@
+package dynamodb
+
+import (
+	"ms-decision-service/internal/domain/entity"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+)
+
+func TestDecisionPolicyItem_RoundTrip(t *testing.T) {
+	fraudCheck := 40
+
+	tests := []struct {
+		name   string
+		policy entity.DecisionPolicy
+	}{
+		{
+			name: "score policy with both thresholds",
+			policy: entity.DecisionPolicy{
+				RuleSet:             entity.RuleSetTransaction,
+				Mode:                entity.ModeScore,
+				FraudCheckThreshold: &fraudCheck,
+				DeclineThreshold:    80,
+				UpdatedBy:           "analyst@example.com",
+				UpdatedAt:           time.Date(2025, 1, 15, 10, 30, 0, 123, time.UTC),
+			},
+		},
+		{
+			name: "first match policy",
+			policy: entity.DecisionPolicy{
+				RuleSet:   entity.RuleSetFraudScore,
+				Mode:      entity.ModeFirstMatch,
+				UpdatedBy: "analyst@example.com",
+				UpdatedAt: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			av, err := attributevalue.MarshalMap(toDecisionPolicyItem(tc.policy))
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			var item decisionPolicyItem
+			if err := attributevalue.UnmarshalMap(av, &item); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if got := toDecisionPolicy(item); !reflect.DeepEqual(got, tc.policy) {
+				t.Errorf("round-trip mismatch:\n got  %+v\n want %+v", got, tc.policy)
+			}
+		})
+	}
+}
@@ -0,0 +1,425 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ms-decision-service/internal/domain/entity"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/rs/zerolog"
+)
+
+// Sort keys of the lists table. Each list is stored as one metadata item and one
+// item per entry, all under the list name partition key.
+const (
+	listMetadataSortKey = "LIST"
+	listEntrySortPrefix = "ENTRY#"
+)
+
+// maxBatchWriteAttempts bounds how often unprocessed batch write items are resubmitted.
+const maxBatchWriteAttempts = 3
+
+type listItem struct {
+	ListName    string `dynamodbav:"list_name"`
+	SortKey     string `dynamodbav:"sk"`
+	ListType    string `dynamodbav:"list_type"`
+	Field       string `dynamodbav:"field"`
+	Description string `dynamodbav:"description,omitempty"`
+	CreatedBy   string `dynamodbav:"created_by"`
+	CreatedAt   string `dynamodbav:"created_at"`
+}
+
+type listEntryItem struct {
+	ListName  string `dynamodbav:"list_name"`
+	SortKey   string `dynamodbav:"sk"`
+	Value     string `dynamodbav:"value"`
+	Reason    string `dynamodbav:"reason,omitempty"`
+	ExpiresAt string `dynamodbav:"expires_at,omitempty"`
+	TTL       int64  `dynamodbav:"ttl,omitempty"`
+	AddedBy   string `dynamodbav:"added_by"`
+	AddedAt   string `dynamodbav:"added_at"`
+}
+
+// DynamoDBListRepository implements repository.ListRepository using AWS DynamoDB.
+// The table is keyed by list_name (hash) and sk (range). Expiring entries carry a
+// ttl attribute so DynamoDB removes them once they have expired.
+type DynamoDBListRepository struct {
+	client    *dynamodb.Client
+	tableName string
+	logger    zerolog.Logger
+}
+
+// NewDynamoDBListRepository creates a new DynamoDB-backed list repository.
+func NewDynamoDBListRepository(
+	client *dynamodb.Client,
+	tableName string,
+	logger zerolog.Logger,
+) *DynamoDBListRepository {
+	return &DynamoDBListRepository{client: client, tableName: tableName, logger: logger}
+}
+
+// FindAll scans the list metadata items and returns the lists sorted by name.
+func (r *DynamoDBListRepository) FindAll(ctx context.Context) ([]entity.ManagedList, error) {
+	avs, err := r.scan(ctx, &dynamodb.ScanInput{
+		TableName:        aws.String(r.tableName),
+		FilterExpression: aws.String("sk = :sk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":sk": &types.AttributeValueMemberS{Value: listMetadataSortKey},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lists, err := r.toLists(avs)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(lists, func(i, j int) bool {
+		return lists[i].Name < lists[j].Name
+	})
+
+	return lists, nil
+}
+
+// FindByName returns the list with the given name, or nil when it does not exist.
+func (r *DynamoDBListRepository) FindByName(ctx context.Context, name string) (*entity.ManagedList, error) {
+	output, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       listKey(name, listMetadataSortKey),
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Str("list_name", name).Msg("failed to get list")
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+
+	if output.Item == nil {
+		return nil, nil
+	}
+
+	lists, err := r.toLists([]map[string]types.AttributeValue{output.Item})
+	if err != nil {
+		return nil, err
+	}
+
+	return &lists[0], nil
+}
+
+// FindEntries queries every entry of the list, following pagination, sorted by value.
+func (r *DynamoDBListRepository) FindEntries(ctx context.Context, name string) ([]entity.ListEntry, error) {
+	avs, err := r.queryList(ctx, name, listEntrySortPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := r.toListEntries(avs)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Value < entries[j].Value
+	})
+
+	return entries, nil
+}
+
+// Create stores the list metadata, failing if a list with the same name exists.
+func (r *DynamoDBListRepository) Create(ctx context.Context, list *entity.ManagedList) error {
+	av, err := attributevalue.MarshalMap(toListItem(*list))
+	if err != nil {
+		r.logger.Error().Err(err).Str("list_name", list.Name).Msg("failed to marshal list")
+		return fmt.Errorf("failed to marshal list: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(list_name)"),
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Str("list_name", list.Name).Msg("failed to create list")
+		return fmt.Errorf("failed to create list: %w", err)
+	}
+
+	r.logger.Info().Str("table", r.tableName).Str("list_name", list.Name).Msg("list created")
+
+	return nil
+}
+
+// Delete removes the list metadata and every entry of the list.
+func (r *DynamoDBListRepository) Delete(ctx context.Context, name string) error {
+	avs, err := r.queryList(ctx, name, "")
+	if err != nil {
+		return err
+	}
+
+	requests := make([]types.WriteRequest, 0, len(avs))
+	for _, av := range avs {
+		requests = append(requests, types.WriteRequest{
+			DeleteRequest: &types.DeleteRequest{Key: map[string]types.AttributeValue{
+				"list_name": av["list_name"],
+				"sk":        av["sk"],
+			}},
+		})
+	}
+
+	if err := r.batchWrite(ctx, requests); err != nil {
+		return err
+	}
+
+	r.logger.Info().Str("table", r.tableName).Str("list_name", name).Int("item_count", len(avs)).Msg("list deleted")
+
+	return nil
+}
+
+// PutEntries stores the entries, replacing entries with the same list and value.
+func (r *DynamoDBListRepository) PutEntries(ctx context.Context, entries []entity.ListEntry) error {
+	requests := make([]types.WriteRequest, 0, len(entries))
+	for _, entry := range entries {
+		av, err := attributevalue.MarshalMap(toListEntryItem(entry))
+		if err != nil {
+			r.logger.Error().Err(err).Str("list_name", entry.ListName).Msg("failed to marshal list entry")
+			return fmt.Errorf("failed to marshal list entry: %w", err)
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: av}})
+	}
+
+	if err := r.batchWrite(ctx, requests); err != nil {
+		return err
+	}
+
+	r.logger.Info().Str("table", r.tableName).Int("count", len(entries)).Msg("list entries saved")
+
+	return nil
+}
+
+// DeleteEntry removes a single entry of the list.
+func (r *DynamoDBListRepository) DeleteEntry(ctx context.Context, name, value string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       listKey(name, listEntrySortPrefix+value),
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Str("list_name", name).Msg("failed to delete list entry")
+		return fmt.Errorf("failed to delete list entry: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSnapshot scans the whole table and indexes every list and entry.
+func (r *DynamoDBListRepository) LoadSnapshot(ctx context.Context) (*entity.ListSnapshot, error) {
+	avs, err := r.scan(ctx, &dynamodb.ScanInput{TableName: aws.String(r.tableName)})
+	if err != nil {
+		return nil, err
+	}
+
+	var listAVs, entryAVs []map[string]types.AttributeValue
+	for _, av := range avs {
+		if sk, ok := av["sk"].(*types.AttributeValueMemberS); ok && sk.Value == listMetadataSortKey {
+			listAVs = append(listAVs, av)
+		} else {
+			entryAVs = append(entryAVs, av)
+		}
+	}
+
+	lists, err := r.toLists(listAVs)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := r.toListEntries(entryAVs)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.NewListSnapshot(lists, entries), nil
+}
+
+func (r *DynamoDBListRepository) scan(ctx context.Context, input *dynamodb.ScanInput) ([]map[string]types.AttributeValue, error) {
+	var avs []map[string]types.AttributeValue
+	for {
+		output, err := r.client.Scan(ctx, input)
+		if err != nil {
+			r.logger.Error().Err(err).Str("table", r.tableName).Msg("failed to scan lists")
+			return nil, fmt.Errorf("failed to scan lists: %w", err)
+		}
+		avs = append(avs, output.Items...)
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return avs, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// queryList returns every item of the list whose sort key starts with prefix.
+func (r *DynamoDBListRepository) queryList(ctx context.Context, name, prefix string) ([]map[string]types.AttributeValue, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("list_name = :name AND begins_with(sk, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":name":   &types.AttributeValueMemberS{Value: name},
+			":prefix": &types.AttributeValueMemberS{Value: prefix},
+		},
+	}
+	if prefix == "" {
+		input.KeyConditionExpression = aws.String("list_name = :name")
+		delete(input.ExpressionAttributeValues, ":prefix")
+	}
+
+	var avs []map[string]types.AttributeValue
+	for {
+		output, err := r.client.Query(ctx, input)
+		if err != nil {
+			r.logger.Error().Err(err).Str("table", r.tableName).Str("list_name", name).Msg("failed to query list")
+			return nil, fmt.Errorf("failed to query list: %w", err)
+		}
+		avs = append(avs, output.Items...)
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return avs, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// batchWrite sends the requests in chunks of maxBatchWriteItems, resubmitting
+// unprocessed items up to maxBatchWriteAttempts times per chunk.
+func (r *DynamoDBListRepository) batchWrite(ctx context.Context, requests []types.WriteRequest) error {
+	for i := 0; i < len(requests); i += maxBatchWriteItems {
+		end := i + maxBatchWriteItems
+		if end > len(requests) {
+			end = len(requests)
+		}
+
+		pending := map[string][]types.WriteRequest{r.tableName: requests[i:end]}
+		for attempt := 1; len(pending[r.tableName]) > 0; attempt++ {
+			if attempt > maxBatchWriteAttempts {
+				r.logger.Warn().Str("table", r.tableName).
+					Int("unprocessed_count", len(pending[r.tableName])).
+					Msg("some list items were not processed")
+				return fmt.Errorf("unprocessed items remain after batch write")
+			}
+
+			output, err := r.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: pending})
+			if err != nil {
+				r.logger.Error().Err(err).Str("table", r.tableName).
+					Int("batch_size", len(pending[r.tableName])).Msg("failed to batch write list items")
+				return fmt.Errorf("failed to batch write list items: %w", err)
+			}
+			pending = output.UnprocessedItems
+		}
+	}
+
+	return nil
+}
+
+func (r *DynamoDBListRepository) toLists(avs []map[string]types.AttributeValue) ([]entity.ManagedList, error) {
+	var items []listItem
+	if err := attributevalue.UnmarshalListOfMaps(avs, &items); err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Int("item_count", len(avs)).Msg("failed to unmarshal lists")
+		return nil, fmt.Errorf("failed to unmarshal lists: %w", err)
+	}
+
+	lists := make([]entity.ManagedList, len(items))
+	for i, item := range items {
+		lists[i] = toManagedList(item)
+	}
+
+	return lists, nil
+}
+
+func (r *DynamoDBListRepository) toListEntries(avs []map[string]types.AttributeValue) ([]entity.ListEntry, error) {
+	var items []listEntryItem
+	if err := attributevalue.UnmarshalListOfMaps(avs, &items); err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Int("item_count", len(avs)).Msg("failed to unmarshal list entries")
+		return nil, fmt.Errorf("failed to unmarshal list entries: %w", err)
+	}
+
+	entries := make([]entity.ListEntry, 0, len(items))
+	for _, item := range items {
+		if !strings.HasPrefix(item.SortKey, listEntrySortPrefix) {
+			continue
+		}
+		entries = append(entries, toListEntry(item))
+	}
+
+	return entries, nil
+}
+
+func listKey(name, sortKey string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"list_name": &types.AttributeValueMemberS{Value: name},
+		"sk":        &types.AttributeValueMemberS{Value: sortKey},
+	}
+}
+
+func toListItem(l entity.ManagedList) listItem {
+	return listItem{
+		ListName:    l.Name,
+		SortKey:     listMetadataSortKey,
+		ListType:    string(l.Type),
+		Field:       string(l.Field),
+		Description: l.Description,
+		CreatedBy:   l.CreatedBy,
+		CreatedAt:   l.CreatedAt.Format(time.RFC3339Nano),
+	}
+}
+
+func toManagedList(item listItem) entity.ManagedList {
+	createdAt, _ := time.Parse(time.RFC3339Nano, item.CreatedAt)
+
+	return entity.ManagedList{
+		Name:        item.ListName,
+		Type:        entity.ListType(item.ListType),
+		Field:       entity.ConditionField(item.Field),
+		Description: item.Description,
+		CreatedBy:   item.CreatedBy,
+		CreatedAt:   createdAt,
+	}
+}
+
+func toListEntryItem(e entity.ListEntry) listEntryItem {
+	item := listEntryItem{
+		ListName: e.ListName,
+		SortKey:  listEntrySortPrefix + e.Value,
+		Value:    e.Value,
+		Reason:   e.Reason,
+		AddedBy:  e.AddedBy,
+		AddedAt:  e.AddedAt.Format(time.RFC3339Nano),
+	}
+
+	if e.ExpiresAt != nil {
+		item.ExpiresAt = e.ExpiresAt.Format(time.RFC3339Nano)
+		item.TTL = e.ExpiresAt.Unix()
+	}
+
+	return item
+}
+
+func toListEntry(item listEntryItem) entity.ListEntry {
+	addedAt, _ := time.Parse(time.RFC3339Nano, item.AddedAt)
+
+	entry := entity.ListEntry{
+		ListName: item.ListName,
+		Value:    item.Value,
+		Reason:   item.Reason,
+		AddedBy:  item.AddedBy,
+		AddedAt:  addedAt,
+	}
+
+	if expiresAt, err := time.Parse(time.RFC3339Nano, item.ExpiresAt); err == nil {
+		entry.ExpiresAt = &expiresAt
+	}
+
+	return entry
+}
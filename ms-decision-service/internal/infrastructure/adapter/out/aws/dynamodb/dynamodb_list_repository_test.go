@@ -0,0 +1,97 @@
+package dynamodb
+
+import (
+	"ms-decision-service/internal/domain/entity"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+)
+
+func TestListItem_RoundTrip(t *testing.T) {
+	original := entity.ManagedList{
+		Name:        "blocked-emails",
+		Type:        entity.ListBlocklist,
+		Field:       entity.FieldCustomerEmail,
+		Description: "Emails confirmed in chargebacks",
+		CreatedBy:   "analyst@example.com",
+		CreatedAt:   time.Date(2025, 1, 15, 10, 30, 0, 123, time.UTC),
+	}
+
+	av, err := attributevalue.MarshalMap(toListItem(original))
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	var item listItem
+	if err := attributevalue.UnmarshalMap(av, &item); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if item.SortKey != listMetadataSortKey {
+		t.Errorf("expected sort key %q, got %q", listMetadataSortKey, item.SortKey)
+	}
+	if got := toManagedList(item); !reflect.DeepEqual(got, original) {
+		t.Errorf("round-trip mismatch:\n got  %+v\n want %+v", got, original)
+	}
+}
+
+func TestListEntryItem_RoundTrip(t *testing.T) {
+	expiresAt := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		entry   entity.ListEntry
+		wantTTL int64
+	}{
+		{
+			name: "permanent entry has no ttl",
+			entry: entity.ListEntry{
+				ListName: "blocked-emails",
+				Value:    "fraud@example.com",
+				Reason:   "chargeback",
+				AddedBy:  "analyst@example.com",
+				AddedAt:  time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "expiring entry carries ttl",
+			entry: entity.ListEntry{
+				ListName:  "blocked-emails",
+				Value:     "mule@example.com",
+				ExpiresAt: &expiresAt,
+				AddedBy:   "analyst@example.com",
+				AddedAt:   time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+			},
+			wantTTL: expiresAt.Unix(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			av, err := attributevalue.MarshalMap(toListEntryItem(tc.entry))
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+			if _, ok := av["ttl"]; ok != (tc.wantTTL != 0) {
+				t.Errorf("expected ttl attribute present=%v", tc.wantTTL != 0)
+			}
+
+			var item listEntryItem
+			if err := attributevalue.UnmarshalMap(av, &item); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if item.TTL != tc.wantTTL {
+				t.Errorf("expected ttl %d, got %d", tc.wantTTL, item.TTL)
+			}
+			if item.SortKey != listEntrySortPrefix+tc.entry.Value {
+				t.Errorf("unexpected sort key %q", item.SortKey)
+			}
+			if got := toListEntry(item); !reflect.DeepEqual(got, tc.entry) {
+				t.Errorf("round-trip mismatch:\n got  %+v\n want %+v", got, tc.entry)
+			}
+		})
+	}
+}
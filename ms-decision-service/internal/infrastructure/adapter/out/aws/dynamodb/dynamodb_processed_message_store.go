@@ -0,0 +1,140 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ms-decision-service/internal/domain/entity"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/rs/zerolog"
+)
+
+type processedMessageItem struct {
+	TransactionID  string `dynamodbav:"transaction_id"`
+	Stage          string `dynamodbav:"stage"`
+	Status         string `dynamodbav:"status"`
+	RulesetVersion int    `dynamodbav:"ruleset_version"`
+	Score          *int   `dynamodbav:"score,omitempty"`
+	DecisionPath   string `dynamodbav:"decision_path,omitempty"`
+	ProcessedAt    string `dynamodbav:"processed_at"`
+	TTL            int64  `dynamodbav:"ttl"`
+}
+
+// DynamoDBProcessedMessageStore implements repository.ProcessedMessageStore using
+// AWS DynamoDB. The table is keyed by transaction_id (hash) and stage (range), and
+// items carry a ttl attribute so DynamoDB removes them once they have expired.
+// Because DynamoDB deletes expired items lazily, Find also ignores them.
+type DynamoDBProcessedMessageStore struct {
+	client    *dynamodb.Client
+	tableName string
+	ttl       time.Duration
+	logger    zerolog.Logger
+}
+
+// NewDynamoDBProcessedMessageStore creates a new DynamoDB-backed processed message
+// store keeping results for the given TTL.
+func NewDynamoDBProcessedMessageStore(
+	client *dynamodb.Client,
+	tableName string,
+	ttl time.Duration,
+	logger zerolog.Logger,
+) *DynamoDBProcessedMessageStore {
+	return &DynamoDBProcessedMessageStore{client: client, tableName: tableName, ttl: ttl, logger: logger}
+}
+
+// Find reads the result recorded for the transaction at the stage with a strongly
+// consistent read.
+func (s *DynamoDBProcessedMessageStore) Find(
+	ctx context.Context,
+	transactionID string,
+	stage entity.ProcessingStage,
+) (*entity.DecisionResult, error) {
+	output, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"transaction_id": &types.AttributeValueMemberS{Value: transactionID},
+			"stage":          &types.AttributeValueMemberS{Value: string(stage)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Str("table", s.tableName).Str("transaction_id", transactionID).
+			Str("stage", string(stage)).Msg("failed to get processed message")
+		return nil, fmt.Errorf("failed to get processed message: %w", err)
+	}
+
+	if output.Item == nil {
+		return nil, nil
+	}
+
+	var item processedMessageItem
+	if err := attributevalue.UnmarshalMap(output.Item, &item); err != nil {
+		s.logger.Error().Err(err).Str("table", s.tableName).Str("transaction_id", transactionID).
+			Str("stage", string(stage)).Msg("failed to unmarshal processed message")
+		return nil, fmt.Errorf("failed to unmarshal processed message: %w", err)
+	}
+
+	if item.TTL <= time.Now().Unix() {
+		return nil, nil
+	}
+
+	result := toProcessedResult(item)
+	return &result, nil
+}
+
+// Save stores the result, replacing any result recorded for the transaction at the stage.
+func (s *DynamoDBProcessedMessageStore) Save(
+	ctx context.Context,
+	stage entity.ProcessingStage,
+	result *entity.DecisionResult,
+) error {
+	av, err := attributevalue.MarshalMap(toProcessedMessageItem(stage, result, time.Now(), s.ttl))
+	if err != nil {
+		s.logger.Error().Err(err).Str("transaction_id", result.TransactionID).Msg("failed to marshal processed message")
+		return fmt.Errorf("failed to marshal processed message: %w", err)
+	}
+
+	if _, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      av,
+	}); err != nil {
+		s.logger.Error().Err(err).Str("table", s.tableName).Str("transaction_id", result.TransactionID).
+			Str("stage", string(stage)).Msg("failed to save processed message")
+		return fmt.Errorf("failed to save processed message: %w", err)
+	}
+
+	return nil
+}
+
+func toProcessedMessageItem(
+	stage entity.ProcessingStage,
+	result *entity.DecisionResult,
+	processedAt time.Time,
+	ttl time.Duration,
+) processedMessageItem {
+	return processedMessageItem{
+		TransactionID:  result.TransactionID,
+		Stage:          string(stage),
+		Status:         string(result.Status),
+		RulesetVersion: result.RulesetVersion,
+		Score:          result.Score,
+		DecisionPath:   string(result.DecisionPath),
+		ProcessedAt:    processedAt.UTC().Format(time.RFC3339Nano),
+		TTL:            processedAt.Add(ttl).Unix(),
+	}
+}
+
+func toProcessedResult(item processedMessageItem) entity.DecisionResult {
+	return entity.DecisionResult{
+		TransactionID:  item.TransactionID,
+		Status:         entity.DecisionStatus(item.Status),
+		RulesetVersion: item.RulesetVersion,
+		Score:          item.Score,
+		DecisionPath:   entity.DecisionPath(item.DecisionPath),
+	}
+}
@@ -0,0 +1,53 @@
+package dynamodb
+
+import (
+	"ms-decision-service/internal/domain/entity"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+)
+
+func TestProcessedMessageItem_RoundTrip(t *testing.T) {
+	score := 55
+	processedAt := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		result entity.DecisionResult
+	}{
+		{
+			name:   "first match decision",
+			result: entity.DecisionResult{TransactionID: "tx-1", Status: entity.DECLINED, RulesetVersion: 4, DecisionPath: entity.DecisionPathDirect},
+		},
+		{
+			name:   "score decision",
+			result: entity.DecisionResult{TransactionID: "tx-2", Status: entity.FRAUDCHECK, RulesetVersion: 4, Score: &score},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			av, err := attributevalue.MarshalMap(toProcessedMessageItem(entity.StageTransactionCreated, &tc.result, processedAt, time.Hour))
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			var item processedMessageItem
+			if err := attributevalue.UnmarshalMap(av, &item); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if item.Stage != string(entity.StageTransactionCreated) {
+				t.Errorf("expected stage %s, got %s", entity.StageTransactionCreated, item.Stage)
+			}
+			if item.TTL != processedAt.Add(time.Hour).Unix() {
+				t.Errorf("expected ttl %d, got %d", processedAt.Add(time.Hour).Unix(), item.TTL)
+			}
+			if got := toProcessedResult(item); !reflect.DeepEqual(got, tc.result) {
+				t.Errorf("round-trip mismatch:\n got  %+v\n want %+v", got, tc.result)
+			}
+		})
+	}
+}
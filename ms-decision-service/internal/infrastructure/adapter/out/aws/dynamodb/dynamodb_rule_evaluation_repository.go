@@ -17,18 +17,47 @@ import (
 
 const maxBatchWriteItems = 25
 
+// shadowEvaluationsIndex is a sparse GSI over the results of SHADOW rules: only those
+// rows carry shadow_rule_id, so the index holds nothing else.
+const shadowEvaluationsIndex = "shadow_rule_id-evaluated_at-index"
+
 type ruleEvaluationItem struct {
-	TransactionID     string `dynamodbav:"transaction_id"`
-	RuleID            string `dynamodbav:"rule_id"`
-	RuleName          string `dynamodbav:"rule_name"`
+	TransactionID     string                `dynamodbav:"transaction_id"`
+	RuleID            string                `dynamodbav:"rule_id"`
+	RuleName          string                `dynamodbav:"rule_name"`
+	ConditionField    string                `dynamodbav:"condition_field"`
+	ConditionOperator string                `dynamodbav:"condition_operator"`
+	ConditionValue    string                `dynamodbav:"condition_value"`
+	ActualFieldValue  string                `dynamodbav:"actual_field_value"`
+	Matched           bool                  `dynamodbav:"matched"`
+	ResultStatus      string                `dynamodbav:"result_status"`
+	EvaluatedAt       string                `dynamodbav:"evaluated_at"`
+	Priority          int                   `dynamodbav:"priority"`
+	ConditionResults  []conditionResultItem `dynamodbav:"condition_results,omitempty"`
+	RuleVersion       int                   `dynamodbav:"rule_version"`
+	RulesetVersion    int                   `dynamodbav:"ruleset_version"`
+	ListHits          []listHitItem         `dynamodbav:"list_hits,omitempty"`
+	DecisionMode      string                `dynamodbav:"decision_mode,omitempty"`
+	ScoreContribution int                   `dynamodbav:"score_contribution,omitempty"`
+	TotalScore        int                   `dynamodbav:"total_score,omitempty"`
+	ShadowRuleID      string                `dynamodbav:"shadow_rule_id,omitempty"`
+	ShadowStatus      string                `dynamodbav:"shadow_status,omitempty"`
+	ActualStatus      string                `dynamodbav:"actual_status,omitempty"`
+}
+
+type listHitItem struct {
+	ListName string `dynamodbav:"list_name"`
+	ListType string `dynamodbav:"list_type"`
+	Field    string `dynamodbav:"field"`
+	Value    string `dynamodbav:"value"`
+}
+
+type conditionResultItem struct {
 	ConditionField    string `dynamodbav:"condition_field"`
 	ConditionOperator string `dynamodbav:"condition_operator"`
 	ConditionValue    string `dynamodbav:"condition_value"`
 	ActualFieldValue  string `dynamodbav:"actual_field_value"`
 	Matched           bool   `dynamodbav:"matched"`
-	ResultStatus      string `dynamodbav:"result_status"`
-	EvaluatedAt       string `dynamodbav:"evaluated_at"`
-	Priority          int    `dynamodbav:"priority"`
 }
 
 // DynamoDBRuleEvaluationRepository implements repository.RuleEvaluationRepository using AWS DynamoDB.
@@ -167,7 +196,71 @@ func (r *DynamoDBRuleEvaluationRepository) FindByTransactionID(ctx context.Conte
 	return results, nil
 }
 
+// FindShadowByRuleID queries the shadow evaluations index for the results recorded for
+// the rule while it was SHADOW, evaluated at or after since.
+func (r *DynamoDBRuleEvaluationRepository) FindShadowByRuleID(
+	ctx context.Context,
+	ruleID string,
+	since time.Time,
+) ([]entity.RuleEvaluationResult, error) {
+	r.logger.Info().
+		Str("table", r.tableName).
+		Str("rule_id", ruleID).
+		Time("since", since).
+		Msg("querying shadow rule evaluations")
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(shadowEvaluationsIndex),
+		KeyConditionExpression: aws.String("shadow_rule_id = :ruleId AND evaluated_at >= :since"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":ruleId": &types.AttributeValueMemberS{Value: ruleID},
+			":since":  &types.AttributeValueMemberS{Value: since.UTC().Format(time.RFC3339)},
+		},
+	}
+
+	var results []entity.RuleEvaluationResult
+	for {
+		output, err := r.client.Query(ctx, input)
+		if err != nil {
+			r.logger.Error().Err(err).
+				Str("table", r.tableName).
+				Str("rule_id", ruleID).
+				Msg("failed to query shadow rule evaluations")
+			return nil, fmt.Errorf("failed to query shadow rule evaluations: %w", err)
+		}
+
+		var items []ruleEvaluationItem
+		if err := attributevalue.UnmarshalListOfMaps(output.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal rule evaluation items: %w", err)
+		}
+		for _, item := range items {
+			results = append(results, toRuleEvaluationResult(item))
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+
+	r.logger.Info().
+		Str("table", r.tableName).
+		Str("rule_id", ruleID).
+		Int("result_count", len(results)).
+		Msg("shadow rule evaluations retrieved")
+
+	return results, nil
+}
+
+// toRuleEvaluationItem converts a result into its table item. evaluated_at is stored
+// in UTC so it sorts chronologically in the shadow evaluations index.
 func toRuleEvaluationItem(r entity.RuleEvaluationResult) ruleEvaluationItem {
+	shadowRuleID := ""
+	if r.Shadow {
+		shadowRuleID = r.RuleID
+	}
+
 	return ruleEvaluationItem{
 		TransactionID:     r.TransactionID,
 		RuleID:            r.RuleID,
@@ -178,8 +271,18 @@ func toRuleEvaluationItem(r entity.RuleEvaluationResult) ruleEvaluationItem {
 		ActualFieldValue:  r.ActualFieldValue,
 		Matched:           r.Matched,
 		ResultStatus:      r.ResultStatus,
-		EvaluatedAt:       r.EvaluatedAt.Format(time.RFC3339),
+		EvaluatedAt:       r.EvaluatedAt.UTC().Format(time.RFC3339),
 		Priority:          r.Priority,
+		ConditionResults:  toConditionResultItems(r.ConditionResults),
+		RuleVersion:       r.RuleVersion,
+		RulesetVersion:    r.RulesetVersion,
+		ListHits:          toListHitItems(r.ListHits),
+		DecisionMode:      string(r.DecisionMode),
+		ScoreContribution: r.ScoreContribution,
+		TotalScore:        r.TotalScore,
+		ShadowRuleID:      shadowRuleID,
+		ShadowStatus:      string(r.ShadowStatus),
+		ActualStatus:      string(r.ActualStatus),
 	}
 }
 
@@ -198,5 +301,68 @@ func toRuleEvaluationResult(item ruleEvaluationItem) entity.RuleEvaluationResult
 		ResultStatus:      item.ResultStatus,
 		EvaluatedAt:       evaluatedAt,
 		Priority:          item.Priority,
+		ConditionResults:  toConditionResults(item.ConditionResults),
+		RuleVersion:       item.RuleVersion,
+		RulesetVersion:    item.RulesetVersion,
+		ListHits:          toListHits(item.ListHits),
+		DecisionMode:      entity.DecisionMode(item.DecisionMode),
+		ScoreContribution: item.ScoreContribution,
+		TotalScore:        item.TotalScore,
+		Shadow:            item.ShadowRuleID != "",
+		ShadowStatus:      entity.DecisionStatus(item.ShadowStatus),
+		ActualStatus:      entity.DecisionStatus(item.ActualStatus),
+	}
+}
+
+func toConditionResultItems(results []entity.ConditionResult) []conditionResultItem {
+	if len(results) == 0 {
+		return nil
+	}
+
+	items := make([]conditionResultItem, len(results))
+	for i, r := range results {
+		items[i] = conditionResultItem(r)
+	}
+	return items
+}
+
+func toConditionResults(items []conditionResultItem) []entity.ConditionResult {
+	if len(items) == 0 {
+		return nil
+	}
+
+	results := make([]entity.ConditionResult, len(items))
+	for i, item := range items {
+		results[i] = entity.ConditionResult(item)
+	}
+	return results
+}
+
+func toListHitItems(hits []entity.ListHit) []listHitItem {
+	if len(hits) == 0 {
+		return nil
+	}
+
+	items := make([]listHitItem, len(hits))
+	for i, h := range hits {
+		items[i] = listHitItem{ListName: h.ListName, ListType: string(h.ListType), Field: string(h.Field), Value: h.Value}
+	}
+	return items
+}
+
+func toListHits(items []listHitItem) []entity.ListHit {
+	if len(items) == 0 {
+		return nil
+	}
+
+	hits := make([]entity.ListHit, len(items))
+	for i, item := range items {
+		hits[i] = entity.ListHit{
+			ListName: item.ListName,
+			ListType: entity.ListType(item.ListType),
+			Field:    entity.ConditionField(item.Field),
+			Value:    item.Value,
+		}
 	}
+	return hits
 }
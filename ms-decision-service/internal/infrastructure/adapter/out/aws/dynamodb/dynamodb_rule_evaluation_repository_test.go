@@ -2,8 +2,11 @@ package dynamodb
 
 import (
 	"ms-decision-service/internal/domain/entity"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 )
 
 func TestToRuleEvaluationItem(t *testing.T) {
@@ -175,3 +178,115 @@ func TestRoundTripConversion(t *testing.T) {
 		t.Errorf("Priority mismatch: got %d, want %d", restored.Priority, original.Priority)
 	}
 }
+
+func TestRoundTripConversion_ConditionResults(t *testing.T) {
+	original := entity.RuleEvaluationResult{
+		TransactionID:     "txn-compound",
+		RuleID:            "rule-compound",
+		RuleName:          "Decline CRYPTO over $1,000",
+		ConditionOperator: "AND",
+		ConditionValue:    "(payment_method EQUAL CRYPTO AND amount_in_cents GREATER_THAN 100000)",
+		Matched:           false,
+		ResultStatus:      "DECLINED",
+		EvaluatedAt:       time.Date(2025, 6, 20, 14, 0, 0, 0, time.UTC),
+		Priority:          1,
+		ConditionResults: []entity.ConditionResult{
+			{ConditionField: "payment_method", ConditionOperator: "EQUAL", ConditionValue: "CRYPTO", ActualFieldValue: "CRYPTO", Matched: true},
+			{ConditionField: "amount_in_cents", ConditionOperator: "GREATER_THAN", ConditionValue: "100000", ActualFieldValue: "500", Matched: false},
+		},
+	}
+
+	restored := toRuleEvaluationResult(toRuleEvaluationItem(original))
+
+	if len(restored.ConditionResults) != len(original.ConditionResults) {
+		t.Fatalf("ConditionResults length: got %d, want %d", len(restored.ConditionResults), len(original.ConditionResults))
+	}
+	for i := range original.ConditionResults {
+		if restored.ConditionResults[i] != original.ConditionResults[i] {
+			t.Errorf("ConditionResults[%d]: got %+v, want %+v", i, restored.ConditionResults[i], original.ConditionResults[i])
+		}
+	}
+
+	single := toRuleEvaluationItem(entity.RuleEvaluationResult{RuleID: "rule-single"})
+	if single.ConditionResults != nil {
+		t.Errorf("expected nil ConditionResults for single-condition result, got %+v", single.ConditionResults)
+	}
+}
+
+func TestRoundTripConversion_ListHits(t *testing.T) {
+	original := entity.RuleEvaluationResult{
+		TransactionID:     "txn-list",
+		RuleID:            "rule-list",
+		ConditionField:    "customer_email",
+		ConditionOperator: "IN_LIST",
+		ConditionValue:    "blocked-emails",
+		Matched:           true,
+		ListHits: []entity.ListHit{
+			{ListName: "blocked-emails", ListType: entity.ListBlocklist, Field: entity.FieldCustomerEmail, Value: "fraud@example.com"},
+		},
+	}
+
+	restored := toRuleEvaluationResult(toRuleEvaluationItem(original))
+
+	if !reflect.DeepEqual(restored.ListHits, original.ListHits) {
+		t.Errorf("ListHits: got %+v, want %+v", restored.ListHits, original.ListHits)
+	}
+}
+
+func TestRoundTripConversion_Score(t *testing.T) {
+	original := entity.RuleEvaluationResult{
+		TransactionID:     "txn-score",
+		RuleID:            "rule-score",
+		Matched:           true,
+		DecisionMode:      entity.ModeScore,
+		ScoreContribution: 30,
+		TotalScore:        55,
+	}
+
+	av, err := attributevalue.MarshalMap(toRuleEvaluationItem(original))
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	var item ruleEvaluationItem
+	if err := attributevalue.UnmarshalMap(av, &item); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	restored := toRuleEvaluationResult(item)
+	if restored.DecisionMode != original.DecisionMode ||
+		restored.ScoreContribution != original.ScoreContribution ||
+		restored.TotalScore != original.TotalScore {
+		t.Errorf("score fields: got %+v, want %+v", restored, original)
+	}
+}
+
+func TestRoundTripConversion_Shadow(t *testing.T) {
+	original := entity.RuleEvaluationResult{
+		TransactionID: "txn-shadow",
+		RuleID:        "rule-shadow",
+		Matched:       true,
+		EvaluatedAt:   time.Date(2025, 1, 15, 5, 30, 0, 0, time.FixedZone("COT", -5*60*60)),
+		Shadow:        true,
+		ShadowStatus:  entity.DECLINED,
+		ActualStatus:  entity.APPROVED,
+	}
+
+	item := toRuleEvaluationItem(original)
+	if item.ShadowRuleID != "rule-shadow" {
+		t.Errorf("expected shadow rows to be indexed by rule, got %q", item.ShadowRuleID)
+	}
+	if item.EvaluatedAt != "2025-01-15T10:30:00Z" {
+		t.Errorf("expected evaluated_at in UTC, got %q", item.EvaluatedAt)
+	}
+
+	restored := toRuleEvaluationResult(item)
+	if !restored.Shadow || restored.ShadowStatus != entity.DECLINED || restored.ActualStatus != entity.APPROVED {
+		t.Errorf("shadow fields: got %+v, want %+v", restored, original)
+	}
+
+	live := toRuleEvaluationItem(entity.RuleEvaluationResult{TransactionID: "txn-live", RuleID: "rule-live"})
+	if live.ShadowRuleID != "" {
+		t.Errorf("expected live rows to stay out of the shadow index, got %q", live.ShadowRuleID)
+	}
+}
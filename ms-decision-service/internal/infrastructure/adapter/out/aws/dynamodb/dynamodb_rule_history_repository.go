@@ -0,0 +1,221 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"ms-decision-service/internal/domain/entity"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/rs/zerolog"
+)
+
+// rulesetCounterID is the partition key of the item holding the ruleset version counter.
+// It shares the history table with the rule versions and is excluded from scans.
+const rulesetCounterID = "__ruleset__"
+
+type ruleVersionItem struct {
+	RuleID          string                `dynamodbav:"rule_id"`
+	Version         int                   `dynamodbav:"version"`
+	RulesetVersion  int                   `dynamodbav:"ruleset_version"`
+	ChangeType      string                `dynamodbav:"change_type"`
+	ChangedBy       string                `dynamodbav:"changed_by"`
+	ChangedAt       string                `dynamodbav:"changed_at"`
+	Deleted         bool                  `dynamodbav:"deleted"`
+	RestoredVersion int                   `dynamodbav:"restored_version,omitempty"`
+	Rule            ruleItem              `dynamodbav:"rule"`
+	Changes         []ruleFieldChangeItem `dynamodbav:"changes,omitempty"`
+}
+
+type ruleFieldChangeItem struct {
+	Field  string `dynamodbav:"field"`
+	Before string `dynamodbav:"before"`
+	After  string `dynamodbav:"after"`
+}
+
+// DynamoDBRuleHistoryRepository implements repository.RuleHistoryRepository using AWS DynamoDB.
+// The table is keyed by rule_id (hash) and version (range). Its items are written
+// by DynamoDBRuleRepository.ApplyChanges.
+type DynamoDBRuleHistoryRepository struct {
+	client    *dynamodb.Client
+	tableName string
+	logger    zerolog.Logger
+}
+
+// NewDynamoDBRuleHistoryRepository creates a new DynamoDB-backed rule history repository.
+func NewDynamoDBRuleHistoryRepository(
+	client *dynamodb.Client,
+	tableName string,
+	logger zerolog.Logger,
+) *DynamoDBRuleHistoryRepository {
+	return &DynamoDBRuleHistoryRepository{client: client, tableName: tableName, logger: logger}
+}
+
+// FindByRuleID queries every version of the rule sorted by version ascending.
+func (r *DynamoDBRuleHistoryRepository) FindByRuleID(ctx context.Context, ruleID string) ([]entity.RuleVersion, error) {
+	output, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("rule_id = :ruleId"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":ruleId": &types.AttributeValueMemberS{Value: ruleID},
+		},
+		ScanIndexForward: aws.Bool(true),
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Str("rule_id", ruleID).Msg("failed to query rule history")
+		return nil, fmt.Errorf("failed to query rule history: %w", err)
+	}
+
+	versions, err := r.toRuleVersions(output.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+
+	return versions, nil
+}
+
+// FindAll scans the whole history table, following pagination, and returns the
+// versions sorted by ruleset version ascending.
+func (r *DynamoDBRuleHistoryRepository) FindAll(ctx context.Context) ([]entity.RuleVersion, error) {
+	input := &dynamodb.ScanInput{
+		TableName:        aws.String(r.tableName),
+		FilterExpression: aws.String("rule_id <> :counter"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":counter": &types.AttributeValueMemberS{Value: rulesetCounterID},
+		},
+	}
+
+	var versions []entity.RuleVersion
+	for {
+		output, err := r.client.Scan(ctx, input)
+		if err != nil {
+			r.logger.Error().Err(err).Str("table", r.tableName).Msg("failed to scan rule history")
+			return nil, fmt.Errorf("failed to scan rule history: %w", err)
+		}
+
+		page, err := r.toRuleVersions(output.Items)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, page...)
+
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].RulesetVersion != versions[j].RulesetVersion {
+			return versions[i].RulesetVersion < versions[j].RulesetVersion
+		}
+		return versions[i].Version < versions[j].Version
+	})
+
+	return versions, nil
+}
+
+// CurrentRulesetVersion reads the ruleset version counter with a strongly consistent
+// read. Returns 0 when no rule has been changed yet.
+func (r *DynamoDBRuleHistoryRepository) CurrentRulesetVersion(ctx context.Context) (int, error) {
+	output, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.tableName),
+		Key:            rulesetCounterKey(),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Msg("failed to get ruleset version")
+		return 0, fmt.Errorf("failed to get ruleset version: %w", err)
+	}
+
+	return parseRulesetVersion(output.Item)
+}
+
+func (r *DynamoDBRuleHistoryRepository) toRuleVersions(avs []map[string]types.AttributeValue) ([]entity.RuleVersion, error) {
+	var items []ruleVersionItem
+	if err := attributevalue.UnmarshalListOfMaps(avs, &items); err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).
+			Int("item_count", len(avs)).Msg("failed to unmarshal rule versions")
+		return nil, fmt.Errorf("failed to unmarshal rule versions: %w", err)
+	}
+
+	versions := make([]entity.RuleVersion, len(items))
+	for i, item := range items {
+		versions[i] = toRuleVersion(item)
+	}
+
+	return versions, nil
+}
+
+func rulesetCounterKey() map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"rule_id": &types.AttributeValueMemberS{Value: rulesetCounterID},
+		"version": &types.AttributeValueMemberN{Value: "0"},
+	}
+}
+
+func parseRulesetVersion(item map[string]types.AttributeValue) (int, error) {
+	attr, ok := item["ruleset_version"].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(attr.Value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse ruleset version %q: %w", attr.Value, err)
+	}
+
+	return version, nil
+}
+
+func toRuleVersionItem(v entity.RuleVersion) ruleVersionItem {
+	item := ruleVersionItem{
+		RuleID:          v.RuleID,
+		Version:         v.Version,
+		RulesetVersion:  v.RulesetVersion,
+		ChangeType:      string(v.ChangeType),
+		ChangedBy:       v.ChangedBy,
+		ChangedAt:       v.ChangedAt.Format(time.RFC3339Nano),
+		Deleted:         v.Deleted,
+		RestoredVersion: v.RestoredVersion,
+		Rule:            toRuleItem(v.Rule),
+	}
+
+	for _, c := range v.Changes {
+		item.Changes = append(item.Changes, ruleFieldChangeItem{Field: c.Field, Before: c.Before, After: c.After})
+	}
+
+	return item
+}
+
+func toRuleVersion(item ruleVersionItem) entity.RuleVersion {
+	changedAt, _ := time.Parse(time.RFC3339Nano, item.ChangedAt)
+
+	version := entity.RuleVersion{
+		RuleID:          item.RuleID,
+		Version:         item.Version,
+		RulesetVersion:  item.RulesetVersion,
+		ChangeType:      entity.RuleChangeType(item.ChangeType),
+		ChangedBy:       item.ChangedBy,
+		ChangedAt:       changedAt,
+		Deleted:         item.Deleted,
+		RestoredVersion: item.RestoredVersion,
+		Rule:            toRule(item.Rule),
+	}
+
+	for _, c := range item.Changes {
+		version.Changes = append(version.Changes, entity.RuleFieldChange{Field: c.Field, Before: c.Before, After: c.After})
+	}
+
+	return version
+}
@@ -0,0 +1,86 @@
+package dynamodb
+
+import (
+	"ms-decision-service/internal/domain/entity"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestRuleVersionItem_RoundTrip(t *testing.T) {
+	original := entity.RuleVersion{
+		RuleID:          "rule-001",
+		Version:         3,
+		RulesetVersion:  12,
+		ChangeType:      entity.RuleRolledBack,
+		ChangedBy:       "analyst@example.com",
+		ChangedAt:       time.Date(2025, 1, 15, 10, 30, 0, 123, time.UTC),
+		RestoredVersion: 1,
+		Rule: entity.Rule{
+			RuleID:            "rule-001",
+			RuleName:          "Block CRYPTO payments",
+			ConditionField:    entity.FieldPaymentMethod,
+			ConditionOperator: entity.OpEqual,
+			ConditionValue:    "CRYPTO",
+			ResultStatus:      entity.DECLINED,
+			Priority:          1,
+			IsActive:          true,
+			Version:           3,
+		},
+		Changes: []entity.RuleFieldChange{{Field: "priority", Before: "5", After: "1"}},
+	}
+
+	av, err := attributevalue.MarshalMap(toRuleVersionItem(original))
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	var item ruleVersionItem
+	if err := attributevalue.UnmarshalMap(av, &item); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if got := toRuleVersion(item); !reflect.DeepEqual(got, original) {
+		t.Errorf("round-trip mismatch:\n got  %+v\n want %+v", got, original)
+	}
+}
+
+func TestParseRulesetVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    map[string]types.AttributeValue
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "missing counter item is version 0",
+			item: nil,
+			want: 0,
+		},
+		{
+			name: "numeric counter",
+			item: map[string]types.AttributeValue{"ruleset_version": &types.AttributeValueMemberN{Value: "42"}},
+			want: 42,
+		},
+		{
+			name:    "corrupt counter",
+			item:    map[string]types.AttributeValue{"ruleset_version": &types.AttributeValueMemberN{Value: "abc"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRulesetVersion(tt.item)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,9 +2,11 @@ package dynamodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"ms-decision-service/internal/domain/entity"
 	"sort"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -14,38 +16,62 @@ import (
 )
 
 type ruleItem struct {
-	RuleID            string `dynamodbav:"rule_id"`
-	RuleName          string `dynamodbav:"rule_name"`
-	ConditionField    string `dynamodbav:"condition_field"`
-	ConditionOperator string `dynamodbav:"condition_operator"`
-	ConditionValue    string `dynamodbav:"condition_value"`
-	ResultStatus      string `dynamodbav:"result_status"`
-	Priority          int    `dynamodbav:"priority"`
-	IsActive          bool   `dynamodbav:"is_active"`
+	RuleID            string         `dynamodbav:"rule_id"`
+	RuleName          string         `dynamodbav:"rule_name"`
+	ConditionField    string         `dynamodbav:"condition_field"`
+	ConditionOperator string         `dynamodbav:"condition_operator"`
+	ConditionValue    string         `dynamodbav:"condition_value"`
+	Condition         *conditionItem `dynamodbav:"condition,omitempty"`
+	ResultStatus      string         `dynamodbav:"result_status"`
+	Priority          int            `dynamodbav:"priority"`
+	ScoreWeight       int            `dynamodbav:"score_weight,omitempty"`
+	IsActive          bool           `dynamodbav:"is_active"`
+	Mode              string         `dynamodbav:"mode,omitempty"`
+	Version           int            `dynamodbav:"version"`
 }
 
+// conditionItem is the nested map representation of a compound condition tree.
+// Rules without a condition attribute keep using the flat condition_* attributes.
+type conditionItem struct {
+	Logic    string          `dynamodbav:"logic,omitempty"`
+	Children []conditionItem `dynamodbav:"children,omitempty"`
+	Field    string          `dynamodbav:"field,omitempty"`
+	Operator string          `dynamodbav:"operator,omitempty"`
+	Value    string          `dynamodbav:"value,omitempty"`
+}
+
+// maxTransactItems is the number of items a DynamoDB transaction can write.
+const maxTransactItems = 100
+
 // DynamoDBRuleRepository implements repository.RuleRepository using AWS DynamoDB.
+// Rule changes are written together with their versions and the ruleset version
+// counter, which live in the rule history table.
 type DynamoDBRuleRepository struct {
-	client    *dynamodb.Client
-	tableName string
-	logger    zerolog.Logger
+	client           *dynamodb.Client
+	tableName        string
+	historyTableName string
+	logger           zerolog.Logger
 }
 
 // NewDynamoDBRuleRepository creates a new DynamoDB-backed rule repository.
 func NewDynamoDBRuleRepository(
 	client *dynamodb.Client,
 	tableName string,
+	historyTableName string,
 	logger zerolog.Logger,
 ) *DynamoDBRuleRepository {
-	return &DynamoDBRuleRepository{client: client, tableName: tableName, logger: logger}
+	return &DynamoDBRuleRepository{client: client, tableName: tableName, historyTableName: historyTableName, logger: logger}
 }
 
-// FindActiveRulesSortedByPriority scans the rules table for active rules and sorts by priority ascending.
+// FindActiveRulesSortedByPriority scans the rules table for active rules, pre-compiles
+// their condition values and sorts them by priority ascending. The scan is strongly
+// consistent, so it sees every change committed before it started.
 func (r *DynamoDBRuleRepository) FindActiveRulesSortedByPriority(ctx context.Context) ([]entity.Rule, error) {
 	r.logger.Info().Str("table", r.tableName).Msg("scanning rules table for active rules")
 
 	input := &dynamodb.ScanInput{
 		TableName:        aws.String(r.tableName),
+		ConsistentRead:   aws.Bool(true),
 		FilterExpression: aws.String("is_active = :active"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
 			":active": &types.AttributeValueMemberBOOL{Value: true},
@@ -67,15 +93,11 @@ func (r *DynamoDBRuleRepository) FindActiveRulesSortedByPriority(ctx context.Con
 
 	rules := make([]entity.Rule, len(items))
 	for i, item := range items {
-		rules[i] = entity.Rule{
-			RuleID:            item.RuleID,
-			RuleName:          item.RuleName,
-			ConditionField:    entity.ConditionField(item.ConditionField),
-			ConditionOperator: entity.ConditionOperator(item.ConditionOperator),
-			ConditionValue:    item.ConditionValue,
-			ResultStatus:      entity.DecisionStatus(item.ResultStatus),
-			Priority:          item.Priority,
-			IsActive:          item.IsActive,
+		rules[i] = toRule(item)
+		// Rules written outside the API may carry values that do not compile;
+		// those conditions never match instead of failing the whole load.
+		if err := rules[i].Compile(); err != nil {
+			r.logger.Warn().Err(err).Str("rule_id", rules[i].RuleID).Msg("rule has conditions that cannot be compiled")
 		}
 	}
 
@@ -111,16 +133,7 @@ func (r *DynamoDBRuleRepository) FindAll(ctx context.Context) ([]entity.Rule, er
 
 	rules := make([]entity.Rule, len(items))
 	for i, item := range items {
-		rules[i] = entity.Rule{
-			RuleID:            item.RuleID,
-			RuleName:          item.RuleName,
-			ConditionField:    entity.ConditionField(item.ConditionField),
-			ConditionOperator: entity.ConditionOperator(item.ConditionOperator),
-			ConditionValue:    item.ConditionValue,
-			ResultStatus:      entity.DecisionStatus(item.ResultStatus),
-			Priority:          item.Priority,
-			IsActive:          item.IsActive,
-		}
+		rules[i] = toRule(item)
 	}
 
 	sort.Slice(rules, func(i, j int) bool {
@@ -132,6 +145,238 @@ func (r *DynamoDBRuleRepository) FindAll(ctx context.Context) ([]entity.Rule, er
 	return rules, nil
 }
 
+// FindByID retrieves a single rule by its ID. Returns nil when the rule does not exist.
+func (r *DynamoDBRuleRepository) FindByID(ctx context.Context, ruleID string) (*entity.Rule, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"rule_id": &types.AttributeValueMemberS{Value: ruleID},
+		},
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Str("rule_id", ruleID).Msg("failed to get rule")
+		return nil, fmt.Errorf("failed to get rule: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var item ruleItem
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Str("rule_id", ruleID).Msg("failed to unmarshal rule")
+		return nil, fmt.Errorf("failed to unmarshal rule: %w", err)
+	}
+
+	rule := toRule(item)
+	return &rule, nil
+}
+
+// ApplyChanges writes the whole change set in a single DynamoDB transaction: the
+// rule puts and deletes, the history versions and the ruleset version counter. The
+// rule writes are conditioned on the stored rule version, the history versions on
+// not existing yet and the counter on still holding the previous ruleset version,
+// so a set made from data that changed concurrently stores nothing.
+func (r *DynamoDBRuleRepository) ApplyChanges(ctx context.Context, set *entity.RuleChangeSet) error {
+	items, err := r.changeSetItems(set)
+	if err != nil {
+		r.logger.Error().Err(err).Int("ruleset_version", set.RulesetVersion).Msg("failed to build rule change transaction")
+		return err
+	}
+	if len(items) > maxTransactItems {
+		return fmt.Errorf("rule change set of %d writes exceeds the transaction limit of %d", len(items), maxTransactItems)
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	if err != nil {
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) && isConflict(canceled) {
+			r.logger.Warn().Err(err).Int("ruleset_version", set.RulesetVersion).Msg("rule change set conflicts with a concurrent change")
+			return fmt.Errorf("%w: %w", entity.ErrRuleChangeConflict, err)
+		}
+		r.logger.Error().Err(err).Str("table", r.tableName).Int("ruleset_version", set.RulesetVersion).Msg("failed to apply rule changes")
+		return fmt.Errorf("failed to apply rule changes: %w", err)
+	}
+
+	r.logger.Info().Str("table", r.tableName).Int("ruleset_version", set.RulesetVersion).
+		Int("change_count", len(set.Changes)).Msg("rule changes applied")
+
+	return nil
+}
+
+// changeSetItems returns the transaction items of the change set, ending with the
+// ruleset version counter update.
+func (r *DynamoDBRuleRepository) changeSetItems(set *entity.RuleChangeSet) ([]types.TransactWriteItem, error) {
+	var items []types.TransactWriteItem
+	for _, change := range set.Changes {
+		if change.Baseline != nil {
+			item, err := r.historyPut(*change.Baseline)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+
+		item, err := r.historyPut(change.Version)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		item, err = r.ruleWrite(change)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	items = append(items, types.TransactWriteItem{Update: &types.Update{
+		TableName:           aws.String(r.historyTableName),
+		Key:                 rulesetCounterKey(),
+		UpdateExpression:    aws.String("SET ruleset_version = :next"),
+		ConditionExpression: aws.String("attribute_not_exists(ruleset_version) OR ruleset_version = :current"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":next":    &types.AttributeValueMemberN{Value: strconv.Itoa(set.RulesetVersion)},
+			":current": &types.AttributeValueMemberN{Value: strconv.Itoa(set.RulesetVersion - 1)},
+		},
+	}})
+
+	return items, nil
+}
+
+func (r *DynamoDBRuleRepository) historyPut(version entity.RuleVersion) (types.TransactWriteItem, error) {
+	av, err := attributevalue.MarshalMap(toRuleVersionItem(version))
+	if err != nil {
+		return types.TransactWriteItem{}, fmt.Errorf("failed to marshal rule version: %w", err)
+	}
+
+	return types.TransactWriteItem{Put: &types.Put{
+		TableName:           aws.String(r.historyTableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(rule_id)"),
+	}}, nil
+}
+
+// ruleWrite creates, replaces or deletes the rule. Rules written before versions
+// were tracked have no version attribute and match a Before.Version of zero.
+func (r *DynamoDBRuleRepository) ruleWrite(change entity.RuleChange) (types.TransactWriteItem, error) {
+	condition := "attribute_not_exists(rule_id)"
+	var values map[string]types.AttributeValue
+	if change.Before != nil {
+		condition = "attribute_exists(rule_id) AND version = :version"
+		if change.Before.Version == 0 {
+			condition = "attribute_exists(rule_id) AND (attribute_not_exists(version) OR version = :version)"
+		}
+		values = map[string]types.AttributeValue{
+			":version": &types.AttributeValueMemberN{Value: strconv.Itoa(change.Before.Version)},
+		}
+	}
+
+	if change.Version.Deleted {
+		return types.TransactWriteItem{Delete: &types.Delete{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"rule_id": &types.AttributeValueMemberS{Value: change.Version.RuleID},
+			},
+			ConditionExpression:       aws.String(condition),
+			ExpressionAttributeValues: values,
+		}}, nil
+	}
+
+	av, err := attributevalue.MarshalMap(toRuleItem(change.Version.Rule))
+	if err != nil {
+		return types.TransactWriteItem{}, fmt.Errorf("failed to marshal rule: %w", err)
+	}
+
+	return types.TransactWriteItem{Put: &types.Put{
+		TableName:                 aws.String(r.tableName),
+		Item:                      av,
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeValues: values,
+	}}, nil
+}
+
+// isConflict reports whether the transaction was canceled because a condition
+// failed or another transaction was writing the same items.
+func isConflict(canceled *types.TransactionCanceledException) bool {
+	for _, reason := range canceled.CancellationReasons {
+		if code := aws.ToString(reason.Code); code == "ConditionalCheckFailed" || code == "TransactionConflict" {
+			return true
+		}
+	}
+	return false
+}
+
+func toRule(item ruleItem) entity.Rule {
+	return entity.Rule{
+		RuleID:            item.RuleID,
+		RuleName:          item.RuleName,
+		ConditionField:    entity.ConditionField(item.ConditionField),
+		ConditionOperator: entity.ConditionOperator(item.ConditionOperator),
+		ConditionValue:    item.ConditionValue,
+		Condition:         toConditionNode(item.Condition),
+		ResultStatus:      entity.DecisionStatus(item.ResultStatus),
+		Priority:          item.Priority,
+		ScoreWeight:       item.ScoreWeight,
+		IsActive:          item.IsActive,
+		Mode:              entity.RuleMode(item.Mode),
+		Version:           item.Version,
+	}
+}
+
+func toRuleItem(rule entity.Rule) ruleItem {
+	return ruleItem{
+		RuleID:            rule.RuleID,
+		RuleName:          rule.RuleName,
+		ConditionField:    string(rule.ConditionField),
+		ConditionOperator: string(rule.ConditionOperator),
+		ConditionValue:    rule.ConditionValue,
+		Condition:         toConditionItem(rule.Condition),
+		ResultStatus:      string(rule.ResultStatus),
+		Priority:          rule.Priority,
+		ScoreWeight:       rule.ScoreWeight,
+		IsActive:          rule.IsActive,
+		Mode:              string(rule.Mode),
+		Version:           rule.Version,
+	}
+}
+
+func toConditionNode(item *conditionItem) *entity.ConditionNode {
+	if item == nil {
+		return nil
+	}
+
+	node := &entity.ConditionNode{
+		Logic:    entity.LogicalOperator(item.Logic),
+		Field:    entity.ConditionField(item.Field),
+		Operator: entity.ConditionOperator(item.Operator),
+		Value:    item.Value,
+	}
+	for i := range item.Children {
+		node.Children = append(node.Children, *toConditionNode(&item.Children[i]))
+	}
+
+	return node
+}
+
+func toConditionItem(node *entity.ConditionNode) *conditionItem {
+	if node == nil {
+		return nil
+	}
+
+	item := &conditionItem{
+		Logic:    string(node.Logic),
+		Field:    string(node.Field),
+		Operator: string(node.Operator),
+		Value:    node.Value,
+	}
+	for i := range node.Children {
+		item.Children = append(item.Children, *toConditionItem(&node.Children[i]))
+	}
+
+	return item
+}
+
 // FilterAndSortActiveRules filters rules to only active ones and sorts by priority ascending.
 // This is exported for testing purposes.
 func FilterAndSortActiveRules(rules []entity.Rule) []entity.Rule {
@@ -1,12 +1,19 @@
 package dynamodb
 
 import (
+	"context"
+	"fmt"
 	"ms-decision-service/internal/domain/entity"
+	"reflect"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
+	"github.com/rs/zerolog"
 )
 
 func genRule() gopter.Gen {
@@ -102,3 +109,167 @@ func TestProperty8_ActiveRulesFilteringAndSortInvariant(t *testing.T) {
 
 	properties.TestingRun(t)
 }
+
+func TestToRule_CompoundConditionRoundTrip(t *testing.T) {
+	original := entity.Rule{
+		RuleID:   "rule-100",
+		RuleName: "Decline CRYPTO over $1,000",
+		Condition: &entity.ConditionNode{
+			Logic: entity.LogicAnd,
+			Children: []entity.ConditionNode{
+				{Field: entity.FieldPaymentMethod, Operator: entity.OpEqual, Value: "CRYPTO"},
+				{
+					Logic:    entity.LogicNot,
+					Children: []entity.ConditionNode{{Field: entity.FieldAmountInCents, Operator: entity.OpLessThanOrEqual, Value: "100000"}},
+				},
+			},
+		},
+		ResultStatus: entity.DECLINED,
+		Priority:     1,
+		IsActive:     true,
+	}
+
+	av, err := attributevalue.MarshalMap(toRuleItem(original))
+	if err != nil {
+		t.Fatalf("failed to marshal rule item: %v", err)
+	}
+
+	var item ruleItem
+	if err := attributevalue.UnmarshalMap(av, &item); err != nil {
+		t.Fatalf("failed to unmarshal rule item: %v", err)
+	}
+
+	if got := toRule(item); !reflect.DeepEqual(got, original) {
+		t.Errorf("round-trip mismatch: got %+v, want %+v", got, original)
+	}
+}
+
+func TestToRule_LegacySingleConditionItem(t *testing.T) {
+	av := map[string]types.AttributeValue{
+		"rule_id":            &types.AttributeValueMemberS{Value: "rule-001"},
+		"rule_name":          &types.AttributeValueMemberS{Value: "Block CRYPTO payments"},
+		"condition_field":    &types.AttributeValueMemberS{Value: "payment_method"},
+		"condition_operator": &types.AttributeValueMemberS{Value: "EQUAL"},
+		"condition_value":    &types.AttributeValueMemberS{Value: "CRYPTO"},
+		"result_status":      &types.AttributeValueMemberS{Value: "DECLINED"},
+		"priority":           &types.AttributeValueMemberN{Value: "1"},
+		"is_active":          &types.AttributeValueMemberBOOL{Value: true},
+	}
+
+	var item ruleItem
+	if err := attributevalue.UnmarshalMap(av, &item); err != nil {
+		t.Fatalf("failed to unmarshal rule item: %v", err)
+	}
+
+	rule := toRule(item)
+	if rule.Condition != nil {
+		t.Errorf("expected no condition tree for legacy rule, got %+v", rule.Condition)
+	}
+	if rule.ConditionField != entity.FieldPaymentMethod || rule.ConditionValue != "CRYPTO" {
+		t.Errorf("unexpected legacy condition: %+v", rule)
+	}
+	if !rule.Matches(&entity.TransactionMessage{PaymentMethod: "CRYPTO"}) {
+		t.Error("expected legacy rule to match CRYPTO transaction")
+	}
+}
+
+func TestChangeSetItems(t *testing.T) {
+	r := &DynamoDBRuleRepository{tableName: "ddb-rules", historyTableName: "ddb-rule-history"}
+	seeded := entity.Rule{RuleID: "rule-001", Priority: 1}
+	updated := entity.Rule{RuleID: "rule-001", Priority: 2, Version: 2}
+
+	items, err := r.changeSetItems(&entity.RuleChangeSet{
+		RulesetVersion: 7,
+		Changes: []entity.RuleChange{
+			{
+				Before:   &seeded,
+				Baseline: &entity.RuleVersion{RuleID: "rule-001", Version: 1, Rule: seeded},
+				Version:  entity.RuleVersion{RuleID: "rule-001", Version: 2, RulesetVersion: 7, Rule: updated},
+			},
+			{
+				Version: entity.RuleVersion{RuleID: "rule-002", Version: 1, RulesetVersion: 7, Rule: entity.Rule{RuleID: "rule-002"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 6 {
+		t.Fatalf("expected baseline, 2 versions, 2 rule writes and the counter, got %d items", len(items))
+	}
+
+	for _, i := range []int{0, 1, 3} {
+		if put := items[i].Put; put == nil || *put.TableName != "ddb-rule-history" || *put.ConditionExpression != "attribute_not_exists(rule_id)" {
+			t.Errorf("item %d: expected a new history version, got %+v", i, items[i])
+		}
+	}
+	if put := items[2].Put; put == nil || *put.ConditionExpression != "attribute_exists(rule_id) AND (attribute_not_exists(version) OR version = :version)" {
+		t.Errorf("expected the seeded rule to be replaced only while unversioned, got %+v", items[2])
+	}
+	if put := items[4].Put; put == nil || *put.ConditionExpression != "attribute_not_exists(rule_id)" {
+		t.Errorf("expected the new rule to be created only when absent, got %+v", items[4])
+	}
+
+	counter := items[5].Update
+	if counter == nil {
+		t.Fatalf("expected the counter update last, got %+v", items[5])
+	}
+	next := counter.ExpressionAttributeValues[":next"].(*types.AttributeValueMemberN).Value
+	current := counter.ExpressionAttributeValues[":current"].(*types.AttributeValueMemberN).Value
+	if next != "7" || current != "6" {
+		t.Errorf("expected the counter to move from 6 to 7, got %s to %s", current, next)
+	}
+}
+
+func TestChangeSetItems_DeleteIsConditionedOnVersion(t *testing.T) {
+	r := &DynamoDBRuleRepository{tableName: "ddb-rules", historyTableName: "ddb-rule-history"}
+	before := entity.Rule{RuleID: "rule-001", Version: 3}
+
+	items, err := r.changeSetItems(&entity.RuleChangeSet{
+		RulesetVersion: 2,
+		Changes: []entity.RuleChange{{
+			Before:  &before,
+			Version: entity.RuleVersion{RuleID: "rule-001", Version: 4, RulesetVersion: 2, Deleted: true},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	del := items[1].Delete
+	if del == nil || *del.ConditionExpression != "attribute_exists(rule_id) AND version = :version" {
+		t.Fatalf("expected a conditional delete, got %+v", items[1])
+	}
+	if v := del.ExpressionAttributeValues[":version"].(*types.AttributeValueMemberN).Value; v != "3" {
+		t.Errorf("expected the delete to require version 3, got %s", v)
+	}
+}
+
+func TestApplyChanges_TransactionLimit(t *testing.T) {
+	r := &DynamoDBRuleRepository{tableName: "ddb-rules", historyTableName: "ddb-rule-history", logger: zerolog.Nop()}
+	changeSet := func(count int) *entity.RuleChangeSet {
+		set := &entity.RuleChangeSet{RulesetVersion: 2}
+		for i := range count {
+			rule := entity.Rule{RuleID: fmt.Sprintf("rule-%03d", i), Priority: i + 1}
+			set.Changes = append(set.Changes, entity.RuleChange{
+				Before:   &rule,
+				Baseline: &entity.RuleVersion{RuleID: rule.RuleID, Version: 1, Rule: rule},
+				Version:  entity.RuleVersion{RuleID: rule.RuleID, Version: 2, RulesetVersion: 2, Deleted: true, Rule: rule},
+			})
+		}
+		return set
+	}
+
+	items, err := r.changeSetItems(changeSet(entity.MaxRuleChanges))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != maxTransactItems {
+		t.Errorf("expected the largest change set to fill the %d items of a transaction, got %d", maxTransactItems, len(items))
+	}
+
+	// The set is rejected before the client, which is nil here, is called
+	if err := r.ApplyChanges(context.Background(), changeSet(entity.MaxRuleChanges+1)); err == nil {
+		t.Error("expected a change set over the transaction limit to be rejected")
+	}
+}
@@ -0,0 +1,132 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"ms-decision-service/internal/domain/entity"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/rs/zerolog"
+)
+
+// transactionItem mirrors the items the transaction evaluator writes to ddb-transactions.
+type transactionItem struct {
+	ID                string `dynamodbav:"id"`
+	AmountInCents     int64  `dynamodbav:"amount_in_cents"`
+	Currency          string `dynamodbav:"currency"`
+	PaymentMethod     string `dynamodbav:"payment_method"`
+	CustomerID        string `dynamodbav:"customer_id"`
+	CustomerName      string `dynamodbav:"customer_name"`
+	CustomerEmail     string `dynamodbav:"customer_email"`
+	CustomerPhone     string `dynamodbav:"customer_phone"`
+	CustomerIPAddress string `dynamodbav:"customer_ip_address"`
+	Status            string `dynamodbav:"status"`
+	CreatedAt         string `dynamodbav:"created_at"`
+	UpdatedAt         string `dynamodbav:"updated_at"`
+}
+
+// DynamoDBTransactionRepository implements repository.TransactionRepository by reading
+// the transaction evaluator's table. It never writes to it.
+type DynamoDBTransactionRepository struct {
+	client    *dynamodb.Client
+	tableName string
+	logger    zerolog.Logger
+}
+
+// NewDynamoDBTransactionRepository creates a new DynamoDB-backed transaction repository.
+func NewDynamoDBTransactionRepository(
+	client *dynamodb.Client,
+	tableName string,
+	logger zerolog.Logger,
+) *DynamoDBTransactionRepository {
+	return &DynamoDBTransactionRepository{client: client, tableName: tableName, logger: logger}
+}
+
+// FindCreatedBetween scans the table for transactions created in [from, to) and
+// returns the most recent limit of them, oldest first. The table has no index on
+// created_at, so every item is read; items with an unparseable created_at are skipped.
+func (r *DynamoDBTransactionRepository) FindCreatedBetween(
+	ctx context.Context,
+	from, to time.Time,
+	limit int,
+) ([]entity.TransactionMessage, error) {
+	r.logger.Info().
+		Str("table", r.tableName).
+		Time("from", from).
+		Time("to", to).
+		Int("limit", limit).
+		Msg("scanning historical transactions")
+
+	input := &dynamodb.ScanInput{TableName: aws.String(r.tableName)}
+
+	var transactions []entity.TransactionMessage
+	for {
+		output, err := r.client.Scan(ctx, input)
+		if err != nil {
+			r.logger.Error().Err(err).Str("table", r.tableName).Msg("failed to scan transactions")
+			return nil, fmt.Errorf("failed to scan transactions: %w", err)
+		}
+
+		var items []transactionItem
+		if err := attributevalue.UnmarshalListOfMaps(output.Items, &items); err != nil {
+			r.logger.Error().Err(err).Str("table", r.tableName).Msg("failed to unmarshal transactions")
+			return nil, fmt.Errorf("failed to unmarshal transactions: %w", err)
+		}
+		for _, item := range items {
+			transaction, ok := toTransactionMessage(item)
+			if !ok || transaction.CreatedAt.Before(from) || !transaction.CreatedAt.Before(to) {
+				continue
+			}
+			transactions = append(transactions, transaction)
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].CreatedAt.Before(transactions[j].CreatedAt)
+	})
+	if limit > 0 && len(transactions) > limit {
+		transactions = transactions[len(transactions)-limit:]
+	}
+
+	r.logger.Info().
+		Str("table", r.tableName).
+		Int("transaction_count", len(transactions)).
+		Msg("historical transactions retrieved")
+
+	return transactions, nil
+}
+
+// toTransactionMessage converts a table item, reporting false when created_at
+// cannot be parsed.
+func toTransactionMessage(item transactionItem) (entity.TransactionMessage, bool) {
+	createdAt, err := time.Parse(time.RFC3339, item.CreatedAt)
+	if err != nil {
+		return entity.TransactionMessage{}, false
+	}
+	updatedAt, _ := time.Parse(time.RFC3339, item.UpdatedAt)
+
+	return entity.TransactionMessage{
+		ID:                item.ID,
+		AmountInCents:     item.AmountInCents,
+		Currency:          item.Currency,
+		PaymentMethod:     item.PaymentMethod,
+		CustomerID:        item.CustomerID,
+		CustomerName:      item.CustomerName,
+		CustomerEmail:     item.CustomerEmail,
+		CustomerPhone:     item.CustomerPhone,
+		CustomerIPAddress: item.CustomerIPAddress,
+		Status:            item.Status,
+		CreatedAt:         createdAt,
+		UpdatedAt:         updatedAt,
+	}, true
+}
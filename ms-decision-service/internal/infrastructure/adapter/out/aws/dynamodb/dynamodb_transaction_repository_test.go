@@ -0,0 +1,37 @@
+package dynamodb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToTransactionMessage(t *testing.T) {
+	item := transactionItem{
+		ID:                "txn-001",
+		AmountInCents:     5000000,
+		Currency:          "USD",
+		PaymentMethod:     "CARD",
+		CustomerID:        "cust-001",
+		CustomerEmail:     "john@example.com",
+		CustomerIPAddress: "10.0.0.1",
+		Status:            "DECLINED",
+		CreatedAt:         "2025-01-15T10:30:00-05:00",
+		UpdatedAt:         "2025-01-15T15:30:02Z",
+	}
+
+	transaction, ok := toTransactionMessage(item)
+	if !ok {
+		t.Fatal("expected the item to convert")
+	}
+	if transaction.ID != "txn-001" || transaction.AmountInCents != 5000000 || transaction.Status != "DECLINED" {
+		t.Errorf("unexpected transaction: %+v", transaction)
+	}
+	if !transaction.CreatedAt.Equal(time.Date(2025, 1, 15, 15, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected created_at: %v", transaction.CreatedAt)
+	}
+
+	item.CreatedAt = "yesterday"
+	if _, ok := toTransactionMessage(item); ok {
+		t.Error("expected an unparseable created_at to be rejected")
+	}
+}
@@ -0,0 +1,162 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ms-decision-service/internal/domain/entity"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/rs/zerolog"
+)
+
+// velocityEventTimeLayout is a fixed-width UTC timestamp, so event IDs sort by time.
+const velocityEventTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+type velocityEventItem struct {
+	VelocityKey      string `dynamodbav:"velocity_key"`
+	EventID          string `dynamodbav:"event_id"`
+	TransactionID    string `dynamodbav:"transaction_id"`
+	AmountInUSDCents int64  `dynamodbav:"amount_in_usd_cents"`
+	OccurredAt       string `dynamodbav:"occurred_at"`
+	TTL              int64  `dynamodbav:"ttl"`
+}
+
+// DynamoDBVelocityStore implements repository.VelocityStore using AWS DynamoDB, so
+// every instance shares the same counters. The table is keyed by velocity_key (hash)
+// and event_id (range), the event time followed by the transaction ID, and items
+// carry a ttl attribute so DynamoDB removes them once they leave the retention period.
+type DynamoDBVelocityStore struct {
+	client    *dynamodb.Client
+	tableName string
+	retention time.Duration
+	logger    zerolog.Logger
+}
+
+// NewDynamoDBVelocityStore creates a new DynamoDB-backed velocity store keeping
+// events for the given retention.
+func NewDynamoDBVelocityStore(
+	client *dynamodb.Client,
+	tableName string,
+	retention time.Duration,
+	logger zerolog.Logger,
+) *DynamoDBVelocityStore {
+	return &DynamoDBVelocityStore{client: client, tableName: tableName, retention: retention, logger: logger}
+}
+
+// Record stores the event unless the key already holds it, then reads the key's
+// events of the longest window with a strongly consistent query and aggregates them.
+func (s *DynamoDBVelocityStore) Record(
+	ctx context.Context,
+	key string,
+	event entity.VelocityEvent,
+	windows []time.Duration,
+) ([]entity.VelocityAggregate, error) {
+	item := toVelocityEventItem(key, event, s.retention)
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal velocity event: %w", err)
+	}
+
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(event_id)"),
+	})
+	var duplicate *types.ConditionalCheckFailedException
+	if err != nil && !errors.As(err, &duplicate) {
+		s.logger.Error().Err(err).Str("table", s.tableName).Str("velocity_key", key).
+			Str("transaction_id", event.TransactionID).Msg("failed to record velocity event")
+		return nil, fmt.Errorf("failed to record velocity event: %w", err)
+	}
+
+	var longest time.Duration
+	for _, window := range windows {
+		longest = max(longest, window)
+	}
+
+	events, err := s.findEvents(ctx, key, event.OccurredAt.Add(-longest), event.OccurredAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return aggregateVelocityEvents(events, event.OccurredAt, windows), nil
+}
+
+// findEvents returns the events of the key that occurred in [from, to].
+func (s *DynamoDBVelocityStore) findEvents(ctx context.Context, key string, from, to time.Time) ([]velocityEventItem, error) {
+	paginator := dynamodb.NewQueryPaginator(s.client, &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("velocity_key = :key AND event_id BETWEEN :from AND :to"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":key":  &types.AttributeValueMemberS{Value: key},
+			":from": &types.AttributeValueMemberS{Value: from.UTC().Format(velocityEventTimeLayout)},
+			// "$" sorts after the "#" separating the time from the transaction ID.
+			":to": &types.AttributeValueMemberS{Value: to.UTC().Format(velocityEventTimeLayout) + "$"},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+
+	var items []velocityEventItem
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			s.logger.Error().Err(err).Str("table", s.tableName).Str("velocity_key", key).Msg("failed to query velocity events")
+			return nil, fmt.Errorf("failed to query velocity events: %w", err)
+		}
+
+		var pageItems []velocityEventItem
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageItems); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal velocity events: %w", err)
+		}
+		items = append(items, pageItems...)
+	}
+
+	return items, nil
+}
+
+// aggregateVelocityEvents sums the events that fall in each window ending at end.
+// A transaction recorded at several times is counted once, at its latest time, and
+// events with an unparsable time are skipped. Items are sorted by event_id, so
+// later events of a transaction come after earlier ones.
+func aggregateVelocityEvents(items []velocityEventItem, end time.Time, windows []time.Duration) []entity.VelocityAggregate {
+	latest := make(map[string]int, len(items))
+	for i, item := range items {
+		latest[item.TransactionID] = i
+	}
+
+	aggregates := make([]entity.VelocityAggregate, len(windows))
+	for i, item := range items {
+		if latest[item.TransactionID] != i {
+			continue
+		}
+		occurredAt, err := time.Parse(velocityEventTimeLayout, item.OccurredAt)
+		if err != nil {
+			continue
+		}
+		for w, window := range windows {
+			if occurredAt.After(end.Add(-window)) && !occurredAt.After(end) {
+				aggregates[w].Count++
+				aggregates[w].AmountSum += item.AmountInUSDCents
+			}
+		}
+	}
+	return aggregates
+}
+
+func toVelocityEventItem(key string, event entity.VelocityEvent, retention time.Duration) velocityEventItem {
+	occurredAt := event.OccurredAt.UTC().Format(velocityEventTimeLayout)
+	return velocityEventItem{
+		VelocityKey:      key,
+		EventID:          occurredAt + "#" + event.TransactionID,
+		TransactionID:    event.TransactionID,
+		AmountInUSDCents: event.AmountInUSDCents,
+		OccurredAt:       occurredAt,
+		TTL:              event.OccurredAt.Add(retention).Unix(),
+	}
+}
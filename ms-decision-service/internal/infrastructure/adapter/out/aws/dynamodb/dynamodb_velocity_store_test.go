@@ -0,0 +1,52 @@
+package dynamodb
+
+import (
+	"ms-decision-service/internal/domain/entity"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestToVelocityEventItem_SortsByTime(t *testing.T) {
+	base := time.Date(2025, 1, 15, 10, 0, 0, 0, time.FixedZone("COT", -5*3600))
+	earlier := toVelocityEventItem("customer:c1", entity.VelocityEvent{TransactionID: "tx-b", OccurredAt: base}, time.Hour)
+	later := toVelocityEventItem("customer:c1", entity.VelocityEvent{TransactionID: "tx-a", OccurredAt: base.Add(500 * time.Millisecond)}, time.Hour)
+
+	if earlier.EventID != "2025-01-15T15:00:00.000000000Z#tx-b" {
+		t.Errorf("unexpected event id %q", earlier.EventID)
+	}
+	if earlier.EventID >= later.EventID {
+		t.Errorf("expected %q to sort before %q", earlier.EventID, later.EventID)
+	}
+	if earlier.TTL != base.Add(time.Hour).Unix() {
+		t.Errorf("expected ttl at the end of the retention, got %d", earlier.TTL)
+	}
+}
+
+func TestAggregateVelocityEvents(t *testing.T) {
+	end := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	windows := []time.Duration{time.Minute, time.Hour, 24 * time.Hour}
+
+	var items []velocityEventItem
+	for _, e := range []entity.VelocityEvent{
+		{TransactionID: "tx-1", AmountInUSDCents: 100, OccurredAt: end.Add(-2 * time.Hour)},
+		{TransactionID: "tx-2", AmountInUSDCents: 200, OccurredAt: end.Add(-30 * time.Minute)},
+		{TransactionID: "tx-3", AmountInUSDCents: 300, OccurredAt: end},
+		// tx-2 recorded again at a later time counts once.
+		{TransactionID: "tx-2", AmountInUSDCents: 200, OccurredAt: end.Add(-10 * time.Second)},
+	} {
+		items = append(items, toVelocityEventItem("customer:c1", e, 24*time.Hour))
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].EventID < items[j].EventID })
+
+	got := aggregateVelocityEvents(items, end, windows)
+	want := []entity.VelocityAggregate{
+		{Count: 2, AmountSum: 500},
+		{Count: 2, AmountSum: 500},
+		{Count: 3, AmountSum: 600},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("aggregateVelocityEvents = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// CachingDecisionPolicyRepository decorates a repository.DecisionPolicyRepository
+// with an in-memory copy of every policy, so evaluations do not read the policies
+// table for every message. Save passes through and invalidates the copy.
+//
+// When a reload fails, the previous policies keep being served until a reload succeeds.
+type CachingDecisionPolicyRepository struct {
+	repository.DecisionPolicyRepository
+	logger zerolog.Logger
+
+	mu       sync.RWMutex
+	policies map[entity.RuleSet]entity.DecisionPolicy
+	stale    bool
+
+	// refreshMu serialises reloads so concurrent misses trigger a single scan.
+	refreshMu sync.Mutex
+}
+
+// NewCachingDecisionPolicyRepository creates a caching decorator around policyRepo.
+func NewCachingDecisionPolicyRepository(
+	policyRepo repository.DecisionPolicyRepository,
+	logger zerolog.Logger,
+) *CachingDecisionPolicyRepository {
+	return &CachingDecisionPolicyRepository{DecisionPolicyRepository: policyRepo, logger: logger}
+}
+
+// FindByRuleSet returns the cached policy of the rule set, reloading the policies
+// first when they are missing or have been invalidated.
+func (c *CachingDecisionPolicyRepository) FindByRuleSet(ctx context.Context, ruleSet entity.RuleSet) (*entity.DecisionPolicy, error) {
+	policies, err := c.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, ok := policies[ruleSet]
+	if !ok {
+		return nil, nil
+	}
+	return &policy, nil
+}
+
+// Save stores the policy and invalidates the cached policies.
+func (c *CachingDecisionPolicyRepository) Save(ctx context.Context, policy *entity.DecisionPolicy) error {
+	defer c.Invalidate()
+	return c.DecisionPolicyRepository.Save(ctx, policy)
+}
+
+// Invalidate marks the cached policies as stale so the next read reloads them.
+func (c *CachingDecisionPolicyRepository) Invalidate() {
+	c.mu.Lock()
+	c.stale = true
+	c.mu.Unlock()
+}
+
+// Refresh reloads the policies from the underlying repository. On failure the
+// previous policies are kept.
+func (c *CachingDecisionPolicyRepository) Refresh(ctx context.Context) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	_, err := c.refreshLocked(ctx)
+	return err
+}
+
+// Run reloads the policies every refreshInterval until ctx is cancelled, which
+// picks up policy changes made through other instances.
+func (c *CachingDecisionPolicyRepository) Run(ctx context.Context, refreshInterval time.Duration) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Refresh(ctx); err != nil {
+				c.logger.Warn().Err(err).Msg("scheduled decision policy refresh failed, serving previous policies")
+			}
+		}
+	}
+}
+
+// read returns the cached policies, reloading them when needed. Stale policies are
+// served when the reload fails; an error is returned only when nothing was loaded yet.
+func (c *CachingDecisionPolicyRepository) read(ctx context.Context) (map[entity.RuleSet]entity.DecisionPolicy, error) {
+	c.mu.RLock()
+	policies, stale := c.policies, c.stale
+	c.mu.RUnlock()
+
+	if policies != nil && !stale {
+		return policies, nil
+	}
+
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	// Another caller may have reloaded the policies while we waited.
+	c.mu.RLock()
+	if c.policies != nil && !c.stale {
+		policies = c.policies
+		c.mu.RUnlock()
+		return policies, nil
+	}
+	c.mu.RUnlock()
+
+	fresh, err := c.refreshLocked(ctx)
+	if err != nil {
+		if policies == nil {
+			return nil, err
+		}
+		c.logger.Warn().Err(err).Msg("decision policy refresh failed, serving stale policies")
+		return policies, nil
+	}
+
+	return fresh, nil
+}
+
+// refreshLocked reloads the policies. Callers must hold refreshMu.
+func (c *CachingDecisionPolicyRepository) refreshLocked(ctx context.Context) (map[entity.RuleSet]entity.DecisionPolicy, error) {
+	list, err := c.DecisionPolicyRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[entity.RuleSet]entity.DecisionPolicy, len(list))
+	for _, policy := range list {
+		policies[policy.RuleSet] = policy
+	}
+
+	c.mu.Lock()
+	c.policies = policies
+	c.stale = false
+	c.mu.Unlock()
+
+	return policies, nil
+}
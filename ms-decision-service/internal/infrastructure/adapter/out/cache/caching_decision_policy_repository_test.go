@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// --- Hand-written mocks ---
+
+type mockDecisionPolicyRepository struct {
+	policies  []entity.DecisionPolicy
+	findErr   error
+	findCalls int
+}
+
+func (m *mockDecisionPolicyRepository) FindAll(_ context.Context) ([]entity.DecisionPolicy, error) {
+	m.findCalls++
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	return m.policies, nil
+}
+
+func (m *mockDecisionPolicyRepository) FindByRuleSet(_ context.Context, _ entity.RuleSet) (*entity.DecisionPolicy, error) {
+	return nil, errors.New("not expected to be called through the cache")
+}
+
+func (m *mockDecisionPolicyRepository) Save(_ context.Context, policy *entity.DecisionPolicy) error {
+	m.policies = []entity.DecisionPolicy{*policy}
+	return nil
+}
+
+// --- Tests ---
+
+func TestCachingDecisionPolicyRepository_ServesPoliciesUntilSave(t *testing.T) {
+	policyRepo := &mockDecisionPolicyRepository{}
+	c := NewCachingDecisionPolicyRepository(policyRepo, zerolog.Nop())
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		policy, err := c.FindByRuleSet(ctx, entity.RuleSetTransaction)
+		if err != nil || policy != nil {
+			t.Fatalf("expected no stored policy, got %+v (%v)", policy, err)
+		}
+	}
+	if policyRepo.findCalls != 1 {
+		t.Fatalf("expected a single scan, got %d", policyRepo.findCalls)
+	}
+
+	saved := &entity.DecisionPolicy{RuleSet: entity.RuleSetTransaction, Mode: entity.ModeScore, DeclineThreshold: 80}
+	if err := c.Save(ctx, saved); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	policy, err := c.FindByRuleSet(ctx, entity.RuleSetTransaction)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy == nil || policy.Mode != entity.ModeScore {
+		t.Errorf("expected the saved policy after the reload, got %+v", policy)
+	}
+}
+
+func TestCachingDecisionPolicyRepository_ServesStalePoliciesWhenStoreIsDown(t *testing.T) {
+	policyRepo := &mockDecisionPolicyRepository{policies: []entity.DecisionPolicy{
+		{RuleSet: entity.RuleSetFraudScore, Mode: entity.ModeScore, DeclineThreshold: 50},
+	}}
+	c := NewCachingDecisionPolicyRepository(policyRepo, zerolog.Nop())
+	ctx := context.Background()
+
+	if err := c.Refresh(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.Invalidate()
+	policyRepo.findErr = errors.New("table unavailable")
+
+	policy, err := c.FindByRuleSet(ctx, entity.RuleSetFraudScore)
+	if err != nil || policy == nil {
+		t.Errorf("expected the stale policy to be served, got %+v (%v)", policy, err)
+	}
+}
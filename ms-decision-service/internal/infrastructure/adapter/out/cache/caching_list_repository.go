@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// CachingListRepository decorates a repository.ListRepository with an in-memory
+// list snapshot, so evaluations do not scan the lists table for every message.
+// Writes pass through to the underlying repository and invalidate the snapshot;
+// every other read goes to the underlying repository.
+//
+// When a reload fails, the previous snapshot keeps being served until a reload succeeds.
+// Entry expiry is applied by the snapshot itself at lookup time.
+type CachingListRepository struct {
+	repository.ListRepository
+	logger zerolog.Logger
+
+	mu       sync.RWMutex
+	snapshot *entity.ListSnapshot
+	stale    bool
+	// generation counts invalidations, so a reload that raced a write leaves the
+	// snapshot stale instead of hiding the write until the next scheduled refresh.
+	generation uint64
+
+	// refreshMu serialises reloads so concurrent misses trigger a single scan.
+	refreshMu sync.Mutex
+}
+
+// NewCachingListRepository creates a caching decorator around listRepo.
+func NewCachingListRepository(
+	listRepo repository.ListRepository,
+	logger zerolog.Logger,
+) *CachingListRepository {
+	return &CachingListRepository{ListRepository: listRepo, logger: logger}
+}
+
+// LoadSnapshot returns the cached snapshot, reloading it first when it is missing
+// or has been invalidated.
+func (c *CachingListRepository) LoadSnapshot(ctx context.Context) (*entity.ListSnapshot, error) {
+	c.mu.RLock()
+	snapshot, stale := c.snapshot, c.stale
+	c.mu.RUnlock()
+
+	if snapshot != nil && !stale {
+		return snapshot, nil
+	}
+
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	// Another caller may have reloaded the snapshot while we waited.
+	c.mu.RLock()
+	if c.snapshot != nil && !c.stale {
+		snapshot = c.snapshot
+		c.mu.RUnlock()
+		return snapshot, nil
+	}
+	c.mu.RUnlock()
+
+	fresh, err := c.refreshLocked(ctx)
+	if err != nil {
+		if snapshot == nil {
+			return nil, err
+		}
+		c.logger.Warn().Err(err).Msg("list cache refresh failed, serving stale snapshot")
+		return snapshot, nil
+	}
+
+	return fresh, nil
+}
+
+// Create stores the list and invalidates the snapshot.
+func (c *CachingListRepository) Create(ctx context.Context, list *entity.ManagedList) error {
+	defer c.Invalidate()
+	return c.ListRepository.Create(ctx, list)
+}
+
+// Delete removes the list and invalidates the snapshot.
+func (c *CachingListRepository) Delete(ctx context.Context, name string) error {
+	defer c.Invalidate()
+	return c.ListRepository.Delete(ctx, name)
+}
+
+// PutEntries stores the entries and invalidates the snapshot.
+func (c *CachingListRepository) PutEntries(ctx context.Context, entries []entity.ListEntry) error {
+	defer c.Invalidate()
+	return c.ListRepository.PutEntries(ctx, entries)
+}
+
+// DeleteEntry removes the entry and invalidates the snapshot.
+func (c *CachingListRepository) DeleteEntry(ctx context.Context, name, value string) error {
+	defer c.Invalidate()
+	return c.ListRepository.DeleteEntry(ctx, name, value)
+}
+
+// Invalidate marks the snapshot as stale so the next read reloads it.
+func (c *CachingListRepository) Invalidate() {
+	c.mu.Lock()
+	c.stale = true
+	c.generation++
+	c.mu.Unlock()
+}
+
+// Refresh reloads the snapshot from the underlying repository. On failure the
+// previous snapshot is kept.
+func (c *CachingListRepository) Refresh(ctx context.Context) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	_, err := c.refreshLocked(ctx)
+	return err
+}
+
+// Run reloads the snapshot every refreshInterval until ctx is cancelled, which
+// picks up list changes made through other instances.
+func (c *CachingListRepository) Run(ctx context.Context, refreshInterval time.Duration) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Refresh(ctx); err != nil {
+				c.logger.Warn().Err(err).Msg("scheduled list cache refresh failed, serving previous snapshot")
+			}
+		}
+	}
+}
+
+// refreshLocked reloads the snapshot. Callers must hold refreshMu.
+func (c *CachingListRepository) refreshLocked(ctx context.Context) (*entity.ListSnapshot, error) {
+	c.mu.RLock()
+	generation := c.generation
+	c.mu.RUnlock()
+
+	snapshot, err := c.ListRepository.LoadSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.snapshot = snapshot
+	c.stale = c.generation != generation
+	c.mu.Unlock()
+
+	c.logger.Debug().Int("list_count", snapshot.ListCount()).Msg("list cache refreshed")
+
+	return snapshot, nil
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// --- Hand-written mocks ---
+
+type mockListRepository struct {
+	lists     []entity.ManagedList
+	entries   []entity.ListEntry
+	loadErr   error
+	loadCalls int
+	// onLoad runs after the snapshot is built, before it is returned.
+	onLoad func()
+}
+
+func (m *mockListRepository) FindAll(_ context.Context) ([]entity.ManagedList, error) {
+	return m.lists, nil
+}
+
+func (m *mockListRepository) FindByName(_ context.Context, _ string) (*entity.ManagedList, error) {
+	return nil, nil
+}
+
+func (m *mockListRepository) FindEntries(_ context.Context, _ string) ([]entity.ListEntry, error) {
+	return m.entries, nil
+}
+
+func (m *mockListRepository) Create(_ context.Context, list *entity.ManagedList) error {
+	m.lists = append(m.lists, *list)
+	return nil
+}
+
+func (m *mockListRepository) Delete(_ context.Context, _ string) error {
+	return nil
+}
+
+func (m *mockListRepository) PutEntries(_ context.Context, entries []entity.ListEntry) error {
+	m.entries = append(m.entries, entries...)
+	return nil
+}
+
+func (m *mockListRepository) DeleteEntry(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func (m *mockListRepository) LoadSnapshot(_ context.Context) (*entity.ListSnapshot, error) {
+	m.loadCalls++
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+	snapshot := entity.NewListSnapshot(m.lists, m.entries)
+	if m.onLoad != nil {
+		m.onLoad()
+	}
+	return snapshot, nil
+}
+
+// --- Tests ---
+
+func TestCachingListRepository_ServesSnapshotUntilWrite(t *testing.T) {
+	listRepo := &mockListRepository{lists: []entity.ManagedList{
+		{Name: "blocked-ips", Type: entity.ListBlocklist, Field: entity.FieldCustomerIPAddress},
+	}}
+	c := NewCachingListRepository(listRepo, zerolog.Nop())
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.LoadSnapshot(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if listRepo.loadCalls != 1 {
+		t.Fatalf("expected a single load, got %d", listRepo.loadCalls)
+	}
+
+	if err := c.PutEntries(ctx, []entity.ListEntry{{ListName: "blocked-ips", Value: "10.0.0.1"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snapshot, err := c.LoadSnapshot(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if listRepo.loadCalls != 2 {
+		t.Errorf("expected the write to trigger a reload, got %d loads", listRepo.loadCalls)
+	}
+	if _, entry := snapshot.Lookup("blocked-ips", entity.FieldCustomerIPAddress, "10.0.0.1"); entry == nil {
+		t.Error("expected the new entry to be visible after the reload")
+	}
+}
+
+func TestCachingListRepository_WriteDuringReloadKeepsSnapshotStale(t *testing.T) {
+	listRepo := &mockListRepository{}
+	c := NewCachingListRepository(listRepo, zerolog.Nop())
+	ctx := context.Background()
+
+	// The write lands after the scan read the lists but before the reload commits.
+	listRepo.onLoad = func() {
+		listRepo.onLoad = nil
+		if err := c.Create(ctx, &entity.ManagedList{Name: "blocked-ips", Type: entity.ListBlocklist, Field: entity.FieldCustomerIPAddress}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := c.Refresh(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snapshot, err := c.LoadSnapshot(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if listRepo.loadCalls != 2 {
+		t.Errorf("expected the next read to reload, got %d loads", listRepo.loadCalls)
+	}
+	if snapshot.ListCount() != 1 {
+		t.Errorf("expected the list created during the reload to be visible, got %d lists", snapshot.ListCount())
+	}
+}
+
+func TestCachingListRepository_ServesStaleSnapshotWhenStoreIsDown(t *testing.T) {
+	listRepo := &mockListRepository{}
+	c := NewCachingListRepository(listRepo, zerolog.Nop())
+	ctx := context.Background()
+
+	if err := c.Refresh(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.Invalidate()
+	listRepo.loadErr = errors.New("table unavailable")
+
+	if snapshot, err := c.LoadSnapshot(ctx); err != nil || snapshot == nil {
+		t.Errorf("expected the stale snapshot to be served, got %v (%v)", snapshot, err)
+	}
+}
+
+func TestCachingListRepository_FailsWithoutSnapshot(t *testing.T) {
+	listRepo := &mockListRepository{loadErr: errors.New("table unavailable")}
+	c := NewCachingListRepository(listRepo, zerolog.Nop())
+
+	if _, err := c.LoadSnapshot(context.Background()); err == nil {
+		t.Error("expected an error when no snapshot has been loaded")
+	}
+}
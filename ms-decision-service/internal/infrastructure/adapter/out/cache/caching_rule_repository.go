@@ -0,0 +1,280 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"ms-decision-service/internal/domain/repository"
+	"ms-decision-service/internal/infrastructure/telemetry"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ruleSnapshot is an immutable, pre-sorted copy of the active rules and the ruleset
+// version they were loaded at.
+type ruleSnapshot struct {
+	rules          []entity.Rule
+	rulesetVersion int
+	refreshedAt    time.Time
+}
+
+// CachingRuleRepository decorates a repository.RuleRepository with an in-memory
+// snapshot of the active rules, so evaluations do not scan the rules table for every
+// message. Writes pass through to the underlying repository and invalidate the
+// snapshot; FindAll and FindByID always read from the underlying repository.
+//
+// When a reload fails, the previous snapshot keeps being served until a reload succeeds.
+type CachingRuleRepository struct {
+	repository.RuleRepository
+	historyRepo repository.RuleHistoryRepository
+	logger      zerolog.Logger
+	now         func() time.Time
+
+	mu       sync.RWMutex
+	snapshot *ruleSnapshot
+	stale    bool
+	// generation is bumped by every invalidation, so a reload that started before
+	// a write cannot mark its snapshot fresh.
+	generation uint64
+
+	// refreshMu serialises reloads so concurrent misses trigger a single scan.
+	refreshMu sync.Mutex
+}
+
+// NewCachingRuleRepository creates a caching decorator around ruleRepo. The ruleset
+// version is read from historyRepo together with the rules.
+func NewCachingRuleRepository(
+	ruleRepo repository.RuleRepository,
+	historyRepo repository.RuleHistoryRepository,
+	logger zerolog.Logger,
+) *CachingRuleRepository {
+	return &CachingRuleRepository{
+		RuleRepository: ruleRepo,
+		historyRepo:    historyRepo,
+		logger:         logger,
+		now:            time.Now,
+	}
+}
+
+// FindActiveRulesSortedByPriority returns the active rules from the snapshot,
+// reloading it first when it is missing or has been invalidated.
+func (c *CachingRuleRepository) FindActiveRulesSortedByPriority(ctx context.Context) ([]entity.Rule, error) {
+	snapshot, err := c.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]entity.Rule(nil), snapshot.rules...), nil
+}
+
+// FindActiveRuleset returns the active rules and the ruleset version of the same
+// snapshot, reloading it first when it is missing or has been invalidated.
+func (c *CachingRuleRepository) FindActiveRuleset(ctx context.Context) ([]entity.Rule, int, error) {
+	snapshot, err := c.read(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return append([]entity.Rule(nil), snapshot.rules...), snapshot.rulesetVersion, nil
+}
+
+// CurrentRulesetVersion returns the ruleset version of the snapshot, reloading it
+// first when it is missing or has been invalidated.
+func (c *CachingRuleRepository) CurrentRulesetVersion(ctx context.Context) (int, error) {
+	snapshot, err := c.read(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return snapshot.rulesetVersion, nil
+}
+
+// ApplyChanges writes the changes and invalidates the snapshot.
+func (c *CachingRuleRepository) ApplyChanges(ctx context.Context, set *entity.RuleChangeSet) error {
+	defer c.Invalidate()
+	return c.RuleRepository.ApplyChanges(ctx, set)
+}
+
+// Invalidate marks the snapshot as stale so the next read reloads it.
+func (c *CachingRuleRepository) Invalidate() {
+	c.mu.Lock()
+	c.stale = true
+	c.generation++
+	c.mu.Unlock()
+}
+
+// Refresh reloads the ruleset version and the active rules from the underlying
+// repositories and replaces the snapshot. On failure the previous snapshot is kept.
+func (c *CachingRuleRepository) Refresh(ctx context.Context) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	return c.refreshLocked(ctx)
+}
+
+// Status reports the version and age of the current snapshot.
+func (c *CachingRuleRepository) Status() entity.RuleCacheStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.snapshot == nil {
+		return entity.RuleCacheStatus{Stale: true}
+	}
+
+	return entity.RuleCacheStatus{
+		RulesetVersion: c.snapshot.rulesetVersion,
+		RuleCount:      len(c.snapshot.rules),
+		RefreshedAt:    c.snapshot.refreshedAt,
+		AgeSeconds:     c.now().Sub(c.snapshot.refreshedAt).Seconds(),
+		Stale:          c.stale,
+	}
+}
+
+// Run keeps the snapshot up to date until ctx is cancelled. Every versionCheckInterval
+// it reads the ruleset version and reloads the rules if it changed, which picks up
+// changes made through other instances. Every refreshInterval it reloads
+// unconditionally, which picks up changes made directly in the table.
+func (c *CachingRuleRepository) Run(ctx context.Context, versionCheckInterval, refreshInterval time.Duration) {
+	versionTicker := time.NewTicker(versionCheckInterval)
+	defer versionTicker.Stop()
+	refreshTicker := time.NewTicker(refreshInterval)
+	defer refreshTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-versionTicker.C:
+			c.checkVersion(ctx)
+		case <-refreshTicker.C:
+			if err := c.Refresh(ctx); err != nil {
+				c.logger.Warn().Err(err).Msg("scheduled rule cache refresh failed, serving previous snapshot")
+			}
+		}
+	}
+}
+
+// checkVersion reloads the snapshot when the stored ruleset version differs from the cached one.
+func (c *CachingRuleRepository) checkVersion(ctx context.Context) {
+	version, err := c.historyRepo.CurrentRulesetVersion(ctx)
+	if err != nil {
+		c.logger.Warn().Err(err).Msg("failed to check ruleset version")
+		return
+	}
+
+	c.mu.RLock()
+	changed := c.snapshot == nil || c.snapshot.rulesetVersion != version
+	c.mu.RUnlock()
+
+	if !changed {
+		return
+	}
+
+	c.logger.Info().Int("ruleset_version", version).Msg("ruleset version changed, refreshing rule cache")
+	if err := c.Refresh(ctx); err != nil {
+		c.logger.Warn().Err(err).Msg("rule cache refresh failed, serving previous snapshot")
+	}
+}
+
+// read returns a usable snapshot, reloading it when needed. A stale snapshot is
+// served when the reload fails; an error is returned only when there is no snapshot.
+func (c *CachingRuleRepository) read(ctx context.Context) (*ruleSnapshot, error) {
+	c.mu.RLock()
+	snapshot, stale := c.snapshot, c.stale
+	c.mu.RUnlock()
+
+	if snapshot != nil && !stale {
+		telemetry.RuleCacheRequests.WithLabelValues("hit").Inc()
+		return snapshot, nil
+	}
+
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	// Another caller may have reloaded the snapshot while we waited.
+	c.mu.RLock()
+	if c.snapshot != nil && !c.stale {
+		snapshot = c.snapshot
+		c.mu.RUnlock()
+		telemetry.RuleCacheRequests.WithLabelValues("hit").Inc()
+		return snapshot, nil
+	}
+	c.mu.RUnlock()
+
+	if err := c.refreshLocked(ctx); err != nil {
+		if snapshot == nil {
+			return nil, err
+		}
+		c.logger.Warn().Err(err).Int("ruleset_version", snapshot.rulesetVersion).
+			Msg("rule cache refresh failed, serving stale snapshot")
+		telemetry.RuleCacheRequests.WithLabelValues("stale").Inc()
+		return snapshot, nil
+	}
+
+	telemetry.RuleCacheRequests.WithLabelValues("miss").Inc()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.snapshot, nil
+}
+
+// refreshLocked reloads the snapshot. Callers must hold refreshMu.
+// A rule change and its ruleset version bump are committed together, and both are
+// read consistently with the version first, so the rules loaded are at least as
+// new as the version the snapshot claims. When the snapshot was invalidated during
+// the reload it is kept but stays stale, so the next read loads the write.
+func (c *CachingRuleRepository) refreshLocked(ctx context.Context) error {
+	c.mu.RLock()
+	generation := c.generation
+	c.mu.RUnlock()
+
+	version, err := c.historyRepo.CurrentRulesetVersion(ctx)
+	if err != nil {
+		telemetry.RuleCacheRefreshes.WithLabelValues("failure").Inc()
+		return fmt.Errorf("failed to load ruleset version: %w", err)
+	}
+
+	rules, err := c.RuleRepository.FindActiveRulesSortedByPriority(ctx)
+	if err != nil {
+		telemetry.RuleCacheRefreshes.WithLabelValues("failure").Inc()
+		return fmt.Errorf("failed to load active rules: %w", err)
+	}
+
+	snapshot := &ruleSnapshot{rules: rules, rulesetVersion: version, refreshedAt: c.now()}
+
+	c.mu.Lock()
+	c.snapshot = snapshot
+	c.stale = c.generation != generation
+	c.mu.Unlock()
+
+	telemetry.RuleCacheRefreshes.WithLabelValues("success").Inc()
+	telemetry.RuleCacheLastRefresh.Set(float64(snapshot.refreshedAt.Unix()))
+	telemetry.RuleCacheRulesetVersion.Set(float64(version))
+
+	c.logger.Info().Int("ruleset_version", version).Int("active_count", len(rules)).Msg("rule cache refreshed")
+
+	return nil
+}
+
+// CachingRuleHistoryRepository decorates a repository.RuleHistoryRepository so that
+// CurrentRulesetVersion is answered from the rule cache snapshot, keeping the
+// version stamped on decisions consistent with the cached rules.
+type CachingRuleHistoryRepository struct {
+	repository.RuleHistoryRepository
+	rules *CachingRuleRepository
+}
+
+// NewCachingRuleHistoryRepository creates a history decorator backed by the given rule cache.
+func NewCachingRuleHistoryRepository(
+	historyRepo repository.RuleHistoryRepository,
+	rules *CachingRuleRepository,
+) *CachingRuleHistoryRepository {
+	return &CachingRuleHistoryRepository{RuleHistoryRepository: historyRepo, rules: rules}
+}
+
+// CurrentRulesetVersion returns the ruleset version of the cached rules.
+func (h *CachingRuleHistoryRepository) CurrentRulesetVersion(ctx context.Context) (int, error) {
+	return h.rules.CurrentRulesetVersion(ctx)
+}
@@ -0,0 +1,263 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// --- Hand-written mocks ---
+
+type mockRuleRepository struct {
+	rules     []entity.Rule
+	findErr   error
+	findCalls int
+	onFind    func()
+}
+
+func (m *mockRuleRepository) FindActiveRulesSortedByPriority(_ context.Context) ([]entity.Rule, error) {
+	m.findCalls++
+	if m.onFind != nil {
+		m.onFind()
+	}
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	return m.rules, nil
+}
+
+func (m *mockRuleRepository) FindAll(_ context.Context) ([]entity.Rule, error) {
+	return m.rules, nil
+}
+
+func (m *mockRuleRepository) FindByID(_ context.Context, _ string) (*entity.Rule, error) {
+	return nil, nil
+}
+
+func (m *mockRuleRepository) ApplyChanges(_ context.Context, _ *entity.RuleChangeSet) error {
+	return nil
+}
+
+type mockRuleHistoryRepository struct {
+	rulesetVersion int
+	versionErr     error
+}
+
+func (m *mockRuleHistoryRepository) FindByRuleID(_ context.Context, _ string) ([]entity.RuleVersion, error) {
+	return nil, nil
+}
+
+func (m *mockRuleHistoryRepository) FindAll(_ context.Context) ([]entity.RuleVersion, error) {
+	return nil, nil
+}
+
+func (m *mockRuleHistoryRepository) CurrentRulesetVersion(_ context.Context) (int, error) {
+	return m.rulesetVersion, m.versionErr
+}
+
+// --- Helpers ---
+
+func newTestCache() (*CachingRuleRepository, *mockRuleRepository, *mockRuleHistoryRepository) {
+	ruleRepo := &mockRuleRepository{rules: []entity.Rule{{RuleID: "rule-001", Priority: 1, IsActive: true}}}
+	historyRepo := &mockRuleHistoryRepository{rulesetVersion: 3}
+	return NewCachingRuleRepository(ruleRepo, historyRepo, zerolog.Nop()), ruleRepo, historyRepo
+}
+
+// --- Tests ---
+
+func TestCachingRuleRepository_ServesSnapshot(t *testing.T) {
+	c, ruleRepo, _ := newTestCache()
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		rules, err := c.FindActiveRulesSortedByPriority(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rules) != 1 {
+			t.Fatalf("expected 1 rule, got %d", len(rules))
+		}
+	}
+
+	if ruleRepo.findCalls != 1 {
+		t.Errorf("expected a single scan, got %d", ruleRepo.findCalls)
+	}
+
+	version, err := c.CurrentRulesetVersion(ctx)
+	if err != nil || version != 3 {
+		t.Errorf("expected ruleset version 3, got %d (%v)", version, err)
+	}
+}
+
+func TestCachingRuleRepository_FindActiveRuleset(t *testing.T) {
+	c, ruleRepo, historyRepo := newTestCache()
+	ctx := context.Background()
+
+	if _, err := c.FindActiveRulesSortedByPriority(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A write through another instance: a reload between reading the version and
+	// the rules separately would pair the old rules with the new version
+	ruleRepo.rules = append(ruleRepo.rules, entity.Rule{RuleID: "rule-002", Priority: 2, IsActive: true})
+	historyRepo.rulesetVersion = 4
+
+	rules, version, err := c.FindActiveRuleset(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 1 || version != 3 {
+		t.Errorf("expected the rule and version of the snapshot, got %d rules at version %d", len(rules), version)
+	}
+
+	c.Invalidate()
+	if rules, version, err = c.FindActiveRuleset(ctx); err != nil || len(rules) != 2 || version != 4 {
+		t.Errorf("expected the reloaded 2 rules at version 4, got %d rules at version %d (%v)", len(rules), version, err)
+	}
+}
+
+func TestCachingRuleRepository_WritesInvalidate(t *testing.T) {
+	c, ruleRepo, historyRepo := newTestCache()
+	ctx := context.Background()
+
+	if _, err := c.FindActiveRulesSortedByPriority(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ruleRepo.rules = append(ruleRepo.rules, entity.Rule{RuleID: "rule-002", Priority: 2, IsActive: true})
+	historyRepo.rulesetVersion = 4
+	if err := c.ApplyChanges(ctx, &entity.RuleChangeSet{RulesetVersion: 4}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !c.Status().Stale {
+		t.Error("expected snapshot to be stale after a write")
+	}
+
+	rules, err := c.FindActiveRulesSortedByPriority(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 2 || ruleRepo.findCalls != 2 {
+		t.Errorf("expected reload with 2 rules, got %d rules after %d scans", len(rules), ruleRepo.findCalls)
+	}
+	if status := c.Status(); status.RulesetVersion != 4 || status.Stale {
+		t.Errorf("unexpected status after reload: %+v", status)
+	}
+}
+
+func TestCachingRuleRepository_WriteDuringReloadKeepsSnapshotStale(t *testing.T) {
+	c, ruleRepo, _ := newTestCache()
+	ctx := context.Background()
+
+	// The write lands after the scan read the rules but before the reload commits.
+	ruleRepo.onFind = func() {
+		ruleRepo.onFind = nil
+		if err := c.ApplyChanges(ctx, &entity.RuleChangeSet{RulesetVersion: 4}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := c.Refresh(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !c.Status().Stale {
+		t.Fatal("expected a reload that raced a write to stay stale")
+	}
+
+	if _, err := c.FindActiveRulesSortedByPriority(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ruleRepo.findCalls != 2 || c.Status().Stale {
+		t.Errorf("expected the next read to reload, got %d scans and %+v", ruleRepo.findCalls, c.Status())
+	}
+}
+
+func TestCachingRuleRepository_ServesStaleSnapshotWhenStoreIsDown(t *testing.T) {
+	c, ruleRepo, _ := newTestCache()
+	ctx := context.Background()
+
+	if _, err := c.FindActiveRulesSortedByPriority(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ruleRepo.findErr = errors.New("dynamo unavailable")
+	c.Invalidate()
+
+	rules, err := c.FindActiveRulesSortedByPriority(ctx)
+	if err != nil {
+		t.Fatalf("expected stale snapshot to be served, got %v", err)
+	}
+	if len(rules) != 1 {
+		t.Errorf("expected 1 cached rule, got %d", len(rules))
+	}
+	if !c.Status().Stale {
+		t.Error("expected snapshot to remain stale until a reload succeeds")
+	}
+}
+
+func TestCachingRuleRepository_FailsWithoutSnapshot(t *testing.T) {
+	c, ruleRepo, _ := newTestCache()
+	ruleRepo.findErr = errors.New("dynamo unavailable")
+
+	if _, err := c.FindActiveRulesSortedByPriority(context.Background()); err == nil {
+		t.Fatal("expected error when no snapshot has ever been loaded")
+	}
+}
+
+func TestCachingRuleRepository_CheckVersionReloadsOnChange(t *testing.T) {
+	c, ruleRepo, historyRepo := newTestCache()
+	ctx := context.Background()
+
+	if err := c.Refresh(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.checkVersion(ctx)
+	if ruleRepo.findCalls != 1 {
+		t.Errorf("expected no reload while the version is unchanged, got %d scans", ruleRepo.findCalls)
+	}
+
+	historyRepo.rulesetVersion = 4
+	c.checkVersion(ctx)
+	if ruleRepo.findCalls != 2 {
+		t.Errorf("expected a reload after the version changed, got %d scans", ruleRepo.findCalls)
+	}
+}
+
+func TestCachingRuleRepository_StatusReportsAge(t *testing.T) {
+	c, _, _ := newTestCache()
+	refreshedAt := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	c.now = func() time.Time { return refreshedAt }
+
+	if err := c.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.now = func() time.Time { return refreshedAt.Add(90 * time.Second) }
+	status := c.Status()
+
+	if status.AgeSeconds != 90 || !status.RefreshedAt.Equal(refreshedAt) || status.RuleCount != 1 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestCachingRuleHistoryRepository_UsesCachedVersion(t *testing.T) {
+	c, _, historyRepo := newTestCache()
+	h := NewCachingRuleHistoryRepository(historyRepo, c)
+	ctx := context.Background()
+
+	if _, err := h.CurrentRulesetVersion(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	historyRepo.rulesetVersion = 9
+	version, err := h.CurrentRulesetVersion(ctx)
+	if err != nil || version != 3 {
+		t.Errorf("expected cached version 3, got %d (%v)", version, err)
+	}
+}
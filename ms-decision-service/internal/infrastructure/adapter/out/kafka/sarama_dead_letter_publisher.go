@@ -0,0 +1,90 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rs/zerolog"
+)
+
+// Headers added to a dead-lettered message, next to the headers of the original
+// message, describing where it came from and why it failed.
+const (
+	HeaderDeadLetterSourceTopic     = "dlq-source-topic"
+	HeaderDeadLetterSourcePartition = "dlq-source-partition"
+	HeaderDeadLetterSourceOffset    = "dlq-source-offset"
+	HeaderDeadLetterError           = "dlq-error"
+	HeaderDeadLetterAttempts        = "dlq-attempts"
+	HeaderDeadLetterFailedAt        = "dlq-failed-at"
+)
+
+// SaramaDeadLetterPublisher implements repository.DeadLetterPublisher using Sarama.
+// The service creates one per consumed topic, writing to Transaction.Created.DLQ
+// or FraudSignals.Calculated.DLQ.
+type SaramaDeadLetterPublisher struct {
+	producer sarama.SyncProducer
+	topic    string
+	logger   zerolog.Logger
+}
+
+// NewSaramaDeadLetterPublisher creates a new Kafka-backed dead-letter publisher
+// writing to the given topic.
+func NewSaramaDeadLetterPublisher(
+	producer sarama.SyncProducer,
+	topic string,
+	logger zerolog.Logger,
+) *SaramaDeadLetterPublisher {
+	return &SaramaDeadLetterPublisher{producer: producer, topic: topic, logger: logger}
+}
+
+// Publish sends the original payload and key to the dead-letter topic, with the
+// original headers followed by the dlq-* headers. dlq-attempts counts the
+// attempts made by the consumer's retry policy.
+func (p *SaramaDeadLetterPublisher) Publish(_ context.Context, deadLetter *entity.DeadLetter) error {
+	msg := &sarama.ProducerMessage{
+		Topic:   p.topic,
+		Value:   sarama.StringEncoder(deadLetter.Payload),
+		Headers: toDeadLetterHeaders(deadLetter),
+	}
+	if deadLetter.Key != "" {
+		msg.Key = sarama.StringEncoder(deadLetter.Key)
+	}
+
+	partition, offset, err := p.producer.SendMessage(msg)
+	if err != nil {
+		return fmt.Errorf("failed to publish dead letter: %w", err)
+	}
+
+	p.logger.Warn().
+		Str("topic", p.topic).
+		Str("source_topic", deadLetter.SourceTopic).
+		Int32("source_partition", deadLetter.SourcePartition).
+		Int64("source_offset", deadLetter.SourceOffset).
+		Int("attempts", deadLetter.Attempts).
+		Str("error", deadLetter.Error).
+		Int32("partition", partition).
+		Int64("offset", offset).
+		Msg("message published to dead-letter topic")
+
+	return nil
+}
+
+func toDeadLetterHeaders(deadLetter *entity.DeadLetter) []sarama.RecordHeader {
+	headers := make([]sarama.RecordHeader, 0, len(deadLetter.Headers)+6)
+	for key, value := range deadLetter.Headers {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+
+	return append(headers,
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterSourceTopic), Value: []byte(deadLetter.SourceTopic)},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterSourcePartition), Value: []byte(strconv.FormatInt(int64(deadLetter.SourcePartition), 10))},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterSourceOffset), Value: []byte(strconv.FormatInt(deadLetter.SourceOffset, 10))},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterError), Value: []byte(deadLetter.Error)},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterAttempts), Value: []byte(strconv.Itoa(deadLetter.Attempts))},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterFailedAt), Value: []byte(deadLetter.FailedAt.UTC().Format(time.RFC3339Nano))},
+	)
+}
@@ -0,0 +1,67 @@
+package kafka
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSaramaDeadLetterPublisher_Publish(t *testing.T) {
+	producer := &capturingSyncProducer{}
+	publisher := NewSaramaDeadLetterPublisher(producer, "Transaction.Created.DLQ", zerolog.Nop())
+
+	deadLetter := &entity.DeadLetter{
+		SourceTopic:     "Transaction.Created",
+		SourcePartition: 2,
+		SourceOffset:    42,
+		Key:             "tx-1",
+		Payload:         `{"id":"tx-1"}`,
+		Headers:         map[string]string{"traceparent": "00-abc-def-01"},
+		Error:           "failed to retrieve rules: timeout",
+		Attempts:        3,
+		FailedAt:        time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	if err := publisher.Publish(context.Background(), deadLetter); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	msg := producer.lastMessage
+	if msg == nil {
+		t.Fatal("expected a message to be sent")
+	}
+	if msg.Topic != "Transaction.Created.DLQ" {
+		t.Errorf("expected topic Transaction.Created.DLQ, got %s", msg.Topic)
+	}
+
+	key, _ := msg.Key.Encode()
+	if string(key) != "tx-1" {
+		t.Errorf("expected key tx-1, got %s", key)
+	}
+	value, _ := msg.Value.Encode()
+	if string(value) != `{"id":"tx-1"}` {
+		t.Errorf("expected original payload, got %s", value)
+	}
+
+	headers := make(map[string]string, len(msg.Headers))
+	for _, header := range msg.Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+	expected := map[string]string{
+		"traceparent":                   "00-abc-def-01",
+		HeaderDeadLetterSourceTopic:     "Transaction.Created",
+		HeaderDeadLetterSourcePartition: "2",
+		HeaderDeadLetterSourceOffset:    "42",
+		HeaderDeadLetterError:           "failed to retrieve rules: timeout",
+		HeaderDeadLetterAttempts:        "3",
+		HeaderDeadLetterFailedAt:        "2025-01-02T03:04:05Z",
+	}
+	for key, want := range expected {
+		if headers[key] != want {
+			t.Errorf("expected header %s=%q, got %q", key, want, headers[key])
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ms-decision-service/internal/domain/entity"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rs/zerolog"
+)
+
+// deadLetterHeaderPrefix prefixes the headers added when a message is dead-lettered.
+const deadLetterHeaderPrefix = "dlq-"
+
+// deadLetterReadTimeout bounds the wait for the next message of a dead-letter topic.
+const deadLetterReadTimeout = 10 * time.Second
+
+// SaramaDeadLetterRepository implements repository.DeadLetterRepository, reading
+// dead-letter topics with a partition consumer and redriving with the producer.
+type SaramaDeadLetterRepository struct {
+	client   sarama.Client
+	producer sarama.SyncProducer
+	logger   zerolog.Logger
+}
+
+// NewSaramaDeadLetterRepository creates a new Kafka-backed dead-letter repository.
+func NewSaramaDeadLetterRepository(
+	client sarama.Client,
+	producer sarama.SyncProducer,
+	logger zerolog.Logger,
+) *SaramaDeadLetterRepository {
+	return &SaramaDeadLetterRepository{client: client, producer: producer, logger: logger}
+}
+
+// Count returns the number of messages retained in the topic across its partitions.
+func (r *SaramaDeadLetterRepository) Count(_ context.Context, topic string) (int64, error) {
+	partitions, err := r.client.Partitions(topic)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list partitions of %s: %w", topic, err)
+	}
+
+	var count int64
+	for _, partition := range partitions {
+		oldest, newest, err := r.offsets(topic, partition)
+		if err != nil {
+			return 0, err
+		}
+		count += newest - oldest
+	}
+	return count, nil
+}
+
+// FindAll reads up to limit messages from each partition and returns the limit
+// messages that failed first.
+func (r *SaramaDeadLetterRepository) FindAll(ctx context.Context, topic string, limit int) ([]entity.DeadLetter, error) {
+	partitions, err := r.client.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions of %s: %w", topic, err)
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(r.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+	defer consumer.Close()
+
+	deadLetters := []entity.DeadLetter{}
+	for _, partition := range partitions {
+		oldest, newest, err := r.offsets(topic, partition)
+		if err != nil {
+			return nil, err
+		}
+		if oldest >= newest {
+			continue
+		}
+
+		messages, err := readPartition(ctx, consumer, topic, partition, oldest, newest, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range messages {
+			deadLetters = append(deadLetters, toDeadLetter(msg))
+		}
+	}
+
+	sort.SliceStable(deadLetters, func(i, j int) bool {
+		a, b := deadLetters[i], deadLetters[j]
+		if !a.FailedAt.Equal(b.FailedAt) {
+			return a.FailedAt.Before(b.FailedAt)
+		}
+		if a.Partition != b.Partition {
+			return a.Partition < b.Partition
+		}
+		return a.Offset < b.Offset
+	})
+	if len(deadLetters) > limit {
+		deadLetters = deadLetters[:limit]
+	}
+
+	return deadLetters, nil
+}
+
+// FindByOffset returns the message at the offset, or nil when the partition does
+// not exist or the offset is not retained.
+func (r *SaramaDeadLetterRepository) FindByOffset(
+	ctx context.Context,
+	topic string,
+	partition int32,
+	offset int64,
+) (*entity.DeadLetter, error) {
+	partitions, err := r.client.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions of %s: %w", topic, err)
+	}
+	if !slices.Contains(partitions, partition) {
+		return nil, nil
+	}
+
+	oldest, newest, err := r.offsets(topic, partition)
+	if err != nil {
+		return nil, err
+	}
+	if offset < oldest || offset >= newest {
+		return nil, nil
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(r.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+	defer consumer.Close()
+
+	messages, err := readPartition(ctx, consumer, topic, partition, offset, newest, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 || messages[0].Offset != offset {
+		return nil, nil
+	}
+
+	deadLetter := toDeadLetter(messages[0])
+	return &deadLetter, nil
+}
+
+// Redrive publishes the original key, payload and headers to the source topic.
+func (r *SaramaDeadLetterRepository) Redrive(_ context.Context, deadLetter *entity.DeadLetter) error {
+	msg := &sarama.ProducerMessage{
+		Topic: deadLetter.SourceTopic,
+		Value: sarama.StringEncoder(deadLetter.Payload),
+	}
+	if deadLetter.Key != "" {
+		msg.Key = sarama.StringEncoder(deadLetter.Key)
+	}
+	for key, value := range deadLetter.Headers {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+
+	partition, offset, err := r.producer.SendMessage(msg)
+	if err != nil {
+		return fmt.Errorf("failed to redrive dead letter %s: %w", deadLetter.ID, err)
+	}
+
+	r.logger.Info().
+		Str("dead_letter_id", deadLetter.ID).
+		Str("topic", deadLetter.SourceTopic).
+		Int32("partition", partition).
+		Int64("offset", offset).
+		Msg("dead letter redriven")
+
+	return nil
+}
+
+// offsets returns the oldest retained offset of the partition and the offset of
+// the next message to be written.
+func (r *SaramaDeadLetterRepository) offsets(topic string, partition int32) (int64, int64, error) {
+	oldest, err := r.client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get oldest offset of %s/%d: %w", topic, partition, err)
+	}
+	newest, err := r.client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get newest offset of %s/%d: %w", topic, partition, err)
+	}
+	return oldest, newest, nil
+}
+
+// readPartition reads up to limit messages of the partition from offset start,
+// stopping before offset end.
+func readPartition(
+	ctx context.Context,
+	consumer sarama.Consumer,
+	topic string,
+	partition int32,
+	start, end int64,
+	limit int,
+) ([]*sarama.ConsumerMessage, error) {
+	partitionConsumer, err := consumer.ConsumePartition(topic, partition, start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume %s/%d: %w", topic, partition, err)
+	}
+	defer partitionConsumer.Close()
+
+	timer := time.NewTimer(deadLetterReadTimeout)
+	defer timer.Stop()
+
+	var messages []*sarama.ConsumerMessage
+	for len(messages) < limit {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			return nil, fmt.Errorf("timed out reading %s/%d", topic, partition)
+		case consumerErr := <-partitionConsumer.Errors():
+			return nil, fmt.Errorf("failed to read %s/%d: %w", topic, partition, consumerErr)
+		case msg, ok := <-partitionConsumer.Messages():
+			if !ok {
+				return nil, errors.New("partition consumer closed")
+			}
+			if msg.Offset >= end {
+				return messages, nil
+			}
+			messages = append(messages, msg)
+			if msg.Offset >= end-1 {
+				return messages, nil
+			}
+		}
+	}
+	return messages, nil
+}
+
+// toDeadLetter converts a message of a dead-letter topic, separating the dlq-*
+// headers from the headers of the original message and reading the transaction
+// ID from its payload.
+func toDeadLetter(msg *sarama.ConsumerMessage) entity.DeadLetter {
+	deadLetter := entity.DeadLetter{
+		ID:        entity.DeadLetterID(msg.Partition, msg.Offset),
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       string(msg.Key),
+		Payload:   string(msg.Value),
+		Headers:   map[string]string{},
+	}
+
+	for _, header := range msg.Headers {
+		if header == nil {
+			continue
+		}
+		key, value := string(header.Key), string(header.Value)
+		switch key {
+		case HeaderDeadLetterSourceTopic:
+			deadLetter.SourceTopic = value
+		case HeaderDeadLetterSourcePartition:
+			if partition, err := strconv.ParseInt(value, 10, 32); err == nil {
+				deadLetter.SourcePartition = int32(partition)
+			}
+		case HeaderDeadLetterSourceOffset:
+			if offset, err := strconv.ParseInt(value, 10, 64); err == nil {
+				deadLetter.SourceOffset = offset
+			}
+		case HeaderDeadLetterError:
+			deadLetter.Error = value
+		case HeaderDeadLetterAttempts:
+			if attempts, err := strconv.Atoi(value); err == nil {
+				deadLetter.Attempts = attempts
+			}
+		case HeaderDeadLetterFailedAt:
+			if failedAt, err := time.Parse(time.RFC3339Nano, value); err == nil {
+				deadLetter.FailedAt = failedAt
+			}
+		default:
+			if !strings.HasPrefix(key, deadLetterHeaderPrefix) {
+				deadLetter.Headers[key] = value
+			}
+		}
+	}
+
+	if deadLetter.FailedAt.IsZero() {
+		deadLetter.FailedAt = msg.Timestamp.UTC()
+	}
+	deadLetter.TransactionID = transactionIDOf(msg.Value)
+
+	return deadLetter
+}
+
+// transactionIDOf returns the id of a Transaction.Created payload or the
+// transaction_id of a FraudSignals.Calculated payload, or an empty string when
+// the payload is neither.
+func transactionIDOf(payload []byte) string {
+	var msg struct {
+		ID            string `json:"id"`
+		TransactionID string `json:"transaction_id"`
+	}
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return ""
+	}
+	if msg.TransactionID != "" {
+		return msg.TransactionID
+	}
+	return msg.ID
+}
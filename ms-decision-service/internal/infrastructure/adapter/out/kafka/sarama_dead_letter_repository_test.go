@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rs/zerolog"
+)
+
+func TestToDeadLetter_RoundTrip(t *testing.T) {
+	original := &entity.DeadLetter{
+		SourceTopic:     "FraudSignals.Calculated",
+		SourcePartition: 4,
+		SourceOffset:    1234,
+		Key:             "tx-9",
+		Payload:         `{"transaction_id":"tx-9","fraud_score":80}`,
+		Headers:         map[string]string{"traceparent": "00-abc-def-01"},
+		Error:           "failed to publish decision result: broker unavailable",
+		Attempts:        3,
+		FailedAt:        time.Date(2025, 3, 4, 5, 6, 7, 890, time.UTC),
+	}
+
+	headers := toDeadLetterHeaders(original)
+	msg := &sarama.ConsumerMessage{
+		Partition: 1,
+		Offset:    17,
+		Key:       []byte(original.Key),
+		Value:     []byte(original.Payload),
+	}
+	for i := range headers {
+		msg.Headers = append(msg.Headers, &headers[i])
+	}
+
+	deadLetter := toDeadLetter(msg)
+
+	expected := *original
+	expected.ID = "1-17"
+	expected.Partition = 1
+	expected.Offset = 17
+	expected.TransactionID = "tx-9"
+	if !reflect.DeepEqual(deadLetter, expected) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", deadLetter, expected)
+	}
+}
+
+func TestToDeadLetter_FallsBackToMessageTimestamp(t *testing.T) {
+	timestamp := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	deadLetter := toDeadLetter(&sarama.ConsumerMessage{Timestamp: timestamp, Value: []byte("{}")})
+
+	if !deadLetter.FailedAt.Equal(timestamp) {
+		t.Errorf("expected failed_at %v, got %v", timestamp, deadLetter.FailedAt)
+	}
+	if len(deadLetter.Headers) != 0 {
+		t.Errorf("expected no headers, got %v", deadLetter.Headers)
+	}
+}
+
+func TestTransactionIDOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		expected string
+	}{
+		{"Transaction.Created", `{"id":"tx-1","amount":100}`, "tx-1"},
+		{"FraudSignals.Calculated", `{"transaction_id":"tx-2","fraud_score":80}`, "tx-2"},
+		{"malformed", `{"id":`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transactionIDOf([]byte(tt.payload)); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSaramaDeadLetterRepository_Redrive(t *testing.T) {
+	producer := &capturingSyncProducer{}
+	repo := NewSaramaDeadLetterRepository(nil, producer, zerolog.Nop())
+
+	err := repo.Redrive(context.Background(), &entity.DeadLetter{
+		ID:          "0-3",
+		SourceTopic: "Transaction.Created",
+		Key:         "tx-1",
+		Payload:     `{"id":"tx-1"}`,
+		Headers:     map[string]string{"traceparent": "00-abc-def-01"},
+		Error:       "failed to retrieve rules",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	msg := producer.lastMessage
+	if msg.Topic != "Transaction.Created" {
+		t.Errorf("expected topic Transaction.Created, got %s", msg.Topic)
+	}
+	value, _ := msg.Value.Encode()
+	if string(value) != `{"id":"tx-1"}` {
+		t.Errorf("expected original payload, got %s", value)
+	}
+	if len(msg.Headers) != 1 || string(msg.Headers[0].Key) != "traceparent" {
+		t.Errorf("expected only the original headers, got %v", msg.Headers)
+	}
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"sync"
+	"time"
+)
+
+// processedMessageKey identifies a processed message by transaction and stage.
+type processedMessageKey struct {
+	transactionID string
+	stage         entity.ProcessingStage
+}
+
+// processedMessage is a recorded result and the time it expires.
+type processedMessage struct {
+	result    entity.DecisionResult
+	expiresAt time.Time
+}
+
+// InMemoryProcessedMessageStore implements repository.ProcessedMessageStore in
+// process memory. Results expire after the TTL. Entries are local to the instance
+// and lost on restart, so it only deduplicates redeliveries to the same instance.
+type InMemoryProcessedMessageStore struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	messages map[processedMessageKey]processedMessage
+	saved    int
+}
+
+// NewInMemoryProcessedMessageStore creates an empty store keeping results for the given TTL.
+func NewInMemoryProcessedMessageStore(ttl time.Duration) *InMemoryProcessedMessageStore {
+	return &InMemoryProcessedMessageStore{
+		ttl:      ttl,
+		now:      time.Now,
+		messages: make(map[processedMessageKey]processedMessage),
+	}
+}
+
+// Find returns the unexpired result recorded for the transaction at the stage.
+func (s *InMemoryProcessedMessageStore) Find(
+	_ context.Context,
+	transactionID string,
+	stage entity.ProcessingStage,
+) (*entity.DecisionResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	message, ok := s.messages[processedMessageKey{transactionID: transactionID, stage: stage}]
+	if !ok || !s.now().Before(message.expiresAt) {
+		return nil, nil
+	}
+
+	result := message.result
+	return &result, nil
+}
+
+// Save records the result, replacing any result recorded for the transaction at the stage.
+func (s *InMemoryProcessedMessageStore) Save(
+	_ context.Context,
+	stage entity.ProcessingStage,
+	result *entity.DecisionResult,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.messages[processedMessageKey{transactionID: result.TransactionID, stage: stage}] = processedMessage{
+		result:    *result,
+		expiresAt: now.Add(s.ttl),
+	}
+
+	s.saved++
+	if s.saved%sweepEvery == 0 {
+		for key, message := range s.messages {
+			if !now.Before(message.expiresAt) {
+				delete(s.messages, key)
+			}
+		}
+	}
+
+	return nil
+}
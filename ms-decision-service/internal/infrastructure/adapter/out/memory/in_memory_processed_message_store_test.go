@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+	"time"
+)
+
+func TestInMemoryProcessedMessageStore_FindsSavedResultPerStage(t *testing.T) {
+	store := NewInMemoryProcessedMessageStore(time.Hour)
+	ctx := context.Background()
+
+	result := &entity.DecisionResult{TransactionID: "tx-1", Status: entity.FRAUDCHECK, RulesetVersion: 3}
+	if err := store.Save(ctx, entity.StageTransactionCreated, result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := store.Find(ctx, "tx-1", entity.StageTransactionCreated)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || *got != *result {
+		t.Errorf("expected %+v, got %+v", result, got)
+	}
+
+	if got, _ := store.Find(ctx, "tx-1", entity.StageFraudScoreCalculated); got != nil {
+		t.Errorf("expected no result for another stage, got %+v", got)
+	}
+	if got, _ := store.Find(ctx, "tx-2", entity.StageTransactionCreated); got != nil {
+		t.Errorf("expected no result for another transaction, got %+v", got)
+	}
+}
+
+func TestInMemoryProcessedMessageStore_ResultsExpire(t *testing.T) {
+	store := NewInMemoryProcessedMessageStore(time.Minute)
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_ = store.Save(ctx, entity.StageTransactionCreated, &entity.DecisionResult{TransactionID: "tx-1", Status: entity.APPROVED})
+
+	now = now.Add(59 * time.Second)
+	if got, _ := store.Find(ctx, "tx-1", entity.StageTransactionCreated); got == nil {
+		t.Fatal("expected the result before the TTL elapsed")
+	}
+
+	now = now.Add(time.Second)
+	if got, _ := store.Find(ctx, "tx-1", entity.StageTransactionCreated); got != nil {
+		t.Errorf("expected the result to expire, got %+v", got)
+	}
+}
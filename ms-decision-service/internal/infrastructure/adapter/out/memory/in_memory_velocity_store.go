@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"sort"
+	"sync"
+	"time"
+)
+
+// sweepEvery is the number of recorded events between sweeps of expired keys.
+const sweepEvery = 1000
+
+// velocitySeries holds the events of a single key, sorted by OccurredAt.
+type velocitySeries struct {
+	events []entity.VelocityEvent
+	seen   map[string]struct{}
+}
+
+// InMemoryVelocityStore implements repository.VelocityStore with per-key event
+// lists held in process memory. Events older than the retention period are
+// discarded, so windows longer than the retention are truncated. Counters are
+// local to the instance and lost on restart.
+type InMemoryVelocityStore struct {
+	retention time.Duration
+
+	mu       sync.Mutex
+	series   map[string]*velocitySeries
+	recorded int
+	latest   time.Time
+}
+
+// NewInMemoryVelocityStore creates an empty store keeping events for the given retention.
+func NewInMemoryVelocityStore(retention time.Duration) *InMemoryVelocityStore {
+	return &InMemoryVelocityStore{
+		retention: retention,
+		series:    make(map[string]*velocitySeries),
+	}
+}
+
+// Record adds the event to the key's series and returns its aggregates over each window.
+func (s *InMemoryVelocityStore) Record(
+	_ context.Context,
+	key string,
+	event entity.VelocityEvent,
+	windows []time.Duration,
+) ([]entity.VelocityAggregate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if event.OccurredAt.After(s.latest) {
+		s.latest = event.OccurredAt
+	}
+
+	series, ok := s.series[key]
+	if !ok {
+		series = &velocitySeries{seen: make(map[string]struct{})}
+		s.series[key] = series
+	}
+
+	if _, duplicate := series.seen[event.TransactionID]; !duplicate {
+		series.seen[event.TransactionID] = struct{}{}
+		i := sort.Search(len(series.events), func(i int) bool {
+			return series.events[i].OccurredAt.After(event.OccurredAt)
+		})
+		series.events = append(series.events, entity.VelocityEvent{})
+		copy(series.events[i+1:], series.events[i:])
+		series.events[i] = event
+	}
+
+	s.prune(series)
+
+	s.recorded++
+	if s.recorded%sweepEvery == 0 {
+		s.sweep()
+	}
+
+	aggregates := make([]entity.VelocityAggregate, len(windows))
+	for i, window := range windows {
+		from := event.OccurredAt.Add(-window)
+		for _, e := range series.events {
+			if e.OccurredAt.After(from) && !e.OccurredAt.After(event.OccurredAt) {
+				aggregates[i].Count++
+				aggregates[i].AmountSum += e.AmountInUSDCents
+			}
+		}
+	}
+
+	return aggregates, nil
+}
+
+// prune drops the events of a series that fall outside the retention period.
+func (s *InMemoryVelocityStore) prune(series *velocitySeries) {
+	cutoff := s.latest.Add(-s.retention)
+
+	expired := 0
+	for expired < len(series.events) && !series.events[expired].OccurredAt.After(cutoff) {
+		delete(series.seen, series.events[expired].TransactionID)
+		expired++
+	}
+	series.events = series.events[expired:]
+}
+
+// sweep prunes every series and removes the keys left without events.
+func (s *InMemoryVelocityStore) sweep() {
+	for key, series := range s.series {
+		s.prune(series)
+		if len(series.events) == 0 {
+			delete(s.series, key)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"ms-decision-service/internal/domain/entity"
+	"testing"
+	"time"
+)
+
+var testWindows = []time.Duration{time.Minute, time.Hour, 24 * time.Hour}
+
+func record(t *testing.T, s *InMemoryVelocityStore, key, txID string, amount int64, at time.Time) []entity.VelocityAggregate {
+	t.Helper()
+	aggregates, err := s.Record(context.Background(), key, entity.VelocityEvent{
+		TransactionID:    txID,
+		AmountInUSDCents: amount,
+		OccurredAt:       at,
+	}, testWindows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return aggregates
+}
+
+func TestInMemoryVelocityStore_AggregatesPerWindow(t *testing.T) {
+	s := NewInMemoryVelocityStore(24 * time.Hour)
+	base := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	record(t, s, "customer:c1", "tx-1", 100, base.Add(-2*time.Hour))
+	record(t, s, "customer:c1", "tx-2", 200, base.Add(-30*time.Minute))
+	record(t, s, "customer:c2", "tx-3", 999, base.Add(-10*time.Second))
+	got := record(t, s, "customer:c1", "tx-4", 300, base)
+
+	want := []entity.VelocityAggregate{
+		{Count: 1, AmountSum: 300},
+		{Count: 2, AmountSum: 500},
+		{Count: 3, AmountSum: 600},
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("window %s: got %+v, want %+v", testWindows[i], got[i], want[i])
+		}
+	}
+}
+
+func TestInMemoryVelocityStore_CountsRedeliveredTransactionOnce(t *testing.T) {
+	s := NewInMemoryVelocityStore(24 * time.Hour)
+	at := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	record(t, s, "ip:10.0.0.1", "tx-1", 100, at)
+	got := record(t, s, "ip:10.0.0.1", "tx-1", 100, at)
+
+	if got[0].Count != 1 || got[0].AmountSum != 100 {
+		t.Errorf("expected redelivery to be counted once, got %+v", got[0])
+	}
+}
+
+func TestInMemoryVelocityStore_LateEventsExcludeLaterOnes(t *testing.T) {
+	s := NewInMemoryVelocityStore(24 * time.Hour)
+	at := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	record(t, s, "customer:c1", "tx-2", 200, at)
+	got := record(t, s, "customer:c1", "tx-1", 100, at.Add(-5*time.Minute))
+
+	if got[1].Count != 1 || got[1].AmountSum != 100 {
+		t.Errorf("expected window to end at the late event, got %+v", got[1])
+	}
+}
+
+func TestInMemoryVelocityStore_DropsEventsOutsideRetention(t *testing.T) {
+	s := NewInMemoryVelocityStore(time.Hour)
+	at := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	record(t, s, "customer:c1", "tx-1", 100, at.Add(-3*time.Hour))
+	got := record(t, s, "customer:c1", "tx-2", 200, at)
+
+	if got[2].Count != 1 {
+		t.Errorf("expected events outside retention to be dropped, got %+v", got[2])
+	}
+
+	s.sweep()
+	record(t, s, "customer:c2", "tx-3", 300, at.Add(2*time.Hour))
+	s.sweep()
+	if _, ok := s.series["customer:c1"]; ok {
+		t.Error("expected expired key to be swept")
+	}
+}
@@ -0,0 +1,77 @@
+package telemetry
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RuleCacheRequests counts reads served by the rule cache, labelled by outcome:
+// "hit" (fresh snapshot), "miss" (snapshot reloaded first) or "stale" (reload
+// failed and the previous snapshot was served).
+var RuleCacheRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rule_cache_requests_total",
+		Help: "Rule cache reads by outcome",
+	},
+	[]string{"result"},
+)
+
+// RuleCacheRefreshes counts rule cache reloads from DynamoDB, labelled by result.
+var RuleCacheRefreshes = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rule_cache_refreshes_total",
+		Help: "Rule cache reloads by result",
+	},
+	[]string{"result"},
+)
+
+// RuleCacheLastRefresh records the Unix time of the last successful rule cache reload.
+// The cache age is time() - rule_cache_last_refresh_timestamp_seconds.
+var RuleCacheLastRefresh = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "rule_cache_last_refresh_timestamp_seconds",
+		Help: "Unix time of the last successful rule cache reload",
+	},
+)
+
+// RuleCacheRulesetVersion records the ruleset version held by the rule cache.
+var RuleCacheRulesetVersion = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "rule_cache_ruleset_version",
+		Help: "Ruleset version of the cached rules",
+	},
+)
+
+// KafkaConsumerRetries counts retries of consumed messages whose processing failed
+// with a retryable error, labelled by topic.
+var KafkaConsumerRetries = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "kafka_consumer_retries_total",
+		Help: "Retries of consumed Kafka messages by topic",
+	},
+	[]string{"topic"},
+)
+
+// KafkaDeadLetters counts consumed messages published to a dead-letter topic,
+// labelled by source topic and reason: "retries_exhausted" or "non_retryable".
+var KafkaDeadLetters = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "kafka_dead_letters_total",
+		Help: "Consumed Kafka messages dead-lettered by topic and reason",
+	},
+	[]string{"topic", "reason"},
+)
+
+// KafkaDeadLetterPublishFailures counts failed attempts to publish to a dead-letter
+// topic, labelled by source topic. The message is not committed until it succeeds.
+var KafkaDeadLetterPublishFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "kafka_dead_letter_publish_failures_total",
+		Help: "Failed dead-letter publish attempts by source topic",
+	},
+	[]string{"topic"},
+)
+
+func init() {
+	prometheus.MustRegister(RuleCacheRequests, RuleCacheRefreshes, RuleCacheLastRefresh, RuleCacheRulesetVersion)
+	prometheus.MustRegister(KafkaConsumerRetries, KafkaDeadLetters, KafkaDeadLetterPublishFailures)
+}
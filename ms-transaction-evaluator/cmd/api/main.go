@@ -2,13 +2,16 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"io"
 	_ "ms-transaction-evaluator/docs"
+	"ms-transaction-evaluator/internal/domain/entity"
 	"ms-transaction-evaluator/internal/domain/usecase"
 	httpAdapter "ms-transaction-evaluator/internal/infrastructure/adapter/in/http"
 	kafkaIn "ms-transaction-evaluator/internal/infrastructure/adapter/in/kafka"
 	dynamodbAdapter "ms-transaction-evaluator/internal/infrastructure/adapter/out/aws/dynamodb"
 	kafkaAdapter "ms-transaction-evaluator/internal/infrastructure/adapter/out/kafka"
+	webhookAdapter "ms-transaction-evaluator/internal/infrastructure/adapter/out/webhook"
 	"ms-transaction-evaluator/internal/infrastructure/telemetry"
 	"net/http"
 	"os"
@@ -101,9 +104,57 @@ func main() {
 		dynamoClient = dynamodb.NewFromConfig(cfg)
 	}
 
-	transactionRepo := dynamodbAdapter.NewDynamoDBTransactionRepository(dynamoClient, tableName, logger)
+	// Signs the next_cursor of GET /transactions; a random one only works on this instance until it restarts
+	cursorSecret := []byte(os.Getenv("TRANSACTION_CURSOR_SECRET"))
+	if len(cursorSecret) == 0 {
+		cursorSecret = make([]byte, 32)
+		if _, err := rand.Read(cursorSecret); err != nil {
+			logger.Fatal().Err(err).Msg("unable to generate transaction cursor secret")
+		}
+		logger.Warn().Msg("TRANSACTION_CURSOR_SECRET is not set, using a random secret")
+	}
+
+	transactionRepo := dynamodbAdapter.NewDynamoDBTransactionRepository(dynamoClient, tableName, cursorSecret, logger)
 	logger.Info().Str("table", tableName).Msg("DynamoDB repository initialized")
 
+	// "backfill-list-buckets" sets the list_bucket of transactions saved before it was added and exits
+	if len(os.Args) > 1 && os.Args[1] == "backfill-list-buckets" {
+		if _, err := transactionRepo.BackfillListBuckets(context.Background()); err != nil {
+			logger.Fatal().Err(err).Msg("failed to backfill transaction list buckets")
+		}
+		return
+	}
+
+	// Pre-aggregated stats, updated as transactions are created and decided
+	transactionStatsTable := getEnvOrDefault("DYNAMO_DB_TRANSACTION_STATS_TABLE", "ddb-transaction-stats")
+	transactionStatsRepo := dynamodbAdapter.NewDynamoDBTransactionStatsRepository(dynamoClient, transactionStatsTable, logger)
+	transactionStatsRecorder := usecase.NewTransactionStatsRecorder(transactionStatsRepo, logger)
+	logger.Info().Str("table", transactionStatsTable).Msg("transaction stats repository initialized")
+
+	// "rebuild-stats" recomputes the stats from the transactions table and exits
+	if len(os.Args) > 1 && os.Args[1] == "rebuild-stats" {
+		rebuildUseCase := usecase.NewRebuildTransactionStatsUseCase(transactionRepo, transactionStatsRepo, logger)
+		if _, err := rebuildUseCase.Execute(context.Background()); err != nil {
+			logger.Fatal().Err(err).Msg("failed to rebuild transaction stats")
+		}
+		return
+	}
+
+	// Transactional outbox: transactions are saved together with their Transaction.Created event
+	outboxTable := getEnvOrDefault("DYNAMO_DB_OUTBOX_TABLE", "ddb-transaction-outbox")
+	idempotencyKeysTable := getEnvOrDefault("DYNAMO_DB_IDEMPOTENCY_KEYS_TABLE", "ddb-idempotency-keys")
+	outboxRepo := dynamodbAdapter.NewDynamoDBOutboxRepository(dynamoClient, outboxTable, tableName, idempotencyKeysTable, logger)
+	idempotencyKeyRepo := dynamodbAdapter.NewDynamoDBIdempotencyKeyRepository(dynamoClient, idempotencyKeysTable, logger)
+	logger.Info().Str("table", outboxTable).Msg("outbox repository initialized")
+
+	// Webhook endpoints notified of final decisions, and their delivery log
+	webhookEndpointsTable := getEnvOrDefault("DYNAMO_DB_WEBHOOK_ENDPOINTS_TABLE", "ddb-webhook-endpoints")
+	webhookDeliveriesTable := getEnvOrDefault("DYNAMO_DB_WEBHOOK_DELIVERIES_TABLE", "ddb-webhook-deliveries")
+	webhookEndpointRepo := dynamodbAdapter.NewDynamoDBWebhookEndpointRepository(dynamoClient, webhookEndpointsTable, logger)
+	webhookDeliveryRepo := dynamodbAdapter.NewDynamoDBWebhookDeliveryRepository(dynamoClient, webhookDeliveriesTable, logger)
+	webhookSender := webhookAdapter.NewHTTPWebhookSender(getEnvAsDuration("WEBHOOK_TIMEOUT", 5*time.Second))
+	logger.Info().Str("endpoints_table", webhookEndpointsTable).Str("deliveries_table", webhookDeliveriesTable).Msg("webhook repositories initialized")
+
 	// Initialize Kafka producer
 	brokerAddress := getEnvOrDefault("KAFKA_BROKER_ADDRESS", "localhost:9092")
 	transactionTopic := getEnvOrDefault("KAFKA_TRANSACTION_CREATED_TOPIC", "Transaction.Created")
@@ -121,13 +172,81 @@ func main() {
 
 	eventPublisher := kafkaAdapter.NewSaramaTransactionPublisher(producer, transactionTopic, logger)
 
+	// Dead-letter topic for Decision.Calculated messages that cannot be processed
+	decisionTopic := getEnvOrDefault("KAFKA_DECISION_CALCULATED_TOPIC", "Decision.Calculated")
+	decisionDLQTopic := getEnvOrDefault("KAFKA_DECISION_CALCULATED_DLQ_TOPIC", "Decision.Calculated.DLQ")
+	decisionDeadLetters := kafkaAdapter.NewSaramaDeadLetterPublisher(producer, decisionDLQTopic, logger)
+
+	// The dead-letter topic is read with a Kafka client for inspection and redrive
+	dlqClientConfig := sarama.NewConfig()
+	dlqClientConfig.Consumer.Return.Errors = true
+	dlqClient, err := sarama.NewClient([]string{brokerAddress}, dlqClientConfig)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create Kafka client for dead-letter topics")
+	}
+	defer dlqClient.Close()
+	deadLetterRepo := kafkaAdapter.NewSaramaDeadLetterRepository(dlqClient, producer, decisionDLQTopic, logger)
+	deadLetterQueue := entity.DeadLetterQueue{Topic: decisionDLQTopic, SourceTopic: decisionTopic}
+	logger.Info().Str("topic", decisionDLQTopic).Msg("dead-letter topic configured")
+
 	// Initialize use cases
 	validateUseCase := usecase.NewValidateCreateTransactionPayloadUseCase()
-	saveUseCase := usecase.NewSaveTransactionUseCase(transactionRepo, eventPublisher)
-	updateStatusUseCase := usecase.NewUpdateTransactionStatusUseCase(transactionRepo)
+	transactionStream := usecase.NewTransactionStream(
+		transactionRepo,
+		getEnvAsInt("TRANSACTION_STREAM_HISTORY", usecase.DefaultTransactionStreamHistory),
+		getEnvAsInt("TRANSACTION_STREAM_BUFFER", usecase.DefaultTransactionStreamBuffer),
+		logger,
+	)
+	saveUseCase := usecase.NewSaveTransactionUseCase(outboxRepo, transactionRepo, idempotencyKeyRepo, getEnvAsDuration("IDEMPOTENCY_KEY_TTL", usecase.DefaultIdempotencyKeyTTL), transactionStream, transactionStatsRecorder)
+	relayOutboxUseCase := usecase.NewRelayOutboxUseCase(outboxRepo, eventPublisher, getEnvAsInt("OUTBOX_BATCH_SIZE", usecase.DefaultOutboxBatchSize), getEnvAsInt("OUTBOX_MAX_ATTEMPTS", usecase.DefaultOutboxMaxAttempts), logger)
+	enqueueWebhookDeliveriesUseCase := usecase.NewEnqueueWebhookDeliveriesUseCase(webhookEndpointRepo, webhookDeliveryRepo, logger)
+	decisionWaiters := usecase.NewDecisionWaiters()
+	transactionFinalizer := usecase.NewTransactionFinalizer(enqueueWebhookDeliveriesUseCase, transactionStatsRecorder, decisionWaiters, transactionStream)
+	updateStatusUseCase := usecase.NewUpdateTransactionStatusUseCase(transactionRepo, transactionFinalizer)
+	waitForDecisionUseCase := usecase.NewWaitForDecisionUseCase(decisionWaiters, transactionRepo, logger)
 	listTransactionsUseCase := usecase.NewListTransactionsUseCase(transactionRepo)
 	getTransactionUseCase := usecase.NewGetTransactionUseCase(transactionRepo)
-	getTransactionStatsUseCase := usecase.NewGetTransactionStatsUseCase(transactionRepo)
+	latencyThresholds := entity.LatencyTierThresholds{
+		LowMs:    float64(getEnvAsInt("LATENCY_TIER_LOW_MS", entity.LatencyLowThresholdMs)),
+		MediumMs: float64(getEnvAsInt("LATENCY_TIER_MEDIUM_MS", entity.LatencyMediumThresholdMs)),
+	}
+	if !latencyThresholds.Valid() {
+		logger.Fatal().Msg("invalid LATENCY_TIER_LOW_MS or LATENCY_TIER_MEDIUM_MS: both must be positive and LOW below MEDIUM")
+	}
+	getTransactionStatsUseCase := usecase.NewGetTransactionStatsUseCase(transactionStatsRepo, latencyThresholds)
+	getTransactionTimeseriesUseCase := usecase.NewGetTransactionTimeseriesUseCase(transactionStatsRepo)
+	getDeadLetterQueuesUseCase := usecase.NewGetDeadLetterQueuesUseCase(deadLetterRepo, deadLetterQueue)
+	listDeadLettersUseCase := usecase.NewListDeadLettersUseCase(deadLetterRepo, deadLetterQueue)
+	getDeadLetterUseCase := usecase.NewGetDeadLetterUseCase(deadLetterRepo, deadLetterQueue)
+	redriveDeadLettersUseCase := usecase.NewRedriveDeadLettersUseCase(deadLetterRepo, deadLetterQueue)
+	registerWebhookEndpointUseCase := usecase.NewRegisterWebhookEndpointUseCase(webhookEndpointRepo)
+	listWebhookEndpointsUseCase := usecase.NewListWebhookEndpointsUseCase(webhookEndpointRepo)
+	deleteWebhookEndpointUseCase := usecase.NewDeleteWebhookEndpointUseCase(webhookEndpointRepo)
+	listWebhookDeliveriesUseCase := usecase.NewListWebhookDeliveriesUseCase(webhookDeliveryRepo)
+	getWebhookDeliveryUseCase := usecase.NewGetWebhookDeliveryUseCase(webhookDeliveryRepo)
+	replayWebhookDeliveryUseCase := usecase.NewReplayWebhookDeliveryUseCase(webhookDeliveryRepo)
+	deliverWebhooksUseCase := usecase.NewDeliverWebhooksUseCase(
+		webhookEndpointRepo,
+		webhookDeliveryRepo,
+		webhookSender,
+		getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", usecase.DefaultWebhookMaxAttempts),
+		getEnvAsInt("WEBHOOK_BATCH_SIZE", usecase.DefaultWebhookBatchSize),
+		logger,
+	)
+
+	// Stuck-transaction sweeper: PENDING transactions without a decision past the SLA
+	// are republished, then given the fallback status
+	stuckFallback, err := usecase.ParseFallbackStatus(getEnvOrDefault("STUCK_TRANSACTION_FALLBACK", string(entity.APPROVED)))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid STUCK_TRANSACTION_FALLBACK")
+	}
+	stuckPolicy := usecase.StuckTransactionPolicy{
+		SLA:            getEnvAsDuration("STUCK_TRANSACTION_SLA", usecase.DefaultStuckTransactionSLA),
+		MaxRepublishes: getEnvAsInt("STUCK_TRANSACTION_MAX_REPUBLISHES", usecase.DefaultStuckTransactionMaxRepublishes),
+		Fallback:       stuckFallback,
+		BatchSize:      getEnvAsInt("STUCK_TRANSACTION_BATCH_SIZE", usecase.DefaultStuckTransactionBatchSize),
+	}
+	sweepStuckTransactionsUseCase := usecase.NewSweepStuckTransactionsUseCase(transactionRepo, eventPublisher, transactionFinalizer, stuckPolicy, logger)
 
 	e := echo.New()
 
@@ -153,14 +272,25 @@ func main() {
 	}))
 
 	// Initialize controllers
-	transactionController := httpAdapter.NewTransactionController(validateUseCase, saveUseCase, logger)
-	transactionStatsController := httpAdapter.NewTransactionStatsController(getTransactionStatsUseCase, logger)
+	transactionController := httpAdapter.NewTransactionController(validateUseCase, saveUseCase, waitForDecisionUseCase, logger)
+	transactionStatsController := httpAdapter.NewTransactionStatsController(getTransactionStatsUseCase, getTransactionTimeseriesUseCase, logger)
 	transactionQueryController := httpAdapter.NewTransactionQueryController(listTransactionsUseCase, getTransactionUseCase, logger)
+	transactionStreamController := httpAdapter.NewTransactionStreamController(transactionStream, logger)
+	deadLetterController := httpAdapter.NewDeadLetterController(
+		getDeadLetterQueuesUseCase, listDeadLettersUseCase, getDeadLetterUseCase, redriveDeadLettersUseCase, logger,
+	)
+	webhookController := httpAdapter.NewWebhookController(
+		registerWebhookEndpointUseCase, listWebhookEndpointsUseCase, deleteWebhookEndpointUseCase,
+		listWebhookDeliveriesUseCase, getWebhookDeliveryUseCase, replayWebhookDeliveryUseCase, logger,
+	)
 
-	// Register routes — stats BEFORE query so /transactions/stats doesn't match /transactions/:id
+	// Register routes — stats and stream BEFORE query so they don't match /transactions/:id
 	transactionController.RegisterRoutes(e)
 	transactionStatsController.RegisterRoutes(e)
+	transactionStreamController.RegisterRoutes(e)
 	transactionQueryController.RegisterRoutes(e)
+	deadLetterController.RegisterRoutes(e)
+	webhookController.RegisterRoutes(e)
 
 	// Swagger UI
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
@@ -169,7 +299,6 @@ func main() {
 	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 
 	// Decision.Calculated consumer
-	decisionTopic := getEnvOrDefault("KAFKA_DECISION_CALCULATED_TOPIC", "Decision.Calculated")
 	decisionConsumerGroup := "transaction-evaluator-decision-group"
 
 	saramaConfig := sarama.NewConfig()
@@ -188,7 +317,7 @@ func main() {
 		Str("topic", decisionTopic).
 		Msg("decision consumer group connected")
 
-	decisionConsumer := kafkaIn.NewDecisionConsumer(updateStatusUseCase, logger, getEnvAsInt("DECISION_MIN_DELAY_MS", 0), getEnvAsInt("DECISION_MAX_DELAY_MS", 0))
+	decisionConsumer := kafkaIn.NewDecisionConsumer(updateStatusUseCase, decisionDeadLetters, logger, getEnvAsInt("DECISION_MIN_DELAY_MS", 0), getEnvAsInt("DECISION_MAX_DELAY_MS", 0))
 	wrappedConsumer := otelsarama.WrapConsumerGroupHandler(decisionConsumer)
 
 	// Graceful shutdown
@@ -204,6 +333,26 @@ func main() {
 		cancel()
 	}()
 
+	// Start outbox relayer in background
+	outboxPollInterval := getEnvAsDuration("OUTBOX_POLL_INTERVAL", 500*time.Millisecond)
+	go relayOutboxUseCase.Run(ctx, outboxPollInterval)
+	logger.Info().Dur("interval", outboxPollInterval).Msg("outbox relayer started")
+
+	// Start stuck-transaction sweeper in background
+	stuckSweepInterval := getEnvAsDuration("STUCK_TRANSACTION_SWEEP_INTERVAL", time.Minute)
+	go sweepStuckTransactionsUseCase.Run(ctx, stuckSweepInterval)
+	logger.Info().
+		Dur("interval", stuckSweepInterval).
+		Dur("sla", stuckPolicy.SLA).
+		Int("max_republishes", stuckPolicy.MaxRepublishes).
+		Str("fallback", string(stuckPolicy.Fallback)).
+		Msg("stuck transaction sweeper started")
+
+	// Start webhook deliverer in background
+	webhookPollInterval := getEnvAsDuration("WEBHOOK_POLL_INTERVAL", time.Second)
+	go deliverWebhooksUseCase.Run(ctx, webhookPollInterval)
+	logger.Info().Dur("interval", webhookPollInterval).Msg("webhook deliverer started")
+
 	// Start decision consumer in background
 	go func() {
 		for {
@@ -239,3 +388,12 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultValue
+}
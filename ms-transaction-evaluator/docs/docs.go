@@ -49,11 +49,35 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.EvaluateTransactionRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key identifying the payment across retries; defaults to external_id",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wait for the decision before responding",
+                        "name": "wait",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "How long to wait for the decision in milliseconds (default 5000, max 30000)",
+                        "name": "timeout_ms",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Transaction validation successful",
+                        "description": "Transaction saved, or decided when waiting",
+                        "schema": {
+                            "$ref": "#/definitions/http.SuccessResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Transaction saved, decision still pending after the wait",
                         "schema": {
                             "$ref": "#/definitions/http.SuccessResponse"
                         }
@@ -63,6 +87,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/http.ErrorResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Idempotency key reused with a different payload",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
                     }
                 }
             }
@@ -125,6 +155,14 @@ const docTemplate = `{
                 "customer": {
                     "$ref": "#/definitions/entity.CustomerInfo"
                 },
+                "external_id": {
+                    "type": "string",
+                    "example": "order-1234"
+                },
+                "merchant_id": {
+                    "type": "string",
+                    "example": "merchant_42"
+                },
                 "payment_method": {
                     "allOf": [
                         {
@@ -0,0 +1,7 @@
+package entity
+
+import "errors"
+
+// ErrCursorMalformed is returned by repositories for a pagination cursor they did
+// not issue.
+var ErrCursorMalformed = errors.New("malformed cursor")
@@ -1,7 +1,9 @@
 package entity
 
 // DecisionCalculatedMessage represents the payload consumed from the Decision.Calculated Kafka topic.
+// DecisionPath is empty for Decision Services that do not report it.
 type DecisionCalculatedMessage struct {
 	TransactionID string `json:"transaction_id"`
 	Status        string `json:"status"`
+	DecisionPath  string `json:"decision_path,omitempty"`
 }
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrIdempotencyKeyExists is returned when a transaction is saved with an
+// idempotency key that is already recorded.
+var ErrIdempotencyKeyExists = errors.New("idempotency key already exists")
+
+// IdempotencyKey records the transaction created for a client-supplied key, with
+// the fingerprint of the request that created it. Transaction is the transaction
+// as it was returned to the client, so a retry gets the same response.
+type IdempotencyKey struct {
+	Key         string
+	Fingerprint string
+	Transaction TransactionEntity
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// Key returns the idempotency key of the request: the Idempotency-Key header,
+// or else the external_id. It is empty when the client sent neither.
+func (r *EvaluateTransactionRequest) Key() string {
+	if r.IdempotencyKey != "" {
+		return r.IdempotencyKey
+	}
+	return r.ExternalID
+}
+
+// Fingerprint returns the SHA-256 of the request payload. Two requests with the
+// same fingerprint describe the same payment.
+func (r *EvaluateTransactionRequest) Fingerprint() string {
+	payload, _ := json.Marshal(r)
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
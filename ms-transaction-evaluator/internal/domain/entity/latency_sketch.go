@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"maps"
+	"math"
+	"slices"
+)
+
+// LatencySketchRelativeAccuracy is the relative error of the quantiles estimated
+// by a LatencySketch: a reported latency is within 2% of an actual one.
+const LatencySketchRelativeAccuracy = 0.02
+
+// MaxSketchedLatencyMs is the largest latency a LatencySketch tells apart. Longer
+// latencies are counted in its last bucket, which bounds the number of buckets to
+// MaxLatencySketchIndex+1.
+const MaxSketchedLatencyMs = 24 * 60 * 60 * 1000
+
+var (
+	latencySketchGamma    = (1 + LatencySketchRelativeAccuracy) / (1 - LatencySketchRelativeAccuracy)
+	latencySketchLogGamma = math.Log(latencySketchGamma)
+
+	// MaxLatencySketchIndex is the index of the last bucket of a LatencySketch.
+	MaxLatencySketchIndex = LatencySketchIndex(MaxSketchedLatencyMs)
+)
+
+// LatencySketch is a mergeable quantile sketch of finalization latencies, in the
+// manner of DDSketch: it counts latencies in buckets whose bounds grow
+// geometrically, so that any latency is within LatencySketchRelativeAccuracy of
+// the value of its bucket. It maps a bucket index to its count and only holds
+// the buckets that are not empty.
+type LatencySketch map[int]int
+
+// LatencySketchIndex returns the index of the bucket holding latencyMs. Bucket i
+// holds the latencies in (gamma^(i-1), gamma^i]; latencies under 1 ms count as
+// 1 ms.
+func LatencySketchIndex(latencyMs int64) int {
+	latencyMs = min(max(latencyMs, 1), MaxSketchedLatencyMs)
+	return int(math.Ceil(math.Log(float64(latencyMs)) / latencySketchLogGamma))
+}
+
+// latencySketchValue returns the value reported for the latencies of bucket i,
+// whose relative distance to both bounds is LatencySketchRelativeAccuracy.
+func latencySketchValue(i int) float64 {
+	return 2 * math.Pow(latencySketchGamma, float64(i)) / (latencySketchGamma + 1)
+}
+
+// Count returns the number of latencies in the sketch.
+func (s LatencySketch) Count() int {
+	count := 0
+	for _, n := range s {
+		count += n
+	}
+	return count
+}
+
+// Quantile estimates the q-quantile (0 to 1) of the latencies, in milliseconds.
+// It is 0 for an empty sketch.
+func (s LatencySketch) Quantile(q float64) float64 {
+	count := s.Count()
+	if count <= 0 {
+		return 0
+	}
+
+	indexes := slices.Sorted(maps.Keys(s))
+	rank := q * float64(count-1)
+	cumulative := 0
+	for _, i := range indexes {
+		cumulative += s[i]
+		if float64(cumulative) > rank {
+			return latencySketchValue(i)
+		}
+	}
+	return latencySketchValue(indexes[len(indexes)-1])
+}
+
+// CountAtMost estimates how many latencies are at most latencyMs. Latencies
+// within LatencySketchRelativeAccuracy of latencyMs may be counted on either side.
+func (s LatencySketch) CountAtMost(latencyMs float64) int {
+	count := 0
+	for i, n := range s {
+		if latencySketchValue(i) <= latencyMs {
+			count += n
+		}
+	}
+	return count
+}
+
+// add adds sign times o to s, which must not be nil, and drops the buckets left
+// empty.
+func (s LatencySketch) add(o LatencySketch, sign int) {
+	for i, n := range o {
+		s[i] += sign * n
+		if s[i] == 0 {
+			delete(s, i)
+		}
+	}
+}
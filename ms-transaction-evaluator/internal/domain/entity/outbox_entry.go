@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+type OutboxStatus string
+
+const (
+	OutboxPending OutboxStatus = "PENDING"
+	OutboxSent    OutboxStatus = "SENT"
+	// OutboxFailed marks an entry the relayer gave up on after too many failed
+	// publish attempts.
+	OutboxFailed OutboxStatus = "FAILED"
+)
+
+// OutboxEntry is a Transaction.Created event waiting to be published. It is written
+// in the same DynamoDB transaction as the transaction it announces, so a saved
+// transaction always has its event. Attempts counts the failed publish attempts and
+// NextAttemptAt is the earliest time the relayer tries again; while a relayer has
+// claimed the entry, it is the end of that claim.
+type OutboxEntry struct {
+	ID            string            `json:"id"`
+	TransactionID string            `json:"transaction_id"`
+	Transaction   TransactionEntity `json:"transaction"`
+	Status        OutboxStatus      `json:"status"`
+	Attempts      int               `json:"attempts"`
+	LastError     string            `json:"last_error,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	NextAttemptAt time.Time         `json:"next_attempt_at"`
+	SentAt        *time.Time        `json:"sent_at,omitempty"`
+}
+
+// NewTransactionCreatedEntry returns the pending entry announcing the transaction.
+func NewTransactionCreatedEntry(id string, transaction *TransactionEntity) *OutboxEntry {
+	return &OutboxEntry{
+		ID:            id,
+		TransactionID: transaction.ID,
+		Transaction:   *transaction,
+		Status:        OutboxPending,
+		CreatedAt:     transaction.CreatedAt,
+		NextAttemptAt: transaction.CreatedAt,
+	}
+}
+
+// Due reports whether the relayer may publish the entry at now.
+func (e *OutboxEntry) Due(now time.Time) bool {
+	return e.Status == OutboxPending && !now.Before(e.NextAttemptAt)
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// StuckTransaction is a PENDING transaction that has not received a decision
+// within the SLA. RepublishAttempts counts the Transaction.Created events the
+// sweeper has published again for it, the last one at LastRepublishedAt.
+type StuckTransaction struct {
+	Transaction       TransactionEntity
+	RepublishAttempts int
+	LastRepublishedAt *time.Time
+}
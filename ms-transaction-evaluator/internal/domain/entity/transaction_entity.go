@@ -7,6 +7,14 @@ type EvaluateTransactionRequest struct {
 	Currency      Currency      `json:"currency" example:"USD"`
 	PaymentMethod PaymentMethod `json:"payment_method" example:"CARD"`
 	CustomerInfo  CustomerInfo  `json:"customer"`
+	// ExternalID is the client's own identifier of the payment. It is used as the
+	// idempotency key when no Idempotency-Key header is sent.
+	ExternalID string `json:"external_id,omitempty" example:"order-1234"`
+	// MerchantID identifies the merchant whose webhook endpoints are notified of
+	// the decision.
+	MerchantID string `json:"merchant_id,omitempty" example:"merchant_42"`
+	// IdempotencyKey is set from the Idempotency-Key header.
+	IdempotencyKey string `json:"-"`
 }
 
 type CustomerInfo struct {
@@ -39,6 +47,23 @@ const (
 	PENDING  TransactionStatus = "PENDING"
 	APPROVED TransactionStatus = "APPROVED"
 	DECLINED TransactionStatus = "DECLINED"
+	// EXPIRED and NEEDS_REVIEW are only set by the stuck-transaction sweeper on
+	// transactions that never received a decision.
+	EXPIRED      TransactionStatus = "EXPIRED"
+	NEEDS_REVIEW TransactionStatus = "NEEDS_REVIEW"
+)
+
+// DecisionPath is how the Decision Service reached a transaction's decision. It
+// is empty until the decision, and for decisions of Decision Services that do
+// not report it.
+type DecisionPath string
+
+const (
+	// DecisionPathDirect is a decision made by the transaction rules alone.
+	DecisionPathDirect DecisionPath = "DIRECT"
+	// DecisionPathFraudCheck is a decision made by the fraud-score rules after the
+	// transaction rules asked for a FRAUD_CHECK.
+	DecisionPathFraudCheck DecisionPath = "FRAUD_CHECK"
 )
 
 type TransactionEntity struct {
@@ -51,7 +76,10 @@ type TransactionEntity struct {
 	CustomerEmail     string            `json:"customer_email"`
 	CustomerPhone     string            `json:"customer_phone"`
 	CustomerIPAddress string            `json:"customer_ip_address"`
+	ExternalID        string            `json:"external_id,omitempty"`
+	MerchantID        string            `json:"merchant_id,omitempty"`
 	Status            TransactionStatus `json:"status"`
+	DecisionPath      DecisionPath      `json:"decision_path,omitempty"`
 	CreatedAt         time.Time         `json:"created_at"`
 	UpdatedAt         time.Time         `json:"updated_at"`
 	FinalizedAt       *time.Time        `json:"finalized_at,omitempty"`
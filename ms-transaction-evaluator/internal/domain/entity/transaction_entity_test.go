@@ -2,6 +2,7 @@ package entity
 
 import (
 	"encoding/json"
+	"math"
 	"testing"
 	"time"
 )
@@ -494,3 +495,106 @@ func TestTransactionEntity_StatusField(t *testing.T) {
 		}
 	})
 }
+
+func TestLatencySketch(t *testing.T) {
+	t.Run("estimates quantiles within the relative accuracy", func(t *testing.T) {
+		sketch := LatencySketch{}
+		for ms := int64(1); ms <= 10_000; ms++ {
+			sketch[LatencySketchIndex(ms)]++
+		}
+
+		if sketch.Count() != 10_000 {
+			t.Fatalf("expected 10000 latencies, got %d", sketch.Count())
+		}
+		for _, q := range []float64{0, 0.5, 0.9, 0.95, 0.99, 1} {
+			want := 1 + q*9_999
+			if got := sketch.Quantile(q); math.Abs(got-want) > want*LatencySketchRelativeAccuracy+1e-9 {
+				t.Errorf("quantile %v: expected %f within 2%%, got %f", q, want, got)
+			}
+		}
+	})
+
+	t.Run("bounds the number of buckets", func(t *testing.T) {
+		if LatencySketchIndex(0) != LatencySketchIndex(1) {
+			t.Errorf("expected latencies under 1 ms to count as 1 ms")
+		}
+		if LatencySketchIndex(7*MaxSketchedLatencyMs) != MaxLatencySketchIndex {
+			t.Errorf("expected latencies over MaxSketchedLatencyMs in the last bucket")
+		}
+		if MaxLatencySketchIndex > 500 {
+			t.Errorf("expected at most 500 buckets, got %d", MaxLatencySketchIndex+1)
+		}
+	})
+
+	t.Run("counts latencies at most a threshold", func(t *testing.T) {
+		sketch := LatencySketch{}
+		for _, ms := range []int64{100, 1000, 1900, 2100, 4000, 9000} {
+			sketch[LatencySketchIndex(ms)]++
+		}
+
+		if got := sketch.CountAtMost(2000); got != 3 {
+			t.Errorf("expected 3 latencies at most 2000 ms, got %d", got)
+		}
+		if got := sketch.CountAtMost(5000); got != 5 {
+			t.Errorf("expected 5 latencies at most 5000 ms, got %d", got)
+		}
+	})
+
+	t.Run("is 0 when empty", func(t *testing.T) {
+		if q := (LatencySketch{}).Quantile(0.5); q != 0 {
+			t.Errorf("expected 0, got %f", q)
+		}
+	})
+}
+
+func TestTransactionCounters_Latency(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	newFinalized := func(status TransactionStatus, method PaymentMethod, latency time.Duration) *TransactionEntity {
+		finalizedAt := createdAt.Add(latency)
+		return &TransactionEntity{ID: "txn", Status: status, PaymentMethod: method, CreatedAt: createdAt, FinalizedAt: &finalizedAt}
+	}
+
+	counters := NewTransactionCounters()
+	counters.Add(CountTransaction(newFinalized(APPROVED, CARD, time.Second)))
+	counters.Add(CountTransaction(newFinalized(APPROVED, CRYPTO, 3*time.Second)))
+	declined := CountTransaction(newFinalized(DECLINED, CARD, 8*time.Second))
+	counters.Add(declined)
+
+	if counters.LatencyByStatus[APPROVED].Count() != 2 || counters.LatencyByPaymentMethod[CARD].Count() != 2 || counters.Latency().Count() != 3 {
+		t.Errorf("unexpected sketches: %v %v", counters.LatencyByStatus, counters.LatencyByPaymentMethod)
+	}
+
+	counters.Sub(declined)
+	if len(counters.LatencyByStatus[DECLINED]) != 0 || counters.Latency().Count() != 2 {
+		t.Errorf("expected the declined latency to be removed, got %v", counters.LatencyByStatus)
+	}
+
+	counters.Sub(CountTransaction(newFinalized(APPROVED, CARD, time.Second)))
+	counters.Sub(CountTransaction(newFinalized(APPROVED, CRYPTO, 3*time.Second)))
+	if !counters.IsZero() {
+		t.Errorf("expected zero counters, got %+v", counters)
+	}
+}
+
+func TestLatencyTierThresholds(t *testing.T) {
+	thresholds := LatencyTierThresholds{LowMs: 500, MediumMs: 1500}
+
+	tests := []struct {
+		latencyMs float64
+		want      LatencyTier
+	}{
+		{500, LatencyLow},
+		{501, LatencyMedium},
+		{1500, LatencyMedium},
+		{1501, LatencyHigh},
+	}
+	for _, tt := range tests {
+		if got := thresholds.Classify(tt.latencyMs); got != tt.want {
+			t.Errorf("latency %v: expected %s, got %s", tt.latencyMs, tt.want, got)
+		}
+	}
+
+	if !DefaultLatencyTierThresholds.Valid() || (LatencyTierThresholds{LowMs: 5000, MediumMs: 2000}).Valid() || (LatencyTierThresholds{MediumMs: 2000}).Valid() {
+		t.Error("expected only increasing positive thresholds to be valid")
+	}
+}
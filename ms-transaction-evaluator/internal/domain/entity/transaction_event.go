@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+type TransactionEventType string
+
+const (
+	// TransactionEventCreated is emitted when a transaction is saved PENDING.
+	TransactionEventCreated TransactionEventType = "transaction.created"
+	// TransactionEventFinalized is emitted when a decision makes a transaction
+	// APPROVED or DECLINED.
+	TransactionEventFinalized TransactionEventType = "transaction.finalized"
+)
+
+// TransactionEvent is a change of a transaction's status, as pushed to stream
+// subscribers. ID orders the events of one evaluator instance.
+type TransactionEvent struct {
+	ID          string               `json:"id"`
+	Type        TransactionEventType `json:"type"`
+	Transaction TransactionEntity    `json:"transaction"`
+	OccurredAt  time.Time            `json:"occurred_at"`
+}
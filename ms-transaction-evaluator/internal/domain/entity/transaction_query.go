@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+type SortOrder string
+
+const (
+	SortDescending SortOrder = "desc"
+	SortAscending  SortOrder = "asc"
+)
+
+// TransactionFilter selects transactions by its non-zero fields. The amount and
+// created_at bounds are inclusive.
+type TransactionFilter struct {
+	Status           TransactionStatus
+	PaymentMethod    PaymentMethod
+	Currency         Currency
+	CustomerID       string
+	MinAmountInCents *int64
+	MaxAmountInCents *int64
+	CreatedFrom      *time.Time
+	CreatedTo        *time.Time
+}
+
+// TransactionQuery requests a page of the transactions matching Filter, ordered
+// by created_at. Cursor is the next_cursor of the previous page of the same
+// query, or empty for the first page.
+type TransactionQuery struct {
+	Filter TransactionFilter
+	Order  SortOrder
+	Limit  int
+	Cursor string
+}
@@ -1,32 +1,73 @@
 package entity
 
+import "time"
+
 // LatencyTier classifies transaction finalization latency into buckets.
 type LatencyTier string
 
 const (
-	LatencyLow    LatencyTier = "LOW"    // ≤ 2000ms
-	LatencyMedium LatencyTier = "MEDIUM" // ≤ 5000ms
-	LatencyHigh   LatencyTier = "HIGH"   // > 5000ms
+	LatencyLow    LatencyTier = "LOW"    // ≤ the low threshold
+	LatencyMedium LatencyTier = "MEDIUM" // ≤ the medium threshold
+	LatencyHigh   LatencyTier = "HIGH"   // > the medium threshold
 )
 
-// Latency tier thresholds in milliseconds.
+// Default latency tier thresholds in milliseconds.
 const (
 	LatencyLowThresholdMs    = 2000
 	LatencyMediumThresholdMs = 5000
 )
 
-// ClassifyLatency returns the LatencyTier for a given latency in milliseconds.
-func ClassifyLatency(latencyMs float64) LatencyTier {
+// LatencyTierThresholds are the upper bounds, in milliseconds, of the LOW and
+// MEDIUM latency tiers.
+type LatencyTierThresholds struct {
+	LowMs    float64 `json:"low_ms"`
+	MediumMs float64 `json:"medium_ms"`
+}
+
+// DefaultLatencyTierThresholds are the thresholds used when none are configured.
+var DefaultLatencyTierThresholds = LatencyTierThresholds{
+	LowMs:    LatencyLowThresholdMs,
+	MediumMs: LatencyMediumThresholdMs,
+}
+
+// Valid reports whether both thresholds are positive and LOW ends before MEDIUM.
+func (t LatencyTierThresholds) Valid() bool {
+	return t.LowMs > 0 && t.LowMs < t.MediumMs
+}
+
+// Classify returns the LatencyTier for a given latency in milliseconds.
+func (t LatencyTierThresholds) Classify(latencyMs float64) LatencyTier {
 	switch {
-	case latencyMs <= LatencyLowThresholdMs:
+	case latencyMs <= t.LowMs:
 		return LatencyLow
-	case latencyMs <= LatencyMediumThresholdMs:
+	case latencyMs <= t.MediumMs:
 		return LatencyMedium
 	default:
 		return LatencyHigh
 	}
 }
 
+// LatencyPercentiles summarizes the finalization latency of a set of
+// transactions. The percentiles are estimates, in milliseconds.
+type LatencyPercentiles struct {
+	Count int     `json:"count"`
+	P50Ms float64 `json:"p50_ms"`
+	P90Ms float64 `json:"p90_ms"`
+	P95Ms float64 `json:"p95_ms"`
+	P99Ms float64 `json:"p99_ms"`
+}
+
+// NewLatencyPercentiles reads the percentiles of a sketch.
+func NewLatencyPercentiles(s LatencySketch) LatencyPercentiles {
+	return LatencyPercentiles{
+		Count: s.Count(),
+		P50Ms: s.Quantile(0.50),
+		P90Ms: s.Quantile(0.90),
+		P95Ms: s.Quantile(0.95),
+		P99Ms: s.Quantile(0.99),
+	}
+}
+
 // TransactionStats holds aggregated metrics across all transactions.
 type TransactionStats struct {
 	Today          int                   `json:"today"`
@@ -36,10 +77,279 @@ type TransactionStats struct {
 	Approved       int                   `json:"approved"`
 	Declined       int                   `json:"declined"`
 	Pending        int                   `json:"pending"`
+	Expired        int                   `json:"expired"`
+	NeedsReview    int                   `json:"needs_review"`
 	PaymentMethods map[PaymentMethod]int `json:"payment_methods"`
 	AvgLatencyMs   float64               `json:"avg_latency_ms"`
 	FinalizedCount int                   `json:"finalized_count"`
 	LatencyLow     int                   `json:"latency_low"`
 	LatencyMedium  int                   `json:"latency_medium"`
 	LatencyHigh    int                   `json:"latency_high"`
+	// LatencyThresholds are the thresholds the tiers were counted with.
+	LatencyThresholds LatencyTierThresholds `json:"latency_thresholds"`
+	// LatencyTierRelativeError bounds the error of the tiers, which are read from
+	// the latency sketches: a latency within this share of a threshold may be
+	// counted in the tier on either side of it.
+	LatencyTierRelativeError float64                                  `json:"latency_tier_relative_error"`
+	Latency                  LatencyPercentiles                       `json:"latency"`
+	LatencyByStatus          map[TransactionStatus]LatencyPercentiles `json:"latency_by_status"`
+	LatencyByPaymentMethod   map[PaymentMethod]LatencyPercentiles     `json:"latency_by_payment_method"`
+	// FraudCheckDecisions and DirectDecisions count the decided transactions by
+	// DecisionPath.
+	FraudCheckDecisions int `json:"fraud_check_decisions"`
+	DirectDecisions     int `json:"direct_decisions"`
+}
+
+// TransactionCounters are additive counts over a set of transactions: how many
+// were created, by current status, payment method and currency, their amount by
+// currency, how the decided ones were decided, and the finalization latency of
+// the finalized ones. Counters of disjoint sets add up to the counters of their
+// union, which lets them be maintained incrementally.
+type TransactionCounters struct {
+	Created        int
+	Statuses       map[TransactionStatus]int
+	PaymentMethods map[PaymentMethod]int
+	Currencies     map[Currency]int
+	AmountsInCents map[Currency]int64
+	DecisionPaths  map[DecisionPath]int
+	Finalized      int
+	LatencySumMs   int64
+	// LatencyByStatus and LatencyByPaymentMethod sketch the latencies of the
+	// finalized transactions by final status and by payment method.
+	LatencyByStatus        map[TransactionStatus]LatencySketch
+	LatencyByPaymentMethod map[PaymentMethod]LatencySketch
+	// AmountsByStatus, StatusesByPaymentMethod, AmountsByPaymentMethod,
+	// StatusesByCurrency and LatencyByCurrency break the counts down by a second
+	// dimension, so the transactions of one status, payment method or currency
+	// have the same metrics as all of them.
+	AmountsByStatus         map[TransactionStatus]map[Currency]int64
+	StatusesByPaymentMethod map[PaymentMethod]map[TransactionStatus]int
+	AmountsByPaymentMethod  map[PaymentMethod]map[Currency]int64
+	StatusesByCurrency      map[Currency]map[TransactionStatus]int
+	LatencyByCurrency       map[Currency]LatencySketch
+}
+
+// NewTransactionCounters returns counters with no transactions.
+func NewTransactionCounters() TransactionCounters {
+	return TransactionCounters{
+		Statuses:                make(map[TransactionStatus]int),
+		PaymentMethods:          make(map[PaymentMethod]int),
+		Currencies:              make(map[Currency]int),
+		AmountsInCents:          make(map[Currency]int64),
+		DecisionPaths:           make(map[DecisionPath]int),
+		LatencyByStatus:         make(map[TransactionStatus]LatencySketch),
+		LatencyByPaymentMethod:  make(map[PaymentMethod]LatencySketch),
+		AmountsByStatus:         make(map[TransactionStatus]map[Currency]int64),
+		StatusesByPaymentMethod: make(map[PaymentMethod]map[TransactionStatus]int),
+		AmountsByPaymentMethod:  make(map[PaymentMethod]map[Currency]int64),
+		StatusesByCurrency:      make(map[Currency]map[TransactionStatus]int),
+		LatencyByCurrency:       make(map[Currency]LatencySketch),
+	}
+}
+
+// CountTransaction returns the counters of the single transaction t.
+func CountTransaction(t *TransactionEntity) TransactionCounters {
+	c := NewTransactionCounters()
+	c.Created = 1
+	c.Statuses[t.Status] = 1
+	c.PaymentMethods[t.PaymentMethod] = 1
+	c.Currencies[t.Currency] = 1
+	c.AmountsInCents[t.Currency] = t.AmountInCents
+	c.AmountsByStatus[t.Status] = map[Currency]int64{t.Currency: t.AmountInCents}
+	c.StatusesByPaymentMethod[t.PaymentMethod] = map[TransactionStatus]int{t.Status: 1}
+	c.AmountsByPaymentMethod[t.PaymentMethod] = map[Currency]int64{t.Currency: t.AmountInCents}
+	c.StatusesByCurrency[t.Currency] = map[TransactionStatus]int{t.Status: 1}
+	if t.DecisionPath != "" {
+		c.DecisionPaths[t.DecisionPath] = 1
+	}
+	if t.FinalizedAt != nil && !t.FinalizedAt.IsZero() {
+		latencyMs := t.FinalizedAt.Sub(t.CreatedAt).Milliseconds()
+		c.Finalized = 1
+		c.LatencySumMs = latencyMs
+		c.LatencyByStatus[t.Status] = LatencySketch{LatencySketchIndex(latencyMs): 1}
+		c.LatencyByPaymentMethod[t.PaymentMethod] = LatencySketch{LatencySketchIndex(latencyMs): 1}
+		c.LatencyByCurrency[t.Currency] = LatencySketch{LatencySketchIndex(latencyMs): 1}
+	}
+	return c
+}
+
+// Add adds o to c.
+func (c *TransactionCounters) Add(o TransactionCounters) {
+	c.add(o, 1)
+}
+
+// Sub subtracts o from c.
+func (c *TransactionCounters) Sub(o TransactionCounters) {
+	c.add(o, -1)
+}
+
+func (c *TransactionCounters) add(o TransactionCounters, sign int) {
+	if c.Statuses == nil {
+		c.Statuses = make(map[TransactionStatus]int)
+	}
+	if c.PaymentMethods == nil {
+		c.PaymentMethods = make(map[PaymentMethod]int)
+	}
+	if c.Currencies == nil {
+		c.Currencies = make(map[Currency]int)
+	}
+	if c.AmountsInCents == nil {
+		c.AmountsInCents = make(map[Currency]int64)
+	}
+	if c.DecisionPaths == nil {
+		c.DecisionPaths = make(map[DecisionPath]int)
+	}
+	if c.LatencyByStatus == nil {
+		c.LatencyByStatus = make(map[TransactionStatus]LatencySketch)
+	}
+	if c.LatencyByPaymentMethod == nil {
+		c.LatencyByPaymentMethod = make(map[PaymentMethod]LatencySketch)
+	}
+	if c.AmountsByStatus == nil {
+		c.AmountsByStatus = make(map[TransactionStatus]map[Currency]int64)
+	}
+	if c.StatusesByPaymentMethod == nil {
+		c.StatusesByPaymentMethod = make(map[PaymentMethod]map[TransactionStatus]int)
+	}
+	if c.AmountsByPaymentMethod == nil {
+		c.AmountsByPaymentMethod = make(map[PaymentMethod]map[Currency]int64)
+	}
+	if c.StatusesByCurrency == nil {
+		c.StatusesByCurrency = make(map[Currency]map[TransactionStatus]int)
+	}
+	if c.LatencyByCurrency == nil {
+		c.LatencyByCurrency = make(map[Currency]LatencySketch)
+	}
+
+	c.Created += sign * o.Created
+	for status, n := range o.Statuses {
+		c.Statuses[status] += sign * n
+	}
+	for method, n := range o.PaymentMethods {
+		c.PaymentMethods[method] += sign * n
+	}
+	for currency, n := range o.Currencies {
+		c.Currencies[currency] += sign * n
+	}
+	for currency, amount := range o.AmountsInCents {
+		c.AmountsInCents[currency] += int64(sign) * amount
+	}
+	for path, n := range o.DecisionPaths {
+		c.DecisionPaths[path] += sign * n
+	}
+	c.Finalized += sign * o.Finalized
+	c.LatencySumMs += int64(sign) * o.LatencySumMs
+	for status, sketch := range o.LatencyByStatus {
+		if c.LatencyByStatus[status] == nil {
+			c.LatencyByStatus[status] = LatencySketch{}
+		}
+		c.LatencyByStatus[status].add(sketch, sign)
+	}
+	for method, sketch := range o.LatencyByPaymentMethod {
+		if c.LatencyByPaymentMethod[method] == nil {
+			c.LatencyByPaymentMethod[method] = LatencySketch{}
+		}
+		c.LatencyByPaymentMethod[method].add(sketch, sign)
+	}
+	addBreakdown(c.AmountsByStatus, o.AmountsByStatus, int64(sign))
+	addBreakdown(c.StatusesByPaymentMethod, o.StatusesByPaymentMethod, sign)
+	addBreakdown(c.AmountsByPaymentMethod, o.AmountsByPaymentMethod, int64(sign))
+	addBreakdown(c.StatusesByCurrency, o.StatusesByCurrency, sign)
+	for currency, sketch := range o.LatencyByCurrency {
+		if c.LatencyByCurrency[currency] == nil {
+			c.LatencyByCurrency[currency] = LatencySketch{}
+		}
+		c.LatencyByCurrency[currency].add(sketch, sign)
+	}
+}
+
+// addBreakdown adds sign times the counts of o to c, which must not be nil.
+func addBreakdown[K1, K2 comparable, V int | int64](c, o map[K1]map[K2]V, sign V) {
+	for k1, counts := range o {
+		if c[k1] == nil {
+			c[k1] = make(map[K2]V)
+		}
+		for k2, n := range counts {
+			c[k1][k2] += sign * n
+		}
+	}
+}
+
+// breakdownIsZero reports whether every count of b is zero.
+func breakdownIsZero[K1, K2 comparable, V int | int64](b map[K1]map[K2]V) bool {
+	for _, counts := range b {
+		for _, n := range counts {
+			if n != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsZero reports whether every counter is zero, as for the difference between
+// the counters of a transaction before and after a change that does not affect
+// them.
+func (c TransactionCounters) IsZero() bool {
+	if c.Created != 0 || c.Finalized != 0 || c.LatencySumMs != 0 {
+		return false
+	}
+	for _, n := range c.Statuses {
+		if n != 0 {
+			return false
+		}
+	}
+	for _, n := range c.PaymentMethods {
+		if n != 0 {
+			return false
+		}
+	}
+	for _, n := range c.Currencies {
+		if n != 0 {
+			return false
+		}
+	}
+	for _, amount := range c.AmountsInCents {
+		if amount != 0 {
+			return false
+		}
+	}
+	for _, n := range c.DecisionPaths {
+		if n != 0 {
+			return false
+		}
+	}
+	for _, sketch := range c.LatencyByStatus {
+		if len(sketch) != 0 {
+			return false
+		}
+	}
+	for _, sketch := range c.LatencyByPaymentMethod {
+		if len(sketch) != 0 {
+			return false
+		}
+	}
+	for _, sketch := range c.LatencyByCurrency {
+		if len(sketch) != 0 {
+			return false
+		}
+	}
+	return breakdownIsZero(c.AmountsByStatus) && breakdownIsZero(c.StatusesByPaymentMethod) &&
+		breakdownIsZero(c.AmountsByPaymentMethod) && breakdownIsZero(c.StatusesByCurrency)
+}
+
+// Latency returns the sketch of the latencies of every finalized transaction.
+func (c TransactionCounters) Latency() LatencySketch {
+	sketch := LatencySketch{}
+	for _, s := range c.LatencyByStatus {
+		sketch.add(s, 1)
+	}
+	return sketch
+}
+
+// HourlyTransactionCounters are the counters of the transactions created in the
+// UTC hour starting at Hour.
+type HourlyTransactionCounters struct {
+	Hour     time.Time
+	Counters TransactionCounters
 }
@@ -0,0 +1,68 @@
+package entity
+
+import "time"
+
+// TimeseriesInterval is the width of the buckets of a transaction time series.
+type TimeseriesInterval string
+
+const (
+	TimeseriesHour TimeseriesInterval = "hour"
+	TimeseriesDay  TimeseriesInterval = "day"
+)
+
+// Duration returns the width of a bucket.
+func (i TimeseriesInterval) Duration() time.Duration {
+	if i == TimeseriesDay {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
+
+// TimeseriesGroupBy is the dimension the counts of each bucket are broken down
+// by, if any.
+type TimeseriesGroupBy string
+
+const (
+	TimeseriesGroupByNone          TimeseriesGroupBy = ""
+	TimeseriesGroupByStatus        TimeseriesGroupBy = "status"
+	TimeseriesGroupByPaymentMethod TimeseriesGroupBy = "payment_method"
+	TimeseriesGroupByCurrency      TimeseriesGroupBy = "currency"
+)
+
+// TransactionTimeseries holds the metrics of the transactions created in each
+// UTC bucket from From (inclusive) to To (exclusive), oldest first. Empty
+// buckets are included.
+type TransactionTimeseries struct {
+	From     time.Time
+	To       time.Time
+	Interval TimeseriesInterval
+	GroupBy  TimeseriesGroupBy
+	Buckets  []TransactionTimeseriesBucket
+}
+
+// TransactionTimeseriesBucket holds the metrics of the transactions created in
+// the bucket starting at Start.
+type TransactionTimeseriesBucket struct {
+	Start time.Time
+	TransactionTimeseriesMetrics
+	// Groups holds the metrics of the transactions of each GroupBy value; it is nil
+	// without one.
+	Groups map[string]TransactionTimeseriesMetrics
+}
+
+// TransactionTimeseriesMetrics are the metrics of a set of transactions in a
+// time series bucket.
+type TransactionTimeseriesMetrics struct {
+	Total    int
+	Approved int
+	Declined int
+	// ApprovalRate and DeclineRate are the shares of APPROVED and DECLINED among
+	// the transactions with either status, or 0 without any.
+	ApprovalRate   float64
+	DeclineRate    float64
+	AmountsInCents map[Currency]int64
+	FinalizedCount int
+	LatencyP50Ms   float64
+	LatencyP95Ms   float64
+	LatencyP99Ms   float64
+}
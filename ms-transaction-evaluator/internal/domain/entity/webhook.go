@@ -0,0 +1,130 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookEventTransactionFinalized is sent when a transaction is APPROVED or
+// DECLINED.
+const WebhookEventTransactionFinalized = "transaction.finalized"
+
+// maxWebhookDeliveryLog bounds the attempts kept in a delivery's log.
+const maxWebhookDeliveryLog = 20
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookEndpoint is a URL notified of final transaction decisions. An endpoint
+// without MerchantID is global and receives the decisions of every transaction.
+// Secret signs the payloads and is only returned when the endpoint is registered.
+type WebhookEndpoint struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	MerchantID string    `json:"merchant_id,omitempty"`
+	Secret     string    `json:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Matches reports whether the endpoint is notified of the transaction.
+func (e *WebhookEndpoint) Matches(transaction *TransactionEntity) bool {
+	return e.MerchantID == "" || e.MerchantID == transaction.MerchantID
+}
+
+// WebhookEvent is the JSON payload POSTed to a webhook endpoint.
+type WebhookEvent struct {
+	ID        string            `json:"id"`
+	Event     string            `json:"event"`
+	CreatedAt time.Time         `json:"created_at"`
+	Data      TransactionEntity `json:"data"`
+}
+
+// WebhookAttempt is one entry of a delivery's log. StatusCode is 0 when no
+// response was received.
+type WebhookAttempt struct {
+	AttemptedAt time.Time `json:"attempted_at"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMs  int64     `json:"duration_ms"`
+}
+
+// WebhookDelivery is the notification of one event to one endpoint. Attempts
+// counts the attempts since the delivery was created or last replayed, and
+// NextAttemptAt is the earliest time the deliverer tries again. Log keeps the
+// latest attempts, including those before a replay.
+type WebhookDelivery struct {
+	ID             string                `json:"id"`
+	EndpointID     string                `json:"endpoint_id"`
+	URL            string                `json:"url"`
+	TransactionID  string                `json:"transaction_id"`
+	Event          string                `json:"event"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	LastStatusCode int                   `json:"last_status_code,omitempty"`
+	LastError      string                `json:"last_error,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	Log            []WebhookAttempt      `json:"log"`
+}
+
+// WebhookDeliveryFilter selects deliveries by their non-empty fields.
+type WebhookDeliveryFilter struct {
+	EndpointID    string
+	TransactionID string
+	Status        WebhookDeliveryStatus
+}
+
+// NewTransactionFinalizedDelivery returns the pending delivery of the finalized
+// transaction to the endpoint.
+func NewTransactionFinalizedDelivery(
+	id string,
+	endpoint *WebhookEndpoint,
+	transaction *TransactionEntity,
+	now time.Time,
+) (*WebhookDelivery, error) {
+	payload, err := json.Marshal(WebhookEvent{
+		ID:        id,
+		Event:     WebhookEventTransactionFinalized,
+		CreatedAt: now,
+		Data:      *transaction,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebhookDelivery{
+		ID:            id,
+		EndpointID:    endpoint.ID,
+		URL:           endpoint.URL,
+		TransactionID: transaction.ID,
+		Event:         WebhookEventTransactionFinalized,
+		Payload:       payload,
+		Status:        WebhookDeliveryPending,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}, nil
+}
+
+// Due reports whether the deliverer may attempt the delivery at now.
+func (d *WebhookDelivery) Due(now time.Time) bool {
+	return d.Status == WebhookDeliveryPending && !now.Before(d.NextAttemptAt)
+}
+
+// Record counts the attempt and appends it to the log, dropping the oldest
+// entries beyond the log size.
+func (d *WebhookDelivery) Record(attempt WebhookAttempt) {
+	d.Attempts++
+	d.LastStatusCode = attempt.StatusCode
+	d.LastError = attempt.Error
+	d.Log = append(d.Log, attempt)
+	if len(d.Log) > maxWebhookDeliveryLog {
+		d.Log = d.Log[len(d.Log)-maxWebhookDeliveryLog:]
+	}
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"ms-transaction-evaluator/internal/domain/entity"
+)
+
+// DeadLetterPublisher defines the port for publishing Decision.Calculated messages
+// that could not be applied to Decision.Calculated.DLQ.
+type DeadLetterPublisher interface {
+	Publish(ctx context.Context, deadLetter *entity.DeadLetter) error
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"ms-transaction-evaluator/internal/domain/entity"
+)
+
+// DeadLetterRepository defines the port for inspecting the Decision.Calculated
+// dead-letter topic and redriving its messages.
+type DeadLetterRepository interface {
+	// Count returns the number of messages retained in the dead-letter topic.
+	Count(ctx context.Context) (int64, error)
+	// FindAll returns up to limit messages of the dead-letter topic, oldest first.
+	FindAll(ctx context.Context, limit int) ([]entity.DeadLetter, error)
+	// FindByOffset returns the message at the given position of the dead-letter
+	// topic, or nil if there is none.
+	FindByOffset(ctx context.Context, partition int32, offset int64) (*entity.DeadLetter, error)
+	// Redrive publishes the original message back to Decision.Calculated.
+	Redrive(ctx context.Context, deadLetter *entity.DeadLetter) error
+}
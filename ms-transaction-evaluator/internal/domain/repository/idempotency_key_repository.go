@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"ms-transaction-evaluator/internal/domain/entity"
+)
+
+// IdempotencyKeyRepository defines the port for reading the idempotency keys
+// recorded by OutboxRepository.SaveTransaction.
+type IdempotencyKeyRepository interface {
+	// Find returns the unexpired record of the key, or nil if there is none.
+	Find(ctx context.Context, key string) (*entity.IdempotencyKey, error)
+}
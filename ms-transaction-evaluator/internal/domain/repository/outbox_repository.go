@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"time"
+)
+
+// OutboxRepository defines the port for the transactional outbox: a transaction is
+// saved atomically with the event announcing it, and the pending events are later
+// published by a relayer.
+type OutboxRepository interface {
+	// SaveTransaction saves the new transaction, its outbox entry and, unless nil,
+	// its idempotency key in a single atomic write. Nothing is saved when the write
+	// fails; it fails with entity.ErrIdempotencyKeyExists if the key is recorded.
+	SaveTransaction(ctx context.Context, transaction *entity.TransactionEntity, entry *entity.OutboxEntry, key *entity.IdempotencyKey) error
+	// FindDue returns up to limit pending entries due at now, earliest first.
+	FindDue(ctx context.Context, now time.Time, limit int) ([]entity.OutboxEntry, error)
+	// Claim leases the entry to the caller until the given time by moving its next
+	// attempt there, provided it is still pending and its next attempt is still
+	// nextAttemptAt, the value it was read with. It returns false when another
+	// relayer claimed or finished the entry first.
+	Claim(ctx context.Context, id string, nextAttemptAt time.Time, until time.Time) (bool, error)
+	// MarkSent records that the entry has been published.
+	MarkSent(ctx context.Context, id string, sentAt time.Time) error
+	// MarkFailed records a failed publish attempt and when to try again.
+	MarkFailed(ctx context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time) error
+	// MarkExhausted records the last failed publish attempt and moves the entry to
+	// FAILED, so it is no longer relayed.
+	MarkExhausted(ctx context.Context, id string, attempts int, lastError string) error
+}
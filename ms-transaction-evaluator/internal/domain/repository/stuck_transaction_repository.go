@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"time"
+)
+
+// StuckTransactionRepository defines the port for finding and resolving PENDING
+// transactions that never received a decision.
+type StuckTransactionRepository interface {
+	// FindStuck returns up to limit PENDING transactions created before cutoff and
+	// not republished since cutoff.
+	FindStuck(ctx context.Context, cutoff time.Time, limit int) ([]entity.StuckTransaction, error)
+	// RecordRepublish stores the republish attempts of a transaction that is still
+	// PENDING.
+	RecordRepublish(ctx context.Context, id string, attempts int, republishedAt time.Time) error
+	// Resolve sets the fallback status of a transaction if it is still PENDING and
+	// reports whether it was.
+	Resolve(ctx context.Context, id string, status entity.TransactionStatus, finalizedAt *time.Time) (bool, error)
+}
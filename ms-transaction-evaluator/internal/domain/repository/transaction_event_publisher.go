@@ -8,3 +8,10 @@ import (
 type TransactionEventPublisher interface {
 	Publish(ctx context.Context, transaction *entity.TransactionEntity) error
 }
+
+// TransactionRepublisher defines the port for publishing the Transaction.Created
+// event of a transaction again. attempt is the 1-based republish attempt, which
+// tells the consumer that the event is not a redelivery of the original.
+type TransactionRepublisher interface {
+	Republish(ctx context.Context, transaction *entity.TransactionEntity, attempt int) error
+}
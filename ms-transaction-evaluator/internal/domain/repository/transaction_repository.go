@@ -8,8 +8,15 @@ import (
 
 type TransactionRepository interface {
 	Save(ctx context.Context, transaction *entity.TransactionEntity) error
-	UpdateStatus(ctx context.Context, id string, status entity.TransactionStatus, finalizedAt *time.Time) error
+	// UpdateStatus sets the status of a PENDING transaction, and its finalized_at
+	// and decision path unless they are nil or empty, and returns the transaction
+	// as it was before. It returns nil, leaving the transaction unchanged, when it
+	// is no longer PENDING, as when the stuck transaction sweeper resolved it first.
+	UpdateStatus(ctx context.Context, id string, status entity.TransactionStatus, finalizedAt *time.Time, decisionPath entity.DecisionPath) (*entity.TransactionEntity, error)
 	FindByID(ctx context.Context, id string) (*entity.TransactionEntity, error)
-	FindAllPaginated(ctx context.Context, limit int, cursor string) ([]entity.TransactionEntity, string, error)
+	// FindAllPaginated returns a page of the transactions matching the query and
+	// the cursor of the next page, empty on the last page. It fails with
+	// entity.ErrCursorMalformed for a cursor it did not issue for the same query.
+	FindAllPaginated(ctx context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error)
 	FindAll(ctx context.Context) ([]entity.TransactionEntity, error)
 }
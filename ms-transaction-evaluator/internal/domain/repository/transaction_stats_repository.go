@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"time"
+)
+
+// TransactionStatsRepository defines the port for the pre-aggregated transaction
+// counters: one set per hour of creation and one over all transactions.
+type TransactionStatsRepository interface {
+	// Add adds delta to the counters of the hour the transaction was created in
+	// and to the overall counters.
+	Add(ctx context.Context, transaction *entity.TransactionEntity, delta entity.TransactionCounters) error
+	// FindHours returns the counters of the hours starting from from to to
+	// inclusive that have any, oldest first.
+	FindHours(ctx context.Context, from, to time.Time) ([]entity.HourlyTransactionCounters, error)
+	// Total returns the counters over all transactions.
+	Total(ctx context.Context) (entity.TransactionCounters, error)
+	// Replace discards every counter and stores the given hours, with their sum
+	// as the overall counters.
+	Replace(ctx context.Context, hours []entity.HourlyTransactionCounters) error
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"time"
+)
+
+// WebhookEndpointRepository defines the port for storing registered webhook
+// endpoints.
+type WebhookEndpointRepository interface {
+	Save(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+	// FindAll returns every endpoint, secrets included.
+	FindAll(ctx context.Context) ([]entity.WebhookEndpoint, error)
+	// Delete removes the endpoint and reports whether it existed.
+	Delete(ctx context.Context, id string) (bool, error)
+}
+
+// WebhookDeliveryRepository defines the port for the webhook delivery log.
+type WebhookDeliveryRepository interface {
+	// Create saves a new delivery and reports whether it was new; a delivery with
+	// the same ID is left unchanged.
+	Create(ctx context.Context, delivery *entity.WebhookDelivery) (bool, error)
+	// Update overwrites the delivery.
+	Update(ctx context.Context, delivery *entity.WebhookDelivery) error
+	// FindByID returns the delivery, or nil if it does not exist.
+	FindByID(ctx context.Context, id string) (*entity.WebhookDelivery, error)
+	// FindDue returns up to limit pending deliveries due at now, oldest first.
+	FindDue(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error)
+	// FindAll returns a page of up to limit deliveries matching the filter and the
+	// cursor of the next page, empty on the last page.
+	FindAll(ctx context.Context, filter entity.WebhookDeliveryFilter, limit int, cursor string) ([]entity.WebhookDelivery, string, error)
+}
+
+// WebhookSender defines the port for POSTing a delivery's payload to its endpoint.
+// It returns the response status code, or 0 when no response was received, and an
+// error unless the endpoint answered with a 2xx status.
+type WebhookSender interface {
+	Send(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) (int, error)
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+)
+
+// checkDeadLetterTopic returns ErrDeadLetterQueueNotFound unless topic is the
+// Decision.Calculated dead-letter topic, the only one this service consumes from.
+func checkDeadLetterTopic(queue entity.DeadLetterQueue, topic string) error {
+	if topic != queue.Topic {
+		return fmt.Errorf("%w: %s", ErrDeadLetterQueueNotFound, topic)
+	}
+	return nil
+}
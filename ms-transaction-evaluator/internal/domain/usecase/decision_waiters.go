@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"ms-transaction-evaluator/internal/domain/entity"
+	"sync"
+)
+
+// DecisionWaiters is an in-process registry of requests waiting for the decision
+// of a transaction. It is fed by the TransactionFinalizer, so a request only sees
+// the decisions consumed and the fallbacks applied by its own instance.
+type DecisionWaiters struct {
+	mu      sync.Mutex
+	waiters map[string][]chan entity.TransactionStatus
+}
+
+// NewDecisionWaiters creates an empty registry.
+func NewDecisionWaiters() *DecisionWaiters {
+	return &DecisionWaiters{waiters: make(map[string][]chan entity.TransactionStatus)}
+}
+
+// Register returns a channel receiving the final status of the transaction, and a
+// function removing the waiter that must be called once the caller stops waiting.
+func (w *DecisionWaiters) Register(transactionID string) (<-chan entity.TransactionStatus, func()) {
+	ch := make(chan entity.TransactionStatus, 1)
+
+	w.mu.Lock()
+	w.waiters[transactionID] = append(w.waiters[transactionID], ch)
+	w.mu.Unlock()
+
+	return ch, func() { w.remove(transactionID, ch) }
+}
+
+// Notify wakes the requests waiting for the transaction with the status it has
+// been given. A PENDING status, left by a FRAUD_CHECK decision, is ignored.
+func (w *DecisionWaiters) Notify(transactionID string, status entity.TransactionStatus) {
+	if status == entity.PENDING {
+		return
+	}
+
+	w.mu.Lock()
+	waiters := w.waiters[transactionID]
+	delete(w.waiters, transactionID)
+	w.mu.Unlock()
+
+	for _, ch := range waiters {
+		ch <- status
+	}
+}
+
+// Len returns the number of waiting requests.
+func (w *DecisionWaiters) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n := 0
+	for _, waiters := range w.waiters {
+		n += len(waiters)
+	}
+	return n
+}
+
+func (w *DecisionWaiters) remove(transactionID string, ch chan entity.TransactionStatus) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	waiters := w.waiters[transactionID]
+	for i := range waiters {
+		if waiters[i] == ch {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+
+	if len(waiters) == 0 {
+		delete(w.waiters, transactionID)
+	} else {
+		w.waiters[transactionID] = waiters
+	}
+}
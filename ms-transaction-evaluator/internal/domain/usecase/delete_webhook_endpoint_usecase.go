@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/repository"
+)
+
+// DeleteWebhookEndpointUseCase removes a webhook endpoint. Its pending deliveries
+// are marked failed by the deliverer.
+type DeleteWebhookEndpointUseCase struct {
+	endpointRepo repository.WebhookEndpointRepository
+}
+
+// NewDeleteWebhookEndpointUseCase creates a new use case.
+func NewDeleteWebhookEndpointUseCase(endpointRepo repository.WebhookEndpointRepository) *DeleteWebhookEndpointUseCase {
+	return &DeleteWebhookEndpointUseCase{endpointRepo: endpointRepo}
+}
+
+// Execute deletes the endpoint.
+func (uc *DeleteWebhookEndpointUseCase) Execute(ctx context.Context, id string) error {
+	deleted, err := uc.endpointRepo.Delete(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWebhookStoreFailed, err)
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s", ErrWebhookEndpointNotFound, id)
+	}
+	return nil
+}
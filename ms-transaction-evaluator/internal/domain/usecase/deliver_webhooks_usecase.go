@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+	"ms-transaction-evaluator/internal/infrastructure/telemetry"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultWebhookBatchSize is the number of due deliveries read per run.
+	DefaultWebhookBatchSize = 100
+	// DefaultWebhookMaxAttempts is how many times a delivery is attempted before it
+	// is marked failed.
+	DefaultWebhookMaxAttempts = 10
+	// webhookInitialBackoff is the wait after the first failed attempt.
+	webhookInitialBackoff = 10 * time.Second
+	// webhookMaxBackoff caps the wait between attempts.
+	webhookMaxBackoff = time.Hour
+)
+
+// DeliverWebhooksUseCase sends the due webhook deliveries and records the result
+// of every attempt.
+type DeliverWebhooksUseCase struct {
+	endpointRepo repository.WebhookEndpointRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	sender       repository.WebhookSender
+	maxAttempts  int
+	batchSize    int
+	logger       zerolog.Logger
+	now          func() time.Time
+}
+
+// NewDeliverWebhooksUseCase creates a new use case. Non-positive maxAttempts and
+// batchSize take their defaults.
+func NewDeliverWebhooksUseCase(
+	endpointRepo repository.WebhookEndpointRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	sender repository.WebhookSender,
+	maxAttempts int,
+	batchSize int,
+	logger zerolog.Logger,
+) *DeliverWebhooksUseCase {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultWebhookMaxAttempts
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultWebhookBatchSize
+	}
+
+	return &DeliverWebhooksUseCase{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+		sender:       sender,
+		maxAttempts:  maxAttempts,
+		batchSize:    batchSize,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// Execute attempts one batch of due deliveries and returns how many were read. A
+// failed attempt is retried with exponential backoff until maxAttempts is reached.
+// A delivery whose endpoint was deleted is marked failed without an attempt.
+func (uc *DeliverWebhooksUseCase) Execute(ctx context.Context) (int, error) {
+	now := uc.now().UTC()
+
+	deliveries, err := uc.deliveryRepo.FindDue(ctx, now, uc.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrWebhookStoreFailed, err)
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	endpoints, err := uc.endpointRepo.FindAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrWebhookStoreFailed, err)
+	}
+	endpointsByID := make(map[string]*entity.WebhookEndpoint, len(endpoints))
+	for i := range endpoints {
+		endpointsByID[endpoints[i].ID] = &endpoints[i]
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		if !delivery.Due(now) {
+			continue
+		}
+
+		endpoint, ok := endpointsByID[delivery.EndpointID]
+		if !ok {
+			delivery.Status = entity.WebhookDeliveryFailed
+			delivery.LastError = "webhook endpoint deleted"
+			telemetry.WebhookDeliveries.WithLabelValues("failed").Inc()
+			uc.update(ctx, delivery)
+			continue
+		}
+
+		uc.attempt(ctx, endpoint, delivery)
+		uc.update(ctx, delivery)
+	}
+
+	return len(deliveries), nil
+}
+
+// Run delivers every interval until ctx is cancelled. A run that read a full batch
+// is followed immediately by the next one.
+func (uc *DeliverWebhooksUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				read, err := uc.Execute(ctx)
+				if err != nil {
+					uc.logger.Warn().Err(err).Msg("webhook delivery run failed")
+				}
+				if err != nil || read < uc.batchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// attempt sends the delivery and records the outcome on it.
+func (uc *DeliverWebhooksUseCase) attempt(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) {
+	start := uc.now().UTC()
+	statusCode, err := uc.sender.Send(ctx, endpoint, delivery)
+	end := uc.now().UTC()
+	telemetry.WebhookDeliveryDuration.Observe(end.Sub(start).Seconds())
+
+	attempt := entity.WebhookAttempt{
+		AttemptedAt: start,
+		StatusCode:  statusCode,
+		DurationMs:  end.Sub(start).Milliseconds(),
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	delivery.Record(attempt)
+
+	switch {
+	case err == nil:
+		delivery.Status = entity.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &end
+		telemetry.WebhookDeliveries.WithLabelValues("delivered").Inc()
+		uc.logger.Info().
+			Str("delivery_id", delivery.ID).
+			Str("transaction_id", delivery.TransactionID).
+			Int("status_code", statusCode).
+			Int("attempts", delivery.Attempts).
+			Msg("webhook delivered")
+	case delivery.Attempts >= uc.maxAttempts:
+		delivery.Status = entity.WebhookDeliveryFailed
+		telemetry.WebhookDeliveries.WithLabelValues("failed").Inc()
+		uc.logger.Error().Err(err).
+			Str("delivery_id", delivery.ID).
+			Str("transaction_id", delivery.TransactionID).
+			Int("status_code", statusCode).
+			Int("attempts", delivery.Attempts).
+			Msg("webhook delivery failed, attempts exhausted")
+	default:
+		delivery.NextAttemptAt = end.Add(webhookBackoff(delivery.Attempts))
+		telemetry.WebhookDeliveries.WithLabelValues("retry").Inc()
+		uc.logger.Warn().Err(err).
+			Str("delivery_id", delivery.ID).
+			Str("transaction_id", delivery.TransactionID).
+			Int("status_code", statusCode).
+			Int("attempts", delivery.Attempts).
+			Time("next_attempt_at", delivery.NextAttemptAt).
+			Msg("webhook delivery failed, will retry")
+	}
+}
+
+// update saves the delivery. A delivery that could not be saved is attempted
+// again by a later run, so delivery is at-least-once.
+func (uc *DeliverWebhooksUseCase) update(ctx context.Context, delivery *entity.WebhookDelivery) {
+	if err := uc.deliveryRepo.Update(ctx, delivery); err != nil {
+		uc.logger.Error().Err(err).
+			Str("delivery_id", delivery.ID).
+			Str("transaction_id", delivery.TransactionID).
+			Msg("failed to record webhook delivery attempt")
+	}
+}
+
+// webhookBackoff returns the wait after the given number of failed attempts,
+// doubling from webhookInitialBackoff up to webhookMaxBackoff.
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookInitialBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, webhookMaxBackoff)
+}
@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// mockWebhookSender is a hand-written mock implementing WebhookSender.
+type mockWebhookSender struct {
+	sendFunc func(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) (int, error)
+	sent     []string
+}
+
+func (m *mockWebhookSender) Send(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) (int, error) {
+	m.sent = append(m.sent, delivery.ID)
+	if m.sendFunc != nil {
+		return m.sendFunc(ctx, endpoint, delivery)
+	}
+	return 200, nil
+}
+
+func newPendingWebhookDelivery(id, endpointID string) entity.WebhookDelivery {
+	return entity.WebhookDelivery{
+		ID:            id,
+		EndpointID:    endpointID,
+		TransactionID: "tx-" + id,
+		Status:        entity.WebhookDeliveryPending,
+		CreatedAt:     webhookNow.Add(-time.Minute),
+		NextAttemptAt: webhookNow.Add(-time.Minute),
+	}
+}
+
+func newTestDeliverUseCase(deliveries *mockWebhookDeliveryRepository, sender *mockWebhookSender, maxAttempts int) *DeliverWebhooksUseCase {
+	endpoints := &mockWebhookEndpointRepository{endpoints: []entity.WebhookEndpoint{{ID: "ep-1", URL: "https://example.com", Secret: "whsec_test"}}}
+	uc := NewDeliverWebhooksUseCase(endpoints, deliveries, sender, maxAttempts, 10, zerolog.Nop())
+	uc.now = func() time.Time { return webhookNow }
+	return uc
+}
+
+func TestDeliverWebhooksUseCase_Execute(t *testing.T) {
+	t.Run("marks successful deliveries delivered", func(t *testing.T) {
+		deliveries := newMockWebhookDeliveryRepository(newPendingWebhookDelivery("1", "ep-1"))
+		sender := &mockWebhookSender{}
+
+		read, err := newTestDeliverUseCase(deliveries, sender, 3).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		d := deliveries.deliveries["1"]
+		if read != 1 || d.Status != entity.WebhookDeliveryDelivered || d.DeliveredAt == nil {
+			t.Errorf("expected delivered, got %d %+v", read, d)
+		}
+		if d.Attempts != 1 || len(d.Log) != 1 || d.Log[0].StatusCode != 200 || d.LastStatusCode != 200 {
+			t.Errorf("expected one logged attempt with status 200, got %+v", d)
+		}
+	})
+
+	t.Run("schedules a retry with exponential backoff", func(t *testing.T) {
+		delivery := newPendingWebhookDelivery("1", "ep-1")
+		delivery.Attempts = 2
+		deliveries := newMockWebhookDeliveryRepository(delivery)
+		sender := &mockWebhookSender{
+			sendFunc: func(_ context.Context, _ *entity.WebhookEndpoint, _ *entity.WebhookDelivery) (int, error) {
+				return 503, errors.New("unexpected status 503")
+			},
+		}
+
+		if _, err := newTestDeliverUseCase(deliveries, sender, 5).Execute(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		d := deliveries.deliveries["1"]
+		if d.Status != entity.WebhookDeliveryPending || d.Attempts != 3 {
+			t.Fatalf("expected a pending retry after 3 attempts, got %+v", d)
+		}
+		if !d.NextAttemptAt.Equal(webhookNow.Add(40 * time.Second)) {
+			t.Errorf("expected next attempt in 40s, got %s", d.NextAttemptAt.Sub(webhookNow))
+		}
+		if d.LastStatusCode != 503 || d.LastError == "" {
+			t.Errorf("expected the failure recorded, got %+v", d)
+		}
+	})
+
+	t.Run("marks the delivery failed once attempts are exhausted", func(t *testing.T) {
+		delivery := newPendingWebhookDelivery("1", "ep-1")
+		delivery.Attempts = 2
+		deliveries := newMockWebhookDeliveryRepository(delivery)
+		sender := &mockWebhookSender{
+			sendFunc: func(_ context.Context, _ *entity.WebhookEndpoint, _ *entity.WebhookDelivery) (int, error) {
+				return 0, errors.New("connection refused")
+			},
+		}
+
+		if _, err := newTestDeliverUseCase(deliveries, sender, 3).Execute(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d := deliveries.deliveries["1"]; d.Status != entity.WebhookDeliveryFailed {
+			t.Errorf("expected FAILED, got %s", d.Status)
+		}
+	})
+
+	t.Run("fails deliveries of deleted endpoints without sending", func(t *testing.T) {
+		deliveries := newMockWebhookDeliveryRepository(newPendingWebhookDelivery("1", "deleted"))
+		sender := &mockWebhookSender{}
+
+		if _, err := newTestDeliverUseCase(deliveries, sender, 3).Execute(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d := deliveries.deliveries["1"]; d.Status != entity.WebhookDeliveryFailed || d.Attempts != 0 {
+			t.Errorf("expected FAILED without attempt, got %+v", d)
+		}
+		if len(sender.sent) != 0 {
+			t.Errorf("expected nothing sent, got %v", sender.sent)
+		}
+	})
+
+	t.Run("skips deliveries not yet due", func(t *testing.T) {
+		waiting := newPendingWebhookDelivery("1", "ep-1")
+		waiting.NextAttemptAt = webhookNow.Add(time.Second)
+		deliveries := newMockWebhookDeliveryRepository(waiting)
+		sender := &mockWebhookSender{}
+
+		read, err := newTestDeliverUseCase(deliveries, sender, 3).Execute(context.Background())
+		if err != nil || read != 0 || len(sender.sent) != 0 {
+			t.Errorf("expected nothing delivered, got %d %v %v", read, sender.sent, err)
+		}
+	})
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{4, 80 * time.Second},
+		{9, 2560 * time.Second},
+		{10, time.Hour},
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempts); got != tt.expected {
+			t.Errorf("webhookBackoff(%d) = %s, expected %s", tt.attempts, got, tt.expected)
+		}
+	}
+}
+
+func TestReplayWebhookDeliveryUseCase_Execute(t *testing.T) {
+	delivered := webhookNow.Add(-time.Hour)
+	delivery := newPendingWebhookDelivery("1", "ep-1")
+	delivery.Status = entity.WebhookDeliveryFailed
+	delivery.Attempts = 10
+	delivery.DeliveredAt = &delivered
+	delivery.Log = []entity.WebhookAttempt{{AttemptedAt: delivered, StatusCode: 500}}
+	deliveries := newMockWebhookDeliveryRepository(delivery)
+
+	uc := NewReplayWebhookDeliveryUseCase(deliveries)
+	uc.now = func() time.Time { return webhookNow }
+
+	replayed, err := uc.Execute(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replayed.Status != entity.WebhookDeliveryPending || replayed.Attempts != 0 || replayed.DeliveredAt != nil ||
+		!replayed.NextAttemptAt.Equal(webhookNow) || len(replayed.Log) != 1 {
+		t.Errorf("unexpected replayed delivery: %+v", replayed)
+	}
+	if deliveries.updates != 1 {
+		t.Errorf("expected the delivery to be saved, got %d updates", deliveries.updates)
+	}
+
+	if _, err := uc.Execute(context.Background(), "missing"); !errors.Is(err, ErrWebhookDeliveryNotFound) {
+		t.Errorf("expected ErrWebhookDeliveryNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+	"ms-transaction-evaluator/internal/infrastructure/telemetry"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// webhookDeliveryNamespace derives the delivery IDs, so that a decision consumed
+// twice creates each delivery once.
+var webhookDeliveryNamespace = uuid.MustParse("5c0a7b1e-9f6d-4c1b-8e3a-2d4f6a8b0c1e")
+
+// EnqueueWebhookDeliveriesUseCase creates the deliveries notifying the matching
+// webhook endpoints of a finalized transaction. They are sent by
+// DeliverWebhooksUseCase.
+type EnqueueWebhookDeliveriesUseCase struct {
+	endpointRepo repository.WebhookEndpointRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	logger       zerolog.Logger
+	now          func() time.Time
+}
+
+// NewEnqueueWebhookDeliveriesUseCase creates a new use case.
+func NewEnqueueWebhookDeliveriesUseCase(
+	endpointRepo repository.WebhookEndpointRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	logger zerolog.Logger,
+) *EnqueueWebhookDeliveriesUseCase {
+	return &EnqueueWebhookDeliveriesUseCase{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// Execute creates a delivery for every global endpoint and every endpoint of the
+// transaction's merchant, and returns how many were new. Deliveries that already
+// exist are skipped, so the call can be retried after a failure.
+func (uc *EnqueueWebhookDeliveriesUseCase) Execute(ctx context.Context, transaction *entity.TransactionEntity) (int, error) {
+	endpoints, err := uc.endpointRepo.FindAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrWebhookEnqueueFailed, err)
+	}
+
+	now := uc.now().UTC()
+	enqueued := 0
+	var errs []error
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		if !endpoint.Matches(transaction) {
+			continue
+		}
+
+		id := uuid.NewSHA1(webhookDeliveryNamespace, []byte(endpoint.ID+"/"+transaction.ID+"/"+string(transaction.Status))).String()
+		delivery, err := entity.NewTransactionFinalizedDelivery(id, endpoint, transaction, now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		created, err := uc.deliveryRepo.Create(ctx, delivery)
+		if err != nil {
+			uc.logger.Error().Err(err).
+				Str("transaction_id", transaction.ID).
+				Str("endpoint_id", endpoint.ID).
+				Msg("failed to enqueue webhook delivery")
+			errs = append(errs, err)
+			continue
+		}
+		if created {
+			enqueued++
+			telemetry.WebhookDeliveriesEnqueued.Inc()
+		}
+	}
+
+	if len(errs) > 0 {
+		return enqueued, fmt.Errorf("%w: %w", ErrWebhookEnqueueFailed, errors.Join(errs...))
+	}
+	return enqueued, nil
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var webhookNow = time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+// mockWebhookEndpointRepository is a hand-written in-memory mock implementing
+// WebhookEndpointRepository.
+type mockWebhookEndpointRepository struct {
+	endpoints []entity.WebhookEndpoint
+	findErr   error
+}
+
+func (m *mockWebhookEndpointRepository) Save(_ context.Context, endpoint *entity.WebhookEndpoint) error {
+	m.endpoints = append(m.endpoints, *endpoint)
+	return nil
+}
+
+func (m *mockWebhookEndpointRepository) FindAll(_ context.Context) ([]entity.WebhookEndpoint, error) {
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	return append([]entity.WebhookEndpoint(nil), m.endpoints...), nil
+}
+
+func (m *mockWebhookEndpointRepository) Delete(_ context.Context, id string) (bool, error) {
+	for i := range m.endpoints {
+		if m.endpoints[i].ID == id {
+			m.endpoints = append(m.endpoints[:i], m.endpoints[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// mockWebhookDeliveryRepository is a hand-written in-memory mock implementing
+// WebhookDeliveryRepository.
+type mockWebhookDeliveryRepository struct {
+	deliveries map[string]entity.WebhookDelivery
+	createErr  error
+	updates    int
+}
+
+func newMockWebhookDeliveryRepository(deliveries ...entity.WebhookDelivery) *mockWebhookDeliveryRepository {
+	m := &mockWebhookDeliveryRepository{deliveries: make(map[string]entity.WebhookDelivery)}
+	for _, d := range deliveries {
+		m.deliveries[d.ID] = d
+	}
+	return m
+}
+
+func (m *mockWebhookDeliveryRepository) Create(_ context.Context, delivery *entity.WebhookDelivery) (bool, error) {
+	if m.createErr != nil {
+		return false, m.createErr
+	}
+	if _, ok := m.deliveries[delivery.ID]; ok {
+		return false, nil
+	}
+	m.deliveries[delivery.ID] = *delivery
+	return true, nil
+}
+
+func (m *mockWebhookDeliveryRepository) Update(_ context.Context, delivery *entity.WebhookDelivery) error {
+	m.updates++
+	m.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (m *mockWebhookDeliveryRepository) FindByID(_ context.Context, id string) (*entity.WebhookDelivery, error) {
+	d, ok := m.deliveries[id]
+	if !ok {
+		return nil, nil
+	}
+	return &d, nil
+}
+
+func (m *mockWebhookDeliveryRepository) FindDue(_ context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	var due []entity.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.Due(now) {
+			due = append(due, d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (m *mockWebhookDeliveryRepository) FindAll(_ context.Context, _ entity.WebhookDeliveryFilter, _ int, _ string) ([]entity.WebhookDelivery, string, error) {
+	return nil, "", nil
+}
+
+func newTestEnqueueUseCase(endpoints *mockWebhookEndpointRepository, deliveries *mockWebhookDeliveryRepository) *EnqueueWebhookDeliveriesUseCase {
+	uc := NewEnqueueWebhookDeliveriesUseCase(endpoints, deliveries, zerolog.Nop())
+	uc.now = func() time.Time { return webhookNow }
+	return uc
+}
+
+func TestEnqueueWebhookDeliveriesUseCase_Execute(t *testing.T) {
+	tx := &entity.TransactionEntity{ID: "tx-1", MerchantID: "merchant_42", Status: entity.APPROVED}
+	endpoints := &mockWebhookEndpointRepository{endpoints: []entity.WebhookEndpoint{
+		{ID: "global", URL: "https://example.com/all"},
+		{ID: "merchant", URL: "https://example.com/42", MerchantID: "merchant_42"},
+		{ID: "other", URL: "https://example.com/7", MerchantID: "merchant_7"},
+	}}
+
+	t.Run("enqueues a delivery per matching endpoint", func(t *testing.T) {
+		deliveries := newMockWebhookDeliveryRepository()
+
+		enqueued, err := newTestEnqueueUseCase(endpoints, deliveries).Execute(context.Background(), tx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if enqueued != 2 || len(deliveries.deliveries) != 2 {
+			t.Fatalf("expected 2 deliveries, got %d (%d stored)", enqueued, len(deliveries.deliveries))
+		}
+		for _, d := range deliveries.deliveries {
+			if d.EndpointID == "other" {
+				t.Errorf("expected no delivery to another merchant's endpoint")
+			}
+			if d.Status != entity.WebhookDeliveryPending || !d.NextAttemptAt.Equal(webhookNow) || d.TransactionID != "tx-1" {
+				t.Errorf("unexpected delivery: %+v", d)
+			}
+		}
+	})
+
+	t.Run("does not enqueue twice for a redelivered decision", func(t *testing.T) {
+		deliveries := newMockWebhookDeliveryRepository()
+		uc := newTestEnqueueUseCase(endpoints, deliveries)
+
+		if _, err := uc.Execute(context.Background(), tx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		enqueued, err := uc.Execute(context.Background(), tx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if enqueued != 0 || len(deliveries.deliveries) != 2 {
+			t.Errorf("expected no new delivery, got %d (%d stored)", enqueued, len(deliveries.deliveries))
+		}
+	})
+
+	t.Run("returns ErrWebhookEnqueueFailed on store failure", func(t *testing.T) {
+		deliveries := newMockWebhookDeliveryRepository()
+		deliveries.createErr = errors.New("throttled")
+
+		_, err := newTestEnqueueUseCase(endpoints, deliveries).Execute(context.Background(), tx)
+		if !errors.Is(err, ErrWebhookEnqueueFailed) {
+			t.Errorf("expected ErrWebhookEnqueueFailed, got %v", err)
+		}
+
+		_, err = newTestEnqueueUseCase(&mockWebhookEndpointRepository{findErr: errors.New("throttled")}, newMockWebhookDeliveryRepository()).
+			Execute(context.Background(), tx)
+		if !errors.Is(err, ErrWebhookEnqueueFailed) {
+			t.Errorf("expected ErrWebhookEnqueueFailed, got %v", err)
+		}
+	})
+}
+
+func TestUpdateTransactionStatusUseCase_Execute_Webhooks(t *testing.T) {
+	endpoints := &mockWebhookEndpointRepository{endpoints: []entity.WebhookEndpoint{{ID: "global", URL: "https://example.com"}}}
+
+	t.Run("enqueues deliveries on finalization", func(t *testing.T) {
+		deliveries := newMockWebhookDeliveryRepository()
+		repo := &updateStatusMockRepo{
+			findByIDFunc: func(_ context.Context, id string) (*entity.TransactionEntity, error) {
+				return &entity.TransactionEntity{ID: id, Status: entity.DECLINED, CreatedAt: webhookNow}, nil
+			},
+		}
+		uc := NewUpdateTransactionStatusUseCase(repo, NewTransactionFinalizer(newTestEnqueueUseCase(endpoints, deliveries), nil, nil, nil))
+
+		if err := uc.Execute(context.Background(), &entity.DecisionCalculatedMessage{TransactionID: "tx-1", Status: "DECLINED"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(deliveries.deliveries) != 1 {
+			t.Errorf("expected 1 delivery, got %d", len(deliveries.deliveries))
+		}
+	})
+
+	t.Run("does not enqueue for FRAUD_CHECK", func(t *testing.T) {
+		deliveries := newMockWebhookDeliveryRepository()
+		uc := NewUpdateTransactionStatusUseCase(&updateStatusMockRepo{}, NewTransactionFinalizer(newTestEnqueueUseCase(endpoints, deliveries), nil, nil, nil))
+
+		if err := uc.Execute(context.Background(), &entity.DecisionCalculatedMessage{TransactionID: "tx-1", Status: "FRAUD_CHECK"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(deliveries.deliveries) != 0 {
+			t.Errorf("expected no delivery, got %d", len(deliveries.deliveries))
+		}
+	})
+
+	t.Run("fails when the finalized transaction cannot be read", func(t *testing.T) {
+		repo := &updateStatusMockRepo{
+			findByIDFunc: func(_ context.Context, _ string) (*entity.TransactionEntity, error) {
+				return nil, errors.New("throttled")
+			},
+		}
+		uc := NewUpdateTransactionStatusUseCase(repo, NewTransactionFinalizer(newTestEnqueueUseCase(endpoints, newMockWebhookDeliveryRepository()), nil, nil, nil))
+
+		err := uc.Execute(context.Background(), &entity.DecisionCalculatedMessage{TransactionID: "tx-1", Status: "APPROVED"})
+		if !errors.Is(err, ErrWebhookEnqueueFailed) {
+			t.Errorf("expected ErrWebhookEnqueueFailed, got %v", err)
+		}
+	})
+}
@@ -9,3 +9,47 @@ var ErrTransactionNotFound = errors.New("transaction not found")
 var ErrInvalidLimit = errors.New("invalid limit: must be between 1 and 100")
 
 var ErrInvalidCursor = errors.New("invalid cursor")
+
+var ErrDeadLetterQueueNotFound = errors.New("dead-letter topic not found")
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+var ErrDeadLetterRetrievalFailed = errors.New("failed to read dead-letter topic")
+
+var ErrDeadLetterLimitInvalid = errors.New("invalid dead letter limit")
+
+var ErrRedriveSelectionInvalid = errors.New("either ids or all must be given")
+
+var ErrRedriveRateInvalid = errors.New("invalid redrive rate")
+
+var ErrOutboxRetrievalFailed = errors.New("failed to read outbox")
+
+var ErrStuckTransactionsRetrievalFailed = errors.New("failed to find stuck transactions")
+
+var ErrInvalidFallbackStatus = errors.New("invalid stuck transaction fallback status")
+
+var ErrIdempotencyKeyConflict = errors.New("idempotency key already used with a different payload")
+
+var ErrInvalidWaitTimeout = errors.New("invalid decision wait timeout")
+
+var ErrWebhookURLInvalid = errors.New("webhook url must be an absolute http or https URL")
+
+var ErrWebhookSecretTooShort = errors.New("webhook secret must be at least 16 characters")
+
+var ErrWebhookEndpointNotFound = errors.New("webhook endpoint not found")
+
+var ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+var ErrWebhookDeliveryStatusInvalid = errors.New("invalid webhook delivery status")
+
+var ErrWebhookStoreFailed = errors.New("failed to access webhook store")
+
+var ErrWebhookEnqueueFailed = errors.New("failed to enqueue webhook deliveries")
+
+var ErrInvalidStreamFilter = errors.New("invalid stream filter")
+
+var ErrInvalidTransactionFilter = errors.New("invalid transaction filter")
+
+var ErrTransactionStatsRebuildFailed = errors.New("failed to rebuild transaction stats")
+
+var ErrInvalidTimeseriesQuery = errors.New("invalid timeseries query")
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+)
+
+// GetDeadLetterQueuesUseCase retrieves the Decision.Calculated dead-letter topic.
+type GetDeadLetterQueuesUseCase struct {
+	deadLetterRepo repository.DeadLetterRepository
+	queue          entity.DeadLetterQueue
+}
+
+// NewGetDeadLetterQueuesUseCase creates a new use case for the Decision.Calculated dead-letter queue.
+func NewGetDeadLetterQueuesUseCase(
+	deadLetterRepo repository.DeadLetterRepository,
+	queue entity.DeadLetterQueue,
+) *GetDeadLetterQueuesUseCase {
+	return &GetDeadLetterQueuesUseCase{
+		deadLetterRepo: deadLetterRepo,
+		queue:          queue,
+	}
+}
+
+// Execute returns the dead-letter queue with the number of messages it retains,
+// as the only entry of the list.
+func (uc *GetDeadLetterQueuesUseCase) Execute(
+	ctx context.Context,
+) ([]entity.DeadLetterQueue, error) {
+	count, err := uc.deadLetterRepo.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDeadLetterRetrievalFailed, err)
+	}
+
+	queue := uc.queue
+	queue.Count = count
+	return []entity.DeadLetterQueue{queue}, nil
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+)
+
+// GetDeadLetterUseCase retrieves a single message of the Decision.Calculated
+// dead-letter topic.
+type GetDeadLetterUseCase struct {
+	deadLetterRepo repository.DeadLetterRepository
+	queue          entity.DeadLetterQueue
+}
+
+// NewGetDeadLetterUseCase creates a new use case for the Decision.Calculated dead-letter queue.
+func NewGetDeadLetterUseCase(
+	deadLetterRepo repository.DeadLetterRepository,
+	queue entity.DeadLetterQueue,
+) *GetDeadLetterUseCase {
+	return &GetDeadLetterUseCase{
+		deadLetterRepo: deadLetterRepo,
+		queue:          queue,
+	}
+}
+
+// Execute returns the message of the dead-letter topic with the given ID.
+func (uc *GetDeadLetterUseCase) Execute(
+	ctx context.Context,
+	topic string,
+	id string,
+) (*entity.DeadLetter, error) {
+	if err := checkDeadLetterTopic(uc.queue, topic); err != nil {
+		return nil, err
+	}
+
+	partition, offset, err := entity.ParseDeadLetterID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetter, err := uc.deadLetterRepo.FindByOffset(ctx, partition, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDeadLetterRetrievalFailed, err)
+	}
+	if deadLetter == nil {
+		return nil, fmt.Errorf("%w: %s", ErrDeadLetterNotFound, id)
+	}
+
+	return deadLetter, nil
+}
@@ -27,8 +27,8 @@ func (m *roundTripMockRepo) Save(_ context.Context, txn *entity.TransactionEntit
 	return nil
 }
 
-func (m *roundTripMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) error {
-	return nil
+func (m *roundTripMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time, _ entity.DecisionPath) (*entity.TransactionEntity, error) {
+	return &entity.TransactionEntity{Status: entity.PENDING}, nil
 }
 
 func (m *roundTripMockRepo) FindByID(_ context.Context, id string) (*entity.TransactionEntity, error) {
@@ -39,7 +39,7 @@ func (m *roundTripMockRepo) FindByID(_ context.Context, id string) (*entity.Tran
 	return txn, nil
 }
 
-func (m *roundTripMockRepo) FindAllPaginated(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
+func (m *roundTripMockRepo) FindAllPaginated(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
 	return nil, "", nil
 }
 
@@ -9,80 +9,96 @@ import (
 
 // GetTransactionStatsUseCase computes aggregated metrics across all transactions.
 type GetTransactionStatsUseCase struct {
-	transactionRepo repository.TransactionRepository
+	statsRepo  repository.TransactionStatsRepository
+	thresholds entity.LatencyTierThresholds
 }
 
-// NewGetTransactionStatsUseCase creates a new GetTransactionStatsUseCase.
-func NewGetTransactionStatsUseCase(repo repository.TransactionRepository) *GetTransactionStatsUseCase {
-	return &GetTransactionStatsUseCase{transactionRepo: repo}
+// NewGetTransactionStatsUseCase creates a new GetTransactionStatsUseCase that
+// counts the finalized transactions in the latency tiers bounded by thresholds.
+func NewGetTransactionStatsUseCase(statsRepo repository.TransactionStatsRepository, thresholds entity.LatencyTierThresholds) *GetTransactionStatsUseCase {
+	return &GetTransactionStatsUseCase{statsRepo: statsRepo, thresholds: thresholds}
 }
 
-// Execute retrieves all transactions and aggregates them into stats in a single pass.
+// Execute reads the pre-aggregated counters: the hourly ones of the last 30 days
+// for the time windows, and the overall ones for everything else. Transactions
+// are counted by hour of creation, so each window starts at the beginning of its
+// first hour. The latency percentiles and tiers are estimated from the latency
+// sketches, so a latency within LatencySketchRelativeAccuracy of a threshold may
+// be counted in either tier; the stats report that bound with the tiers.
 func (uc *GetTransactionStatsUseCase) Execute(ctx context.Context) (*entity.TransactionStats, error) {
-	transactions, err := uc.transactionRepo.FindAll(ctx)
+	now := time.Now().UTC()
+	last24h := now.Add(-24 * time.Hour).Truncate(time.Hour)
+	last7d := now.Add(-7 * 24 * time.Hour).Truncate(time.Hour)
+	last30d := now.Add(-30 * 24 * time.Hour).Truncate(time.Hour)
+
+	hours, err := uc.statsRepo.FindHours(ctx, last30d, now.Truncate(time.Hour))
 	if err != nil {
 		return nil, err
 	}
 
-	now := time.Now()
-	last24h := now.Add(-24 * time.Hour)
-	last7d := now.Add(-7 * 24 * time.Hour)
-	last30d := now.Add(-30 * 24 * time.Hour)
+	total, err := uc.statsRepo.Total(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	stats := &entity.TransactionStats{
+		Total:          total.Created,
+		Approved:       total.Statuses[entity.APPROVED],
+		Declined:       total.Statuses[entity.DECLINED],
+		Pending:        total.Statuses[entity.PENDING],
+		Expired:        total.Statuses[entity.EXPIRED],
+		NeedsReview:    total.Statuses[entity.NEEDS_REVIEW],
 		PaymentMethods: make(map[entity.PaymentMethod]int),
-	}
-
-	var latencySum float64
+		FinalizedCount: total.Finalized,
 
-	for i := range transactions {
-		txn := &transactions[i]
-		stats.Total++
+		LatencyThresholds:        uc.thresholds,
+		LatencyTierRelativeError: entity.LatencySketchRelativeAccuracy,
+		LatencyByStatus:          make(map[entity.TransactionStatus]entity.LatencyPercentiles),
+		LatencyByPaymentMethod:   make(map[entity.PaymentMethod]entity.LatencyPercentiles),
+		FraudCheckDecisions:      total.DecisionPaths[entity.DecisionPathFraudCheck],
+		DirectDecisions:          total.DecisionPaths[entity.DecisionPathDirect],
+	}
 
-		// Time buckets
-		if !txn.CreatedAt.Before(last24h) {
-			stats.Today++
-		}
-		if !txn.CreatedAt.Before(last7d) {
-			stats.ThisWeek++
+	// Time buckets
+	for _, hour := range hours {
+		if !hour.Hour.Before(last24h) {
+			stats.Today += hour.Counters.Created
 		}
-		if !txn.CreatedAt.Before(last30d) {
-			stats.ThisMonth++
+		if !hour.Hour.Before(last7d) {
+			stats.ThisWeek += hour.Counters.Created
 		}
+		stats.ThisMonth += hour.Counters.Created
+	}
 
-		// Status counts
-		switch txn.Status {
-		case entity.APPROVED:
-			stats.Approved++
-		case entity.DECLINED:
-			stats.Declined++
-		case entity.PENDING:
-			stats.Pending++
+	// Payment method counts
+	for method, n := range total.PaymentMethods {
+		if n != 0 {
+			stats.PaymentMethods[method] = n
 		}
+	}
 
-		// Payment method counts
-		stats.PaymentMethods[txn.PaymentMethod]++
-
-		// Latency for finalized transactions
-		if txn.FinalizedAt != nil && !txn.FinalizedAt.IsZero() {
-			latencyMs := float64(txn.FinalizedAt.Sub(txn.CreatedAt).Milliseconds())
-			latencySum += latencyMs
-			stats.FinalizedCount++
-
-			tier := entity.ClassifyLatency(latencyMs)
-			switch tier {
-			case entity.LatencyLow:
-				stats.LatencyLow++
-			case entity.LatencyMedium:
-				stats.LatencyMedium++
-			case entity.LatencyHigh:
-				stats.LatencyHigh++
-			}
+	// Latency percentiles and tiers
+	latency := total.Latency()
+	stats.Latency = entity.NewLatencyPercentiles(latency)
+	for status, sketch := range total.LatencyByStatus {
+		if sketch.Count() > 0 {
+			stats.LatencyByStatus[status] = entity.NewLatencyPercentiles(sketch)
 		}
 	}
+	for method, sketch := range total.LatencyByPaymentMethod {
+		if sketch.Count() > 0 {
+			stats.LatencyByPaymentMethod[method] = entity.NewLatencyPercentiles(sketch)
+		}
+	}
+
+	low := latency.CountAtMost(uc.thresholds.LowMs)
+	medium := latency.CountAtMost(uc.thresholds.MediumMs)
+	stats.LatencyLow = low
+	stats.LatencyMedium = medium - low
+	stats.LatencyHigh = latency.Count() - medium
 
 	if stats.FinalizedCount > 0 {
-		stats.AvgLatencyMs = latencySum / float64(stats.FinalizedCount)
+		stats.AvgLatencyMs = float64(total.LatencySumMs) / float64(stats.FinalizedCount)
 	}
 
 	return stats, nil
@@ -13,30 +13,45 @@ import (
 // Feature: dashboard-pagination-metrics, Property 3: Stats aggregation correctness
 // Validates: Requirements 3.2, 3.3, 3.4, 3.5
 
-// statsMockRepo is a hand-written mock implementing TransactionRepository
-// that returns a pre-configured slice of transactions from FindAll.
+// statsMockRepo is a hand-written mock implementing TransactionStatsRepository
+// that counts a pre-configured slice of transactions.
 type statsMockRepo struct {
 	transactions []entity.TransactionEntity
 }
 
-func (m *statsMockRepo) Save(_ context.Context, _ *entity.TransactionEntity) error {
+func (m *statsMockRepo) Add(_ context.Context, _ *entity.TransactionEntity, _ entity.TransactionCounters) error {
 	return nil
 }
 
-func (m *statsMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) error {
-	return nil
-}
+func (m *statsMockRepo) FindHours(_ context.Context, from, to time.Time) ([]entity.HourlyTransactionCounters, error) {
+	counters := map[time.Time]entity.TransactionCounters{}
+	for i := range m.transactions {
+		hour := m.transactions[i].CreatedAt.UTC().Truncate(time.Hour)
+		if hour.Before(from) || hour.After(to) {
+			continue
+		}
+		c := counters[hour]
+		c.Add(entity.CountTransaction(&m.transactions[i]))
+		counters[hour] = c
+	}
 
-func (m *statsMockRepo) FindByID(_ context.Context, _ string) (*entity.TransactionEntity, error) {
-	return nil, nil
+	hours := make([]entity.HourlyTransactionCounters, 0, len(counters))
+	for hour, c := range counters {
+		hours = append(hours, entity.HourlyTransactionCounters{Hour: hour, Counters: c})
+	}
+	return hours, nil
 }
 
-func (m *statsMockRepo) FindAllPaginated(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
-	return nil, "", nil
+func (m *statsMockRepo) Total(_ context.Context) (entity.TransactionCounters, error) {
+	total := entity.NewTransactionCounters()
+	for i := range m.transactions {
+		total.Add(entity.CountTransaction(&m.transactions[i]))
+	}
+	return total, nil
 }
 
-func (m *statsMockRepo) FindAll(_ context.Context) ([]entity.TransactionEntity, error) {
-	return m.transactions, nil
+func (m *statsMockRepo) Replace(_ context.Context, _ []entity.HourlyTransactionCounters) error {
+	return nil
 }
 
 // genStatus draws a random TransactionStatus.
@@ -130,7 +145,7 @@ func TestProperty_StatsAggregationCorrectness(t *testing.T) {
 
 		// Execute the use case
 		repo := &statsMockRepo{transactions: txns}
-		uc := NewGetTransactionStatsUseCase(repo)
+		uc := NewGetTransactionStatsUseCase(repo, entity.DefaultLatencyTierThresholds)
 		stats, err := uc.Execute(context.Background())
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -152,9 +167,10 @@ func TestProperty_StatsAggregationCorrectness(t *testing.T) {
 		wantPending := 0
 		wantPM := make(map[entity.PaymentMethod]int)
 		wantFinalizedCount := 0
-		wantLatencyLow := 0
-		wantLatencyMedium := 0
-		wantLatencyHigh := 0
+		// The tiers are read from the latency sketch, so a latency within its
+		// relative accuracy of a threshold may be counted on either side
+		minAtMostLow, maxAtMostLow := 0, 0
+		minAtMostMedium, maxAtMostMedium := 0, 0
 		var wantLatencySum float64
 
 		for i := range txns {
@@ -186,13 +202,18 @@ func TestProperty_StatsAggregationCorrectness(t *testing.T) {
 				wantLatencySum += latencyMs
 				wantFinalizedCount++
 
-				switch {
-				case latencyMs <= entity.LatencyLowThresholdMs:
-					wantLatencyLow++
-				case latencyMs <= entity.LatencyMediumThresholdMs:
-					wantLatencyMedium++
-				default:
-					wantLatencyHigh++
+				accuracy := entity.LatencySketchRelativeAccuracy
+				if latencyMs <= entity.LatencyLowThresholdMs*(1-accuracy) {
+					minAtMostLow++
+				}
+				if latencyMs <= entity.LatencyLowThresholdMs*(1+accuracy) {
+					maxAtMostLow++
+				}
+				if latencyMs <= entity.LatencyMediumThresholdMs*(1-accuracy) {
+					minAtMostMedium++
+				}
+				if latencyMs <= entity.LatencyMediumThresholdMs*(1+accuracy) {
+					maxAtMostMedium++
 				}
 			}
 		}
@@ -249,14 +270,15 @@ func TestProperty_StatsAggregationCorrectness(t *testing.T) {
 		if stats.FinalizedCount != wantFinalizedCount {
 			t.Fatalf("FinalizedCount: got %d, want %d", stats.FinalizedCount, wantFinalizedCount)
 		}
-		if stats.LatencyLow != wantLatencyLow {
-			t.Fatalf("LatencyLow: got %d, want %d", stats.LatencyLow, wantLatencyLow)
+		if stats.LatencyLow < minAtMostLow || stats.LatencyLow > maxAtMostLow {
+			t.Fatalf("LatencyLow: got %d, want %d to %d", stats.LatencyLow, minAtMostLow, maxAtMostLow)
 		}
-		if stats.LatencyMedium != wantLatencyMedium {
-			t.Fatalf("LatencyMedium: got %d, want %d", stats.LatencyMedium, wantLatencyMedium)
+		if atMostMedium := stats.LatencyLow + stats.LatencyMedium; atMostMedium < minAtMostMedium || atMostMedium > maxAtMostMedium {
+			t.Fatalf("LatencyLow+LatencyMedium: got %d, want %d to %d", atMostMedium, minAtMostMedium, maxAtMostMedium)
 		}
-		if stats.LatencyHigh != wantLatencyHigh {
-			t.Fatalf("LatencyHigh: got %d, want %d", stats.LatencyHigh, wantLatencyHigh)
+		if stats.LatencyLow+stats.LatencyMedium+stats.LatencyHigh != wantFinalizedCount {
+			t.Fatalf("latency tiers (%d+%d+%d) do not sum to FinalizedCount %d",
+				stats.LatencyLow, stats.LatencyMedium, stats.LatencyHigh, wantFinalizedCount)
 		}
 		if math.Abs(stats.AvgLatencyMs-wantAvgLatency) > 0.01 {
 			t.Fatalf("AvgLatencyMs: got %f, want %f", stats.AvgLatencyMs, wantAvgLatency)
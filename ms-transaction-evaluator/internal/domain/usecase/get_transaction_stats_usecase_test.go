@@ -3,35 +3,32 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"ms-transaction-evaluator/internal/domain/entity"
 	"testing"
 	"time"
 )
 
-// statsErrorMockRepo is a mock that returns an error from FindAll.
+// statsErrorMockRepo is a mock that returns an error from every read.
 type statsErrorMockRepo struct {
 	err error
 }
 
-func (m *statsErrorMockRepo) Save(_ context.Context, _ *entity.TransactionEntity) error {
-	return nil
+func (m *statsErrorMockRepo) Add(_ context.Context, _ *entity.TransactionEntity, _ entity.TransactionCounters) error {
+	return m.err
 }
 
-func (m *statsErrorMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) error {
-	return nil
-}
-
-func (m *statsErrorMockRepo) FindByID(_ context.Context, _ string) (*entity.TransactionEntity, error) {
-	return nil, nil
+func (m *statsErrorMockRepo) FindHours(_ context.Context, _, _ time.Time) ([]entity.HourlyTransactionCounters, error) {
+	return nil, m.err
 }
 
-func (m *statsErrorMockRepo) FindAllPaginated(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
-	return nil, "", nil
+func (m *statsErrorMockRepo) Total(_ context.Context) (entity.TransactionCounters, error) {
+	return entity.TransactionCounters{}, m.err
 }
 
-func (m *statsErrorMockRepo) FindAll(_ context.Context) ([]entity.TransactionEntity, error) {
-	return nil, m.err
+func (m *statsErrorMockRepo) Replace(_ context.Context, _ []entity.HourlyTransactionCounters) error {
+	return m.err
 }
 
 func TestGetTransactionStatsUseCase_Execute(t *testing.T) {
@@ -214,7 +211,7 @@ func TestGetTransactionStatsUseCase_Execute(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := &statsMockRepo{transactions: tc.transactions}
-			uc := NewGetTransactionStatsUseCase(repo)
+			uc := NewGetTransactionStatsUseCase(repo, entity.DefaultLatencyTierThresholds)
 
 			stats, err := uc.Execute(context.Background())
 			if err != nil {
@@ -273,9 +270,9 @@ func TestGetTransactionStatsUseCase_Execute(t *testing.T) {
 }
 
 func TestGetTransactionStatsUseCase_Execute_ErrorPropagation(t *testing.T) {
-	repoErr := errors.New("dynamodb scan failed")
+	repoErr := errors.New("dynamodb query failed")
 	repo := &statsErrorMockRepo{err: repoErr}
-	uc := NewGetTransactionStatsUseCase(repo)
+	uc := NewGetTransactionStatsUseCase(repo, entity.DefaultLatencyTierThresholds)
 
 	stats, err := uc.Execute(context.Background())
 	if err == nil {
@@ -288,3 +285,81 @@ func TestGetTransactionStatsUseCase_Execute_ErrorPropagation(t *testing.T) {
 		t.Errorf("expected nil stats on error, got %+v", stats)
 	}
 }
+
+func TestGetTransactionStatsUseCase_Execute_LatencyBreakdown(t *testing.T) {
+	now := time.Now()
+	newFinalized := func(id string, status entity.TransactionStatus, method entity.PaymentMethod, path entity.DecisionPath, latencyMs int) entity.TransactionEntity {
+		createdAt := now.Add(-time.Hour)
+		finalizedAt := createdAt.Add(time.Duration(latencyMs) * time.Millisecond)
+		return entity.TransactionEntity{
+			ID:            id,
+			PaymentMethod: method,
+			Status:        status,
+			DecisionPath:  path,
+			CreatedAt:     createdAt,
+			UpdatedAt:     finalizedAt,
+			FinalizedAt:   &finalizedAt,
+		}
+	}
+
+	var transactions []entity.TransactionEntity
+	for i := range 100 {
+		// 1 to 100 ms, approved directly by card
+		transactions = append(transactions, newFinalized(fmt.Sprintf("txn_a%d", i), entity.APPROVED, entity.CARD, entity.DecisionPathDirect, i+1))
+	}
+	for i := range 10 {
+		// 1 to 10 s, declined after a fraud check, paid in crypto
+		transactions = append(transactions, newFinalized(fmt.Sprintf("txn_d%d", i), entity.DECLINED, entity.CRYPTO, entity.DecisionPathFraudCheck, (i+1)*1000))
+	}
+	transactions = append(transactions, newFinalized("txn_e", entity.EXPIRED, entity.CARD, "", 60_000))
+
+	thresholds := entity.LatencyTierThresholds{LowMs: 500, MediumMs: 4500}
+	uc := NewGetTransactionStatsUseCase(&statsMockRepo{transactions: transactions}, thresholds)
+
+	stats, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	within := func(got, want float64) bool {
+		return math.Abs(got-want) <= want*entity.LatencySketchRelativeAccuracy+1e-9
+	}
+
+	if stats.Latency.Count != 111 || !within(stats.Latency.P50Ms, 56) || !within(stats.Latency.P90Ms, 100) || !within(stats.Latency.P99Ms, 9000) {
+		t.Errorf("unexpected overall percentiles: %+v", stats.Latency)
+	}
+
+	approved := stats.LatencyByStatus[entity.APPROVED]
+	if approved.Count != 100 || !within(approved.P50Ms, 50) || !within(approved.P95Ms, 95) || !within(approved.P99Ms, 99) {
+		t.Errorf("unexpected APPROVED percentiles: %+v", approved)
+	}
+	declined := stats.LatencyByStatus[entity.DECLINED]
+	if declined.Count != 10 || !within(declined.P50Ms, 5000) || !within(declined.P90Ms, 9000) {
+		t.Errorf("unexpected DECLINED percentiles: %+v", declined)
+	}
+	if _, ok := stats.LatencyByStatus[entity.PENDING]; ok || len(stats.LatencyByStatus) != 3 {
+		t.Errorf("expected percentiles of the 3 final statuses, got %v", stats.LatencyByStatus)
+	}
+
+	if card := stats.LatencyByPaymentMethod[entity.CARD]; card.Count != 101 || !within(card.P99Ms, 100) {
+		t.Errorf("unexpected CARD percentiles: %+v", card)
+	}
+	if crypto := stats.LatencyByPaymentMethod[entity.CRYPTO]; crypto.Count != 10 || !within(crypto.P50Ms, 5000) {
+		t.Errorf("unexpected CRYPTO percentiles: %+v", crypto)
+	}
+
+	// Tiers use the configured thresholds: 0.5 s and 4.5 s
+	if stats.LatencyThresholds != thresholds {
+		t.Errorf("expected thresholds %+v, got %+v", thresholds, stats.LatencyThresholds)
+	}
+	if stats.LatencyTierRelativeError != entity.LatencySketchRelativeAccuracy {
+		t.Errorf("expected the tier error bound %f, got %f", entity.LatencySketchRelativeAccuracy, stats.LatencyTierRelativeError)
+	}
+	if stats.LatencyLow != 100 || stats.LatencyMedium != 4 || stats.LatencyHigh != 7 {
+		t.Errorf("expected tiers 100/4/7, got %d/%d/%d", stats.LatencyLow, stats.LatencyMedium, stats.LatencyHigh)
+	}
+
+	if stats.DirectDecisions != 100 || stats.FraudCheckDecisions != 10 {
+		t.Errorf("expected 100 direct and 10 fraud-check decisions, got %d and %d", stats.DirectDecisions, stats.FraudCheckDecisions)
+	}
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultHourlyTimeseriesRange and DefaultDailyTimeseriesRange are how far
+	// back a time series starts when from is not given.
+	DefaultHourlyTimeseriesRange = 24 * time.Hour
+	DefaultDailyTimeseriesRange  = 30 * 24 * time.Hour
+	// MaxTimeseriesBuckets is the most buckets a time series may have: 31 days
+	// of hours.
+	MaxTimeseriesBuckets = 31 * 24
+)
+
+// TransactionTimeseriesInput holds the query parameters of
+// GET /transactions/stats/timeseries as received.
+type TransactionTimeseriesInput struct {
+	// From and To are inclusive RFC 3339 timestamps or dates (2006-01-02); a date
+	// covers the whole UTC day. To defaults to now and From to a range before To
+	// that depends on the interval.
+	From string
+	To   string
+	// Interval is hour (default) or day.
+	Interval string
+	// GroupBy is empty, status, payment_method or currency.
+	GroupBy string
+}
+
+// GetTransactionTimeseriesUseCase computes bucketed transaction metrics from the
+// hourly stats counters.
+type GetTransactionTimeseriesUseCase struct {
+	statsRepo repository.TransactionStatsRepository
+	now       func() time.Time
+}
+
+// NewGetTransactionTimeseriesUseCase creates a new GetTransactionTimeseriesUseCase.
+func NewGetTransactionTimeseriesUseCase(statsRepo repository.TransactionStatsRepository) *GetTransactionTimeseriesUseCase {
+	return &GetTransactionTimeseriesUseCase{statsRepo: statsRepo, now: time.Now}
+}
+
+// Execute validates the input and sums the hourly counters into buckets. From
+// and To are widened to the start and end of the buckets holding them.
+func (uc *GetTransactionTimeseriesUseCase) Execute(ctx context.Context, input TransactionTimeseriesInput) (*entity.TransactionTimeseries, error) {
+	series, err := uc.toTimeseries(input)
+	if err != nil {
+		return nil, err
+	}
+
+	width := series.Interval.Duration()
+	hours, err := uc.statsRepo.FindHours(ctx, series.From, series.To.Add(-time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make([]entity.TransactionCounters, int(series.To.Sub(series.From)/width))
+	for _, hour := range hours {
+		i := int(hour.Hour.Sub(series.From) / width)
+		if hour.Hour.Before(series.From) || i >= len(counters) {
+			continue
+		}
+		counters[i].Add(hour.Counters)
+	}
+
+	series.Buckets = make([]entity.TransactionTimeseriesBucket, len(counters))
+	for i, c := range counters {
+		series.Buckets[i] = toTimeseriesBucket(series.From.Add(time.Duration(i)*width), c, series.GroupBy)
+	}
+
+	return series, nil
+}
+
+// toTimeseries parses the input into an empty series whose From is the start of
+// its first bucket and To the end of its last.
+func (uc *GetTransactionTimeseriesUseCase) toTimeseries(in TransactionTimeseriesInput) (*entity.TransactionTimeseries, error) {
+	series := &entity.TransactionTimeseries{Interval: entity.TimeseriesHour}
+
+	switch entity.TimeseriesInterval(strings.ToLower(in.Interval)) {
+	case "", entity.TimeseriesHour:
+	case entity.TimeseriesDay:
+		series.Interval = entity.TimeseriesDay
+	default:
+		return nil, fmt.Errorf("%w: interval must be hour or day", ErrInvalidTimeseriesQuery)
+	}
+
+	series.GroupBy = entity.TimeseriesGroupBy(strings.ToLower(in.GroupBy))
+	switch series.GroupBy {
+	case entity.TimeseriesGroupByNone, entity.TimeseriesGroupByStatus, entity.TimeseriesGroupByPaymentMethod, entity.TimeseriesGroupByCurrency:
+	default:
+		return nil, fmt.Errorf("%w: group_by must be status, payment_method or currency", ErrInvalidTimeseriesQuery)
+	}
+
+	to := uc.now().UTC()
+	if in.To != "" {
+		parsed, err := parseCreatedBound("to", in.To, true)
+		if err != nil {
+			return nil, fmt.Errorf("%w: to must be an RFC 3339 timestamp or a YYYY-MM-DD date", ErrInvalidTimeseriesQuery)
+		}
+		to = *parsed
+	}
+
+	from := to.Add(-DefaultHourlyTimeseriesRange)
+	if series.Interval == entity.TimeseriesDay {
+		from = to.Add(-DefaultDailyTimeseriesRange)
+	}
+	if in.From != "" {
+		parsed, err := parseCreatedBound("from", in.From, false)
+		if err != nil {
+			return nil, fmt.Errorf("%w: from must be an RFC 3339 timestamp or a YYYY-MM-DD date", ErrInvalidTimeseriesQuery)
+		}
+		from = *parsed
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("%w: from is after to", ErrInvalidTimeseriesQuery)
+	}
+
+	// Days are UTC days, which Truncate aligns to as Unix time starts at midnight
+	width := series.Interval.Duration()
+	series.From = from.Truncate(width)
+	series.To = to.Truncate(width).Add(width)
+	if buckets := int(series.To.Sub(series.From) / width); buckets > MaxTimeseriesBuckets {
+		return nil, fmt.Errorf("%w: %d buckets requested, at most %d are allowed", ErrInvalidTimeseriesQuery, buckets, MaxTimeseriesBuckets)
+	}
+
+	return series, nil
+}
+
+func toTimeseriesBucket(start time.Time, c entity.TransactionCounters, groupBy entity.TimeseriesGroupBy) entity.TransactionTimeseriesBucket {
+	bucket := entity.TransactionTimeseriesBucket{
+		Start:                        start,
+		TransactionTimeseriesMetrics: toTimeseriesMetrics(c.Created, c.Statuses, c.AmountsInCents, c.Finalized, c.Latency()),
+	}
+
+	if groupBy != entity.TimeseriesGroupByNone {
+		bucket.Groups = make(map[string]entity.TransactionTimeseriesMetrics)
+		switch groupBy {
+		case entity.TimeseriesGroupByStatus:
+			for status, n := range c.Statuses {
+				if n != 0 {
+					latency := c.LatencyByStatus[status]
+					statuses := map[entity.TransactionStatus]int{status: n}
+					bucket.Groups[string(status)] = toTimeseriesMetrics(n, statuses, c.AmountsByStatus[status], latency.Count(), latency)
+				}
+			}
+		case entity.TimeseriesGroupByPaymentMethod:
+			for method, n := range c.PaymentMethods {
+				if n != 0 {
+					latency := c.LatencyByPaymentMethod[method]
+					bucket.Groups[string(method)] = toTimeseriesMetrics(n, c.StatusesByPaymentMethod[method], c.AmountsByPaymentMethod[method], latency.Count(), latency)
+				}
+			}
+		case entity.TimeseriesGroupByCurrency:
+			for currency, n := range c.Currencies {
+				if n != 0 {
+					latency := c.LatencyByCurrency[currency]
+					amounts := map[entity.Currency]int64{currency: c.AmountsInCents[currency]}
+					bucket.Groups[string(currency)] = toTimeseriesMetrics(n, c.StatusesByCurrency[currency], amounts, latency.Count(), latency)
+				}
+			}
+		}
+	}
+
+	return bucket
+}
+
+// toTimeseriesMetrics computes the metrics of total transactions with the given
+// counts by status, amounts by currency, and finalized ones with their latency.
+func toTimeseriesMetrics(
+	total int,
+	statuses map[entity.TransactionStatus]int,
+	amounts map[entity.Currency]int64,
+	finalized int,
+	latency entity.LatencySketch,
+) entity.TransactionTimeseriesMetrics {
+	metrics := entity.TransactionTimeseriesMetrics{
+		Total:          total,
+		Approved:       statuses[entity.APPROVED],
+		Declined:       statuses[entity.DECLINED],
+		AmountsInCents: make(map[entity.Currency]int64),
+		FinalizedCount: finalized,
+		LatencyP50Ms:   latency.Quantile(0.50),
+		LatencyP95Ms:   latency.Quantile(0.95),
+		LatencyP99Ms:   latency.Quantile(0.99),
+	}
+
+	if decided := metrics.Approved + metrics.Declined; decided > 0 {
+		metrics.ApprovalRate = float64(metrics.Approved) / float64(decided)
+		metrics.DeclineRate = float64(metrics.Declined) / float64(decided)
+	}
+
+	for currency, amount := range amounts {
+		if amount != 0 {
+			metrics.AmountsInCents[currency] = amount
+		}
+	}
+
+	return metrics
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"testing"
+	"time"
+)
+
+func newTimeseriesTransaction(id string, createdAt time.Time, currency entity.Currency, status entity.TransactionStatus, latency time.Duration) entity.TransactionEntity {
+	transaction := entity.TransactionEntity{
+		ID:            id,
+		AmountInCents: 1000,
+		Currency:      currency,
+		PaymentMethod: entity.CARD,
+		Status:        status,
+		CreatedAt:     createdAt,
+		UpdatedAt:     createdAt,
+	}
+	if latency > 0 {
+		finalizedAt := createdAt.Add(latency)
+		transaction.FinalizedAt = &finalizedAt
+	}
+	return transaction
+}
+
+func TestGetTransactionTimeseriesUseCase_Execute(t *testing.T) {
+	now := time.Date(2025, 1, 10, 15, 20, 0, 0, time.UTC)
+	transactions := []entity.TransactionEntity{
+		newTimeseriesTransaction("txn_1", now.Add(-10*time.Minute), entity.USD, entity.APPROVED, 200*time.Millisecond),
+		newTimeseriesTransaction("txn_2", now.Add(-15*time.Minute), entity.USD, entity.APPROVED, 400*time.Millisecond),
+		newTimeseriesTransaction("txn_3", now.Add(-18*time.Minute), entity.EUR, entity.DECLINED, 3*time.Second),
+		newTimeseriesTransaction("txn_4", now.Add(-3*time.Hour), entity.COP, entity.PENDING, 0),
+		newTimeseriesTransaction("txn_5", now.Add(-48*time.Hour), entity.USD, entity.APPROVED, time.Second),
+	}
+	uc := NewGetTransactionTimeseriesUseCase(&statsMockRepo{transactions: transactions})
+	uc.now = func() time.Time { return now }
+
+	t.Run("defaults to the hours of the last day", func(t *testing.T) {
+		series, err := uc.Execute(context.Background(), TransactionTimeseriesInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if series.Interval != entity.TimeseriesHour || len(series.Buckets) != 25 {
+			t.Fatalf("expected 25 hourly buckets, got %s with %d", series.Interval, len(series.Buckets))
+		}
+		if want := time.Date(2025, 1, 9, 15, 0, 0, 0, time.UTC); !series.From.Equal(want) {
+			t.Errorf("expected from %v, got %v", want, series.From)
+		}
+		if want := time.Date(2025, 1, 10, 16, 0, 0, 0, time.UTC); !series.To.Equal(want) {
+			t.Errorf("expected to %v, got %v", want, series.To)
+		}
+
+		last := series.Buckets[24]
+		if !last.Start.Equal(now.Truncate(time.Hour)) || last.Total != 3 || last.Approved != 2 || last.Declined != 1 {
+			t.Errorf("unexpected last bucket: %+v", last)
+		}
+		if last.ApprovalRate != 2.0/3 || last.DeclineRate != 1.0/3 {
+			t.Errorf("expected rates 2/3 and 1/3, got %f and %f", last.ApprovalRate, last.DeclineRate)
+		}
+		if last.AmountsInCents[entity.USD] != 2000 || last.AmountsInCents[entity.EUR] != 1000 {
+			t.Errorf("unexpected amounts: %v", last.AmountsInCents)
+		}
+		// The median of 200, 400 and 3000 ms is 400 ms, estimated within 2%
+		if last.FinalizedCount != 3 || math.Abs(last.LatencyP50Ms-400) > 8 || last.LatencyP99Ms < last.LatencyP50Ms {
+			t.Errorf("unexpected latency: %d finalized, p50 %f, p99 %f", last.FinalizedCount, last.LatencyP50Ms, last.LatencyP99Ms)
+		}
+		if last.Groups != nil {
+			t.Errorf("expected no groups without group_by, got %v", last.Groups)
+		}
+
+		if series.Buckets[21].Total != 1 || series.Buckets[21].ApprovalRate != 0 {
+			t.Errorf("expected the pending transaction alone 3 hours ago, got %+v", series.Buckets[21])
+		}
+		if series.Buckets[0].Total != 0 || series.Buckets[0].AmountsInCents == nil {
+			t.Errorf("expected an empty first bucket, got %+v", series.Buckets[0])
+		}
+	})
+
+	t.Run("sums whole days and groups by status", func(t *testing.T) {
+		series, err := uc.Execute(context.Background(), TransactionTimeseriesInput{
+			From:     "2025-01-08",
+			To:       "2025-01-10",
+			Interval: "day",
+			GroupBy:  "status",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(series.Buckets) != 3 {
+			t.Fatalf("expected 3 daily buckets, got %d", len(series.Buckets))
+		}
+		totals := []int{series.Buckets[0].Total, series.Buckets[1].Total, series.Buckets[2].Total}
+		if totals[0] != 1 || totals[1] != 0 || totals[2] != 4 {
+			t.Errorf("expected totals [1 0 4], got %v", totals)
+		}
+		groups := series.Buckets[2].Groups
+		if len(groups) != 3 || groups["APPROVED"].Total != 2 || groups["DECLINED"].Total != 1 || groups["PENDING"].Total != 1 {
+			t.Errorf("unexpected groups: %v", groups)
+		}
+		approved := groups["APPROVED"]
+		if approved.Approved != 2 || approved.ApprovalRate != 1 || approved.AmountsInCents[entity.USD] != 2000 || approved.FinalizedCount != 2 {
+			t.Errorf("unexpected APPROVED group: %+v", approved)
+		}
+		// The only DECLINED transaction took 3 s, estimated within 2%
+		declined := groups["DECLINED"]
+		if declined.DeclineRate != 1 || declined.AmountsInCents[entity.EUR] != 1000 || math.Abs(declined.LatencyP50Ms-3000) > 60 {
+			t.Errorf("unexpected DECLINED group: %+v", declined)
+		}
+		if pending := groups["PENDING"]; pending.FinalizedCount != 0 || pending.ApprovalRate != 0 || pending.AmountsInCents[entity.COP] != 1000 {
+			t.Errorf("unexpected PENDING group: %+v", pending)
+		}
+		if series.Buckets[1].Groups == nil || len(series.Buckets[1].Groups) != 0 {
+			t.Errorf("expected empty groups for an empty bucket, got %v", series.Buckets[1].Groups)
+		}
+	})
+
+	t.Run("groups by currency", func(t *testing.T) {
+		series, err := uc.Execute(context.Background(), TransactionTimeseriesInput{From: "2025-01-10T00:00:00Z", Interval: "DAY", GroupBy: "currency"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(series.Buckets) != 1 {
+			t.Fatalf("expected 1 daily bucket, got %d", len(series.Buckets))
+		}
+		groups := series.Buckets[0].Groups
+		if groups["USD"].Total != 2 || groups["EUR"].Total != 1 || groups["COP"].Total != 1 {
+			t.Errorf("unexpected groups: %v", groups)
+		}
+		if usd := groups["USD"]; usd.Approved != 2 || usd.AmountsInCents[entity.USD] != 2000 || len(usd.AmountsInCents) != 1 || usd.FinalizedCount != 2 {
+			t.Errorf("unexpected USD group: %+v", usd)
+		}
+		if eur := groups["EUR"]; eur.Declined != 1 || eur.DeclineRate != 1 || eur.FinalizedCount != 1 {
+			t.Errorf("unexpected EUR group: %+v", eur)
+		}
+	})
+
+	t.Run("groups by payment method", func(t *testing.T) {
+		series, err := uc.Execute(context.Background(), TransactionTimeseriesInput{From: "2025-01-10T00:00:00Z", Interval: "day", GroupBy: "payment_method"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		groups := series.Buckets[0].Groups
+		card := groups["CARD"]
+		if len(groups) != 1 || card.Total != 4 || card.Approved != 2 || card.Declined != 1 || card.FinalizedCount != 3 {
+			t.Errorf("unexpected groups: %+v", groups)
+		}
+		if card.AmountsInCents[entity.USD] != 2000 || card.AmountsInCents[entity.EUR] != 1000 || card.AmountsInCents[entity.COP] != 1000 {
+			t.Errorf("unexpected CARD amounts: %v", card.AmountsInCents)
+		}
+	})
+
+	t.Run("rejects invalid queries", func(t *testing.T) {
+		inputs := map[string]TransactionTimeseriesInput{
+			"unknown interval": {Interval: "week"},
+			"unknown group_by": {GroupBy: "customer"},
+			"malformed from":   {From: "yesterday"},
+			"malformed to":     {To: "2025-13-01"},
+			"from after to":    {From: "2025-01-10", To: "2025-01-09"},
+			"too many buckets": {From: "2024-01-01", To: "2025-01-01"},
+		}
+		for name, input := range inputs {
+			if _, err := uc.Execute(context.Background(), input); !errors.Is(err, ErrInvalidTimeseriesQuery) {
+				t.Errorf("%s: expected ErrInvalidTimeseriesQuery, got %v", name, err)
+			}
+		}
+
+		if _, err := uc.Execute(context.Background(), TransactionTimeseriesInput{From: "2024-01-01", To: "2025-01-01", Interval: "day"}); err != nil {
+			t.Errorf("expected a year of days to be allowed, got %v", err)
+		}
+	})
+
+	t.Run("returns the repository error", func(t *testing.T) {
+		repoErr := errors.New("DynamoDB query failed")
+		uc := NewGetTransactionTimeseriesUseCase(&statsErrorMockRepo{err: repoErr})
+
+		if _, err := uc.Execute(context.Background(), TransactionTimeseriesInput{}); !errors.Is(err, repoErr) {
+			t.Errorf("expected %v, got %v", repoErr, err)
+		}
+	})
+}
@@ -18,8 +18,8 @@ func (m *getTransactionMockRepo) Save(_ context.Context, _ *entity.TransactionEn
 	return nil
 }
 
-func (m *getTransactionMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) error {
-	return nil
+func (m *getTransactionMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time, _ entity.DecisionPath) (*entity.TransactionEntity, error) {
+	return &entity.TransactionEntity{Status: entity.PENDING}, nil
 }
 
 func (m *getTransactionMockRepo) FindByID(ctx context.Context, id string) (*entity.TransactionEntity, error) {
@@ -29,7 +29,7 @@ func (m *getTransactionMockRepo) FindByID(ctx context.Context, id string) (*enti
 	return nil, nil
 }
 
-func (m *getTransactionMockRepo) FindAllPaginated(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
+func (m *getTransactionMockRepo) FindAllPaginated(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
 	return nil, "", nil
 }
 
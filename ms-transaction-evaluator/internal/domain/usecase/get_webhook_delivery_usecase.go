@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+)
+
+// GetWebhookDeliveryUseCase retrieves a webhook delivery with its log.
+type GetWebhookDeliveryUseCase struct {
+	deliveryRepo repository.WebhookDeliveryRepository
+}
+
+// NewGetWebhookDeliveryUseCase creates a new use case.
+func NewGetWebhookDeliveryUseCase(deliveryRepo repository.WebhookDeliveryRepository) *GetWebhookDeliveryUseCase {
+	return &GetWebhookDeliveryUseCase{deliveryRepo: deliveryRepo}
+}
+
+// Execute returns the delivery.
+func (uc *GetWebhookDeliveryUseCase) Execute(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	return findWebhookDelivery(ctx, uc.deliveryRepo, id)
+}
+
+// findWebhookDelivery returns the delivery or ErrWebhookDeliveryNotFound.
+func findWebhookDelivery(ctx context.Context, deliveryRepo repository.WebhookDeliveryRepository, id string) (*entity.WebhookDelivery, error) {
+	delivery, err := deliveryRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWebhookStoreFailed, err)
+	}
+	if delivery == nil {
+		return nil, fmt.Errorf("%w: %s", ErrWebhookDeliveryNotFound, id)
+	}
+	return delivery, nil
+}
@@ -29,15 +29,17 @@ func (m *paginatedMockRepo) Save(_ context.Context, _ *entity.TransactionEntity)
 	return nil
 }
 
-func (m *paginatedMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) error {
-	return nil
+func (m *paginatedMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time, _ entity.DecisionPath) (*entity.TransactionEntity, error) {
+	return &entity.TransactionEntity{Status: entity.PENDING}, nil
 }
 
 func (m *paginatedMockRepo) FindByID(_ context.Context, _ string) (*entity.TransactionEntity, error) {
 	return nil, nil
 }
 
-func (m *paginatedMockRepo) FindAllPaginated(_ context.Context, limit int, _ string) ([]entity.TransactionEntity, string, error) {
+func (m *paginatedMockRepo) FindAllPaginated(_ context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
+	limit := query.Limit
+
 	// Copy and sort by created_at descending (simulates real DB behavior)
 	sorted := make([]entity.TransactionEntity, len(m.transactions))
 	copy(sorted, m.transactions)
@@ -98,7 +100,7 @@ func TestProperty_PaginatedListRespectsLimitAndOrdering(t *testing.T) {
 		repo := &paginatedMockRepo{transactions: txns}
 		uc := NewListTransactionsUseCase(repo)
 
-		result, _, err := uc.Execute(context.Background(), limit, "")
+		result, _, err := uc.Execute(context.Background(), ListTransactionsInput{Limit: limit})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -136,8 +138,8 @@ func (m *cursorPaginatedMockRepo) Save(_ context.Context, _ *entity.TransactionE
 	return nil
 }
 
-func (m *cursorPaginatedMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) error {
-	return nil
+func (m *cursorPaginatedMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time, _ entity.DecisionPath) (*entity.TransactionEntity, error) {
+	return &entity.TransactionEntity{Status: entity.PENDING}, nil
 }
 
 func (m *cursorPaginatedMockRepo) FindByID(_ context.Context, _ string) (*entity.TransactionEntity, error) {
@@ -149,7 +151,9 @@ type testPaginationCursor struct {
 	CreatedAt string `json:"created_at"`
 }
 
-func (m *cursorPaginatedMockRepo) FindAllPaginated(_ context.Context, limit int, cursor string) ([]entity.TransactionEntity, string, error) {
+func (m *cursorPaginatedMockRepo) FindAllPaginated(_ context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
+	limit, cursor := query.Limit, query.Cursor
+
 	// Sort all transactions by created_at descending (matches real adapter)
 	sorted := make([]entity.TransactionEntity, len(m.transactions))
 	copy(sorted, m.transactions)
@@ -256,7 +260,7 @@ func TestProperty_CursorPaginationYieldsCompleteCoverage(t *testing.T) {
 		maxPages := numTxns + 2 // safety bound to prevent infinite loops
 
 		for page := 0; page < maxPages; page++ {
-			result, nextCursor, err := uc.Execute(context.Background(), pageSize, cursor)
+			result, nextCursor, err := uc.Execute(context.Background(), ListTransactionsInput{Limit: pageSize, Cursor: cursor})
 			if err != nil {
 				t.Fatalf("unexpected error on page %d: %v", page, err)
 			}
@@ -309,7 +313,7 @@ func TestProperty_InvalidLimitValuesProduceErrors(t *testing.T) {
 			// Generate limit values ≤ 0 (includes zero and negative numbers)
 			limit := rapid.IntRange(-1000, 0).Draw(t, "invalidLimit")
 
-			_, _, err := uc.Execute(context.Background(), limit, "")
+			_, _, err := uc.Execute(context.Background(), ListTransactionsInput{Limit: limit})
 			if err == nil {
 				t.Fatalf("expected error for limit %d, got nil", limit)
 			}
@@ -324,7 +328,7 @@ func TestProperty_InvalidLimitValuesProduceErrors(t *testing.T) {
 			// Generate limit values > 100
 			limit := rapid.IntRange(101, 10000).Draw(t, "invalidLimit")
 
-			_, _, err := uc.Execute(context.Background(), limit, "")
+			_, _, err := uc.Execute(context.Background(), ListTransactionsInput{Limit: limit})
 			if err == nil {
 				t.Fatalf("expected error for limit %d, got nil", limit)
 			}
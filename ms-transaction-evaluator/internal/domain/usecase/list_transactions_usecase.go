@@ -2,8 +2,13 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"ms-transaction-evaluator/internal/domain/entity"
 	"ms-transaction-evaluator/internal/domain/repository"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const (
@@ -11,7 +16,28 @@ const (
 	maxLimit = 100
 )
 
-// ListTransactionsUseCase retrieves a paginated list of transactions.
+// ListTransactionsInput holds the query parameters of GET /transactions as
+// received. Empty fields are not filtered on.
+type ListTransactionsInput struct {
+	Limit  int
+	Cursor string
+	// Status, PaymentMethod and Currency are matched case-insensitively.
+	Status        string
+	PaymentMethod string
+	Currency      string
+	CustomerID    string
+	// MinAmountInCents and MaxAmountInCents are inclusive bounds.
+	MinAmountInCents string
+	MaxAmountInCents string
+	// CreatedFrom and CreatedTo are inclusive RFC 3339 timestamps or dates
+	// (2006-01-02); a date covers the whole UTC day.
+	CreatedFrom string
+	CreatedTo   string
+	// Order is asc or desc by created_at, desc by default.
+	Order string
+}
+
+// ListTransactionsUseCase retrieves a filtered, paginated list of transactions.
 type ListTransactionsUseCase struct {
 	transactionRepo repository.TransactionRepository
 }
@@ -21,16 +47,128 @@ func NewListTransactionsUseCase(repo repository.TransactionRepository) *ListTran
 	return &ListTransactionsUseCase{transactionRepo: repo}
 }
 
-// Execute retrieves a paginated list of transactions sorted by created_at descending.
-func (uc *ListTransactionsUseCase) Execute(ctx context.Context, limit int, cursor string) ([]entity.TransactionEntity, string, error) {
-	if limit < minLimit || limit > maxLimit {
+// Execute validates the input and retrieves a page of the matching transactions
+// sorted by created_at, newest first unless the order is asc.
+func (uc *ListTransactionsUseCase) Execute(ctx context.Context, input ListTransactionsInput) ([]entity.TransactionEntity, string, error) {
+	if input.Limit < minLimit || input.Limit > maxLimit {
 		return nil, "", ErrInvalidLimit
 	}
 
-	transactions, nextCursor, err := uc.transactionRepo.FindAllPaginated(ctx, limit, cursor)
+	query, err := input.toQuery()
+	if err != nil {
+		return nil, "", err
+	}
+
+	transactions, nextCursor, err := uc.transactionRepo.FindAllPaginated(ctx, query)
 	if err != nil {
+		if errors.Is(err, entity.ErrCursorMalformed) {
+			return nil, "", fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
 		return nil, "", err
 	}
 
 	return transactions, nextCursor, nil
 }
+
+func (in ListTransactionsInput) toQuery() (entity.TransactionQuery, error) {
+	query := entity.TransactionQuery{
+		Order:  entity.SortDescending,
+		Limit:  in.Limit,
+		Cursor: in.Cursor,
+	}
+	filter := &query.Filter
+
+	if in.Status != "" {
+		filter.Status = entity.TransactionStatus(strings.ToUpper(in.Status))
+		switch filter.Status {
+		case entity.PENDING, entity.APPROVED, entity.DECLINED, entity.EXPIRED, entity.NEEDS_REVIEW:
+		default:
+			return entity.TransactionQuery{}, fmt.Errorf("%w: unknown status %q", ErrInvalidTransactionFilter, in.Status)
+		}
+	}
+
+	if in.PaymentMethod != "" {
+		filter.PaymentMethod = entity.PaymentMethod(strings.ToUpper(in.PaymentMethod))
+		switch filter.PaymentMethod {
+		case entity.CARD, entity.BANK_TRANSFER, entity.CRYPTO:
+		default:
+			return entity.TransactionQuery{}, fmt.Errorf("%w: unknown payment_method %q", ErrInvalidTransactionFilter, in.PaymentMethod)
+		}
+	}
+
+	if in.Currency != "" {
+		filter.Currency = entity.Currency(strings.ToUpper(in.Currency))
+		switch filter.Currency {
+		case entity.USD, entity.COP, entity.EUR:
+		default:
+			return entity.TransactionQuery{}, fmt.Errorf("%w: unknown currency %q", ErrInvalidTransactionFilter, in.Currency)
+		}
+	}
+
+	filter.CustomerID = in.CustomerID
+
+	var err error
+	if filter.MinAmountInCents, err = parseAmountBound("min_amount_in_cents", in.MinAmountInCents); err != nil {
+		return entity.TransactionQuery{}, err
+	}
+	if filter.MaxAmountInCents, err = parseAmountBound("max_amount_in_cents", in.MaxAmountInCents); err != nil {
+		return entity.TransactionQuery{}, err
+	}
+	if filter.MinAmountInCents != nil && filter.MaxAmountInCents != nil && *filter.MinAmountInCents > *filter.MaxAmountInCents {
+		return entity.TransactionQuery{}, fmt.Errorf("%w: min_amount_in_cents is greater than max_amount_in_cents", ErrInvalidTransactionFilter)
+	}
+
+	if filter.CreatedFrom, err = parseCreatedBound("created_from", in.CreatedFrom, false); err != nil {
+		return entity.TransactionQuery{}, err
+	}
+	if filter.CreatedTo, err = parseCreatedBound("created_to", in.CreatedTo, true); err != nil {
+		return entity.TransactionQuery{}, err
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && filter.CreatedFrom.After(*filter.CreatedTo) {
+		return entity.TransactionQuery{}, fmt.Errorf("%w: created_from is after created_to", ErrInvalidTransactionFilter)
+	}
+
+	switch entity.SortOrder(strings.ToLower(in.Order)) {
+	case "", entity.SortDescending:
+	case entity.SortAscending:
+		query.Order = entity.SortAscending
+	default:
+		return entity.TransactionQuery{}, fmt.Errorf("%w: order must be asc or desc", ErrInvalidTransactionFilter)
+	}
+
+	return query, nil
+}
+
+func parseAmountBound(name, value string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	amount, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || amount < 0 {
+		return nil, fmt.Errorf("%w: %s must be a non-negative integer", ErrInvalidTransactionFilter, name)
+	}
+	return &amount, nil
+}
+
+// parseCreatedBound parses an RFC 3339 timestamp or a date. A date is the start
+// of the day, or its last instant when endOfDay is set.
+func parseCreatedBound(name, value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		t = t.UTC()
+		return &t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be an RFC 3339 timestamp or a YYYY-MM-DD date", ErrInvalidTransactionFilter, name)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
@@ -11,24 +11,24 @@ import (
 // listTransactionsMockRepo is a hand-written mock implementing TransactionRepository
 // for ListTransactionsUseCase tests.
 type listTransactionsMockRepo struct {
-	findAllPaginatedFunc func(ctx context.Context, limit int, cursor string) ([]entity.TransactionEntity, string, error)
+	findAllPaginatedFunc func(ctx context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error)
 }
 
 func (m *listTransactionsMockRepo) Save(_ context.Context, _ *entity.TransactionEntity) error {
 	return nil
 }
 
-func (m *listTransactionsMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) error {
-	return nil
+func (m *listTransactionsMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time, _ entity.DecisionPath) (*entity.TransactionEntity, error) {
+	return &entity.TransactionEntity{Status: entity.PENDING}, nil
 }
 
 func (m *listTransactionsMockRepo) FindByID(_ context.Context, _ string) (*entity.TransactionEntity, error) {
 	return nil, nil
 }
 
-func (m *listTransactionsMockRepo) FindAllPaginated(ctx context.Context, limit int, cursor string) ([]entity.TransactionEntity, string, error) {
+func (m *listTransactionsMockRepo) FindAllPaginated(ctx context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
 	if m.findAllPaginatedFunc != nil {
-		return m.findAllPaginatedFunc(ctx, limit, cursor)
+		return m.findAllPaginatedFunc(ctx, query)
 	}
 	return nil, "", nil
 }
@@ -66,7 +66,7 @@ func TestListTransactionsUseCase_Execute(t *testing.T) {
 		name                 string
 		limit                int
 		cursor               string
-		findAllPaginatedFunc func(ctx context.Context, limit int, cursor string) ([]entity.TransactionEntity, string, error)
+		findAllPaginatedFunc func(ctx context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error)
 		expectError          bool
 		checkSentinel        error
 		expectCount          int
@@ -76,7 +76,7 @@ func TestListTransactionsUseCase_Execute(t *testing.T) {
 			name:   "valid limit returns transactions",
 			limit:  20,
 			cursor: "",
-			findAllPaginatedFunc: func(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
+			findAllPaginatedFunc: func(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
 				return sampleTxns, "next_cursor_abc", nil
 			},
 			expectError:      false,
@@ -87,7 +87,7 @@ func TestListTransactionsUseCase_Execute(t *testing.T) {
 			name:   "limit at minimum boundary (1)",
 			limit:  1,
 			cursor: "",
-			findAllPaginatedFunc: func(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
+			findAllPaginatedFunc: func(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
 				return sampleTxns[:1], "", nil
 			},
 			expectError:      false,
@@ -98,7 +98,7 @@ func TestListTransactionsUseCase_Execute(t *testing.T) {
 			name:   "limit at maximum boundary (100)",
 			limit:  100,
 			cursor: "",
-			findAllPaginatedFunc: func(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
+			findAllPaginatedFunc: func(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
 				return sampleTxns, "", nil
 			},
 			expectError:      false,
@@ -130,7 +130,7 @@ func TestListTransactionsUseCase_Execute(t *testing.T) {
 			name:   "empty database returns empty slice",
 			limit:  20,
 			cursor: "",
-			findAllPaginatedFunc: func(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
+			findAllPaginatedFunc: func(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
 				return []entity.TransactionEntity{}, "", nil
 			},
 			expectError:      false,
@@ -141,8 +141,8 @@ func TestListTransactionsUseCase_Execute(t *testing.T) {
 			name:   "malformed cursor propagates repository error",
 			limit:  20,
 			cursor: "not-valid-base64!@#$",
-			findAllPaginatedFunc: func(_ context.Context, _ int, cursor string) ([]entity.TransactionEntity, string, error) {
-				return nil, "", ErrInvalidCursor
+			findAllPaginatedFunc: func(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
+				return nil, "", entity.ErrCursorMalformed
 			},
 			expectError:   true,
 			checkSentinel: ErrInvalidCursor,
@@ -151,7 +151,7 @@ func TestListTransactionsUseCase_Execute(t *testing.T) {
 			name:   "repository error is propagated",
 			limit:  20,
 			cursor: "",
-			findAllPaginatedFunc: func(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
+			findAllPaginatedFunc: func(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
 				return nil, "", errors.New("dynamodb connection failed")
 			},
 			expectError: true,
@@ -160,11 +160,11 @@ func TestListTransactionsUseCase_Execute(t *testing.T) {
 			name:   "valid cursor passes through to repository",
 			limit:  10,
 			cursor: "eyJpZCI6InR4bl8wMDEifQ==",
-			findAllPaginatedFunc: func(_ context.Context, limit int, cursor string) ([]entity.TransactionEntity, string, error) {
-				if limit != 10 {
+			findAllPaginatedFunc: func(_ context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
+				if query.Limit != 10 {
 					return nil, "", errors.New("unexpected limit")
 				}
-				if cursor != "eyJpZCI6InR4bl8wMDEifQ==" {
+				if query.Cursor != "eyJpZCI6InR4bl8wMDEifQ==" {
 					return nil, "", errors.New("unexpected cursor")
 				}
 				return sampleTxns[1:], "", nil
@@ -180,7 +180,7 @@ func TestListTransactionsUseCase_Execute(t *testing.T) {
 			repo := &listTransactionsMockRepo{findAllPaginatedFunc: tt.findAllPaginatedFunc}
 			uc := NewListTransactionsUseCase(repo)
 
-			txns, nextCursor, err := uc.Execute(context.Background(), tt.limit, tt.cursor)
+			txns, nextCursor, err := uc.Execute(context.Background(), ListTransactionsInput{Limit: tt.limit, Cursor: tt.cursor})
 
 			if tt.expectError {
 				if err == nil {
@@ -206,3 +206,87 @@ func TestListTransactionsUseCase_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestListTransactionsUseCase_Execute_Filters(t *testing.T) {
+	t.Run("builds the repository query from the input", func(t *testing.T) {
+		var captured entity.TransactionQuery
+		repo := &listTransactionsMockRepo{
+			findAllPaginatedFunc: func(_ context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
+				captured = query
+				return nil, "", nil
+			},
+		}
+
+		_, _, err := NewListTransactionsUseCase(repo).Execute(context.Background(), ListTransactionsInput{
+			Limit:            20,
+			Cursor:           "abc",
+			Status:           "approved",
+			PaymentMethod:    "card",
+			Currency:         "usd",
+			CustomerID:       "cust_123",
+			MinAmountInCents: "100",
+			MaxAmountInCents: "5000",
+			CreatedFrom:      "2025-01-15T10:00:00-05:00",
+			CreatedTo:        "2025-01-20",
+			Order:            "ASC",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		filter := captured.Filter
+		if filter.Status != entity.APPROVED || filter.PaymentMethod != entity.CARD || filter.Currency != entity.USD || filter.CustomerID != "cust_123" {
+			t.Errorf("unexpected filter %+v", filter)
+		}
+		if filter.MinAmountInCents == nil || *filter.MinAmountInCents != 100 || filter.MaxAmountInCents == nil || *filter.MaxAmountInCents != 5000 {
+			t.Errorf("unexpected amount range %v %v", filter.MinAmountInCents, filter.MaxAmountInCents)
+		}
+		if from := time.Date(2025, 1, 15, 15, 0, 0, 0, time.UTC); filter.CreatedFrom == nil || !filter.CreatedFrom.Equal(from) {
+			t.Errorf("expected created_from %s, got %v", from, filter.CreatedFrom)
+		}
+		if to := time.Date(2025, 1, 21, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond); filter.CreatedTo == nil || !filter.CreatedTo.Equal(to) {
+			t.Errorf("expected created_to %s, got %v", to, filter.CreatedTo)
+		}
+		if captured.Order != entity.SortAscending || captured.Limit != 20 || captured.Cursor != "abc" {
+			t.Errorf("unexpected query %+v", captured)
+		}
+	})
+
+	t.Run("defaults to newest first without filters", func(t *testing.T) {
+		var captured entity.TransactionQuery
+		repo := &listTransactionsMockRepo{
+			findAllPaginatedFunc: func(_ context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
+				captured = query
+				return nil, "", nil
+			},
+		}
+
+		if _, _, err := NewListTransactionsUseCase(repo).Execute(context.Background(), ListTransactionsInput{Limit: 20}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if captured.Order != entity.SortDescending || captured.Filter != (entity.TransactionFilter{}) {
+			t.Errorf("expected an unfiltered descending query, got %+v", captured)
+		}
+	})
+
+	t.Run("rejects invalid filters", func(t *testing.T) {
+		uc := NewListTransactionsUseCase(&listTransactionsMockRepo{})
+
+		for _, input := range []ListTransactionsInput{
+			{Status: "SETTLED"},
+			{PaymentMethod: "CASH"},
+			{Currency: "GBP"},
+			{MinAmountInCents: "-1"},
+			{MaxAmountInCents: "ten"},
+			{MinAmountInCents: "500", MaxAmountInCents: "100"},
+			{CreatedFrom: "yesterday"},
+			{CreatedFrom: "2025-01-20", CreatedTo: "2025-01-10"},
+			{Order: "random"},
+		} {
+			input.Limit = 20
+			if _, _, err := uc.Execute(context.Background(), input); !errors.Is(err, ErrInvalidTransactionFilter) {
+				t.Errorf("input %+v: expected ErrInvalidTransactionFilter, got %v", input, err)
+			}
+		}
+	})
+}
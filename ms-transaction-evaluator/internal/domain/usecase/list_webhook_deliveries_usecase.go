@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+)
+
+// ListWebhookDeliveriesUseCase lists the webhook delivery log.
+type ListWebhookDeliveriesUseCase struct {
+	deliveryRepo repository.WebhookDeliveryRepository
+}
+
+// NewListWebhookDeliveriesUseCase creates a new use case.
+func NewListWebhookDeliveriesUseCase(deliveryRepo repository.WebhookDeliveryRepository) *ListWebhookDeliveriesUseCase {
+	return &ListWebhookDeliveriesUseCase{deliveryRepo: deliveryRepo}
+}
+
+// Execute returns a page of up to limit deliveries matching the filter and the
+// cursor of the next page.
+func (uc *ListWebhookDeliveriesUseCase) Execute(
+	ctx context.Context,
+	filter entity.WebhookDeliveryFilter,
+	limit int,
+	cursor string,
+) ([]entity.WebhookDelivery, string, error) {
+	if limit < minLimit || limit > maxLimit {
+		return nil, "", ErrInvalidLimit
+	}
+
+	switch filter.Status {
+	case "", entity.WebhookDeliveryPending, entity.WebhookDeliveryDelivered, entity.WebhookDeliveryFailed:
+	default:
+		return nil, "", fmt.Errorf("%w: %s", ErrWebhookDeliveryStatusInvalid, filter.Status)
+	}
+
+	deliveries, nextCursor, err := uc.deliveryRepo.FindAll(ctx, filter, limit, cursor)
+	if err != nil {
+		if errors.Is(err, entity.ErrCursorMalformed) {
+			return nil, "", fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+		return nil, "", fmt.Errorf("%w: %w", ErrWebhookStoreFailed, err)
+	}
+
+	return deliveries, nextCursor, nil
+}
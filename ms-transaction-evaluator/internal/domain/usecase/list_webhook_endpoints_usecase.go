@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+	"sort"
+)
+
+// ListWebhookEndpointsUseCase lists the registered webhook endpoints.
+type ListWebhookEndpointsUseCase struct {
+	endpointRepo repository.WebhookEndpointRepository
+}
+
+// NewListWebhookEndpointsUseCase creates a new use case.
+func NewListWebhookEndpointsUseCase(endpointRepo repository.WebhookEndpointRepository) *ListWebhookEndpointsUseCase {
+	return &ListWebhookEndpointsUseCase{endpointRepo: endpointRepo}
+}
+
+// Execute returns the endpoints, oldest first, without their secrets.
+func (uc *ListWebhookEndpointsUseCase) Execute(ctx context.Context) ([]entity.WebhookEndpoint, error) {
+	endpoints, err := uc.endpointRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWebhookStoreFailed, err)
+	}
+
+	for i := range endpoints {
+		endpoints[i].Secret = ""
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].CreatedAt.Before(endpoints[j].CreatedAt)
+	})
+
+	return endpoints, nil
+}
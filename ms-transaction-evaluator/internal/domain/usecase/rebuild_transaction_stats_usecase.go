@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// RebuildTransactionStatsUseCase recomputes the transaction stats counters from
+// the transactions, to backfill them or to correct updates that failed.
+type RebuildTransactionStatsUseCase struct {
+	transactionRepo repository.TransactionRepository
+	statsRepo       repository.TransactionStatsRepository
+	logger          zerolog.Logger
+}
+
+// NewRebuildTransactionStatsUseCase creates a new RebuildTransactionStatsUseCase.
+func NewRebuildTransactionStatsUseCase(
+	transactionRepo repository.TransactionRepository,
+	statsRepo repository.TransactionStatsRepository,
+	logger zerolog.Logger,
+) *RebuildTransactionStatsUseCase {
+	return &RebuildTransactionStatsUseCase{
+		transactionRepo: transactionRepo,
+		statsRepo:       statsRepo,
+		logger:          logger,
+	}
+}
+
+// Execute reads every transaction, counts them by hour of creation and replaces
+// the stored counters, returning the number of transactions counted. Changes
+// recorded while it runs may be lost, so it is meant to run while no
+// transaction is being created or decided.
+func (uc *RebuildTransactionStatsUseCase) Execute(ctx context.Context) (int, error) {
+	counters := map[time.Time]entity.TransactionCounters{}
+	counted := 0
+
+	query := entity.TransactionQuery{Order: entity.SortAscending, Limit: maxLimit}
+	for {
+		transactions, nextCursor, err := uc.transactionRepo.FindAllPaginated(ctx, query)
+		if err != nil {
+			return counted, fmt.Errorf("%w: %w", ErrTransactionStatsRebuildFailed, err)
+		}
+
+		for i := range transactions {
+			hour := transactions[i].CreatedAt.UTC().Truncate(time.Hour)
+			c := counters[hour]
+			c.Add(entity.CountTransaction(&transactions[i]))
+			counters[hour] = c
+		}
+		counted += len(transactions)
+
+		if nextCursor == "" {
+			break
+		}
+		query.Cursor = nextCursor
+		uc.logger.Debug().Int("counted", counted).Msg("rebuilding transaction stats")
+	}
+
+	hours := make([]entity.HourlyTransactionCounters, 0, len(counters))
+	for hour, c := range counters {
+		hours = append(hours, entity.HourlyTransactionCounters{Hour: hour, Counters: c})
+	}
+	slices.SortFunc(hours, func(a, b entity.HourlyTransactionCounters) int {
+		return a.Hour.Compare(b.Hour)
+	})
+
+	if err := uc.statsRepo.Replace(ctx, hours); err != nil {
+		return counted, fmt.Errorf("%w: %w", ErrTransactionStatsRebuildFailed, err)
+	}
+
+	uc.logger.Info().Int("transactions", counted).Int("hours", len(hours)).Msg("transaction stats rebuilt")
+	return counted, nil
+}
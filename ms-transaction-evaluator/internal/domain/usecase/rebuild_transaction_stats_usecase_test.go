@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// pagedMockRepo is a hand-written mock implementing TransactionRepository whose
+// FindAllPaginated returns pages in order, the cursor being the next page index.
+type pagedMockRepo struct {
+	pages   [][]entity.TransactionEntity
+	err     error
+	queries []entity.TransactionQuery
+}
+
+func (m *pagedMockRepo) Save(_ context.Context, _ *entity.TransactionEntity) error {
+	return nil
+}
+
+func (m *pagedMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time, _ entity.DecisionPath) (*entity.TransactionEntity, error) {
+	return &entity.TransactionEntity{Status: entity.PENDING}, nil
+}
+
+func (m *pagedMockRepo) FindByID(_ context.Context, _ string) (*entity.TransactionEntity, error) {
+	return nil, nil
+}
+
+func (m *pagedMockRepo) FindAllPaginated(_ context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
+	m.queries = append(m.queries, query)
+	if m.err != nil {
+		return nil, "", m.err
+	}
+
+	page := len(m.queries) - 1
+	if page+1 < len(m.pages) {
+		return m.pages[page], "next", nil
+	}
+	return m.pages[page], "", nil
+}
+
+func (m *pagedMockRepo) FindAll(_ context.Context) ([]entity.TransactionEntity, error) {
+	return nil, nil
+}
+
+func TestRebuildTransactionStatsUseCase_Execute(t *testing.T) {
+	hour := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	finalizedAt := hour.Add(30*time.Minute + time.Second)
+
+	t.Run("counts every page by hour of creation", func(t *testing.T) {
+		transactionRepo := &pagedMockRepo{pages: [][]entity.TransactionEntity{
+			{
+				{ID: "tx-1", PaymentMethod: entity.CARD, Status: entity.PENDING, CreatedAt: hour.Add(5 * time.Minute)},
+				{ID: "tx-2", PaymentMethod: entity.CRYPTO, Status: entity.APPROVED, CreatedAt: hour.Add(30 * time.Minute), FinalizedAt: &finalizedAt},
+			},
+			{
+				{ID: "tx-3", PaymentMethod: entity.CARD, Status: entity.DECLINED, CreatedAt: hour.Add(2 * time.Hour)},
+			},
+		}}
+		statsRepo := &recordingStatsRepo{}
+
+		counted, err := NewRebuildTransactionStatsUseCase(transactionRepo, statsRepo, zerolog.Nop()).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if counted != 3 {
+			t.Errorf("expected 3 transactions counted, got %d", counted)
+		}
+		if len(transactionRepo.queries) != 2 || transactionRepo.queries[1].Cursor != "next" {
+			t.Errorf("expected the second page to be read with the cursor, got %+v", transactionRepo.queries)
+		}
+
+		if len(statsRepo.replaced) != 2 {
+			t.Fatalf("expected 2 hours, got %d", len(statsRepo.replaced))
+		}
+		first, second := statsRepo.replaced[0], statsRepo.replaced[1]
+		if !first.Hour.Equal(hour) || first.Counters.Created != 2 || first.Counters.Finalized != 1 || first.Counters.LatencySumMs != 1000 {
+			t.Errorf("unexpected first hour %+v", first)
+		}
+		if !second.Hour.Equal(hour.Add(2*time.Hour)) || second.Counters.Statuses[entity.DECLINED] != 1 {
+			t.Errorf("unexpected second hour %+v", second)
+		}
+	})
+
+	t.Run("does not replace the counters when a page cannot be read", func(t *testing.T) {
+		statsRepo := &recordingStatsRepo{}
+		_, err := NewRebuildTransactionStatsUseCase(&pagedMockRepo{err: errors.New("throttled")}, statsRepo, zerolog.Nop()).Execute(context.Background())
+		if !errors.Is(err, ErrTransactionStatsRebuildFailed) {
+			t.Errorf("expected ErrTransactionStatsRebuildFailed, got %v", err)
+		}
+		if statsRepo.replaced != nil {
+			t.Errorf("expected no replace, got %+v", statsRepo.replaced)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+	"time"
+)
+
+const (
+	// DefaultRedriveRate is the number of messages redriven per second when no rate is given.
+	DefaultRedriveRate = 10
+	// MaxRedriveRate is the highest number of messages redriven per second.
+	MaxRedriveRate = 100
+	// MaxRedriveMessages is the largest number of messages redriven by one request.
+	MaxRedriveMessages = 1000
+)
+
+// RedriveRequest selects the dead letters to publish back to Decision.Calculated:
+// either the messages with the given IDs or, with All, the oldest
+// MaxRedriveMessages messages of the topic.
+type RedriveRequest struct {
+	Topic         string
+	IDs           []string
+	All           bool
+	DryRun        bool
+	RatePerSecond int
+}
+
+// RedriveDeadLettersUseCase publishes dead letters back to Decision.Calculated.
+type RedriveDeadLettersUseCase struct {
+	deadLetterRepo repository.DeadLetterRepository
+	queue          entity.DeadLetterQueue
+	wait           func(ctx context.Context, d time.Duration) error
+}
+
+// NewRedriveDeadLettersUseCase creates a new use case for the Decision.Calculated dead-letter queue.
+func NewRedriveDeadLettersUseCase(
+	deadLetterRepo repository.DeadLetterRepository,
+	queue entity.DeadLetterQueue,
+) *RedriveDeadLettersUseCase {
+	return &RedriveDeadLettersUseCase{
+		deadLetterRepo: deadLetterRepo,
+		queue:          queue,
+		wait:           waitFor,
+	}
+}
+
+// Execute redrives the selected dead letters at no more than RatePerSecond
+// messages per second (DefaultRedriveRate when zero). A dry run only reports what
+// would be redriven. Messages that did not come from Decision.Calculated,
+// unknown IDs and failed publishes are reported as failures. Kafka keeps the
+// messages in the dead-letter topic after they have been redriven.
+func (uc *RedriveDeadLettersUseCase) Execute(
+	ctx context.Context,
+	req RedriveRequest,
+) (*entity.RedriveResult, error) {
+	if err := checkDeadLetterTopic(uc.queue, req.Topic); err != nil {
+		return nil, err
+	}
+
+	if req.All == (len(req.IDs) > 0) {
+		return nil, ErrRedriveSelectionInvalid
+	}
+	if len(req.IDs) > MaxRedriveMessages {
+		return nil, fmt.Errorf("%w: at most %d ids are allowed", ErrRedriveSelectionInvalid, MaxRedriveMessages)
+	}
+
+	rate := req.RatePerSecond
+	if rate == 0 {
+		rate = DefaultRedriveRate
+	}
+	if rate < 0 || rate > MaxRedriveRate {
+		return nil, fmt.Errorf("%w: must be between 1 and %d messages per second", ErrRedriveRateInvalid, MaxRedriveRate)
+	}
+
+	result := &entity.RedriveResult{
+		Topic:       uc.queue.Topic,
+		SourceTopic: uc.queue.SourceTopic,
+		DryRun:      req.DryRun,
+		RedrivenIDs: []string{},
+		Failed:      []entity.RedriveFailure{},
+	}
+
+	deadLetters, err := uc.selectDeadLetters(ctx, req, result)
+	if err != nil {
+		return nil, err
+	}
+	result.Selected = len(deadLetters) + len(result.Failed)
+
+	interval := time.Second / time.Duration(rate)
+	published := 0
+	for i := range deadLetters {
+		deadLetter := &deadLetters[i]
+		if deadLetter.SourceTopic != uc.queue.SourceTopic {
+			result.Failed = append(result.Failed, entity.RedriveFailure{
+				ID:    deadLetter.ID,
+				Error: fmt.Sprintf("source topic %q is not %q", deadLetter.SourceTopic, uc.queue.SourceTopic),
+			})
+			continue
+		}
+
+		if req.DryRun {
+			result.RedrivenIDs = append(result.RedrivenIDs, deadLetter.ID)
+			continue
+		}
+
+		if published > 0 {
+			if err := uc.wait(ctx, interval); err != nil {
+				for _, remaining := range deadLetters[i:] {
+					result.Failed = append(result.Failed, entity.RedriveFailure{ID: remaining.ID, Error: err.Error()})
+				}
+				break
+			}
+		}
+		published++
+
+		if err := uc.deadLetterRepo.Redrive(ctx, deadLetter); err != nil {
+			result.Failed = append(result.Failed, entity.RedriveFailure{ID: deadLetter.ID, Error: err.Error()})
+			continue
+		}
+		result.RedrivenIDs = append(result.RedrivenIDs, deadLetter.ID)
+	}
+	result.Redriven = len(result.RedrivenIDs)
+
+	return result, nil
+}
+
+// selectDeadLetters loads the requested dead letters, recording unknown or invalid
+// IDs as failures of the result.
+func (uc *RedriveDeadLettersUseCase) selectDeadLetters(
+	ctx context.Context,
+	req RedriveRequest,
+	result *entity.RedriveResult,
+) ([]entity.DeadLetter, error) {
+	if req.All {
+		deadLetters, err := uc.deadLetterRepo.FindAll(ctx, MaxRedriveMessages)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDeadLetterRetrievalFailed, err)
+		}
+		return deadLetters, nil
+	}
+
+	seen := make(map[string]bool, len(req.IDs))
+	deadLetters := make([]entity.DeadLetter, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		partition, offset, err := entity.ParseDeadLetterID(id)
+		if err != nil {
+			result.Failed = append(result.Failed, entity.RedriveFailure{ID: id, Error: err.Error()})
+			continue
+		}
+
+		deadLetter, err := uc.deadLetterRepo.FindByOffset(ctx, partition, offset)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDeadLetterRetrievalFailed, err)
+		}
+		if deadLetter == nil {
+			result.Failed = append(result.Failed, entity.RedriveFailure{ID: id, Error: ErrDeadLetterNotFound.Error()})
+			continue
+		}
+		deadLetters = append(deadLetters, *deadLetter)
+	}
+
+	return deadLetters, nil
+}
+
+// waitFor waits for d, returning the context's error if it ends first.
+func waitFor(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"testing"
+	"time"
+)
+
+// --- Mock DeadLetterRepository ---
+
+type mockDeadLetterRepository struct {
+	deadLetters []entity.DeadLetter
+	countErr    error
+	findAllErr  error
+	redriveFunc func(ctx context.Context, deadLetter *entity.DeadLetter) error
+	redriven    []string
+}
+
+func (m *mockDeadLetterRepository) Count(_ context.Context) (int64, error) {
+	if m.countErr != nil {
+		return 0, m.countErr
+	}
+	return int64(len(m.deadLetters)), nil
+}
+
+func (m *mockDeadLetterRepository) FindAll(_ context.Context, limit int) ([]entity.DeadLetter, error) {
+	if m.findAllErr != nil {
+		return nil, m.findAllErr
+	}
+	if limit < len(m.deadLetters) {
+		return m.deadLetters[:limit], nil
+	}
+	return m.deadLetters, nil
+}
+
+func (m *mockDeadLetterRepository) FindByOffset(_ context.Context, partition int32, offset int64) (*entity.DeadLetter, error) {
+	for i := range m.deadLetters {
+		if m.deadLetters[i].Partition == partition && m.deadLetters[i].Offset == offset {
+			deadLetter := m.deadLetters[i]
+			return &deadLetter, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDeadLetterRepository) Redrive(ctx context.Context, deadLetter *entity.DeadLetter) error {
+	if m.redriveFunc != nil {
+		if err := m.redriveFunc(ctx, deadLetter); err != nil {
+			return err
+		}
+	}
+	m.redriven = append(m.redriven, deadLetter.ID)
+	return nil
+}
+
+func newDeadLetter(partition int32, offset int64, sourceTopic string) entity.DeadLetter {
+	return entity.DeadLetter{
+		ID:          entity.DeadLetterID(partition, offset),
+		Partition:   partition,
+		Offset:      offset,
+		SourceTopic: sourceTopic,
+		Payload:     `{"transaction_id":"tx-1","status":"APPROVED"}`,
+		Error:       "failed to update transaction status",
+		Attempts:    1,
+	}
+}
+
+var testDeadLetterQueue = entity.DeadLetterQueue{Topic: "Decision.Calculated.DLQ", SourceTopic: "Decision.Calculated"}
+
+func newTestRedriveUseCase(repo *mockDeadLetterRepository) (*RedriveDeadLettersUseCase, *[]time.Duration) {
+	uc := NewRedriveDeadLettersUseCase(repo, testDeadLetterQueue)
+	var waits []time.Duration
+	uc.wait = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return uc, &waits
+}
+
+func TestRedriveDeadLettersUseCase_RedrivesSelectedIDs(t *testing.T) {
+	repo := &mockDeadLetterRepository{deadLetters: []entity.DeadLetter{
+		newDeadLetter(0, 1, "Decision.Calculated"),
+		newDeadLetter(0, 2, "Decision.Calculated"),
+		newDeadLetter(1, 1, "Decision.Calculated"),
+	}}
+	uc, waits := newTestRedriveUseCase(repo)
+
+	result, err := uc.Execute(context.Background(), RedriveRequest{
+		Topic:         "Decision.Calculated.DLQ",
+		IDs:           []string{"0-2", "1-1", "0-2", "0-9", "bad"},
+		RatePerSecond: 4,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(repo.redriven) != 2 || repo.redriven[0] != "0-2" || repo.redriven[1] != "1-1" {
+		t.Errorf("expected 0-2 and 1-1 to be redriven, got %v", repo.redriven)
+	}
+	if result.Selected != 4 || result.Redriven != 2 {
+		t.Errorf("expected 4 selected and 2 redriven, got %d and %d", result.Selected, result.Redriven)
+	}
+	if len(result.Failed) != 2 || result.Failed[0].ID != "0-9" || result.Failed[1].ID != "bad" {
+		t.Errorf("expected 0-9 and bad to fail, got %+v", result.Failed)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 250*time.Millisecond {
+		t.Errorf("expected one 250ms wait between publishes, got %v", *waits)
+	}
+}
+
+func TestRedriveDeadLettersUseCase_DryRunPublishesNothing(t *testing.T) {
+	repo := &mockDeadLetterRepository{deadLetters: []entity.DeadLetter{
+		newDeadLetter(0, 1, "Decision.Calculated"),
+		newDeadLetter(0, 2, "Transaction.Created"),
+	}}
+	uc, _ := newTestRedriveUseCase(repo)
+
+	result, err := uc.Execute(context.Background(), RedriveRequest{
+		Topic:  "Decision.Calculated.DLQ",
+		All:    true,
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(repo.redriven) != 0 {
+		t.Errorf("expected nothing to be redriven, got %v", repo.redriven)
+	}
+	if !result.DryRun || result.Redriven != 1 || result.RedrivenIDs[0] != "0-1" {
+		t.Errorf("expected 0-1 to be reported, got %+v", result)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].ID != "0-2" {
+		t.Errorf("expected the message from another topic to fail, got %+v", result.Failed)
+	}
+}
+
+func TestRedriveDeadLettersUseCase_PublishFailureIsReported(t *testing.T) {
+	repo := &mockDeadLetterRepository{
+		deadLetters: []entity.DeadLetter{newDeadLetter(0, 1, "Decision.Calculated")},
+		redriveFunc: func(_ context.Context, _ *entity.DeadLetter) error {
+			return errors.New("broker unavailable")
+		},
+	}
+	uc, _ := newTestRedriveUseCase(repo)
+
+	result, err := uc.Execute(context.Background(), RedriveRequest{Topic: "Decision.Calculated.DLQ", All: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result.Redriven != 0 || len(result.Failed) != 1 || result.Failed[0].Error != "broker unavailable" {
+		t.Errorf("expected the publish failure to be reported, got %+v", result)
+	}
+}
+
+func TestRedriveDeadLettersUseCase_InvalidRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      RedriveRequest
+		expected error
+	}{
+		{"unknown topic", RedriveRequest{Topic: "Other.DLQ", All: true}, ErrDeadLetterQueueNotFound},
+		{"no selection", RedriveRequest{Topic: "Decision.Calculated.DLQ"}, ErrRedriveSelectionInvalid},
+		{"ids and all", RedriveRequest{Topic: "Decision.Calculated.DLQ", IDs: []string{"0-1"}, All: true}, ErrRedriveSelectionInvalid},
+		{"negative rate", RedriveRequest{Topic: "Decision.Calculated.DLQ", All: true, RatePerSecond: -1}, ErrRedriveRateInvalid},
+		{"rate too high", RedriveRequest{Topic: "Decision.Calculated.DLQ", All: true, RatePerSecond: MaxRedriveRate + 1}, ErrRedriveRateInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _ := newTestRedriveUseCase(&mockDeadLetterRepository{})
+			if _, err := uc.Execute(context.Background(), tt.req); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestRedriveDeadLettersUseCase_RetrievalFailure(t *testing.T) {
+	uc, _ := newTestRedriveUseCase(&mockDeadLetterRepository{findAllErr: errors.New("timeout")})
+
+	_, err := uc.Execute(context.Background(), RedriveRequest{Topic: "Decision.Calculated.DLQ", All: true})
+	if !errors.Is(err, ErrDeadLetterRetrievalFailed) {
+		t.Errorf("expected ErrDeadLetterRetrievalFailed, got %v", err)
+	}
+}
+
+func TestListDeadLettersUseCase_Limit(t *testing.T) {
+	repo := &mockDeadLetterRepository{deadLetters: []entity.DeadLetter{
+		newDeadLetter(0, 1, "Decision.Calculated"),
+		newDeadLetter(0, 2, "Decision.Calculated"),
+	}}
+	uc := NewListDeadLettersUseCase(repo, testDeadLetterQueue)
+
+	deadLetters, err := uc.Execute(context.Background(), "Decision.Calculated.DLQ", 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(deadLetters) != 1 {
+		t.Errorf("expected 1 dead letter, got %d", len(deadLetters))
+	}
+
+	if _, err := uc.Execute(context.Background(), "Decision.Calculated.DLQ", MaxDeadLetterListLimit+1); !errors.Is(err, ErrDeadLetterLimitInvalid) {
+		t.Errorf("expected ErrDeadLetterLimitInvalid, got %v", err)
+	}
+}
+
+func TestGetDeadLetterUseCase(t *testing.T) {
+	repo := &mockDeadLetterRepository{deadLetters: []entity.DeadLetter{newDeadLetter(2, 5, "Decision.Calculated")}}
+	uc := NewGetDeadLetterUseCase(repo, testDeadLetterQueue)
+
+	deadLetter, err := uc.Execute(context.Background(), "Decision.Calculated.DLQ", "2-5")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if deadLetter.ID != "2-5" {
+		t.Errorf("expected dead letter 2-5, got %s", deadLetter.ID)
+	}
+
+	if _, err := uc.Execute(context.Background(), "Decision.Calculated.DLQ", "2-6"); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("expected ErrDeadLetterNotFound, got %v", err)
+	}
+	if _, err := uc.Execute(context.Background(), "Decision.Calculated.DLQ", "x"); !errors.Is(err, entity.ErrDeadLetterIDInvalid) {
+		t.Errorf("expected ErrDeadLetterIDInvalid, got %v", err)
+	}
+}
+
+func TestGetDeadLetterQueuesUseCase(t *testing.T) {
+	repo := &mockDeadLetterRepository{deadLetters: []entity.DeadLetter{newDeadLetter(0, 1, "Decision.Calculated")}}
+	uc := NewGetDeadLetterQueuesUseCase(repo, testDeadLetterQueue)
+
+	queues, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(queues) != 1 || queues[0].Topic != "Decision.Calculated.DLQ" || queues[0].SourceTopic != "Decision.Calculated" || queues[0].Count != 1 {
+		t.Errorf("expected only the Decision.Calculated queue with one message, got %+v", queues)
+	}
+
+	repo.countErr = errors.New("broker unavailable")
+	if _, err := uc.Execute(context.Background()); !errors.Is(err, ErrDeadLetterRetrievalFailed) {
+		t.Errorf("expected ErrDeadLetterRetrievalFailed, got %v", err)
+	}
+}
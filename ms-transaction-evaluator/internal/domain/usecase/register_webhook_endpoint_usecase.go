@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// minWebhookSecretLength is the shortest secret a client may choose.
+const minWebhookSecretLength = 16
+
+// RegisterWebhookEndpointRequest describes a new webhook endpoint. An empty
+// MerchantID registers a global endpoint and an empty Secret is generated.
+type RegisterWebhookEndpointRequest struct {
+	URL        string
+	MerchantID string
+	Secret     string
+}
+
+// RegisterWebhookEndpointUseCase registers a webhook endpoint.
+type RegisterWebhookEndpointUseCase struct {
+	endpointRepo repository.WebhookEndpointRepository
+	now          func() time.Time
+}
+
+// NewRegisterWebhookEndpointUseCase creates a new use case.
+func NewRegisterWebhookEndpointUseCase(endpointRepo repository.WebhookEndpointRepository) *RegisterWebhookEndpointUseCase {
+	return &RegisterWebhookEndpointUseCase{endpointRepo: endpointRepo, now: time.Now}
+}
+
+// Execute validates and saves the endpoint. The returned endpoint carries its
+// secret, which is not returned again.
+func (uc *RegisterWebhookEndpointUseCase) Execute(ctx context.Context, req RegisterWebhookEndpointRequest) (*entity.WebhookEndpoint, error) {
+	parsed, err := url.Parse(req.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrWebhookURLInvalid, req.URL)
+	}
+
+	if len(req.MerchantID) > maxIdentifierLength {
+		return nil, ErrMerchantIDTooLong
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+	} else if len(secret) < minWebhookSecretLength {
+		return nil, ErrWebhookSecretTooShort
+	}
+
+	endpoint := &entity.WebhookEndpoint{
+		ID:         uuid.New().String(),
+		URL:        req.URL,
+		MerchantID: req.MerchantID,
+		Secret:     secret,
+		CreatedAt:  uc.now().UTC(),
+	}
+
+	if err := uc.endpointRepo.Save(ctx, endpoint); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWebhookStoreFailed, err)
+	}
+
+	return endpoint, nil
+}
+
+// generateWebhookSecret returns a random secret of 32 bytes, hex encoded.
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegisterWebhookEndpointUseCase_Execute(t *testing.T) {
+	t.Run("generates a secret when none is given", func(t *testing.T) {
+		repo := &mockWebhookEndpointRepository{}
+
+		endpoint, err := NewRegisterWebhookEndpointUseCase(repo).Execute(context.Background(), RegisterWebhookEndpointRequest{
+			URL:        "https://example.com/hooks",
+			MerchantID: "merchant_42",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if endpoint.ID == "" || !strings.HasPrefix(endpoint.Secret, "whsec_") || endpoint.MerchantID != "merchant_42" {
+			t.Errorf("unexpected endpoint: %+v", endpoint)
+		}
+		if len(repo.endpoints) != 1 {
+			t.Errorf("expected the endpoint to be saved, got %d", len(repo.endpoints))
+		}
+	})
+
+	t.Run("keeps the given secret", func(t *testing.T) {
+		endpoint, err := NewRegisterWebhookEndpointUseCase(&mockWebhookEndpointRepository{}).Execute(context.Background(), RegisterWebhookEndpointRequest{
+			URL:    "http://localhost:9000",
+			Secret: "0123456789abcdef",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if endpoint.Secret != "0123456789abcdef" {
+			t.Errorf("expected the given secret, got %q", endpoint.Secret)
+		}
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			req      RegisterWebhookEndpointRequest
+			expected error
+		}{
+			{"empty url", RegisterWebhookEndpointRequest{}, ErrWebhookURLInvalid},
+			{"unsupported scheme", RegisterWebhookEndpointRequest{URL: "ftp://example.com"}, ErrWebhookURLInvalid},
+			{"missing host", RegisterWebhookEndpointRequest{URL: "https://"}, ErrWebhookURLInvalid},
+			{"short secret", RegisterWebhookEndpointRequest{URL: "https://example.com", Secret: "short"}, ErrWebhookSecretTooShort},
+			{"long merchant", RegisterWebhookEndpointRequest{URL: "https://example.com", MerchantID: strings.Repeat("m", maxIdentifierLength+1)}, ErrMerchantIDTooLong},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo := &mockWebhookEndpointRepository{}
+				if _, err := NewRegisterWebhookEndpointUseCase(repo).Execute(context.Background(), tt.req); !errors.Is(err, tt.expected) {
+					t.Errorf("expected %v, got %v", tt.expected, err)
+				}
+				if len(repo.endpoints) != 0 {
+					t.Errorf("expected nothing saved")
+				}
+			})
+		}
+	})
+}
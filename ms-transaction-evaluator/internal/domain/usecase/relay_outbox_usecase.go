@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+	"ms-transaction-evaluator/internal/infrastructure/telemetry"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultOutboxBatchSize is the number of due entries read per relay.
+	DefaultOutboxBatchSize = 100
+	// DefaultOutboxMaxAttempts is how many times an entry is published before the
+	// relayer gives up on it.
+	DefaultOutboxMaxAttempts = 20
+	// outboxInitialBackoff is the wait after the first failed publish of an entry.
+	outboxInitialBackoff = time.Second
+	// outboxMaxBackoff caps the wait between publish attempts of an entry.
+	outboxMaxBackoff = 5 * time.Minute
+	// outboxClaimLease is how long a claimed entry is hidden from other relayers.
+	// It must exceed the time taken to publish the entry and mark it.
+	outboxClaimLease = 30 * time.Second
+)
+
+// RelayOutboxUseCase publishes the pending outbox entries to Kafka and marks them sent.
+type RelayOutboxUseCase struct {
+	outboxRepo     repository.OutboxRepository
+	eventPublisher repository.TransactionEventPublisher
+	batchSize      int
+	maxAttempts    int
+	logger         zerolog.Logger
+	now            func() time.Time
+}
+
+// NewRelayOutboxUseCase creates a new use case reading batchSize entries per relay
+// and giving up on an entry after maxAttempts failed publishes. Non-positive values
+// fall back to the defaults.
+func NewRelayOutboxUseCase(
+	outboxRepo repository.OutboxRepository,
+	eventPublisher repository.TransactionEventPublisher,
+	batchSize int,
+	maxAttempts int,
+	logger zerolog.Logger,
+) *RelayOutboxUseCase {
+	if batchSize <= 0 {
+		batchSize = DefaultOutboxBatchSize
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultOutboxMaxAttempts
+	}
+
+	return &RelayOutboxUseCase{
+		outboxRepo:     outboxRepo,
+		eventPublisher: eventPublisher,
+		batchSize:      batchSize,
+		maxAttempts:    maxAttempts,
+		logger:         logger,
+		now:            time.Now,
+	}
+}
+
+// Execute publishes a batch of due entries and returns how many were published
+// and marked sent. Each entry is claimed for outboxClaimLease before it is
+// published, so an entry read by several relayers is published by one of them;
+// the others skip it. A failed publish is retried with exponential backoff until
+// maxAttempts is reached, after which the entry is moved to FAILED. An entry whose
+// publish succeeded but could not be marked sent, or whose claim ran out before
+// it was marked, is published again by a later relay, so delivery is
+// at-least-once. It also updates the outbox lag metrics.
+func (uc *RelayOutboxUseCase) Execute(ctx context.Context) (int, error) {
+	now := uc.now()
+	entries, err := uc.outboxRepo.FindDue(ctx, now, uc.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrOutboxRetrievalFailed, err)
+	}
+
+	observeOutboxLag(entries, now)
+
+	published := 0
+	for i := range entries {
+		entry := &entries[i]
+		if !entry.Due(now) {
+			continue
+		}
+
+		claimed, err := uc.outboxRepo.Claim(ctx, entry.ID, entry.NextAttemptAt, now.Add(outboxClaimLease))
+		if err != nil {
+			uc.logger.Error().Err(err).
+				Str("outbox_id", entry.ID).
+				Str("transaction_id", entry.TransactionID).
+				Msg("failed to claim outbox entry")
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := uc.eventPublisher.Publish(ctx, &entry.Transaction); err != nil {
+			uc.markFailed(ctx, entry, err, now)
+			continue
+		}
+
+		sentAt := uc.now()
+		telemetry.OutboxEventsPublished.Inc()
+		telemetry.OutboxPublishDelay.Observe(sentAt.Sub(entry.CreatedAt).Seconds())
+
+		if err := uc.outboxRepo.MarkSent(ctx, entry.ID, sentAt); err != nil {
+			uc.logger.Error().Err(err).
+				Str("outbox_id", entry.ID).
+				Str("transaction_id", entry.TransactionID).
+				Msg("outbox entry published but not marked sent, it will be published again")
+			continue
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+// Run relays the outbox every interval until ctx is cancelled. A relay that
+// published a full batch is followed immediately by the next one.
+func (uc *RelayOutboxUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				published, err := uc.Execute(ctx)
+				if err != nil {
+					uc.logger.Warn().Err(err).Msg("outbox relay failed")
+				}
+				if err != nil || published < uc.batchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// markFailed records the failed publish attempt and schedules the next one, or
+// gives up on the entry once it has used up its attempts.
+func (uc *RelayOutboxUseCase) markFailed(ctx context.Context, entry *entity.OutboxEntry, cause error, now time.Time) {
+	telemetry.OutboxPublishFailures.Inc()
+
+	attempts := entry.Attempts + 1
+	lastError := fmt.Errorf("%w: %w", ErrEventPublishFailed, cause).Error()
+
+	if attempts >= uc.maxAttempts {
+		telemetry.OutboxEntriesFailed.Inc()
+		uc.logger.Error().Err(cause).
+			Str("outbox_id", entry.ID).
+			Str("transaction_id", entry.TransactionID).
+			Int("attempts", attempts).
+			Msg("failed to publish outbox entry, attempts exhausted")
+
+		if err := uc.outboxRepo.MarkExhausted(ctx, entry.ID, attempts, lastError); err != nil {
+			uc.logger.Error().Err(err).
+				Str("outbox_id", entry.ID).
+				Str("transaction_id", entry.TransactionID).
+				Msg("failed to record exhausted outbox entry")
+		}
+		return
+	}
+
+	nextAttemptAt := now.Add(outboxBackoff(attempts))
+
+	uc.logger.Warn().Err(cause).
+		Str("outbox_id", entry.ID).
+		Str("transaction_id", entry.TransactionID).
+		Int("attempts", attempts).
+		Time("next_attempt_at", nextAttemptAt).
+		Msg("failed to publish outbox entry")
+
+	if err := uc.outboxRepo.MarkFailed(ctx, entry.ID, attempts, lastError, nextAttemptAt); err != nil {
+		uc.logger.Error().Err(err).
+			Str("outbox_id", entry.ID).
+			Str("transaction_id", entry.TransactionID).
+			Msg("failed to record outbox publish failure")
+	}
+}
+
+// outboxBackoff returns the wait after the given number of failed attempts,
+// doubling from outboxInitialBackoff up to outboxMaxBackoff.
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxInitialBackoff
+	for i := 1; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, outboxMaxBackoff)
+}
+
+// observeOutboxLag sets the outbox gauges from the due entries. Entries are read
+// by next attempt, so a retried entry may be older than the first one.
+func observeOutboxLag(entries []entity.OutboxEntry, now time.Time) {
+	telemetry.OutboxPendingEntries.Set(float64(len(entries)))
+
+	var lag time.Duration
+	for i := range entries {
+		lag = max(lag, now.Sub(entries[i].CreatedAt))
+	}
+	telemetry.OutboxLag.Set(lag.Seconds())
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var relayNow = time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+func newPendingOutboxEntry(id string, createdAt time.Time) entity.OutboxEntry {
+	entry := entity.NewTransactionCreatedEntry(id, &entity.TransactionEntity{
+		ID:        "tx-" + id,
+		Status:    entity.PENDING,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	})
+	return *entry
+}
+
+func newTestRelayUseCase(repo *mockOutboxRepository, pub *mockEventPublisher, batchSize int) *RelayOutboxUseCase {
+	uc := NewRelayOutboxUseCase(repo, pub, batchSize, 5, zerolog.Nop())
+	uc.now = func() time.Time { return relayNow }
+	return uc
+}
+
+func TestRelayOutboxUseCase_Execute(t *testing.T) {
+	t.Run("publishes due entries and marks them sent", func(t *testing.T) {
+		entries := []entity.OutboxEntry{
+			newPendingOutboxEntry("1", relayNow.Add(-2*time.Second)),
+			newPendingOutboxEntry("2", relayNow.Add(-time.Second)),
+		}
+		repo := &mockOutboxRepository{
+			findDueFunc: func(_ context.Context, _ time.Time, _ int) ([]entity.OutboxEntry, error) {
+				return entries, nil
+			},
+		}
+		pub := &mockEventPublisher{}
+
+		published, err := newTestRelayUseCase(repo, pub, 10).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if published != 2 {
+			t.Errorf("expected 2 published, got %d", published)
+		}
+		if strings.Join(pub.published, ",") != "tx-1,tx-2" {
+			t.Errorf("expected transactions published oldest first, got %v", pub.published)
+		}
+		if strings.Join(repo.sent, ",") != "1,2" {
+			t.Errorf("expected both entries marked sent, got %v", repo.sent)
+		}
+	})
+
+	t.Run("skips entries whose next attempt is in the future", func(t *testing.T) {
+		waiting := newPendingOutboxEntry("1", relayNow.Add(-time.Minute))
+		waiting.Attempts = 1
+		waiting.NextAttemptAt = relayNow.Add(time.Second)
+		repo := &mockOutboxRepository{
+			findDueFunc: func(_ context.Context, _ time.Time, _ int) ([]entity.OutboxEntry, error) {
+				return []entity.OutboxEntry{waiting, newPendingOutboxEntry("2", relayNow)}, nil
+			},
+		}
+		pub := &mockEventPublisher{}
+
+		published, err := newTestRelayUseCase(repo, pub, 10).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if published != 1 || strings.Join(pub.published, ",") != "tx-2" {
+			t.Errorf("expected only tx-2 published, got %d %v", published, pub.published)
+		}
+	})
+
+	t.Run("claims each entry before publishing and skips entries claimed elsewhere", func(t *testing.T) {
+		retried := newPendingOutboxEntry("2", relayNow.Add(-time.Minute))
+		retried.Attempts = 1
+		retried.NextAttemptAt = relayNow.Add(-time.Second)
+		repo := &mockOutboxRepository{
+			findDueFunc: func(_ context.Context, _ time.Time, _ int) ([]entity.OutboxEntry, error) {
+				return []entity.OutboxEntry{newPendingOutboxEntry("1", relayNow.Add(-time.Minute)), retried}, nil
+			},
+		}
+		type claim struct{ nextAttemptAt, until time.Time }
+		claims := map[string]claim{}
+		repo.claimFunc = func(_ context.Context, id string, nextAttemptAt time.Time, until time.Time) (bool, error) {
+			claims[id] = claim{nextAttemptAt, until}
+			return id != "1", nil
+		}
+		pub := &mockEventPublisher{}
+
+		published, err := newTestRelayUseCase(repo, pub, 10).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if published != 1 || strings.Join(pub.published, ",") != "tx-2" || strings.Join(repo.sent, ",") != "2" {
+			t.Errorf("expected only the claimed entry published, got %d %v sent %v", published, pub.published, repo.sent)
+		}
+		if got := claims["2"]; !got.nextAttemptAt.Equal(retried.NextAttemptAt) || !got.until.Equal(relayNow.Add(outboxClaimLease)) {
+			t.Errorf("expected the claim to move the next attempt from %v to %v, got %+v", retried.NextAttemptAt, relayNow.Add(outboxClaimLease), got)
+		}
+	})
+
+	t.Run("claim failure skips the entry", func(t *testing.T) {
+		repo := &mockOutboxRepository{
+			findDueFunc: func(_ context.Context, _ time.Time, _ int) ([]entity.OutboxEntry, error) {
+				return []entity.OutboxEntry{newPendingOutboxEntry("1", relayNow)}, nil
+			},
+			claimFunc: func(_ context.Context, _ string, _ time.Time, _ time.Time) (bool, error) {
+				return false, errors.New("throttled")
+			},
+		}
+		pub := &mockEventPublisher{}
+
+		published, err := newTestRelayUseCase(repo, pub, 10).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if published != 0 || len(pub.published) != 0 || len(repo.failed) != 0 {
+			t.Errorf("expected the entry to be left for a later relay, got %d %v %v", published, pub.published, repo.failed)
+		}
+	})
+
+	t.Run("publish failure schedules a retry with backoff", func(t *testing.T) {
+		failing := newPendingOutboxEntry("1", relayNow.Add(-time.Minute))
+		failing.Attempts = 2
+		repo := &mockOutboxRepository{
+			findDueFunc: func(_ context.Context, _ time.Time, _ int) ([]entity.OutboxEntry, error) {
+				return []entity.OutboxEntry{failing}, nil
+			},
+		}
+		pub := &mockEventPublisher{
+			publishFunc: func(_ context.Context, _ *entity.TransactionEntity) error {
+				return errors.New("broker unavailable")
+			},
+		}
+
+		published, err := newTestRelayUseCase(repo, pub, 10).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if published != 0 || len(repo.sent) != 0 {
+			t.Errorf("expected nothing published, got %d sent %v", published, repo.sent)
+		}
+
+		failure, ok := repo.failed["1"]
+		if !ok {
+			t.Fatal("expected the failure to be recorded")
+		}
+		if failure.attempts != 3 {
+			t.Errorf("expected attempts 3, got %d", failure.attempts)
+		}
+		if want := relayNow.Add(4 * time.Second); !failure.nextAttemptAt.Equal(want) {
+			t.Errorf("expected next attempt at %v, got %v", want, failure.nextAttemptAt)
+		}
+		if failure.lastError != "failed to publish transaction event: broker unavailable" {
+			t.Errorf("unexpected last error %q", failure.lastError)
+		}
+	})
+
+	t.Run("publish failure on the last attempt gives up on the entry", func(t *testing.T) {
+		failing := newPendingOutboxEntry("1", relayNow.Add(-time.Hour))
+		failing.Attempts = 4
+		repo := &mockOutboxRepository{
+			findDueFunc: func(_ context.Context, _ time.Time, _ int) ([]entity.OutboxEntry, error) {
+				return []entity.OutboxEntry{failing}, nil
+			},
+		}
+		pub := &mockEventPublisher{
+			publishFunc: func(_ context.Context, _ *entity.TransactionEntity) error {
+				return errors.New("broker unavailable")
+			},
+		}
+
+		if _, err := newTestRelayUseCase(repo, pub, 10).Execute(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, ok := repo.failed["1"]; ok {
+			t.Error("expected no retry to be scheduled")
+		}
+		exhausted, ok := repo.exhausted["1"]
+		if !ok {
+			t.Fatal("expected the entry to be moved to FAILED")
+		}
+		if exhausted.attempts != 5 || exhausted.lastError != "failed to publish transaction event: broker unavailable" {
+			t.Errorf("unexpected exhausted entry %+v", exhausted)
+		}
+	})
+
+	t.Run("entry not marked sent is not counted", func(t *testing.T) {
+		repo := &mockOutboxRepository{
+			findDueFunc: func(_ context.Context, _ time.Time, _ int) ([]entity.OutboxEntry, error) {
+				return []entity.OutboxEntry{newPendingOutboxEntry("1", relayNow)}, nil
+			},
+			markSentErr: errors.New("throttled"),
+		}
+		pub := &mockEventPublisher{}
+
+		published, err := newTestRelayUseCase(repo, pub, 10).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if published != 0 || len(pub.published) != 1 {
+			t.Errorf("expected the event published but not counted, got %d %v", published, pub.published)
+		}
+	})
+
+	t.Run("retrieval failure wraps ErrOutboxRetrievalFailed", func(t *testing.T) {
+		repo := &mockOutboxRepository{
+			findDueFunc: func(_ context.Context, _ time.Time, _ int) ([]entity.OutboxEntry, error) {
+				return nil, errors.New("database error")
+			},
+		}
+
+		_, err := newTestRelayUseCase(repo, &mockEventPublisher{}, 10).Execute(context.Background())
+		if !errors.Is(err, ErrOutboxRetrievalFailed) {
+			t.Errorf("expected ErrOutboxRetrievalFailed, got %v", err)
+		}
+	})
+
+	t.Run("reads batchSize entries due now", func(t *testing.T) {
+		var gotNow time.Time
+		var gotLimit int
+		repo := &mockOutboxRepository{
+			findDueFunc: func(_ context.Context, now time.Time, limit int) ([]entity.OutboxEntry, error) {
+				gotNow, gotLimit = now, limit
+				return nil, nil
+			},
+		}
+
+		if _, err := newTestRelayUseCase(repo, &mockEventPublisher{}, 0).Execute(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotLimit != DefaultOutboxBatchSize {
+			t.Errorf("expected default batch size %d, got %d", DefaultOutboxBatchSize, gotLimit)
+		}
+		if !gotNow.Equal(relayNow) {
+			t.Errorf("expected entries due at %v, got %v", relayNow, gotNow)
+		}
+	})
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{5, 16 * time.Second},
+		{9, 256 * time.Second},
+		{10, outboxMaxBackoff},
+		{100, outboxMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts); got != tt.want {
+			t.Errorf("outboxBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
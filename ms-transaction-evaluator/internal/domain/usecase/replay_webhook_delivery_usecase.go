@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+	"time"
+)
+
+// ReplayWebhookDeliveryUseCase schedules a webhook delivery to be sent again.
+type ReplayWebhookDeliveryUseCase struct {
+	deliveryRepo repository.WebhookDeliveryRepository
+	now          func() time.Time
+}
+
+// NewReplayWebhookDeliveryUseCase creates a new use case.
+func NewReplayWebhookDeliveryUseCase(deliveryRepo repository.WebhookDeliveryRepository) *ReplayWebhookDeliveryUseCase {
+	return &ReplayWebhookDeliveryUseCase{deliveryRepo: deliveryRepo, now: time.Now}
+}
+
+// Execute makes the delivery pending and due immediately, with a fresh set of
+// attempts, and returns it. The same payload is sent again; its log is kept.
+func (uc *ReplayWebhookDeliveryUseCase) Execute(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	delivery, err := findWebhookDelivery(ctx, uc.deliveryRepo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Status = entity.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = uc.now().UTC()
+	delivery.DeliveredAt = nil
+
+	if err := uc.deliveryRepo.Update(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWebhookStoreFailed, err)
+	}
+
+	return delivery, nil
+}
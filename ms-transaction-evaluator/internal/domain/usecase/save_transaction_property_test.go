@@ -12,36 +12,33 @@ import (
 // Feature: transaction-finalization-latency, Property 2: New transactions have no finalized_at
 // Validates: Requirements 1.4
 
-// saveCaptureMockRepo captures the transaction entity passed to Save.
+// saveCaptureMockRepo captures the transaction entity passed to SaveTransaction.
 type saveCaptureMockRepo struct {
 	captured *entity.TransactionEntity
 }
 
-func (m *saveCaptureMockRepo) Save(_ context.Context, txn *entity.TransactionEntity) error {
+func (m *saveCaptureMockRepo) SaveTransaction(_ context.Context, txn *entity.TransactionEntity, _ *entity.OutboxEntry, _ *entity.IdempotencyKey) error {
 	m.captured = txn
 	return nil
 }
 
-func (m *saveCaptureMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) error {
-	return nil
-}
-
-func (m *saveCaptureMockRepo) FindByID(_ context.Context, _ string) (*entity.TransactionEntity, error) {
+func (m *saveCaptureMockRepo) FindDue(_ context.Context, _ time.Time, _ int) ([]entity.OutboxEntry, error) {
 	return nil, nil
 }
 
-func (m *saveCaptureMockRepo) FindAllPaginated(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
-	return nil, "", nil
+func (m *saveCaptureMockRepo) Claim(_ context.Context, _ string, _ time.Time, _ time.Time) (bool, error) {
+	return true, nil
 }
 
-func (m *saveCaptureMockRepo) FindAll(_ context.Context) ([]entity.TransactionEntity, error) {
-	return nil, nil
+func (m *saveCaptureMockRepo) MarkSent(_ context.Context, _ string, _ time.Time) error {
+	return nil
 }
 
-// noopEventPublisher is a mock event publisher that always succeeds.
-type noopEventPublisher struct{}
+func (m *saveCaptureMockRepo) MarkFailed(_ context.Context, _ string, _ int, _ string, _ time.Time) error {
+	return nil
+}
 
-func (m *noopEventPublisher) Publish(_ context.Context, _ *entity.TransactionEntity) error {
+func (m *saveCaptureMockRepo) MarkExhausted(_ context.Context, _ string, _ int, _ string) error {
 	return nil
 }
 
@@ -51,8 +48,7 @@ func TestProperty_NewTransactionsHaveNoFinalizedAt(t *testing.T) {
 
 	rapid.Check(t, func(t *rapid.T) {
 		mock := &saveCaptureMockRepo{}
-		pub := &noopEventPublisher{}
-		uc := NewSaveTransactionUseCase(mock, pub)
+		uc := NewSaveTransactionUseCase(mock, &streamMockRepo{}, &mockIdempotencyKeyRepository{}, 0, nil, nil)
 
 		currency := currencies[rapid.IntRange(0, len(currencies)-1).Draw(t, "currencyIdx")]
 		paymentMethod := paymentMethods[rapid.IntRange(0, len(paymentMethods)-1).Draw(t, "paymentMethodIdx")]
@@ -70,7 +66,7 @@ func TestProperty_NewTransactionsHaveNoFinalizedAt(t *testing.T) {
 			},
 		}
 
-		result, err := uc.Execute(context.Background(), req)
+		result, _, err := uc.Execute(context.Background(), req)
 		if err != nil {
 			t.Fatalf("unexpected error from Execute: %v", err)
 		}
@@ -13,25 +13,63 @@ import (
 
 var ErrSaveTransactionFailed = errors.New("failed to save transaction")
 
+// DefaultIdempotencyKeyTTL is how long an idempotency key is remembered.
+const DefaultIdempotencyKeyTTL = 24 * time.Hour
+
 type SaveTransactionUseCase struct {
+	outboxRepo      repository.OutboxRepository
 	transactionRepo repository.TransactionRepository
-	eventPublisher  repository.TransactionEventPublisher
+	keyRepo         repository.IdempotencyKeyRepository
+	keyTTL          time.Duration
+	stream          *TransactionStream
+	stats           *TransactionStatsRecorder
 }
 
-func NewSaveTransactionUseCase(transactionRepo repository.TransactionRepository, eventPublisher repository.TransactionEventPublisher) *SaveTransactionUseCase {
+// NewSaveTransactionUseCase creates a new use case remembering idempotency keys
+// for keyTTL and replaying transactions as read from transactionRepo. Saved transactions are published to stream and counted by stats,
+// either of which may be nil.
+func NewSaveTransactionUseCase(
+	outboxRepo repository.OutboxRepository,
+	transactionRepo repository.TransactionRepository,
+	keyRepo repository.IdempotencyKeyRepository,
+	keyTTL time.Duration,
+	stream *TransactionStream,
+	stats *TransactionStatsRecorder,
+) *SaveTransactionUseCase {
+	if keyTTL <= 0 {
+		keyTTL = DefaultIdempotencyKeyTTL
+	}
+
 	return &SaveTransactionUseCase{
+		outboxRepo:      outboxRepo,
 		transactionRepo: transactionRepo,
-		eventPublisher:  eventPublisher,
+		keyRepo:         keyRepo,
+		keyTTL:          keyTTL,
+		stream:          stream,
+		stats:           stats,
 	}
 }
 
-func (uc *SaveTransactionUseCase) Execute(ctx context.Context, req *entity.EvaluateTransactionRequest) (*entity.TransactionEntity, error) {
+// Execute saves the transaction together with the outbox entry of its
+// Transaction.Created event. The event is published by RelayOutboxUseCase.
+// A request with an idempotency key that was already used returns the current
+// state of the transaction saved by the first request and replayed=true, or
+// ErrIdempotencyKeyConflict if the payload differs.
+func (uc *SaveTransactionUseCase) Execute(ctx context.Context, req *entity.EvaluateTransactionRequest) (transaction *entity.TransactionEntity, replayed bool, err error) {
 	if req == nil {
-		return nil, errors.New("request is nil")
+		return nil, false, errors.New("request is nil")
+	}
+
+	if req.Key() != "" {
+		if original, err := uc.replay(ctx, req); original != nil || err != nil {
+			return original, original != nil, err
+		}
 	}
 
+	now := time.Now().UTC()
+
 	// Create transaction entity from request
-	transaction := &entity.TransactionEntity{
+	transaction = &entity.TransactionEntity{
 		ID:                uuid.New().String(),
 		AmountInCents:     req.AmountInCents,
 		Currency:          req.Currency,
@@ -41,20 +79,70 @@ func (uc *SaveTransactionUseCase) Execute(ctx context.Context, req *entity.Evalu
 		CustomerEmail:     req.CustomerInfo.Email,
 		CustomerPhone:     req.CustomerInfo.Phone,
 		CustomerIPAddress: req.CustomerInfo.IpAddress,
+		ExternalID:        req.ExternalID,
+		MerchantID:        req.MerchantID,
 		Status:            entity.PENDING,
-		CreatedAt:         time.Now().UTC(),
-		UpdatedAt:         time.Now().UTC(),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	var key *entity.IdempotencyKey
+	if req.Key() != "" {
+		key = &entity.IdempotencyKey{
+			Key:         req.Key(),
+			Fingerprint: req.Fingerprint(),
+			Transaction: *transaction,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(uc.keyTTL),
+		}
 	}
 
-	// Save to repository
-	if err := uc.transactionRepo.Save(ctx, transaction); err != nil {
-		return nil, err
+	// Save the transaction, its event and its idempotency key atomically
+	entry := entity.NewTransactionCreatedEntry(uuid.New().String(), transaction)
+	if err := uc.outboxRepo.SaveTransaction(ctx, transaction, entry, key); err != nil {
+		// A concurrent request with the same key saved its transaction first
+		if errors.Is(err, entity.ErrIdempotencyKeyExists) {
+			if original, err := uc.replay(ctx, req); original != nil || err != nil {
+				return original, original != nil, err
+			}
+		}
+		return nil, false, fmt.Errorf("%w: %w", ErrSaveTransactionFailed, err)
 	}
 
-	// Publish transaction event to Kafka
-	if err := uc.eventPublisher.Publish(ctx, transaction); err != nil {
-		return nil, fmt.Errorf("failed to publish transaction event: %w", ErrEventPublishFailed)
+	if uc.stats != nil {
+		uc.stats.RecordChange(ctx, nil, transaction)
+	}
+	if uc.stream != nil {
+		uc.stream.PublishCreated(transaction)
+	}
+
+	return transaction, false, nil
+}
+
+// replay returns the transaction recorded for the request's idempotency key, or
+// nil if the key has not been used. The key holds the transaction as it was
+// saved, still PENDING, so the transaction is read again; the recorded copy is
+// returned only when the transaction cannot be found.
+func (uc *SaveTransactionUseCase) replay(ctx context.Context, req *entity.EvaluateTransactionRequest) (*entity.TransactionEntity, error) {
+	recorded, err := uc.keyRepo.Find(ctx, req.Key())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSaveTransactionFailed, err)
+	}
+	if recorded == nil {
+		return nil, nil
+	}
+
+	if recorded.Fingerprint != req.Fingerprint() {
+		return nil, fmt.Errorf("%w: %s", ErrIdempotencyKeyConflict, req.Key())
+	}
+
+	current, err := uc.transactionRepo.FindByID(ctx, recorded.Transaction.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSaveTransactionFailed, err)
+	}
+	if current != nil {
+		return current, nil
 	}
 
-	return transaction, nil
+	return &recorded.Transaction, nil
 }
@@ -3,58 +3,117 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"ms-transaction-evaluator/internal/domain/entity"
 	"testing"
 	"time"
+
+	"github.com/rs/zerolog"
 )
 
-type mockTransactionRepository struct {
-	saveFunc func(ctx context.Context, transaction *entity.TransactionEntity) error
+type mockOutboxRepository struct {
+	saveFunc    func(ctx context.Context, transaction *entity.TransactionEntity, entry *entity.OutboxEntry) error
+	findDueFunc func(ctx context.Context, now time.Time, limit int) ([]entity.OutboxEntry, error)
+	claimFunc   func(ctx context.Context, id string, nextAttemptAt time.Time, until time.Time) (bool, error)
+	markSentErr error
+	savedEntry  *entity.OutboxEntry
+	savedKey    *entity.IdempotencyKey
+	sent        []string
+	failed      map[string]outboxFailure
+	exhausted   map[string]outboxFailure
+}
+
+// outboxFailure records the arguments of a MarkFailed or MarkExhausted call.
+type outboxFailure struct {
+	attempts      int
+	lastError     string
+	nextAttemptAt time.Time
 }
 
-func (m *mockTransactionRepository) Save(ctx context.Context, transaction *entity.TransactionEntity) error {
+func (m *mockOutboxRepository) SaveTransaction(ctx context.Context, transaction *entity.TransactionEntity, entry *entity.OutboxEntry, key *entity.IdempotencyKey) error {
+	m.savedEntry = entry
+	m.savedKey = key
 	if m.saveFunc != nil {
-		return m.saveFunc(ctx, transaction)
+		return m.saveFunc(ctx, transaction, entry)
 	}
 	return nil
 }
 
-func (m *mockTransactionRepository) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) error {
+func (m *mockOutboxRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]entity.OutboxEntry, error) {
+	if m.findDueFunc != nil {
+		return m.findDueFunc(ctx, now, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockOutboxRepository) Claim(ctx context.Context, id string, nextAttemptAt time.Time, until time.Time) (bool, error) {
+	if m.claimFunc != nil {
+		return m.claimFunc(ctx, id, nextAttemptAt, until)
+	}
+	return true, nil
+}
+
+func (m *mockOutboxRepository) MarkSent(_ context.Context, id string, _ time.Time) error {
+	if m.markSentErr != nil {
+		return m.markSentErr
+	}
+	m.sent = append(m.sent, id)
 	return nil
 }
 
-func (m *mockTransactionRepository) FindByID(_ context.Context, _ string) (*entity.TransactionEntity, error) {
-	return nil, nil
+func (m *mockOutboxRepository) MarkFailed(_ context.Context, id string, attempts int, lastError string, nextAttemptAt time.Time) error {
+	if m.failed == nil {
+		m.failed = make(map[string]outboxFailure)
+	}
+	m.failed[id] = outboxFailure{attempts: attempts, lastError: lastError, nextAttemptAt: nextAttemptAt}
+	return nil
 }
 
-func (m *mockTransactionRepository) FindAllPaginated(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
-	return nil, "", nil
+func (m *mockOutboxRepository) MarkExhausted(_ context.Context, id string, attempts int, lastError string) error {
+	if m.exhausted == nil {
+		m.exhausted = make(map[string]outboxFailure)
+	}
+	m.exhausted[id] = outboxFailure{attempts: attempts, lastError: lastError}
+	return nil
 }
 
-func (m *mockTransactionRepository) FindAll(_ context.Context) ([]entity.TransactionEntity, error) {
-	return nil, nil
+type mockIdempotencyKeyRepository struct {
+	keys    map[string]*entity.IdempotencyKey
+	findErr error
+	finds   int
+}
+
+func (m *mockIdempotencyKeyRepository) Find(_ context.Context, key string) (*entity.IdempotencyKey, error) {
+	m.finds++
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	return m.keys[key], nil
 }
 
 type mockEventPublisher struct {
 	publishFunc func(ctx context.Context, transaction *entity.TransactionEntity) error
+	published   []string
 }
 
 func (m *mockEventPublisher) Publish(ctx context.Context, transaction *entity.TransactionEntity) error {
 	if m.publishFunc != nil {
-		return m.publishFunc(ctx, transaction)
+		if err := m.publishFunc(ctx, transaction); err != nil {
+			return err
+		}
 	}
+	m.published = append(m.published, transaction.ID)
 	return nil
 }
 
 func TestSaveTransactionUseCase_Execute(t *testing.T) {
 	tests := []struct {
-		name           string
-		request        *entity.EvaluateTransactionRequest
-		setupMock      func(*mockTransactionRepository)
-		setupPublisher func(*mockEventPublisher)
-		expectError    bool
-		errorMsg       string
-		checkSentinel  error
+		name          string
+		request       *entity.EvaluateTransactionRequest
+		setupMock     func(*mockOutboxRepository)
+		expectError   bool
+		errorMsg      string
+		checkSentinel error
 	}{
 		{
 			name: "successful save and publish",
@@ -70,21 +129,19 @@ func TestSaveTransactionUseCase_Execute(t *testing.T) {
 					IpAddress:  "192.168.1.1",
 				},
 			},
-			setupMock: func(m *mockTransactionRepository) {
-				m.saveFunc = func(ctx context.Context, transaction *entity.TransactionEntity) error {
+			setupMock: func(m *mockOutboxRepository) {
+				m.saveFunc = func(ctx context.Context, transaction *entity.TransactionEntity, entry *entity.OutboxEntry) error {
 					return nil
 				}
 			},
-			setupPublisher: func(m *mockEventPublisher) {},
-			expectError:    false,
+			expectError: false,
 		},
 		{
-			name:           "nil request",
-			request:        nil,
-			setupMock:      func(m *mockTransactionRepository) {},
-			setupPublisher: func(m *mockEventPublisher) {},
-			expectError:    true,
-			errorMsg:       "request is nil",
+			name:        "nil request",
+			request:     nil,
+			setupMock:   func(m *mockOutboxRepository) {},
+			expectError: true,
+			errorMsg:    "request is nil",
 		},
 		{
 			name: "repository save error",
@@ -100,54 +157,25 @@ func TestSaveTransactionUseCase_Execute(t *testing.T) {
 					IpAddress:  "192.168.1.1",
 				},
 			},
-			setupMock: func(m *mockTransactionRepository) {
-				m.saveFunc = func(ctx context.Context, transaction *entity.TransactionEntity) error {
+			setupMock: func(m *mockOutboxRepository) {
+				m.saveFunc = func(ctx context.Context, transaction *entity.TransactionEntity, entry *entity.OutboxEntry) error {
 					return errors.New("database error")
 				}
 			},
-			setupPublisher: func(m *mockEventPublisher) {},
-			expectError:    true,
-			errorMsg:       "database error",
-		},
-		{
-			name: "event publish failure wraps ErrEventPublishFailed",
-			request: &entity.EvaluateTransactionRequest{
-				AmountInCents: 5000,
-				Currency:      entity.EUR,
-				PaymentMethod: entity.BANK_TRANSFER,
-				CustomerInfo: entity.CustomerInfo{
-					CustomerID: "cust_456",
-					Name:       "Jane Doe",
-					Email:      "jane@example.com",
-					Phone:      "+0987654321",
-					IpAddress:  "10.0.0.1",
-				},
-			},
-			setupMock: func(m *mockTransactionRepository) {
-				m.saveFunc = func(ctx context.Context, transaction *entity.TransactionEntity) error {
-					return nil
-				}
-			},
-			setupPublisher: func(m *mockEventPublisher) {
-				m.publishFunc = func(ctx context.Context, transaction *entity.TransactionEntity) error {
-					return errors.New("kafka unavailable")
-				}
-			},
 			expectError:   true,
-			checkSentinel: ErrEventPublishFailed,
+			errorMsg:      "failed to save transaction: database error",
+			checkSentinel: ErrSaveTransactionFailed,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockRepo := &mockTransactionRepository{}
+			mockRepo := &mockOutboxRepository{}
 			tt.setupMock(mockRepo)
-			mockPub := &mockEventPublisher{}
-			tt.setupPublisher(mockPub)
-			useCase := NewSaveTransactionUseCase(mockRepo, mockPub)
+			useCase := NewSaveTransactionUseCase(mockRepo, &streamMockRepo{}, &mockIdempotencyKeyRepository{}, 0, nil, nil)
 
 			ctx := context.Background()
-			result, err := useCase.Execute(ctx, tt.request)
+			result, replayed, err := useCase.Execute(ctx, tt.request)
 
 			if tt.expectError {
 				if err == nil {
@@ -167,6 +195,12 @@ func TestSaveTransactionUseCase_Execute(t *testing.T) {
 				if err != nil {
 					t.Errorf("expected no error but got: %v", err)
 				}
+				if replayed {
+					t.Errorf("expected a new transaction, got a replay")
+				}
+				if mockRepo.savedKey != nil {
+					t.Errorf("expected no idempotency key, got %+v", mockRepo.savedKey)
+				}
 				if result == nil {
 					t.Errorf("expected result but got nil")
 				} else {
@@ -206,8 +240,218 @@ func TestSaveTransactionUseCase_Execute(t *testing.T) {
 					if result.UpdatedAt.IsZero() {
 						t.Errorf("expected UpdatedAt to be set")
 					}
+					entry := mockRepo.savedEntry
+					if entry == nil || entry.ID == "" || entry.TransactionID != result.ID || entry.Status != entity.OutboxPending {
+						t.Errorf("expected a pending outbox entry for the transaction, got %+v", entry)
+					} else if entry.Transaction.ID != result.ID || !entry.NextAttemptAt.Equal(result.CreatedAt) {
+						t.Errorf("expected the entry to carry the transaction and be due immediately, got %+v", entry)
+					}
 				}
 			}
 		})
 	}
 }
+
+func newIdempotentRequest(key string) *entity.EvaluateTransactionRequest {
+	return &entity.EvaluateTransactionRequest{
+		AmountInCents:  10000,
+		Currency:       entity.USD,
+		PaymentMethod:  entity.CARD,
+		ExternalID:     "order-1234",
+		IdempotencyKey: key,
+		CustomerInfo: entity.CustomerInfo{
+			CustomerID: "cust_123",
+			Name:       "John Doe",
+			Email:      "john@example.com",
+			Phone:      "+1234567890",
+			IpAddress:  "192.168.1.1",
+		},
+	}
+}
+
+func TestSaveTransactionUseCase_Execute_IdempotencyKey(t *testing.T) {
+	t.Run("first request records the key with the transaction", func(t *testing.T) {
+		repo := &mockOutboxRepository{}
+		useCase := NewSaveTransactionUseCase(repo, &streamMockRepo{}, &mockIdempotencyKeyRepository{}, time.Hour, nil, nil)
+		req := newIdempotentRequest("key-1")
+
+		result, replayed, err := useCase.Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if replayed {
+			t.Error("expected a new transaction, got a replay")
+		}
+		if result.ExternalID != "order-1234" {
+			t.Errorf("expected external ID order-1234, got %q", result.ExternalID)
+		}
+
+		key := repo.savedKey
+		if key == nil {
+			t.Fatal("expected the idempotency key to be saved")
+		}
+		if key.Key != "key-1" || key.Fingerprint != req.Fingerprint() || key.Transaction.ID != result.ID {
+			t.Errorf("unexpected idempotency key %+v", key)
+		}
+		if key.ExpiresAt.Sub(key.CreatedAt) != time.Hour {
+			t.Errorf("expected the key to expire after 1h, got %v", key.ExpiresAt.Sub(key.CreatedAt))
+		}
+	})
+
+	t.Run("external ID is the key when the header is absent", func(t *testing.T) {
+		repo := &mockOutboxRepository{}
+		useCase := NewSaveTransactionUseCase(repo, &streamMockRepo{}, &mockIdempotencyKeyRepository{}, 0, nil, nil)
+
+		if _, _, err := useCase.Execute(context.Background(), newIdempotentRequest("")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repo.savedKey == nil || repo.savedKey.Key != "order-1234" {
+			t.Errorf("expected the external ID as key, got %+v", repo.savedKey)
+		}
+	})
+
+	t.Run("retry with the same payload replays the recorded transaction when it is not found", func(t *testing.T) {
+		req := newIdempotentRequest("key-1")
+		original := entity.TransactionEntity{ID: "txn-original", Status: entity.PENDING}
+		keys := &mockIdempotencyKeyRepository{keys: map[string]*entity.IdempotencyKey{
+			"key-1": {Key: "key-1", Fingerprint: req.Fingerprint(), Transaction: original},
+		}}
+		repo := &mockOutboxRepository{}
+
+		result, replayed, err := NewSaveTransactionUseCase(repo, &streamMockRepo{}, keys, 0, nil, nil).Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !replayed || result.ID != "txn-original" {
+			t.Errorf("expected a replay of txn-original, got %v %+v", replayed, result)
+		}
+		if repo.savedEntry != nil {
+			t.Error("expected nothing to be saved on replay")
+		}
+	})
+
+	t.Run("retry after a status update replays the current transaction", func(t *testing.T) {
+		req := newIdempotentRequest("key-1")
+		store := &pendingTransactionStore{transactions: map[string]*entity.TransactionEntity{}}
+		keys := &mockIdempotencyKeyRepository{keys: map[string]*entity.IdempotencyKey{}}
+		repo := &mockOutboxRepository{saveFunc: func(_ context.Context, transaction *entity.TransactionEntity, _ *entity.OutboxEntry) error {
+			saved := *transaction
+			store.transactions[transaction.ID] = &saved
+			return nil
+		}}
+		uc := NewSaveTransactionUseCase(repo, store, keys, 0, nil, nil)
+
+		saved, _, err := uc.Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		keys.keys["key-1"] = repo.savedKey
+
+		decision := &entity.DecisionCalculatedMessage{TransactionID: saved.ID, Status: "APPROVED", DecisionPath: "DIRECT"}
+		if err := NewUpdateTransactionStatusUseCase(store, nil).Execute(context.Background(), decision); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		result, replayed, err := uc.Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !replayed || result.ID != saved.ID || result.Status != entity.APPROVED || result.FinalizedAt == nil {
+			t.Errorf("expected a replay of the approved transaction, got %v %+v", replayed, result)
+		}
+	})
+
+	t.Run("transaction lookup failure on replay wraps ErrSaveTransactionFailed", func(t *testing.T) {
+		req := newIdempotentRequest("key-1")
+		keys := &mockIdempotencyKeyRepository{keys: map[string]*entity.IdempotencyKey{
+			"key-1": {Key: "key-1", Fingerprint: req.Fingerprint(), Transaction: entity.TransactionEntity{ID: "txn-original", Status: entity.PENDING}},
+		}}
+		transactions := &streamMockRepo{findErr: errors.New("throttled")}
+
+		_, _, err := NewSaveTransactionUseCase(&mockOutboxRepository{}, transactions, keys, 0, nil, nil).Execute(context.Background(), req)
+		if !errors.Is(err, ErrSaveTransactionFailed) {
+			t.Errorf("expected ErrSaveTransactionFailed, got %v", err)
+		}
+	})
+
+	t.Run("reused key with a different payload conflicts", func(t *testing.T) {
+		keys := &mockIdempotencyKeyRepository{keys: map[string]*entity.IdempotencyKey{
+			"key-1": {Key: "key-1", Fingerprint: "other", Transaction: entity.TransactionEntity{ID: "txn-original"}},
+		}}
+
+		result, _, err := NewSaveTransactionUseCase(&mockOutboxRepository{}, &streamMockRepo{}, keys, 0, nil, nil).Execute(context.Background(), newIdempotentRequest("key-1"))
+		if !errors.Is(err, ErrIdempotencyKeyConflict) {
+			t.Errorf("expected ErrIdempotencyKeyConflict, got %v", err)
+		}
+		if result != nil {
+			t.Errorf("expected nil result, got %+v", result)
+		}
+	})
+
+	t.Run("concurrent request that recorded the key first is replayed", func(t *testing.T) {
+		req := newIdempotentRequest("key-1")
+		keys := &mockIdempotencyKeyRepository{keys: map[string]*entity.IdempotencyKey{}}
+		repo := &mockOutboxRepository{saveFunc: func(_ context.Context, _ *entity.TransactionEntity, _ *entity.OutboxEntry) error {
+			keys.keys["key-1"] = &entity.IdempotencyKey{Key: "key-1", Fingerprint: req.Fingerprint(), Transaction: entity.TransactionEntity{ID: "txn-winner"}}
+			return fmt.Errorf("%w: key-1", entity.ErrIdempotencyKeyExists)
+		}}
+
+		result, replayed, err := NewSaveTransactionUseCase(repo, &streamMockRepo{}, keys, 0, nil, nil).Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !replayed || result.ID != "txn-winner" {
+			t.Errorf("expected a replay of txn-winner, got %v %+v", replayed, result)
+		}
+		if keys.finds != 2 {
+			t.Errorf("expected the key to be looked up twice, got %d", keys.finds)
+		}
+	})
+
+	t.Run("key lookup failure wraps ErrSaveTransactionFailed", func(t *testing.T) {
+		keys := &mockIdempotencyKeyRepository{findErr: errors.New("throttled")}
+
+		_, _, err := NewSaveTransactionUseCase(&mockOutboxRepository{}, &streamMockRepo{}, keys, 0, nil, nil).Execute(context.Background(), newIdempotentRequest("key-1"))
+		if !errors.Is(err, ErrSaveTransactionFailed) {
+			t.Errorf("expected ErrSaveTransactionFailed, got %v", err)
+		}
+	})
+}
+
+func TestSaveTransactionUseCase_Execute_Stream(t *testing.T) {
+	t.Run("publishes the saved transaction", func(t *testing.T) {
+		stream := NewTransactionStream(&streamMockRepo{}, 0, 0, zerolog.Nop())
+		sub := stream.Subscribe(TransactionStreamFilter{}, "")
+		defer sub.Close()
+
+		result, _, err := NewSaveTransactionUseCase(&mockOutboxRepository{}, &streamMockRepo{}, &mockIdempotencyKeyRepository{}, 0, stream, nil).Execute(context.Background(), newIdempotentRequest("key-1"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		event := receive(t, sub)
+		if event.Type != entity.TransactionEventCreated || event.Transaction.ID != result.ID {
+			t.Errorf("expected %s created, got %s %s", result.ID, event.Type, event.Transaction.ID)
+		}
+	})
+
+	t.Run("does not publish a replay", func(t *testing.T) {
+		req := newIdempotentRequest("key-1")
+		keys := &mockIdempotencyKeyRepository{keys: map[string]*entity.IdempotencyKey{
+			"key-1": {Key: "key-1", Fingerprint: req.Fingerprint(), Transaction: entity.TransactionEntity{ID: "txn-original"}},
+		}}
+		stream := NewTransactionStream(&streamMockRepo{}, 0, 0, zerolog.Nop())
+		sub := stream.Subscribe(TransactionStreamFilter{}, "")
+		defer sub.Close()
+
+		if _, _, err := NewSaveTransactionUseCase(&mockOutboxRepository{}, &streamMockRepo{}, keys, 0, stream, nil).Execute(context.Background(), req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		select {
+		case event := <-sub.Events:
+			t.Errorf("expected no event, got %s", event.Transaction.ID)
+		default:
+		}
+	})
+}
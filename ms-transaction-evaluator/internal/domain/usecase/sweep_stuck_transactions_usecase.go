@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+	"ms-transaction-evaluator/internal/infrastructure/telemetry"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultStuckTransactionSLA is how long a transaction may stay PENDING before
+	// the sweeper acts on it.
+	DefaultStuckTransactionSLA = 5 * time.Minute
+	// DefaultStuckTransactionMaxRepublishes is how many times a stuck transaction is
+	// republished before the fallback status is applied.
+	DefaultStuckTransactionMaxRepublishes = 3
+	// DefaultStuckTransactionBatchSize is the number of stuck transactions handled
+	// per sweep.
+	DefaultStuckTransactionBatchSize = 100
+)
+
+// fallbackStatuses are the statuses a stuck transaction may be resolved with.
+var fallbackStatuses = map[entity.TransactionStatus]bool{
+	entity.APPROVED:     true,
+	entity.DECLINED:     true,
+	entity.EXPIRED:      true,
+	entity.NEEDS_REVIEW: true,
+}
+
+// ParseFallbackStatus returns the fallback status named by s.
+func ParseFallbackStatus(s string) (entity.TransactionStatus, error) {
+	status := entity.TransactionStatus(s)
+	if !fallbackStatuses[status] {
+		return "", fmt.Errorf("%w: %s", ErrInvalidFallbackStatus, s)
+	}
+	return status, nil
+}
+
+// StuckTransactionPolicy configures when and how stuck transactions are resolved.
+type StuckTransactionPolicy struct {
+	// SLA is how long a transaction may stay PENDING, and how long the sweeper
+	// waits after each republish before acting again.
+	SLA time.Duration
+	// MaxRepublishes is how many times the transaction is republished to
+	// Transaction.Created before Fallback is applied.
+	MaxRepublishes int
+	// Fallback is the status applied once the republishes are exhausted.
+	Fallback entity.TransactionStatus
+	// BatchSize is the number of stuck transactions handled per sweep.
+	BatchSize int
+}
+
+// SweepStuckTransactionsUseCase finds transactions left PENDING past the SLA,
+// republishes them and eventually applies the fallback status.
+type SweepStuckTransactionsUseCase struct {
+	stuckRepo   repository.StuckTransactionRepository
+	republisher repository.TransactionRepublisher
+	finalizer   *TransactionFinalizer
+	policy      StuckTransactionPolicy
+	logger      zerolog.Logger
+	now         func() time.Time
+}
+
+// NewSweepStuckTransactionsUseCase creates a new use case. A non-positive SLA or
+// batch size and a negative MaxRepublishes take their defaults; the fallback
+// defaults to APPROVED (fail-open). Resolved transactions are announced through
+// finalizer like decided ones; it may be nil.
+func NewSweepStuckTransactionsUseCase(
+	stuckRepo repository.StuckTransactionRepository,
+	republisher repository.TransactionRepublisher,
+	finalizer *TransactionFinalizer,
+	policy StuckTransactionPolicy,
+	logger zerolog.Logger,
+) *SweepStuckTransactionsUseCase {
+	if policy.SLA <= 0 {
+		policy.SLA = DefaultStuckTransactionSLA
+	}
+	if policy.MaxRepublishes < 0 {
+		policy.MaxRepublishes = DefaultStuckTransactionMaxRepublishes
+	}
+	if policy.Fallback == "" {
+		policy.Fallback = entity.APPROVED
+	}
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = DefaultStuckTransactionBatchSize
+	}
+
+	return &SweepStuckTransactionsUseCase{
+		stuckRepo:   stuckRepo,
+		republisher: republisher,
+		finalizer:   finalizer,
+		policy:      policy,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Execute handles one batch of stuck transactions and returns how many were
+// republished and how many were given the fallback status. A transaction decided
+// while the sweep runs is left unchanged.
+func (uc *SweepStuckTransactionsUseCase) Execute(ctx context.Context) (republished, resolved int, err error) {
+	now := uc.now().UTC()
+
+	stuck, err := uc.stuckRepo.FindStuck(ctx, now.Add(-uc.policy.SLA), uc.policy.BatchSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %w", ErrStuckTransactionsRetrievalFailed, err)
+	}
+	telemetry.StuckTransactionsDetected.Add(float64(len(stuck)))
+
+	for i := range stuck {
+		if stuck[i].RepublishAttempts < uc.policy.MaxRepublishes {
+			if uc.republish(ctx, &stuck[i], now) {
+				republished++
+			}
+			continue
+		}
+
+		if uc.resolve(ctx, &stuck[i], now) {
+			resolved++
+		}
+	}
+
+	return republished, resolved, nil
+}
+
+// Run sweeps every interval until ctx is cancelled.
+func (uc *SweepStuckTransactionsUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			republished, resolved, err := uc.Execute(ctx)
+			if err != nil {
+				uc.logger.Warn().Err(err).Msg("stuck transaction sweep failed")
+				continue
+			}
+			if republished > 0 || resolved > 0 {
+				uc.logger.Info().
+					Int("republished", republished).
+					Int("resolved", resolved).
+					Msg("stuck transaction sweep completed")
+			}
+		}
+	}
+}
+
+// republish publishes the transaction again and records the attempt.
+func (uc *SweepStuckTransactionsUseCase) republish(ctx context.Context, stuck *entity.StuckTransaction, now time.Time) bool {
+	txn := &stuck.Transaction
+	attempt := stuck.RepublishAttempts + 1
+
+	if err := uc.republisher.Republish(ctx, txn, attempt); err != nil {
+		telemetry.StuckTransactionSweepErrors.WithLabelValues("republish").Inc()
+		uc.logger.Error().Err(err).
+			Str("transaction_id", txn.ID).
+			Int("attempt", attempt).
+			Msg("failed to republish stuck transaction")
+		return false
+	}
+
+	// An unrecorded attempt is republished again by the next sweep, which the
+	// decision service handles like the first.
+	if err := uc.stuckRepo.RecordRepublish(ctx, txn.ID, attempt, now); err != nil {
+		uc.logger.Error().Err(err).
+			Str("transaction_id", txn.ID).
+			Int("attempt", attempt).
+			Msg("stuck transaction republished but attempt not recorded")
+	}
+
+	telemetry.StuckTransactionsRepublished.Inc()
+	uc.logger.Warn().
+		Str("transaction_id", txn.ID).
+		Time("created_at", txn.CreatedAt).
+		Dur("pending_for", now.Sub(txn.CreatedAt)).
+		Int("attempt", attempt).
+		Int("max_attempts", uc.policy.MaxRepublishes).
+		Msg("stuck transaction republished")
+
+	return true
+}
+
+// resolve applies the fallback status. APPROVED, DECLINED and EXPIRED finalize
+// the transaction; NEEDS_REVIEW leaves it to a reviewer.
+func (uc *SweepStuckTransactionsUseCase) resolve(ctx context.Context, stuck *entity.StuckTransaction, now time.Time) bool {
+	txn := &stuck.Transaction
+	status := uc.policy.Fallback
+
+	var finalizedAt *time.Time
+	if status != entity.NEEDS_REVIEW {
+		finalizedAt = &now
+	}
+
+	ok, err := uc.stuckRepo.Resolve(ctx, txn.ID, status, finalizedAt)
+	if err != nil {
+		telemetry.StuckTransactionSweepErrors.WithLabelValues("resolve").Inc()
+		uc.logger.Error().Err(err).
+			Str("transaction_id", txn.ID).
+			Str("status", string(status)).
+			Msg("failed to resolve stuck transaction")
+		return false
+	}
+	if !ok {
+		uc.logger.Info().
+			Str("transaction_id", txn.ID).
+			Msg("stuck transaction decided before fallback, left unchanged")
+		return false
+	}
+
+	// The fallback cannot be applied again, so a failed announcement is only logged
+	if uc.finalizer != nil {
+		resolved := *txn
+		resolved.Status = status
+		resolved.FinalizedAt = finalizedAt
+		resolved.UpdatedAt = now
+		if err := uc.finalizer.Finalize(ctx, txn.ID, status, txn, &resolved); err != nil {
+			telemetry.StuckTransactionSweepErrors.WithLabelValues("finalize").Inc()
+			uc.logger.Error().Err(err).
+				Str("transaction_id", txn.ID).
+				Str("status", string(status)).
+				Msg("stuck transaction resolved but not announced")
+		}
+	}
+
+	telemetry.StuckTransactionsResolved.WithLabelValues(string(status)).Inc()
+	uc.logger.Warn().
+		Str("transaction_id", txn.ID).
+		Time("created_at", txn.CreatedAt).
+		Dur("pending_for", now.Sub(txn.CreatedAt)).
+		Int("republish_attempts", stuck.RepublishAttempts).
+		Str("status", string(status)).
+		Msg("stuck transaction resolved with fallback status")
+
+	return true
+}
@@ -0,0 +1,291 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// --- Mock StuckTransactionRepository ---
+
+type mockStuckTransactionRepository struct {
+	stuck       []entity.StuckTransaction
+	findErr     error
+	cutoff      time.Time
+	decided     map[string]bool
+	resolveErr  error
+	republishes map[string]int
+	resolved    map[string]entity.TransactionStatus
+	finalizedAt map[string]*time.Time
+}
+
+func (m *mockStuckTransactionRepository) FindStuck(_ context.Context, cutoff time.Time, limit int) ([]entity.StuckTransaction, error) {
+	m.cutoff = cutoff
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	if limit < len(m.stuck) {
+		return m.stuck[:limit], nil
+	}
+	return m.stuck, nil
+}
+
+func (m *mockStuckTransactionRepository) RecordRepublish(_ context.Context, id string, attempts int, _ time.Time) error {
+	if m.republishes == nil {
+		m.republishes = make(map[string]int)
+	}
+	m.republishes[id] = attempts
+	return nil
+}
+
+func (m *mockStuckTransactionRepository) Resolve(_ context.Context, id string, status entity.TransactionStatus, finalizedAt *time.Time) (bool, error) {
+	if m.resolveErr != nil {
+		return false, m.resolveErr
+	}
+	if m.decided[id] {
+		return false, nil
+	}
+	if m.resolved == nil {
+		m.resolved = make(map[string]entity.TransactionStatus)
+		m.finalizedAt = make(map[string]*time.Time)
+	}
+	m.resolved[id] = status
+	m.finalizedAt[id] = finalizedAt
+	return true, nil
+}
+
+// --- Mock TransactionRepublisher ---
+
+type mockTransactionRepublisher struct {
+	err      error
+	attempts map[string]int
+}
+
+func (m *mockTransactionRepublisher) Republish(_ context.Context, transaction *entity.TransactionEntity, attempt int) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.attempts == nil {
+		m.attempts = make(map[string]int)
+	}
+	m.attempts[transaction.ID] = attempt
+	return nil
+}
+
+var sweepNow = time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+func newStuckTransaction(id string, attempts int) entity.StuckTransaction {
+	return entity.StuckTransaction{
+		Transaction: entity.TransactionEntity{
+			ID:        id,
+			Status:    entity.PENDING,
+			CreatedAt: sweepNow.Add(-time.Hour),
+		},
+		RepublishAttempts: attempts,
+	}
+}
+
+func newTestSweepUseCase(
+	repo *mockStuckTransactionRepository,
+	republisher *mockTransactionRepublisher,
+	policy StuckTransactionPolicy,
+) *SweepStuckTransactionsUseCase {
+	uc := NewSweepStuckTransactionsUseCase(repo, republisher, nil, policy, zerolog.Nop())
+	uc.now = func() time.Time { return sweepNow }
+	return uc
+}
+
+func TestSweepStuckTransactionsUseCase_Execute(t *testing.T) {
+	policy := StuckTransactionPolicy{SLA: 10 * time.Minute, MaxRepublishes: 2, Fallback: entity.EXPIRED}
+
+	t.Run("republishes until the attempts are exhausted, then applies the fallback", func(t *testing.T) {
+		repo := &mockStuckTransactionRepository{
+			stuck: []entity.StuckTransaction{newStuckTransaction("tx-new", 0), newStuckTransaction("tx-retried", 1), newStuckTransaction("tx-exhausted", 2)},
+		}
+		republisher := &mockTransactionRepublisher{}
+
+		republished, resolved, err := newTestSweepUseCase(repo, republisher, policy).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if republished != 2 || resolved != 1 {
+			t.Errorf("expected 2 republished and 1 resolved, got %d and %d", republished, resolved)
+		}
+		if want := sweepNow.Add(-10 * time.Minute); !repo.cutoff.Equal(want) {
+			t.Errorf("expected cutoff %v, got %v", want, repo.cutoff)
+		}
+		if republisher.attempts["tx-new"] != 1 || republisher.attempts["tx-retried"] != 2 {
+			t.Errorf("unexpected republish attempts %v", republisher.attempts)
+		}
+		if repo.republishes["tx-new"] != 1 || repo.republishes["tx-retried"] != 2 {
+			t.Errorf("expected the attempts recorded, got %v", repo.republishes)
+		}
+		if _, ok := republisher.attempts["tx-exhausted"]; ok {
+			t.Error("expected the exhausted transaction not to be republished")
+		}
+		if repo.resolved["tx-exhausted"] != entity.EXPIRED {
+			t.Errorf("expected tx-exhausted EXPIRED, got %v", repo.resolved)
+		}
+		if finalizedAt := repo.finalizedAt["tx-exhausted"]; finalizedAt == nil || !finalizedAt.Equal(sweepNow) {
+			t.Errorf("expected finalized_at %v, got %v", sweepNow, finalizedAt)
+		}
+	})
+
+	t.Run("NEEDS_REVIEW fallback does not finalize the transaction", func(t *testing.T) {
+		repo := &mockStuckTransactionRepository{stuck: []entity.StuckTransaction{newStuckTransaction("tx-1", 2)}}
+		review := policy
+		review.Fallback = entity.NEEDS_REVIEW
+
+		if _, _, err := newTestSweepUseCase(repo, &mockTransactionRepublisher{}, review).Execute(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repo.resolved["tx-1"] != entity.NEEDS_REVIEW || repo.finalizedAt["tx-1"] != nil {
+			t.Errorf("expected NEEDS_REVIEW without finalized_at, got %v %v", repo.resolved["tx-1"], repo.finalizedAt["tx-1"])
+		}
+	})
+
+	t.Run("zero max republishes applies the fallback immediately", func(t *testing.T) {
+		repo := &mockStuckTransactionRepository{stuck: []entity.StuckTransaction{newStuckTransaction("tx-1", 0)}}
+		republisher := &mockTransactionRepublisher{}
+		noRepublish := policy
+		noRepublish.MaxRepublishes = 0
+
+		republished, resolved, err := newTestSweepUseCase(repo, republisher, noRepublish).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if republished != 0 || resolved != 1 || len(republisher.attempts) != 0 {
+			t.Errorf("expected only the fallback, got %d republished %d resolved", republished, resolved)
+		}
+	})
+
+	t.Run("transaction decided before the fallback is not counted", func(t *testing.T) {
+		repo := &mockStuckTransactionRepository{
+			stuck:   []entity.StuckTransaction{newStuckTransaction("tx-1", 2)},
+			decided: map[string]bool{"tx-1": true},
+		}
+
+		_, resolved, err := newTestSweepUseCase(repo, &mockTransactionRepublisher{}, policy).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resolved != 0 {
+			t.Errorf("expected nothing resolved, got %d", resolved)
+		}
+	})
+
+	t.Run("resolved and decided transactions are counted by the stats", func(t *testing.T) {
+		repo := &mockStuckTransactionRepository{
+			stuck:   []entity.StuckTransaction{newStuckTransaction("tx-1", 2), newStuckTransaction("tx-2", 2)},
+			decided: map[string]bool{"tx-2": true},
+		}
+		stats := &recordingStatsRepo{}
+		uc := newTestSweepUseCase(repo, &mockTransactionRepublisher{}, policy)
+		uc.finalizer = NewTransactionFinalizer(nil, NewTransactionStatsRecorder(stats, zerolog.Nop()), nil, nil)
+
+		if _, _, err := uc.Execute(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(stats.added) != 1 {
+			t.Fatalf("expected one delta, got %d", len(stats.added))
+		}
+		if delta := stats.added[0]; delta.Statuses[entity.PENDING] != -1 || delta.Statuses[entity.EXPIRED] != 1 || delta.LatencySumMs != time.Hour.Milliseconds() {
+			t.Errorf("unexpected delta %+v", delta)
+		}
+	})
+
+	t.Run("resolved transactions are announced like decided ones", func(t *testing.T) {
+		repo := &mockStuckTransactionRepository{stuck: []entity.StuckTransaction{newStuckTransaction("tx-1", 2)}}
+		endpoints := &mockWebhookEndpointRepository{endpoints: []entity.WebhookEndpoint{{ID: "global", URL: "https://example.com/all"}}}
+		deliveries := newMockWebhookDeliveryRepository()
+		waiters := NewDecisionWaiters()
+		decisions, unregister := waiters.Register("tx-1")
+		defer unregister()
+		stream := NewTransactionStream(&streamMockRepo{}, 0, 0, zerolog.Nop())
+		sub := stream.Subscribe(TransactionStreamFilter{}, "")
+		defer sub.Close()
+
+		uc := newTestSweepUseCase(repo, &mockTransactionRepublisher{}, policy)
+		uc.finalizer = NewTransactionFinalizer(newTestEnqueueUseCase(endpoints, deliveries), nil, waiters, stream)
+
+		if _, _, err := uc.Execute(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(deliveries.deliveries) != 1 {
+			t.Errorf("expected a webhook delivery, got %d", len(deliveries.deliveries))
+		}
+		select {
+		case status := <-decisions:
+			if status != entity.EXPIRED {
+				t.Errorf("expected the waiter to see EXPIRED, got %s", status)
+			}
+		default:
+			t.Error("expected the waiter to be notified")
+		}
+		select {
+		case event := <-sub.Events:
+			if event.Type != entity.TransactionEventFinalized || event.Transaction.Status != entity.EXPIRED {
+				t.Errorf("expected tx-1 finalized as EXPIRED, got %s %+v", event.Type, event.Transaction)
+			}
+		default:
+			t.Error("expected a stream event")
+		}
+	})
+
+	t.Run("failed republish is not recorded", func(t *testing.T) {
+		repo := &mockStuckTransactionRepository{stuck: []entity.StuckTransaction{newStuckTransaction("tx-1", 0)}}
+		republisher := &mockTransactionRepublisher{err: errors.New("broker unavailable")}
+
+		republished, _, err := newTestSweepUseCase(repo, republisher, policy).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if republished != 0 || len(repo.republishes) != 0 {
+			t.Errorf("expected no recorded republish, got %d %v", republished, repo.republishes)
+		}
+	})
+
+	t.Run("failed resolve continues with the next transaction", func(t *testing.T) {
+		repo := &mockStuckTransactionRepository{
+			stuck:      []entity.StuckTransaction{newStuckTransaction("tx-1", 2), newStuckTransaction("tx-2", 0)},
+			resolveErr: errors.New("throttled"),
+		}
+
+		republished, resolved, err := newTestSweepUseCase(repo, &mockTransactionRepublisher{}, policy).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if republished != 1 || resolved != 0 {
+			t.Errorf("expected 1 republished and 0 resolved, got %d and %d", republished, resolved)
+		}
+	})
+
+	t.Run("retrieval failure wraps ErrStuckTransactionsRetrievalFailed", func(t *testing.T) {
+		repo := &mockStuckTransactionRepository{findErr: errors.New("database error")}
+
+		_, _, err := newTestSweepUseCase(repo, &mockTransactionRepublisher{}, policy).Execute(context.Background())
+		if !errors.Is(err, ErrStuckTransactionsRetrievalFailed) {
+			t.Errorf("expected ErrStuckTransactionsRetrievalFailed, got %v", err)
+		}
+	})
+}
+
+func TestParseFallbackStatus(t *testing.T) {
+	for _, s := range []string{"APPROVED", "DECLINED", "EXPIRED", "NEEDS_REVIEW"} {
+		status, err := ParseFallbackStatus(s)
+		if err != nil || string(status) != s {
+			t.Errorf("ParseFallbackStatus(%q) = %q, %v", s, status, err)
+		}
+	}
+
+	for _, s := range []string{"", "PENDING", "approved"} {
+		if _, err := ParseFallbackStatus(s); !errors.Is(err, ErrInvalidFallbackStatus) {
+			t.Errorf("ParseFallbackStatus(%q): expected ErrInvalidFallbackStatus, got %v", s, err)
+		}
+	}
+}
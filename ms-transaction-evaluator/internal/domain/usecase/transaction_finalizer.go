@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"ms-transaction-evaluator/internal/domain/entity"
+)
+
+// TransactionFinalizer announces that a transaction has left PENDING: it updates
+// the stats counters, enqueues the webhook deliveries, wakes the requests waiting
+// for the decision and publishes the transaction.finalized event. The decision
+// consumer and the stuck transaction sweeper share it, so a transaction is
+// announced the same way whichever of them resolved it.
+type TransactionFinalizer struct {
+	webhooks *EnqueueWebhookDeliveriesUseCase
+	stats    *TransactionStatsRecorder
+	waiters  *DecisionWaiters
+	stream   *TransactionStream
+}
+
+// NewTransactionFinalizer creates a new finalizer. Any of webhooks, stats, waiters
+// and stream may be nil, in which case it is not notified.
+func NewTransactionFinalizer(
+	webhooks *EnqueueWebhookDeliveriesUseCase,
+	stats *TransactionStatsRecorder,
+	waiters *DecisionWaiters,
+	stream *TransactionStream,
+) *TransactionFinalizer {
+	return &TransactionFinalizer{webhooks: webhooks, stats: stats, waiters: waiters, stream: stream}
+}
+
+// Finalize announces that the transaction with the given ID now has status.
+// before is the transaction as it was before the change and after as it is now;
+// either may be nil when it was not read. The counters need both and the webhook
+// deliveries need after. A failure to enqueue the deliveries is returned before
+// the waiters and the stream are notified, so that the change can be retried; the
+// deliveries already enqueued are not duplicated.
+func (f *TransactionFinalizer) Finalize(
+	ctx context.Context,
+	transactionID string,
+	status entity.TransactionStatus,
+	before, after *entity.TransactionEntity,
+) error {
+	if after != nil {
+		if f.stats != nil && before != nil {
+			f.stats.RecordChange(ctx, before, after)
+		}
+
+		if f.webhooks != nil {
+			if _, err := f.webhooks.Execute(ctx, after); err != nil {
+				return err
+			}
+		}
+	}
+
+	if f.waiters != nil {
+		f.waiters.Notify(transactionID, status)
+	}
+	if f.stream != nil {
+		f.stream.PublishFinalized(ctx, transactionID, status)
+	}
+
+	return nil
+}
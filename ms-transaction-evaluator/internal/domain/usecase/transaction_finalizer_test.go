@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestTransactionFinalizer_Finalize(t *testing.T) {
+	finalizedAt := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	before := &entity.TransactionEntity{ID: "tx-1", Status: entity.PENDING, CreatedAt: finalizedAt.Add(-time.Second)}
+	after := &entity.TransactionEntity{ID: "tx-1", Status: entity.APPROVED, CreatedAt: before.CreatedAt, FinalizedAt: &finalizedAt}
+
+	t.Run("notifies every follower", func(t *testing.T) {
+		stats := &recordingStatsRepo{}
+		endpoints := &mockWebhookEndpointRepository{endpoints: []entity.WebhookEndpoint{{ID: "global", URL: "https://example.com/all"}}}
+		deliveries := newMockWebhookDeliveryRepository()
+		waiters := NewDecisionWaiters()
+		decisions, unregister := waiters.Register("tx-1")
+		defer unregister()
+
+		finalizer := NewTransactionFinalizer(newTestEnqueueUseCase(endpoints, deliveries), NewTransactionStatsRecorder(stats, zerolog.Nop()), waiters, nil)
+		if err := finalizer.Finalize(context.Background(), "tx-1", entity.APPROVED, before, after); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(stats.added) != 1 || len(deliveries.deliveries) != 1 {
+			t.Errorf("expected the counters and a delivery, got %d deltas and %d deliveries", len(stats.added), len(deliveries.deliveries))
+		}
+		select {
+		case status := <-decisions:
+			if status != entity.APPROVED {
+				t.Errorf("expected APPROVED, got %s", status)
+			}
+		default:
+			t.Error("expected the waiter to be notified")
+		}
+	})
+
+	t.Run("enqueue failure is returned before the waiters are notified", func(t *testing.T) {
+		endpoints := &mockWebhookEndpointRepository{findErr: errors.New("throttled")}
+		waiters := NewDecisionWaiters()
+		decisions, unregister := waiters.Register("tx-1")
+		defer unregister()
+
+		finalizer := NewTransactionFinalizer(newTestEnqueueUseCase(endpoints, newMockWebhookDeliveryRepository()), nil, waiters, nil)
+		if err := finalizer.Finalize(context.Background(), "tx-1", entity.APPROVED, before, after); !errors.Is(err, ErrWebhookEnqueueFailed) {
+			t.Fatalf("expected ErrWebhookEnqueueFailed, got %v", err)
+		}
+
+		select {
+		case status := <-decisions:
+			t.Errorf("expected no notification, got %s", status)
+		default:
+		}
+	})
+
+	t.Run("an unread transaction only wakes the waiters", func(t *testing.T) {
+		stats := &recordingStatsRepo{}
+		waiters := NewDecisionWaiters()
+		decisions, unregister := waiters.Register("tx-1")
+		defer unregister()
+
+		finalizer := NewTransactionFinalizer(nil, NewTransactionStatsRecorder(stats, zerolog.Nop()), waiters, nil)
+		if err := finalizer.Finalize(context.Background(), "tx-1", entity.DECLINED, before, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(stats.added) != 0 {
+			t.Errorf("expected no counters without the transaction, got %d deltas", len(stats.added))
+		}
+		select {
+		case status := <-decisions:
+			if status != entity.DECLINED {
+				t.Errorf("expected DECLINED, got %s", status)
+			}
+		default:
+			t.Error("expected the waiter to be notified")
+		}
+	})
+}
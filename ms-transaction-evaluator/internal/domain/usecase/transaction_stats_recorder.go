@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+	"ms-transaction-evaluator/internal/infrastructure/telemetry"
+
+	"github.com/rs/zerolog"
+)
+
+// TransactionStatsRecorder keeps the transaction stats counters up to date as
+// transactions are created and change status.
+type TransactionStatsRecorder struct {
+	statsRepo repository.TransactionStatsRepository
+	logger    zerolog.Logger
+}
+
+// NewTransactionStatsRecorder creates a new TransactionStatsRecorder.
+func NewTransactionStatsRecorder(statsRepo repository.TransactionStatsRepository, logger zerolog.Logger) *TransactionStatsRecorder {
+	return &TransactionStatsRecorder{statsRepo: statsRepo, logger: logger}
+}
+
+// RecordChange applies the change of a transaction from before to after to the
+// counters; before is nil for a created transaction. A failure is logged and
+// counted but not returned: the change itself is already saved, and the counters
+// are corrected by rebuilding them.
+func (r *TransactionStatsRecorder) RecordChange(ctx context.Context, before, after *entity.TransactionEntity) {
+	delta := entity.CountTransaction(after)
+	if before != nil {
+		delta.Sub(entity.CountTransaction(before))
+	}
+	if delta.IsZero() {
+		return
+	}
+
+	if err := r.statsRepo.Add(ctx, after, delta); err != nil {
+		telemetry.TransactionStatsUpdateFailures.Inc()
+		r.logger.Error().Err(err).
+			Str("transaction_id", after.ID).
+			Str("status", string(after.Status)).
+			Msg("failed to update transaction stats")
+	}
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// recordingStatsRepo is a hand-written mock implementing TransactionStatsRepository
+// that captures the deltas added and the hours replaced.
+type recordingStatsRepo struct {
+	added    []entity.TransactionCounters
+	addErr   error
+	replaced []entity.HourlyTransactionCounters
+}
+
+func (m *recordingStatsRepo) Add(_ context.Context, _ *entity.TransactionEntity, delta entity.TransactionCounters) error {
+	if m.addErr != nil {
+		return m.addErr
+	}
+	m.added = append(m.added, delta)
+	return nil
+}
+
+func (m *recordingStatsRepo) FindHours(_ context.Context, _, _ time.Time) ([]entity.HourlyTransactionCounters, error) {
+	return nil, nil
+}
+
+func (m *recordingStatsRepo) Total(_ context.Context) (entity.TransactionCounters, error) {
+	return entity.NewTransactionCounters(), nil
+}
+
+func (m *recordingStatsRepo) Replace(_ context.Context, hours []entity.HourlyTransactionCounters) error {
+	m.replaced = hours
+	return nil
+}
+
+func TestTransactionStatsRecorder_RecordChange(t *testing.T) {
+	createdAt := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	pending := &entity.TransactionEntity{ID: "tx-1", PaymentMethod: entity.CARD, Status: entity.PENDING, CreatedAt: createdAt}
+
+	t.Run("counts a created transaction", func(t *testing.T) {
+		repo := &recordingStatsRepo{}
+		NewTransactionStatsRecorder(repo, zerolog.Nop()).RecordChange(context.Background(), nil, pending)
+
+		if len(repo.added) != 1 {
+			t.Fatalf("expected one delta, got %d", len(repo.added))
+		}
+		if delta := repo.added[0]; delta.Created != 1 || delta.Statuses[entity.PENDING] != 1 || delta.PaymentMethods[entity.CARD] != 1 {
+			t.Errorf("unexpected delta %+v", delta)
+		}
+	})
+
+	t.Run("moves a finalized transaction between statuses", func(t *testing.T) {
+		repo := &recordingStatsRepo{}
+		finalizedAt := createdAt.Add(3 * time.Second)
+		approved := *pending
+		approved.Status = entity.APPROVED
+		approved.FinalizedAt = &finalizedAt
+		approved.DecisionPath = entity.DecisionPathFraudCheck
+
+		NewTransactionStatsRecorder(repo, zerolog.Nop()).RecordChange(context.Background(), pending, &approved)
+
+		if len(repo.added) != 1 {
+			t.Fatalf("expected one delta, got %d", len(repo.added))
+		}
+		delta := repo.added[0]
+		if delta.Created != 0 || delta.Statuses[entity.PENDING] != -1 || delta.Statuses[entity.APPROVED] != 1 || delta.PaymentMethods[entity.CARD] != 0 {
+			t.Errorf("unexpected counts %+v", delta)
+		}
+		if delta.Finalized != 1 || delta.LatencySumMs != 3000 || delta.DecisionPaths[entity.DecisionPathFraudCheck] != 1 {
+			t.Errorf("unexpected finalization %+v", delta)
+		}
+		if sketch := delta.LatencyByStatus[entity.APPROVED]; sketch[entity.LatencySketchIndex(3000)] != 1 || sketch.Count() != 1 {
+			t.Errorf("unexpected latency sketch %v", delta.LatencyByStatus)
+		}
+	})
+
+	t.Run("skips a change that leaves the counters unchanged", func(t *testing.T) {
+		repo := &recordingStatsRepo{}
+		updated := *pending
+		updated.UpdatedAt = createdAt.Add(time.Minute)
+
+		NewTransactionStatsRecorder(repo, zerolog.Nop()).RecordChange(context.Background(), pending, &updated)
+
+		if len(repo.added) != 0 {
+			t.Errorf("expected no delta, got %+v", repo.added)
+		}
+	})
+
+	t.Run("does not fail on a repository error", func(t *testing.T) {
+		repo := &recordingStatsRepo{addErr: errors.New("throttled")}
+		NewTransactionStatsRecorder(repo, zerolog.Nop()).RecordChange(context.Background(), nil, pending)
+	})
+}
@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+	"ms-transaction-evaluator/internal/infrastructure/telemetry"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultTransactionStreamHistory is how many recent events are kept to resume
+	// a stream from its Last-Event-ID.
+	DefaultTransactionStreamHistory = 1000
+	// DefaultTransactionStreamBuffer is how many events may wait for a subscriber
+	// before it is dropped as too slow.
+	DefaultTransactionStreamBuffer = 256
+)
+
+// TransactionStreamFilter selects the events of a subscription. An empty list
+// matches every value.
+type TransactionStreamFilter struct {
+	Statuses       []entity.TransactionStatus
+	PaymentMethods []entity.PaymentMethod
+}
+
+// ParseTransactionStreamFilter parses comma-separated lists of statuses and
+// payment methods.
+func ParseTransactionStreamFilter(statuses, paymentMethods string) (TransactionStreamFilter, error) {
+	var filter TransactionStreamFilter
+
+	for _, s := range splitList(statuses) {
+		status := entity.TransactionStatus(s)
+		switch status {
+		case entity.PENDING, entity.APPROVED, entity.DECLINED, entity.EXPIRED, entity.NEEDS_REVIEW:
+			filter.Statuses = append(filter.Statuses, status)
+		default:
+			return TransactionStreamFilter{}, fmt.Errorf("%w: unknown status %q", ErrInvalidStreamFilter, s)
+		}
+	}
+
+	for _, m := range splitList(paymentMethods) {
+		method := entity.PaymentMethod(m)
+		switch method {
+		case entity.CARD, entity.BANK_TRANSFER, entity.CRYPTO:
+			filter.PaymentMethods = append(filter.PaymentMethods, method)
+		default:
+			return TransactionStreamFilter{}, fmt.Errorf("%w: unknown payment method %q", ErrInvalidStreamFilter, m)
+		}
+	}
+
+	return filter, nil
+}
+
+// Matches reports whether the transaction passes the filter.
+func (f TransactionStreamFilter) Matches(transaction *entity.TransactionEntity) bool {
+	return containsOrEmpty(f.Statuses, transaction.Status) && containsOrEmpty(f.PaymentMethods, transaction.PaymentMethod)
+}
+
+// TransactionSubscription receives the events matching its filter. Events is
+// closed when the subscription is dropped for falling behind; the client should
+// then reconnect with the ID of the last event it received.
+type TransactionSubscription struct {
+	// Replay holds the missed events since the requested Last-Event-ID.
+	Replay []entity.TransactionEvent
+	// Resumed is false when a Last-Event-ID was given but the events since could
+	// not be replayed, because it is unknown or older than the kept history.
+	Resumed bool
+	Events  <-chan entity.TransactionEvent
+
+	events chan entity.TransactionEvent
+	filter TransactionStreamFilter
+	stream *TransactionStream
+}
+
+// Close ends the subscription.
+func (s *TransactionSubscription) Close() {
+	s.stream.remove(s)
+}
+
+// TransactionStream is an in-process broker of transaction events. It is fed by
+// SaveTransactionUseCase and the Decision.Calculated consumer, so a subscriber
+// only sees the transactions created and decided by its own instance. Publishing
+// never blocks: a subscriber whose buffer is full is dropped.
+type TransactionStream struct {
+	transactionRepo repository.TransactionRepository
+	logger          zerolog.Logger
+	historySize     int
+	bufferSize      int
+	now             func() time.Time
+
+	mu          sync.Mutex
+	epoch       string
+	seq         uint64
+	history     []entity.TransactionEvent
+	subscribers map[*TransactionSubscription]struct{}
+}
+
+// NewTransactionStream creates a broker keeping historySize events for resumption
+// and buffering bufferSize events per subscriber. Non-positive sizes take their
+// defaults. transactionRepo is read to complete finalized transactions.
+func NewTransactionStream(
+	transactionRepo repository.TransactionRepository,
+	historySize int,
+	bufferSize int,
+	logger zerolog.Logger,
+) *TransactionStream {
+	if historySize <= 0 {
+		historySize = DefaultTransactionStreamHistory
+	}
+	if bufferSize <= 0 {
+		bufferSize = DefaultTransactionStreamBuffer
+	}
+
+	return &TransactionStream{
+		transactionRepo: transactionRepo,
+		logger:          logger,
+		historySize:     historySize,
+		bufferSize:      bufferSize,
+		now:             time.Now,
+		epoch:           strconv.FormatInt(time.Now().UnixMilli(), 36),
+		subscribers:     make(map[*TransactionSubscription]struct{}),
+	}
+}
+
+// PublishCreated emits a transaction.created event.
+func (s *TransactionStream) PublishCreated(transaction *entity.TransactionEntity) {
+	s.publish(entity.TransactionEventCreated, *transaction)
+}
+
+// PublishFinalized emits a transaction.finalized event for a transaction given
+// status. A PENDING status, left by a FRAUD_CHECK decision, is ignored. The
+// transaction is read to complete the event; if it cannot be read, or the read is
+// stale, the event carries the given status.
+func (s *TransactionStream) PublishFinalized(ctx context.Context, transactionID string, status entity.TransactionStatus) {
+	if status == entity.PENDING {
+		return
+	}
+
+	transaction := entity.TransactionEntity{ID: transactionID}
+	current, err := s.transactionRepo.FindByID(ctx, transactionID)
+	if err != nil || current == nil {
+		s.logger.Warn().Err(err).
+			Str("transaction_id", transactionID).
+			Msg("failed to read finalized transaction for the stream")
+	} else {
+		transaction = *current
+	}
+	transaction.Status = status
+
+	s.publish(entity.TransactionEventFinalized, transaction)
+}
+
+// Subscribe registers a subscriber. With a lastEventID, the kept events after it
+// that match the filter are returned in Replay.
+func (s *TransactionStream) Subscribe(filter TransactionStreamFilter, lastEventID string) *TransactionSubscription {
+	events := make(chan entity.TransactionEvent, s.bufferSize)
+	sub := &TransactionSubscription{
+		Resumed: true,
+		Events:  events,
+		events:  events,
+		filter:  filter,
+		stream:  s,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if lastEventID != "" {
+		replay, ok := s.since(lastEventID)
+		sub.Resumed = ok
+		for _, event := range replay {
+			if filter.Matches(&event.Transaction) {
+				sub.Replay = append(sub.Replay, event)
+			}
+		}
+	}
+
+	s.subscribers[sub] = struct{}{}
+	telemetry.TransactionStreamSubscribers.Inc()
+
+	return sub
+}
+
+// Len returns the number of subscribers.
+func (s *TransactionStream) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscribers)
+}
+
+func (s *TransactionStream) publish(eventType entity.TransactionEventType, transaction entity.TransactionEntity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	event := entity.TransactionEvent{
+		ID:          s.epoch + "-" + strconv.FormatUint(s.seq, 10),
+		Type:        eventType,
+		Transaction: transaction,
+		OccurredAt:  s.now().UTC(),
+	}
+
+	s.history = append(s.history, event)
+	if len(s.history) > s.historySize {
+		s.history = s.history[len(s.history)-s.historySize:]
+	}
+	telemetry.TransactionStreamEvents.WithLabelValues(string(eventType)).Inc()
+
+	for sub := range s.subscribers {
+		if !sub.filter.Matches(&event.Transaction) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			s.logger.Warn().Int("buffer", s.bufferSize).Msg("dropping slow transaction stream subscriber")
+			telemetry.TransactionStreamDroppedSubscribers.Inc()
+			s.drop(sub)
+		}
+	}
+}
+
+// since returns the kept events after lastEventID, and false if they cannot all
+// be returned. Callers must hold mu.
+func (s *TransactionStream) since(lastEventID string) ([]entity.TransactionEvent, bool) {
+	epoch, rawSeq, ok := strings.Cut(lastEventID, "-")
+	if !ok || epoch != s.epoch {
+		return nil, false
+	}
+	seq, err := strconv.ParseUint(rawSeq, 10, 64)
+	if err != nil || seq > s.seq {
+		return nil, false
+	}
+
+	missed := int(s.seq - seq)
+	if missed > len(s.history) {
+		return nil, false
+	}
+	return append([]entity.TransactionEvent(nil), s.history[len(s.history)-missed:]...), true
+}
+
+func (s *TransactionStream) remove(sub *TransactionSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.drop(sub)
+}
+
+// drop unregisters the subscriber and closes its channel. Callers must hold mu.
+func (s *TransactionStream) drop(sub *TransactionSubscription) {
+	if _, ok := s.subscribers[sub]; !ok {
+		return
+	}
+	delete(s.subscribers, sub)
+	close(sub.events)
+	telemetry.TransactionStreamSubscribers.Dec()
+}
+
+func splitList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, strings.ToUpper(value))
+		}
+	}
+	return values
+}
+
+func containsOrEmpty[T comparable](values []T, value T) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
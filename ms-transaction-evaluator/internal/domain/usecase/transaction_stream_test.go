@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// streamMockRepo is a hand-written mock implementing TransactionRepository for
+// TransactionStream tests. FindByID returns the stored transaction or findErr.
+type streamMockRepo struct {
+	transactions map[string]entity.TransactionEntity
+	findErr      error
+}
+
+func (m *streamMockRepo) Save(_ context.Context, _ *entity.TransactionEntity) error {
+	return nil
+}
+
+func (m *streamMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time, _ entity.DecisionPath) (*entity.TransactionEntity, error) {
+	return &entity.TransactionEntity{Status: entity.PENDING}, nil
+}
+
+func (m *streamMockRepo) FindByID(_ context.Context, id string) (*entity.TransactionEntity, error) {
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	transaction, ok := m.transactions[id]
+	if !ok {
+		return nil, nil
+	}
+	return &transaction, nil
+}
+
+func (m *streamMockRepo) FindAllPaginated(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
+	return nil, "", nil
+}
+
+func (m *streamMockRepo) FindAll(_ context.Context) ([]entity.TransactionEntity, error) {
+	return nil, nil
+}
+
+func created(id string, method entity.PaymentMethod) *entity.TransactionEntity {
+	return &entity.TransactionEntity{ID: id, PaymentMethod: method, Status: entity.PENDING}
+}
+
+func receive(t *testing.T, sub *TransactionSubscription) entity.TransactionEvent {
+	t.Helper()
+	select {
+	case event := <-sub.Events:
+		return event
+	default:
+		t.Fatal("expected an event")
+		return entity.TransactionEvent{}
+	}
+}
+
+func TestParseTransactionStreamFilter(t *testing.T) {
+	filter, err := ParseTransactionStreamFilter("approved, DECLINED", "card")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(filter.Statuses) != 2 || filter.Statuses[0] != entity.APPROVED || filter.Statuses[1] != entity.DECLINED {
+		t.Errorf("unexpected statuses %v", filter.Statuses)
+	}
+	if len(filter.PaymentMethods) != 1 || filter.PaymentMethods[0] != entity.CARD {
+		t.Errorf("unexpected payment methods %v", filter.PaymentMethods)
+	}
+
+	if !filter.Matches(&entity.TransactionEntity{Status: entity.APPROVED, PaymentMethod: entity.CARD}) {
+		t.Error("expected an approved card transaction to match")
+	}
+	if filter.Matches(&entity.TransactionEntity{Status: entity.PENDING, PaymentMethod: entity.CARD}) {
+		t.Error("expected a pending transaction not to match")
+	}
+	if filter.Matches(&entity.TransactionEntity{Status: entity.APPROVED, PaymentMethod: entity.CRYPTO}) {
+		t.Error("expected a crypto transaction not to match")
+	}
+
+	empty, err := ParseTransactionStreamFilter("", "")
+	if err != nil || !empty.Matches(&entity.TransactionEntity{Status: entity.EXPIRED, PaymentMethod: entity.BANK_TRANSFER}) {
+		t.Errorf("expected an empty filter to match everything, got %v", err)
+	}
+
+	for _, tt := range [][2]string{{"SETTLED", ""}, {"", "CASH"}} {
+		if _, err := ParseTransactionStreamFilter(tt[0], tt[1]); !errors.Is(err, ErrInvalidStreamFilter) {
+			t.Errorf("filter %v: expected ErrInvalidStreamFilter, got %v", tt, err)
+		}
+	}
+}
+
+func TestTransactionStream(t *testing.T) {
+	t.Run("delivers the matching events", func(t *testing.T) {
+		stream := NewTransactionStream(&streamMockRepo{}, 0, 0, zerolog.Nop())
+		cards := stream.Subscribe(TransactionStreamFilter{PaymentMethods: []entity.PaymentMethod{entity.CARD}}, "")
+		defer cards.Close()
+
+		stream.PublishCreated(created("tx-1", entity.CRYPTO))
+		stream.PublishCreated(created("tx-2", entity.CARD))
+
+		event := receive(t, cards)
+		if event.Type != entity.TransactionEventCreated || event.Transaction.ID != "tx-2" {
+			t.Errorf("expected tx-2 created, got %s %s", event.Type, event.Transaction.ID)
+		}
+		select {
+		case event := <-cards.Events:
+			t.Errorf("expected no more events, got %s", event.Transaction.ID)
+		default:
+		}
+	})
+
+	t.Run("publishes the read transaction with the decided status", func(t *testing.T) {
+		repo := &streamMockRepo{transactions: map[string]entity.TransactionEntity{
+			"tx-1": {ID: "tx-1", PaymentMethod: entity.CARD, Status: entity.PENDING},
+		}}
+		stream := NewTransactionStream(repo, 0, 0, zerolog.Nop())
+		sub := stream.Subscribe(TransactionStreamFilter{}, "")
+		defer sub.Close()
+
+		stream.PublishFinalized(context.Background(), "tx-1", entity.APPROVED)
+
+		event := receive(t, sub)
+		if event.Type != entity.TransactionEventFinalized {
+			t.Errorf("expected a finalized event, got %s", event.Type)
+		}
+		if event.Transaction.Status != entity.APPROVED || event.Transaction.PaymentMethod != entity.CARD {
+			t.Errorf("expected an approved card transaction, got %+v", event.Transaction)
+		}
+	})
+
+	t.Run("publishes the decided status when the transaction cannot be read", func(t *testing.T) {
+		stream := NewTransactionStream(&streamMockRepo{findErr: errors.New("throttled")}, 0, 0, zerolog.Nop())
+		sub := stream.Subscribe(TransactionStreamFilter{}, "")
+		defer sub.Close()
+
+		stream.PublishFinalized(context.Background(), "tx-1", entity.DECLINED)
+
+		event := receive(t, sub)
+		if event.Transaction.ID != "tx-1" || event.Transaction.Status != entity.DECLINED {
+			t.Errorf("expected tx-1 DECLINED, got %+v", event.Transaction)
+		}
+	})
+
+	t.Run("ignores the PENDING status left by FRAUD_CHECK", func(t *testing.T) {
+		stream := NewTransactionStream(&streamMockRepo{}, 0, 0, zerolog.Nop())
+		sub := stream.Subscribe(TransactionStreamFilter{}, "")
+		defer sub.Close()
+
+		stream.PublishFinalized(context.Background(), "tx-1", entity.PENDING)
+
+		select {
+		case event := <-sub.Events:
+			t.Errorf("expected no event, got %s", event.Type)
+		default:
+		}
+	})
+
+	t.Run("replays the matching events after the last event ID", func(t *testing.T) {
+		stream := NewTransactionStream(&streamMockRepo{}, 0, 0, zerolog.Nop())
+		first := stream.Subscribe(TransactionStreamFilter{}, "")
+		stream.PublishCreated(created("tx-1", entity.CARD))
+		lastEventID := receive(t, first).ID
+		first.Close()
+
+		stream.PublishCreated(created("tx-2", entity.CRYPTO))
+		stream.PublishCreated(created("tx-3", entity.CARD))
+
+		sub := stream.Subscribe(TransactionStreamFilter{PaymentMethods: []entity.PaymentMethod{entity.CARD}}, lastEventID)
+		defer sub.Close()
+
+		if !sub.Resumed {
+			t.Fatal("expected the subscription to resume")
+		}
+		if len(sub.Replay) != 1 || sub.Replay[0].Transaction.ID != "tx-3" {
+			t.Errorf("expected tx-3 to be replayed, got %+v", sub.Replay)
+		}
+	})
+
+	t.Run("does not resume from an unknown or expired event ID", func(t *testing.T) {
+		stream := NewTransactionStream(&streamMockRepo{}, 2, 0, zerolog.Nop())
+		first := stream.Subscribe(TransactionStreamFilter{}, "")
+		stream.PublishCreated(created("tx-1", entity.CARD))
+		expired := receive(t, first).ID
+		first.Close()
+
+		stream.PublishCreated(created("tx-2", entity.CARD))
+		stream.PublishCreated(created("tx-3", entity.CARD))
+		stream.PublishCreated(created("tx-4", entity.CARD))
+
+		for _, id := range []string{expired, "other-1", "garbage", stream.epoch + "-99"} {
+			sub := stream.Subscribe(TransactionStreamFilter{}, id)
+			if sub.Resumed || len(sub.Replay) != 0 {
+				t.Errorf("Last-Event-ID %q: expected no resumption, got %v %d", id, sub.Resumed, len(sub.Replay))
+			}
+			sub.Close()
+		}
+	})
+
+	t.Run("drops a slow subscriber without blocking", func(t *testing.T) {
+		stream := NewTransactionStream(&streamMockRepo{}, 0, 1, zerolog.Nop())
+		slow := stream.Subscribe(TransactionStreamFilter{}, "")
+		fast := stream.Subscribe(TransactionStreamFilter{}, "")
+		defer fast.Close()
+
+		stream.PublishCreated(created("tx-1", entity.CARD))
+		receive(t, fast)
+		stream.PublishCreated(created("tx-2", entity.CARD))
+
+		if stream.Len() != 1 {
+			t.Fatalf("expected the slow subscriber to be dropped, got %d subscribers", stream.Len())
+		}
+		if event := receive(t, fast); event.Transaction.ID != "tx-2" {
+			t.Errorf("expected tx-2, got %s", event.Transaction.ID)
+		}
+
+		<-slow.Events
+		if _, ok := <-slow.Events; ok {
+			t.Error("expected the slow subscriber's channel to be closed")
+		}
+		slow.Close()
+	})
+}
@@ -26,10 +26,10 @@ func (m *statusCaptureMockRepo) Save(_ context.Context, _ *entity.TransactionEnt
 	return nil
 }
 
-func (m *statusCaptureMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, finalizedAt *time.Time) error {
+func (m *statusCaptureMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, finalizedAt *time.Time, _ entity.DecisionPath) (*entity.TransactionEntity, error) {
 	m.updateStatusCalled = true
 	m.capturedFinalizedAt = finalizedAt
-	return nil
+	return &entity.TransactionEntity{Status: entity.PENDING}, nil
 }
 
 func (m *statusCaptureMockRepo) FindByID(_ context.Context, _ string) (*entity.TransactionEntity, error) {
@@ -38,7 +38,7 @@ func (m *statusCaptureMockRepo) FindByID(_ context.Context, _ string) (*entity.T
 	}, nil
 }
 
-func (m *statusCaptureMockRepo) FindAllPaginated(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
+func (m *statusCaptureMockRepo) FindAllPaginated(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
 	return nil, "", nil
 }
 
@@ -51,7 +51,7 @@ func TestProperty_FinalizedAtSetIffTerminalStatus(t *testing.T) {
 
 	rapid.Check(t, func(t *rapid.T) {
 		mock := &statusCaptureMockRepo{}
-		uc := NewUpdateTransactionStatusUseCase(mock)
+		uc := NewUpdateTransactionStatusUseCase(mock, nil)
 
 		txnID := rapid.StringMatching(`^txn_[a-z0-9]{8,16}$`).Draw(t, "transactionID")
 		statusIdx := rapid.IntRange(0, len(decisionStatuses)-1).Draw(t, "statusIdx")
@@ -96,8 +96,8 @@ func (m *histogramMockRepo) Save(_ context.Context, _ *entity.TransactionEntity)
 	return nil
 }
 
-func (m *histogramMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) error {
-	return nil
+func (m *histogramMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time, _ entity.DecisionPath) (*entity.TransactionEntity, error) {
+	return &entity.TransactionEntity{Status: entity.PENDING}, nil
 }
 
 func (m *histogramMockRepo) FindByID(_ context.Context, _ string) (*entity.TransactionEntity, error) {
@@ -106,7 +106,7 @@ func (m *histogramMockRepo) FindByID(_ context.Context, _ string) (*entity.Trans
 	}, nil
 }
 
-func (m *histogramMockRepo) FindAllPaginated(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
+func (m *histogramMockRepo) FindAllPaginated(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
 	return nil, "", nil
 }
 
@@ -144,7 +144,7 @@ func TestProperty_HistogramObservedWithCorrectLabelOnFinalization(t *testing.T)
 		// Use a created_at slightly in the past so latency is positive
 		createdAt := time.Now().UTC().Add(-2 * time.Second)
 		mock := &histogramMockRepo{createdAt: createdAt}
-		uc := NewUpdateTransactionStatusUseCase(mock)
+		uc := NewUpdateTransactionStatusUseCase(mock, nil)
 
 		statusIdx := rapid.IntRange(0, len(terminalStatuses)-1).Draw(t, "statusIdx")
 		status := terminalStatuses[statusIdx]
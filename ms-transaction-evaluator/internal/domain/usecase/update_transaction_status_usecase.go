@@ -27,16 +27,27 @@ var statusMap = map[string]entity.TransactionStatus{
 // UpdateTransactionStatusUseCase updates a transaction's status based on a decision result.
 type UpdateTransactionStatusUseCase struct {
 	transactionRepo repository.TransactionRepository
+	finalizer       *TransactionFinalizer
 }
 
-// NewUpdateTransactionStatusUseCase creates a new use case.
-func NewUpdateTransactionStatusUseCase(repo repository.TransactionRepository) *UpdateTransactionStatusUseCase {
-	return &UpdateTransactionStatusUseCase{transactionRepo: repo}
+// NewUpdateTransactionStatusUseCase creates a new use case. finalizer may be nil,
+// in which case nothing is notified of finalized transactions.
+func NewUpdateTransactionStatusUseCase(
+	repo repository.TransactionRepository,
+	finalizer *TransactionFinalizer,
+) *UpdateTransactionStatusUseCase {
+	return &UpdateTransactionStatusUseCase{transactionRepo: repo, finalizer: finalizer}
 }
 
 // Execute maps the decision status to a transaction status and updates the record.
 // For terminal statuses (APPROVED, DECLINED), it records the finalized_at timestamp
-// and observes the finalization latency in the Prometheus histogram.
+// and the decision path of the message, when it is a known one, observes the
+// finalization latency in the Prometheus histogram, then hands the transaction to
+// the finalizer, which updates the stats counters, enqueues the webhook
+// deliveries, wakes the waiting requests and publishes it on the stream. A
+// decision for a transaction that is no longer PENDING is ignored. A failure to
+// enqueue the deliveries is returned, so that the decision can be retried; the
+// deliveries already enqueued are not duplicated.
 func (uc *UpdateTransactionStatusUseCase) Execute(ctx context.Context, msg *entity.DecisionCalculatedMessage) error {
 	if msg == nil {
 		return ErrDecisionMessageNil
@@ -48,34 +59,55 @@ func (uc *UpdateTransactionStatusUseCase) Execute(ctx context.Context, msg *enti
 	}
 
 	var finalizedAt *time.Time
+	var decisionPath entity.DecisionPath
 
 	if txnStatus == entity.APPROVED || txnStatus == entity.DECLINED {
 		now := time.Now().UTC()
 		finalizedAt = &now
+
+		switch path := entity.DecisionPath(msg.DecisionPath); path {
+		case entity.DecisionPathDirect, entity.DecisionPathFraudCheck:
+			decisionPath = path
+		}
 	}
 
-	if err := uc.transactionRepo.UpdateStatus(ctx, msg.TransactionID, txnStatus, finalizedAt); err != nil {
+	// The counters are updated by the difference the decision makes, from the
+	// transaction as the update found it, so racing decisions are counted once
+	before, err := uc.transactionRepo.UpdateStatus(ctx, msg.TransactionID, txnStatus, finalizedAt, decisionPath)
+	if err != nil {
 		return fmt.Errorf("%w: %w", ErrStatusUpdateFailed, err)
 	}
+	// A duplicate decision, or one arriving after the stuck transaction sweeper
+	// resolved the transaction, changes nothing and is not announced again
+	if before == nil {
+		log.Printf("transaction %s is no longer pending, %s decision ignored", msg.TransactionID, msg.Status)
+		return nil
+	}
 
-	if finalizedAt != nil {
-		uc.observeLatency(ctx, msg.TransactionID, *finalizedAt, string(txnStatus))
+	if finalizedAt == nil {
+		return nil
 	}
 
-	return nil
-}
+	txn, err := uc.transactionRepo.FindByID(ctx, msg.TransactionID)
+	if err != nil || txn == nil {
+		log.Printf("failed to fetch finalized transaction %s: %v", msg.TransactionID, err)
+		if uc.finalizer != nil && uc.finalizer.webhooks != nil {
+			return fmt.Errorf("%w: transaction %s could not be read: %v", ErrWebhookEnqueueFailed, msg.TransactionID, err)
+		}
+		txn = nil
+	} else {
+		uc.observeLatency(txn, *finalizedAt, string(txnStatus))
+	}
 
-// observeLatency fetches the transaction to get created_at, computes latency,
-// and observes the histogram. Failures are logged but do not fail the status update.
-func (uc *UpdateTransactionStatusUseCase) observeLatency(
-	ctx context.Context, transactionID string, finalizedAt time.Time, status string,
-) {
-	txn, err := uc.transactionRepo.FindByID(ctx, transactionID)
-	if err != nil {
-		log.Printf("failed to fetch transaction for latency observation: %v", err)
-		return
+	if uc.finalizer == nil {
+		return nil
 	}
+	return uc.finalizer.Finalize(ctx, msg.TransactionID, txnStatus, before, txn)
+}
 
+// observeLatency computes the latency from the transaction's created_at and
+// observes the histogram. Failures are logged but do not fail the status update.
+func (uc *UpdateTransactionStatusUseCase) observeLatency(txn *entity.TransactionEntity, finalizedAt time.Time, status string) {
 	latencySeconds := finalizedAt.Sub(txn.CreatedAt).Seconds()
 
 	func() {
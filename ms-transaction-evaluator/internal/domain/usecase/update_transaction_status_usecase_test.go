@@ -10,27 +10,46 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
+	"github.com/rs/zerolog"
 )
 
 // updateStatusMockRepo is a hand-written mock implementing TransactionRepository
-// that captures the finalizedAt parameter and supports configurable FindByID behavior.
+// that captures the finalizedAt and decisionPath parameters and supports configurable
+// FindByID behavior.
 type updateStatusMockRepo struct {
-	capturedFinalizedAt *time.Time
-	capturedStatus      entity.TransactionStatus
-	updateStatusCalled  bool
-	updateStatusErr     error
-	findByIDFunc        func(ctx context.Context, id string) (*entity.TransactionEntity, error)
+	capturedFinalizedAt  *time.Time
+	capturedStatus       entity.TransactionStatus
+	capturedDecisionPath entity.DecisionPath
+	updateStatusCalled   bool
+	updateStatusErr      error
+	// notPending makes UpdateStatus report the transaction as no longer PENDING.
+	notPending   bool
+	findByIDFunc func(ctx context.Context, id string) (*entity.TransactionEntity, error)
 }
 
 func (m *updateStatusMockRepo) Save(_ context.Context, _ *entity.TransactionEntity) error {
 	return nil
 }
 
-func (m *updateStatusMockRepo) UpdateStatus(_ context.Context, _ string, status entity.TransactionStatus, finalizedAt *time.Time) error {
+func (m *updateStatusMockRepo) UpdateStatus(ctx context.Context, id string, status entity.TransactionStatus, finalizedAt *time.Time, decisionPath entity.DecisionPath) (*entity.TransactionEntity, error) {
+	// The transaction as it was before is what FindByID returns until the update
+	before := &entity.TransactionEntity{ID: id, Status: entity.PENDING}
+	if m.findByIDFunc != nil {
+		if txn, err := m.findByIDFunc(ctx, id); err == nil && txn != nil {
+			before = txn
+		}
+	}
 	m.updateStatusCalled = true
 	m.capturedStatus = status
 	m.capturedFinalizedAt = finalizedAt
-	return m.updateStatusErr
+	m.capturedDecisionPath = decisionPath
+	if m.updateStatusErr != nil {
+		return nil, m.updateStatusErr
+	}
+	if m.notPending {
+		return nil, nil
+	}
+	return before, nil
 }
 
 func (m *updateStatusMockRepo) FindByID(ctx context.Context, id string) (*entity.TransactionEntity, error) {
@@ -40,7 +59,7 @@ func (m *updateStatusMockRepo) FindByID(ctx context.Context, id string) (*entity
 	return nil, nil
 }
 
-func (m *updateStatusMockRepo) FindAllPaginated(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
+func (m *updateStatusMockRepo) FindAllPaginated(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
 	return nil, "", nil
 }
 
@@ -111,7 +130,7 @@ func TestUpdateTransactionStatusUseCase_Execute_Finalization(t *testing.T) {
 					}, nil
 				},
 			}
-			uc := NewUpdateTransactionStatusUseCase(mock)
+			uc := NewUpdateTransactionStatusUseCase(mock, nil)
 
 			msg := &entity.DecisionCalculatedMessage{
 				TransactionID: "txn_test_001",
@@ -165,7 +184,7 @@ func TestUpdateTransactionStatusUseCase_Execute_Finalization(t *testing.T) {
 
 func TestUpdateTransactionStatusUseCase_Execute_NilMessage(t *testing.T) {
 	mock := &updateStatusMockRepo{}
-	uc := NewUpdateTransactionStatusUseCase(mock)
+	uc := NewUpdateTransactionStatusUseCase(mock, nil)
 
 	err := uc.Execute(context.Background(), nil)
 	if err == nil {
@@ -178,7 +197,7 @@ func TestUpdateTransactionStatusUseCase_Execute_NilMessage(t *testing.T) {
 
 func TestUpdateTransactionStatusUseCase_Execute_InvalidStatus(t *testing.T) {
 	mock := &updateStatusMockRepo{}
-	uc := NewUpdateTransactionStatusUseCase(mock)
+	uc := NewUpdateTransactionStatusUseCase(mock, nil)
 
 	msg := &entity.DecisionCalculatedMessage{
 		TransactionID: "txn_test_002",
@@ -198,7 +217,7 @@ func TestUpdateTransactionStatusUseCase_Execute_UpdateStatusError(t *testing.T)
 	mock := &updateStatusMockRepo{
 		updateStatusErr: errors.New("dynamodb connection failed"),
 	}
-	uc := NewUpdateTransactionStatusUseCase(mock)
+	uc := NewUpdateTransactionStatusUseCase(mock, nil)
 
 	msg := &entity.DecisionCalculatedMessage{
 		TransactionID: "txn_test_003",
@@ -213,3 +232,164 @@ func TestUpdateTransactionStatusUseCase_Execute_UpdateStatusError(t *testing.T)
 		t.Errorf("expected ErrStatusUpdateFailed, got: %v", err)
 	}
 }
+
+func TestUpdateTransactionStatusUseCase_Execute_Stats(t *testing.T) {
+	createdAt := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	mock := &updateStatusMockRepo{}
+	mock.findByIDFunc = func(_ context.Context, id string) (*entity.TransactionEntity, error) {
+		txn := &entity.TransactionEntity{ID: id, PaymentMethod: entity.CARD, Status: entity.PENDING, CreatedAt: createdAt}
+		if mock.updateStatusCalled {
+			txn.Status = mock.capturedStatus
+			txn.FinalizedAt = mock.capturedFinalizedAt
+			txn.DecisionPath = mock.capturedDecisionPath
+		}
+		return txn, nil
+	}
+	stats := &recordingStatsRepo{}
+	uc := NewUpdateTransactionStatusUseCase(mock, NewTransactionFinalizer(nil, NewTransactionStatsRecorder(stats, zerolog.Nop()), nil, nil))
+
+	if err := uc.Execute(context.Background(), &entity.DecisionCalculatedMessage{TransactionID: "txn_stats", Status: "FRAUD_CHECK"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stats.added) != 0 {
+		t.Fatalf("expected FRAUD_CHECK not to change the stats, got %+v", stats.added)
+	}
+
+	if err := uc.Execute(context.Background(), &entity.DecisionCalculatedMessage{TransactionID: "txn_stats", Status: "DECLINED", DecisionPath: "FRAUD_CHECK"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stats.added) != 1 {
+		t.Fatalf("expected one delta, got %d", len(stats.added))
+	}
+	if delta := stats.added[0]; delta.Statuses[entity.PENDING] != -1 || delta.Statuses[entity.DECLINED] != 1 || delta.Finalized != 1 || delta.Created != 0 {
+		t.Errorf("unexpected delta %+v", delta)
+	}
+	if delta := stats.added[0]; delta.DecisionPaths[entity.DecisionPathFraudCheck] != 1 {
+		t.Errorf("expected the fraud-check decision to be counted, got %v", delta.DecisionPaths)
+	}
+}
+
+func TestUpdateTransactionStatusUseCase_Execute_DecisionPath(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       string
+		decisionPath string
+		want         entity.DecisionPath
+	}{
+		{name: "records a direct decision", status: "APPROVED", decisionPath: "DIRECT", want: entity.DecisionPathDirect},
+		{name: "records a fraud-check decision", status: "DECLINED", decisionPath: "FRAUD_CHECK", want: entity.DecisionPathFraudCheck},
+		{name: "ignores a missing decision path", status: "APPROVED", decisionPath: "", want: ""},
+		{name: "ignores an unknown decision path", status: "APPROVED", decisionPath: "MANUAL", want: ""},
+		{name: "ignores the decision path of a non-final status", status: "FRAUD_CHECK", decisionPath: "DIRECT", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &updateStatusMockRepo{}
+			uc := NewUpdateTransactionStatusUseCase(mock, nil)
+
+			err := uc.Execute(context.Background(), &entity.DecisionCalculatedMessage{TransactionID: "txn_path", Status: tt.status, DecisionPath: tt.decisionPath})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if mock.capturedDecisionPath != tt.want {
+				t.Errorf("expected decision path %q, got %q", tt.want, mock.capturedDecisionPath)
+			}
+		})
+	}
+}
+
+// pendingTransactionStore is an in-memory store of transactions shared by the
+// decision consumer and the stuck transaction sweeper. Both only change a
+// transaction that is still PENDING, like the DynamoDB repository.
+type pendingTransactionStore struct {
+	updateStatusMockRepo
+	transactions map[string]*entity.TransactionEntity
+}
+
+func (s *pendingTransactionStore) UpdateStatus(_ context.Context, id string, status entity.TransactionStatus, finalizedAt *time.Time, decisionPath entity.DecisionPath) (*entity.TransactionEntity, error) {
+	txn := s.transactions[id]
+	if txn.Status != entity.PENDING {
+		return nil, nil
+	}
+	before := *txn
+	txn.Status, txn.FinalizedAt, txn.DecisionPath = status, finalizedAt, decisionPath
+	return &before, nil
+}
+
+func (s *pendingTransactionStore) FindByID(_ context.Context, id string) (*entity.TransactionEntity, error) {
+	txn := *s.transactions[id]
+	return &txn, nil
+}
+
+func (s *pendingTransactionStore) FindStuck(_ context.Context, _ time.Time, _ int) ([]entity.StuckTransaction, error) {
+	var stuck []entity.StuckTransaction
+	for _, txn := range s.transactions {
+		if txn.Status == entity.PENDING {
+			stuck = append(stuck, entity.StuckTransaction{Transaction: *txn, RepublishAttempts: 2})
+		}
+	}
+	return stuck, nil
+}
+
+func (s *pendingTransactionStore) RecordRepublish(_ context.Context, _ string, _ int, _ time.Time) error {
+	return nil
+}
+
+func (s *pendingTransactionStore) Resolve(ctx context.Context, id string, status entity.TransactionStatus, finalizedAt *time.Time) (bool, error) {
+	before, err := s.UpdateStatus(ctx, id, status, finalizedAt, "")
+	return before != nil, err
+}
+
+func TestUpdateTransactionStatusUseCase_Execute_AfterSweeperResolved(t *testing.T) {
+	store := &pendingTransactionStore{transactions: map[string]*entity.TransactionEntity{
+		"tx-late": {ID: "tx-late", PaymentMethod: entity.CARD, Status: entity.PENDING, CreatedAt: sweepNow.Add(-time.Hour)},
+	}}
+	endpoints := &mockWebhookEndpointRepository{endpoints: []entity.WebhookEndpoint{{ID: "global", URL: "https://example.com/all"}}}
+	deliveries := newMockWebhookDeliveryRepository()
+	stats := &recordingStatsRepo{}
+	finalizer := NewTransactionFinalizer(newTestEnqueueUseCase(endpoints, deliveries), NewTransactionStatsRecorder(stats, zerolog.Nop()), nil, nil)
+
+	policy := StuckTransactionPolicy{SLA: 10 * time.Minute, MaxRepublishes: 2, Fallback: entity.EXPIRED}
+	sweeper := NewSweepStuckTransactionsUseCase(store, &mockTransactionRepublisher{}, finalizer, policy, zerolog.Nop())
+	sweeper.now = func() time.Time { return sweepNow }
+	if _, resolved, err := sweeper.Execute(context.Background()); err != nil || resolved != 1 {
+		t.Fatalf("expected the sweeper to resolve the transaction, got %d, %v", resolved, err)
+	}
+
+	uc := NewUpdateTransactionStatusUseCase(store, finalizer)
+	if err := uc.Execute(context.Background(), &entity.DecisionCalculatedMessage{TransactionID: "tx-late", Status: "APPROVED", DecisionPath: "DIRECT"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if status := store.transactions["tx-late"].Status; status != entity.EXPIRED {
+		t.Errorf("expected the late decision to leave EXPIRED, got %s", status)
+	}
+	if len(deliveries.deliveries) != 1 {
+		t.Errorf("expected the transaction to be announced once, got %d deliveries", len(deliveries.deliveries))
+	}
+	if len(stats.added) != 1 {
+		t.Errorf("expected the stats to change once, got %+v", stats.added)
+	}
+}
+
+func TestUpdateTransactionStatusUseCase_Execute_RacingDecisions(t *testing.T) {
+	store := &pendingTransactionStore{transactions: map[string]*entity.TransactionEntity{
+		"tx-raced": {ID: "tx-raced", PaymentMethod: entity.CARD, Status: entity.PENDING, CreatedAt: time.Now().Add(-time.Minute)},
+	}}
+	stats := &recordingStatsRepo{}
+	uc := NewUpdateTransactionStatusUseCase(store, NewTransactionFinalizer(nil, NewTransactionStatsRecorder(stats, zerolog.Nop()), nil, nil))
+
+	for _, status := range []string{"APPROVED", "DECLINED"} {
+		if err := uc.Execute(context.Background(), &entity.DecisionCalculatedMessage{TransactionID: "tx-raced", Status: status, DecisionPath: "DIRECT"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(stats.added) != 1 {
+		t.Fatalf("expected the stats to change once, got %+v", stats.added)
+	}
+	if delta := stats.added[0]; delta.Statuses[entity.PENDING] != -1 || delta.Statuses[entity.APPROVED] != 1 || delta.Statuses[entity.DECLINED] != 0 {
+		t.Errorf("expected PENDING to move to APPROVED only, got %+v", delta.Statuses)
+	}
+}
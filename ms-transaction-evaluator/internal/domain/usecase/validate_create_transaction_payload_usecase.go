@@ -21,8 +21,15 @@ var (
 	ErrCustomerEmailInvalid  = errors.New("customer email is invalid")
 	ErrCustomerPhoneRequired = errors.New("customer phone is required")
 	ErrCustomerIPRequired    = errors.New("customer ip_address is required")
+	ErrExternalIDTooLong     = errors.New("external_id must be at most 255 characters")
+	ErrIdempotencyKeyTooLong = errors.New("idempotency key must be at most 255 characters")
+	ErrMerchantIDTooLong     = errors.New("merchant_id must be at most 255 characters")
 )
 
+// maxIdentifierLength bounds the Idempotency-Key header, external_id and
+// merchant_id.
+const maxIdentifierLength = 255
+
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
 
 type ValidateCreateTransactionPayloadUseCase struct{}
@@ -61,6 +68,16 @@ func (uc *ValidateCreateTransactionPayloadUseCase) Execute(req *entity.EvaluateT
 		return err
 	}
 
+	if len(req.ExternalID) > maxIdentifierLength {
+		return ErrExternalIDTooLong
+	}
+	if len(req.IdempotencyKey) > maxIdentifierLength {
+		return ErrIdempotencyKeyTooLong
+	}
+	if len(req.MerchantID) > maxIdentifierLength {
+		return ErrMerchantIDTooLong
+	}
+
 	return nil
 }
 
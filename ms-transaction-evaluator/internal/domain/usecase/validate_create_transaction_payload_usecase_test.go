@@ -3,6 +3,7 @@ package usecase
 import (
 	"errors"
 	"ms-transaction-evaluator/internal/domain/entity"
+	"strings"
 	"testing"
 )
 
@@ -303,6 +304,43 @@ func TestValidateCreateTransactionPayloadUseCase_Execute(t *testing.T) {
 			t.Errorf("Expected no error for valid positive amount, got: %v", err)
 		}
 	})
+
+	t.Run("should return error when external ID is too long", func(t *testing.T) {
+		req := createValidRequest()
+		req.ExternalID = strings.Repeat("a", 256)
+		err := uc.Execute(req)
+		if !errors.Is(err, ErrExternalIDTooLong) {
+			t.Errorf("Expected ErrExternalIDTooLong, got: %v", err)
+		}
+	})
+
+	t.Run("should return error when idempotency key is too long", func(t *testing.T) {
+		req := createValidRequest()
+		req.IdempotencyKey = strings.Repeat("a", 256)
+		err := uc.Execute(req)
+		if !errors.Is(err, ErrIdempotencyKeyTooLong) {
+			t.Errorf("Expected ErrIdempotencyKeyTooLong, got: %v", err)
+		}
+	})
+
+	t.Run("should return error when merchant ID is too long", func(t *testing.T) {
+		req := createValidRequest()
+		req.MerchantID = strings.Repeat("a", 256)
+		err := uc.Execute(req)
+		if !errors.Is(err, ErrMerchantIDTooLong) {
+			t.Errorf("Expected ErrMerchantIDTooLong, got: %v", err)
+		}
+	})
+
+	t.Run("should accept identifiers at the maximum length", func(t *testing.T) {
+		req := createValidRequest()
+		req.ExternalID = strings.Repeat("a", 255)
+		req.IdempotencyKey = strings.Repeat("b", 255)
+		req.MerchantID = strings.Repeat("c", 255)
+		if err := uc.Execute(req); err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+	})
 }
 
 func TestValidateCustomerInfo(t *testing.T) {
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
+	"ms-transaction-evaluator/internal/infrastructure/telemetry"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultDecisionWaitTimeout is how long a synchronous request waits for the
+	// decision when no timeout is given.
+	DefaultDecisionWaitTimeout = 5 * time.Second
+	// MaxDecisionWaitTimeout is the longest a synchronous request may wait.
+	MaxDecisionWaitTimeout = 30 * time.Second
+)
+
+// ParseWaitTimeout returns the wait timeout given in milliseconds by ms, or
+// DefaultDecisionWaitTimeout when ms is empty.
+func ParseWaitTimeout(ms string) (time.Duration, error) {
+	if ms == "" {
+		return DefaultDecisionWaitTimeout, nil
+	}
+
+	n, err := strconv.Atoi(ms)
+	if err != nil || n <= 0 || int64(n) > MaxDecisionWaitTimeout.Milliseconds() {
+		return 0, fmt.Errorf("%w: timeout_ms must be between 1 and %d", ErrInvalidWaitTimeout, MaxDecisionWaitTimeout.Milliseconds())
+	}
+	return time.Duration(n) * time.Millisecond, nil
+}
+
+// WaitForDecisionUseCase waits for the final status of a saved transaction.
+type WaitForDecisionUseCase struct {
+	waiters         *DecisionWaiters
+	transactionRepo repository.TransactionRepository
+	logger          zerolog.Logger
+}
+
+// NewWaitForDecisionUseCase creates a new use case waiting on waiters.
+func NewWaitForDecisionUseCase(
+	waiters *DecisionWaiters,
+	transactionRepo repository.TransactionRepository,
+	logger zerolog.Logger,
+) *WaitForDecisionUseCase {
+	return &WaitForDecisionUseCase{
+		waiters:         waiters,
+		transactionRepo: transactionRepo,
+		logger:          logger,
+	}
+}
+
+// Execute waits up to timeout for the decision of transaction and returns the
+// transaction with its final status and decided=true. If no decision arrives in
+// time, it returns the transaction as last read and decided=false.
+//
+// The transaction is read once the waiter is registered, so a decision consumed
+// before that is not missed, and once more on timeout, so a decision consumed by
+// another instance is still returned.
+func (uc *WaitForDecisionUseCase) Execute(
+	ctx context.Context,
+	transaction *entity.TransactionEntity,
+	timeout time.Duration,
+) (result *entity.TransactionEntity, decided bool, err error) {
+	if timeout <= 0 || timeout > MaxDecisionWaitTimeout {
+		return nil, false, fmt.Errorf("%w: %s", ErrInvalidWaitTimeout, timeout)
+	}
+
+	start := time.Now()
+	decisions, unregister := uc.waiters.Register(transaction.ID)
+	defer unregister()
+
+	if current := uc.read(ctx, transaction); current.Status != entity.PENDING {
+		uc.observe("decided", start)
+		return current, true, nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case status := <-decisions:
+		current := uc.read(ctx, transaction)
+		if current.Status == entity.PENDING {
+			// The read raced the status update; report the notified status
+			decidedTransaction := *current
+			decidedTransaction.Status = status
+			current = &decidedTransaction
+		}
+		uc.observe("decided", start)
+		return current, true, nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	current := uc.read(context.WithoutCancel(ctx), transaction)
+	if current.Status != entity.PENDING {
+		uc.observe("decided", start)
+		return current, true, nil
+	}
+
+	uc.observe("timeout", start)
+	return current, false, nil
+}
+
+// read returns the stored transaction, or transaction if it cannot be read.
+func (uc *WaitForDecisionUseCase) read(ctx context.Context, transaction *entity.TransactionEntity) *entity.TransactionEntity {
+	current, err := uc.transactionRepo.FindByID(ctx, transaction.ID)
+	if err != nil || current == nil {
+		uc.logger.Warn().Err(err).
+			Str("transaction_id", transaction.ID).
+			Msg("failed to read transaction while waiting for decision")
+		return transaction
+	}
+	return current
+}
+
+func (uc *WaitForDecisionUseCase) observe(outcome string, start time.Time) {
+	telemetry.DecisionWaits.WithLabelValues(outcome).Inc()
+	telemetry.DecisionWaitDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// waitMockRepo is a hand-written mock implementing TransactionRepository for
+// WaitForDecisionUseCase tests. It returns statuses in order, repeating the last.
+type waitMockRepo struct {
+	statuses []entity.TransactionStatus
+	findErr  error
+	reads    int
+	onRead   func(read int)
+}
+
+func (m *waitMockRepo) Save(_ context.Context, _ *entity.TransactionEntity) error {
+	return nil
+}
+
+func (m *waitMockRepo) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time, _ entity.DecisionPath) (*entity.TransactionEntity, error) {
+	return &entity.TransactionEntity{Status: entity.PENDING}, nil
+}
+
+func (m *waitMockRepo) FindByID(_ context.Context, id string) (*entity.TransactionEntity, error) {
+	m.reads++
+	if m.onRead != nil {
+		m.onRead(m.reads)
+	}
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	status := m.statuses[min(m.reads, len(m.statuses))-1]
+	return &entity.TransactionEntity{ID: id, Status: status}, nil
+}
+
+func (m *waitMockRepo) FindAllPaginated(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
+	return nil, "", nil
+}
+
+func (m *waitMockRepo) FindAll(_ context.Context) ([]entity.TransactionEntity, error) {
+	return nil, nil
+}
+
+func TestWaitForDecisionUseCase_Execute(t *testing.T) {
+	pending := &entity.TransactionEntity{ID: "tx-1", Status: entity.PENDING}
+
+	t.Run("returns immediately when already decided", func(t *testing.T) {
+		waiters := NewDecisionWaiters()
+		repo := &waitMockRepo{statuses: []entity.TransactionStatus{entity.APPROVED}}
+
+		result, decided, err := NewWaitForDecisionUseCase(waiters, repo, zerolog.Nop()).Execute(context.Background(), pending, time.Second)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !decided || result.Status != entity.APPROVED {
+			t.Errorf("expected APPROVED, got %v %s", decided, result.Status)
+		}
+		if waiters.Len() != 0 {
+			t.Errorf("expected the waiter to be removed, got %d", waiters.Len())
+		}
+	})
+
+	t.Run("returns the transaction once the decision is notified", func(t *testing.T) {
+		waiters := NewDecisionWaiters()
+		repo := &waitMockRepo{statuses: []entity.TransactionStatus{entity.PENDING, entity.DECLINED}}
+		repo.onRead = func(read int) {
+			if read == 1 {
+				waiters.Notify("tx-1", entity.DECLINED)
+			}
+		}
+
+		result, decided, err := NewWaitForDecisionUseCase(waiters, repo, zerolog.Nop()).Execute(context.Background(), pending, 10*time.Second)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !decided || result.Status != entity.DECLINED {
+			t.Errorf("expected DECLINED, got %v %s", decided, result.Status)
+		}
+	})
+
+	t.Run("uses the notified status when the read is stale", func(t *testing.T) {
+		waiters := NewDecisionWaiters()
+		repo := &waitMockRepo{statuses: []entity.TransactionStatus{entity.PENDING}}
+		repo.onRead = func(read int) {
+			if read == 1 {
+				waiters.Notify("tx-1", entity.APPROVED)
+			}
+		}
+
+		result, decided, err := NewWaitForDecisionUseCase(waiters, repo, zerolog.Nop()).Execute(context.Background(), pending, 10*time.Second)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !decided || result.Status != entity.APPROVED {
+			t.Errorf("expected APPROVED, got %v %s", decided, result.Status)
+		}
+	})
+
+	t.Run("returns the pending transaction on timeout", func(t *testing.T) {
+		waiters := NewDecisionWaiters()
+		repo := &waitMockRepo{statuses: []entity.TransactionStatus{entity.PENDING}}
+
+		result, decided, err := NewWaitForDecisionUseCase(waiters, repo, zerolog.Nop()).Execute(context.Background(), pending, 10*time.Millisecond)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if decided || result.Status != entity.PENDING {
+			t.Errorf("expected PENDING, got %v %s", decided, result.Status)
+		}
+		if repo.reads != 2 {
+			t.Errorf("expected the transaction to be read again on timeout, got %d reads", repo.reads)
+		}
+		if waiters.Len() != 0 {
+			t.Errorf("expected the waiter to be removed, got %d", waiters.Len())
+		}
+	})
+
+	t.Run("returns a decision consumed by another instance on timeout", func(t *testing.T) {
+		repo := &waitMockRepo{statuses: []entity.TransactionStatus{entity.PENDING, entity.APPROVED}}
+
+		result, decided, err := NewWaitForDecisionUseCase(NewDecisionWaiters(), repo, zerolog.Nop()).Execute(context.Background(), pending, 10*time.Millisecond)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !decided || result.Status != entity.APPROVED {
+			t.Errorf("expected APPROVED, got %v %s", decided, result.Status)
+		}
+	})
+
+	t.Run("falls back to the given transaction when it cannot be read", func(t *testing.T) {
+		repo := &waitMockRepo{findErr: errors.New("throttled")}
+
+		result, decided, err := NewWaitForDecisionUseCase(NewDecisionWaiters(), repo, zerolog.Nop()).Execute(context.Background(), pending, 10*time.Millisecond)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if decided || result != pending {
+			t.Errorf("expected the given transaction, got %v %+v", decided, result)
+		}
+	})
+
+	t.Run("rejects an invalid timeout", func(t *testing.T) {
+		repo := &waitMockRepo{statuses: []entity.TransactionStatus{entity.PENDING}}
+		uc := NewWaitForDecisionUseCase(NewDecisionWaiters(), repo, zerolog.Nop())
+
+		for _, timeout := range []time.Duration{0, MaxDecisionWaitTimeout + time.Millisecond} {
+			if _, _, err := uc.Execute(context.Background(), pending, timeout); !errors.Is(err, ErrInvalidWaitTimeout) {
+				t.Errorf("timeout %s: expected ErrInvalidWaitTimeout, got %v", timeout, err)
+			}
+		}
+	})
+}
+
+func TestParseWaitTimeout(t *testing.T) {
+	tests := []struct {
+		ms       string
+		expected time.Duration
+	}{
+		{"", DefaultDecisionWaitTimeout},
+		{"1", time.Millisecond},
+		{"2500", 2500 * time.Millisecond},
+		{"30000", MaxDecisionWaitTimeout},
+	}
+	for _, tt := range tests {
+		got, err := ParseWaitTimeout(tt.ms)
+		if err != nil || got != tt.expected {
+			t.Errorf("ParseWaitTimeout(%q) = %s, %v; expected %s", tt.ms, got, err, tt.expected)
+		}
+	}
+
+	for _, ms := range []string{"0", "-5", "30001", "abc", "99999999999999999999"} {
+		if _, err := ParseWaitTimeout(ms); !errors.Is(err, ErrInvalidWaitTimeout) {
+			t.Errorf("ParseWaitTimeout(%q): expected ErrInvalidWaitTimeout, got %v", ms, err)
+		}
+	}
+}
+
+func TestDecisionWaiters(t *testing.T) {
+	t.Run("notifies every waiter of the transaction", func(t *testing.T) {
+		waiters := NewDecisionWaiters()
+		first, _ := waiters.Register("tx-1")
+		second, _ := waiters.Register("tx-1")
+		other, unregisterOther := waiters.Register("tx-2")
+		defer unregisterOther()
+
+		waiters.Notify("tx-1", entity.APPROVED)
+
+		for _, ch := range []<-chan entity.TransactionStatus{first, second} {
+			if status := <-ch; status != entity.APPROVED {
+				t.Errorf("expected APPROVED, got %s", status)
+			}
+		}
+		select {
+		case status := <-other:
+			t.Errorf("expected tx-2 not to be notified, got %s", status)
+		default:
+		}
+		if waiters.Len() != 1 {
+			t.Errorf("expected 1 remaining waiter, got %d", waiters.Len())
+		}
+	})
+
+	t.Run("ignores the PENDING status left by FRAUD_CHECK", func(t *testing.T) {
+		waiters := NewDecisionWaiters()
+		ch, unregister := waiters.Register("tx-1")
+		defer unregister()
+
+		waiters.Notify("tx-1", entity.PENDING)
+
+		select {
+		case status := <-ch:
+			t.Errorf("expected no notification, got %s", status)
+		default:
+		}
+	})
+
+	t.Run("unregister removes only its waiter", func(t *testing.T) {
+		waiters := NewDecisionWaiters()
+		_, unregisterFirst := waiters.Register("tx-1")
+		second, unregisterSecond := waiters.Register("tx-1")
+		defer unregisterSecond()
+
+		unregisterFirst()
+		if waiters.Len() != 1 {
+			t.Fatalf("expected 1 waiter, got %d", waiters.Len())
+		}
+
+		waiters.Notify("tx-1", entity.DECLINED)
+		if status := <-second; status != entity.DECLINED {
+			t.Errorf("expected DECLINED, got %s", status)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"errors"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// RedriveRequest is the request body for redriving dead letters. Either ids or
+// all must be given.
+type RedriveRequest struct {
+	IDs           []string `json:"ids"`
+	All           bool     `json:"all"`
+	DryRun        bool     `json:"dry_run"`
+	RatePerSecond int      `json:"rate_per_second"`
+}
+
+// DeadLetterController handles the admin HTTP endpoints for inspecting the
+// Decision.Calculated dead-letter topic and redriving its messages.
+type DeadLetterController struct {
+	getDeadLetterQueuesUseCase *usecase.GetDeadLetterQueuesUseCase
+	listDeadLettersUseCase     *usecase.ListDeadLettersUseCase
+	getDeadLetterUseCase       *usecase.GetDeadLetterUseCase
+	redriveDeadLettersUseCase  *usecase.RedriveDeadLettersUseCase
+	logger                     zerolog.Logger
+}
+
+// NewDeadLetterController creates a new DeadLetterController.
+func NewDeadLetterController(
+	getDeadLetterQueuesUseCase *usecase.GetDeadLetterQueuesUseCase,
+	listDeadLettersUseCase *usecase.ListDeadLettersUseCase,
+	getDeadLetterUseCase *usecase.GetDeadLetterUseCase,
+	redriveDeadLettersUseCase *usecase.RedriveDeadLettersUseCase,
+	logger zerolog.Logger,
+) *DeadLetterController {
+	return &DeadLetterController{
+		getDeadLetterQueuesUseCase: getDeadLetterQueuesUseCase,
+		listDeadLettersUseCase:     listDeadLettersUseCase,
+		getDeadLetterUseCase:       getDeadLetterUseCase,
+		redriveDeadLettersUseCase:  redriveDeadLettersUseCase,
+		logger:                     logger,
+	}
+}
+
+// GetQueues handles GET /admin/dlq.
+func (dc *DeadLetterController) GetQueues(c *echo.Context) error {
+	queues, err := dc.getDeadLetterQueuesUseCase.Execute(c.Request().Context())
+	if err != nil {
+		return dc.handleError(c, err, "")
+	}
+
+	return c.JSON(http.StatusOK, DataResponse{Data: queues})
+}
+
+// ListDeadLetters handles GET /admin/dlq/:topic/messages. The optional limit query
+// parameter defaults to 100.
+func (dc *DeadLetterController) ListDeadLetters(c *echo.Context) error {
+	topic := c.Param("topic")
+
+	limit := 0
+	if raw := c.QueryParam("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			dc.logger.Warn().Err(err).Str("limit", raw).Msg("invalid limit parameter")
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid limit parameter",
+				Details: "limit must be a number",
+			})
+		}
+		limit = parsed
+	}
+
+	deadLetters, err := dc.listDeadLettersUseCase.Execute(c.Request().Context(), topic, limit)
+	if err != nil {
+		return dc.handleError(c, err, topic)
+	}
+
+	return c.JSON(http.StatusOK, DataResponse{Data: deadLetters})
+}
+
+// GetDeadLetter handles GET /admin/dlq/:topic/messages/:id, where id is
+// <partition>-<offset>.
+func (dc *DeadLetterController) GetDeadLetter(c *echo.Context) error {
+	topic := c.Param("topic")
+
+	deadLetter, err := dc.getDeadLetterUseCase.Execute(c.Request().Context(), topic, c.Param("id"))
+	if err != nil {
+		return dc.handleError(c, err, topic)
+	}
+
+	return c.JSON(http.StatusOK, DataResponse{Data: deadLetter})
+}
+
+// Redrive handles POST /admin/dlq/:topic/redrive.
+func (dc *DeadLetterController) Redrive(c *echo.Context) error {
+	topic := c.Param("topic")
+
+	var req RedriveRequest
+	if err := c.Bind(&req); err != nil {
+		dc.logger.Warn().Err(err).Msg("failed to bind redrive request")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	result, err := dc.redriveDeadLettersUseCase.Execute(c.Request().Context(), usecase.RedriveRequest{
+		Topic:         topic,
+		IDs:           req.IDs,
+		All:           req.All,
+		DryRun:        req.DryRun,
+		RatePerSecond: req.RatePerSecond,
+	})
+	if err != nil {
+		return dc.handleError(c, err, topic)
+	}
+
+	dc.logger.Info().
+		Str("topic", topic).
+		Bool("dry_run", result.DryRun).
+		Int("selected", result.Selected).
+		Int("redriven", result.Redriven).
+		Int("failed", len(result.Failed)).
+		Msg("dead letters redriven")
+
+	return c.JSON(http.StatusOK, DataResponse{Data: result})
+}
+
+// handleError maps dead-letter use case errors to HTTP responses.
+func (dc *DeadLetterController) handleError(c *echo.Context, err error, topic string) error {
+	switch {
+	case errors.Is(err, entity.ErrDeadLetterIDInvalid),
+		errors.Is(err, usecase.ErrDeadLetterLimitInvalid),
+		errors.Is(err, usecase.ErrRedriveSelectionInvalid),
+		errors.Is(err, usecase.ErrRedriveRateInvalid):
+		dc.logger.Warn().Err(err).Str("topic", topic).Msg("invalid dead letter request")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Details: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrDeadLetterQueueNotFound):
+		dc.logger.Warn().Str("topic", topic).Msg("dead-letter topic not found")
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Dead-letter topic not found",
+			Details: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrDeadLetterNotFound):
+		dc.logger.Warn().Str("topic", topic).Msg("dead letter not found")
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Dead letter not found",
+			Details: err.Error(),
+		})
+	default:
+		dc.logger.Error().Err(err).Str("topic", topic).Msg("failed to manage dead letters")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
+
+// RegisterRoutes registers the dead-letter admin routes on the Echo instance.
+func (dc *DeadLetterController) RegisterRoutes(e *echo.Echo) {
+	e.GET("/admin/dlq", dc.GetQueues)
+	e.GET("/admin/dlq/:topic/messages", dc.ListDeadLetters)
+	e.GET("/admin/dlq/:topic/messages/:id", dc.GetDeadLetter)
+	e.POST("/admin/dlq/:topic/redrive", dc.Redrive)
+}
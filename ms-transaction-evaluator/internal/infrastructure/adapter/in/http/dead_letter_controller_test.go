@@ -0,0 +1,180 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/usecase"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+type mockDeadLetterRepository struct {
+	deadLetters []entity.DeadLetter
+	redriven    []string
+}
+
+func (m *mockDeadLetterRepository) Count(_ context.Context) (int64, error) {
+	return int64(len(m.deadLetters)), nil
+}
+
+func (m *mockDeadLetterRepository) FindAll(_ context.Context, _ int) ([]entity.DeadLetter, error) {
+	return m.deadLetters, nil
+}
+
+func (m *mockDeadLetterRepository) FindByOffset(_ context.Context, partition int32, offset int64) (*entity.DeadLetter, error) {
+	for i := range m.deadLetters {
+		if m.deadLetters[i].Partition == partition && m.deadLetters[i].Offset == offset {
+			return &m.deadLetters[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDeadLetterRepository) Redrive(_ context.Context, deadLetter *entity.DeadLetter) error {
+	m.redriven = append(m.redriven, deadLetter.ID)
+	return nil
+}
+
+func serveDeadLetterRequest(e *echo.Echo, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func newDeadLetterController(repo *mockDeadLetterRepository) *echo.Echo {
+	queue := entity.DeadLetterQueue{Topic: "Decision.Calculated.DLQ", SourceTopic: "Decision.Calculated"}
+	controller := NewDeadLetterController(
+		usecase.NewGetDeadLetterQueuesUseCase(repo, queue),
+		usecase.NewListDeadLettersUseCase(repo, queue),
+		usecase.NewGetDeadLetterUseCase(repo, queue),
+		usecase.NewRedriveDeadLettersUseCase(repo, queue),
+		zerolog.Nop(),
+	)
+
+	e := echo.New()
+	controller.RegisterRoutes(e)
+
+	return e
+}
+
+func testDeadLetters() []entity.DeadLetter {
+	return []entity.DeadLetter{{
+		ID:            "0-4",
+		Partition:     0,
+		Offset:        4,
+		TransactionID: "tx-1",
+		SourceTopic:   "Decision.Calculated",
+		Payload:       `{"transaction_id":"tx-1","status":"APPROVED"}`,
+		Error:         "failed to update transaction status: timeout",
+		Attempts:      1,
+	}}
+}
+
+func TestDeadLetterController_GetQueues(t *testing.T) {
+	e := newDeadLetterController(&mockDeadLetterRepository{deadLetters: testDeadLetters()})
+
+	rec := serveDeadLetterRequest(e, http.MethodGet, "/admin/dlq", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		Data []entity.DeadLetterQueue `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Data) != 1 || body.Data[0].Count != 1 {
+		t.Errorf("expected one queue with one message, got %+v", body.Data)
+	}
+}
+
+func TestDeadLetterController_ListAndGet(t *testing.T) {
+	e := newDeadLetterController(&mockDeadLetterRepository{deadLetters: testDeadLetters()})
+
+	rec := serveDeadLetterRequest(e, http.MethodGet, "/admin/dlq/Decision.Calculated.DLQ/messages?limit=10", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serveDeadLetterRequest(e, http.MethodGet, "/admin/dlq/Decision.Calculated.DLQ/messages/0-4", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		Data entity.DeadLetter `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Data.TransactionID != "tx-1" || body.Data.Error != "failed to update transaction status: timeout" || body.Data.Payload != `{"transaction_id":"tx-1","status":"APPROVED"}` {
+		t.Errorf("unexpected dead letter %+v", body.Data)
+	}
+}
+
+func TestDeadLetterController_Errors(t *testing.T) {
+	e := newDeadLetterController(&mockDeadLetterRepository{deadLetters: testDeadLetters()})
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		expected int
+	}{
+		{"unknown topic", http.MethodGet, "/admin/dlq/Other.DLQ/messages", "", http.StatusNotFound},
+		{"invalid limit", http.MethodGet, "/admin/dlq/Decision.Calculated.DLQ/messages?limit=x", "", http.StatusBadRequest},
+		{"limit too high", http.MethodGet, "/admin/dlq/Decision.Calculated.DLQ/messages?limit=5000", "", http.StatusBadRequest},
+		{"invalid id", http.MethodGet, "/admin/dlq/Decision.Calculated.DLQ/messages/abc", "", http.StatusBadRequest},
+		{"unknown id", http.MethodGet, "/admin/dlq/Decision.Calculated.DLQ/messages/0-5", "", http.StatusNotFound},
+		{"no selection", http.MethodPost, "/admin/dlq/Decision.Calculated.DLQ/redrive", `{}`, http.StatusBadRequest},
+		{"invalid rate", http.MethodPost, "/admin/dlq/Decision.Calculated.DLQ/redrive", `{"all":true,"rate_per_second":1000}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveDeadLetterRequest(e, tt.method, tt.target, tt.body)
+			if rec.Code != tt.expected {
+				t.Errorf("expected status %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestDeadLetterController_Redrive(t *testing.T) {
+	repo := &mockDeadLetterRepository{deadLetters: testDeadLetters()}
+	e := newDeadLetterController(repo)
+
+	rec := serveDeadLetterRequest(e, http.MethodPost, "/admin/dlq/Decision.Calculated.DLQ/redrive", `{"all":true,"dry_run":true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(repo.redriven) != 0 {
+		t.Fatalf("expected a dry run to redrive nothing, got %v", repo.redriven)
+	}
+
+	rec = serveDeadLetterRequest(e, http.MethodPost, "/admin/dlq/Decision.Calculated.DLQ/redrive", `{"ids":["0-4"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var body struct {
+		Data entity.RedriveResult `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Data.Redriven != 1 || len(repo.redriven) != 1 || repo.redriven[0] != "0-4" {
+		t.Errorf("expected 0-4 to be redriven, got %+v", body.Data)
+	}
+}
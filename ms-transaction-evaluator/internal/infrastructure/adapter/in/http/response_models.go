@@ -11,3 +11,8 @@ type ErrorResponse struct {
 	Error   string `json:"error" example:"Validation failed"`
 	Details string `json:"details" example:"customer email is invalid"`
 }
+
+// DataResponse wraps the data returned by an endpoint
+type DataResponse struct {
+	Data interface{} `json:"data"`
+}
@@ -5,21 +5,36 @@ import (
 	"ms-transaction-evaluator/internal/domain/entity"
 	"ms-transaction-evaluator/internal/domain/usecase"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v5"
 	"github.com/rs/zerolog"
 )
 
+// Idempotency headers of POST /evaluate.
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
 type TransactionController struct {
 	validateUseCase *usecase.ValidateCreateTransactionPayloadUseCase
 	saveUseCase     *usecase.SaveTransactionUseCase
+	waitUseCase     *usecase.WaitForDecisionUseCase
 	logger          zerolog.Logger
 }
 
-func NewTransactionController(validateUseCase *usecase.ValidateCreateTransactionPayloadUseCase, saveUseCase *usecase.SaveTransactionUseCase, logger zerolog.Logger) *TransactionController {
+func NewTransactionController(
+	validateUseCase *usecase.ValidateCreateTransactionPayloadUseCase,
+	saveUseCase *usecase.SaveTransactionUseCase,
+	waitUseCase *usecase.WaitForDecisionUseCase,
+	logger zerolog.Logger,
+) *TransactionController {
 	return &TransactionController{
 		validateUseCase: validateUseCase,
 		saveUseCase:     saveUseCase,
+		waitUseCase:     waitUseCase,
 		logger:          logger,
 	}
 }
@@ -31,8 +46,13 @@ func NewTransactionController(validateUseCase *usecase.ValidateCreateTransaction
 // @Accept json
 // @Produce json
 // @Param request body entity.EvaluateTransactionRequest true "Transaction evaluation request"
-// @Success 200 {object} SuccessResponse "Transaction validation successful"
+// @Param Idempotency-Key header string false "Key identifying the payment across retries; defaults to external_id"
+// @Param wait query bool false "Wait for the decision before responding"
+// @Param timeout_ms query int false "How long to wait for the decision in milliseconds (default 5000, max 30000)"
+// @Success 200 {object} SuccessResponse "Transaction saved, or decided when waiting"
+// @Success 202 {object} SuccessResponse "Transaction saved, decision still pending after the wait"
 // @Failure 400 {object} ErrorResponse "Invalid request or validation failed"
+// @Failure 409 {object} ErrorResponse "Idempotency key reused with a different payload"
 // @Router /evaluate [post]
 func (tc *TransactionController) EvaluateTransaction(c *echo.Context) error {
 	var req entity.EvaluateTransactionRequest
@@ -55,6 +75,17 @@ func (tc *TransactionController) EvaluateTransaction(c *echo.Context) error {
 		Str("customer_id", req.CustomerInfo.CustomerID).
 		Msg("request parsed")
 
+	req.IdempotencyKey = c.Request().Header.Get(HeaderIdempotencyKey)
+
+	wait, timeout, err := parseWait(c)
+	if err != nil {
+		tc.logger.Warn().Err(err).Msg("invalid wait parameters")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid wait parameters",
+			Details: err.Error(),
+		})
+	}
+
 	// Validate the request
 	if err := tc.validateUseCase.Execute(&req); err != nil {
 		tc.logger.Warn().Err(err).Msg("validation failed")
@@ -65,12 +96,12 @@ func (tc *TransactionController) EvaluateTransaction(c *echo.Context) error {
 	}
 
 	// Save the transaction after validation succeeds
-	transaction, err := tc.saveUseCase.Execute(c.Request().Context(), &req)
+	transaction, replayed, err := tc.saveUseCase.Execute(c.Request().Context(), &req)
 	if err != nil {
-		if errors.Is(err, usecase.ErrEventPublishFailed) {
-			tc.logger.Error().Err(err).Msg("transaction saved but Kafka publish failed")
-			return c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "Transaction saved but event publish failed",
+		if errors.Is(err, usecase.ErrIdempotencyKeyConflict) {
+			tc.logger.Warn().Err(err).Str("idempotency_key", req.Key()).Msg("idempotency key reused with a different payload")
+			return c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "Idempotency key conflict",
 				Details: err.Error(),
 			})
 		}
@@ -82,10 +113,22 @@ func (tc *TransactionController) EvaluateTransaction(c *echo.Context) error {
 		})
 	}
 
-	tc.logger.Info().
-		Str("transaction_id", transaction.ID).
-		Str("status", string(transaction.Status)).
-		Msg("transaction processed successfully")
+	if replayed {
+		tc.logger.Info().
+			Str("transaction_id", transaction.ID).
+			Str("idempotency_key", req.Key()).
+			Msg("idempotent retry, returning the original transaction")
+		c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	} else {
+		tc.logger.Info().
+			Str("transaction_id", transaction.ID).
+			Str("status", string(transaction.Status)).
+			Msg("transaction processed successfully")
+	}
+
+	if wait {
+		return tc.respondWithDecision(c, transaction, timeout)
+	}
 
 	// Return success with the saved transaction
 	return c.JSON(http.StatusOK, SuccessResponse{
@@ -94,6 +137,56 @@ func (tc *TransactionController) EvaluateTransaction(c *echo.Context) error {
 	})
 }
 
+// respondWithDecision waits for the decision of the saved transaction. It responds
+// 200 with the decided transaction, or 202 with the pending one on timeout.
+func (tc *TransactionController) respondWithDecision(c *echo.Context, transaction *entity.TransactionEntity, timeout time.Duration) error {
+	decided, ok, err := tc.waitUseCase.Execute(c.Request().Context(), transaction, timeout)
+	if err != nil {
+		tc.logger.Error().Err(err).Str("transaction_id", transaction.ID).Msg("failed to wait for decision")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to wait for decision",
+			Details: err.Error(),
+		})
+	}
+
+	if !ok {
+		tc.logger.Info().
+			Str("transaction_id", transaction.ID).
+			Dur("timeout", timeout).
+			Msg("no decision before timeout, returning pending transaction")
+		return c.JSON(http.StatusAccepted, SuccessResponse{
+			Message: "Transaction saved, decision pending",
+			Data:    decided,
+		})
+	}
+
+	tc.logger.Info().
+		Str("transaction_id", decided.ID).
+		Str("status", string(decided.Status)).
+		Msg("transaction decided")
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Transaction decided",
+		Data:    decided,
+	})
+}
+
+// parseWait reads the wait and timeout_ms query parameters.
+func parseWait(c *echo.Context) (wait bool, timeout time.Duration, err error) {
+	if raw := c.QueryParam("wait"); raw != "" {
+		wait, err = strconv.ParseBool(raw)
+		if err != nil {
+			return false, 0, errors.New("wait must be true or false")
+		}
+	}
+
+	timeout, err = usecase.ParseWaitTimeout(c.QueryParam("timeout_ms"))
+	if err != nil {
+		return false, 0, err
+	}
+
+	return wait, timeout, nil
+}
+
 func (tc *TransactionController) RegisterRoutes(e *echo.Echo) {
 	e.POST("/evaluate", tc.EvaluateTransaction)
 }
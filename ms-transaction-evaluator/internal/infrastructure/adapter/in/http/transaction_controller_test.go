@@ -15,41 +15,46 @@ import (
 	"github.com/rs/zerolog"
 )
 
-type mockTransactionRepository struct{}
+type mockOutboxRepository struct{}
 
-func (m *mockTransactionRepository) Save(_ context.Context, _ *entity.TransactionEntity) error {
+func (m *mockOutboxRepository) SaveTransaction(_ context.Context, _ *entity.TransactionEntity, _ *entity.OutboxEntry, _ *entity.IdempotencyKey) error {
 	return nil
 }
 
-func (m *mockTransactionRepository) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) error {
-	return nil
-}
-
-func (m *mockTransactionRepository) FindByID(_ context.Context, _ string) (*entity.TransactionEntity, error) {
+func (m *mockOutboxRepository) FindDue(_ context.Context, _ time.Time, _ int) ([]entity.OutboxEntry, error) {
 	return nil, nil
 }
 
-func (m *mockTransactionRepository) FindAllPaginated(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
-	return nil, "", nil
+func (m *mockOutboxRepository) Claim(_ context.Context, _ string, _ time.Time, _ time.Time) (bool, error) {
+	return true, nil
 }
 
-func (m *mockTransactionRepository) FindAll(_ context.Context) ([]entity.TransactionEntity, error) {
-	return nil, nil
+func (m *mockOutboxRepository) MarkSent(_ context.Context, _ string, _ time.Time) error {
+	return nil
 }
 
-type mockEventPublisher struct{}
+func (m *mockOutboxRepository) MarkFailed(_ context.Context, _ string, _ int, _ string, _ time.Time) error {
+	return nil
+}
 
-func (m *mockEventPublisher) Publish(ctx context.Context, transaction *entity.TransactionEntity) error {
+func (m *mockOutboxRepository) MarkExhausted(_ context.Context, _ string, _ int, _ string) error {
 	return nil
 }
 
+type mockIdempotencyKeyRepository struct {
+	keys map[string]*entity.IdempotencyKey
+}
+
+func (m *mockIdempotencyKeyRepository) Find(_ context.Context, key string) (*entity.IdempotencyKey, error) {
+	return m.keys[key], nil
+}
+
 func TestTransactionController_EvaluateTransaction(t *testing.T) {
 	// Setup
 	validateUseCase := usecase.NewValidateCreateTransactionPayloadUseCase()
-	mockRepo := &mockTransactionRepository{}
-	mockPub := &mockEventPublisher{}
-	saveUseCase := usecase.NewSaveTransactionUseCase(mockRepo, mockPub)
-	controller := NewTransactionController(validateUseCase, saveUseCase, zerolog.Nop())
+	mockRepo := &mockOutboxRepository{}
+	saveUseCase := usecase.NewSaveTransactionUseCase(mockRepo, &mockQueryTransactionRepository{}, &mockIdempotencyKeyRepository{}, 0, nil, nil)
+	controller := NewTransactionController(validateUseCase, saveUseCase, nil, zerolog.Nop())
 	e := echo.New()
 
 	t.Run("should return 200 with valid transaction request", func(t *testing.T) {
@@ -321,3 +326,183 @@ func TestTransactionController_EvaluateTransaction(t *testing.T) {
 		}
 	})
 }
+
+func TestTransactionController_EvaluateTransaction_IdempotencyKey(t *testing.T) {
+	requestBody := `{
+		"amount_in_cents": 10000,
+		"currency": "USD",
+		"payment_method": "CARD",
+		"external_id": "order-1234",
+		"customer": {
+			"customer_id": "cust_123",
+			"name": "John Doe",
+			"email": "john@example.com",
+			"phone": "+1234567890",
+			"ip_address": "192.168.1.1"
+		}
+	}`
+
+	var recorded entity.EvaluateTransactionRequest
+	if err := json.Unmarshal([]byte(requestBody), &recorded); err != nil {
+		t.Fatalf("Failed to unmarshal request: %v", err)
+	}
+	keys := &mockIdempotencyKeyRepository{keys: map[string]*entity.IdempotencyKey{
+		"key-1": {Key: "key-1", Fingerprint: recorded.Fingerprint(), Transaction: entity.TransactionEntity{ID: "txn-original", Status: entity.PENDING}},
+		"key-2": {Key: "key-2", Fingerprint: "other", Transaction: entity.TransactionEntity{ID: "txn-other"}},
+	}}
+	saveUseCase := usecase.NewSaveTransactionUseCase(&mockOutboxRepository{}, &mockQueryTransactionRepository{}, keys, 0, nil, nil)
+	controller := NewTransactionController(usecase.NewValidateCreateTransactionPayloadUseCase(), saveUseCase, nil, zerolog.Nop())
+	e := echo.New()
+
+	evaluate := func(t *testing.T, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/evaluate", strings.NewReader(requestBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(HeaderIdempotencyKey, key)
+		rec := httptest.NewRecorder()
+
+		if err := controller.EvaluateTransaction(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		return rec
+	}
+
+	t.Run("should replay the original transaction for a reused key", func(t *testing.T) {
+		rec := evaluate(t, "key-1")
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+		if rec.Header().Get(HeaderIdempotentReplayed) != "true" {
+			t.Errorf("Expected %s header, got %q", HeaderIdempotentReplayed, rec.Header().Get(HeaderIdempotentReplayed))
+		}
+
+		var response struct {
+			Data entity.TransactionEntity `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if response.Data.ID != "txn-original" {
+			t.Errorf("Expected txn-original, got %q", response.Data.ID)
+		}
+	})
+
+	t.Run("should return 409 for a key reused with a different payload", func(t *testing.T) {
+		rec := evaluate(t, "key-2")
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Expected status code %d, got %d", http.StatusConflict, rec.Code)
+		}
+
+		var response map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if response["error"] != "Idempotency key conflict" {
+			t.Errorf("Expected 'Idempotency key conflict' error, got: %v", response["error"])
+		}
+	})
+
+	t.Run("should save a new transaction for an unused key", func(t *testing.T) {
+		rec := evaluate(t, "key-3")
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+		if rec.Header().Get(HeaderIdempotentReplayed) != "" {
+			t.Errorf("Expected no %s header on a new transaction", HeaderIdempotentReplayed)
+		}
+	})
+}
+
+func TestTransactionController_EvaluateTransaction_Wait(t *testing.T) {
+	requestBody := `{
+		"amount_in_cents": 10000,
+		"currency": "USD",
+		"payment_method": "CARD",
+		"customer": {
+			"customer_id": "cust_123",
+			"name": "John Doe",
+			"email": "john@example.com",
+			"phone": "+1234567890",
+			"ip_address": "192.168.1.1"
+		}
+	}`
+	e := echo.New()
+
+	newController := func(status entity.TransactionStatus) *TransactionController {
+		repo := &mockQueryTransactionRepository{
+			findByIDFunc: func(_ context.Context, id string) (*entity.TransactionEntity, error) {
+				return &entity.TransactionEntity{ID: id, Status: status}, nil
+			},
+		}
+		waitUseCase := usecase.NewWaitForDecisionUseCase(usecase.NewDecisionWaiters(), repo, zerolog.Nop())
+		saveUseCase := usecase.NewSaveTransactionUseCase(&mockOutboxRepository{}, &mockQueryTransactionRepository{}, &mockIdempotencyKeyRepository{}, 0, nil, nil)
+		return NewTransactionController(usecase.NewValidateCreateTransactionPayloadUseCase(), saveUseCase, waitUseCase, zerolog.Nop())
+	}
+
+	evaluate := func(t *testing.T, controller *TransactionController, query string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, "/evaluate?"+query, strings.NewReader(requestBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		if err := controller.EvaluateTransaction(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		var response map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		return rec, response
+	}
+
+	t.Run("should return 200 with the decided transaction", func(t *testing.T) {
+		rec, response := evaluate(t, newController(entity.DECLINED), "wait=true&timeout_ms=1000")
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+		data, _ := response["data"].(map[string]interface{})
+		if data["status"] != "DECLINED" {
+			t.Errorf("Expected DECLINED, got: %v", data["status"])
+		}
+	})
+
+	t.Run("should return 202 with the pending transaction on timeout", func(t *testing.T) {
+		rec, response := evaluate(t, newController(entity.PENDING), "wait=true&timeout_ms=10")
+
+		if rec.Code != http.StatusAccepted {
+			t.Errorf("Expected status code %d, got %d", http.StatusAccepted, rec.Code)
+		}
+		data, _ := response["data"].(map[string]interface{})
+		if data["status"] != "PENDING" || data["id"] == "" {
+			t.Errorf("Expected the pending transaction, got: %v", data)
+		}
+	})
+
+	t.Run("should not wait without wait=true", func(t *testing.T) {
+		rec, response := evaluate(t, newController(entity.APPROVED), "wait=false")
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+		data, _ := response["data"].(map[string]interface{})
+		if data["status"] != "PENDING" {
+			t.Errorf("Expected the saved PENDING transaction, got: %v", data["status"])
+		}
+	})
+
+	t.Run("should return 400 with invalid wait parameters", func(t *testing.T) {
+		for _, query := range []string{"wait=maybe", "wait=true&timeout_ms=0", "wait=true&timeout_ms=60000", "wait=true&timeout_ms=abc"} {
+			rec, response := evaluate(t, newController(entity.APPROVED), query)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected status code %d, got %d", query, http.StatusBadRequest, rec.Code)
+			}
+			if response["error"] != "Invalid wait parameters" {
+				t.Errorf("%s: expected 'Invalid wait parameters' error, got: %v", query, response["error"])
+			}
+		}
+	})
+}
@@ -24,7 +24,10 @@ type TransactionResponse struct {
 	CustomerEmail         string                   `json:"customer_email"`
 	CustomerPhone         string                   `json:"customer_phone"`
 	CustomerIPAddress     string                   `json:"customer_ip_address"`
+	ExternalID            string                   `json:"external_id,omitempty"`
+	MerchantID            string                   `json:"merchant_id,omitempty"`
 	Status                entity.TransactionStatus `json:"status"`
+	DecisionPath          entity.DecisionPath      `json:"decision_path,omitempty"`
 	CreatedAt             time.Time                `json:"created_at"`
 	UpdatedAt             time.Time                `json:"updated_at"`
 	FinalizedAt           *time.Time               `json:"finalized_at,omitempty"`
@@ -44,7 +47,10 @@ func toTransactionResponse(e entity.TransactionEntity) TransactionResponse {
 		CustomerEmail:     e.CustomerEmail,
 		CustomerPhone:     e.CustomerPhone,
 		CustomerIPAddress: e.CustomerIPAddress,
+		ExternalID:        e.ExternalID,
+		MerchantID:        e.MerchantID,
 		Status:            e.Status,
+		DecisionPath:      e.DecisionPath,
 		CreatedAt:         e.CreatedAt,
 		UpdatedAt:         e.UpdatedAt,
 		FinalizedAt:       e.FinalizedAt,
@@ -91,7 +97,10 @@ func NewTransactionQueryController(
 
 const defaultLimit = 20
 
-// ListTransactions handles GET /transactions.
+// ListTransactions handles GET /transactions. Besides limit and cursor, the
+// status, payment_method, currency, customer_id, min_amount_in_cents,
+// max_amount_in_cents, created_from, created_to and order query parameters
+// filter and sort the list.
 func (tqc *TransactionQueryController) ListTransactions(c *echo.Context) error {
 	limitStr := c.QueryParam("limit")
 	cursor := c.QueryParam("cursor")
@@ -109,7 +118,19 @@ func (tqc *TransactionQueryController) ListTransactions(c *echo.Context) error {
 		limit = parsed
 	}
 
-	transactions, nextCursor, err := tqc.listUseCase.Execute(c.Request().Context(), limit, cursor)
+	transactions, nextCursor, err := tqc.listUseCase.Execute(c.Request().Context(), usecase.ListTransactionsInput{
+		Limit:            limit,
+		Cursor:           cursor,
+		Status:           c.QueryParam("status"),
+		PaymentMethod:    c.QueryParam("payment_method"),
+		Currency:         c.QueryParam("currency"),
+		CustomerID:       c.QueryParam("customer_id"),
+		MinAmountInCents: c.QueryParam("min_amount_in_cents"),
+		MaxAmountInCents: c.QueryParam("max_amount_in_cents"),
+		CreatedFrom:      c.QueryParam("created_from"),
+		CreatedTo:        c.QueryParam("created_to"),
+		Order:            c.QueryParam("order"),
+	})
 	if err != nil {
 		if errors.Is(err, usecase.ErrInvalidLimit) {
 			tqc.logger.Warn().Int("limit", limit).Msg("invalid limit value")
@@ -118,6 +139,13 @@ func (tqc *TransactionQueryController) ListTransactions(c *echo.Context) error {
 				Details: err.Error(),
 			})
 		}
+		if errors.Is(err, usecase.ErrInvalidTransactionFilter) {
+			tqc.logger.Warn().Err(err).Msg("invalid transaction filter")
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid filter parameter",
+				Details: err.Error(),
+			})
+		}
 		if errors.Is(err, usecase.ErrInvalidCursor) {
 			tqc.logger.Warn().Str("cursor", cursor).Msg("invalid cursor value")
 			return c.JSON(http.StatusBadRequest, ErrorResponse{
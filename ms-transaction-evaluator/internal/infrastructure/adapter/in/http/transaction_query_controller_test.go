@@ -18,15 +18,15 @@ import (
 // used by the query controller tests.
 type mockQueryTransactionRepository struct {
 	findByIDFunc         func(ctx context.Context, id string) (*entity.TransactionEntity, error)
-	findAllPaginatedFunc func(ctx context.Context, limit int, cursor string) ([]entity.TransactionEntity, string, error)
+	findAllPaginatedFunc func(ctx context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error)
 }
 
 func (m *mockQueryTransactionRepository) Save(_ context.Context, _ *entity.TransactionEntity) error {
 	return nil
 }
 
-func (m *mockQueryTransactionRepository) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) error {
-	return nil
+func (m *mockQueryTransactionRepository) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time, _ entity.DecisionPath) (*entity.TransactionEntity, error) {
+	return &entity.TransactionEntity{Status: entity.PENDING}, nil
 }
 
 func (m *mockQueryTransactionRepository) FindByID(ctx context.Context, id string) (*entity.TransactionEntity, error) {
@@ -36,9 +36,9 @@ func (m *mockQueryTransactionRepository) FindByID(ctx context.Context, id string
 	return nil, nil
 }
 
-func (m *mockQueryTransactionRepository) FindAllPaginated(ctx context.Context, limit int, cursor string) ([]entity.TransactionEntity, string, error) {
+func (m *mockQueryTransactionRepository) FindAllPaginated(ctx context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
 	if m.findAllPaginatedFunc != nil {
-		return m.findAllPaginatedFunc(ctx, limit, cursor)
+		return m.findAllPaginatedFunc(ctx, query)
 	}
 	return nil, "", nil
 }
@@ -79,7 +79,7 @@ func TestTransactionQueryController_ListTransactions(t *testing.T) {
 	t.Run("should return 200 with valid params and transactions", func(t *testing.T) {
 		txn := sampleTransaction()
 		repo := &mockQueryTransactionRepository{
-			findAllPaginatedFunc: func(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
+			findAllPaginatedFunc: func(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
 				return []entity.TransactionEntity{txn}, "next123", nil
 			},
 		}
@@ -109,7 +109,7 @@ func TestTransactionQueryController_ListTransactions(t *testing.T) {
 
 	t.Run("should return 200 with empty result", func(t *testing.T) {
 		repo := &mockQueryTransactionRepository{
-			findAllPaginatedFunc: func(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
+			findAllPaginatedFunc: func(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
 				return []entity.TransactionEntity{}, "", nil
 			},
 		}
@@ -183,8 +183,8 @@ func TestTransactionQueryController_ListTransactions(t *testing.T) {
 
 	t.Run("should return 400 for invalid cursor", func(t *testing.T) {
 		repo := &mockQueryTransactionRepository{
-			findAllPaginatedFunc: func(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
-				return nil, "", usecase.ErrInvalidCursor
+			findAllPaginatedFunc: func(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
+				return nil, "", entity.ErrCursorMalformed
 			},
 		}
 		_, e := newQueryController(repo)
@@ -210,8 +210,8 @@ func TestTransactionQueryController_ListTransactions(t *testing.T) {
 	t.Run("should use default limit when not specified", func(t *testing.T) {
 		var capturedLimit int
 		repo := &mockQueryTransactionRepository{
-			findAllPaginatedFunc: func(_ context.Context, limit int, _ string) ([]entity.TransactionEntity, string, error) {
-				capturedLimit = limit
+			findAllPaginatedFunc: func(_ context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
+				capturedLimit = query.Limit
 				return []entity.TransactionEntity{}, "", nil
 			},
 		}
@@ -231,6 +231,54 @@ func TestTransactionQueryController_ListTransactions(t *testing.T) {
 	})
 }
 
+func TestTransactionQueryController_ListTransactions_Filters(t *testing.T) {
+	t.Run("should pass the filters to the repository", func(t *testing.T) {
+		var captured entity.TransactionQuery
+		repo := &mockQueryTransactionRepository{
+			findAllPaginatedFunc: func(_ context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
+				captured = query
+				return []entity.TransactionEntity{}, "", nil
+			},
+		}
+		_, e := newQueryController(repo)
+
+		req := httptest.NewRequest(http.MethodGet, "/transactions?status=DECLINED&payment_method=CRYPTO&currency=EUR&customer_id=cust_9"+
+			"&min_amount_in_cents=1000&max_amount_in_cents=9000&created_from=2025-01-01&created_to=2025-01-31&order=asc", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		filter := captured.Filter
+		if filter.Status != entity.DECLINED || filter.PaymentMethod != entity.CRYPTO || filter.Currency != entity.EUR || filter.CustomerID != "cust_9" {
+			t.Errorf("unexpected filter %+v", filter)
+		}
+		if filter.MinAmountInCents == nil || filter.MaxAmountInCents == nil || filter.CreatedFrom == nil || filter.CreatedTo == nil {
+			t.Errorf("expected amount and date ranges, got %+v", filter)
+		}
+		if captured.Order != entity.SortAscending {
+			t.Errorf("expected ascending order, got %s", captured.Order)
+		}
+	})
+
+	t.Run("should return 400 for an invalid filter", func(t *testing.T) {
+		_, e := newQueryController(&mockQueryTransactionRepository{})
+
+		req := httptest.NewRequest(http.MethodGet, "/transactions?currency=GBP", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+		var resp ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Error != "Invalid filter parameter" {
+			t.Errorf("unexpected body %s", rec.Body.String())
+		}
+	})
+}
+
 func TestTransactionQueryController_GetTransaction(t *testing.T) {
 	t.Run("should return 200 with valid ID", func(t *testing.T) {
 		txn := sampleTransaction()
@@ -1,9 +1,11 @@
 package http
 
 import (
+	"errors"
 	"ms-transaction-evaluator/internal/domain/entity"
 	"ms-transaction-evaluator/internal/domain/usecase"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v5"
 	"github.com/rs/zerolog"
@@ -12,19 +14,41 @@ import (
 // TransactionStatsResponse is the API response DTO for transaction statistics.
 // PaymentMethods uses map[string]int (not map[PaymentMethod]int) since this is the HTTP layer.
 type TransactionStatsResponse struct {
-	Today          int            `json:"today"`
-	ThisWeek       int            `json:"this_week"`
-	ThisMonth      int            `json:"this_month"`
-	Total          int            `json:"total"`
-	Approved       int            `json:"approved"`
-	Declined       int            `json:"declined"`
-	Pending        int            `json:"pending"`
-	PaymentMethods map[string]int `json:"payment_methods"`
-	AvgLatencyMs   float64        `json:"avg_latency_ms"`
-	FinalizedCount int            `json:"finalized_count"`
-	LatencyLow     int            `json:"latency_low"`
-	LatencyMedium  int            `json:"latency_medium"`
-	LatencyHigh    int            `json:"latency_high"`
+	Today                    int                                   `json:"today"`
+	ThisWeek                 int                                   `json:"this_week"`
+	ThisMonth                int                                   `json:"this_month"`
+	Total                    int                                   `json:"total"`
+	Approved                 int                                   `json:"approved"`
+	Declined                 int                                   `json:"declined"`
+	Pending                  int                                   `json:"pending"`
+	PaymentMethods           map[string]int                        `json:"payment_methods"`
+	AvgLatencyMs             float64                               `json:"avg_latency_ms"`
+	FinalizedCount           int                                   `json:"finalized_count"`
+	LatencyLow               int                                   `json:"latency_low"`
+	LatencyMedium            int                                   `json:"latency_medium"`
+	LatencyHigh              int                                   `json:"latency_high"`
+	LatencyLowThresholdMs    float64                               `json:"latency_low_threshold_ms"`
+	LatencyMediumThresholdMs float64                               `json:"latency_medium_threshold_ms"`
+	LatencyTierRelativeError float64                               `json:"latency_tier_relative_error"`
+	LatencyPercentiles       LatencyPercentilesResponse            `json:"latency_percentiles"`
+	LatencyByStatus          map[string]LatencyPercentilesResponse `json:"latency_by_status"`
+	LatencyByPaymentMethod   map[string]LatencyPercentilesResponse `json:"latency_by_payment_method"`
+	FraudCheckDecisions      int                                   `json:"fraud_check_decisions"`
+	DirectDecisions          int                                   `json:"direct_decisions"`
+}
+
+// LatencyPercentilesResponse is the API response DTO for the finalization latency
+// percentiles of a set of transactions.
+type LatencyPercentilesResponse struct {
+	Count int     `json:"count"`
+	P50Ms float64 `json:"p50_ms"`
+	P90Ms float64 `json:"p90_ms"`
+	P95Ms float64 `json:"p95_ms"`
+	P99Ms float64 `json:"p99_ms"`
+}
+
+func toLatencyPercentilesResponse(p entity.LatencyPercentiles) LatencyPercentilesResponse {
+	return LatencyPercentilesResponse{Count: p.Count, P50Ms: p.P50Ms, P90Ms: p.P90Ms, P95Ms: p.P95Ms, P99Ms: p.P99Ms}
 }
 
 // toTransactionStatsResponse maps a domain TransactionStats entity to the HTTP response DTO.
@@ -34,6 +58,16 @@ func toTransactionStatsResponse(stats *entity.TransactionStats) TransactionStats
 		paymentMethods[string(method)] = count
 	}
 
+	byStatus := make(map[string]LatencyPercentilesResponse, len(stats.LatencyByStatus))
+	for status, p := range stats.LatencyByStatus {
+		byStatus[string(status)] = toLatencyPercentilesResponse(p)
+	}
+
+	byPaymentMethod := make(map[string]LatencyPercentilesResponse, len(stats.LatencyByPaymentMethod))
+	for method, p := range stats.LatencyByPaymentMethod {
+		byPaymentMethod[string(method)] = toLatencyPercentilesResponse(p)
+	}
+
 	return TransactionStatsResponse{
 		Today:          stats.Today,
 		ThisWeek:       stats.ThisWeek,
@@ -48,23 +82,110 @@ func toTransactionStatsResponse(stats *entity.TransactionStats) TransactionStats
 		LatencyLow:     stats.LatencyLow,
 		LatencyMedium:  stats.LatencyMedium,
 		LatencyHigh:    stats.LatencyHigh,
+
+		LatencyLowThresholdMs:    stats.LatencyThresholds.LowMs,
+		LatencyMediumThresholdMs: stats.LatencyThresholds.MediumMs,
+		LatencyTierRelativeError: stats.LatencyTierRelativeError,
+		LatencyPercentiles:       toLatencyPercentilesResponse(stats.Latency),
+		LatencyByStatus:          byStatus,
+		LatencyByPaymentMethod:   byPaymentMethod,
+		FraudCheckDecisions:      stats.FraudCheckDecisions,
+		DirectDecisions:          stats.DirectDecisions,
 	}
 }
 
-// TransactionStatsController handles the transaction stats endpoint.
+// TransactionTimeseriesResponse is the API response DTO for a transaction time series.
+type TransactionTimeseriesResponse struct {
+	From     time.Time                             `json:"from"`
+	To       time.Time                             `json:"to"`
+	Interval string                                `json:"interval"`
+	GroupBy  string                                `json:"group_by,omitempty"`
+	Buckets  []TransactionTimeseriesBucketResponse `json:"buckets"`
+}
+
+// TransactionTimeseriesBucketResponse is the API response DTO for one bucket of a time series.
+type TransactionTimeseriesBucketResponse struct {
+	Start time.Time `json:"start"`
+	TransactionTimeseriesMetricsResponse
+	Groups map[string]TransactionTimeseriesMetricsResponse `json:"groups,omitempty"`
+}
+
+// TransactionTimeseriesMetricsResponse is the API response DTO for the metrics of a bucket or of one of its groups.
+type TransactionTimeseriesMetricsResponse struct {
+	Total          int              `json:"total"`
+	Approved       int              `json:"approved"`
+	Declined       int              `json:"declined"`
+	ApprovalRate   float64          `json:"approval_rate"`
+	DeclineRate    float64          `json:"decline_rate"`
+	AmountsInCents map[string]int64 `json:"amounts_in_cents"`
+	FinalizedCount int              `json:"finalized_count"`
+	LatencyP50Ms   float64          `json:"latency_p50_ms"`
+	LatencyP95Ms   float64          `json:"latency_p95_ms"`
+	LatencyP99Ms   float64          `json:"latency_p99_ms"`
+}
+
+// toTransactionTimeseriesResponse maps a domain TransactionTimeseries entity to the HTTP response DTO.
+func toTransactionTimeseriesResponse(series *entity.TransactionTimeseries) TransactionTimeseriesResponse {
+	buckets := make([]TransactionTimeseriesBucketResponse, len(series.Buckets))
+	for i, bucket := range series.Buckets {
+		buckets[i] = TransactionTimeseriesBucketResponse{
+			Start:                                bucket.Start,
+			TransactionTimeseriesMetricsResponse: toTransactionTimeseriesMetricsResponse(bucket.TransactionTimeseriesMetrics),
+		}
+		if bucket.Groups != nil {
+			buckets[i].Groups = make(map[string]TransactionTimeseriesMetricsResponse, len(bucket.Groups))
+			for key, metrics := range bucket.Groups {
+				buckets[i].Groups[key] = toTransactionTimeseriesMetricsResponse(metrics)
+			}
+		}
+	}
+
+	return TransactionTimeseriesResponse{
+		From:     series.From,
+		To:       series.To,
+		Interval: string(series.Interval),
+		GroupBy:  string(series.GroupBy),
+		Buckets:  buckets,
+	}
+}
+
+func toTransactionTimeseriesMetricsResponse(metrics entity.TransactionTimeseriesMetrics) TransactionTimeseriesMetricsResponse {
+	amounts := make(map[string]int64, len(metrics.AmountsInCents))
+	for currency, amount := range metrics.AmountsInCents {
+		amounts[string(currency)] = amount
+	}
+
+	return TransactionTimeseriesMetricsResponse{
+		Total:          metrics.Total,
+		Approved:       metrics.Approved,
+		Declined:       metrics.Declined,
+		ApprovalRate:   metrics.ApprovalRate,
+		DeclineRate:    metrics.DeclineRate,
+		AmountsInCents: amounts,
+		FinalizedCount: metrics.FinalizedCount,
+		LatencyP50Ms:   metrics.LatencyP50Ms,
+		LatencyP95Ms:   metrics.LatencyP95Ms,
+		LatencyP99Ms:   metrics.LatencyP99Ms,
+	}
+}
+
+// TransactionStatsController handles the transaction stats endpoints.
 type TransactionStatsController struct {
-	statsUseCase *usecase.GetTransactionStatsUseCase
-	logger       zerolog.Logger
+	statsUseCase      *usecase.GetTransactionStatsUseCase
+	timeseriesUseCase *usecase.GetTransactionTimeseriesUseCase
+	logger            zerolog.Logger
 }
 
 // NewTransactionStatsController creates a new TransactionStatsController.
 func NewTransactionStatsController(
 	statsUseCase *usecase.GetTransactionStatsUseCase,
+	timeseriesUseCase *usecase.GetTransactionTimeseriesUseCase,
 	logger zerolog.Logger,
 ) *TransactionStatsController {
 	return &TransactionStatsController{
-		statsUseCase: statsUseCase,
-		logger:       logger,
+		statsUseCase:      statsUseCase,
+		timeseriesUseCase: timeseriesUseCase,
+		logger:            logger,
 	}
 }
 
@@ -84,7 +205,35 @@ func (tsc *TransactionStatsController) GetStats(c *echo.Context) error {
 	return c.JSON(http.StatusOK, toTransactionStatsResponse(stats))
 }
 
+// GetTimeseries handles GET /transactions/stats/timeseries.
+func (tsc *TransactionStatsController) GetTimeseries(c *echo.Context) error {
+	series, err := tsc.timeseriesUseCase.Execute(c.Request().Context(), usecase.TransactionTimeseriesInput{
+		From:     c.QueryParam("from"),
+		To:       c.QueryParam("to"),
+		Interval: c.QueryParam("interval"),
+		GroupBy:  c.QueryParam("group_by"),
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidTimeseriesQuery) {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid timeseries parameter",
+				Details: err.Error(),
+			})
+		}
+		tsc.logger.Error().Err(err).Msg("failed to get transaction timeseries")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Details: err.Error(),
+		})
+	}
+
+	tsc.logger.Info().Int("buckets", len(series.Buckets)).Msg("transaction timeseries retrieved")
+
+	return c.JSON(http.StatusOK, toTransactionTimeseriesResponse(series))
+}
+
 // RegisterRoutes registers the transaction stats routes on the Echo instance.
 func (tsc *TransactionStatsController) RegisterRoutes(e *echo.Echo) {
 	e.GET("/transactions/stats", tsc.GetStats)
+	e.GET("/transactions/stats/timeseries", tsc.GetTimeseries)
 }
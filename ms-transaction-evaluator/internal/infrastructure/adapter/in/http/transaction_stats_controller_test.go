@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math"
 	"ms-transaction-evaluator/internal/domain/entity"
 	"ms-transaction-evaluator/internal/domain/usecase"
 	"net/http"
@@ -15,38 +16,71 @@ import (
 	"github.com/rs/zerolog"
 )
 
-// mockStatsTransactionRepository is a hand-written mock for TransactionRepository
-// used by the stats controller tests.
-type mockStatsTransactionRepository struct {
-	findAllFunc func(ctx context.Context) ([]entity.TransactionEntity, error)
+// mockTransactionStatsRepository is a hand-written mock for
+// TransactionStatsRepository used by the stats controller tests. It counts the
+// transactions returned by transactionsFunc.
+type mockTransactionStatsRepository struct {
+	transactionsFunc func() ([]entity.TransactionEntity, error)
 }
 
-func (m *mockStatsTransactionRepository) Save(_ context.Context, _ *entity.TransactionEntity) error {
-	return nil
+func (m *mockTransactionStatsRepository) counters(from, to *time.Time) (map[time.Time]entity.TransactionCounters, error) {
+	var transactions []entity.TransactionEntity
+	if m.transactionsFunc != nil {
+		var err error
+		if transactions, err = m.transactionsFunc(); err != nil {
+			return nil, err
+		}
+	}
+
+	counters := map[time.Time]entity.TransactionCounters{}
+	for i := range transactions {
+		hour := transactions[i].CreatedAt.UTC().Truncate(time.Hour)
+		if (from != nil && hour.Before(*from)) || (to != nil && hour.After(*to)) {
+			continue
+		}
+		c := counters[hour]
+		c.Add(entity.CountTransaction(&transactions[i]))
+		counters[hour] = c
+	}
+	return counters, nil
 }
 
-func (m *mockStatsTransactionRepository) UpdateStatus(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) error {
+func (m *mockTransactionStatsRepository) Add(_ context.Context, _ *entity.TransactionEntity, _ entity.TransactionCounters) error {
 	return nil
 }
 
-func (m *mockStatsTransactionRepository) FindByID(_ context.Context, _ string) (*entity.TransactionEntity, error) {
-	return nil, nil
+func (m *mockTransactionStatsRepository) FindHours(_ context.Context, from, to time.Time) ([]entity.HourlyTransactionCounters, error) {
+	counters, err := m.counters(&from, &to)
+	if err != nil {
+		return nil, err
+	}
+	hours := make([]entity.HourlyTransactionCounters, 0, len(counters))
+	for hour, c := range counters {
+		hours = append(hours, entity.HourlyTransactionCounters{Hour: hour, Counters: c})
+	}
+	return hours, nil
 }
 
-func (m *mockStatsTransactionRepository) FindAllPaginated(_ context.Context, _ int, _ string) ([]entity.TransactionEntity, string, error) {
-	return nil, "", nil
+func (m *mockTransactionStatsRepository) Total(_ context.Context) (entity.TransactionCounters, error) {
+	counters, err := m.counters(nil, nil)
+	if err != nil {
+		return entity.TransactionCounters{}, err
+	}
+	total := entity.NewTransactionCounters()
+	for _, c := range counters {
+		total.Add(c)
+	}
+	return total, nil
 }
 
-func (m *mockStatsTransactionRepository) FindAll(ctx context.Context) ([]entity.TransactionEntity, error) {
-	if m.findAllFunc != nil {
-		return m.findAllFunc(ctx)
-	}
-	return nil, nil
+func (m *mockTransactionStatsRepository) Replace(_ context.Context, _ []entity.HourlyTransactionCounters) error {
+	return nil
 }
 
-func newStatsController(repo *mockStatsTransactionRepository) (*TransactionStatsController, *echo.Echo) {
-	statsUC := usecase.NewGetTransactionStatsUseCase(repo)
-	controller := NewTransactionStatsController(statsUC, zerolog.Nop())
+func newStatsController(repo *mockTransactionStatsRepository) (*TransactionStatsController, *echo.Echo) {
+	statsUC := usecase.NewGetTransactionStatsUseCase(repo, entity.DefaultLatencyTierThresholds)
+	timeseriesUC := usecase.NewGetTransactionTimeseriesUseCase(repo)
+	controller := NewTransactionStatsController(statsUC, timeseriesUC, zerolog.Nop())
 
 	e := echo.New()
 	controller.RegisterRoutes(e)
@@ -59,8 +93,8 @@ func TestTransactionStatsController_GetStats(t *testing.T) {
 		now := time.Now().UTC()
 		finalizedAt := now.Add(3 * time.Second)
 
-		repo := &mockStatsTransactionRepository{
-			findAllFunc: func(_ context.Context) ([]entity.TransactionEntity, error) {
+		repo := &mockTransactionStatsRepository{
+			transactionsFunc: func() ([]entity.TransactionEntity, error) {
 				return []entity.TransactionEntity{
 					{
 						ID:            "txn_1",
@@ -68,6 +102,7 @@ func TestTransactionStatsController_GetStats(t *testing.T) {
 						Currency:      entity.USD,
 						PaymentMethod: entity.CARD,
 						Status:        entity.APPROVED,
+						DecisionPath:  entity.DecisionPathFraudCheck,
 						CreatedAt:     now,
 						UpdatedAt:     now,
 						FinalizedAt:   &finalizedAt,
@@ -159,12 +194,34 @@ func TestTransactionStatsController_GetStats(t *testing.T) {
 		if resp.LatencyHigh != 0 {
 			t.Errorf("expected latency_high 0, got %d", resp.LatencyHigh)
 		}
+		if resp.LatencyLowThresholdMs != 2000 || resp.LatencyMediumThresholdMs != 5000 {
+			t.Errorf("expected thresholds 2000 and 5000, got %f and %f", resp.LatencyLowThresholdMs, resp.LatencyMediumThresholdMs)
+		}
+		if resp.LatencyTierRelativeError != 0.02 {
+			t.Errorf("expected a tier error bound of 0.02, got %f", resp.LatencyTierRelativeError)
+		}
+
+		// Percentiles — the single 3000ms latency, estimated within 2%
+		if p := resp.LatencyPercentiles; p.Count != 1 || math.Abs(p.P50Ms-3000) > 60 || p.P99Ms != p.P50Ms {
+			t.Errorf("unexpected latency_percentiles: %+v", p)
+		}
+		if p, ok := resp.LatencyByStatus["APPROVED"]; !ok || p.Count != 1 || len(resp.LatencyByStatus) != 1 {
+			t.Errorf("expected APPROVED latency percentiles only, got %+v", resp.LatencyByStatus)
+		}
+		if p, ok := resp.LatencyByPaymentMethod["CARD"]; !ok || p.Count != 1 || len(resp.LatencyByPaymentMethod) != 1 {
+			t.Errorf("expected CARD latency percentiles only, got %+v", resp.LatencyByPaymentMethod)
+		}
+
+		// Decision paths — txn_1 went through a fraud check
+		if resp.FraudCheckDecisions != 1 || resp.DirectDecisions != 0 {
+			t.Errorf("expected 1 fraud-check and 0 direct decisions, got %d and %d", resp.FraudCheckDecisions, resp.DirectDecisions)
+		}
 	})
 
 	t.Run("should return 500 on database error", func(t *testing.T) {
-		repo := &mockStatsTransactionRepository{
-			findAllFunc: func(_ context.Context) ([]entity.TransactionEntity, error) {
-				return nil, errors.New("DynamoDB scan failed")
+		repo := &mockTransactionStatsRepository{
+			transactionsFunc: func() ([]entity.TransactionEntity, error) {
+				return nil, errors.New("DynamoDB query failed")
 			},
 		}
 		_, e := newStatsController(repo)
@@ -186,8 +243,86 @@ func TestTransactionStatsController_GetStats(t *testing.T) {
 			t.Errorf("expected error %q, got %q", "Internal server error", resp.Error)
 		}
 
-		if resp.Details != "DynamoDB scan failed" {
-			t.Errorf("expected details %q, got %q", "DynamoDB scan failed", resp.Details)
+		if resp.Details != "DynamoDB query failed" {
+			t.Errorf("expected details %q, got %q", "DynamoDB query failed", resp.Details)
+		}
+	})
+}
+
+func TestTransactionStatsController_GetTimeseries(t *testing.T) {
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	finalizedAt := day.Add(10*time.Hour + 2*time.Second)
+
+	repo := &mockTransactionStatsRepository{
+		transactionsFunc: func() ([]entity.TransactionEntity, error) {
+			return []entity.TransactionEntity{
+				{ID: "txn_1", AmountInCents: 10000, Currency: entity.USD, PaymentMethod: entity.CARD, Status: entity.APPROVED, CreatedAt: day.Add(10 * time.Hour), FinalizedAt: &finalizedAt},
+				{ID: "txn_2", AmountInCents: 5000, Currency: entity.EUR, PaymentMethod: entity.CRYPTO, Status: entity.DECLINED, CreatedAt: day.Add(10*time.Hour + 30*time.Minute)},
+				{ID: "txn_3", AmountInCents: 2500, Currency: entity.USD, PaymentMethod: entity.CARD, Status: entity.PENDING, CreatedAt: day.Add(36 * time.Hour)},
+			}, nil
+		},
+	}
+	_, e := newStatsController(repo)
+
+	t.Run("should return 200 with one bucket per day", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/transactions/stats/timeseries?from=2025-01-01&to=2025-01-03&interval=day&group_by=currency", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+
+		var resp TransactionTimeseriesResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+
+		if resp.Interval != "day" || resp.GroupBy != "currency" || len(resp.Buckets) != 3 {
+			t.Fatalf("expected 3 daily buckets grouped by currency, got %+v", resp)
+		}
+
+		first := resp.Buckets[0]
+		if !first.Start.Equal(day) || first.Total != 2 || first.Approved != 1 || first.Declined != 1 {
+			t.Errorf("unexpected first bucket: %+v", first)
+		}
+		if first.ApprovalRate != 0.5 || first.DeclineRate != 0.5 {
+			t.Errorf("expected approval and decline rates of 0.5, got %f and %f", first.ApprovalRate, first.DeclineRate)
+		}
+		usd, eur := first.Groups["USD"], first.Groups["EUR"]
+		if usd.Total != 1 || usd.Approved != 1 || usd.AmountsInCents["USD"] != 10000 || usd.FinalizedCount != 1 {
+			t.Errorf("unexpected USD group: %+v", usd)
+		}
+		if eur.Total != 1 || eur.DeclineRate != 1 || eur.AmountsInCents["EUR"] != 5000 || eur.FinalizedCount != 0 {
+			t.Errorf("unexpected EUR group: %+v", eur)
+		}
+		if first.AmountsInCents["USD"] != 10000 || first.AmountsInCents["EUR"] != 5000 {
+			t.Errorf("expected amounts USD 10000 and EUR 5000, got %v", first.AmountsInCents)
+		}
+		if first.FinalizedCount != 1 || math.Abs(first.LatencyP50Ms-2000) > 40 {
+			t.Errorf("expected one finalization with a p50 within 2%% of 2000, got %d and %f", first.FinalizedCount, first.LatencyP50Ms)
+		}
+
+		if resp.Buckets[1].Total != 1 || resp.Buckets[2].Total != 0 {
+			t.Errorf("expected totals 1 and 0 for the following days, got %d and %d", resp.Buckets[1].Total, resp.Buckets[2].Total)
+		}
+	})
+
+	t.Run("should return 400 for an invalid interval", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/transactions/stats/timeseries?interval=week", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+
+		var resp ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if resp.Error != "Invalid timeseries parameter" {
+			t.Errorf("expected error %q, got %q", "Invalid timeseries parameter", resp.Error)
 		}
 	})
 }
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/usecase"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// HeaderLastEventID is sent by EventSource clients when they reconnect.
+const HeaderLastEventID = "Last-Event-ID"
+
+// streamResetEvent tells the client that the events since its Last-Event-ID
+// could not be replayed, so it should reload the transactions it displays.
+const streamResetEvent = "reset"
+
+// defaultStreamHeartbeat is how often a comment is sent on an idle stream so
+// proxies do not close it.
+const defaultStreamHeartbeat = 15 * time.Second
+
+// TransactionStreamController handles the Server-Sent Events stream of
+// transaction events.
+type TransactionStreamController struct {
+	stream    *usecase.TransactionStream
+	heartbeat time.Duration
+	logger    zerolog.Logger
+}
+
+// NewTransactionStreamController creates a new TransactionStreamController.
+func NewTransactionStreamController(stream *usecase.TransactionStream, logger zerolog.Logger) *TransactionStreamController {
+	return &TransactionStreamController{
+		stream:    stream,
+		heartbeat: defaultStreamHeartbeat,
+		logger:    logger,
+	}
+}
+
+// StreamTransactions godoc
+// @Summary Stream transaction events
+// @Description Server-Sent Events stream of transaction.created and transaction.finalized events. Reconnecting with Last-Event-ID replays the missed events; a reset event is sent first when they cannot be replayed.
+// @Tags transactions
+// @Produce text/event-stream
+// @Param status query string false "Comma-separated statuses to receive"
+// @Param payment_method query string false "Comma-separated payment methods to receive"
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Param last_event_id query string false "ID of the last event received, for clients that cannot set headers"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} ErrorResponse "Invalid stream filter"
+// @Router /transactions/stream [get]
+func (sc *TransactionStreamController) StreamTransactions(c *echo.Context) error {
+	filter, err := usecase.ParseTransactionStreamFilter(c.QueryParam("status"), c.QueryParam("payment_method"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidStreamFilter) {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid stream filter",
+				Details: err.Error(),
+			})
+		}
+		return err
+	}
+
+	lastEventID := c.Request().Header.Get(HeaderLastEventID)
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("last_event_id")
+	}
+
+	sub := sc.stream.Subscribe(filter, lastEventID)
+	defer sub.Close()
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	if !sub.Resumed {
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: {}\n\n", streamResetEvent); err != nil {
+			return nil
+		}
+	}
+	for _, event := range sub.Replay {
+		if err := writeTransactionEvent(w, event); err != nil {
+			return nil
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return nil
+	}
+
+	sc.logger.Debug().
+		Str("last_event_id", lastEventID).
+		Int("replayed", len(sub.Replay)).
+		Msg("transaction stream opened")
+
+	heartbeat := time.NewTicker(sc.heartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Dropped for falling behind; the client reconnects with its Last-Event-ID
+				sc.logger.Info().Msg("closing slow transaction stream")
+				return nil
+			}
+			if err := writeTransactionEvent(w, event); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return nil
+		}
+	}
+}
+
+// writeTransactionEvent writes event in the Server-Sent Events format.
+func writeTransactionEvent(w io.Writer, event entity.TransactionEvent) error {
+	data, err := json.Marshal(toTransactionResponse(event.Transaction))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// RegisterRoutes registers the transaction stream route on the Echo instance.
+func (sc *TransactionStreamController) RegisterRoutes(e *echo.Echo) {
+	e.GET("/transactions/stream", sc.StreamTransactions)
+}
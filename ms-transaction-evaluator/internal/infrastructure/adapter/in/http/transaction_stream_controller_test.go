@@ -0,0 +1,185 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/usecase"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// sseEvent is one event read from a Server-Sent Events stream.
+type sseEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+// openTransactionStream starts a server for the controller and opens the stream
+// at target. The returned function closes the connection and the server.
+func openTransactionStream(t *testing.T, controller *TransactionStreamController, target string, header http.Header) (*http.Response, *bufio.Reader, func()) {
+	t.Helper()
+
+	e := echo.New()
+	controller.RegisterRoutes(e)
+	server := httptest.NewServer(e)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+target, nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+
+	return resp, bufio.NewReader(resp.Body), func() {
+		cancel()
+		resp.Body.Close()
+		server.Close()
+	}
+}
+
+// readSSEEvent reads the next event, skipping comments.
+func readSSEEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+
+	var ev sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if ev.event != "" {
+				return ev
+			}
+		case strings.HasPrefix(line, "id: "):
+			ev.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			ev.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			ev.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// waitForSubscribers waits until the stream has n subscribers.
+func waitForSubscribers(t *testing.T, stream *usecase.TransactionStream, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for stream.Len() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d subscribers, got %d", n, stream.Len())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTransactionStreamController_StreamTransactions(t *testing.T) {
+	t.Run("streams the matching events", func(t *testing.T) {
+		stream := usecase.NewTransactionStream(&mockQueryTransactionRepository{}, 0, 0, zerolog.Nop())
+		resp, r, closeStream := openTransactionStream(t, NewTransactionStreamController(stream, zerolog.Nop()), "/transactions/stream?payment_method=card", nil)
+		defer closeStream()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", resp.StatusCode)
+		}
+		if ct := resp.Header.Get(echo.HeaderContentType); ct != "text/event-stream" {
+			t.Errorf("expected text/event-stream, got %q", ct)
+		}
+
+		waitForSubscribers(t, stream, 1)
+		stream.PublishCreated(&entity.TransactionEntity{ID: "tx-1", PaymentMethod: entity.CRYPTO, Status: entity.PENDING})
+		stream.PublishCreated(&entity.TransactionEntity{ID: "tx-2", PaymentMethod: entity.CARD, Status: entity.PENDING})
+
+		ev := readSSEEvent(t, r)
+		if ev.event != string(entity.TransactionEventCreated) || ev.id == "" {
+			t.Errorf("expected a transaction.created event with an ID, got %+v", ev)
+		}
+		var data TransactionResponse
+		if err := json.Unmarshal([]byte(ev.data), &data); err != nil {
+			t.Fatalf("failed to decode event data: %v", err)
+		}
+		if data.ID != "tx-2" || data.PaymentMethod != entity.CARD {
+			t.Errorf("expected tx-2, got %+v", data)
+		}
+	})
+
+	t.Run("replays the events after Last-Event-ID", func(t *testing.T) {
+		stream := usecase.NewTransactionStream(&mockQueryTransactionRepository{}, 0, 0, zerolog.Nop())
+		controller := NewTransactionStreamController(stream, zerolog.Nop())
+
+		_, r, closeFirst := openTransactionStream(t, controller, "/transactions/stream", nil)
+		waitForSubscribers(t, stream, 1)
+		stream.PublishCreated(&entity.TransactionEntity{ID: "tx-1", Status: entity.PENDING})
+		lastEventID := readSSEEvent(t, r).id
+		closeFirst()
+		waitForSubscribers(t, stream, 0)
+
+		stream.PublishCreated(&entity.TransactionEntity{ID: "tx-2", Status: entity.PENDING})
+
+		_, r, closeSecond := openTransactionStream(t, controller, "/transactions/stream", http.Header{HeaderLastEventID: {lastEventID}})
+		defer closeSecond()
+
+		ev := readSSEEvent(t, r)
+		if ev.event != string(entity.TransactionEventCreated) || !strings.Contains(ev.data, `"id":"tx-2"`) {
+			t.Errorf("expected tx-2 to be replayed, got %+v", ev)
+		}
+	})
+
+	t.Run("sends a reset when the events cannot be replayed", func(t *testing.T) {
+		stream := usecase.NewTransactionStream(&mockQueryTransactionRepository{}, 0, 0, zerolog.Nop())
+		_, r, closeStream := openTransactionStream(t, NewTransactionStreamController(stream, zerolog.Nop()), "/transactions/stream?last_event_id=unknown-1", nil)
+		defer closeStream()
+
+		if ev := readSSEEvent(t, r); ev.event != streamResetEvent {
+			t.Errorf("expected a reset event, got %+v", ev)
+		}
+	})
+
+	t.Run("sends heartbeats on an idle stream", func(t *testing.T) {
+		stream := usecase.NewTransactionStream(&mockQueryTransactionRepository{}, 0, 0, zerolog.Nop())
+		controller := NewTransactionStreamController(stream, zerolog.Nop())
+		controller.heartbeat = 10 * time.Millisecond
+		_, r, closeStream := openTransactionStream(t, controller, "/transactions/stream", nil)
+		defer closeStream()
+
+		line, err := r.ReadString('\n')
+		if err != nil || line != ": keep-alive\n" {
+			t.Errorf("expected a keep-alive comment, got %q %v", line, err)
+		}
+	})
+
+	t.Run("rejects an invalid filter", func(t *testing.T) {
+		e := echo.New()
+		NewTransactionStreamController(usecase.NewTransactionStream(&mockQueryTransactionRepository{}, 0, 0, zerolog.Nop()), zerolog.Nop()).RegisterRoutes(e)
+
+		req := httptest.NewRequest(http.MethodGet, "/transactions/stream?status=SETTLED", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d", rec.Code)
+		}
+		var body ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error != "Invalid stream filter" {
+			t.Errorf("unexpected body %s", rec.Body.String())
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package http
+
+import (
+	"errors"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+// RegisterWebhookRequest is the request body for registering a webhook endpoint.
+// Without merchant_id the endpoint receives the decisions of every transaction;
+// without secret one is generated.
+type RegisterWebhookRequest struct {
+	URL        string `json:"url" example:"https://merchant.example.com/webhooks"`
+	MerchantID string `json:"merchant_id" example:"merchant_42"`
+	Secret     string `json:"secret"`
+}
+
+// ListWebhookDeliveriesResponse represents the response for GET /webhooks/deliveries.
+type ListWebhookDeliveriesResponse struct {
+	Data       []entity.WebhookDelivery `json:"data"`
+	NextCursor string                   `json:"next_cursor"`
+}
+
+// WebhookController handles the HTTP endpoints managing webhook endpoints and
+// their delivery log.
+type WebhookController struct {
+	registerUseCase       *usecase.RegisterWebhookEndpointUseCase
+	listEndpointsUseCase  *usecase.ListWebhookEndpointsUseCase
+	deleteUseCase         *usecase.DeleteWebhookEndpointUseCase
+	listDeliveriesUseCase *usecase.ListWebhookDeliveriesUseCase
+	getDeliveryUseCase    *usecase.GetWebhookDeliveryUseCase
+	replayDeliveryUseCase *usecase.ReplayWebhookDeliveryUseCase
+	logger                zerolog.Logger
+}
+
+// NewWebhookController creates a new WebhookController.
+func NewWebhookController(
+	registerUseCase *usecase.RegisterWebhookEndpointUseCase,
+	listEndpointsUseCase *usecase.ListWebhookEndpointsUseCase,
+	deleteUseCase *usecase.DeleteWebhookEndpointUseCase,
+	listDeliveriesUseCase *usecase.ListWebhookDeliveriesUseCase,
+	getDeliveryUseCase *usecase.GetWebhookDeliveryUseCase,
+	replayDeliveryUseCase *usecase.ReplayWebhookDeliveryUseCase,
+	logger zerolog.Logger,
+) *WebhookController {
+	return &WebhookController{
+		registerUseCase:       registerUseCase,
+		listEndpointsUseCase:  listEndpointsUseCase,
+		deleteUseCase:         deleteUseCase,
+		listDeliveriesUseCase: listDeliveriesUseCase,
+		getDeliveryUseCase:    getDeliveryUseCase,
+		replayDeliveryUseCase: replayDeliveryUseCase,
+		logger:                logger,
+	}
+}
+
+// RegisterEndpoint handles POST /webhooks. The response is the only one carrying
+// the endpoint's secret.
+func (wc *WebhookController) RegisterEndpoint(c *echo.Context) error {
+	var req RegisterWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		wc.logger.Warn().Err(err).Msg("failed to bind webhook request")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	endpoint, err := wc.registerUseCase.Execute(c.Request().Context(), usecase.RegisterWebhookEndpointRequest{
+		URL:        req.URL,
+		MerchantID: req.MerchantID,
+		Secret:     req.Secret,
+	})
+	if err != nil {
+		return wc.handleError(c, err)
+	}
+
+	wc.logger.Info().
+		Str("endpoint_id", endpoint.ID).
+		Str("url", endpoint.URL).
+		Str("merchant_id", endpoint.MerchantID).
+		Msg("webhook endpoint registered")
+
+	return c.JSON(http.StatusCreated, DataResponse{Data: endpoint})
+}
+
+// ListEndpoints handles GET /webhooks.
+func (wc *WebhookController) ListEndpoints(c *echo.Context) error {
+	endpoints, err := wc.listEndpointsUseCase.Execute(c.Request().Context())
+	if err != nil {
+		return wc.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DataResponse{Data: endpoints})
+}
+
+// DeleteEndpoint handles DELETE /webhooks/:id.
+func (wc *WebhookController) DeleteEndpoint(c *echo.Context) error {
+	id := c.Param("id")
+	if err := wc.deleteUseCase.Execute(c.Request().Context(), id); err != nil {
+		return wc.handleError(c, err)
+	}
+
+	wc.logger.Info().Str("endpoint_id", id).Msg("webhook endpoint deleted")
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /webhooks/deliveries. The endpoint_id,
+// transaction_id and status query parameters filter the log; limit defaults to 20.
+func (wc *WebhookController) ListDeliveries(c *echo.Context) error {
+	limit := defaultLimit
+	if raw := c.QueryParam("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			wc.logger.Warn().Str("limit", raw).Msg("invalid limit parameter")
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid limit parameter",
+				Details: "limit must be a positive integer between 1 and 100",
+			})
+		}
+		limit = parsed
+	}
+
+	filter := entity.WebhookDeliveryFilter{
+		EndpointID:    c.QueryParam("endpoint_id"),
+		TransactionID: c.QueryParam("transaction_id"),
+		Status:        entity.WebhookDeliveryStatus(c.QueryParam("status")),
+	}
+
+	deliveries, nextCursor, err := wc.listDeliveriesUseCase.Execute(c.Request().Context(), filter, limit, c.QueryParam("cursor"))
+	if err != nil {
+		return wc.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, ListWebhookDeliveriesResponse{
+		Data:       deliveries,
+		NextCursor: nextCursor,
+	})
+}
+
+// GetDelivery handles GET /webhooks/deliveries/:id.
+func (wc *WebhookController) GetDelivery(c *echo.Context) error {
+	delivery, err := wc.getDeliveryUseCase.Execute(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return wc.handleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, DataResponse{Data: delivery})
+}
+
+// ReplayDelivery handles POST /webhooks/deliveries/:id/replay. The delivery is
+// sent again by the deliverer with a fresh set of attempts.
+func (wc *WebhookController) ReplayDelivery(c *echo.Context) error {
+	delivery, err := wc.replayDeliveryUseCase.Execute(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return wc.handleError(c, err)
+	}
+
+	wc.logger.Info().
+		Str("delivery_id", delivery.ID).
+		Str("transaction_id", delivery.TransactionID).
+		Msg("webhook delivery replayed")
+
+	return c.JSON(http.StatusAccepted, DataResponse{Data: delivery})
+}
+
+// handleError maps webhook use case errors to HTTP responses.
+func (wc *WebhookController) handleError(c *echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrWebhookURLInvalid),
+		errors.Is(err, usecase.ErrWebhookSecretTooShort),
+		errors.Is(err, usecase.ErrMerchantIDTooLong),
+		errors.Is(err, usecase.ErrWebhookDeliveryStatusInvalid),
+		errors.Is(err, usecase.ErrInvalidLimit),
+		errors.Is(err, usecase.ErrInvalidCursor):
+		wc.logger.Warn().Err(err).Msg("invalid webhook request")
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Details: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrWebhookEndpointNotFound):
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Webhook endpoint not found",
+			Details: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrWebhookDeliveryNotFound):
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Webhook delivery not found",
+			Details: err.Error(),
+		})
+	default:
+		wc.logger.Error().Err(err).Msg("failed to manage webhooks")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
+
+// RegisterRoutes registers the webhook routes on the Echo instance.
+func (wc *WebhookController) RegisterRoutes(e *echo.Echo) {
+	e.POST("/webhooks", wc.RegisterEndpoint)
+	e.GET("/webhooks", wc.ListEndpoints)
+	e.DELETE("/webhooks/:id", wc.DeleteEndpoint)
+	e.GET("/webhooks/deliveries", wc.ListDeliveries)
+	e.GET("/webhooks/deliveries/:id", wc.GetDelivery)
+	e.POST("/webhooks/deliveries/:id/replay", wc.ReplayDelivery)
+}
@@ -0,0 +1,217 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/usecase"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+)
+
+type mockWebhookEndpointRepository struct {
+	endpoints []entity.WebhookEndpoint
+}
+
+func (m *mockWebhookEndpointRepository) Save(_ context.Context, endpoint *entity.WebhookEndpoint) error {
+	m.endpoints = append(m.endpoints, *endpoint)
+	return nil
+}
+
+func (m *mockWebhookEndpointRepository) FindAll(_ context.Context) ([]entity.WebhookEndpoint, error) {
+	return append([]entity.WebhookEndpoint(nil), m.endpoints...), nil
+}
+
+func (m *mockWebhookEndpointRepository) Delete(_ context.Context, id string) (bool, error) {
+	for i := range m.endpoints {
+		if m.endpoints[i].ID == id {
+			m.endpoints = append(m.endpoints[:i], m.endpoints[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type mockWebhookDeliveryRepository struct {
+	deliveries []entity.WebhookDelivery
+	filter     entity.WebhookDeliveryFilter
+}
+
+func (m *mockWebhookDeliveryRepository) Create(_ context.Context, delivery *entity.WebhookDelivery) (bool, error) {
+	m.deliveries = append(m.deliveries, *delivery)
+	return true, nil
+}
+
+func (m *mockWebhookDeliveryRepository) Update(_ context.Context, delivery *entity.WebhookDelivery) error {
+	for i := range m.deliveries {
+		if m.deliveries[i].ID == delivery.ID {
+			m.deliveries[i] = *delivery
+		}
+	}
+	return nil
+}
+
+func (m *mockWebhookDeliveryRepository) FindByID(_ context.Context, id string) (*entity.WebhookDelivery, error) {
+	for i := range m.deliveries {
+		if m.deliveries[i].ID == id {
+			d := m.deliveries[i]
+			return &d, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockWebhookDeliveryRepository) FindDue(_ context.Context, _ time.Time, _ int) ([]entity.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (m *mockWebhookDeliveryRepository) FindAll(_ context.Context, filter entity.WebhookDeliveryFilter, _ int, cursor string) ([]entity.WebhookDelivery, string, error) {
+	if cursor == "bad" {
+		return nil, "", entity.ErrCursorMalformed
+	}
+	m.filter = filter
+	return m.deliveries, "next", nil
+}
+
+func serveWebhookRequest(e *echo.Echo, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func newWebhookController(endpoints *mockWebhookEndpointRepository, deliveries *mockWebhookDeliveryRepository) *echo.Echo {
+	controller := NewWebhookController(
+		usecase.NewRegisterWebhookEndpointUseCase(endpoints),
+		usecase.NewListWebhookEndpointsUseCase(endpoints),
+		usecase.NewDeleteWebhookEndpointUseCase(endpoints),
+		usecase.NewListWebhookDeliveriesUseCase(deliveries),
+		usecase.NewGetWebhookDeliveryUseCase(deliveries),
+		usecase.NewReplayWebhookDeliveryUseCase(deliveries),
+		zerolog.Nop(),
+	)
+
+	e := echo.New()
+	controller.RegisterRoutes(e)
+
+	return e
+}
+
+func TestWebhookController_Endpoints(t *testing.T) {
+	endpoints := &mockWebhookEndpointRepository{}
+	e := newWebhookController(endpoints, &mockWebhookDeliveryRepository{})
+
+	rec := serveWebhookRequest(e, http.MethodPost, "/webhooks", `{"url":"https://example.com/hooks","merchant_id":"merchant_42"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created struct {
+		Data entity.WebhookEndpoint `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.Data.ID == "" || created.Data.Secret == "" || created.Data.MerchantID != "merchant_42" {
+		t.Errorf("expected the endpoint with its secret, got %+v", created.Data)
+	}
+
+	rec = serveWebhookRequest(e, http.MethodGet, "/webhooks", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var listed struct {
+		Data []entity.WebhookEndpoint `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(listed.Data) != 1 || listed.Data[0].Secret != "" {
+		t.Errorf("expected one endpoint without its secret, got %+v", listed.Data)
+	}
+
+	rec = serveWebhookRequest(e, http.MethodDelete, "/webhooks/"+created.Data.ID, "")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(endpoints.endpoints) != 0 {
+		t.Errorf("expected the endpoint to be deleted")
+	}
+}
+
+func TestWebhookController_Deliveries(t *testing.T) {
+	deliveries := &mockWebhookDeliveryRepository{deliveries: []entity.WebhookDelivery{{
+		ID:            "whd_001",
+		TransactionID: "tx-1",
+		Status:        entity.WebhookDeliveryFailed,
+		Attempts:      10,
+		Log:           []entity.WebhookAttempt{{StatusCode: 500}},
+	}}}
+	e := newWebhookController(&mockWebhookEndpointRepository{}, deliveries)
+
+	rec := serveWebhookRequest(e, http.MethodGet, "/webhooks/deliveries?transaction_id=tx-1&status=FAILED", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var listed ListWebhookDeliveriesResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(listed.Data) != 1 || listed.NextCursor != "next" {
+		t.Errorf("unexpected response %+v", listed)
+	}
+	if deliveries.filter.TransactionID != "tx-1" || deliveries.filter.Status != entity.WebhookDeliveryFailed {
+		t.Errorf("expected the filter to be passed, got %+v", deliveries.filter)
+	}
+
+	rec = serveWebhookRequest(e, http.MethodGet, "/webhooks/deliveries/whd_001", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serveWebhookRequest(e, http.MethodPost, "/webhooks/deliveries/whd_001/replay", "")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if d := deliveries.deliveries[0]; d.Status != entity.WebhookDeliveryPending || d.Attempts != 0 || len(d.Log) != 1 {
+		t.Errorf("expected the delivery to be pending again with its log, got %+v", d)
+	}
+}
+
+func TestWebhookController_Errors(t *testing.T) {
+	e := newWebhookController(&mockWebhookEndpointRepository{}, &mockWebhookDeliveryRepository{})
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		expected int
+	}{
+		{"invalid url", http.MethodPost, "/webhooks", `{"url":"not a url"}`, http.StatusBadRequest},
+		{"short secret", http.MethodPost, "/webhooks", `{"url":"https://example.com","secret":"short"}`, http.StatusBadRequest},
+		{"malformed body", http.MethodPost, "/webhooks", `{`, http.StatusBadRequest},
+		{"unknown endpoint", http.MethodDelete, "/webhooks/missing", "", http.StatusNotFound},
+		{"invalid status", http.MethodGet, "/webhooks/deliveries?status=LOST", "", http.StatusBadRequest},
+		{"invalid limit", http.MethodGet, "/webhooks/deliveries?limit=x", "", http.StatusBadRequest},
+		{"limit too high", http.MethodGet, "/webhooks/deliveries?limit=500", "", http.StatusBadRequest},
+		{"invalid cursor", http.MethodGet, "/webhooks/deliveries?cursor=bad", "", http.StatusBadRequest},
+		{"unknown delivery", http.MethodGet, "/webhooks/deliveries/missing", "", http.StatusNotFound},
+		{"replay unknown delivery", http.MethodPost, "/webhooks/deliveries/missing/replay", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveWebhookRequest(e, tt.method, tt.target, tt.body)
+			if rec.Code != tt.expected {
+				t.Errorf("expected status %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -3,9 +3,12 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"math/rand/v2"
 	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/repository"
 	"ms-transaction-evaluator/internal/domain/usecase"
+	"ms-transaction-evaluator/internal/infrastructure/telemetry"
 	"time"
 
 	"github.com/IBM/sarama"
@@ -15,15 +18,21 @@ import (
 // DecisionConsumer implements sarama.ConsumerGroupHandler for the Decision.Calculated topic.
 type DecisionConsumer struct {
 	useCase       *usecase.UpdateTransactionStatusUseCase
+	deadLetters   repository.DeadLetterPublisher
 	logger        zerolog.Logger
 	maxDelayMs    int
 	minDelayMs    int
 }
 
+// deadLetterRetryInterval is the wait between failed attempts to publish a message
+// to the dead-letter topic.
+const deadLetterRetryInterval = time.Second
+
 // NewDecisionConsumer creates a new consumer for decision results.
+// Messages that cannot be processed are published to deadLetters, which may be nil.
 // minDelayMs and maxDelayMs control an artificial processing delay (0 = disabled).
-func NewDecisionConsumer(uc *usecase.UpdateTransactionStatusUseCase, logger zerolog.Logger, minDelayMs, maxDelayMs int) *DecisionConsumer {
-	return &DecisionConsumer{useCase: uc, logger: logger, minDelayMs: minDelayMs, maxDelayMs: maxDelayMs}
+func NewDecisionConsumer(uc *usecase.UpdateTransactionStatusUseCase, deadLetters repository.DeadLetterPublisher, logger zerolog.Logger, minDelayMs, maxDelayMs int) *DecisionConsumer {
+	return &DecisionConsumer{useCase: uc, deadLetters: deadLetters, logger: logger, minDelayMs: minDelayMs, maxDelayMs: maxDelayMs}
 }
 
 func (c *DecisionConsumer) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
@@ -44,6 +53,9 @@ func (c *DecisionConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, cla
 			c.logger.Error().Err(err).
 				Str("raw", string(msg.Value)).
 				Msg("failed to unmarshal decision message")
+			if !c.deadLetter(session.Context(), msg, fmt.Errorf("failed to deserialize message: %w", err)) {
+				return nil
+			}
 			session.MarkMessage(msg, "")
 			continue
 		}
@@ -67,9 +79,73 @@ func (c *DecisionConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, cla
 			c.logger.Error().Err(err).
 				Str("transaction_id", decision.TransactionID).
 				Msg("failed to update transaction status")
+			if !c.deadLetter(session.Context(), msg, err) {
+				return nil
+			}
 		}
 
 		session.MarkMessage(msg, "")
 	}
 	return nil
 }
+
+// deadLetter publishes the message to the dead-letter topic, retrying until it
+// succeeds. It returns false when ctx ends first; the message must then be left
+// uncommitted so that it is redelivered. Without a dead-letter publisher the
+// message is dropped.
+func (c *DecisionConsumer) deadLetter(ctx context.Context, msg *sarama.ConsumerMessage, cause error) bool {
+	if c.deadLetters == nil {
+		return true
+	}
+
+	deadLetter := &entity.DeadLetter{
+		SourceTopic:     msg.Topic,
+		SourcePartition: msg.Partition,
+		SourceOffset:    msg.Offset,
+		Key:             string(msg.Key),
+		Payload:         string(msg.Value),
+		Headers:         toHeaderMap(msg.Headers),
+		Error:           cause.Error(),
+		Attempts:        1,
+		FailedAt:        time.Now().UTC(),
+	}
+
+	for {
+		err := c.deadLetters.Publish(ctx, deadLetter)
+		if err == nil {
+			telemetry.KafkaDeadLetters.WithLabelValues(msg.Topic).Inc()
+			return true
+		}
+
+		c.logger.Error().Err(err).
+			Str("topic", msg.Topic).
+			Int32("partition", msg.Partition).
+			Int64("offset", msg.Offset).
+			Msg("failed to publish decision message to dead-letter topic")
+
+		select {
+		case <-ctx.Done():
+			c.logger.Info().
+				Str("topic", msg.Topic).
+				Int64("offset", msg.Offset).
+				Msg("session ended before message was dead-lettered, leaving it uncommitted")
+			return false
+		case <-time.After(deadLetterRetryInterval):
+		}
+	}
+}
+
+func toHeaderMap(headers []*sarama.RecordHeader) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(headers))
+	for _, header := range headers {
+		if header == nil {
+			continue
+		}
+		values[string(header.Key)] = string(header.Value)
+	}
+	return values
+}
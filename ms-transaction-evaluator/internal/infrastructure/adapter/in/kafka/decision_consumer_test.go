@@ -0,0 +1,252 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"ms-transaction-evaluator/internal/domain/usecase"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rs/zerolog"
+)
+
+type mockTransactionRepository struct {
+	updateStatusFunc func(ctx context.Context, id string, status entity.TransactionStatus, finalizedAt *time.Time) (*entity.TransactionEntity, error)
+}
+
+func (m *mockTransactionRepository) Save(_ context.Context, _ *entity.TransactionEntity) error {
+	return nil
+}
+
+func (m *mockTransactionRepository) UpdateStatus(ctx context.Context, id string, status entity.TransactionStatus, finalizedAt *time.Time, _ entity.DecisionPath) (*entity.TransactionEntity, error) {
+	if m.updateStatusFunc != nil {
+		return m.updateStatusFunc(ctx, id, status, finalizedAt)
+	}
+	return &entity.TransactionEntity{Status: entity.PENDING}, nil
+}
+
+func (m *mockTransactionRepository) FindByID(_ context.Context, id string) (*entity.TransactionEntity, error) {
+	return &entity.TransactionEntity{ID: id, CreatedAt: time.Now().UTC()}, nil
+}
+
+func (m *mockTransactionRepository) FindAllPaginated(_ context.Context, _ entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
+	return nil, "", nil
+}
+
+func (m *mockTransactionRepository) FindAll(_ context.Context) ([]entity.TransactionEntity, error) {
+	return nil, nil
+}
+
+type mockDeadLetterPublisher struct {
+	publishFunc func(ctx context.Context, deadLetter *entity.DeadLetter) error
+	published   []*entity.DeadLetter
+}
+
+func (m *mockDeadLetterPublisher) Publish(ctx context.Context, deadLetter *entity.DeadLetter) error {
+	if m.publishFunc != nil {
+		if err := m.publishFunc(ctx, deadLetter); err != nil {
+			return err
+		}
+	}
+	m.published = append(m.published, deadLetter)
+	return nil
+}
+
+type mockConsumerGroupSession struct {
+	ctx            context.Context
+	markedMessages []*sarama.ConsumerMessage
+}
+
+func (m *mockConsumerGroupSession) Claims() map[string][]int32               { return nil }
+func (m *mockConsumerGroupSession) MemberID() string                         { return "test-member" }
+func (m *mockConsumerGroupSession) GenerationID() int32                      { return 1 }
+func (m *mockConsumerGroupSession) MarkOffset(string, int32, int64, string)  {}
+func (m *mockConsumerGroupSession) Commit()                                  {}
+func (m *mockConsumerGroupSession) ResetOffset(string, int32, int64, string) {}
+
+func (m *mockConsumerGroupSession) Context() context.Context {
+	if m.ctx != nil {
+		return m.ctx
+	}
+	return context.Background()
+}
+
+func (m *mockConsumerGroupSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	m.markedMessages = append(m.markedMessages, msg)
+}
+
+type mockConsumerGroupClaim struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (m *mockConsumerGroupClaim) Topic() string                            { return "Decision.Calculated" }
+func (m *mockConsumerGroupClaim) Partition() int32                         { return 0 }
+func (m *mockConsumerGroupClaim) InitialOffset() int64                     { return 0 }
+func (m *mockConsumerGroupClaim) HighWaterMarkOffset() int64               { return 0 }
+func (m *mockConsumerGroupClaim) Messages() <-chan *sarama.ConsumerMessage { return m.messages }
+
+func consumeOne(consumer *DecisionConsumer, session *mockConsumerGroupSession, msg *sarama.ConsumerMessage) error {
+	msgChan := make(chan *sarama.ConsumerMessage, 1)
+	msgChan <- msg
+	close(msgChan)
+	return consumer.ConsumeClaim(session, &mockConsumerGroupClaim{messages: msgChan})
+}
+
+func TestDecisionConsumer_ValidMessage(t *testing.T) {
+	deadLetters := &mockDeadLetterPublisher{}
+	uc := usecase.NewUpdateTransactionStatusUseCase(&mockTransactionRepository{}, nil)
+	consumer := NewDecisionConsumer(uc, deadLetters, zerolog.Nop(), 0, 0)
+	session := &mockConsumerGroupSession{}
+
+	err := consumeOne(consumer, session, &sarama.ConsumerMessage{Value: []byte(`{"transaction_id":"tx-1","status":"APPROVED"}`)})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(session.markedMessages) != 1 {
+		t.Fatalf("expected 1 marked message, got %d", len(session.markedMessages))
+	}
+	if len(deadLetters.published) != 0 {
+		t.Fatalf("expected no dead letters, got %d", len(deadLetters.published))
+	}
+}
+
+func TestDecisionConsumer_FailedMessagesAreDeadLettered(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		updateErr     error
+		expectedError string
+	}{
+		{"malformed JSON", "not json", nil, "failed to deserialize message"},
+		{"invalid status", `{"transaction_id":"tx-1","status":"UNKNOWN"}`, nil, usecase.ErrInvalidStatus.Error()},
+		{"update failure", `{"transaction_id":"tx-1","status":"DECLINED"}`, errors.New("throttled"), usecase.ErrStatusUpdateFailed.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockTransactionRepository{
+				updateStatusFunc: func(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) (*entity.TransactionEntity, error) {
+					if tt.updateErr != nil {
+						return nil, tt.updateErr
+					}
+					return &entity.TransactionEntity{Status: entity.PENDING}, nil
+				},
+			}
+			deadLetters := &mockDeadLetterPublisher{}
+			consumer := NewDecisionConsumer(usecase.NewUpdateTransactionStatusUseCase(repo, nil), deadLetters, zerolog.Nop(), 0, 0)
+			session := &mockConsumerGroupSession{}
+
+			msg := &sarama.ConsumerMessage{Topic: "Decision.Calculated", Partition: 2, Offset: 11, Key: []byte("tx-1"), Value: []byte(tt.value)}
+			if err := consumeOne(consumer, session, msg); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if len(session.markedMessages) != 1 {
+				t.Fatalf("expected 1 marked message, got %d", len(session.markedMessages))
+			}
+			if len(deadLetters.published) != 1 {
+				t.Fatalf("expected 1 dead letter, got %d", len(deadLetters.published))
+			}
+
+			deadLetter := deadLetters.published[0]
+			if !strings.Contains(deadLetter.Error, tt.expectedError) {
+				t.Errorf("expected error containing %q, got %q", tt.expectedError, deadLetter.Error)
+			}
+			if deadLetter.SourceTopic != "Decision.Calculated" || deadLetter.SourcePartition != 2 || deadLetter.SourceOffset != 11 {
+				t.Errorf("unexpected source %s[%d]@%d", deadLetter.SourceTopic, deadLetter.SourcePartition, deadLetter.SourceOffset)
+			}
+			if deadLetter.Payload != tt.value || deadLetter.Key != "tx-1" {
+				t.Errorf("expected the original message, got key %q payload %q", deadLetter.Key, deadLetter.Payload)
+			}
+		})
+	}
+}
+
+func TestDecisionConsumer_SessionEndsBeforeDeadLetterPublished(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	deadLetters := &mockDeadLetterPublisher{
+		publishFunc: func(_ context.Context, _ *entity.DeadLetter) error {
+			cancel()
+			return errors.New("broker unavailable")
+		},
+	}
+	consumer := NewDecisionConsumer(usecase.NewUpdateTransactionStatusUseCase(&mockTransactionRepository{}, nil), deadLetters, zerolog.Nop(), 0, 0)
+	session := &mockConsumerGroupSession{ctx: ctx}
+
+	if err := consumeOne(consumer, session, &sarama.ConsumerMessage{Value: []byte("not json")}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(session.markedMessages) != 0 {
+		t.Fatalf("expected the message to stay uncommitted, got %d marked", len(session.markedMessages))
+	}
+}
+
+func TestDecisionConsumer_NotifiesWaiters(t *testing.T) {
+	waiters := usecase.NewDecisionWaiters()
+	decisions, unregister := waiters.Register("tx-1")
+	defer unregister()
+
+	consumer := NewDecisionConsumer(usecase.NewUpdateTransactionStatusUseCase(&mockTransactionRepository{}, usecase.NewTransactionFinalizer(nil, nil, waiters, nil)), nil, zerolog.Nop(), 0, 0)
+	session := &mockConsumerGroupSession{}
+
+	if err := consumeOne(consumer, session, &sarama.ConsumerMessage{Value: []byte(`{"transaction_id":"tx-1","status":"DECLINED"}`)}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	select {
+	case status := <-decisions:
+		if status != entity.DECLINED {
+			t.Errorf("expected DECLINED, got %s", status)
+		}
+	default:
+		t.Fatal("expected the waiter to be notified")
+	}
+}
+
+func TestDecisionConsumer_FailedUpdateDoesNotNotifyWaiters(t *testing.T) {
+	waiters := usecase.NewDecisionWaiters()
+	decisions, unregister := waiters.Register("tx-1")
+	defer unregister()
+
+	repo := &mockTransactionRepository{
+		updateStatusFunc: func(_ context.Context, _ string, _ entity.TransactionStatus, _ *time.Time) (*entity.TransactionEntity, error) {
+			return nil, errors.New("throttled")
+		},
+	}
+	consumer := NewDecisionConsumer(usecase.NewUpdateTransactionStatusUseCase(repo, usecase.NewTransactionFinalizer(nil, nil, waiters, nil)), nil, zerolog.Nop(), 0, 0)
+
+	if err := consumeOne(consumer, &mockConsumerGroupSession{}, &sarama.ConsumerMessage{Value: []byte(`{"transaction_id":"tx-1","status":"APPROVED"}`)}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	select {
+	case status := <-decisions:
+		t.Fatalf("expected no notification, got %s", status)
+	default:
+	}
+}
+
+func TestDecisionConsumer_PublishesToStream(t *testing.T) {
+	stream := usecase.NewTransactionStream(&mockTransactionRepository{}, 0, 0, zerolog.Nop())
+	sub := stream.Subscribe(usecase.TransactionStreamFilter{}, "")
+	defer sub.Close()
+
+	consumer := NewDecisionConsumer(usecase.NewUpdateTransactionStatusUseCase(&mockTransactionRepository{}, usecase.NewTransactionFinalizer(nil, nil, nil, stream)), nil, zerolog.Nop(), 0, 0)
+	if err := consumeOne(consumer, &mockConsumerGroupSession{}, &sarama.ConsumerMessage{Value: []byte(`{"transaction_id":"tx-1","status":"APPROVED"}`)}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	select {
+	case event := <-sub.Events:
+		if event.Type != entity.TransactionEventFinalized || event.Transaction.ID != "tx-1" || event.Transaction.Status != entity.APPROVED {
+			t.Errorf("expected tx-1 finalized as APPROVED, got %s %+v", event.Type, event.Transaction)
+		}
+	default:
+		t.Fatal("expected a finalized event")
+	}
+}
@@ -0,0 +1,120 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type idempotencyKeyItem struct {
+	IdempotencyKey string `dynamodbav:"idempotency_key"`
+	Fingerprint    string `dynamodbav:"fingerprint"`
+	TransactionID  string `dynamodbav:"transaction_id"`
+	Transaction    string `dynamodbav:"transaction"`
+	CreatedAt      string `dynamodbav:"created_at"`
+	TTL            int64  `dynamodbav:"ttl"`
+}
+
+// DynamoDBIdempotencyKeyRepository implements repository.IdempotencyKeyRepository
+// using AWS DynamoDB. The table is keyed by idempotency_key (hash) and expires its
+// items through the ttl attribute; the items are written by
+// DynamoDBOutboxRepository.SaveTransaction.
+type DynamoDBIdempotencyKeyRepository struct {
+	client    *dynamodb.Client
+	tableName string
+	logger    zerolog.Logger
+}
+
+// NewDynamoDBIdempotencyKeyRepository creates a new DynamoDB-backed idempotency
+// key repository.
+func NewDynamoDBIdempotencyKeyRepository(
+	client *dynamodb.Client,
+	tableName string,
+	logger zerolog.Logger,
+) *DynamoDBIdempotencyKeyRepository {
+	return &DynamoDBIdempotencyKeyRepository{
+		client:    client,
+		tableName: tableName,
+		logger:    logger,
+	}
+}
+
+// Find reads the key with a consistent read. Expired items that DynamoDB has not
+// deleted yet are ignored.
+func (r *DynamoDBIdempotencyKeyRepository) Find(ctx context.Context, key string) (*entity.IdempotencyKey, error) {
+	output, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"idempotency_key": &types.AttributeValueMemberS{Value: key},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Str("idempotency_key", key).Str("table", r.tableName).Msg("failed to get idempotency key")
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	if output.Item == nil {
+		return nil, nil
+	}
+
+	var item idempotencyKeyItem
+	if err := attributevalue.UnmarshalMap(output.Item, &item); err != nil {
+		r.logger.Error().Err(err).Str("idempotency_key", key).Msg("failed to unmarshal idempotency key")
+		return nil, fmt.Errorf("failed to unmarshal idempotency key: %w", err)
+	}
+
+	record, err := toIdempotencyKey(item)
+	if err != nil {
+		return nil, err
+	}
+	if !record.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+
+	return record, nil
+}
+
+func toIdempotencyKeyItem(key *entity.IdempotencyKey) (idempotencyKeyItem, error) {
+	transaction, err := json.Marshal(key.Transaction)
+	if err != nil {
+		return idempotencyKeyItem{}, fmt.Errorf("failed to marshal idempotency key transaction: %w", err)
+	}
+
+	return idempotencyKeyItem{
+		IdempotencyKey: key.Key,
+		Fingerprint:    key.Fingerprint,
+		TransactionID:  key.Transaction.ID,
+		Transaction:    string(transaction),
+		CreatedAt:      key.CreatedAt.UTC().Format(time.RFC3339Nano),
+		TTL:            key.ExpiresAt.Unix(),
+	}, nil
+}
+
+func toIdempotencyKey(item idempotencyKeyItem) (*entity.IdempotencyKey, error) {
+	var transaction entity.TransactionEntity
+	if err := json.Unmarshal([]byte(item.Transaction), &transaction); err != nil {
+		return nil, fmt.Errorf("failed to parse idempotency key transaction: %w", err)
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, item.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+
+	return &entity.IdempotencyKey{
+		Key:         item.IdempotencyKey,
+		Fingerprint: item.Fingerprint,
+		Transaction: transaction,
+		CreatedAt:   createdAt,
+		ExpiresAt:   time.Unix(item.TTL, 0),
+	}, nil
+}
@@ -0,0 +1,398 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// outboxStatusIndex is the GSI of the outbox table keyed by status (hash) and
+// next_attempt_at (range), used to read the due entries earliest first. Every
+// pending entry shares the PENDING partition of the index and is written there
+// when it is saved, claimed and marked, so the write throughput of that one
+// partition (about 1,000 writes per second) caps the rate of new transactions.
+// Sharding the hash key, e.g. PENDING#0..n, would lift the cap at the cost of
+// one query per shard.
+const outboxStatusIndex = "status-next_attempt_at-index"
+
+// outboxTimeFormat has a fixed number of fractional digits so that created_at
+// sorts chronologically.
+const outboxTimeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// sentOutboxEntryRetention is how long sent entries are kept before the table's
+// TTL removes them.
+const sentOutboxEntryRetention = 7 * 24 * time.Hour
+
+type outboxItem struct {
+	ID            string `dynamodbav:"id"`
+	TransactionID string `dynamodbav:"transaction_id"`
+	Payload       string `dynamodbav:"payload"`
+	Status        string `dynamodbav:"status"`
+	Attempts      int    `dynamodbav:"attempts"`
+	LastError     string `dynamodbav:"last_error,omitempty"`
+	CreatedAt     string `dynamodbav:"created_at"`
+	NextAttemptAt string `dynamodbav:"next_attempt_at"`
+	SentAt        string `dynamodbav:"sent_at,omitempty"`
+	TTL           int64  `dynamodbav:"ttl,omitempty"`
+}
+
+// DynamoDBOutboxRepository implements repository.OutboxRepository using AWS
+// DynamoDB. New transactions, their outbox entries and their idempotency keys are
+// written together with TransactWriteItems. The outbox table is keyed by id
+// (hash) and read through the status-next_attempt_at-index GSI; sent entries
+// expire through the ttl attribute.
+type DynamoDBOutboxRepository struct {
+	client               *dynamodb.Client
+	tableName            string
+	transactionsTable    string
+	idempotencyKeysTable string
+	logger               zerolog.Logger
+}
+
+// NewDynamoDBOutboxRepository creates a new DynamoDB-backed outbox repository
+// writing transactions to transactionsTable and idempotency keys to
+// idempotencyKeysTable.
+func NewDynamoDBOutboxRepository(
+	client *dynamodb.Client,
+	tableName string,
+	transactionsTable string,
+	idempotencyKeysTable string,
+	logger zerolog.Logger,
+) *DynamoDBOutboxRepository {
+	return &DynamoDBOutboxRepository{
+		client:               client,
+		tableName:            tableName,
+		transactionsTable:    transactionsTable,
+		idempotencyKeysTable: idempotencyKeysTable,
+		logger:               logger,
+	}
+}
+
+// SaveTransaction writes the transaction, its outbox entry and its idempotency key
+// in one DynamoDB transaction. It fails when any of them already exists.
+func (r *DynamoDBOutboxRepository) SaveTransaction(
+	ctx context.Context,
+	transaction *entity.TransactionEntity,
+	entry *entity.OutboxEntry,
+	key *entity.IdempotencyKey,
+) error {
+	transactionAV, err := attributevalue.MarshalMap(toTransactionItem(transaction))
+	if err != nil {
+		r.logger.Error().Err(err).Str("transaction_id", transaction.ID).Msg("failed to marshal transaction for DynamoDB")
+		return fmt.Errorf("failed to marshal transaction: %w", err)
+	}
+
+	item, err := toOutboxItem(entry)
+	if err != nil {
+		r.logger.Error().Err(err).Str("transaction_id", transaction.ID).Msg("failed to marshal outbox entry payload")
+		return err
+	}
+	outboxAV, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		r.logger.Error().Err(err).Str("transaction_id", transaction.ID).Msg("failed to marshal outbox entry for DynamoDB")
+		return fmt.Errorf("failed to marshal outbox entry: %w", err)
+	}
+
+	transactItems := []types.TransactWriteItem{
+		{Put: &types.Put{
+			TableName:           aws.String(r.transactionsTable),
+			Item:                transactionAV,
+			ConditionExpression: aws.String("attribute_not_exists(id)"),
+		}},
+		{Put: &types.Put{
+			TableName:           aws.String(r.tableName),
+			Item:                outboxAV,
+			ConditionExpression: aws.String("attribute_not_exists(id)"),
+		}},
+	}
+
+	if key != nil {
+		keyItem, err := toIdempotencyKeyItem(key)
+		if err != nil {
+			r.logger.Error().Err(err).Str("transaction_id", transaction.ID).Msg("failed to marshal idempotency key transaction")
+			return err
+		}
+		keyAV, err := attributevalue.MarshalMap(keyItem)
+		if err != nil {
+			r.logger.Error().Err(err).Str("transaction_id", transaction.ID).Msg("failed to marshal idempotency key for DynamoDB")
+			return fmt.Errorf("failed to marshal idempotency key: %w", err)
+		}
+		// An expired key that DynamoDB has not deleted yet may be reused
+		transactItems = append(transactItems, types.TransactWriteItem{Put: &types.Put{
+			TableName:           aws.String(r.idempotencyKeysTable),
+			Item:                keyAV,
+			ConditionExpression: aws.String("attribute_not_exists(idempotency_key) OR #ttl <= :now"),
+			ExpressionAttributeNames: map[string]string{
+				"#ttl": "ttl",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":now": &types.AttributeValueMemberN{Value: fmt.Sprint(key.CreatedAt.Unix())},
+			},
+		}})
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	if err != nil {
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) {
+			reasons := canceled.CancellationReasons
+			if key != nil && len(reasons) == len(transactItems) && aws.ToString(reasons[2].Code) == "ConditionalCheckFailed" {
+				r.logger.Info().
+					Str("transaction_id", transaction.ID).
+					Str("idempotency_key", key.Key).
+					Msg("idempotency key already used")
+				return fmt.Errorf("%w: %s", entity.ErrIdempotencyKeyExists, key.Key)
+			}
+			for _, reason := range reasons {
+				if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+					r.logger.Warn().
+						Str("transaction_id", transaction.ID).
+						Str("table", r.transactionsTable).
+						Msg("duplicate transaction")
+					return fmt.Errorf("transaction with id %s already exists", transaction.ID)
+				}
+			}
+		}
+
+		r.logger.Error().
+			Err(err).
+			Str("transaction_id", transaction.ID).
+			Str("table", r.transactionsTable).
+			Str("outbox_table", r.tableName).
+			Msg("failed to save transaction with outbox entry")
+		return fmt.Errorf("failed to save transaction: %w", err)
+	}
+
+	r.logger.Info().
+		Str("transaction_id", transaction.ID).
+		Str("outbox_id", entry.ID).
+		Str("table", r.transactionsTable).
+		Msg("transaction saved with outbox entry")
+
+	return nil
+}
+
+// FindDue queries the status index for the pending entries whose next attempt is
+// at or before now, earliest first.
+func (r *DynamoDBOutboxRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]entity.OutboxEntry, error) {
+	output, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(outboxStatusIndex),
+		KeyConditionExpression: aws.String("#s = :pending AND next_attempt_at <= :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#s": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pending": &types.AttributeValueMemberS{Value: string(entity.OutboxPending)},
+			":now":     &types.AttributeValueMemberS{Value: now.UTC().Format(outboxTimeFormat)},
+		},
+		ScanIndexForward: aws.Bool(true),
+		Limit:            aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Msg("failed to query due outbox entries")
+		return nil, fmt.Errorf("failed to query due outbox entries: %w", err)
+	}
+
+	var items []outboxItem
+	if err := attributevalue.UnmarshalListOfMaps(output.Items, &items); err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Msg("failed to unmarshal outbox entries")
+		return nil, fmt.Errorf("failed to unmarshal outbox entries: %w", err)
+	}
+
+	entries := make([]entity.OutboxEntry, 0, len(items))
+	for _, item := range items {
+		entry, err := toOutboxEntry(item)
+		if err != nil {
+			r.logger.Warn().Err(err).Str("outbox_id", item.ID).Msg("failed to map outbox item to entry, skipping")
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Claim moves the next attempt of a pending entry from nextAttemptAt to until.
+// The entry then drops out of the due range of the index, so other relayers no
+// longer read it, and becomes due again when until passes if it is not marked
+// by then.
+func (r *DynamoDBOutboxRepository) Claim(ctx context.Context, id string, nextAttemptAt time.Time, until time.Time) (bool, error) {
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:    aws.String("SET next_attempt_at = :until"),
+		ConditionExpression: aws.String("#s = :pending AND next_attempt_at = :next_attempt_at"),
+		ExpressionAttributeNames: map[string]string{
+			"#s": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pending":         &types.AttributeValueMemberS{Value: string(entity.OutboxPending)},
+			":next_attempt_at": &types.AttributeValueMemberS{Value: nextAttemptAt.UTC().Format(outboxTimeFormat)},
+			":until":           &types.AttributeValueMemberS{Value: until.UTC().Format(outboxTimeFormat)},
+		},
+	})
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			r.logger.Info().Str("outbox_id", id).Msg("outbox entry claimed by another relayer")
+			return false, nil
+		}
+
+		r.logger.Error().Err(err).Str("outbox_id", id).Str("table", r.tableName).Msg("failed to claim outbox entry")
+		return false, fmt.Errorf("failed to claim outbox entry: %w", err)
+	}
+
+	return true, nil
+}
+
+// MarkSent moves a pending entry to SENT and schedules its expiry. An entry that
+// is no longer pending has been sent by another relayer and is left unchanged.
+func (r *DynamoDBOutboxRepository) MarkSent(ctx context.Context, id string, sentAt time.Time) error {
+	return r.updatePending(ctx, id, "SET #s = :sent, sent_at = :sent_at, #ttl = :ttl",
+		map[string]types.AttributeValue{
+			":sent":    &types.AttributeValueMemberS{Value: string(entity.OutboxSent)},
+			":sent_at": &types.AttributeValueMemberS{Value: sentAt.UTC().Format(outboxTimeFormat)},
+			":ttl":     &types.AttributeValueMemberN{Value: fmt.Sprint(sentAt.Add(sentOutboxEntryRetention).Unix())},
+		})
+}
+
+// MarkFailed records a failed publish attempt of a pending entry.
+func (r *DynamoDBOutboxRepository) MarkFailed(
+	ctx context.Context,
+	id string,
+	attempts int,
+	lastError string,
+	nextAttemptAt time.Time,
+) error {
+	return r.updatePending(ctx, id, "SET attempts = :attempts, last_error = :last_error, next_attempt_at = :next_attempt_at",
+		map[string]types.AttributeValue{
+			":attempts":        &types.AttributeValueMemberN{Value: fmt.Sprint(attempts)},
+			":last_error":      &types.AttributeValueMemberS{Value: lastError},
+			":next_attempt_at": &types.AttributeValueMemberS{Value: nextAttemptAt.UTC().Format(outboxTimeFormat)},
+		})
+}
+
+// MarkExhausted records the last failed publish attempt of a pending entry and
+// moves it to FAILED, which takes it out of the pending partition of the index.
+func (r *DynamoDBOutboxRepository) MarkExhausted(ctx context.Context, id string, attempts int, lastError string) error {
+	return r.updatePending(ctx, id, "SET #s = :failed, attempts = :attempts, last_error = :last_error",
+		map[string]types.AttributeValue{
+			":failed":     &types.AttributeValueMemberS{Value: string(entity.OutboxFailed)},
+			":attempts":   &types.AttributeValueMemberN{Value: fmt.Sprint(attempts)},
+			":last_error": &types.AttributeValueMemberS{Value: lastError},
+		})
+}
+
+// updatePending applies the update expression to the entry if it is still pending.
+func (r *DynamoDBOutboxRepository) updatePending(
+	ctx context.Context,
+	id string,
+	updateExpr string,
+	values map[string]types.AttributeValue,
+) error {
+	values[":pending"] = &types.AttributeValueMemberS{Value: string(entity.OutboxPending)}
+
+	names := map[string]string{"#s": "status"}
+	if _, ok := values[":ttl"]; ok {
+		names["#ttl"] = "ttl"
+	}
+
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          aws.String(updateExpr),
+		ConditionExpression:       aws.String("#s = :pending"),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	})
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			r.logger.Info().Str("outbox_id", id).Msg("outbox entry is no longer pending")
+			return nil
+		}
+
+		r.logger.Error().Err(err).Str("outbox_id", id).Str("table", r.tableName).Msg("failed to update outbox entry")
+		return fmt.Errorf("failed to update outbox entry: %w", err)
+	}
+
+	return nil
+}
+
+func toOutboxItem(entry *entity.OutboxEntry) (outboxItem, error) {
+	payload, err := json.Marshal(entry.Transaction)
+	if err != nil {
+		return outboxItem{}, fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+
+	item := outboxItem{
+		ID:            entry.ID,
+		TransactionID: entry.TransactionID,
+		Payload:       string(payload),
+		Status:        string(entry.Status),
+		Attempts:      entry.Attempts,
+		LastError:     entry.LastError,
+		CreatedAt:     entry.CreatedAt.UTC().Format(outboxTimeFormat),
+		NextAttemptAt: entry.NextAttemptAt.UTC().Format(outboxTimeFormat),
+	}
+	if entry.SentAt != nil {
+		item.SentAt = entry.SentAt.UTC().Format(outboxTimeFormat)
+	}
+
+	return item, nil
+}
+
+func toOutboxEntry(item outboxItem) (entity.OutboxEntry, error) {
+	var transaction entity.TransactionEntity
+	if err := json.Unmarshal([]byte(item.Payload), &transaction); err != nil {
+		return entity.OutboxEntry{}, fmt.Errorf("failed to parse outbox payload: %w", err)
+	}
+
+	createdAt, err := time.Parse(outboxTimeFormat, item.CreatedAt)
+	if err != nil {
+		return entity.OutboxEntry{}, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+
+	nextAttemptAt, err := time.Parse(outboxTimeFormat, item.NextAttemptAt)
+	if err != nil {
+		return entity.OutboxEntry{}, fmt.Errorf("failed to parse next_attempt_at: %w", err)
+	}
+
+	var sentAt *time.Time
+	if item.SentAt != "" {
+		t, err := time.Parse(outboxTimeFormat, item.SentAt)
+		if err != nil {
+			return entity.OutboxEntry{}, fmt.Errorf("failed to parse sent_at: %w", err)
+		}
+		sentAt = &t
+	}
+
+	return entity.OutboxEntry{
+		ID:            item.ID,
+		TransactionID: item.TransactionID,
+		Transaction:   transaction,
+		Status:        entity.OutboxStatus(item.Status),
+		Attempts:      item.Attempts,
+		LastError:     item.LastError,
+		CreatedAt:     createdAt,
+		NextAttemptAt: nextAttemptAt,
+		SentAt:        sentAt,
+	}, nil
+}
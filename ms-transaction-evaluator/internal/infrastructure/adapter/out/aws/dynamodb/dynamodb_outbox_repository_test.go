@@ -0,0 +1,308 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	smithymiddleware "github.com/aws/smithy-go/middleware"
+	"github.com/rs/zerolog"
+)
+
+func newTestOutboxEntry() *entity.OutboxEntry {
+	createdAt := time.Date(2025, 1, 15, 10, 0, 0, 123456789, time.UTC)
+	return entity.NewTransactionCreatedEntry("outbox_001", &entity.TransactionEntity{
+		ID:                "txn_001",
+		AmountInCents:     1000,
+		Currency:          entity.USD,
+		PaymentMethod:     entity.CARD,
+		CustomerID:        "cust_1",
+		CustomerName:      "Alice",
+		CustomerEmail:     "alice@test.com",
+		CustomerPhone:     "+1111111111",
+		CustomerIPAddress: "10.0.0.1",
+		Status:            entity.PENDING,
+		CreatedAt:         createdAt,
+		UpdatedAt:         createdAt,
+	})
+}
+
+// newCapturingTransactWriteClient creates a DynamoDB client that captures the
+// TransactWriteItemsInput parameters via middleware without a real call.
+func newCapturingTransactWriteClient(captured *dynamodb.TransactWriteItemsInput) *dynamodb.Client {
+	cfg := aws.Config{
+		Region: "us-east-1",
+	}
+	return dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		o.BaseEndpoint = aws.String("http://localhost:8000")
+		o.HTTPClient = &fakeHTTPClient{}
+		o.APIOptions = append(o.APIOptions, func(stack *smithymiddleware.Stack) error {
+			return stack.Initialize.Add(smithymiddleware.InitializeMiddlewareFunc(
+				"CaptureTransactWriteItems",
+				func(ctx context.Context, in smithymiddleware.InitializeInput, next smithymiddleware.InitializeHandler) (smithymiddleware.InitializeOutput, smithymiddleware.Metadata, error) {
+					if input, ok := in.Parameters.(*dynamodb.TransactWriteItemsInput); ok {
+						*captured = *input
+					}
+					return next.HandleInitialize(ctx, in)
+				},
+			), smithymiddleware.Before)
+		})
+	})
+}
+
+// outboxQueryResponseJSON builds a DynamoDB Query JSON response body with the
+// given outbox items.
+func outboxQueryResponseJSON(t *testing.T, items ...outboxItem) string {
+	t.Helper()
+	jsonItems := make([]map[string]map[string]string, 0, len(items))
+	for _, item := range items {
+		jsonItems = append(jsonItems, map[string]map[string]string{
+			"id":              {"S": item.ID},
+			"transaction_id":  {"S": item.TransactionID},
+			"payload":         {"S": item.Payload},
+			"status":          {"S": item.Status},
+			"attempts":        {"N": fmt.Sprint(item.Attempts)},
+			"created_at":      {"S": item.CreatedAt},
+			"next_attempt_at": {"S": item.NextAttemptAt},
+		})
+	}
+	body, err := json.Marshal(map[string]any{"Count": len(items), "Items": jsonItems})
+	if err != nil {
+		t.Fatalf("Failed to build query response: %v", err)
+	}
+	return string(body)
+}
+
+func TestOutboxItem_RoundTrip(t *testing.T) {
+	entry := newTestOutboxEntry()
+	entry.Attempts = 2
+	entry.LastError = "failed to publish transaction event: broker unavailable"
+	sentAt := entry.CreatedAt.Add(3 * time.Second)
+	entry.SentAt = &sentAt
+
+	item, err := toOutboxItem(entry)
+	if err != nil {
+		t.Fatalf("toOutboxItem returned unexpected error: %v", err)
+	}
+	got, err := toOutboxEntry(item)
+	if err != nil {
+		t.Fatalf("toOutboxEntry returned unexpected error: %v", err)
+	}
+
+	if got.ID != entry.ID || got.TransactionID != entry.TransactionID || got.Status != entry.Status {
+		t.Errorf("Expected keys to round trip, got %+v", got)
+	}
+	if got.Attempts != 2 || got.LastError != entry.LastError {
+		t.Errorf("Expected attempts and last error to round trip, got %d %q", got.Attempts, got.LastError)
+	}
+	if !got.CreatedAt.Equal(entry.CreatedAt) || !got.NextAttemptAt.Equal(entry.NextAttemptAt) {
+		t.Errorf("Expected timestamps to round trip, got %v %v", got.CreatedAt, got.NextAttemptAt)
+	}
+	if got.SentAt == nil || !got.SentAt.Equal(sentAt) {
+		t.Errorf("Expected sent_at %v, got %v", sentAt, got.SentAt)
+	}
+	if got.Transaction.ID != "txn_001" || got.Transaction.AmountInCents != 1000 || got.Transaction.CustomerEmail != "alice@test.com" {
+		t.Errorf("Expected the transaction payload to round trip, got %+v", got.Transaction)
+	}
+}
+
+func TestOutboxSaveTransaction(t *testing.T) {
+	var captured dynamodb.TransactWriteItemsInput
+	client := newCapturingTransactWriteClient(&captured)
+	repo := NewDynamoDBOutboxRepository(client, "outbox", "transactions", "idempotency_keys", zerolog.Nop())
+
+	entry := newTestOutboxEntry()
+	if err := repo.SaveTransaction(context.Background(), &entry.Transaction, entry, nil); err != nil {
+		t.Fatalf("SaveTransaction returned unexpected error: %v", err)
+	}
+
+	if len(captured.TransactItems) != 2 {
+		t.Fatalf("Expected 2 transact items, got %d", len(captured.TransactItems))
+	}
+
+	tables := []string{"transactions", "outbox"}
+	for i, transactItem := range captured.TransactItems {
+		put := transactItem.Put
+		if put == nil {
+			t.Fatalf("Expected item %d to be a Put", i)
+		}
+		if aws.ToString(put.TableName) != tables[i] {
+			t.Errorf("Expected item %d to target %s, got %s", i, tables[i], aws.ToString(put.TableName))
+		}
+		if aws.ToString(put.ConditionExpression) != "attribute_not_exists(id)" {
+			t.Errorf("Expected item %d to be conditioned on a new id, got %s", i, aws.ToString(put.ConditionExpression))
+		}
+	}
+
+	var item outboxItem
+	if err := attributevalue.UnmarshalMap(captured.TransactItems[1].Put.Item, &item); err != nil {
+		t.Fatalf("Failed to unmarshal outbox item: %v", err)
+	}
+	if item.ID != "outbox_001" || item.TransactionID != "txn_001" || item.Status != string(entity.OutboxPending) {
+		t.Errorf("Unexpected outbox item %+v", item)
+	}
+}
+
+func TestOutboxFindDue(t *testing.T) {
+	item, err := toOutboxItem(newTestOutboxEntry())
+	if err != nil {
+		t.Fatalf("toOutboxItem returned unexpected error: %v", err)
+	}
+	corrupt := item
+	corrupt.ID = "outbox_002"
+	corrupt.Payload = "not json"
+
+	body := outboxQueryResponseJSON(t, item, corrupt)
+	var captured []dynamodb.QueryInput
+	client := newCapturingQueryClient(&sequentialHTTPClient{responses: []string{body}}, &captured)
+	repo := NewDynamoDBOutboxRepository(client, "outbox", "transactions", "idempotency_keys", zerolog.Nop())
+
+	now := time.Date(2025, 1, 15, 10, 5, 0, 0, time.UTC)
+	entries, err := repo.FindDue(context.Background(), now, 10)
+	if err != nil {
+		t.Fatalf("FindDue returned unexpected error: %v", err)
+	}
+	if len(captured) != 1 {
+		t.Fatalf("Expected a single query, got %d", len(captured))
+	}
+	input := captured[0]
+	if aws.ToString(input.IndexName) != "status-next_attempt_at-index" || aws.ToString(input.KeyConditionExpression) != "#s = :pending AND next_attempt_at <= :now" {
+		t.Errorf("Expected a query of the due entries, got %q %q", aws.ToString(input.IndexName), aws.ToString(input.KeyConditionExpression))
+	}
+	if got := input.ExpressionAttributeValues[":now"].(*types.AttributeValueMemberS).Value; got != "2025-01-15T10:05:00.000000000Z" {
+		t.Errorf("Unexpected :now %s", got)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected the corrupt item to be skipped, got %d entries", len(entries))
+	}
+	if entries[0].ID != "outbox_001" || entries[0].Transaction.ID != "txn_001" {
+		t.Errorf("Unexpected entry %+v", entries[0])
+	}
+
+	t.Run("should return error on service failure", func(t *testing.T) {
+		client := newScanDynamoDBClient(&errorHTTPClient{})
+		repo := NewDynamoDBOutboxRepository(client, "outbox", "transactions", "idempotency_keys", zerolog.Nop())
+
+		if _, err := repo.FindDue(context.Background(), time.Now(), 10); err == nil {
+			t.Error("Expected an error, got nil")
+		}
+	})
+}
+
+func TestOutboxSaveTransaction_IdempotencyKey(t *testing.T) {
+	var captured dynamodb.TransactWriteItemsInput
+	client := newCapturingTransactWriteClient(&captured)
+	repo := NewDynamoDBOutboxRepository(client, "outbox", "transactions", "idempotency_keys", zerolog.Nop())
+
+	entry := newTestOutboxEntry()
+	key := &entity.IdempotencyKey{
+		Key:         "order-1234",
+		Fingerprint: "abc123",
+		Transaction: entry.Transaction,
+		CreatedAt:   entry.CreatedAt,
+		ExpiresAt:   entry.CreatedAt.Add(24 * time.Hour),
+	}
+	if err := repo.SaveTransaction(context.Background(), &entry.Transaction, entry, key); err != nil {
+		t.Fatalf("SaveTransaction returned unexpected error: %v", err)
+	}
+
+	if len(captured.TransactItems) != 3 {
+		t.Fatalf("Expected 3 transact items, got %d", len(captured.TransactItems))
+	}
+	put := captured.TransactItems[2].Put
+	if put == nil || aws.ToString(put.TableName) != "idempotency_keys" {
+		t.Fatalf("Expected the third item to put into idempotency_keys, got %+v", captured.TransactItems[2])
+	}
+	if aws.ToString(put.ConditionExpression) != "attribute_not_exists(idempotency_key) OR #ttl <= :now" {
+		t.Errorf("Unexpected condition %s", aws.ToString(put.ConditionExpression))
+	}
+
+	var item idempotencyKeyItem
+	if err := attributevalue.UnmarshalMap(put.Item, &item); err != nil {
+		t.Fatalf("Failed to unmarshal idempotency key item: %v", err)
+	}
+	got, err := toIdempotencyKey(item)
+	if err != nil {
+		t.Fatalf("toIdempotencyKey returned unexpected error: %v", err)
+	}
+	if got.Key != "order-1234" || got.Fingerprint != "abc123" || item.TransactionID != "txn_001" {
+		t.Errorf("Unexpected idempotency key item %+v", item)
+	}
+	if got.Transaction.ID != "txn_001" || got.Transaction.CustomerEmail != "alice@test.com" {
+		t.Errorf("Expected the transaction to round trip, got %+v", got.Transaction)
+	}
+	// The ttl attribute has second precision
+	if !got.CreatedAt.Equal(key.CreatedAt) || !got.ExpiresAt.Equal(key.ExpiresAt.Truncate(time.Second)) {
+		t.Errorf("Expected timestamps to round trip, got %v %v", got.CreatedAt, got.ExpiresAt)
+	}
+}
+
+func TestOutboxMarkExhausted(t *testing.T) {
+	var captured dynamodb.UpdateItemInput
+	client := newCapturingDynamoDBClient(&captured)
+	repo := NewDynamoDBOutboxRepository(client, "outbox", "transactions", "idempotency_keys", zerolog.Nop())
+
+	if err := repo.MarkExhausted(context.Background(), "outbox_001", 20, "broker unavailable"); err != nil {
+		t.Fatalf("MarkExhausted returned unexpected error: %v", err)
+	}
+
+	if aws.ToString(captured.ConditionExpression) != "#s = :pending" {
+		t.Errorf("Expected the update to be conditioned on a pending entry, got %s", aws.ToString(captured.ConditionExpression))
+	}
+	if got := captured.ExpressionAttributeValues[":failed"].(*types.AttributeValueMemberS).Value; got != string(entity.OutboxFailed) {
+		t.Errorf("Expected the entry to move to FAILED, got %s", got)
+	}
+	if got := captured.ExpressionAttributeValues[":attempts"].(*types.AttributeValueMemberN).Value; got != "20" {
+		t.Errorf("Expected 20 attempts, got %s", got)
+	}
+}
+
+func TestOutboxClaim(t *testing.T) {
+	nextAttemptAt := time.Date(2025, 1, 15, 10, 0, 0, 123456789, time.UTC)
+	until := nextAttemptAt.Add(30 * time.Second)
+
+	t.Run("moves the next attempt if it is unchanged", func(t *testing.T) {
+		var captured dynamodb.UpdateItemInput
+		client := newCapturingDynamoDBClient(&captured)
+		repo := NewDynamoDBOutboxRepository(client, "outbox", "transactions", "idempotency_keys", zerolog.Nop())
+
+		claimed, err := repo.Claim(context.Background(), "outbox_001", nextAttemptAt, until)
+		if err != nil {
+			t.Fatalf("Claim returned unexpected error: %v", err)
+		}
+		if !claimed {
+			t.Error("Expected the entry to be claimed")
+		}
+
+		if aws.ToString(captured.ConditionExpression) != "#s = :pending AND next_attempt_at = :next_attempt_at" {
+			t.Errorf("Expected the claim to be conditioned on the next attempt read, got %s", aws.ToString(captured.ConditionExpression))
+		}
+		if got := captured.ExpressionAttributeValues[":next_attempt_at"].(*types.AttributeValueMemberS).Value; got != "2025-01-15T10:00:00.123456789Z" {
+			t.Errorf("Expected the next attempt read, got %s", got)
+		}
+		if got := captured.ExpressionAttributeValues[":until"].(*types.AttributeValueMemberS).Value; got != "2025-01-15T10:00:30.123456789Z" {
+			t.Errorf("Expected the end of the lease, got %s", got)
+		}
+	})
+
+	t.Run("reports an entry claimed by another relayer", func(t *testing.T) {
+		body := `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`
+		client := newScanDynamoDBClient(&statusHTTPClient{status: 400, body: body})
+		repo := NewDynamoDBOutboxRepository(client, "outbox", "transactions", "idempotency_keys", zerolog.Nop())
+
+		claimed, err := repo.Claim(context.Background(), "outbox_001", nextAttemptAt, until)
+		if err != nil {
+			t.Fatalf("Claim returned unexpected error: %v", err)
+		}
+		if claimed {
+			t.Error("Expected the entry not to be claimed")
+		}
+	})
+}
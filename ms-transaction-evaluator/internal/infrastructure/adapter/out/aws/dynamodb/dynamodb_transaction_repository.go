@@ -2,12 +2,18 @@ package dynamodb
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"maps"
 	"ms-transaction-evaluator/internal/domain/entity"
-	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -19,16 +25,21 @@ import (
 )
 
 type DynamoDBTransactionRepository struct {
-	client    *dynamodb.Client
-	tableName string
-	logger    zerolog.Logger
+	client       *dynamodb.Client
+	tableName    string
+	cursorSecret []byte
+	logger       zerolog.Logger
 }
 
-func NewDynamoDBTransactionRepository(client *dynamodb.Client, tableName string, logger zerolog.Logger) *DynamoDBTransactionRepository {
+// NewDynamoDBTransactionRepository creates a repository of the tableName table.
+// cursorSecret signs the cursors of FindAllPaginated, so every instance serving
+// the same clients needs the same one.
+func NewDynamoDBTransactionRepository(client *dynamodb.Client, tableName string, cursorSecret []byte, logger zerolog.Logger) *DynamoDBTransactionRepository {
 	return &DynamoDBTransactionRepository{
-		client:    client,
-		tableName: tableName,
-		logger:    logger,
+		client:       client,
+		tableName:    tableName,
+		cursorSecret: cursorSecret,
+		logger:       logger,
 	}
 }
 
@@ -42,10 +53,16 @@ type transactionItem struct {
 	CustomerEmail     string                   `dynamodbav:"customer_email"`
 	CustomerPhone     string                   `dynamodbav:"customer_phone"`
 	CustomerIPAddress string                   `dynamodbav:"customer_ip_address"`
+	ExternalID        string                   `dynamodbav:"external_id,omitempty"`
+	MerchantID        string                   `dynamodbav:"merchant_id,omitempty"`
 	Status            entity.TransactionStatus `dynamodbav:"status"`
+	DecisionPath      string                   `dynamodbav:"decision_path,omitempty"`
 	CreatedAt         string                   `dynamodbav:"created_at"`
 	UpdatedAt         string                   `dynamodbav:"updated_at"`
 	FinalizedAt       string                   `dynamodbav:"finalized_at,omitempty"`
+	RepublishAttempts int                      `dynamodbav:"republish_attempts,omitempty"`
+	LastRepublishedAt string                   `dynamodbav:"last_republished_at,omitempty"`
+	ListBucket        string                   `dynamodbav:"list_bucket,omitempty"`
 }
 
 func (r *DynamoDBTransactionRepository) Save(ctx context.Context, transaction *entity.TransactionEntity) error {
@@ -55,22 +72,7 @@ func (r *DynamoDBTransactionRepository) Save(ctx context.Context, transaction *e
 		Msg("saving transaction to DynamoDB")
 
 	// Convert entity to DynamoDB item
-	item := transactionItem{
-		ID:                transaction.ID,
-		AmountInCents:     transaction.AmountInCents,
-		Currency:          string(transaction.Currency),
-		PaymentMethod:     string(transaction.PaymentMethod),
-		CustomerID:        transaction.CustomerID,
-		CustomerName:      transaction.CustomerName,
-		CustomerEmail:     transaction.CustomerEmail,
-		CustomerPhone:     transaction.CustomerPhone,
-		CustomerIPAddress: transaction.CustomerIPAddress,
-		Status:            transaction.Status,
-		CreatedAt:         transaction.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:         transaction.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}
-
-	av, err := attributevalue.MarshalMap(item)
+	av, err := attributevalue.MarshalMap(toTransactionItem(transaction))
 	if err != nil {
 		r.logger.Error().
 			Err(err).
@@ -110,8 +112,33 @@ func (r *DynamoDBTransactionRepository) Save(ctx context.Context, transaction *e
 	return nil
 }
 
-// UpdateStatus updates the status and updated_at fields of a transaction in DynamoDB.
-func (r *DynamoDBTransactionRepository) UpdateStatus(ctx context.Context, id string, status entity.TransactionStatus, finalizedAt *time.Time) error {
+// toTransactionItem converts a new transaction to its DynamoDB item.
+func toTransactionItem(transaction *entity.TransactionEntity) transactionItem {
+	return transactionItem{
+		ID:                transaction.ID,
+		AmountInCents:     transaction.AmountInCents,
+		Currency:          string(transaction.Currency),
+		PaymentMethod:     string(transaction.PaymentMethod),
+		CustomerID:        transaction.CustomerID,
+		CustomerName:      transaction.CustomerName,
+		CustomerEmail:     transaction.CustomerEmail,
+		CustomerPhone:     transaction.CustomerPhone,
+		CustomerIPAddress: transaction.CustomerIPAddress,
+		ExternalID:        transaction.ExternalID,
+		MerchantID:        transaction.MerchantID,
+		Status:            transaction.Status,
+		CreatedAt:         transaction.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:         transaction.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ListBucket:        transactionListBucket(transaction.ID),
+	}
+}
+
+// UpdateStatus updates the status and updated_at fields of a transaction in DynamoDB,
+// and finalized_at and decision_path when they are given. The update is conditioned
+// on the transaction being PENDING, like Resolve, so a late or duplicate decision
+// does not overwrite a status already set; it then returns nil. Otherwise it
+// returns the transaction as the update found it.
+func (r *DynamoDBTransactionRepository) UpdateStatus(ctx context.Context, id string, status entity.TransactionStatus, finalizedAt *time.Time, decisionPath entity.DecisionPath) (*entity.TransactionEntity, error) {
 	r.logger.Info().
 		Str("transaction_id", id).
 		Str("status", string(status)).
@@ -122,8 +149,9 @@ func (r *DynamoDBTransactionRepository) UpdateStatus(ctx context.Context, id str
 
 	updateExpr := "SET #s = :status, updated_at = :now"
 	exprAttrValues := map[string]types.AttributeValue{
-		":status": &types.AttributeValueMemberS{Value: string(status)},
-		":now":    &types.AttributeValueMemberS{Value: now},
+		":status":  &types.AttributeValueMemberS{Value: string(status)},
+		":now":     &types.AttributeValueMemberS{Value: now},
+		":pending": &types.AttributeValueMemberS{Value: string(entity.PENDING)},
 	}
 
 	if finalizedAt != nil {
@@ -133,25 +161,50 @@ func (r *DynamoDBTransactionRepository) UpdateStatus(ctx context.Context, id str
 		}
 	}
 
-	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+	if decisionPath != "" {
+		updateExpr += ", decision_path = :decision_path"
+		exprAttrValues[":decision_path"] = &types.AttributeValueMemberS{Value: string(decisionPath)}
+	}
+
+	result, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(r.tableName),
 		Key: map[string]types.AttributeValue{
 			"id": &types.AttributeValueMemberS{Value: id},
 		},
 		UpdateExpression:          aws.String(updateExpr),
-		ConditionExpression:       aws.String("attribute_exists(id)"),
+		ConditionExpression:       aws.String("#s = :pending"),
 		ExpressionAttributeNames: map[string]string{
 			"#s": "status",
 		},
 		ExpressionAttributeValues: exprAttrValues,
+		ReturnValues:              types.ReturnValueAllOld,
+		// The item tells a transaction that is no longer PENDING from a missing one
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
 	})
 	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			if len(ccf.Item) == 0 {
+				r.logger.Error().
+					Str("transaction_id", id).
+					Str("table", r.tableName).
+					Msg("transaction to update not found")
+				return nil, fmt.Errorf("failed to update transaction status: transaction %s not found", id)
+			}
+
+			r.logger.Info().
+				Str("transaction_id", id).
+				Str("status", string(status)).
+				Msg("transaction is no longer pending, status left unchanged")
+			return nil, nil
+		}
+
 		r.logger.Error().
 			Err(err).
 			Str("transaction_id", id).
 			Str("table", r.tableName).
 			Msg("failed to update transaction status")
-		return fmt.Errorf("failed to update transaction status: %w", err)
+		return nil, fmt.Errorf("failed to update transaction status: %w", err)
 	}
 
 	r.logger.Info().
@@ -160,12 +213,25 @@ func (r *DynamoDBTransactionRepository) UpdateStatus(ctx context.Context, id str
 		Str("table", r.tableName).
 		Msg("transaction status updated")
 
-	return nil
-}
+	// The status is already updated, so a transaction that cannot be read back
+	// is still reported as updated, with only what the condition guarantees
+	before := entity.TransactionEntity{ID: id, Status: entity.PENDING}
+	var item transactionItem
+	if err := attributevalue.UnmarshalMap(result.Attributes, &item); err != nil {
+		r.logger.Error().
+			Err(err).
+			Str("transaction_id", id).
+			Msg("failed to unmarshal previous transaction from DynamoDB")
+	} else if txn, err := r.mapItemToEntity(item); err != nil {
+		r.logger.Error().
+			Err(err).
+			Str("transaction_id", id).
+			Msg("failed to map previous transaction from DynamoDB")
+	} else {
+		before = txn
+	}
 
-type paginationCursor struct {
-	ID        string `json:"id"`
-	CreatedAt string `json:"created_at"`
+	return &before, nil
 }
 
 func (r *DynamoDBTransactionRepository) mapItemToEntity(item transactionItem) (entity.TransactionEntity, error) {
@@ -198,7 +264,10 @@ func (r *DynamoDBTransactionRepository) mapItemToEntity(item transactionItem) (e
 		CustomerEmail:     item.CustomerEmail,
 		CustomerPhone:     item.CustomerPhone,
 		CustomerIPAddress: item.CustomerIPAddress,
+		ExternalID:        item.ExternalID,
+		MerchantID:        item.MerchantID,
 		Status:            item.Status,
+		DecisionPath:      entity.DecisionPath(item.DecisionPath),
 		CreatedAt:         createdAt,
 		UpdatedAt:         updatedAt,
 		FinalizedAt:       finalizedAt,
@@ -299,54 +368,53 @@ func (r *DynamoDBTransactionRepository) FindAll(ctx context.Context) ([]entity.T
 	return transactions, nil
 }
 
-func (r *DynamoDBTransactionRepository) FindAllPaginated(ctx context.Context, limit int, cursor string) ([]entity.TransactionEntity, string, error) {
+// FindAllPaginated reads a page of the transactions matching the query, ordered
+// by created_at across pages. A filter on customer_id or status is served by the
+// customer_id-created_at-index or status-created_at-index GSI; any other query
+// merges the partitions of the list_bucket-created_at-index GSI. The remaining
+// conditions are applied as a FilterExpression.
+func (r *DynamoDBTransactionRepository) FindAllPaginated(ctx context.Context, query entity.TransactionQuery) ([]entity.TransactionEntity, string, error) {
+	req := newTransactionListRequest(query)
+
 	r.logger.Info().
-		Int("limit", limit).
+		Int("limit", query.Limit).
+		Str("index", req.index).
 		Str("table", r.tableName).
-		Msg("scanning transactions from DynamoDB")
+		Msg("listing transactions from DynamoDB")
 
-	input := &dynamodb.ScanInput{
-		TableName: aws.String(r.tableName),
-		Limit:     aws.Int32(int32(limit)),
+	fingerprint, err := transactionQueryFingerprint(query)
+	if err != nil {
+		return nil, "", err
 	}
 
-	if cursor != "" {
-		decoded, err := base64.StdEncoding.DecodeString(cursor)
-		if err != nil {
-			r.logger.Error().
-				Err(err).
-				Msg("failed to decode cursor")
-			return nil, "", errors.New("invalid cursor: failed to decode base64")
-		}
-
-		var cur paginationCursor
-		if err := json.Unmarshal(decoded, &cur); err != nil {
-			r.logger.Error().
-				Err(err).
-				Msg("failed to unmarshal cursor JSON")
-			return nil, "", errors.New("invalid cursor: failed to parse JSON")
-		}
-
-		if cur.ID == "" || cur.CreatedAt == "" {
-			return nil, "", errors.New("invalid cursor: missing required fields")
-		}
-
-		input.ExclusiveStartKey = map[string]types.AttributeValue{
-			"id": &types.AttributeValueMemberS{Value: cur.ID},
+	var cur *transactionCursor
+	if query.Cursor != "" {
+		if cur, err = r.decodeTransactionCursor(query.Cursor, fingerprint, req.index); err != nil {
+			return nil, "", err
 		}
 	}
 
-	result, err := r.client.Scan(ctx, input)
+	var items []map[string]types.AttributeValue
+	var next *transactionCursor
+	if req.index == transactionBucketIndex {
+		items, next, err = r.readTransactionBuckets(ctx, req, query, cur)
+	} else {
+		items, next, err = r.readTransactionIndex(ctx, req, query, cur)
+	}
 	if err != nil {
-		r.logger.Error().
-			Err(err).
-			Str("table", r.tableName).
-			Msg("failed to scan transactions from DynamoDB")
-		return nil, "", fmt.Errorf("failed to scan transactions: %w", err)
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if next != nil {
+		next.Query = fingerprint
+		if nextCursor, err = r.encodeTransactionCursor(next); err != nil {
+			return nil, "", err
+		}
 	}
 
-	transactions := make([]entity.TransactionEntity, 0, len(result.Items))
-	for _, item := range result.Items {
+	transactions := make([]entity.TransactionEntity, 0, len(items))
+	for _, item := range items {
 		var ddbItem transactionItem
 		if err := attributevalue.UnmarshalMap(item, &ddbItem); err != nil {
 			r.logger.Warn().
@@ -366,31 +434,677 @@ func (r *DynamoDBTransactionRepository) FindAllPaginated(ctx context.Context, li
 		transactions = append(transactions, txn)
 	}
 
-	// Sort client-side by created_at descending
-	sort.Slice(transactions, func(i, j int) bool {
-		return transactions[i].CreatedAt.After(transactions[j].CreatedAt)
+	return transactions, nextCursor, nil
+}
+
+// readTransactionIndex reads the status or customer index from the cursor until
+// query.Limit items match or the index is exhausted.
+func (r *DynamoDBTransactionRepository) readTransactionIndex(
+	ctx context.Context,
+	req *transactionListRequest,
+	query entity.TransactionQuery,
+	cur *transactionCursor,
+) ([]map[string]types.AttributeValue, *transactionCursor, error) {
+	var startKey map[string]types.AttributeValue
+	if cur != nil {
+		startKey = toTransactionKey(cur.Key)
+	}
+
+	var items []map[string]types.AttributeValue
+	for {
+		page, lastEvaluatedKey, err := r.readTransactionPage(ctx, req, query, startKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i, item := range page {
+			items = append(items, item)
+			if len(items) == query.Limit {
+				// Resume after this item if the index has more to read
+				if i < len(page)-1 || len(lastEvaluatedKey) > 0 {
+					key, err := fromTransactionKey(item, req.keyAttributes())
+					if err != nil {
+						return nil, nil, err
+					}
+					return items, &transactionCursor{Key: key}, nil
+				}
+				return items, nil, nil
+			}
+		}
+
+		if len(lastEvaluatedKey) == 0 {
+			return items, nil, nil
+		}
+		startKey = lastEvaluatedKey
+	}
+}
+
+// transactionBucket is the read state of one partition of the bucket index.
+type transactionBucket struct {
+	req *transactionListRequest
+	// position is the key of the last item taken from the partition, where the
+	// next page resumes; nil before the first one.
+	position map[string]string
+	startKey map[string]types.AttributeValue
+	buffered []map[string]types.AttributeValue
+	// exhausted is set once the partition has no more items to read.
+	exhausted bool
+}
+
+// readTransactionBuckets merges the partitions of the bucket index into a page
+// of query.Limit items ordered by created_at. Every partition is read in index
+// order from its position in the cursor, so an item is returned exactly once
+// however the items are spread.
+func (r *DynamoDBTransactionRepository) readTransactionBuckets(
+	ctx context.Context,
+	req *transactionListRequest,
+	query entity.TransactionQuery,
+	cur *transactionCursor,
+) ([]map[string]types.AttributeValue, *transactionCursor, error) {
+	buckets := make([]*transactionBucket, transactionListBuckets)
+	for i := range buckets {
+		bucketReq := *req
+		bucketReq.values = maps.Clone(req.values)
+		bucketReq.values[":list_bucket"] = &types.AttributeValueMemberS{Value: strconv.Itoa(i)}
+		buckets[i] = &transactionBucket{req: &bucketReq}
+
+		if cur == nil {
+			continue
+		}
+		buckets[i].position = cur.Buckets[i].Key
+		buckets[i].startKey = toTransactionKey(cur.Buckets[i].Key)
+		buckets[i].exhausted = cur.Buckets[i].Done
+
+		// Nothing has been returned from this partition yet, so its items before
+		// the last one returned were created since the previous page; skip them
+		if len(cur.Buckets[i].Key) == 0 {
+			if query.Order == entity.SortAscending && cur.Through > bucketReq.createdFrom {
+				bucketReq.createdFrom = cur.Through
+			}
+			if query.Order != entity.SortAscending && (bucketReq.createdTo == "" || cur.Through < bucketReq.createdTo) {
+				bucketReq.createdTo = cur.Through
+			}
+		}
+	}
+
+	var items []map[string]types.AttributeValue
+	for len(items) < query.Limit {
+		var next *transactionBucket
+		for _, bucket := range buckets {
+			for len(bucket.buffered) == 0 && !bucket.exhausted {
+				page, lastEvaluatedKey, err := r.readTransactionPage(ctx, bucket.req, query, bucket.startKey)
+				if err != nil {
+					return nil, nil, err
+				}
+				bucket.buffered = page
+				bucket.startKey = lastEvaluatedKey
+				bucket.exhausted = len(lastEvaluatedKey) == 0
+			}
+			if len(bucket.buffered) > 0 && (next == nil || transactionItemPrecedes(bucket.buffered[0], next.buffered[0], query.Order)) {
+				next = bucket
+			}
+		}
+		if next == nil {
+			break
+		}
+
+		item := next.buffered[0]
+		key, err := fromTransactionKey(item, req.keyAttributes())
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, item)
+		next.buffered = next.buffered[1:]
+		next.position = key
+	}
+
+	// Items read past a partition's position are read again by the next page
+	next := &transactionCursor{Buckets: make([]transactionBucketPosition, len(buckets))}
+	if len(items) > 0 {
+		next.Through = transactionItemString(items[len(items)-1], "created_at")
+	} else if cur != nil {
+		next.Through = cur.Through
+	}
+	more := false
+	for i, bucket := range buckets {
+		if bucket.exhausted && len(bucket.buffered) == 0 {
+			next.Buckets[i].Done = true
+			continue
+		}
+		next.Buckets[i].Key = bucket.position
+		more = true
+	}
+	if !more {
+		return items, nil, nil
+	}
+	return items, next, nil
+}
+
+// transactionItemPrecedes reports whether item a comes before b in order, by
+// created_at and then id.
+func transactionItemPrecedes(a, b map[string]types.AttributeValue, order entity.SortOrder) bool {
+	ka, kb := transactionItemString(a, "created_at"), transactionItemString(b, "created_at")
+	if ka == kb {
+		ka, kb = transactionItemString(a, "id"), transactionItemString(b, "id")
+	}
+	if order == entity.SortAscending {
+		return ka < kb
+	}
+	return ka > kb
+}
+
+func transactionItemString(item map[string]types.AttributeValue, name string) string {
+	if value, ok := item[name].(*types.AttributeValueMemberS); ok {
+		return value.Value
+	}
+	return ""
+}
+
+// readTransactionPage queries up to query.Limit items of the request's index
+// from startKey and returns the matching ones with the LastEvaluatedKey.
+func (r *DynamoDBTransactionRepository) readTransactionPage(
+	ctx context.Context,
+	req *transactionListRequest,
+	query entity.TransactionQuery,
+	startKey map[string]types.AttributeValue,
+) ([]map[string]types.AttributeValue, map[string]types.AttributeValue, error) {
+	var filterExpression *string
+	if len(req.filters) > 0 {
+		filterExpression = aws.String(strings.Join(req.filters, " AND "))
+	}
+
+	// created_at is the sort key of every index, so its range is part of the key
+	// condition
+	keyCondition := req.keyCondition
+	names, values := maps.Clone(req.names), maps.Clone(req.values)
+	if req.createdFrom != "" || req.createdTo != "" {
+		names["#created_at"] = "created_at"
+	}
+	switch {
+	case req.createdFrom != "" && req.createdTo != "":
+		keyCondition += " AND #created_at BETWEEN :created_from AND :created_to"
+	case req.createdFrom != "":
+		keyCondition += " AND #created_at >= :created_from"
+	case req.createdTo != "":
+		keyCondition += " AND #created_at <= :created_to"
+	}
+	if req.createdFrom != "" {
+		values[":created_from"] = &types.AttributeValueMemberS{Value: req.createdFrom}
+	}
+	if req.createdTo != "" {
+		values[":created_to"] = &types.AttributeValueMemberS{Value: req.createdTo}
+	}
+
+	output, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tableName),
+		IndexName:                 aws.String(req.index),
+		KeyConditionExpression:    aws.String(keyCondition),
+		FilterExpression:          filterExpression,
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+		ScanIndexForward:          aws.Bool(query.Order == entity.SortAscending),
+		Limit:                     aws.Int32(int32(query.Limit)),
+		ExclusiveStartKey:         startKey,
 	})
+	if err != nil {
+		r.logger.Error().
+			Err(err).
+			Str("table", r.tableName).
+			Str("index", req.index).
+			Msg("failed to query transactions from DynamoDB")
+		return nil, nil, fmt.Errorf("failed to query transactions: %w", err)
+	}
+	return output.Items, output.LastEvaluatedKey, nil
+}
 
-	// Build next_cursor from DynamoDB's LastEvaluatedKey
-	var nextCursor string
-	if result.LastEvaluatedKey != nil && len(result.LastEvaluatedKey) > 0 && len(transactions) > 0 {
-		// Use the last item in our sorted results for the cursor
-		lastTxn := transactions[len(transactions)-1]
-		cur := paginationCursor{
-			ID:        lastTxn.ID,
-			CreatedAt: lastTxn.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+// Secondary indexes of the transactions table, keyed by an attribute and
+// created_at.
+const (
+	transactionStatusIndex   = "status-created_at-index"
+	transactionCustomerIndex = "customer_id-created_at-index"
+	transactionBucketIndex   = "list_bucket-created_at-index"
+)
+
+// transactionListBuckets is the number of list_bucket values the transactions
+// are spread over, so the bucket index is not written on a single partition.
+// Changing it requires rewriting list_bucket on every item.
+const transactionListBuckets = 4
+
+// transactionListBucket returns the list_bucket of a transaction.
+func transactionListBucket(id string) string {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return strconv.Itoa(int(h.Sum32() % transactionListBuckets))
+}
+
+// BackfillListBuckets sets the list_bucket of the transactions saved before it
+// was added, so that FindAllPaginated lists them, and returns the number of
+// transactions updated. A transaction that already has one is left unchanged,
+// so it can be run again, and alongside new transactions being saved.
+func (r *DynamoDBTransactionRepository) BackfillListBuckets(ctx context.Context) (int, error) {
+	r.logger.Info().
+		Str("table", r.tableName).
+		Msg("backfilling transaction list buckets")
+
+	updated := 0
+	var lastEvaluatedKey map[string]types.AttributeValue
+	for {
+		result, err := r.client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:            aws.String(r.tableName),
+			ProjectionExpression: aws.String("id"),
+			FilterExpression:     aws.String("attribute_not_exists(list_bucket)"),
+			ExclusiveStartKey:    lastEvaluatedKey,
+		})
+		if err != nil {
+			r.logger.Error().
+				Err(err).
+				Str("table", r.tableName).
+				Msg("failed to scan transactions without a list bucket")
+			return updated, fmt.Errorf("failed to scan transactions: %w", err)
+		}
+
+		for _, item := range result.Items {
+			id := transactionItemString(item, "id")
+			_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+				TableName: aws.String(r.tableName),
+				Key: map[string]types.AttributeValue{
+					"id": &types.AttributeValueMemberS{Value: id},
+				},
+				UpdateExpression:    aws.String("SET list_bucket = :list_bucket"),
+				ConditionExpression: aws.String("attribute_exists(id) AND attribute_not_exists(list_bucket)"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":list_bucket": &types.AttributeValueMemberS{Value: transactionListBucket(id)},
+				},
+			})
+			if err != nil {
+				var ccf *types.ConditionalCheckFailedException
+				if errors.As(err, &ccf) {
+					continue
+				}
+
+				r.logger.Error().
+					Err(err).
+					Str("transaction_id", id).
+					Str("table", r.tableName).
+					Msg("failed to set transaction list bucket")
+				return updated, fmt.Errorf("failed to set transaction list bucket: %w", err)
+			}
+			updated++
+		}
+
+		lastEvaluatedKey = result.LastEvaluatedKey
+		if lastEvaluatedKey == nil {
+			break
 		}
+		r.logger.Debug().Int("updated", updated).Msg("backfilling transaction list buckets")
+	}
+
+	r.logger.Info().
+		Int("transactions", updated).
+		Str("table", r.tableName).
+		Msg("transaction list buckets backfilled")
+	return updated, nil
+}
+
+// transactionListRequest is how a transaction query is read: a Query of index
+// with keyCondition and the inclusive created_at range, keeping the items
+// matching filters. The bucket index is queried once per :list_bucket value.
+type transactionListRequest struct {
+	index        string
+	keyCondition string
+	createdFrom  string
+	createdTo    string
+	filters      []string
+	names        map[string]string
+	values       map[string]types.AttributeValue
+}
+
+func newTransactionListRequest(query entity.TransactionQuery) *transactionListRequest {
+	filter := query.Filter
+	req := &transactionListRequest{
+		names:  map[string]string{},
+		values: map[string]types.AttributeValue{},
+	}
+
+	// The customer is the most selective key
+	switch {
+	case filter.CustomerID != "":
+		req.index = transactionCustomerIndex
+		req.keyCondition = req.condition("customer_id", "=", ":customer_id", &types.AttributeValueMemberS{Value: filter.CustomerID})
+	case filter.Status != "":
+		req.index = transactionStatusIndex
+		req.keyCondition = req.condition("status", "=", ":status", &types.AttributeValueMemberS{Value: string(filter.Status)})
+	default:
+		// :list_bucket is set by each query of the partitions
+		req.index = transactionBucketIndex
+		req.keyCondition = "#list_bucket = :list_bucket"
+		req.names["#list_bucket"] = "list_bucket"
+	}
+	if filter.Status != "" && req.index != transactionStatusIndex {
+		req.filters = append(req.filters, req.condition("status", "=", ":status", &types.AttributeValueMemberS{Value: string(filter.Status)}))
+	}
+
+	if filter.CreatedFrom != nil {
+		req.createdFrom = formatTransactionTime(*filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		req.createdTo = formatTransactionTime(*filter.CreatedTo)
+	}
+
+	if filter.PaymentMethod != "" {
+		req.filters = append(req.filters, req.condition("payment_method", "=", ":payment_method", &types.AttributeValueMemberS{Value: string(filter.PaymentMethod)}))
+	}
+	if filter.Currency != "" {
+		req.filters = append(req.filters, req.condition("currency", "=", ":currency", &types.AttributeValueMemberS{Value: string(filter.Currency)}))
+	}
+	if filter.MinAmountInCents != nil {
+		req.filters = append(req.filters, req.condition("amount_in_cents", ">=", ":min_amount", &types.AttributeValueMemberN{Value: strconv.FormatInt(*filter.MinAmountInCents, 10)}))
+	}
+	if filter.MaxAmountInCents != nil {
+		req.filters = append(req.filters, req.condition("amount_in_cents", "<=", ":max_amount", &types.AttributeValueMemberN{Value: strconv.FormatInt(*filter.MaxAmountInCents, 10)}))
+	}
+
+	return req
+}
+
+// condition returns "#attribute operator placeholder" and sets its name and value.
+func (req *transactionListRequest) condition(attribute, operator, placeholder string, value types.AttributeValue) string {
+	req.names["#"+attribute] = attribute
+	req.values[placeholder] = value
+	return fmt.Sprintf("#%s %s %s", attribute, operator, placeholder)
+}
+
+// keyAttributes returns the attributes of an item's key in the index read.
+func (req *transactionListRequest) keyAttributes() []string {
+	switch req.index {
+	case transactionCustomerIndex:
+		return []string{"id", "customer_id", "created_at"}
+	case transactionStatusIndex:
+		return []string{"id", "status", "created_at"}
+	default:
+		return []string{"id", "list_bucket", "created_at"}
+	}
+}
+
+func formatTransactionTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z07:00")
+}
+
+// transactionCursor is the next_cursor of a transaction list: where the read of
+// the index resumes, and a fingerprint of the query so a cursor is only
+// accepted by the query that issued it. It is signed so clients cannot forge
+// start keys.
+type transactionCursor struct {
+	Query string `json:"query"`
+	// Key is the last item returned from the status or customer index.
+	Key map[string]string `json:"key,omitempty"`
+	// Buckets are the positions in each partition of the bucket index, and
+	// Through the created_at of the last item returned from any of them.
+	Buckets []transactionBucketPosition `json:"buckets,omitempty"`
+	Through string                      `json:"through,omitempty"`
+}
+
+// transactionBucketPosition is the last item returned from a partition of the
+// bucket index, none if Key is empty, or Done once the partition is exhausted.
+type transactionBucketPosition struct {
+	Key  map[string]string `json:"key,omitempty"`
+	Done bool              `json:"done,omitempty"`
+}
+
+// transactionQueryFingerprint hashes the filter and order of a query; the limit
+// may change between pages.
+func transactionQueryFingerprint(query entity.TransactionQuery) (string, error) {
+	b, err := json.Marshal(struct {
+		Filter entity.TransactionFilter
+		Order  entity.SortOrder
+	}{query.Filter, query.Order})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal transaction query: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8]), nil
+}
 
-		curJSON, err := json.Marshal(cur)
+// encodeTransactionCursor encodes cur as <payload>.<HMAC-SHA256 of payload>,
+// both base64url.
+func (r *DynamoDBTransactionRepository) encodeTransactionCursor(cur *transactionCursor) (string, error) {
+	b, err := json.Marshal(cur)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(b)
+	return payload + "." + r.signTransactionCursor(payload), nil
+}
+
+func (r *DynamoDBTransactionRepository) decodeTransactionCursor(cursor, fingerprint, index string) (*transactionCursor, error) {
+	payload, signature, ok := strings.Cut(cursor, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(r.signTransactionCursor(payload))) {
+		return nil, fmt.Errorf("%w: invalid signature", entity.ErrCursorMalformed)
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", entity.ErrCursorMalformed, err)
+	}
+
+	var cur transactionCursor
+	if err := json.Unmarshal(b, &cur); err != nil {
+		return nil, fmt.Errorf("%w: %w", entity.ErrCursorMalformed, err)
+	}
+	if cur.Query != fingerprint {
+		return nil, fmt.Errorf("%w: issued for different filters", entity.ErrCursorMalformed)
+	}
+	if index == transactionBucketIndex && len(cur.Buckets) != transactionListBuckets {
+		return nil, fmt.Errorf("%w: expected %d bucket positions", entity.ErrCursorMalformed, transactionListBuckets)
+	}
+	if index != transactionBucketIndex && cur.Key["id"] == "" {
+		return nil, fmt.Errorf("%w: missing id", entity.ErrCursorMalformed)
+	}
+
+	return &cur, nil
+}
+
+func (r *DynamoDBTransactionRepository) signTransactionCursor(payload string) string {
+	mac := hmac.New(sha256.New, r.cursorSecret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// fromTransactionKey returns the values of an item's key attributes.
+func fromTransactionKey(item map[string]types.AttributeValue, keyAttributes []string) (map[string]string, error) {
+	key := make(map[string]string, len(keyAttributes))
+	for _, name := range keyAttributes {
+		value, ok := item[name].(*types.AttributeValueMemberS)
+		if !ok {
+			return nil, fmt.Errorf("transaction item has no %s", name)
+		}
+		key[name] = value.Value
+	}
+	return key, nil
+}
+
+// toTransactionKey converts a key from a cursor to an ExclusiveStartKey, nil
+// for an empty key.
+func toTransactionKey(key map[string]string) map[string]types.AttributeValue {
+	if len(key) == 0 {
+		return nil
+	}
+	startKey := make(map[string]types.AttributeValue, len(key))
+	for name, value := range key {
+		startKey[name] = &types.AttributeValueMemberS{Value: value}
+	}
+	return startKey
+}
+
+// FindStuck queries the status index for PENDING transactions created before
+// cutoff, oldest first, and keeps those not republished since cutoff, until limit
+// are found or the index is exhausted.
+func (r *DynamoDBTransactionRepository) FindStuck(ctx context.Context, cutoff time.Time, limit int) ([]entity.StuckTransaction, error) {
+	r.logger.Debug().
+		Time("cutoff", cutoff).
+		Str("table", r.tableName).
+		Msg("querying stuck transactions from DynamoDB")
+
+	cutoffStr := cutoff.UTC().Format("2006-01-02T15:04:05Z07:00")
+
+	var stuck []entity.StuckTransaction
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for len(stuck) < limit {
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			IndexName:              aws.String(transactionStatusIndex),
+			KeyConditionExpression: aws.String("#s = :pending AND created_at < :cutoff"),
+			FilterExpression:       aws.String("attribute_not_exists(last_republished_at) OR last_republished_at < :cutoff"),
+			ExpressionAttributeNames: map[string]string{
+				"#s": "status",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pending": &types.AttributeValueMemberS{Value: string(entity.PENDING)},
+				":cutoff":  &types.AttributeValueMemberS{Value: cutoffStr},
+			},
+			ScanIndexForward:  aws.Bool(true),
+			ExclusiveStartKey: lastEvaluatedKey,
+		})
 		if err != nil {
 			r.logger.Error().
 				Err(err).
-				Msg("failed to marshal cursor")
-			return nil, "", fmt.Errorf("failed to marshal cursor: %w", err)
+				Str("table", r.tableName).
+				Msg("failed to query stuck transactions from DynamoDB")
+			return nil, fmt.Errorf("failed to query stuck transactions: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var ddbItem transactionItem
+			if err := attributevalue.UnmarshalMap(item, &ddbItem); err != nil {
+				r.logger.Warn().
+					Err(err).
+					Msg("failed to unmarshal transaction item, skipping")
+				continue
+			}
+
+			txn, err := r.mapItemToEntity(ddbItem)
+			if err != nil {
+				r.logger.Warn().
+					Err(err).
+					Msg("failed to map transaction item to entity, skipping")
+				continue
+			}
+
+			var lastRepublishedAt *time.Time
+			if ddbItem.LastRepublishedAt != "" {
+				t, err := time.Parse("2006-01-02T15:04:05Z07:00", ddbItem.LastRepublishedAt)
+				if err != nil {
+					r.logger.Warn().
+						Err(err).
+						Str("transaction_id", ddbItem.ID).
+						Msg("failed to parse last_republished_at, skipping")
+					continue
+				}
+				lastRepublishedAt = &t
+			}
+
+			stuck = append(stuck, entity.StuckTransaction{
+				Transaction:       txn,
+				RepublishAttempts: ddbItem.RepublishAttempts,
+				LastRepublishedAt: lastRepublishedAt,
+			})
+			if len(stuck) == limit {
+				break
+			}
 		}
 
-		nextCursor = base64.StdEncoding.EncodeToString(curJSON)
+		lastEvaluatedKey = result.LastEvaluatedKey
+		if lastEvaluatedKey == nil {
+			break
+		}
 	}
 
-	return transactions, nextCursor, nil
+	return stuck, nil
+}
+
+// RecordRepublish stores the republish attempts of a PENDING transaction. A
+// transaction that has been decided in the meantime is left unchanged.
+func (r *DynamoDBTransactionRepository) RecordRepublish(ctx context.Context, id string, attempts int, republishedAt time.Time) error {
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:    aws.String("SET republish_attempts = :attempts, last_republished_at = :republished_at"),
+		ConditionExpression: aws.String("#s = :pending"),
+		ExpressionAttributeNames: map[string]string{
+			"#s": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":attempts":       &types.AttributeValueMemberN{Value: strconv.Itoa(attempts)},
+			":republished_at": &types.AttributeValueMemberS{Value: republishedAt.UTC().Format("2006-01-02T15:04:05Z07:00")},
+			":pending":        &types.AttributeValueMemberS{Value: string(entity.PENDING)},
+		},
+	})
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			r.logger.Info().
+				Str("transaction_id", id).
+				Msg("transaction is no longer pending, republish not recorded")
+			return nil
+		}
+
+		r.logger.Error().
+			Err(err).
+			Str("transaction_id", id).
+			Str("table", r.tableName).
+			Msg("failed to record transaction republish")
+		return fmt.Errorf("failed to record transaction republish: %w", err)
+	}
+
+	return nil
+}
+
+// Resolve sets the status of a transaction that is still PENDING. It returns false
+// when the transaction has been decided in the meantime.
+func (r *DynamoDBTransactionRepository) Resolve(ctx context.Context, id string, status entity.TransactionStatus, finalizedAt *time.Time) (bool, error) {
+	updateExpr := "SET #s = :status, updated_at = :now"
+	exprAttrValues := map[string]types.AttributeValue{
+		":status":  &types.AttributeValueMemberS{Value: string(status)},
+		":now":     &types.AttributeValueMemberS{Value: time.Now().UTC().Format("2006-01-02T15:04:05Z07:00")},
+		":pending": &types.AttributeValueMemberS{Value: string(entity.PENDING)},
+	}
+
+	if finalizedAt != nil {
+		updateExpr += ", finalized_at = :finalized_at"
+		exprAttrValues[":finalized_at"] = &types.AttributeValueMemberS{
+			Value: finalizedAt.UTC().Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:    aws.String(updateExpr),
+		ConditionExpression: aws.String("#s = :pending"),
+		ExpressionAttributeNames: map[string]string{
+			"#s": "status",
+		},
+		ExpressionAttributeValues: exprAttrValues,
+	})
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			return false, nil
+		}
+
+		r.logger.Error().
+			Err(err).
+			Str("transaction_id", id).
+			Str("status", string(status)).
+			Str("table", r.tableName).
+			Msg("failed to resolve stuck transaction")
+		return false, fmt.Errorf("failed to resolve transaction: %w", err)
+	}
+
+	return true, nil
 }
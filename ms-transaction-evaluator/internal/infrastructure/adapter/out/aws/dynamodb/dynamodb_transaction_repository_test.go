@@ -3,6 +3,9 @@ package dynamodb
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"ms-transaction-evaluator/internal/domain/entity"
@@ -15,6 +18,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	smithymiddleware "github.com/aws/smithy-go/middleware"
 	"github.com/rs/zerolog"
 )
@@ -147,12 +151,18 @@ func TestUpdateStatus_FinalizedAt(t *testing.T) {
 		var captured dynamodb.UpdateItemInput
 		client := newCapturingDynamoDBClient(&captured)
 		logger := zerolog.Nop()
-		repo := NewDynamoDBTransactionRepository(client, "transactions", logger)
+		repo := NewDynamoDBTransactionRepository(client, "transactions", nil, logger)
 
 		finalizedAt := time.Date(2025, 1, 15, 10, 0, 2, 0, time.UTC)
-		err := repo.UpdateStatus(context.Background(), "txn_001", entity.APPROVED, &finalizedAt)
-		if err != nil {
-			t.Fatalf("UpdateStatus returned unexpected error: %v", err)
+		before, err := repo.UpdateStatus(context.Background(), "txn_001", entity.APPROVED, &finalizedAt, entity.DecisionPathFraudCheck)
+		if err != nil || before == nil {
+			t.Fatalf("UpdateStatus returned %v, %v", before, err)
+		}
+		if aws.ToString(captured.ConditionExpression) != "#s = :pending" {
+			t.Errorf("Expected the update to be conditioned on PENDING, got %s", aws.ToString(captured.ConditionExpression))
+		}
+		if captured.ReturnValues != types.ReturnValueAllOld {
+			t.Errorf("Expected the previous transaction to be returned, got %s", captured.ReturnValues)
 		}
 
 		// Verify UpdateExpression contains finalized_at
@@ -171,15 +181,23 @@ func TestUpdateStatus_FinalizedAt(t *testing.T) {
 		if _, ok := captured.ExpressionAttributeValues[":finalized_at"]; !ok {
 			t.Error("Expected ExpressionAttributeValues to contain ':finalized_at' key")
 		}
+
+		// Verify the decision path is set
+		if !strings.Contains(expr, "decision_path") {
+			t.Errorf("Expected UpdateExpression to contain 'decision_path', got: %s", expr)
+		}
+		if v, ok := captured.ExpressionAttributeValues[":decision_path"].(*types.AttributeValueMemberS); !ok || v.Value != "FRAUD_CHECK" {
+			t.Errorf("Expected ':decision_path' to be FRAUD_CHECK, got: %v", captured.ExpressionAttributeValues[":decision_path"])
+		}
 	})
 
 	t.Run("should NOT include finalized_at in UpdateExpression when finalizedAt is nil", func(t *testing.T) {
 		var captured dynamodb.UpdateItemInput
 		client := newCapturingDynamoDBClient(&captured)
 		logger := zerolog.Nop()
-		repo := NewDynamoDBTransactionRepository(client, "transactions", logger)
+		repo := NewDynamoDBTransactionRepository(client, "transactions", nil, logger)
 
-		err := repo.UpdateStatus(context.Background(), "txn_002", entity.PENDING, nil)
+		_, err := repo.UpdateStatus(context.Background(), "txn_002", entity.PENDING, nil, "")
 		if err != nil {
 			t.Fatalf("UpdateStatus returned unexpected error: %v", err)
 		}
@@ -200,6 +218,49 @@ func TestUpdateStatus_FinalizedAt(t *testing.T) {
 	})
 }
 
+func TestUpdateStatus_ConditionalOnPending(t *testing.T) {
+	ccf := `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"%s}`
+
+	t.Run("should return the transaction as the update found it", func(t *testing.T) {
+		attributes := `{"Attributes":{"id":{"S":"txn_001"},"status":{"S":"PENDING"},"payment_method":{"S":"CRYPTO"},"created_at":{"S":"2025-01-15T10:00:00Z"},"updated_at":{"S":"2025-01-15T10:00:00Z"}}}`
+		client := newScanDynamoDBClient(&statusHTTPClient{status: 200, body: attributes})
+		repo := NewDynamoDBTransactionRepository(client, "transactions", nil, zerolog.Nop())
+
+		finalizedAt := time.Now()
+		before, err := repo.UpdateStatus(context.Background(), "txn_001", entity.APPROVED, &finalizedAt, entity.DecisionPathDirect)
+		if err != nil {
+			t.Fatalf("UpdateStatus returned unexpected error: %v", err)
+		}
+		if before == nil || before.Status != entity.PENDING || before.PaymentMethod != entity.CRYPTO || before.FinalizedAt != nil {
+			t.Errorf("Expected the PENDING CRYPTO transaction, got %+v", before)
+		}
+	})
+
+	t.Run("should leave a transaction that is no longer PENDING unchanged", func(t *testing.T) {
+		item := `,"Item":{"id":{"S":"txn_001"},"status":{"S":"EXPIRED"}}`
+		client := newScanDynamoDBClient(&statusHTTPClient{status: 400, body: fmt.Sprintf(ccf, item)})
+		repo := NewDynamoDBTransactionRepository(client, "transactions", nil, zerolog.Nop())
+
+		finalizedAt := time.Now()
+		before, err := repo.UpdateStatus(context.Background(), "txn_001", entity.APPROVED, &finalizedAt, entity.DecisionPathDirect)
+		if err != nil {
+			t.Fatalf("UpdateStatus returned unexpected error: %v", err)
+		}
+		if before != nil {
+			t.Errorf("Expected the transaction to be reported as not updated, got %+v", before)
+		}
+	})
+
+	t.Run("should return an error when the transaction does not exist", func(t *testing.T) {
+		client := newScanDynamoDBClient(&statusHTTPClient{status: 400, body: fmt.Sprintf(ccf, "")})
+		repo := NewDynamoDBTransactionRepository(client, "transactions", nil, zerolog.Nop())
+
+		if _, err := repo.UpdateStatus(context.Background(), "txn_missing", entity.APPROVED, nil, ""); err == nil {
+			t.Error("Expected an error, got nil")
+		}
+	})
+}
+
 // sequentialHTTPClient returns a different HTTP response for each successive
 // request, allowing multi-page DynamoDB Scan simulation.
 type sequentialHTTPClient struct {
@@ -327,7 +388,7 @@ func TestFindAll(t *testing.T) {
 
 		client := newScanDynamoDBClient(httpClient)
 		logger := zerolog.Nop()
-		repo := NewDynamoDBTransactionRepository(client, "transactions", logger)
+		repo := NewDynamoDBTransactionRepository(client, "transactions", nil, logger)
 
 		results, err := repo.FindAll(context.Background())
 		if err != nil {
@@ -372,7 +433,7 @@ func TestFindAll(t *testing.T) {
 
 		client := newScanDynamoDBClient(httpClient)
 		logger := zerolog.Nop()
-		repo := NewDynamoDBTransactionRepository(client, "transactions", logger)
+		repo := NewDynamoDBTransactionRepository(client, "transactions", nil, logger)
 
 		results, err := repo.FindAll(context.Background())
 		if err != nil {
@@ -394,7 +455,7 @@ func TestFindAll(t *testing.T) {
 
 		client := newScanDynamoDBClient(httpClient)
 		logger := zerolog.Nop()
-		repo := NewDynamoDBTransactionRepository(client, "transactions", logger)
+		repo := NewDynamoDBTransactionRepository(client, "transactions", nil, logger)
 
 		results, err := repo.FindAll(context.Background())
 		if err == nil {
@@ -410,3 +471,401 @@ func TestFindAll(t *testing.T) {
 		}
 	})
 }
+
+func TestFindStuck(t *testing.T) {
+	created := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC).Format("2006-01-02T15:04:05Z07:00")
+	pendingItem := transactionItem{
+		ID: "txn_001", AmountInCents: 1000, Currency: "USD",
+		PaymentMethod: "CARD", CustomerID: "cust_1", CustomerName: "Alice",
+		CustomerEmail: "alice@test.com", CustomerPhone: "+1111111111",
+		CustomerIPAddress: "10.0.0.1", Status: entity.PENDING,
+		CreatedAt: created, UpdatedAt: created,
+	}
+	page1 := scanResponseJSON([]transactionItem{pendingItem}, true, "txn_001")
+	republished := strings.Replace(page1, `"updated_at"`, `"republish_attempts":{"N":"2"},"last_republished_at":{"S":"`+created+`"},"updated_at"`, 1)
+	second := pendingItem
+	second.ID = "txn_002"
+	third := pendingItem
+	third.ID = "txn_003"
+	page2 := scanResponseJSON([]transactionItem{second, third}, false, "")
+
+	t.Run("should collect stuck transactions across pages up to limit", func(t *testing.T) {
+		httpClient := &sequentialHTTPClient{responses: []string{republished, page2}}
+		var captured []dynamodb.QueryInput
+		repo := NewDynamoDBTransactionRepository(newCapturingQueryClient(httpClient, &captured), "transactions", nil, zerolog.Nop())
+
+		stuck, err := repo.FindStuck(context.Background(), time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), 2)
+		if err != nil {
+			t.Fatalf("FindStuck returned unexpected error: %v", err)
+		}
+		if len(captured) != 2 {
+			t.Fatalf("Expected 2 queries, got %d", len(captured))
+		}
+		input := captured[0]
+		if aws.ToString(input.IndexName) != "status-created_at-index" || aws.ToString(input.KeyConditionExpression) != "#s = :pending AND created_at < :cutoff" {
+			t.Errorf("Expected a query of the status index, got %q %q", aws.ToString(input.IndexName), aws.ToString(input.KeyConditionExpression))
+		}
+		if got := input.ExpressionAttributeValues[":cutoff"].(*types.AttributeValueMemberS).Value; got != "2025-01-15T10:00:00Z" {
+			t.Errorf("Unexpected :cutoff %s", got)
+		}
+		if len(stuck) != 2 {
+			t.Fatalf("Expected 2 stuck transactions, got %d", len(stuck))
+		}
+		if stuck[0].Transaction.ID != "txn_001" || stuck[0].RepublishAttempts != 2 || stuck[0].LastRepublishedAt == nil {
+			t.Errorf("Expected txn_001 with 2 republish attempts, got %+v", stuck[0])
+		}
+		if stuck[1].Transaction.ID != "txn_002" || stuck[1].RepublishAttempts != 0 || stuck[1].LastRepublishedAt != nil {
+			t.Errorf("Expected txn_002 never republished, got %+v", stuck[1])
+		}
+	})
+
+	t.Run("should return error on service failure", func(t *testing.T) {
+		repo := NewDynamoDBTransactionRepository(newScanDynamoDBClient(&errorHTTPClient{}), "transactions", nil, zerolog.Nop())
+
+		if _, err := repo.FindStuck(context.Background(), time.Now(), 10); err == nil {
+			t.Error("Expected an error, got nil")
+		}
+	})
+}
+
+func TestResolve_ConditionalOnPending(t *testing.T) {
+	var captured dynamodb.UpdateItemInput
+	client := newCapturingDynamoDBClient(&captured)
+	repo := NewDynamoDBTransactionRepository(client, "transactions", nil, zerolog.Nop())
+
+	ok, err := repo.Resolve(context.Background(), "txn_001", entity.EXPIRED, nil)
+	if err != nil || !ok {
+		t.Fatalf("Resolve returned %v, %v", ok, err)
+	}
+	if aws.ToString(captured.ConditionExpression) != "#s = :pending" {
+		t.Errorf("Expected the update to be conditioned on PENDING, got %s", aws.ToString(captured.ConditionExpression))
+	}
+	if strings.Contains(aws.ToString(captured.UpdateExpression), "finalized_at") {
+		t.Errorf("Expected no finalized_at without finalizedAt, got %s", aws.ToString(captured.UpdateExpression))
+	}
+}
+
+// backfillHTTPClient answers the Scan requests of BackfillListBuckets with scans
+// in turn and records the UpdateItem requests, failing the condition of the IDs
+// in conflicting.
+type backfillHTTPClient struct {
+	scans       []string
+	conflicting map[string]bool
+	updated     []map[string]any
+}
+
+func (b *backfillHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	status, body := 200, `{}`
+	switch req.Header.Get("X-Amz-Target") {
+	case "DynamoDB_20120810.Scan":
+		body, b.scans = b.scans[0], b.scans[1:]
+	case "DynamoDB_20120810.UpdateItem":
+		var input map[string]any
+		if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+			return nil, err
+		}
+		id := input["Key"].(map[string]any)["id"].(map[string]any)["S"].(string)
+		if b.conflicting[id] {
+			status, body = 400, `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`
+		} else {
+			b.updated = append(b.updated, input)
+		}
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.0"}},
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}, nil
+}
+
+func TestBackfillListBuckets(t *testing.T) {
+	t.Run("sets the bucket of the transactions without one across pages", func(t *testing.T) {
+		httpClient := &backfillHTTPClient{
+			scans: []string{
+				`{"Count":2,"Items":[{"id":{"S":"txn_001"}},{"id":{"S":"txn_002"}}],"LastEvaluatedKey":{"id":{"S":"txn_002"}}}`,
+				`{"Count":1,"Items":[{"id":{"S":"txn_003"}}]}`,
+			},
+			// txn_002 was saved with a bucket while the scan ran
+			conflicting: map[string]bool{"txn_002": true},
+		}
+		repo := NewDynamoDBTransactionRepository(newScanDynamoDBClient(httpClient), "transactions", nil, zerolog.Nop())
+
+		updated, err := repo.BackfillListBuckets(context.Background())
+		if err != nil {
+			t.Fatalf("BackfillListBuckets returned unexpected error: %v", err)
+		}
+		if updated != 2 || len(httpClient.updated) != 2 {
+			t.Fatalf("Expected 2 transactions updated, got %d (%d requests)", updated, len(httpClient.updated))
+		}
+		for i, id := range []string{"txn_001", "txn_003"} {
+			input := httpClient.updated[i]
+			if input["ConditionExpression"] != "attribute_exists(id) AND attribute_not_exists(list_bucket)" {
+				t.Errorf("Expected an update conditioned on a missing bucket, got %v", input["ConditionExpression"])
+			}
+			bucket := input["ExpressionAttributeValues"].(map[string]any)[":list_bucket"].(map[string]any)["S"]
+			if bucket != transactionListBucket(id) {
+				t.Errorf("Expected %s in bucket %s, got %v", id, transactionListBucket(id), bucket)
+			}
+		}
+	})
+
+	t.Run("should return error on service failure", func(t *testing.T) {
+		repo := NewDynamoDBTransactionRepository(newScanDynamoDBClient(&errorHTTPClient{}), "transactions", nil, zerolog.Nop())
+
+		if _, err := repo.BackfillListBuckets(context.Background()); err == nil {
+			t.Error("Expected an error, got nil")
+		}
+	})
+}
+
+// newCapturingQueryClient creates a DynamoDB client answering with httpClient that
+// captures every QueryInput via middleware.
+func newCapturingQueryClient(httpClient aws.HTTPClient, captured *[]dynamodb.QueryInput) *dynamodb.Client {
+	return dynamodb.NewFromConfig(aws.Config{Region: "us-east-1"}, func(o *dynamodb.Options) {
+		o.BaseEndpoint = aws.String("http://localhost:8000")
+		o.HTTPClient = httpClient
+		o.APIOptions = append(o.APIOptions, func(stack *smithymiddleware.Stack) error {
+			return stack.Initialize.Add(smithymiddleware.InitializeMiddlewareFunc(
+				"CaptureQuery",
+				func(ctx context.Context, in smithymiddleware.InitializeInput, next smithymiddleware.InitializeHandler) (smithymiddleware.InitializeOutput, smithymiddleware.Metadata, error) {
+					if input, ok := in.Parameters.(*dynamodb.QueryInput); ok {
+						*captured = append(*captured, *input)
+					}
+					return next.HandleInitialize(ctx, in)
+				},
+			), smithymiddleware.Before)
+		})
+	})
+}
+
+var testCursorSecret = []byte("test-cursor-secret")
+
+func newListedTransactionItem(id, createdAt string) transactionItem {
+	return transactionItem{
+		ID: id, AmountInCents: 1000, Currency: "USD", PaymentMethod: "CARD",
+		CustomerID: "cust_1", Status: entity.APPROVED, CreatedAt: createdAt, UpdatedAt: createdAt,
+		ListBucket: transactionListBucket(id),
+	}
+}
+
+// transactionWireItem converts a transaction item to the DynamoDB JSON format.
+// t is a *testing.T or a *rapid.T.
+func transactionWireItem(t interface {
+	Helper()
+	Fatalf(format string, args ...any)
+}, item transactionItem) map[string]map[string]string {
+	t.Helper()
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		t.Fatalf("Failed to marshal item: %v", err)
+	}
+	wireItem := map[string]map[string]string{}
+	for name, value := range av {
+		switch v := value.(type) {
+		case *types.AttributeValueMemberS:
+			wireItem[name] = map[string]string{"S": v.Value}
+		case *types.AttributeValueMemberN:
+			wireItem[name] = map[string]string{"N": v.Value}
+		}
+	}
+	return wireItem
+}
+
+// transactionQueryResponseJSON builds a DynamoDB Query JSON response body with
+// the given items and, when lastKeyID is set, a LastEvaluatedKey.
+func transactionQueryResponseJSON(t *testing.T, lastKeyID string, items ...transactionItem) string {
+	t.Helper()
+	wireItems := make([]map[string]map[string]string, 0, len(items))
+	for _, item := range items {
+		wireItems = append(wireItems, transactionWireItem(t, item))
+	}
+
+	response := map[string]any{"Count": len(wireItems), "Items": wireItems}
+	if lastKeyID != "" {
+		response["LastEvaluatedKey"] = map[string]map[string]string{"id": {"S": lastKeyID}}
+	}
+	body, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Failed to build query response: %v", err)
+	}
+	return string(body)
+}
+
+func TestFindAllPaginated(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC)
+	minAmount := int64(500)
+
+	t.Run("queries the customer index with the other filters", func(t *testing.T) {
+		var captured []dynamodb.QueryInput
+		client := newCapturingQueryClient(&sequentialHTTPClient{responses: []string{
+			scanResponseJSON([]transactionItem{newListedTransactionItem("txn_001", "2025-01-10T10:00:00Z")}, false, ""),
+		}}, &captured)
+		repo := NewDynamoDBTransactionRepository(client, "transactions", testCursorSecret, zerolog.Nop())
+
+		transactions, cursor, err := repo.FindAllPaginated(context.Background(), entity.TransactionQuery{
+			Filter: entity.TransactionFilter{
+				CustomerID:       "cust_1",
+				Status:           entity.APPROVED,
+				Currency:         entity.USD,
+				MinAmountInCents: &minAmount,
+				CreatedFrom:      &from,
+				CreatedTo:        &to,
+			},
+			Order: entity.SortAscending,
+			Limit: 10,
+		})
+		if err != nil {
+			t.Fatalf("FindAllPaginated returned unexpected error: %v", err)
+		}
+		if len(transactions) != 1 || cursor != "" {
+			t.Errorf("Expected one transaction and no cursor, got %d %q", len(transactions), cursor)
+		}
+
+		if len(captured) != 1 {
+			t.Fatalf("Expected 1 query, got %d", len(captured))
+		}
+		input := captured[0]
+		if aws.ToString(input.IndexName) != "customer_id-created_at-index" {
+			t.Errorf("Expected the customer index, got %q", aws.ToString(input.IndexName))
+		}
+		if key := aws.ToString(input.KeyConditionExpression); key != "#customer_id = :customer_id AND #created_at BETWEEN :created_from AND :created_to" {
+			t.Errorf("Unexpected key condition %q", key)
+		}
+		if filter := aws.ToString(input.FilterExpression); filter != "#status = :status AND #currency = :currency AND #amount_in_cents >= :min_amount" {
+			t.Errorf("Unexpected filter expression %q", filter)
+		}
+		if v := input.ExpressionAttributeValues[":created_to"].(*types.AttributeValueMemberS).Value; v != "2025-01-31T23:59:59Z" {
+			t.Errorf("Expected created_to 2025-01-31T23:59:59Z, got %s", v)
+		}
+		if !aws.ToBool(input.ScanIndexForward) {
+			t.Error("Expected an ascending query")
+		}
+	})
+
+	t.Run("queries the status index newest first", func(t *testing.T) {
+		var captured []dynamodb.QueryInput
+		client := newCapturingQueryClient(&sequentialHTTPClient{responses: []string{
+			scanResponseJSON(nil, false, ""),
+		}}, &captured)
+		repo := NewDynamoDBTransactionRepository(client, "transactions", testCursorSecret, zerolog.Nop())
+
+		_, _, err := repo.FindAllPaginated(context.Background(), entity.TransactionQuery{
+			Filter: entity.TransactionFilter{Status: entity.PENDING},
+			Order:  entity.SortDescending,
+			Limit:  10,
+		})
+		if err != nil {
+			t.Fatalf("FindAllPaginated returned unexpected error: %v", err)
+		}
+
+		input := captured[0]
+		if aws.ToString(input.IndexName) != "status-created_at-index" || aws.ToString(input.KeyConditionExpression) != "#status = :status" {
+			t.Errorf("Expected a query of the status index, got %q %q", aws.ToString(input.IndexName), aws.ToString(input.KeyConditionExpression))
+		}
+		if input.FilterExpression != nil || aws.ToBool(input.ScanIndexForward) {
+			t.Errorf("Expected a descending query without filter, got %q %v", aws.ToString(input.FilterExpression), aws.ToBool(input.ScanIndexForward))
+		}
+	})
+
+	t.Run("merges the bucket partitions and resumes each from the cursor", func(t *testing.T) {
+		var captured []dynamodb.QueryInput
+		client := newCapturingQueryClient(&sequentialHTTPClient{responses: []string{
+			transactionQueryResponseJSON(t, "", newListedTransactionItem("txn_001", "2025-01-10T10:00:00Z")),
+			transactionQueryResponseJSON(t, "txn_004",
+				newListedTransactionItem("txn_002", "2025-01-12T10:00:00Z"),
+				newListedTransactionItem("txn_004", "2025-01-09T10:00:00Z"),
+			),
+			transactionQueryResponseJSON(t, ""),
+			transactionQueryResponseJSON(t, "", newListedTransactionItem("txn_003", "2025-01-11T10:00:00Z")),
+			transactionQueryResponseJSON(t, "", newListedTransactionItem("txn_001", "2025-01-10T10:00:00Z")),
+			transactionQueryResponseJSON(t, "", newListedTransactionItem("txn_004", "2025-01-09T10:00:00Z")),
+		}}, &captured)
+		repo := NewDynamoDBTransactionRepository(client, "transactions", testCursorSecret, zerolog.Nop())
+
+		query := entity.TransactionQuery{
+			Filter: entity.TransactionFilter{PaymentMethod: entity.CARD},
+			Order:  entity.SortDescending,
+			Limit:  2,
+		}
+		transactions, cursor, err := repo.FindAllPaginated(context.Background(), query)
+		if err != nil {
+			t.Fatalf("FindAllPaginated returned unexpected error: %v", err)
+		}
+		if len(transactions) != 2 || transactions[0].ID != "txn_002" || transactions[1].ID != "txn_003" {
+			t.Fatalf("Expected txn_002 then txn_003, got %+v", transactions)
+		}
+		if len(captured) != transactionListBuckets {
+			t.Fatalf("Expected one query per bucket, got %d", len(captured))
+		}
+		for i, input := range captured {
+			if aws.ToString(input.IndexName) != "list_bucket-created_at-index" || aws.ToString(input.KeyConditionExpression) != "#list_bucket = :list_bucket" {
+				t.Errorf("Expected a query of the bucket index, got %q %q", aws.ToString(input.IndexName), aws.ToString(input.KeyConditionExpression))
+			}
+			if bucket := input.ExpressionAttributeValues[":list_bucket"].(*types.AttributeValueMemberS).Value; bucket != fmt.Sprint(i) {
+				t.Errorf("Expected bucket %d, got %s", i, bucket)
+			}
+		}
+		if aws.ToString(captured[0].FilterExpression) != "#payment_method = :payment_method" {
+			t.Errorf("Unexpected filter expression %q", aws.ToString(captured[0].FilterExpression))
+		}
+		if cursor == "" {
+			t.Fatal("Expected a cursor, buckets 0 and 1 have more items")
+		}
+
+		query.Cursor = cursor
+		query.Limit = 5
+		transactions, cursor, err = repo.FindAllPaginated(context.Background(), query)
+		if err != nil {
+			t.Fatalf("FindAllPaginated returned unexpected error for the next page: %v", err)
+		}
+		if len(transactions) != 2 || transactions[0].ID != "txn_001" || transactions[1].ID != "txn_004" || cursor != "" {
+			t.Fatalf("Expected txn_001 then txn_004 and no cursor, got %+v %q", transactions, cursor)
+		}
+		if len(captured) != transactionListBuckets+2 {
+			t.Fatalf("Expected only the unfinished buckets to be queried, got %d queries", len(captured)-transactionListBuckets)
+		}
+		if captured[4].ExclusiveStartKey != nil {
+			t.Errorf("Expected bucket 0 to restart, nothing was returned from it")
+		}
+		if id := captured[5].ExclusiveStartKey["id"].(*types.AttributeValueMemberS).Value; id != "txn_002" {
+			t.Errorf("Expected bucket 1 to resume after txn_002, got %s", id)
+		}
+	})
+
+	t.Run("rejects a cursor of other filters or another secret", func(t *testing.T) {
+		client := newScanDynamoDBClient(&sequentialHTTPClient{responses: []string{
+			transactionQueryResponseJSON(t, "txn_001", newListedTransactionItem("txn_001", "2025-01-10T10:00:00Z")),
+		}})
+		query := entity.TransactionQuery{Filter: entity.TransactionFilter{Status: entity.APPROVED}, Limit: 1}
+
+		_, cursor, err := NewDynamoDBTransactionRepository(client, "transactions", testCursorSecret, zerolog.Nop()).FindAllPaginated(context.Background(), query)
+		if err != nil || cursor == "" {
+			t.Fatalf("Expected a cursor, got %q %v", cursor, err)
+		}
+
+		query.Cursor = cursor
+		repo := NewDynamoDBTransactionRepository(client, "transactions", []byte("other-secret"), zerolog.Nop())
+		if _, _, err := repo.FindAllPaginated(context.Background(), query); !errors.Is(err, entity.ErrCursorMalformed) {
+			t.Errorf("Expected ErrCursorMalformed for a cursor signed with another secret, got %v", err)
+		}
+
+		repo = NewDynamoDBTransactionRepository(client, "transactions", testCursorSecret, zerolog.Nop())
+		query.Filter.Status = entity.DECLINED
+		if _, _, err := repo.FindAllPaginated(context.Background(), query); !errors.Is(err, entity.ErrCursorMalformed) {
+			t.Errorf("Expected ErrCursorMalformed for a cursor of other filters, got %v", err)
+		}
+	})
+
+	t.Run("rejects a malformed cursor", func(t *testing.T) {
+		repo := NewDynamoDBTransactionRepository(newScanDynamoDBClient(&fakeHTTPClient{}), "transactions", testCursorSecret, zerolog.Nop())
+
+		payload := base64.RawURLEncoding.EncodeToString([]byte(`{"key":{"id":"txn_001"}}`))
+		for _, cursor := range []string{"not base64!", "bm90IGpzb24=", "e30=", payload, payload + "." + repo.signTransactionCursor(payload+"x")} {
+			_, _, err := repo.FindAllPaginated(context.Background(), entity.TransactionQuery{Limit: 10, Cursor: cursor})
+			if !errors.Is(err, entity.ErrCursorMalformed) {
+				t.Errorf("cursor %q: expected ErrCursorMalformed, got %v", cursor, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,503 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// transactionStatsHourLayout is the period of the counters of an hour.
+	transactionStatsHourLayout = "2006-01-02T15"
+	// transactionStatsTotalPeriod is the period of the overall counters. It sorts
+	// after every hour, so it is never read as one.
+	transactionStatsTotalPeriod = "all"
+	// transactionStatsReplaceShard is the shard the counters are written to by
+	// Replace.
+	transactionStatsReplaceShard = "0"
+)
+
+// Counter attributes of a stats item. Statuses, payment methods, currencies,
+// amounts and decision paths have an attribute each, named by a prefix and the
+// status, payment method, currency or decision path. Latency sketch buckets have
+// an attribute each too, named by a prefix, the status, payment method or
+// currency, and the bucket index, as latency_status_APPROVED_371. The counts
+// broken down by a second dimension are named by a prefix and both keys joined by
+// a dot, as by_payment_method_status_BANK_TRANSFER.NEEDS_REVIEW, since either may
+// contain underscores.
+const (
+	statsCreatedAttribute           = "created"
+	statsFinalizedAttribute         = "finalized"
+	statsLatencySumAttribute        = "latency_sum_ms"
+	statsStatusPrefix               = "status_"
+	statsPaymentMethodPrefix        = "payment_method_"
+	statsCurrencyPrefix             = "currency_"
+	statsAmountPrefix               = "amount_in_cents_"
+	statsDecisionPathPrefix         = "decision_path_"
+	statsLatencyStatusPrefix        = "latency_status_"
+	statsLatencyPaymentMethodPrefix = "latency_payment_method_"
+	statsLatencyCurrencyPrefix      = "latency_currency_"
+	statsStatusAmountPrefix         = "by_status_amount_in_cents_"
+	statsPaymentMethodStatusPrefix  = "by_payment_method_status_"
+	statsPaymentMethodAmountPrefix  = "by_payment_method_amount_in_cents_"
+	statsCurrencyStatusPrefix       = "by_currency_status_"
+)
+
+// maxBatchWriteItems is the most requests DynamoDB accepts in one BatchWriteItem.
+const maxBatchWriteItems = 25
+
+// maxBatchWriteAttempts is how many times the unprocessed requests of a batch are
+// submitted before giving up.
+const maxBatchWriteAttempts = 5
+
+// DynamoDBTransactionStatsRepository implements repository.TransactionStatsRepository
+// using AWS DynamoDB. The table is keyed by shard (hash) and period (range): the
+// counters of each hour and the overall counters are spread over the same shards
+// as the list_bucket of the transactions, so concurrent updates do not contend on
+// one item, and the shards are summed when read.
+type DynamoDBTransactionStatsRepository struct {
+	client    *dynamodb.Client
+	tableName string
+	logger    zerolog.Logger
+}
+
+// NewDynamoDBTransactionStatsRepository creates a new DynamoDB-backed
+// transaction stats repository.
+func NewDynamoDBTransactionStatsRepository(
+	client *dynamodb.Client,
+	tableName string,
+	logger zerolog.Logger,
+) *DynamoDBTransactionStatsRepository {
+	return &DynamoDBTransactionStatsRepository{
+		client:    client,
+		tableName: tableName,
+		logger:    logger,
+	}
+}
+
+// Add atomically adds delta to the hour item and to the overall item of the
+// transaction's shard. The two updates are separate writes: if the second fails,
+// the first is kept and the error is returned.
+func (r *DynamoDBTransactionStatsRepository) Add(ctx context.Context, transaction *entity.TransactionEntity, delta entity.TransactionCounters) error {
+	attributes := counterAttributes(delta)
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	names := slices.Sorted(maps.Keys(attributes))
+	terms := make([]string, len(names))
+	exprNames := make(map[string]string, len(names))
+	exprValues := make(map[string]types.AttributeValue, len(names))
+	for i, name := range names {
+		exprNames[fmt.Sprintf("#c%d", i)] = name
+		exprValues[fmt.Sprintf(":c%d", i)] = &types.AttributeValueMemberN{Value: strconv.FormatInt(attributes[name], 10)}
+		terms[i] = fmt.Sprintf("#c%d :c%d", i, i)
+	}
+
+	shard := transactionListBucket(transaction.ID)
+	for _, period := range []string{transactionStatsHour(transaction.CreatedAt), transactionStatsTotalPeriod} {
+		_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                 aws.String(r.tableName),
+			Key:                       transactionStatsKey(shard, period),
+			UpdateExpression:          aws.String("ADD " + strings.Join(terms, ", ")),
+			ExpressionAttributeNames:  exprNames,
+			ExpressionAttributeValues: exprValues,
+		})
+		if err != nil {
+			r.logger.Error().Err(err).
+				Str("transaction_id", transaction.ID).
+				Str("period", period).
+				Str("table", r.tableName).
+				Msg("failed to update transaction stats")
+			return fmt.Errorf("failed to update transaction stats: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// FindHours queries every shard for the hours from from to to and sums them.
+func (r *DynamoDBTransactionStatsRepository) FindHours(ctx context.Context, from, to time.Time) ([]entity.HourlyTransactionCounters, error) {
+	counters := map[string]entity.TransactionCounters{}
+
+	for shard := range transactionListBuckets {
+		input := &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			KeyConditionExpression: aws.String("#shard = :shard AND #period BETWEEN :from AND :to"),
+			ExpressionAttributeNames: map[string]string{
+				"#shard":  "shard",
+				"#period": "period",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":shard": &types.AttributeValueMemberS{Value: strconv.Itoa(shard)},
+				":from":  &types.AttributeValueMemberS{Value: transactionStatsHour(from)},
+				":to":    &types.AttributeValueMemberS{Value: transactionStatsHour(to)},
+			},
+		}
+
+		for {
+			output, err := r.client.Query(ctx, input)
+			if err != nil {
+				r.logger.Error().Err(err).Int("shard", shard).Str("table", r.tableName).Msg("failed to query transaction stats")
+				return nil, fmt.Errorf("failed to query transaction stats: %w", err)
+			}
+
+			for _, item := range output.Items {
+				period, c, err := fromTransactionStatsItem(item)
+				if err != nil {
+					return nil, err
+				}
+				sum := counters[period]
+				sum.Add(c)
+				counters[period] = sum
+			}
+
+			if output.LastEvaluatedKey == nil {
+				break
+			}
+			input.ExclusiveStartKey = output.LastEvaluatedKey
+		}
+	}
+
+	hours := make([]entity.HourlyTransactionCounters, 0, len(counters))
+	for _, period := range slices.Sorted(maps.Keys(counters)) {
+		hour, err := time.Parse(transactionStatsHourLayout, period)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse transaction stats period %q: %w", period, err)
+		}
+		hours = append(hours, entity.HourlyTransactionCounters{Hour: hour, Counters: counters[period]})
+	}
+
+	return hours, nil
+}
+
+// Total reads the overall item of every shard and sums them.
+func (r *DynamoDBTransactionStatsRepository) Total(ctx context.Context) (entity.TransactionCounters, error) {
+	total := entity.NewTransactionCounters()
+
+	for shard := range transactionListBuckets {
+		output, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName: aws.String(r.tableName),
+			Key:       transactionStatsKey(strconv.Itoa(shard), transactionStatsTotalPeriod),
+		})
+		if err != nil {
+			r.logger.Error().Err(err).Int("shard", shard).Str("table", r.tableName).Msg("failed to get transaction stats")
+			return entity.TransactionCounters{}, fmt.Errorf("failed to get transaction stats: %w", err)
+		}
+		if output.Item == nil {
+			continue
+		}
+
+		_, c, err := fromTransactionStatsItem(output.Item)
+		if err != nil {
+			return entity.TransactionCounters{}, err
+		}
+		total.Add(c)
+	}
+
+	return total, nil
+}
+
+// Replace writes the hours and their sum to a single shard, then deletes every
+// other item. It is not atomic: the counters are inconsistent until it returns.
+func (r *DynamoDBTransactionStatsRepository) Replace(ctx context.Context, hours []entity.HourlyTransactionCounters) error {
+	existing, err := r.scanKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	total := entity.NewTransactionCounters()
+	written := map[string]bool{}
+	requests := make([]types.WriteRequest, 0, len(hours)+1+len(existing))
+	for _, hour := range hours {
+		period := transactionStatsHour(hour.Hour)
+		total.Add(hour.Counters)
+		written[period] = true
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{
+			Item: toTransactionStatsItem(transactionStatsReplaceShard, period, hour.Counters),
+		}})
+	}
+	written[transactionStatsTotalPeriod] = true
+	requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{
+		Item: toTransactionStatsItem(transactionStatsReplaceShard, transactionStatsTotalPeriod, total),
+	}})
+
+	for _, key := range existing {
+		shard := key["shard"].(*types.AttributeValueMemberS).Value
+		period := key["period"].(*types.AttributeValueMemberS).Value
+		if shard == transactionStatsReplaceShard && written[period] {
+			continue
+		}
+		requests = append(requests, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: key}})
+	}
+
+	return r.batchWrite(ctx, requests)
+}
+
+// scanKeys returns the key of every item of the table.
+func (r *DynamoDBTransactionStatsRepository) scanKeys(ctx context.Context) ([]map[string]types.AttributeValue, error) {
+	input := &dynamodb.ScanInput{
+		TableName:                aws.String(r.tableName),
+		ProjectionExpression:     aws.String("#shard, #period"),
+		ExpressionAttributeNames: map[string]string{"#shard": "shard", "#period": "period"},
+	}
+
+	var keys []map[string]types.AttributeValue
+	for {
+		output, err := r.client.Scan(ctx, input)
+		if err != nil {
+			r.logger.Error().Err(err).Str("table", r.tableName).Msg("failed to scan transaction stats")
+			return nil, fmt.Errorf("failed to scan transaction stats: %w", err)
+		}
+		keys = append(keys, output.Items...)
+
+		if output.LastEvaluatedKey == nil {
+			return keys, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// batchWrite submits the requests in batches of maxBatchWriteItems, resubmitting
+// the unprocessed ones.
+func (r *DynamoDBTransactionStatsRepository) batchWrite(ctx context.Context, requests []types.WriteRequest) error {
+	for i := 0; i < len(requests); i += maxBatchWriteItems {
+		end := min(i+maxBatchWriteItems, len(requests))
+
+		pending := map[string][]types.WriteRequest{r.tableName: requests[i:end]}
+		for attempt := 1; len(pending[r.tableName]) > 0; attempt++ {
+			if attempt > maxBatchWriteAttempts {
+				r.logger.Warn().Str("table", r.tableName).
+					Int("unprocessed_count", len(pending[r.tableName])).
+					Msg("some transaction stats items were not processed")
+				return fmt.Errorf("unprocessed transaction stats items remain after batch write")
+			}
+
+			output, err := r.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: pending})
+			if err != nil {
+				r.logger.Error().Err(err).Str("table", r.tableName).
+					Int("batch_size", len(pending[r.tableName])).Msg("failed to batch write transaction stats")
+				return fmt.Errorf("failed to batch write transaction stats: %w", err)
+			}
+			pending = output.UnprocessedItems
+		}
+	}
+
+	return nil
+}
+
+// transactionStatsHour returns the period of the hour t is in.
+func transactionStatsHour(t time.Time) string {
+	return t.UTC().Format(transactionStatsHourLayout)
+}
+
+func transactionStatsKey(shard, period string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"shard":  &types.AttributeValueMemberS{Value: shard},
+		"period": &types.AttributeValueMemberS{Value: period},
+	}
+}
+
+// counterAttributes returns the non-zero counters by attribute name.
+func counterAttributes(c entity.TransactionCounters) map[string]int64 {
+	attributes := map[string]int64{}
+	set := func(name string, n int64) {
+		if n != 0 {
+			attributes[name] = n
+		}
+	}
+
+	set(statsCreatedAttribute, int64(c.Created))
+	set(statsFinalizedAttribute, int64(c.Finalized))
+	set(statsLatencySumAttribute, c.LatencySumMs)
+	for status, n := range c.Statuses {
+		set(statsStatusPrefix+string(status), int64(n))
+	}
+	for method, n := range c.PaymentMethods {
+		set(statsPaymentMethodPrefix+string(method), int64(n))
+	}
+	for currency, n := range c.Currencies {
+		set(statsCurrencyPrefix+string(currency), int64(n))
+	}
+	for currency, amount := range c.AmountsInCents {
+		set(statsAmountPrefix+string(currency), amount)
+	}
+	for path, n := range c.DecisionPaths {
+		set(statsDecisionPathPrefix+string(path), int64(n))
+	}
+	for status, sketch := range c.LatencyByStatus {
+		for i, n := range sketch {
+			set(latencySketchAttribute(statsLatencyStatusPrefix, string(status), i), int64(n))
+		}
+	}
+	for method, sketch := range c.LatencyByPaymentMethod {
+		for i, n := range sketch {
+			set(latencySketchAttribute(statsLatencyPaymentMethodPrefix, string(method), i), int64(n))
+		}
+	}
+	for currency, sketch := range c.LatencyByCurrency {
+		for i, n := range sketch {
+			set(latencySketchAttribute(statsLatencyCurrencyPrefix, string(currency), i), int64(n))
+		}
+	}
+	for status, amounts := range c.AmountsByStatus {
+		for currency, amount := range amounts {
+			set(breakdownAttribute(statsStatusAmountPrefix, string(status), string(currency)), amount)
+		}
+	}
+	for method, statuses := range c.StatusesByPaymentMethod {
+		for status, n := range statuses {
+			set(breakdownAttribute(statsPaymentMethodStatusPrefix, string(method), string(status)), int64(n))
+		}
+	}
+	for method, amounts := range c.AmountsByPaymentMethod {
+		for currency, amount := range amounts {
+			set(breakdownAttribute(statsPaymentMethodAmountPrefix, string(method), string(currency)), amount)
+		}
+	}
+	for currency, statuses := range c.StatusesByCurrency {
+		for status, n := range statuses {
+			set(breakdownAttribute(statsCurrencyStatusPrefix, string(currency), string(status)), int64(n))
+		}
+	}
+
+	return attributes
+}
+
+func toTransactionStatsItem(shard, period string, c entity.TransactionCounters) map[string]types.AttributeValue {
+	item := transactionStatsKey(shard, period)
+	for name, n := range counterAttributes(c) {
+		item[name] = &types.AttributeValueMemberN{Value: strconv.FormatInt(n, 10)}
+	}
+	return item
+}
+
+// latencySketchAttribute returns the name of the attribute of bucket i of the
+// latency sketch of key.
+func latencySketchAttribute(prefix, key string, i int) string {
+	return prefix + key + "_" + strconv.Itoa(i)
+}
+
+// parseLatencySketchAttribute returns the key and bucket index of a latency
+// sketch attribute named by latencySketchAttribute. Keys may contain
+// underscores, as NEEDS_REVIEW, so the index is after the last one.
+func parseLatencySketchAttribute(prefix, name string) (string, int, bool) {
+	rest := strings.TrimPrefix(name, prefix)
+	sep := strings.LastIndex(rest, "_")
+	if sep < 0 {
+		return "", 0, false
+	}
+	i, err := strconv.Atoi(rest[sep+1:])
+	return rest[:sep], i, err == nil
+}
+
+// breakdownAttribute returns the name of the attribute of the count of key2
+// among the transactions of key1.
+func breakdownAttribute(prefix, key1, key2 string) string {
+	return prefix + key1 + "." + key2
+}
+
+// parseBreakdownAttribute returns the keys of an attribute named by
+// breakdownAttribute.
+func parseBreakdownAttribute(prefix, name string) (string, string, bool) {
+	return strings.Cut(strings.TrimPrefix(name, prefix), ".")
+}
+
+// fromTransactionStatsItem returns the period and counters of a stats item.
+// Attributes it does not know of, as those of the latency histogram it used to
+// keep, are ignored.
+func fromTransactionStatsItem(item map[string]types.AttributeValue) (string, entity.TransactionCounters, error) {
+	c := entity.NewTransactionCounters()
+
+	var period string
+	if v, ok := item["period"].(*types.AttributeValueMemberS); ok {
+		period = v.Value
+	}
+
+	for name, value := range item {
+		v, ok := value.(*types.AttributeValueMemberN)
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(v.Value, 10, 64)
+		if err != nil {
+			return "", entity.TransactionCounters{}, fmt.Errorf("failed to parse transaction stats attribute %s of %q: %w", name, period, err)
+		}
+
+		switch {
+		case name == statsCreatedAttribute:
+			c.Created = int(n)
+		case name == statsFinalizedAttribute:
+			c.Finalized = int(n)
+		case name == statsLatencySumAttribute:
+			c.LatencySumMs = n
+		case strings.HasPrefix(name, statsStatusPrefix):
+			c.Statuses[entity.TransactionStatus(strings.TrimPrefix(name, statsStatusPrefix))] = int(n)
+		case strings.HasPrefix(name, statsPaymentMethodPrefix):
+			c.PaymentMethods[entity.PaymentMethod(strings.TrimPrefix(name, statsPaymentMethodPrefix))] = int(n)
+		case strings.HasPrefix(name, statsCurrencyPrefix):
+			c.Currencies[entity.Currency(strings.TrimPrefix(name, statsCurrencyPrefix))] = int(n)
+		case strings.HasPrefix(name, statsAmountPrefix):
+			c.AmountsInCents[entity.Currency(strings.TrimPrefix(name, statsAmountPrefix))] = n
+		case strings.HasPrefix(name, statsDecisionPathPrefix):
+			c.DecisionPaths[entity.DecisionPath(strings.TrimPrefix(name, statsDecisionPathPrefix))] = int(n)
+		case strings.HasPrefix(name, statsLatencyStatusPrefix):
+			if status, i, ok := parseLatencySketchAttribute(statsLatencyStatusPrefix, name); ok {
+				addLatencySketchBucket(c.LatencyByStatus, entity.TransactionStatus(status), i, int(n))
+			}
+		case strings.HasPrefix(name, statsLatencyPaymentMethodPrefix):
+			if method, i, ok := parseLatencySketchAttribute(statsLatencyPaymentMethodPrefix, name); ok {
+				addLatencySketchBucket(c.LatencyByPaymentMethod, entity.PaymentMethod(method), i, int(n))
+			}
+		case strings.HasPrefix(name, statsLatencyCurrencyPrefix):
+			if currency, i, ok := parseLatencySketchAttribute(statsLatencyCurrencyPrefix, name); ok {
+				addLatencySketchBucket(c.LatencyByCurrency, entity.Currency(currency), i, int(n))
+			}
+		case strings.HasPrefix(name, statsStatusAmountPrefix):
+			if status, currency, ok := parseBreakdownAttribute(statsStatusAmountPrefix, name); ok {
+				setBreakdownCount(c.AmountsByStatus, entity.TransactionStatus(status), entity.Currency(currency), n)
+			}
+		case strings.HasPrefix(name, statsPaymentMethodStatusPrefix):
+			if method, status, ok := parseBreakdownAttribute(statsPaymentMethodStatusPrefix, name); ok {
+				setBreakdownCount(c.StatusesByPaymentMethod, entity.PaymentMethod(method), entity.TransactionStatus(status), int(n))
+			}
+		case strings.HasPrefix(name, statsPaymentMethodAmountPrefix):
+			if method, currency, ok := parseBreakdownAttribute(statsPaymentMethodAmountPrefix, name); ok {
+				setBreakdownCount(c.AmountsByPaymentMethod, entity.PaymentMethod(method), entity.Currency(currency), n)
+			}
+		case strings.HasPrefix(name, statsCurrencyStatusPrefix):
+			if currency, status, ok := parseBreakdownAttribute(statsCurrencyStatusPrefix, name); ok {
+				setBreakdownCount(c.StatusesByCurrency, entity.Currency(currency), entity.TransactionStatus(status), int(n))
+			}
+		}
+	}
+
+	return period, c, nil
+}
+
+func addLatencySketchBucket[K comparable](sketches map[K]entity.LatencySketch, key K, i, n int) {
+	if n == 0 {
+		return
+	}
+	if sketches[key] == nil {
+		sketches[key] = entity.LatencySketch{}
+	}
+	sketches[key][i] = n
+}
+
+func setBreakdownCount[K1, K2 comparable, V int | int64](counts map[K1]map[K2]V, key1 K1, key2 K2, n V) {
+	if counts[key1] == nil {
+		counts[key1] = make(map[K2]V)
+	}
+	counts[key1][key2] = n
+}
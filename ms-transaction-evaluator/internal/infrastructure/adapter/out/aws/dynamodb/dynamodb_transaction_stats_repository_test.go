@@ -0,0 +1,274 @@
+package dynamodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// fakeStatsTable is an in-memory stats table answering the UpdateItem, Query,
+// GetItem, Scan and BatchWriteItem requests of DynamoDBTransactionStatsRepository.
+// Its items are keyed by shard and period.
+type fakeStatsTable struct {
+	t     *testing.T
+	items map[[2]string]map[string]map[string]string
+	// batchSizes records the number of requests of each BatchWriteItem.
+	batchSizes []int
+}
+
+func newFakeStatsTable(t *testing.T) *fakeStatsTable {
+	return &fakeStatsTable{t: t, items: map[[2]string]map[string]map[string]string{}}
+}
+
+type fakeStatsRequest struct {
+	Key                       map[string]map[string]string
+	UpdateExpression          string
+	KeyConditionExpression    string
+	ExpressionAttributeNames  map[string]string
+	ExpressionAttributeValues map[string]map[string]string
+	RequestItems              map[string][]struct {
+		PutRequest    *struct{ Item map[string]map[string]string }
+		DeleteRequest *struct{ Key map[string]map[string]string }
+	}
+}
+
+func fakeStatsKey(item map[string]map[string]string) [2]string {
+	return [2]string{item["shard"]["S"], item["period"]["S"]}
+}
+
+func (f *fakeStatsTable) Do(req *http.Request) (*http.Response, error) {
+	var request fakeStatsRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		f.t.Fatalf("failed to decode request: %v", err)
+	}
+
+	response := map[string]any{}
+	switch target := strings.TrimPrefix(req.Header.Get("X-Amz-Target"), "DynamoDB_20120810."); target {
+	case "UpdateItem":
+		key := fakeStatsKey(request.Key)
+		item, ok := f.items[key]
+		if !ok {
+			item = request.Key
+			f.items[key] = item
+		}
+		for _, term := range strings.Split(strings.TrimPrefix(request.UpdateExpression, "ADD "), ", ") {
+			name, value, _ := strings.Cut(term, " ")
+			attribute := request.ExpressionAttributeNames[name]
+			current, _ := strconv.ParseInt(item[attribute]["N"], 10, 64)
+			delta, _ := strconv.ParseInt(request.ExpressionAttributeValues[value]["N"], 10, 64)
+			item[attribute] = map[string]string{"N": strconv.FormatInt(current+delta, 10)}
+		}
+	case "GetItem":
+		if item, ok := f.items[fakeStatsKey(request.Key)]; ok {
+			response["Item"] = item
+		}
+	case "Query":
+		shard := request.ExpressionAttributeValues[":shard"]["S"]
+		from, to := request.ExpressionAttributeValues[":from"]["S"], request.ExpressionAttributeValues[":to"]["S"]
+		items := []map[string]map[string]string{}
+		for key, item := range f.items {
+			if key[0] == shard && key[1] >= from && key[1] <= to {
+				items = append(items, item)
+			}
+		}
+		response["Items"] = items
+	case "Scan":
+		items := []map[string]map[string]string{}
+		for _, item := range f.items {
+			items = append(items, map[string]map[string]string{"shard": item["shard"], "period": item["period"]})
+		}
+		response["Items"] = items
+	case "BatchWriteItem":
+		for _, requests := range request.RequestItems {
+			f.batchSizes = append(f.batchSizes, len(requests))
+			for _, r := range requests {
+				if r.PutRequest != nil {
+					f.items[fakeStatsKey(r.PutRequest.Item)] = r.PutRequest.Item
+				}
+				if r.DeleteRequest != nil {
+					delete(f.items, fakeStatsKey(r.DeleteRequest.Key))
+				}
+			}
+		}
+	default:
+		f.t.Fatalf("unexpected DynamoDB operation %s", target)
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		f.t.Fatalf("failed to encode response: %v", err)
+	}
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.0"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}, nil
+}
+
+func newStatsTransaction(id string, createdAt time.Time, status entity.TransactionStatus, latency time.Duration) *entity.TransactionEntity {
+	transaction := &entity.TransactionEntity{
+		ID:            id,
+		AmountInCents: 1000,
+		Currency:      entity.USD,
+		PaymentMethod: entity.CARD,
+		Status:        status,
+		CreatedAt:     createdAt,
+		UpdatedAt:     createdAt,
+	}
+	if latency > 0 {
+		finalizedAt := createdAt.Add(latency)
+		transaction.FinalizedAt = &finalizedAt
+		transaction.UpdatedAt = finalizedAt
+	}
+	return transaction
+}
+
+func TestTransactionStatsItem_RoundTrip(t *testing.T) {
+	finalized := newStatsTransaction("txn_001", time.Now(), entity.APPROVED, 2500*time.Millisecond)
+	finalized.DecisionPath = entity.DecisionPathFraudCheck
+	review := newStatsTransaction("txn_003", time.Now(), entity.NEEDS_REVIEW, 2*time.Hour)
+	review.PaymentMethod = entity.BANK_TRANSFER
+	review.Currency = entity.EUR
+	want := entity.CountTransaction(finalized)
+	want.Add(entity.CountTransaction(newStatsTransaction("txn_002", time.Now(), entity.DECLINED, 90*time.Second)))
+	want.Add(entity.CountTransaction(review))
+
+	period, got, err := fromTransactionStatsItem(toTransactionStatsItem("2", "2025-01-01T10", want))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if period != "2025-01-01T10" {
+		t.Errorf("expected period 2025-01-01T10, got %s", period)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestDynamoDBTransactionStatsRepository(t *testing.T) {
+	ctx := context.Background()
+	hour := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("adds to the hour and the total, summed over shards", func(t *testing.T) {
+		table := newFakeStatsTable(t)
+		repo := NewDynamoDBTransactionStatsRepository(newScanDynamoDBClient(table), "stats", zerolog.Nop())
+
+		transactions := []*entity.TransactionEntity{
+			newStatsTransaction("txn_a", hour.Add(5*time.Minute), entity.PENDING, 0),
+			newStatsTransaction("txn_b", hour.Add(50*time.Minute), entity.PENDING, 0),
+			newStatsTransaction("txn_c", hour.Add(2*time.Hour), entity.PENDING, 0),
+			newStatsTransaction("txn_d", hour.Add(-24*time.Hour), entity.PENDING, 0),
+		}
+		for _, transaction := range transactions {
+			if err := repo.Add(ctx, transaction, entity.CountTransaction(transaction)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		// txn_a is approved after 1.5s: PENDING -1, APPROVED +1, one finalized
+		approved := *transactions[0]
+		approved.Status = entity.APPROVED
+		finalizedAt := approved.CreatedAt.Add(1500 * time.Millisecond)
+		approved.FinalizedAt = &finalizedAt
+		approved.DecisionPath = entity.DecisionPathDirect
+		delta := entity.CountTransaction(&approved)
+		delta.Sub(entity.CountTransaction(transactions[0]))
+		if err := repo.Add(ctx, &approved, delta); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		hours, err := repo.FindHours(ctx, hour, hour.Add(2*time.Hour))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(hours) != 2 || !hours[0].Hour.Equal(hour) || !hours[1].Hour.Equal(hour.Add(2*time.Hour)) {
+			t.Fatalf("expected hours 10:00 and 12:00, got %+v", hours)
+		}
+		if c := hours[0].Counters; c.Created != 2 || c.Statuses[entity.APPROVED] != 1 || c.Statuses[entity.PENDING] != 1 || c.Finalized != 1 {
+			t.Errorf("unexpected counters of 10:00: %+v", c)
+		}
+
+		total, err := repo.Total(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if total.Created != 4 || total.Statuses[entity.PENDING] != 3 || total.PaymentMethods[entity.CARD] != 4 || total.AmountsInCents[entity.USD] != 4000 {
+			t.Errorf("unexpected total: %+v", total)
+		}
+		if total.LatencySumMs != 1500 || total.LatencyByStatus[entity.APPROVED].Count() != 1 || total.LatencyByPaymentMethod[entity.CARD][entity.LatencySketchIndex(1500)] != 1 {
+			t.Errorf("unexpected total latency: %d %v %v", total.LatencySumMs, total.LatencyByStatus, total.LatencyByPaymentMethod)
+		}
+		if total.DecisionPaths[entity.DecisionPathDirect] != 1 {
+			t.Errorf("expected one direct decision, got %v", total.DecisionPaths)
+		}
+	})
+
+	t.Run("replaces every counter in batches", func(t *testing.T) {
+		table := newFakeStatsTable(t)
+		repo := NewDynamoDBTransactionStatsRepository(newScanDynamoDBClient(table), "stats", zerolog.Nop())
+
+		stale := newStatsTransaction("txn_stale", hour.Add(-48*time.Hour), entity.PENDING, 0)
+		if err := repo.Add(ctx, stale, entity.CountTransaction(stale)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var hours []entity.HourlyTransactionCounters
+		for i := range 30 {
+			transaction := newStatsTransaction("txn_"+strconv.Itoa(i), hour.Add(time.Duration(i)*time.Hour), entity.DECLINED, time.Second)
+			hours = append(hours, entity.HourlyTransactionCounters{Hour: transaction.CreatedAt, Counters: entity.CountTransaction(transaction)})
+		}
+		if err := repo.Replace(ctx, hours); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !reflect.DeepEqual(table.batchSizes, []int{25, 8}) {
+			t.Errorf("expected batches of 25 and 8 requests, got %v", table.batchSizes)
+		}
+
+		total, err := repo.Total(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if total.Created != 30 || total.Statuses[entity.DECLINED] != 30 || total.Statuses[entity.PENDING] != 0 || total.Finalized != 30 {
+			t.Errorf("unexpected total: %+v", total)
+		}
+
+		found, err := repo.FindHours(ctx, hour.Add(-72*time.Hour), hour.Add(72*time.Hour))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(found) != 30 || !found[0].Hour.Equal(hour) {
+			t.Errorf("expected the 30 replaced hours, got %d starting %v", len(found), found[0].Hour)
+		}
+	})
+
+	t.Run("does not write a zero delta", func(t *testing.T) {
+		table := newFakeStatsTable(t)
+		repo := NewDynamoDBTransactionStatsRepository(newScanDynamoDBClient(table), "stats", zerolog.Nop())
+
+		if err := repo.Add(ctx, newStatsTransaction("txn_a", hour, entity.PENDING, 0), entity.NewTransactionCounters()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(table.items) != 0 {
+			t.Errorf("expected no item, got %d", len(table.items))
+		}
+	})
+
+	t.Run("returns the error of a failed read", func(t *testing.T) {
+		repo := NewDynamoDBTransactionStatsRepository(newScanDynamoDBClient(&errorHTTPClient{}), "stats", zerolog.Nop())
+
+		if _, err := repo.Total(ctx); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
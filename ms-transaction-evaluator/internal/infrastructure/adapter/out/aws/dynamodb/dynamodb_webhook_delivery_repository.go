@@ -0,0 +1,403 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// webhookDeliveryStatusIndex is the GSI of the deliveries table keyed by status
+// (hash) and next_attempt_at (range), used to read the due deliveries.
+const webhookDeliveryStatusIndex = "status-next_attempt_at-index"
+
+// finishedWebhookDeliveryRetention is how long delivered and failed deliveries
+// are kept before the table's TTL removes them.
+const finishedWebhookDeliveryRetention = 30 * 24 * time.Hour
+
+type webhookDeliveryItem struct {
+	ID             string `dynamodbav:"id"`
+	EndpointID     string `dynamodbav:"endpoint_id"`
+	URL            string `dynamodbav:"url"`
+	TransactionID  string `dynamodbav:"transaction_id"`
+	Event          string `dynamodbav:"event"`
+	Payload        string `dynamodbav:"payload"`
+	Status         string `dynamodbav:"status"`
+	Attempts       int    `dynamodbav:"attempts"`
+	LastStatusCode int    `dynamodbav:"last_status_code,omitempty"`
+	LastError      string `dynamodbav:"last_error,omitempty"`
+	CreatedAt      string `dynamodbav:"created_at"`
+	NextAttemptAt  string `dynamodbav:"next_attempt_at"`
+	DeliveredAt    string `dynamodbav:"delivered_at,omitempty"`
+	Log            string `dynamodbav:"log"`
+	TTL            int64  `dynamodbav:"ttl,omitempty"`
+}
+
+// webhookDeliveryCursor is the base64 JSON cursor of FindAll: the key of the last
+// delivery returned.
+type webhookDeliveryCursor struct {
+	ID string `json:"id"`
+}
+
+// DynamoDBWebhookDeliveryRepository implements repository.WebhookDeliveryRepository
+// using AWS DynamoDB. The table is keyed by id (hash) and read through the
+// status-next_attempt_at-index GSI by the deliverer; finished deliveries expire
+// through the ttl attribute.
+type DynamoDBWebhookDeliveryRepository struct {
+	client    *dynamodb.Client
+	tableName string
+	logger    zerolog.Logger
+	now       func() time.Time
+}
+
+// NewDynamoDBWebhookDeliveryRepository creates a new DynamoDB-backed webhook
+// delivery repository.
+func NewDynamoDBWebhookDeliveryRepository(
+	client *dynamodb.Client,
+	tableName string,
+	logger zerolog.Logger,
+) *DynamoDBWebhookDeliveryRepository {
+	return &DynamoDBWebhookDeliveryRepository{
+		client:    client,
+		tableName: tableName,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Create puts the delivery unless one with the same id exists.
+func (r *DynamoDBWebhookDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) (bool, error) {
+	av, err := r.marshal(delivery)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	})
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			r.logger.Info().Str("delivery_id", delivery.ID).Msg("webhook delivery already exists")
+			return false, nil
+		}
+
+		r.logger.Error().Err(err).Str("delivery_id", delivery.ID).Str("table", r.tableName).Msg("failed to create webhook delivery")
+		return false, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	return true, nil
+}
+
+// Update puts the delivery.
+func (r *DynamoDBWebhookDeliveryRepository) Update(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	av, err := r.marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      av,
+	}); err != nil {
+		r.logger.Error().Err(err).Str("delivery_id", delivery.ID).Str("table", r.tableName).Msg("failed to update webhook delivery")
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID gets the delivery, or nil if it does not exist.
+func (r *DynamoDBWebhookDeliveryRepository) FindByID(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	output, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Str("delivery_id", id).Str("table", r.tableName).Msg("failed to get webhook delivery")
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	if output.Item == nil {
+		return nil, nil
+	}
+
+	var item webhookDeliveryItem
+	if err := attributevalue.UnmarshalMap(output.Item, &item); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook delivery: %w", err)
+	}
+
+	delivery, err := toWebhookDelivery(item)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// FindDue queries the status index for the pending deliveries whose next attempt
+// is at or before now, earliest first.
+func (r *DynamoDBWebhookDeliveryRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	output, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(webhookDeliveryStatusIndex),
+		KeyConditionExpression: aws.String("#s = :pending AND next_attempt_at <= :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#s": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pending": &types.AttributeValueMemberS{Value: string(entity.WebhookDeliveryPending)},
+			":now":     &types.AttributeValueMemberS{Value: now.UTC().Format(outboxTimeFormat)},
+		},
+		ScanIndexForward: aws.Bool(true),
+		Limit:            aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Msg("failed to query due webhook deliveries")
+		return nil, fmt.Errorf("failed to query due webhook deliveries: %w", err)
+	}
+
+	return r.toWebhookDeliveries(output.Items)
+}
+
+// FindAll scans the table in key order until limit deliveries match the filter.
+// Each page is returned newest first.
+func (r *DynamoDBWebhookDeliveryRepository) FindAll(
+	ctx context.Context,
+	filter entity.WebhookDeliveryFilter,
+	limit int,
+	cursor string,
+) ([]entity.WebhookDelivery, string, error) {
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(r.tableName),
+		Limit:     aws.Int32(int32(limit)),
+	}
+	applyWebhookDeliveryFilter(input, filter)
+
+	if cursor != "" {
+		id, err := decodeWebhookDeliveryCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		input.ExclusiveStartKey = map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		}
+	}
+
+	var items []map[string]types.AttributeValue
+	var nextCursor string
+	for {
+		output, err := r.client.Scan(ctx, input)
+		if err != nil {
+			r.logger.Error().Err(err).Str("table", r.tableName).Msg("failed to scan webhook deliveries")
+			return nil, "", fmt.Errorf("failed to scan webhook deliveries: %w", err)
+		}
+
+		for i, item := range output.Items {
+			items = append(items, item)
+			if len(items) == limit {
+				// Resume after this item if the scan has more to read
+				if i < len(output.Items)-1 || len(output.LastEvaluatedKey) > 0 {
+					if nextCursor, err = encodeWebhookDeliveryCursor(item); err != nil {
+						return nil, "", err
+					}
+				}
+				break
+			}
+		}
+
+		if len(items) == limit || len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+
+	deliveries, err := r.toWebhookDeliveries(items)
+	if err != nil {
+		return nil, "", err
+	}
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+
+	return deliveries, nextCursor, nil
+}
+
+func (r *DynamoDBWebhookDeliveryRepository) marshal(delivery *entity.WebhookDelivery) (map[string]types.AttributeValue, error) {
+	item, err := toWebhookDeliveryItem(delivery)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status != entity.WebhookDeliveryPending {
+		item.TTL = r.now().Add(finishedWebhookDeliveryRetention).Unix()
+	}
+
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook delivery: %w", err)
+	}
+	return av, nil
+}
+
+func (r *DynamoDBWebhookDeliveryRepository) toWebhookDeliveries(avs []map[string]types.AttributeValue) ([]entity.WebhookDelivery, error) {
+	var items []webhookDeliveryItem
+	if err := attributevalue.UnmarshalListOfMaps(avs, &items); err != nil {
+		r.logger.Error().Err(err).Str("table", r.tableName).Msg("failed to unmarshal webhook deliveries")
+		return nil, fmt.Errorf("failed to unmarshal webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]entity.WebhookDelivery, 0, len(items))
+	for _, item := range items {
+		delivery, err := toWebhookDelivery(item)
+		if err != nil {
+			r.logger.Warn().Err(err).Str("delivery_id", item.ID).Msg("failed to map webhook delivery item, skipping")
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+// applyWebhookDeliveryFilter sets the scan's filter expression from the non-empty
+// fields of filter.
+func applyWebhookDeliveryFilter(input *dynamodb.ScanInput, filter entity.WebhookDeliveryFilter) {
+	var conditions []string
+	names := map[string]string{}
+	values := map[string]types.AttributeValue{}
+
+	add := func(attribute, value string) {
+		conditions = append(conditions, fmt.Sprintf("#%s = :%s", attribute, attribute))
+		names["#"+attribute] = attribute
+		values[":"+attribute] = &types.AttributeValueMemberS{Value: value}
+	}
+	if filter.EndpointID != "" {
+		add("endpoint_id", filter.EndpointID)
+	}
+	if filter.TransactionID != "" {
+		add("transaction_id", filter.TransactionID)
+	}
+	if filter.Status != "" {
+		add("status", string(filter.Status))
+	}
+
+	if len(conditions) > 0 {
+		input.FilterExpression = aws.String(strings.Join(conditions, " AND "))
+		input.ExpressionAttributeNames = names
+		input.ExpressionAttributeValues = values
+	}
+}
+
+func encodeWebhookDeliveryCursor(item map[string]types.AttributeValue) (string, error) {
+	id, ok := item["id"].(*types.AttributeValueMemberS)
+	if !ok {
+		return "", errors.New("webhook delivery item has no id")
+	}
+
+	b, err := json.Marshal(webhookDeliveryCursor{ID: id.Value})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+	return base64.URLEncoding.EncodeToString(b), nil
+}
+
+func decodeWebhookDeliveryCursor(cursor string) (string, error) {
+	b, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", entity.ErrCursorMalformed, err)
+	}
+
+	var cur webhookDeliveryCursor
+	if err := json.Unmarshal(b, &cur); err != nil {
+		return "", fmt.Errorf("%w: %w", entity.ErrCursorMalformed, err)
+	}
+	if cur.ID == "" {
+		return "", fmt.Errorf("%w: missing id", entity.ErrCursorMalformed)
+	}
+	return cur.ID, nil
+}
+
+func toWebhookDeliveryItem(delivery *entity.WebhookDelivery) (webhookDeliveryItem, error) {
+	log, err := json.Marshal(delivery.Log)
+	if err != nil {
+		return webhookDeliveryItem{}, fmt.Errorf("failed to marshal webhook delivery log: %w", err)
+	}
+
+	item := webhookDeliveryItem{
+		ID:             delivery.ID,
+		EndpointID:     delivery.EndpointID,
+		URL:            delivery.URL,
+		TransactionID:  delivery.TransactionID,
+		Event:          delivery.Event,
+		Payload:        string(delivery.Payload),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt.UTC().Format(outboxTimeFormat),
+		NextAttemptAt:  delivery.NextAttemptAt.UTC().Format(outboxTimeFormat),
+		Log:            string(log),
+	}
+	if delivery.DeliveredAt != nil {
+		item.DeliveredAt = delivery.DeliveredAt.UTC().Format(outboxTimeFormat)
+	}
+
+	return item, nil
+}
+
+func toWebhookDelivery(item webhookDeliveryItem) (entity.WebhookDelivery, error) {
+	var log []entity.WebhookAttempt
+	if err := json.Unmarshal([]byte(item.Log), &log); err != nil {
+		return entity.WebhookDelivery{}, fmt.Errorf("failed to parse webhook delivery log: %w", err)
+	}
+
+	createdAt, err := time.Parse(outboxTimeFormat, item.CreatedAt)
+	if err != nil {
+		return entity.WebhookDelivery{}, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+
+	nextAttemptAt, err := time.Parse(outboxTimeFormat, item.NextAttemptAt)
+	if err != nil {
+		return entity.WebhookDelivery{}, fmt.Errorf("failed to parse next_attempt_at: %w", err)
+	}
+
+	var deliveredAt *time.Time
+	if item.DeliveredAt != "" {
+		t, err := time.Parse(outboxTimeFormat, item.DeliveredAt)
+		if err != nil {
+			return entity.WebhookDelivery{}, fmt.Errorf("failed to parse delivered_at: %w", err)
+		}
+		deliveredAt = &t
+	}
+
+	return entity.WebhookDelivery{
+		ID:             item.ID,
+		EndpointID:     item.EndpointID,
+		URL:            item.URL,
+		TransactionID:  item.TransactionID,
+		Event:          item.Event,
+		Payload:        json.RawMessage(item.Payload),
+		Status:         entity.WebhookDeliveryStatus(item.Status),
+		Attempts:       item.Attempts,
+		LastStatusCode: item.LastStatusCode,
+		LastError:      item.LastError,
+		CreatedAt:      createdAt,
+		NextAttemptAt:  nextAttemptAt,
+		DeliveredAt:    deliveredAt,
+		Log:            log,
+	}, nil
+}
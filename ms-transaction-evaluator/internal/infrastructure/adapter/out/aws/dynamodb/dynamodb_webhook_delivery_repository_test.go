@@ -0,0 +1,244 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	smithymiddleware "github.com/aws/smithy-go/middleware"
+	"github.com/rs/zerolog"
+)
+
+func newTestWebhookDelivery(id string) *entity.WebhookDelivery {
+	createdAt := time.Date(2025, 1, 15, 10, 0, 0, 123456789, time.UTC)
+	delivery, _ := entity.NewTransactionFinalizedDelivery(id, &entity.WebhookEndpoint{ID: "ep_001", URL: "https://example.com/hooks"},
+		&entity.TransactionEntity{ID: "txn_001", Status: entity.APPROVED, CreatedAt: createdAt}, createdAt)
+	return delivery
+}
+
+// newCapturingScanClient creates a DynamoDB client answering with httpClient that
+// captures every ScanInput via middleware.
+func newCapturingScanClient(httpClient aws.HTTPClient, captured *[]dynamodb.ScanInput) *dynamodb.Client {
+	return dynamodb.NewFromConfig(aws.Config{Region: "us-east-1"}, func(o *dynamodb.Options) {
+		o.BaseEndpoint = aws.String("http://localhost:8000")
+		o.HTTPClient = httpClient
+		o.APIOptions = append(o.APIOptions, func(stack *smithymiddleware.Stack) error {
+			return stack.Initialize.Add(smithymiddleware.InitializeMiddlewareFunc(
+				"CaptureScan",
+				func(ctx context.Context, in smithymiddleware.InitializeInput, next smithymiddleware.InitializeHandler) (smithymiddleware.InitializeOutput, smithymiddleware.Metadata, error) {
+					if input, ok := in.Parameters.(*dynamodb.ScanInput); ok {
+						*captured = append(*captured, *input)
+					}
+					return next.HandleInitialize(ctx, in)
+				},
+			), smithymiddleware.Before)
+		})
+	})
+}
+
+// statusHTTPClient answers every request with the given status and JSON body.
+type statusHTTPClient struct {
+	status int
+	body   string
+}
+
+func (s *statusHTTPClient) Do(_ *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: s.status,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.0"}},
+		Body:       io.NopCloser(strings.NewReader(s.body)),
+	}, nil
+}
+
+// webhookDeliveryScanResponseJSON builds a DynamoDB Scan JSON response body with
+// the given deliveries and, when lastKeyID is set, a LastEvaluatedKey.
+func webhookDeliveryScanResponseJSON(t *testing.T, lastKeyID string, deliveries ...*entity.WebhookDelivery) string {
+	t.Helper()
+	jsonItems := make([]map[string]map[string]string, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		item, err := toWebhookDeliveryItem(delivery)
+		if err != nil {
+			t.Fatalf("toWebhookDeliveryItem returned unexpected error: %v", err)
+		}
+		av, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			t.Fatalf("Failed to marshal item: %v", err)
+		}
+		jsonItem := map[string]map[string]string{}
+		for name, value := range av {
+			switch v := value.(type) {
+			case *types.AttributeValueMemberS:
+				jsonItem[name] = map[string]string{"S": v.Value}
+			case *types.AttributeValueMemberN:
+				jsonItem[name] = map[string]string{"N": v.Value}
+			}
+		}
+		jsonItems = append(jsonItems, jsonItem)
+	}
+
+	response := map[string]any{"Count": len(jsonItems), "Items": jsonItems}
+	if lastKeyID != "" {
+		response["LastEvaluatedKey"] = map[string]map[string]string{"id": {"S": lastKeyID}}
+	}
+	body, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Failed to build scan response: %v", err)
+	}
+	return string(body)
+}
+
+func TestWebhookDeliveryItem_RoundTrip(t *testing.T) {
+	delivery := newTestWebhookDelivery("whd_001")
+	deliveredAt := delivery.CreatedAt.Add(time.Second)
+	delivery.Record(entity.WebhookAttempt{AttemptedAt: delivery.CreatedAt, StatusCode: 500, Error: "boom", DurationMs: 12})
+	delivery.Record(entity.WebhookAttempt{AttemptedAt: deliveredAt, StatusCode: 200, DurationMs: 8})
+	delivery.Status = entity.WebhookDeliveryDelivered
+	delivery.DeliveredAt = &deliveredAt
+
+	item, err := toWebhookDeliveryItem(delivery)
+	if err != nil {
+		t.Fatalf("toWebhookDeliveryItem returned unexpected error: %v", err)
+	}
+	got, err := toWebhookDelivery(item)
+	if err != nil {
+		t.Fatalf("toWebhookDelivery returned unexpected error: %v", err)
+	}
+
+	if got.ID != delivery.ID || got.Status != entity.WebhookDeliveryDelivered || got.Attempts != 2 || got.LastStatusCode != 200 {
+		t.Errorf("Unexpected delivery %+v", got)
+	}
+	if string(got.Payload) != string(delivery.Payload) {
+		t.Errorf("Expected payload %s, got %s", delivery.Payload, got.Payload)
+	}
+	if !got.CreatedAt.Equal(delivery.CreatedAt) || got.DeliveredAt == nil || !got.DeliveredAt.Equal(deliveredAt) {
+		t.Errorf("Expected timestamps to round trip, got %+v", got)
+	}
+	if len(got.Log) != 2 || got.Log[0].Error != "boom" || got.Log[1].StatusCode != 200 {
+		t.Errorf("Expected the log to round trip, got %+v", got.Log)
+	}
+}
+
+func TestWebhookDeliveryCreate_Conditional(t *testing.T) {
+	var captured dynamodb.PutItemInput
+	client := dynamodb.NewFromConfig(aws.Config{Region: "us-east-1"}, func(o *dynamodb.Options) {
+		o.BaseEndpoint = aws.String("http://localhost:8000")
+		o.HTTPClient = &fakeHTTPClient{}
+		o.APIOptions = append(o.APIOptions, func(stack *smithymiddleware.Stack) error {
+			return stack.Initialize.Add(smithymiddleware.InitializeMiddlewareFunc(
+				"CapturePutItem",
+				func(ctx context.Context, in smithymiddleware.InitializeInput, next smithymiddleware.InitializeHandler) (smithymiddleware.InitializeOutput, smithymiddleware.Metadata, error) {
+					if input, ok := in.Parameters.(*dynamodb.PutItemInput); ok {
+						captured = *input
+					}
+					return next.HandleInitialize(ctx, in)
+				},
+			), smithymiddleware.Before)
+		})
+	})
+	repo := NewDynamoDBWebhookDeliveryRepository(client, "webhook_deliveries", zerolog.Nop())
+
+	created, err := repo.Create(context.Background(), newTestWebhookDelivery("whd_001"))
+	if err != nil || !created {
+		t.Fatalf("Expected the delivery to be created, got %v %v", created, err)
+	}
+	if aws.ToString(captured.ConditionExpression) != "attribute_not_exists(id)" {
+		t.Errorf("Expected a conditional put, got %q", aws.ToString(captured.ConditionExpression))
+	}
+	if _, ok := captured.Item["ttl"]; ok {
+		t.Error("Expected a pending delivery not to expire")
+	}
+
+	t.Run("reports an existing delivery as not created", func(t *testing.T) {
+		body := `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`
+		client := newScanDynamoDBClient(&statusHTTPClient{status: 400, body: body})
+		repo := NewDynamoDBWebhookDeliveryRepository(client, "webhook_deliveries", zerolog.Nop())
+
+		created, err := repo.Create(context.Background(), newTestWebhookDelivery("whd_001"))
+		if err != nil || created {
+			t.Errorf("Expected not created without error, got %v %v", created, err)
+		}
+	})
+}
+
+func TestWebhookDeliveryFindAll(t *testing.T) {
+	first := newTestWebhookDelivery("whd_001")
+	second := newTestWebhookDelivery("whd_002")
+	second.CreatedAt = first.CreatedAt.Add(time.Second)
+	third := newTestWebhookDelivery("whd_003")
+
+	t.Run("applies the filter and scans until the page is full", func(t *testing.T) {
+		var captured []dynamodb.ScanInput
+		client := newCapturingScanClient(&sequentialHTTPClient{responses: []string{
+			webhookDeliveryScanResponseJSON(t, "whd_001", first),
+			webhookDeliveryScanResponseJSON(t, "", second, third),
+		}}, &captured)
+		repo := NewDynamoDBWebhookDeliveryRepository(client, "webhook_deliveries", zerolog.Nop())
+
+		filter := entity.WebhookDeliveryFilter{TransactionID: "txn_001", Status: entity.WebhookDeliveryPending}
+		deliveries, cursor, err := repo.FindAll(context.Background(), filter, 2, "")
+		if err != nil {
+			t.Fatalf("FindAll returned unexpected error: %v", err)
+		}
+		if len(deliveries) != 2 || deliveries[0].ID != "whd_002" || deliveries[1].ID != "whd_001" {
+			t.Fatalf("Expected whd_002 then whd_001, got %+v", deliveries)
+		}
+		if cursor == "" {
+			t.Fatal("Expected a cursor, the second scan page had more items")
+		}
+		if id, err := decodeWebhookDeliveryCursor(cursor); err != nil || id != "whd_002" {
+			t.Errorf("Expected the cursor to resume after whd_002, got %q %v", id, err)
+		}
+
+		if len(captured) != 2 {
+			t.Fatalf("Expected 2 scans, got %d", len(captured))
+		}
+		expr := aws.ToString(captured[0].FilterExpression)
+		if !strings.Contains(expr, "#transaction_id = :transaction_id") || !strings.Contains(expr, "#status = :status") ||
+			strings.Contains(expr, "endpoint_id") {
+			t.Errorf("Unexpected filter expression %q", expr)
+		}
+		if id := captured[1].ExclusiveStartKey["id"].(*types.AttributeValueMemberS).Value; id != "whd_001" {
+			t.Errorf("Expected the second scan to start after whd_001, got %s", id)
+		}
+	})
+
+	t.Run("returns no cursor on the last page", func(t *testing.T) {
+		var captured []dynamodb.ScanInput
+		client := newCapturingScanClient(&sequentialHTTPClient{responses: []string{
+			webhookDeliveryScanResponseJSON(t, "", first),
+		}}, &captured)
+		repo := NewDynamoDBWebhookDeliveryRepository(client, "webhook_deliveries", zerolog.Nop())
+
+		cursor, _ := encodeWebhookDeliveryCursor(map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "whd_000"}})
+		deliveries, next, err := repo.FindAll(context.Background(), entity.WebhookDeliveryFilter{}, 2, cursor)
+		if err != nil || len(deliveries) != 1 || next != "" {
+			t.Errorf("Expected one delivery and no cursor, got %d %q %v", len(deliveries), next, err)
+		}
+		if captured[0].FilterExpression != nil {
+			t.Errorf("Expected no filter expression, got %q", aws.ToString(captured[0].FilterExpression))
+		}
+		if id := captured[0].ExclusiveStartKey["id"].(*types.AttributeValueMemberS).Value; id != "whd_000" {
+			t.Errorf("Expected the scan to start after whd_000, got %s", id)
+		}
+	})
+
+	t.Run("rejects a malformed cursor", func(t *testing.T) {
+		repo := NewDynamoDBWebhookDeliveryRepository(newScanDynamoDBClient(&fakeHTTPClient{}), "webhook_deliveries", zerolog.Nop())
+
+		for _, cursor := range []string{"not base64!", "bm90IGpzb24=", "e30="} {
+			if _, _, err := repo.FindAll(context.Background(), entity.WebhookDeliveryFilter{}, 2, cursor); !errors.Is(err, entity.ErrCursorMalformed) {
+				t.Errorf("cursor %q: expected ErrCursorMalformed, got %v", cursor, err)
+			}
+		}
+	})
+}
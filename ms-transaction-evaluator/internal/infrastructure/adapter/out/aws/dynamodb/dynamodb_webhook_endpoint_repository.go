@@ -0,0 +1,129 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type webhookEndpointItem struct {
+	ID         string `dynamodbav:"id"`
+	URL        string `dynamodbav:"url"`
+	MerchantID string `dynamodbav:"merchant_id,omitempty"`
+	Secret     string `dynamodbav:"secret"`
+	CreatedAt  string `dynamodbav:"created_at"`
+}
+
+// DynamoDBWebhookEndpointRepository implements repository.WebhookEndpointRepository
+// using AWS DynamoDB. The table is keyed by id (hash); endpoints are few, so they
+// are read with a full scan.
+type DynamoDBWebhookEndpointRepository struct {
+	client    *dynamodb.Client
+	tableName string
+	logger    zerolog.Logger
+}
+
+// NewDynamoDBWebhookEndpointRepository creates a new DynamoDB-backed webhook
+// endpoint repository.
+func NewDynamoDBWebhookEndpointRepository(
+	client *dynamodb.Client,
+	tableName string,
+	logger zerolog.Logger,
+) *DynamoDBWebhookEndpointRepository {
+	return &DynamoDBWebhookEndpointRepository{
+		client:    client,
+		tableName: tableName,
+		logger:    logger,
+	}
+}
+
+// Save puts the endpoint.
+func (r *DynamoDBWebhookEndpointRepository) Save(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	av, err := attributevalue.MarshalMap(webhookEndpointItem{
+		ID:         endpoint.ID,
+		URL:        endpoint.URL,
+		MerchantID: endpoint.MerchantID,
+		Secret:     endpoint.Secret,
+		CreatedAt:  endpoint.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook endpoint: %w", err)
+	}
+
+	if _, err := r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      av,
+	}); err != nil {
+		r.logger.Error().Err(err).Str("endpoint_id", endpoint.ID).Str("table", r.tableName).Msg("failed to put webhook endpoint")
+		return fmt.Errorf("failed to put webhook endpoint: %w", err)
+	}
+
+	return nil
+}
+
+// FindAll scans every page of the table.
+func (r *DynamoDBWebhookEndpointRepository) FindAll(ctx context.Context) ([]entity.WebhookEndpoint, error) {
+	var endpoints []entity.WebhookEndpoint
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		output, err := r.client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(r.tableName),
+			ExclusiveStartKey: lastEvaluatedKey,
+		})
+		if err != nil {
+			r.logger.Error().Err(err).Str("table", r.tableName).Msg("failed to scan webhook endpoints")
+			return nil, fmt.Errorf("failed to scan webhook endpoints: %w", err)
+		}
+
+		var items []webhookEndpointItem
+		if err := attributevalue.UnmarshalListOfMaps(output.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal webhook endpoints: %w", err)
+		}
+
+		for _, item := range items {
+			createdAt, err := time.Parse(time.RFC3339Nano, item.CreatedAt)
+			if err != nil {
+				r.logger.Warn().Err(err).Str("endpoint_id", item.ID).Msg("failed to parse webhook endpoint, skipping")
+				continue
+			}
+			endpoints = append(endpoints, entity.WebhookEndpoint{
+				ID:         item.ID,
+				URL:        item.URL,
+				MerchantID: item.MerchantID,
+				Secret:     item.Secret,
+				CreatedAt:  createdAt,
+			})
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return endpoints, nil
+		}
+		lastEvaluatedKey = output.LastEvaluatedKey
+	}
+}
+
+// Delete removes the endpoint and reports whether it existed.
+func (r *DynamoDBWebhookEndpointRepository) Delete(ctx context.Context, id string) (bool, error) {
+	output, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Str("endpoint_id", id).Str("table", r.tableName).Msg("failed to delete webhook endpoint")
+		return false, fmt.Errorf("failed to delete webhook endpoint: %w", err)
+	}
+
+	return len(output.Attributes) > 0, nil
+}
@@ -0,0 +1,47 @@
+package dynamodb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWebhookEndpointFindAll(t *testing.T) {
+	page1 := `{"Count":1,"Items":[{"id":{"S":"ep_001"},"url":{"S":"https://example.com/a"},"secret":{"S":"whsec_a"},"created_at":{"S":"2025-01-15T10:00:00Z"}}],"LastEvaluatedKey":{"id":{"S":"ep_001"}}}`
+	page2 := `{"Count":2,"Items":[{"id":{"S":"ep_002"},"url":{"S":"https://example.com/b"},"merchant_id":{"S":"merchant_42"},"secret":{"S":"whsec_b"},"created_at":{"S":"2025-01-15T10:00:01Z"}},{"id":{"S":"ep_003"},"url":{"S":"https://example.com/c"},"secret":{"S":"whsec_c"},"created_at":{"S":"not a time"}}]}`
+	client := newScanDynamoDBClient(&sequentialHTTPClient{responses: []string{page1, page2}})
+	repo := NewDynamoDBWebhookEndpointRepository(client, "webhook_endpoints", zerolog.Nop())
+
+	endpoints, err := repo.FindAll(context.Background())
+	if err != nil {
+		t.Fatalf("FindAll returned unexpected error: %v", err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("Expected 2 endpoints across both pages with the corrupt one skipped, got %d", len(endpoints))
+	}
+	if endpoints[1].ID != "ep_002" || endpoints[1].MerchantID != "merchant_42" || endpoints[1].Secret != "whsec_b" {
+		t.Errorf("Unexpected endpoint %+v", endpoints[1])
+	}
+}
+
+func TestWebhookEndpointDelete(t *testing.T) {
+	t.Run("reports a deleted endpoint", func(t *testing.T) {
+		body := `{"Attributes":{"id":{"S":"ep_001"}}}`
+		repo := NewDynamoDBWebhookEndpointRepository(newScanDynamoDBClient(&sequentialHTTPClient{responses: []string{body}}), "webhook_endpoints", zerolog.Nop())
+
+		deleted, err := repo.Delete(context.Background(), "ep_001")
+		if err != nil || !deleted {
+			t.Errorf("Expected deleted, got %v %v", deleted, err)
+		}
+	})
+
+	t.Run("reports a missing endpoint", func(t *testing.T) {
+		repo := NewDynamoDBWebhookEndpointRepository(newScanDynamoDBClient(&fakeHTTPClient{}), "webhook_endpoints", zerolog.Nop())
+
+		deleted, err := repo.Delete(context.Background(), "ep_404")
+		if err != nil || deleted {
+			t.Errorf("Expected not deleted, got %v %v", deleted, err)
+		}
+	})
+}
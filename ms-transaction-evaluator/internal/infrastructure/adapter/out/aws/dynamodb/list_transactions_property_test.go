@@ -0,0 +1,307 @@
+package dynamodb
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"pgregory.net/rapid"
+)
+
+// fakeTransactionTable is an in-memory transactions table answering the Query
+// requests of FindAllPaginated like DynamoDB: items of the index partition in
+// sort key order from ExclusiveStartKey, Limit evaluated before the filter, and
+// a LastEvaluatedKey whenever Limit is reached. DynamoDB does not order the items
+// sharing a sort key value by id, so the table orders them by a drawn rank, and
+// then as they were put.
+type fakeTransactionTable struct {
+	t     *rapid.T
+	items []map[string]map[string]string
+	// ranks holds the drawn rank of each item, by id.
+	ranks map[string]int
+}
+
+type fakeQueryRequest struct {
+	IndexName                 string
+	KeyConditionExpression    string
+	FilterExpression          string
+	ExpressionAttributeNames  map[string]string
+	ExpressionAttributeValues map[string]map[string]string
+	ScanIndexForward          bool
+	Limit                     int
+	ExclusiveStartKey         map[string]map[string]string
+}
+
+func (f *fakeTransactionTable) put(transaction *entity.TransactionEntity) {
+	if f.ranks == nil {
+		f.ranks = map[string]int{}
+	}
+	f.ranks[transaction.ID] = rapid.Int().Draw(f.t, "tieRank")
+	f.items = append(f.items, transactionWireItem(f.t, toTransactionItem(transaction)))
+}
+
+func (f *fakeTransactionTable) Do(req *http.Request) (*http.Response, error) {
+	if target := req.Header.Get("X-Amz-Target"); target != "DynamoDB_20120810.Query" {
+		f.t.Fatalf("unexpected DynamoDB operation %s", target)
+	}
+	var query fakeQueryRequest
+	if err := json.NewDecoder(req.Body).Decode(&query); err != nil {
+		f.t.Fatalf("failed to decode query: %v", err)
+	}
+
+	partitionKey := strings.TrimSuffix(query.IndexName, "-created_at-index")
+	var partition []map[string]map[string]string
+	for _, item := range f.items {
+		if _, ok := item[partitionKey]; ok && f.matches(item, query.KeyConditionExpression, query) {
+			partition = append(partition, item)
+		}
+	}
+	slices.SortStableFunc(partition, func(a, b map[string]map[string]string) int {
+		if c := strings.Compare(a["created_at"]["S"], b["created_at"]["S"]); c != 0 {
+			return c
+		}
+		return cmp.Compare(f.ranks[a["id"]["S"]], f.ranks[b["id"]["S"]])
+	})
+	if !query.ScanIndexForward {
+		slices.Reverse(partition)
+	}
+
+	if query.ExclusiveStartKey != nil {
+		start := slices.IndexFunc(partition, func(item map[string]map[string]string) bool {
+			return item["id"]["S"] == query.ExclusiveStartKey["id"]["S"]
+		})
+		partition = partition[start+1:]
+	}
+
+	var items []map[string]map[string]string
+	var lastEvaluatedKey map[string]map[string]string
+	for i, item := range partition {
+		if query.FilterExpression == "" || f.matches(item, query.FilterExpression, query) {
+			items = append(items, item)
+		}
+		if i+1 == query.Limit {
+			lastEvaluatedKey = map[string]map[string]string{
+				"id":         item["id"],
+				partitionKey: item[partitionKey],
+				"created_at": item["created_at"],
+			}
+			break
+		}
+	}
+
+	response := map[string]any{"Count": len(items), "Items": items}
+	if lastEvaluatedKey != nil {
+		response["LastEvaluatedKey"] = lastEvaluatedKey
+	}
+	body, err := json.Marshal(response)
+	if err != nil {
+		f.t.Fatalf("failed to encode query response: %v", err)
+	}
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.0"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}, nil
+}
+
+// matches evaluates a condition expression made of "#name op :value" and
+// "#name BETWEEN :low AND :high" terms joined by AND.
+func (f *fakeTransactionTable) matches(item map[string]map[string]string, expression string, query fakeQueryRequest) bool {
+	tokens := strings.Fields(expression)
+	for len(tokens) > 0 {
+		attribute := item[query.ExpressionAttributeNames[tokens[0]]]
+		switch tokens[1] {
+		case "BETWEEN":
+			low, high := query.ExpressionAttributeValues[tokens[2]], query.ExpressionAttributeValues[tokens[4]]
+			if compareFakeValues(attribute, low) < 0 || compareFakeValues(attribute, high) > 0 {
+				return false
+			}
+			tokens = tokens[5:]
+		default:
+			c := compareFakeValues(attribute, query.ExpressionAttributeValues[tokens[2]])
+			if (tokens[1] == "=" && c != 0) || (tokens[1] == ">=" && c < 0) || (tokens[1] == "<=" && c > 0) {
+				return false
+			}
+			tokens = tokens[3:]
+		}
+		if len(tokens) > 0 {
+			tokens = tokens[1:]
+		}
+	}
+	return true
+}
+
+func compareFakeValues(a, b map[string]string) int {
+	if n, ok := b["N"]; ok {
+		x, _ := strconv.ParseInt(a["N"], 10, 64)
+		y, _ := strconv.ParseInt(n, 10, 64)
+		return int(x - y)
+	}
+	return strings.Compare(a["S"], b["S"])
+}
+
+// drawListedTransactions draws transactions created within a minute, so many
+// share a created_at second.
+func drawListedTransactions(t *rapid.T, n int, base time.Time, prefix string) []*entity.TransactionEntity {
+	transactions := make([]*entity.TransactionEntity, n)
+	for i := range transactions {
+		createdAt := base.Add(time.Duration(rapid.IntRange(0, 59).Draw(t, "createdAtOffset")) * time.Second)
+		transactions[i] = &entity.TransactionEntity{
+			ID:            fmt.Sprintf("%s_%04d", prefix, i),
+			AmountInCents: rapid.Int64Range(1, 10_000).Draw(t, "amount"),
+			Currency:      entity.USD,
+			PaymentMethod: rapid.SampledFrom([]entity.PaymentMethod{entity.CARD, entity.BANK_TRANSFER, entity.CRYPTO}).Draw(t, "paymentMethod"),
+			CustomerID:    "cust_test",
+			Status:        entity.PENDING,
+			CreatedAt:     createdAt,
+			UpdatedAt:     createdAt,
+		}
+	}
+	return transactions
+}
+
+func drawTransactionFilter(t *rapid.T) entity.TransactionFilter {
+	var filter entity.TransactionFilter
+	if rapid.Bool().Draw(t, "filterPaymentMethod") {
+		filter.PaymentMethod = entity.CARD
+	}
+	if rapid.Bool().Draw(t, "filterMinAmount") {
+		minAmount := rapid.Int64Range(1, 10_000).Draw(t, "minAmount")
+		filter.MinAmountInCents = &minAmount
+	}
+	return filter
+}
+
+func matchesTransactionFilter(transaction *entity.TransactionEntity, filter entity.TransactionFilter) bool {
+	if filter.PaymentMethod != "" && transaction.PaymentMethod != filter.PaymentMethod {
+		return false
+	}
+	return filter.MinAmountInCents == nil || transaction.AmountInCents >= *filter.MinAmountInCents
+}
+
+// listAllTransactions follows next_cursor from the first page, calling
+// beforePage before reading each page after the first.
+func listAllTransactions(t *rapid.T, repo *DynamoDBTransactionRepository, query entity.TransactionQuery, pageSize int, beforePage func()) []entity.TransactionEntity {
+	var listed []entity.TransactionEntity
+	for page := 0; ; page++ {
+		if page > 1000 {
+			t.Fatalf("pagination did not terminate")
+		}
+		if page > 0 {
+			beforePage()
+		}
+
+		query.Limit = pageSize
+		transactions, nextCursor, err := repo.FindAllPaginated(context.Background(), query)
+		if err != nil {
+			t.Fatalf("unexpected error on page %d: %v", page, err)
+		}
+		if len(transactions) > pageSize {
+			t.Fatalf("page %d has %d transactions, limit is %d", page, len(transactions), pageSize)
+		}
+		listed = append(listed, transactions...)
+
+		if nextCursor == "" {
+			return listed
+		}
+		query.Cursor = nextCursor
+	}
+}
+
+// Feature: fraud-analyst-dashboard, Property 5: Bucket index pagination is ordered without gaps or duplicates
+// Validates: Requirements 1.2, 1.3
+
+func TestProperty_BucketPaginationIsOrderedWithoutGapsOrDuplicates(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		transactions := drawListedTransactions(t, rapid.IntRange(0, 60).Draw(t, "numTransactions"), base, "txn")
+		filter := drawTransactionFilter(t)
+		order := rapid.SampledFrom([]entity.SortOrder{entity.SortDescending, entity.SortAscending}).Draw(t, "order")
+		pageSize := rapid.IntRange(1, 15).Draw(t, "pageSize")
+
+		table := &fakeTransactionTable{t: t}
+		expected := map[string]bool{}
+		for _, transaction := range transactions {
+			table.put(transaction)
+			if matchesTransactionFilter(transaction, filter) {
+				expected[transaction.ID] = true
+			}
+		}
+		repo := NewDynamoDBTransactionRepository(newScanDynamoDBClient(table), "transactions", testCursorSecret, zerolog.Nop())
+
+		listed := listAllTransactions(t, repo, entity.TransactionQuery{Filter: filter, Order: order}, pageSize, func() {})
+
+		seen := map[string]bool{}
+		for i, transaction := range listed {
+			if seen[transaction.ID] {
+				t.Fatalf("duplicate transaction %s", transaction.ID)
+			}
+			seen[transaction.ID] = true
+			if !expected[transaction.ID] {
+				t.Fatalf("transaction %s does not match the filter", transaction.ID)
+			}
+			if i > 0 {
+				previous := listed[i-1].CreatedAt
+				if (order == entity.SortDescending && transaction.CreatedAt.After(previous)) ||
+					(order == entity.SortAscending && transaction.CreatedAt.Before(previous)) {
+					t.Fatalf("ordering violation at %d: %v after %v in %s order", i, transaction.CreatedAt, previous, order)
+				}
+			}
+		}
+		if len(seen) != len(expected) {
+			t.Fatalf("listed %d transactions, expected %d", len(seen), len(expected))
+		}
+	})
+}
+
+// Feature: fraud-analyst-dashboard, Property 6: Newest-first pagination is stable while transactions are created
+// Validates: Requirements 1.3
+
+func TestProperty_BucketPaginationIsStableUnderInserts(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		transactions := drawListedTransactions(t, rapid.IntRange(1, 60).Draw(t, "numTransactions"), base, "txn")
+		pageSize := rapid.IntRange(1, 15).Draw(t, "pageSize")
+
+		table := &fakeTransactionTable{t: t}
+		for _, transaction := range transactions {
+			table.put(transaction)
+		}
+		repo := NewDynamoDBTransactionRepository(newScanDynamoDBClient(table), "transactions", testCursorSecret, zerolog.Nop())
+
+		// Transactions created while paging are newer than every listed one
+		inserted := 0
+		listed := listAllTransactions(t, repo, entity.TransactionQuery{Order: entity.SortDescending}, pageSize, func() {
+			newer := drawListedTransactions(t, rapid.IntRange(0, 5).Draw(t, "numInserted"), base.Add(time.Hour), fmt.Sprintf("new%d", inserted))
+			for _, transaction := range newer {
+				table.put(transaction)
+			}
+			inserted++
+		})
+
+		seen := map[string]bool{}
+		for _, transaction := range listed {
+			if seen[transaction.ID] {
+				t.Fatalf("duplicate transaction %s", transaction.ID)
+			}
+			if strings.HasPrefix(transaction.ID, "new") {
+				t.Fatalf("transaction %s created after the first page was listed", transaction.ID)
+			}
+			seen[transaction.ID] = true
+		}
+		if len(seen) != len(transactions) {
+			t.Fatalf("listed %d transactions, expected %d", len(seen), len(transactions))
+		}
+	})
+}
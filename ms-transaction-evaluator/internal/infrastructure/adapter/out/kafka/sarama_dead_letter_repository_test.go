@@ -0,0 +1,92 @@
+package kafka
+
+import (
+	"context"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rs/zerolog"
+)
+
+func TestToDeadLetter_RoundTrip(t *testing.T) {
+	original := &entity.DeadLetter{
+		SourceTopic:     "Decision.Calculated",
+		SourcePartition: 4,
+		SourceOffset:    1234,
+		Key:             "tx-9",
+		Payload:         `{"transaction_id":"tx-9","status":"DECLINED"}`,
+		Headers:         map[string]string{"traceparent": "00-abc-def-01"},
+		Error:           "failed to update transaction status: throttled",
+		Attempts:        1,
+		FailedAt:        time.Date(2025, 3, 4, 5, 6, 7, 890, time.UTC),
+	}
+
+	headers := toDeadLetterHeaders(original)
+	msg := &sarama.ConsumerMessage{
+		Partition: 1,
+		Offset:    17,
+		Key:       []byte(original.Key),
+		Value:     []byte(original.Payload),
+	}
+	for i := range headers {
+		msg.Headers = append(msg.Headers, &headers[i])
+	}
+
+	deadLetter := toDeadLetter(msg)
+
+	expected := *original
+	expected.ID = "1-17"
+	expected.Partition = 1
+	expected.Offset = 17
+	expected.TransactionID = "tx-9"
+	if !reflect.DeepEqual(deadLetter, expected) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", deadLetter, expected)
+	}
+}
+
+func TestToDeadLetter_MalformedDecision(t *testing.T) {
+	timestamp := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	deadLetter := toDeadLetter(&sarama.ConsumerMessage{Timestamp: timestamp, Value: []byte("not json")})
+
+	if deadLetter.TransactionID != "" || deadLetter.Payload != "not json" {
+		t.Errorf("expected the raw payload without a transaction ID, got %+v", deadLetter)
+	}
+	if !deadLetter.FailedAt.Equal(timestamp) {
+		t.Errorf("expected failed_at %v, got %v", timestamp, deadLetter.FailedAt)
+	}
+	if len(deadLetter.Headers) != 0 {
+		t.Errorf("expected no headers, got %v", deadLetter.Headers)
+	}
+}
+
+func TestSaramaDeadLetterRepository_Redrive(t *testing.T) {
+	producer := &capturingSyncProducer{}
+	repo := NewSaramaDeadLetterRepository(nil, producer, "Decision.Calculated.DLQ", zerolog.Nop())
+
+	err := repo.Redrive(context.Background(), &entity.DeadLetter{
+		ID:          "0-3",
+		SourceTopic: "Decision.Calculated",
+		Key:         "tx-1",
+		Payload:     `{"transaction_id":"tx-1","status":"APPROVED"}`,
+		Headers:     map[string]string{"traceparent": "00-abc-def-01"},
+		Error:       "failed to update transaction status",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	msg := producer.lastMessage
+	if msg.Topic != "Decision.Calculated" {
+		t.Errorf("expected topic Decision.Calculated, got %s", msg.Topic)
+	}
+	value, _ := msg.Value.Encode()
+	if string(value) != `{"transaction_id":"tx-1","status":"APPROVED"}` {
+		t.Errorf("expected original payload, got %s", value)
+	}
+	if len(msg.Headers) != 1 || string(msg.Headers[0].Key) != "traceparent" {
+		t.Errorf("expected only the original headers, got %v", msg.Headers)
+	}
+}
@@ -5,11 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"ms-transaction-evaluator/internal/domain/entity"
+	"strconv"
 
 	"github.com/IBM/sarama"
 	"github.com/rs/zerolog"
 )
 
+// HeaderRepublishAttempt marks a Transaction.Created event published again by the
+// stuck-transaction sweeper, with the 1-based attempt as value.
+const HeaderRepublishAttempt = "republish-attempt"
+
 type SaramaTransactionPublisher struct {
 	producer sarama.SyncProducer
 	topic    string
@@ -21,6 +26,17 @@ func NewSaramaTransactionPublisher(producer sarama.SyncProducer, topic string, l
 }
 
 func (p *SaramaTransactionPublisher) Publish(_ context.Context, transaction *entity.TransactionEntity) error {
+	return p.send(transaction, nil)
+}
+
+// Republish publishes the transaction again with the republish-attempt header.
+func (p *SaramaTransactionPublisher) Republish(_ context.Context, transaction *entity.TransactionEntity, attempt int) error {
+	return p.send(transaction, []sarama.RecordHeader{
+		{Key: []byte(HeaderRepublishAttempt), Value: []byte(strconv.Itoa(attempt))},
+	})
+}
+
+func (p *SaramaTransactionPublisher) send(transaction *entity.TransactionEntity, headers []sarama.RecordHeader) error {
 	p.logger.Info().
 		Str("transaction_id", transaction.ID).
 		Str("topic", p.topic).
@@ -35,9 +51,10 @@ func (p *SaramaTransactionPublisher) Publish(_ context.Context, transaction *ent
 	}
 
 	msg := &sarama.ProducerMessage{
-		Topic: p.topic,
-		Key:   sarama.StringEncoder(transaction.ID),
-		Value: sarama.ByteEncoder(payload),
+		Topic:   p.topic,
+		Key:     sarama.StringEncoder(transaction.ID),
+		Value:   sarama.ByteEncoder(payload),
+		Headers: headers,
 	}
 
 	partition, offset, err := p.producer.SendMessage(msg)
@@ -285,3 +285,28 @@ func TestProperty1_StructuredLogFieldPreservation(t *testing.T) {
 		properties.TestingRun(t)
 	})
 }
+
+func TestRepublish_AddsAttemptHeader(t *testing.T) {
+	mockProducer := &capturingSyncProducer{}
+	publisher := NewSaramaTransactionPublisher(mockProducer, "test-topic", zerolog.Nop())
+	txn := &entity.TransactionEntity{ID: "txn_001", Status: entity.PENDING}
+
+	if err := publisher.Publish(context.Background(), txn); err != nil {
+		t.Fatalf("Publish returned unexpected error: %v", err)
+	}
+	if len(mockProducer.lastMessage.Headers) != 0 {
+		t.Errorf("expected no headers on a first publish, got %v", mockProducer.lastMessage.Headers)
+	}
+
+	if err := publisher.Republish(context.Background(), txn, 2); err != nil {
+		t.Fatalf("Republish returned unexpected error: %v", err)
+	}
+	msg := mockProducer.lastMessage
+	if len(msg.Headers) != 1 || string(msg.Headers[0].Key) != HeaderRepublishAttempt || string(msg.Headers[0].Value) != "2" {
+		t.Errorf("expected a republish-attempt header of 2, got %v", msg.Headers)
+	}
+	key, _ := msg.Key.Encode()
+	if string(key) != "txn_001" {
+		t.Errorf("expected key txn_001, got %s", key)
+	}
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"io"
+	"ms-transaction-evaluator/internal/domain/entity"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPWebhookSender_Send(t *testing.T) {
+	delivery := &entity.WebhookDelivery{
+		ID:      "delivery-1",
+		Event:   entity.WebhookEventTransactionFinalized,
+		Payload: []byte(`{"id":"delivery-1","event":"transaction.finalized"}`),
+	}
+
+	t.Run("posts a signed payload", func(t *testing.T) {
+		var received *http.Request
+		var body []byte
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer receiver.Close()
+
+		sender := NewHTTPWebhookSender(time.Second)
+		sender.now = func() time.Time { return time.Unix(1736935200, 0) }
+		endpoint := &entity.WebhookEndpoint{ID: "ep-1", URL: receiver.URL, Secret: "whsec_test_secret"}
+
+		status, err := sender.Send(context.Background(), endpoint, delivery)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if status != http.StatusNoContent {
+			t.Errorf("expected 204, got %d", status)
+		}
+		if received.Method != http.MethodPost || string(body) != string(delivery.Payload) {
+			t.Errorf("expected the payload POSTed, got %s %s", received.Method, body)
+		}
+		if received.Header.Get(HeaderWebhookID) != "delivery-1" ||
+			received.Header.Get(HeaderWebhookEvent) != entity.WebhookEventTransactionFinalized ||
+			received.Header.Get(HeaderWebhookTimestamp) != "1736935200" {
+			t.Errorf("unexpected headers: %v", received.Header)
+		}
+
+		expected := Sign("whsec_test_secret", "1736935200", body)
+		if !hmac.Equal([]byte(received.Header.Get(HeaderWebhookSignature)), []byte(expected)) {
+			t.Errorf("expected signature %s, got %s", expected, received.Header.Get(HeaderWebhookSignature))
+		}
+	})
+
+	t.Run("returns the status code of a non-2xx response", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer receiver.Close()
+
+		status, err := NewHTTPWebhookSender(time.Second).Send(context.Background(), &entity.WebhookEndpoint{URL: receiver.URL}, delivery)
+		if err == nil || status != http.StatusServiceUnavailable {
+			t.Errorf("expected a 503 error, got %d %v", status, err)
+		}
+	})
+
+	t.Run("returns 0 when the endpoint is unreachable", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		receiver.Close()
+
+		status, err := NewHTTPWebhookSender(time.Second).Send(context.Background(), &entity.WebhookEndpoint{URL: receiver.URL}, delivery)
+		if err == nil || status != 0 {
+			t.Errorf("expected a connection error, got %d %v", status, err)
+		}
+	})
+}
+
+func TestSign(t *testing.T) {
+	// printf '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	expected := "sha256=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"
+	if got := Sign("secret", "1700000000", []byte("{}")); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
@@ -15,6 +15,216 @@ var TransactionFinalizationDuration = prometheus.NewHistogramVec(
 	[]string{"status"},
 )
 
+// KafkaDeadLetters counts consumed messages published to a dead-letter topic,
+// labelled by source topic.
+var KafkaDeadLetters = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "kafka_dead_letters_total",
+		Help: "Consumed Kafka messages dead-lettered by topic",
+	},
+	[]string{"topic"},
+)
+
+// OutboxLag records the age in seconds of the oldest due outbox entry, or 0
+// when none is due.
+var OutboxLag = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "outbox_lag_seconds",
+		Help: "Age of the oldest due outbox entry",
+	},
+)
+
+// OutboxPendingEntries records the number of due outbox entries read by the
+// last relay, capped at the relay batch size.
+var OutboxPendingEntries = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "outbox_pending_entries",
+		Help: "Due outbox entries read by the last relay",
+	},
+)
+
+// OutboxEventsPublished counts outbox entries published to Kafka.
+var OutboxEventsPublished = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "outbox_events_published_total",
+		Help: "Outbox entries published to Kafka",
+	},
+)
+
+// OutboxPublishFailures counts failed attempts to publish an outbox entry.
+var OutboxPublishFailures = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "outbox_publish_failures_total",
+		Help: "Failed attempts to publish an outbox entry",
+	},
+)
+
+// OutboxEntriesFailed counts outbox entries given up on after too many failed
+// publish attempts.
+var OutboxEntriesFailed = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "outbox_entries_failed_total",
+		Help: "Outbox entries given up on after too many failed publish attempts",
+	},
+)
+
+// OutboxPublishDelay records the time between saving an outbox entry and
+// publishing it in seconds.
+var OutboxPublishDelay = prometheus.NewHistogram(
+	prometheus.HistogramOpts{
+		Name:    "outbox_publish_delay_seconds",
+		Help:    "Time between saving an outbox entry and publishing it",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	},
+)
+
+// StuckTransactionsDetected counts PENDING transactions found by the sweeper
+// without a decision past the SLA, once per sweep that finds them.
+var StuckTransactionsDetected = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "stuck_transactions_detected_total",
+		Help: "PENDING transactions found without a decision past the SLA",
+	},
+)
+
+// StuckTransactionsRepublished counts Transaction.Created events published again
+// for stuck transactions.
+var StuckTransactionsRepublished = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "stuck_transactions_republished_total",
+		Help: "Transaction.Created events republished for stuck transactions",
+	},
+)
+
+// StuckTransactionsResolved counts stuck transactions given the fallback status,
+// labelled by status.
+var StuckTransactionsResolved = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "stuck_transactions_resolved_total",
+		Help: "Stuck transactions given the fallback status",
+	},
+	[]string{"status"},
+)
+
+// StuckTransactionSweepErrors counts failed sweeper actions, labelled by action
+// (republish, resolve or finalize).
+var StuckTransactionSweepErrors = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "stuck_transaction_sweep_errors_total",
+		Help: "Failed stuck-transaction sweeper actions",
+	},
+	[]string{"action"},
+)
+
+// DecisionWaits counts synchronous POST /evaluate requests, labelled by outcome
+// (decided or timeout).
+var DecisionWaits = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "decision_waits_total",
+		Help: "Synchronous evaluate requests by outcome",
+	},
+	[]string{"outcome"},
+)
+
+// DecisionWaitDuration records how long synchronous POST /evaluate requests waited
+// for the decision in seconds, labelled by outcome.
+var DecisionWaitDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "decision_wait_duration_seconds",
+		Help:    "Time synchronous evaluate requests waited for the decision",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	},
+	[]string{"outcome"},
+)
+
+// WebhookDeliveriesEnqueued counts the webhook deliveries created for finalized
+// transactions.
+var WebhookDeliveriesEnqueued = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "webhook_deliveries_enqueued_total",
+		Help: "Webhook deliveries created for finalized transactions",
+	},
+)
+
+// WebhookDeliveries counts webhook delivery outcomes, labelled by outcome
+// (delivered, retry or failed).
+var WebhookDeliveries = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "webhook_deliveries_total",
+		Help: "Webhook delivery attempts by outcome",
+	},
+	[]string{"outcome"},
+)
+
+// WebhookDeliveryDuration records how long webhook endpoints took to respond in
+// seconds.
+var WebhookDeliveryDuration = prometheus.NewHistogram(
+	prometheus.HistogramOpts{
+		Name:    "webhook_delivery_duration_seconds",
+		Help:    "Time webhook endpoints took to respond",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	},
+)
+
+// TransactionStreamSubscribers is the number of open transaction stream
+// subscriptions.
+var TransactionStreamSubscribers = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "transaction_stream_subscribers",
+		Help: "Open transaction stream subscriptions",
+	},
+)
+
+// TransactionStreamEvents counts the events published to the transaction stream,
+// labelled by type.
+var TransactionStreamEvents = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "transaction_stream_events_total",
+		Help: "Events published to the transaction stream by type",
+	},
+	[]string{"type"},
+)
+
+// TransactionStreamDroppedSubscribers counts the subscribers dropped for falling
+// behind the transaction stream.
+var TransactionStreamDroppedSubscribers = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "transaction_stream_dropped_subscribers_total",
+		Help: "Transaction stream subscribers dropped for falling behind",
+	},
+)
+
+// TransactionStatsUpdateFailures counts transaction changes whose counters could
+// not be updated, leaving the stats off until they are rebuilt.
+var TransactionStatsUpdateFailures = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "transaction_stats_update_failures_total",
+		Help: "Transaction changes not applied to the transaction stats counters",
+	},
+)
+
 func init() {
-	prometheus.MustRegister(TransactionFinalizationDuration)
+	prometheus.MustRegister(
+		TransactionFinalizationDuration,
+		KafkaDeadLetters,
+		OutboxLag,
+		OutboxPendingEntries,
+		OutboxEventsPublished,
+		OutboxPublishFailures,
+		OutboxEntriesFailed,
+		OutboxPublishDelay,
+		StuckTransactionsDetected,
+		StuckTransactionsRepublished,
+		StuckTransactionsResolved,
+		StuckTransactionSweepErrors,
+		DecisionWaits,
+		DecisionWaitDuration,
+		WebhookDeliveriesEnqueued,
+		WebhookDeliveries,
+		WebhookDeliveryDuration,
+		TransactionStreamSubscribers,
+		TransactionStreamEvents,
+		TransactionStreamDroppedSubscribers,
+		TransactionStatsUpdateFailures,
+	)
 }